@@ -0,0 +1,286 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Startup configuration of the server, read from command-line flags, environment variables and an optional config file,
+// in that order of precedence, and validated before anything is started. Relative paths are taken to be relative to the
+// base directory, which the server switches into on startup so that it can be run from anywhere, such as by systemd.
+
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Team254/cheesy-arena/updater"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Prefix of the environment variable corresponding to each option, which is followed by the option name in upper case.
+const envVarPrefix = "CHEESY_ARENA_"
+
+// Name of the option giving the path of the config file, which can only be set by flag or environment variable.
+const configFileOption = "config"
+
+// Settings that the server needs before it can start up.
+type Config struct {
+	ConfigFile      string
+	HttpPort        int
+	HttpsPort       int
+	TlsCertFile     string
+	TlsKeyFile      string
+	TrustedProxies  string
+	DbPath          string
+	Fields          int
+	BaseDir         string
+	LogFile         string
+	LogLevel        slog.Level
+	RobotSimulation bool
+	UpdateUrl       string
+}
+
+// Options whose environment variables don't follow the usual naming, for compatibility with older deployments.
+var envVarOverrides = map[string]string{
+	"database": "CHEESY_ARENA_DATABASE_URL",
+}
+
+// Parses the given command-line arguments, falling back to the given environment and then to the config file for any
+// options that aren't given as flags. Returns flag.ErrHelp if the usage message was requested and printed to output.
+func Load(args []string, getenv func(string) string, output io.Writer) (*Config, error) {
+	var config Config
+	flagSet := flag.NewFlagSet("cheesy-arena", flag.ContinueOnError)
+	flagSet.SetOutput(output)
+	flagSet.StringVar(&config.ConfigFile, configFileOption, "", "path of a file of 'option = value' lines to read")
+	flagSet.IntVar(&config.HttpPort, "http-port", 8080, "port on which to serve the web interface over HTTP")
+	flagSet.IntVar(&config.HttpsPort, "https-port", 8443, "port on which to serve the web interface over HTTPS")
+	flagSet.StringVar(&config.TlsCertFile, "tls-cert-file", "", "path of the PEM certificate for serving HTTPS")
+	flagSet.StringVar(&config.TlsKeyFile, "tls-key-file", "", "path of the PEM private key for serving HTTPS")
+	flagSet.StringVar(
+		&config.TrustedProxies,
+		"trusted-proxies",
+		"",
+		"comma-separated IP addresses or CIDR ranges of reverse proxies whose X-Forwarded-* headers are honored",
+	)
+	flagSet.StringVar(
+		&config.DbPath, "database", "./event.db", "path of the event database file, or a postgres:// URL",
+	)
+	flagSet.IntVar(
+		&config.Fields,
+		"fields",
+		1,
+		"number of fields to run at once, each with its own match play, panels and displays under /fields/<number>",
+	)
+	flagSet.StringVar(
+		&config.BaseDir,
+		"base-dir",
+		".",
+		"directory containing the templates, static files and pre-generated schedules",
+	)
+	flagSet.StringVar(&config.LogFile, "log-file", "./logs/cheesy-arena.log", "path of the rotating server log file")
+	flagSet.TextVar(
+		&config.LogLevel, "log-level", slog.LevelInfo, "lowest level of message to log: debug, info, warn or error",
+	)
+	flagSet.BoolVar(
+		&config.RobotSimulation,
+		"simulate-robots",
+		false,
+		"start with the driver stations, robots and scoring inputs simulated, for demos and testing",
+	)
+	flagSet.StringVar(
+		&config.UpdateUrl,
+		"update-url",
+		updater.DefaultReleaseUrl,
+		"release endpoint to check for newer builds of the server, or blank to not check",
+	)
+	flagSet.Usage = func() {
+		fmt.Fprintf(output, "Usage: cheesy-arena [options]\n\nEach option can also be set by the environment variable")
+		fmt.Fprintf(output, " shown, or in the config file.\n\n")
+		flagSet.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(output, "  -%s (%s)\n    \t%s (default %q)\n", f.Name, envVarName(f.Name), f.Usage, f.DefValue)
+		})
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	if flagSet.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument '%s'; run with -help to see the available options", flagSet.Arg(0))
+	}
+
+	// Note which options were given on the command line so that they take precedence over the other sources.
+	setByFlag := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) {
+		setByFlag[f.Name] = true
+	})
+	var errs []error
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if setByFlag[f.Name] {
+			return
+		}
+		if value := getenv(envVarName(f.Name)); value != "" {
+			if err := flagSet.Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value '%s' for %s: %v", value, envVarName(f.Name), err))
+			}
+			setByFlag[f.Name] = true
+		}
+	})
+	if config.ConfigFile != "" {
+		fileValues, err := readConfigFile(config.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, fileValue := range fileValues {
+			if fileValue.name == configFileOption || flagSet.Lookup(fileValue.name) == nil {
+				errs = append(
+					errs, fmt.Errorf("%s:%d: unknown option '%s'", config.ConfigFile, fileValue.line, fileValue.name),
+				)
+				continue
+			}
+			if setByFlag[fileValue.name] {
+				continue
+			}
+			if err = flagSet.Set(fileValue.name, fileValue.value); err != nil {
+				errs = append(
+					errs,
+					fmt.Errorf(
+						"%s:%d: invalid value '%s' for %s: %v",
+						config.ConfigFile,
+						fileValue.line,
+						fileValue.value,
+						fileValue.name,
+						err,
+					),
+				)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Checks that the settings are consistent and refer to files and directories that exist, returning an error that
+// describes every problem found.
+func (config *Config) Validate() error {
+	var errs []error
+	if err := validatePort("HTTP", config.HttpPort); err != nil {
+		errs = append(errs, err)
+	}
+	if config.HttpsEnabled() {
+		if err := validatePort("HTTPS", config.HttpsPort); err != nil {
+			errs = append(errs, err)
+		} else if config.HttpsPort == config.HttpPort {
+			errs = append(errs, fmt.Errorf("the HTTP and HTTPS ports must be different; both are %d", config.HttpPort))
+		}
+		if config.TlsCertFile == "" || config.TlsKeyFile == "" {
+			errs = append(errs, fmt.Errorf("serving HTTPS requires both a TLS certificate file and a key file"))
+		}
+		for _, path := range []string{config.TlsCertFile, config.TlsKeyFile} {
+			if path == "" {
+				continue
+			}
+			if _, err := os.Stat(config.pathInBaseDir(path)); err != nil {
+				errs = append(errs, fmt.Errorf("can't read TLS file '%s': %v", path, err))
+			}
+		}
+	}
+	if config.Fields < 1 {
+		errs = append(errs, fmt.Errorf("invalid number of fields %d; there must be at least one", config.Fields))
+	}
+	if info, err := os.Stat(config.BaseDir); err != nil || !info.IsDir() {
+		errs = append(errs, fmt.Errorf("base directory '%s' doesn't exist", config.BaseDir))
+	} else if _, err = os.Stat(filepath.Join(config.BaseDir, "templates", "base.html")); err != nil {
+		errs = append(
+			errs,
+			fmt.Errorf(
+				"base directory '%s' doesn't contain the templates; run the server from the cheesy-arena directory or "+
+					"set -base-dir to point at it",
+				config.BaseDir,
+			),
+		)
+	}
+	if !isPostgresUrl(config.DbPath) {
+		dbDir := filepath.Dir(config.pathInBaseDir(config.DbPath))
+		if info, err := os.Stat(dbDir); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("directory '%s' for the event database doesn't exist", dbDir))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Returns the location of the given path once the server has switched into the base directory, which relative paths
+// in the configuration are taken to be relative to.
+func (config *Config) pathInBaseDir(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(config.BaseDir, path)
+}
+
+// Returns true if the web interface should also be served over HTTPS.
+func (config *Config) HttpsEnabled() bool {
+	return config.TlsCertFile != "" || config.TlsKeyFile != ""
+}
+
+// Returns the name of the environment variable that sets the given option.
+func envVarName(optionName string) string {
+	if envVar, ok := envVarOverrides[optionName]; ok {
+		return envVar
+	}
+	return envVarPrefix + strings.ToUpper(strings.ReplaceAll(optionName, "-", "_"))
+}
+
+func validatePort(protocol string, port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid %s port %d; it must be between 1 and 65535", protocol, port)
+	}
+	return nil
+}
+
+// Returns true if the given database path refers to a Postgres server rather than to an embedded database file.
+func isPostgresUrl(path string) bool {
+	return strings.HasPrefix(path, "postgres://") || strings.HasPrefix(path, "postgresql://")
+}
+
+// A single setting read from the config file.
+type configFileValue struct {
+	name  string
+	value string
+	line  int
+}
+
+// Reads the 'option = value' lines of the given config file, ignoring blank lines and those starting with '#'.
+func readConfigFile(path string) ([]configFileValue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read config file: %v", err)
+	}
+	defer file.Close()
+
+	var values []configFileValue
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected a line of the form 'option = value'", path, lineNumber)
+		}
+		value = strings.Trim(strings.TrimSpace(value), "\"")
+		values = append(values, configFileValue{strings.TrimSpace(name), value, lineNumber})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read config file: %v", err)
+	}
+	return values, nil
+}
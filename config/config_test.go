@@ -0,0 +1,153 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package config
+
+import (
+	"bytes"
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load([]string{"-base-dir", ".."}, fakeEnv(nil), new(bytes.Buffer))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 8080, config.HttpPort)
+		assert.Equal(t, 8443, config.HttpsPort)
+		assert.False(t, config.HttpsEnabled())
+		assert.Equal(t, "./event.db", config.DbPath)
+		assert.Equal(t, 1, config.Fields)
+		assert.Equal(t, "./logs/cheesy-arena.log", config.LogFile)
+		assert.Equal(t, slog.LevelInfo, config.LogLevel)
+		assert.False(t, config.RobotSimulation)
+		assert.Equal(t, "https://api.github.com/repos/frc9611/cyberarena/releases/latest", config.UpdateUrl)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "field2.db")
+	configFile := writeConfigFile(
+		t,
+		"# Field 2\nhttp-port = 8082\nlog-level = debug\ndatabase = \""+dbPath+"\"\nsimulate-robots = true\n"+
+			"update-url =\nfields = 2\n",
+	)
+	env := fakeEnv(map[string]string{"CHEESY_ARENA_HTTP_PORT": "9090", "CHEESY_ARENA_LOG_LEVEL": "warn"})
+
+	config, err := Load(
+		[]string{"-base-dir", "..", "-config", configFile, "-log-level", "error"}, env, new(bytes.Buffer),
+	)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 9090, config.HttpPort)
+		assert.Equal(t, slog.LevelError, config.LogLevel)
+		assert.Equal(t, dbPath, config.DbPath)
+		assert.True(t, config.RobotSimulation)
+		assert.Equal(t, "", config.UpdateUrl)
+		assert.Equal(t, 2, config.Fields)
+	}
+
+	// Check that the config file can also be given by environment variable, and that the older database variable works.
+	env = fakeEnv(
+		map[string]string{
+			"CHEESY_ARENA_CONFIG": configFile, "CHEESY_ARENA_DATABASE_URL": "postgres://arena@dbhost/events",
+		},
+	)
+	config, err = Load([]string{"-base-dir", ".."}, env, new(bytes.Buffer))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 8082, config.HttpPort)
+		assert.Equal(t, slog.LevelDebug, config.LogLevel)
+		assert.Equal(t, "postgres://arena@dbhost/events", config.DbPath)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	output := new(bytes.Buffer)
+	_, err := Load([]string{"-help"}, fakeEnv(nil), output)
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Contains(t, output.String(), "-http-port (CHEESY_ARENA_HTTP_PORT)")
+	assert.Contains(t, output.String(), "-database (CHEESY_ARENA_DATABASE_URL)")
+
+	_, err = Load([]string{"-base-dir", "..", "extra"}, fakeEnv(nil), new(bytes.Buffer))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unexpected argument 'extra'")
+	}
+
+	_, err = Load(
+		[]string{"-base-dir", ".."},
+		fakeEnv(map[string]string{"CHEESY_ARENA_HTTP_PORT": "eighty", "CHEESY_ARENA_LOG_LEVEL": "loud"}),
+		new(bytes.Buffer),
+	)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid value 'eighty' for CHEESY_ARENA_HTTP_PORT")
+		assert.Contains(t, err.Error(), "invalid value 'loud' for CHEESY_ARENA_LOG_LEVEL")
+	}
+
+	configFile := writeConfigFile(t, "http-port = 8080\nhttp-prot = 8081\nbogus line\n")
+	_, err = Load([]string{"-config", configFile}, fakeEnv(nil), new(bytes.Buffer))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), ":3: expected a line of the form 'option = value'")
+	}
+	configFile = writeConfigFile(t, "http-port = 8080\nhttp-prot = 8081\n")
+	_, err = Load([]string{"-config", configFile}, fakeEnv(nil), new(bytes.Buffer))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), ":2: unknown option 'http-prot'")
+	}
+	_, err = Load([]string{"-config", "missing.conf"}, fakeEnv(nil), new(bytes.Buffer))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't read config file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	config := Config{HttpPort: 8080, HttpsPort: 8443, Fields: 1, BaseDir: "..", DbPath: "./event.db"}
+	assert.Nil(t, config.Validate())
+
+	// Check that all problems are reported together.
+	config = Config{
+		HttpPort:    0,
+		HttpsPort:   70000,
+		TlsCertFile: "cert.pem",
+		BaseDir:     ".",
+		DbPath:      "/nonexistent/event.db",
+	}
+	err := config.Validate()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid HTTP port 0")
+		assert.Contains(t, err.Error(), "invalid HTTPS port 70000")
+		assert.Contains(t, err.Error(), "invalid number of fields 0; there must be at least one")
+		assert.Contains(t, err.Error(), "requires both a TLS certificate file and a key file")
+		assert.Contains(t, err.Error(), "can't read TLS file 'cert.pem'")
+		assert.Contains(t, err.Error(), "base directory '.' doesn't contain the templates")
+		assert.Contains(t, err.Error(), "directory '/nonexistent' for the event database doesn't exist")
+	}
+
+	config = Config{
+		HttpPort: 8080, HttpsPort: 8080, TlsCertFile: "LICENSE", TlsKeyFile: "README.md", Fields: 1, BaseDir: "..",
+	}
+	config.DbPath = "postgres://arena@dbhost/events"
+	err = config.Validate()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the HTTP and HTTPS ports must be different; both are 8080", err.Error())
+	}
+
+	config = Config{HttpPort: 8080, Fields: 1, BaseDir: "nonexistent", DbPath: "./event.db"}
+	err = config.Validate()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "base directory 'nonexistent' doesn't exist")
+	}
+}
+
+func fakeEnv(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "cheesy-arena.conf")
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for keeping track of where the event is in the day's run of show so that the displays can count down to it.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Reloads the run of show after it has been edited and publishes the item coming up next to the displays.
+func (arena *Arena) UpdateAgenda() error {
+	nextAgendaItem, err := arena.getNextAgendaItem()
+	if err != nil {
+		return err
+	}
+	arena.NextAgendaItem = nextAgendaItem
+	arena.AgendaNotifier.Notify()
+	return nil
+}
+
+// Moves on to the following item of the run of show once the current one is over, and publishes it to the displays.
+func (arena *Arena) updateNextAgendaItem() {
+	nextAgendaItem, err := arena.getNextAgendaItem()
+	if err != nil {
+		logging.Arena.Error("Failed to load the run of show.", "error", err)
+		return
+	}
+	if nextAgendaItem == nil && arena.NextAgendaItem == nil ||
+		nextAgendaItem != nil && arena.NextAgendaItem != nil && *nextAgendaItem == *arena.NextAgendaItem {
+		return
+	}
+	arena.NextAgendaItem = nextAgendaItem
+	arena.AgendaNotifier.Notify()
+}
+
+// Returns the first agenda item that isn't over yet, or nil if they all are.
+func (arena *Arena) getNextAgendaItem() (*model.AgendaItem, error) {
+	agendaItems, err := arena.Database.GetAllAgendaItems()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, agendaItem := range agendaItems {
+		if agendaItem.EndTime().After(now) {
+			return &agendaItem, nil
+		}
+	}
+	return nil, nil
+}
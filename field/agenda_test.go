@@ -0,0 +1,42 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNextAgendaItem(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.Nil(t, arena.UpdateAgenda())
+	assert.Nil(t, arena.NextAgendaItem)
+
+	// Items that are over should be skipped, but one that is still under way should be kept.
+	now := time.Now()
+	arena.Database.CreateAgendaItem(&model.AgendaItem{Name: "Opening Ceremony", StartTime: now.Add(-2 * time.Hour)})
+	arena.Database.CreateAgendaItem(
+		&model.AgendaItem{Name: "Lunch", StartTime: now.Add(-30 * time.Minute), DurationMin: 60},
+	)
+	arena.Database.CreateAgendaItem(&model.AgendaItem{Name: "Awards", StartTime: now.Add(3 * time.Hour)})
+	assert.Nil(t, arena.UpdateAgenda())
+	if assert.NotNil(t, arena.NextAgendaItem) {
+		assert.Equal(t, "Lunch", arena.NextAgendaItem.Name)
+	}
+
+	lunch, _ := arena.Database.GetAgendaItemById(2)
+	lunch.DurationMin = 10
+	arena.Database.UpdateAgendaItem(lunch)
+	arena.updateNextAgendaItem()
+	if assert.NotNil(t, arena.NextAgendaItem) {
+		assert.Equal(t, "Awards", arena.NextAgendaItem.Name)
+	}
+
+	arena.Database.TruncateAgendaItems()
+	arena.updateNextAgendaItem()
+	assert.Nil(t, arena.NextAgendaItem)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for handling timeouts called by the playoff alliances, which are limited in number for each alliance.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Starts a timeout on behalf of the given alliance ("red" or "blue") in the current playoff match and charges it
+// against that alliance's allotment.
+func (arena *Arena) CallAllianceTimeout(alliance string) error {
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot start timeout while there is a match still in progress or with results pending")
+	}
+	if arena.CurrentMatch.Type != model.Playoff {
+		return fmt.Errorf("alliance timeouts can only be called during the playoffs")
+	}
+
+	var allianceId int
+	switch alliance {
+	case "red":
+		allianceId = arena.CurrentMatch.PlayoffRedAlliance
+	case "blue":
+		allianceId = arena.CurrentMatch.PlayoffBlueAlliance
+	default:
+		return fmt.Errorf("invalid alliance '%s'", alliance)
+	}
+	playoffAlliance, err := arena.Database.GetAllianceById(allianceId)
+	if err != nil {
+		return err
+	}
+	if playoffAlliance == nil {
+		return fmt.Errorf("alliance %d does not exist", allianceId)
+	}
+	if playoffAlliance.TimeoutsUsed >= arena.EventSettings.PlayoffTimeoutsPerAlliance {
+		return fmt.Errorf("alliance %d has no timeouts remaining", allianceId)
+	}
+
+	playoffAlliance.TimeoutsUsed++
+	if err = arena.Database.UpdateAlliance(playoffAlliance); err != nil {
+		return err
+	}
+	return arena.StartTimeout(
+		fmt.Sprintf("Alliance %d Timeout", allianceId), arena.EventSettings.PlayoffTimeoutDurationSec,
+	)
+}
+
+// Returns the number of timeouts that the given playoff alliance has yet to use.
+func (arena *Arena) allianceTimeoutsRemaining(allianceId int) int {
+	playoffAlliance, err := arena.Database.GetAllianceById(allianceId)
+	if err != nil || playoffAlliance == nil {
+		return 0
+	}
+	return max(arena.EventSettings.PlayoffTimeoutsPerAlliance-playoffAlliance.TimeoutsUsed, 0)
+}
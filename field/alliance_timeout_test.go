@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCallAllianceTimeout(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.PlayoffTimeoutsPerAlliance = 1
+	arena.EventSettings.PlayoffTimeoutDurationSec = 360
+	arena.Database.CreateAlliance(&model.Alliance{Id: 2, TeamIds: []int{254, 1114, 2056}})
+	arena.Database.CreateAlliance(&model.Alliance{Id: 7, TeamIds: []int{1678, 604, 8}})
+
+	err := arena.CallAllianceTimeout("red")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "only be called during the playoffs")
+	}
+
+	arena.CurrentMatch = &model.Match{Type: model.Playoff, PlayoffRedAlliance: 2, PlayoffBlueAlliance: 7}
+	assert.Equal(t, 1, arena.allianceTimeoutsRemaining(2))
+	err = arena.CallAllianceTimeout("purple")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid alliance")
+	}
+
+	assert.Nil(t, arena.CallAllianceTimeout("blue"))
+	assert.Equal(t, TimeoutActive, arena.MatchState)
+	assert.Equal(t, "Alliance 7 Timeout", arena.breakDescription)
+	assert.Equal(t, 0, arena.allianceTimeoutsRemaining(7))
+	assert.Equal(t, 1, arena.allianceTimeoutsRemaining(2))
+	alliance, _ := arena.Database.GetAllianceById(7)
+	assert.Equal(t, 1, alliance.TimeoutsUsed)
+
+	// Another timeout can't be started while one is underway.
+	err = arena.CallAllianceTimeout("red")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot start timeout")
+	}
+	assert.Equal(t, 1, arena.allianceTimeoutsRemaining(2))
+
+	// An alliance can't exceed its allotment.
+	arena.MatchState = PreMatch
+	err = arena.CallAllianceTimeout("blue")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "alliance 7 has no timeouts remaining")
+	}
+	assert.Nil(t, arena.CallAllianceTimeout("red"))
+	assert.Equal(t, "Alliance 2 Timeout", arena.breakDescription)
+}
@@ -7,17 +7,24 @@ package field
 
 import (
 	"fmt"
-	"log"
 	"math"
+	"math/rand"
 	"reflect"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/network"
+	"github.com/Team254/cheesy-arena/notification"
 	"github.com/Team254/cheesy-arena/partner"
 	"github.com/Team254/cheesy-arena/playoff"
 	"github.com/Team254/cheesy-arena/plc"
+	"github.com/Team254/cheesy-arena/scoreboard"
+	"github.com/Team254/cheesy-arena/updater"
 )
 
 const (
@@ -32,6 +39,13 @@ const (
 	MaxMatchGapMin           = 20
 )
 
+// Audience display outputs that can be shown different screens from one another; the stage output drives the screen in
+// the venue and the stream output is keyed over the broadcast.
+const (
+	StageAudienceDisplay  = "stage"
+	StreamAudienceDisplay = "stream"
+)
+
 // Progression of match states.
 type MatchState int
 
@@ -45,19 +59,30 @@ const (
 	PostMatch
 	TimeoutActive
 	PostTimeout
+	FieldFault
 )
 
 type Arena struct {
+	FieldId          int // Number of the field that the arena runs, counting from 1.
+	fieldGroup       *fieldGroup
 	Database         *model.Database
 	EventSettings    *model.EventSettings
 	accessPoint      network.AccessPoint
 	networkSwitch    *network.Switch
 	Plc              plc.Plc
 	TbaClient        *partner.TbaClient
+	TbaPublisher     *partner.TbaPublisher
 	NexusClient      *partner.NexusClient
+	FirstClient      *partner.FirstClient
+	PushNotifier     *notification.PushNotifier
+	ReplayClient     *partner.ReplayClient
+	Updater          *updater.Updater
 	AllianceStations map[string]*AllianceStation
 	Displays         map[string]*Display
 	TeamSigns        *TeamSigns
+	PracticeField    *PracticeField
+	Lighting         lighting.SacnController
+	Scoreboard       scoreboard.Controller
 	ScoringPanelRegistry
 	ArenaNotifiers
 	MatchState
@@ -70,8 +95,12 @@ type Arena struct {
 	lastDsPacketTime                  time.Time
 	lastPeriodicTaskTime              time.Time
 	EventStatus                       EventStatus
+	NextAgendaItem                    *model.AgendaItem // The run of show item under way or coming up next, if any.
 	FieldReset                        bool
+	ScoreReview                       *ScoreReview
+	CardsConfirmed                    bool // Whether the head referee has confirmed the cards for the current match.
 	AudienceDisplayMode               string
+	StreamAudienceDisplayMode         string
 	SavedMatch                        *model.Match
 	SavedMatchResult                  *model.MatchResult
 	SavedRankings                     game.Rankings
@@ -83,11 +112,43 @@ type Arena struct {
 	PlayoffTournament                 *playoff.PlayoffTournament
 	LowerThird                        *model.LowerThird
 	ShowLowerThird                    bool
+	LowerThirdPlaylistId              int
+	LowerThirdPlaylistPosition        int
 	MuteMatchSounds                   bool
+	OnDeck                            *OnDeckMatch
+	NextMatchOverrideId               int
+	RobotSimulationEnabled            bool
+	InterruptedMatch                  *model.ArenaSnapshot
+	StaffReadiness                    StaffReadiness
+	ShowFlowPosition                  int
 	matchAborted                      bool
+	faultedMatchState                 MatchState
+	fieldFaultStartTime               time.Time
 	soundsPlayed                      map[*game.MatchSound]struct{}
 	breakDescription                  string
 	preloadedTeams                    *[6]*model.Team
+	foulMutex                         sync.Mutex
+	matchTimeline                     []model.MatchTimelineEvent
+	timelineMutex                     sync.Mutex
+	pendingSettings                   *model.EventSettings
+	settingsMutex                     sync.Mutex
+	robotSimulation                   *robotSimulation
+	matchCues                         MatchCuesMessage
+	simulationRand                    *rand.Rand
+	interruptedMatchName              string
+	snapshotSaved                     bool
+	lastSnapshotTime                  time.Time
+	lastAutoBackupTime                time.Time
+	videoStreamSync                   *model.VideoMarker
+	showFlowStepTime                  time.Time
+	showFlowAutoAdvanceSec            int
+	activeVideoStinger                *model.VideoStinger
+	videoStingerStartTime             time.Time
+	replayBannerEndTime               time.Time
+	captions                          []Caption
+	captionsMutex                     sync.Mutex
+	displayAlerts                     []DisplayAlert
+	restartRequests                   chan struct{}
 }
 
 type AllianceStation struct {
@@ -99,10 +160,34 @@ type AllianceStation struct {
 	Team       *model.Team
 	WifiStatus network.TeamWifiStatus
 	aStopReset bool
+
+	RadioPairingIssue       string // Any mismatch between the station's team and the network its robot is connected to.
+	BypassReason            string // Why the station is bypassed, if it is.
+	ExpectedRadioMacAddress string // MAC address of the robot radio registered for the station's team at check-in.
+
+	timelineDsLinked    bool
+	timelineRobotLinked bool
 }
 
-// Creates the arena and sets it to its initial state.
+// Creates the arena for the first field and sets it to its initial state.
 func NewArena(dbPath string) (*Arena, error) {
+	arena := newArena()
+	arena.FieldId = 1
+	arena.fieldGroup = &fieldGroup{arenas: []*Arena{arena}}
+
+	// Checking for software updates is only turned on by the caller, which knows where to check.
+	arena.Updater = updater.NewUpdater("", updater.Version, updater.StagingDir)
+	arena.restartRequests = make(chan struct{}, 1)
+
+	if err := arena.openDatabase(dbPath); err != nil {
+		return nil, err
+	}
+	arena.initializeMatchState()
+	return arena, nil
+}
+
+// Creates an arena with the components that every field has of its own, not yet connected to the event database.
+func newArena() *Arena {
 	arena := new(Arena)
 	arena.configureNotifiers()
 	arena.Plc = new(plc.ModbusPlc)
@@ -118,51 +203,180 @@ func NewArena(dbPath string) (*Arena, error) {
 	arena.Displays = make(map[string]*Display)
 
 	arena.TeamSigns = NewTeamSigns()
-
-	var err error
-	arena.Database, err = model.OpenDatabase(dbPath)
-	if err != nil {
-		return nil, err
-	}
-	err = arena.LoadSettings()
-	if err != nil {
-		return nil, err
-	}
-
+	arena.PracticeField = NewPracticeField()
+	arena.networkSwitch = network.NewSwitch("", "")
 	arena.ScoringPanelRegistry.initialize()
+	return arena
+}
 
+// Puts the arena into its initial state once its event settings have been loaded.
+func (arena *Arena) initializeMatchState() {
 	// Load empty match as current.
 	arena.MatchState = PreMatch
 	arena.LoadTestMatch()
 	arena.LastMatchTimeSec = 0
 	arena.lastMatchState = -1
+	arena.ShowFlowPosition = -1
+	arena.LowerThirdPlaylistPosition = -1
+	arena.lastAutoBackupTime = time.Now()
 
 	// Initialize display parameters.
 	arena.AudienceDisplayMode = "blank"
+	arena.StreamAudienceDisplayMode = "blank"
 	arena.SavedMatch = &model.Match{}
 	arena.SavedMatchResult = model.NewMatchResult()
 	arena.AllianceStationDisplayMode = "match"
+}
 
-	return arena, nil
+// Opens the event database at the given path and loads the state that is derived from it.
+func (arena *Arena) openDatabase(dbPath string) error {
+	var err error
+	arena.Database, err = model.OpenDatabase(dbPath)
+	if err != nil {
+		return err
+	}
+	arena.PushNotifier = notification.NewPushNotifier(arena.Database, arena.getWebPushCredentials)
+	if arena.TbaPublisher != nil {
+		arena.TbaPublisher.Stop()
+	}
+	arena.TbaPublisher = partner.NewTbaPublisher(arena.Database, arena.getTbaPublishingClient)
+	if err = arena.LoadSettings(); err != nil {
+		return err
+	}
+	arena.InterruptedMatch = nil
+	if err = arena.loadInterruptedMatch(); err != nil {
+		return err
+	}
+	arena.videoStreamSync, err = arena.Database.GetLatestVideoMarker(model.VideoMarkerStreamSync)
+	return err
+}
+
+// Closes the event database, calls the given function to replace its file on disk, and then opens the replacement and
+// reloads everything that depends on it. The database is reopened even if the replacement fails, so that the arena is
+// left in a usable state. This is only allowed between matches.
+func (arena *Arena) ReplaceDatabase(replaceFile func(dbPath string) error) error {
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot change the event database while a match is in progress")
+	}
+	if !arena.Database.IsEmbedded() {
+		return fmt.Errorf("the event database can only be replaced when using the embedded database")
+	}
+	if len(arena.Fields()) > 1 {
+		return fmt.Errorf("the event database can't be replaced while running more than one field")
+	}
+	dbPath := arena.Database.Path
+	if err := arena.Database.Close(); err != nil {
+		return err
+	}
+	replaceErr := replaceFile(dbPath)
+	if err := arena.openDatabase(dbPath); err != nil {
+		return err
+	}
+	if replaceErr != nil {
+		return replaceErr
+	}
+
+	arena.AllianceSelectionAlliances = []model.Alliance{}
+	arena.AllianceSelectionRankedTeams = []model.AllianceSelectionRankedTeam{}
+	if err := arena.LoadTestMatch(); err != nil {
+		return err
+	}
+	arena.ReloadDisplaysNotifier.Notify()
+	return nil
 }
 
-// Loads or reloads the event settings upon initial setup or change.
+// Loads or reloads the event settings upon initial setup or change, for every field since they share the settings. If a
+// match is under way, the new settings are held back and applied all at once as soon as the arena returns to the
+// pre-match state, so that the match timing, network configuration and so on can't change partway through a match.
 func (arena *Arena) LoadSettings() error {
+	for _, fieldArena := range arena.Fields() {
+		if err := fieldArena.loadSettings(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Loads or reloads the event settings for this arena's field alone.
+func (arena *Arena) loadSettings() error {
 	settings, err := arena.Database.GetEventSettings()
 	if err != nil {
 		return err
 	}
+
+	arena.settingsMutex.Lock()
+	defer arena.settingsMutex.Unlock()
+	if arena.MatchState != PreMatch {
+		logging.Arena.Info("Deferring the event settings changes until the current match is over.")
+		arena.pendingSettings = settings
+		arena.EventSettingsNotifier.Notify()
+		return nil
+	}
+	arena.pendingSettings = nil
+	return arena.applySettings(settings)
+}
+
+// Returns a copy of the event settings as most recently saved, including any changes that are still pending until the
+// current match is over, for the caller to modify and save before calling LoadSettings().
+func (arena *Arena) SavedEventSettings() *model.EventSettings {
+	arena.settingsMutex.Lock()
+	defer arena.settingsMutex.Unlock()
+	settings := *arena.EventSettings
+	if arena.pendingSettings != nil {
+		settings = *arena.pendingSettings
+	}
+	return &settings
+}
+
+// Returns true if there are saved event settings that are waiting for the current match to end before taking effect.
+func (arena *Arena) SettingsChangesPending() bool {
+	arena.settingsMutex.Lock()
+	defer arena.settingsMutex.Unlock()
+	return arena.pendingSettings != nil
+}
+
+// Returns the status of the field access point as of its most recent poll.
+func (arena *Arena) AccessPointStatus() string {
+	return arena.accessPoint.Status
+}
+
+// Applies any deferred event settings once the arena is back in the pre-match state.
+func (arena *Arena) applyPendingSettings() {
+	if arena.MatchState != PreMatch {
+		return
+	}
+	arena.settingsMutex.Lock()
+	defer arena.settingsMutex.Unlock()
+	if arena.pendingSettings == nil {
+		return
+	}
+	settings := arena.pendingSettings
+	arena.pendingSettings = nil
+	if err := arena.applySettings(settings); err != nil {
+		logging.Arena.Error("Failed to apply the deferred event settings.", "error", err)
+	}
+}
+
+// Reconfigures the arena and the components that depend on the event settings to use the given settings.
+func (arena *Arena) applySettings(settings *model.EventSettings) error {
+	previousSettings := arena.EventSettings
 	arena.EventSettings = settings
 
+	// Only the first field drives the field hardware, so the others are set up as if none were configured.
+	hardwareSettings := settings
+	if !arena.IsFirstField() {
+		hardwareSettings = &model.EventSettings{ScoreboardFormat: settings.ScoreboardFormat}
+	}
+
 	// Initialize the components that depend on settings.
-	arena.TeamSigns.Red1.SetAddress(settings.TeamSignRed1Address)
-	arena.TeamSigns.Red2.SetAddress(settings.TeamSignRed2Address)
-	arena.TeamSigns.Red3.SetAddress(settings.TeamSignRed3Address)
-	arena.TeamSigns.RedTimer.SetAddress(settings.TeamSignRedTimerAddress)
-	arena.TeamSigns.Blue1.SetAddress(settings.TeamSignBlue1Address)
-	arena.TeamSigns.Blue2.SetAddress(settings.TeamSignBlue2Address)
-	arena.TeamSigns.Blue3.SetAddress(settings.TeamSignBlue3Address)
-	arena.TeamSigns.BlueTimer.SetAddress(settings.TeamSignBlueTimerAddress)
+	arena.TeamSigns.Red1.SetAddress(hardwareSettings.TeamSignRed1Address)
+	arena.TeamSigns.Red2.SetAddress(hardwareSettings.TeamSignRed2Address)
+	arena.TeamSigns.Red3.SetAddress(hardwareSettings.TeamSignRed3Address)
+	arena.TeamSigns.RedTimer.SetAddress(hardwareSettings.TeamSignRedTimerAddress)
+	arena.TeamSigns.Blue1.SetAddress(hardwareSettings.TeamSignBlue1Address)
+	arena.TeamSigns.Blue2.SetAddress(hardwareSettings.TeamSignBlue2Address)
+	arena.TeamSigns.Blue3.SetAddress(hardwareSettings.TeamSignBlue3Address)
+	arena.TeamSigns.BlueTimer.SetAddress(hardwareSettings.TeamSignBlueTimerAddress)
 	accessPointWifiStatuses := [6]*network.TeamWifiStatus{
 		&arena.AllianceStations["R1"].WifiStatus,
 		&arena.AllianceStations["R2"].WifiStatus,
@@ -172,23 +386,33 @@ func (arena *Arena) LoadSettings() error {
 		&arena.AllianceStations["B3"].WifiStatus,
 	}
 	arena.accessPoint.SetSettings(
-		settings.ApAddress,
-		settings.ApPassword,
-		settings.ApChannel,
-		settings.NetworkSecurityEnabled,
+		hardwareSettings.ApAddress,
+		hardwareSettings.ApPassword,
+		hardwareSettings.ApChannel,
+		hardwareSettings.NetworkSecurityEnabled,
 		accessPointWifiStatuses,
 	)
-	arena.networkSwitch = network.NewSwitch(settings.SwitchAddress, settings.SwitchPassword)
-	arena.Plc.SetAddress(settings.PlcAddress)
+	arena.networkSwitch = network.NewSwitch(hardwareSettings.SwitchAddress, hardwareSettings.SwitchPassword)
+	arena.Plc.SetAddress(hardwareSettings.PlcAddress)
+	arena.Lighting.SetAddress(hardwareSettings.LightingAddress, hardwareSettings.LightingUniverse)
+	if err := arena.Scoreboard.Configure(
+		hardwareSettings.ScoreboardAddress, hardwareSettings.ScoreboardFormat, hardwareSettings.ScoreboardTemplate,
+	); err != nil {
+		logging.Hardware.Error(
+			"Failed to connect to scoreboard controller.", "address", hardwareSettings.ScoreboardAddress, "error", err,
+		)
+	}
 	arena.TbaClient = partner.NewTbaClient(settings.TbaEventCode, settings.TbaSecretId, settings.TbaSecret)
+	arena.TbaClient.Location = settings.Location()
 	arena.NexusClient = partner.NewNexusClient(settings.TbaEventCode)
+	arena.FirstClient = partner.NewFirstClient(settings.FirstApiUsername, settings.FirstApiKey, settings.FirstEventCode)
+	arena.ReplayClient = partner.NewReplayClient(settings.ReplayAddress)
 
-	game.MatchTiming.WarmupDurationSec = settings.WarmupDurationSec
-	game.MatchTiming.AutoDurationSec = settings.AutoDurationSec
-	game.MatchTiming.PauseDurationSec = settings.PauseDurationSec
-	game.MatchTiming.TeleopDurationSec = settings.TeleopDurationSec
-	game.MatchTiming.WarningRemainingDurationSec = settings.WarningRemainingDurationSec
-	game.UpdateMatchSounds()
+	if err := game.SetCurrentGame(settings.GameKey); err != nil {
+		return err
+	}
+	game.MatchSoundCues = settings.MatchSoundCues
+	arena.applyMatchTiming()
 	arena.MatchTimingNotifier.Notify()
 
 	game.MelodyBonusThresholdWithoutCoop = settings.MelodyBonusThresholdWithoutCoop
@@ -196,24 +420,54 @@ func (arena *Arena) LoadSettings() error {
 	game.AmplificationNoteLimit = settings.AmplificationNoteLimit
 	game.AmplificationDurationSec = settings.AmplificationDurationSec
 
-	// Reconstruct the playoff tournament in memory.
-	if err = arena.CreatePlayoffTournament(); err != nil {
-		return err
+	// Reconstruct the playoff tournament in memory, or adopt that of the first field, which all the fields share.
+	if arena.IsFirstField() {
+		if err := arena.CreatePlayoffTournament(); err != nil {
+			return err
+		}
+		if err := arena.UpdatePlayoffTournament(); err != nil {
+			return err
+		}
+	} else {
+		arena.PlayoffTournament = arena.Field(1).PlayoffTournament
 	}
-	if err = arena.UpdatePlayoffTournament(); err != nil {
-		return err
+
+	// Have the displays reload if anything they render once upon loading has changed.
+	if previousSettings != nil &&
+		(settings.Name != previousSettings.Name || settings.SoundPackId != previousSettings.SoundPackId ||
+			settings.ThemeAccentColor != previousSettings.ThemeAccentColor ||
+			settings.ThemeFontFile != previousSettings.ThemeFontFile ||
+			settings.ThemeLogoFile != previousSettings.ThemeLogoFile ||
+			settings.ThemeBackgroundFile != previousSettings.ThemeBackgroundFile) {
+		arena.ReloadDisplaysNotifier.Notify()
 	}
+	arena.EventSettingsNotifier.Notify()
+	arena.PitDisplayNotifier.Notify()
 
 	return nil
 }
 
-// Constructs an empty playoff tournament in memory, based only on the number of alliances.
+// Returns the client with which the TBA publisher should publish, or nil if publishing is disabled.
+func (arena *Arena) getTbaPublishingClient() *partner.TbaClient {
+	if !arena.EventSettings.TbaPublishingEnabled {
+		return nil
+	}
+	return arena.TbaClient
+}
+
+// Constructs an empty playoff tournament in memory, based only on the number of alliances, and shares it with all the
+// fields.
 func (arena *Arena) CreatePlayoffTournament() error {
-	var err error
-	arena.PlayoffTournament, err = playoff.NewPlayoffTournament(
+	playoffTournament, err := playoff.NewPlayoffTournament(
 		arena.EventSettings.PlayoffType, arena.EventSettings.NumPlayoffAlliances,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	for _, fieldArena := range arena.Fields() {
+		fieldArena.PlayoffTournament = playoffTournament
+	}
+	return nil
 }
 
 // Performs the one-time creation of all matches for the playoff tournament.
@@ -223,12 +477,18 @@ func (arena *Arena) CreatePlayoffMatches(startTime time.Time) error {
 
 // Traverses the playoff tournament rounds to assess winners and populate subsequent matches.
 func (arena *Arena) UpdatePlayoffTournament() error {
-	alliances, err := arena.Database.GetAllAlliances()
+	return arena.UpdatePlayoffTournamentFrom(arena.Database)
+}
+
+// Same as UpdatePlayoffTournament, but reads and writes the playoff matches through the given database, such as a view
+// of the event database within a transaction.
+func (arena *Arena) UpdatePlayoffTournamentFrom(database *model.Database) error {
+	alliances, err := database.GetAllAlliances()
 	if err != nil {
 		return err
 	}
 	if len(alliances) > 0 {
-		return arena.PlayoffTournament.UpdateMatches(arena.Database)
+		return arena.PlayoffTournament.UpdateMatches(database)
 	}
 	return nil
 }
@@ -238,22 +498,32 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 	if arena.MatchState != PreMatch {
 		return fmt.Errorf("cannot load match while there is a match still in progress or with results pending")
 	}
+	if err := arena.checkMatchNotOnOtherField(match); err != nil {
+		return err
+	}
 
 	arena.CurrentMatch = match
 
+	// Fill any open practice match slots from the filler line.
+	if err := arena.assignFillerTeams(match); err != nil {
+		return err
+	}
+
 	loadedByNexus := false
 	if match.ShouldAllowNexusSubstitution() && arena.EventSettings.NexusEnabled {
 		// Attempt to get the match lineup from Nexus for FRC.
 		lineup, err := arena.NexusClient.GetLineup(match.TbaMatchKey)
 		if err != nil {
-			log.Printf("Failed to load lineup from Nexus: %s", err.Error())
+			logging.Partner.Error("Failed to load lineup from Nexus.", "error", err)
 		} else {
 			err = arena.SubstituteTeams(lineup[0], lineup[1], lineup[2], lineup[3], lineup[4], lineup[5])
 			if err != nil {
-				log.Printf("Failed to substitute teams using Nexus lineup; loading match normally: %s", err.Error())
+				logging.Partner.Warn(
+					"Failed to substitute teams using Nexus lineup; loading match normally.", "error", err,
+				)
 			} else {
-				log.Printf(
-					"Successfully loaded lineup for match %s from Nexus: %v", match.TbaMatchKey.String(), *lineup,
+				logging.Partner.Info(
+					"Successfully loaded lineup from Nexus.", "match", match.TbaMatchKey.String(), "lineup", *lineup,
 				)
 				loadedByNexus = true
 			}
@@ -299,29 +569,75 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 		)
 	}
 
+	// Switch to the timing for the new match's type, in case it differs from that of the previous match.
+	if arena.applyMatchTiming() {
+		arena.MatchTimingNotifier.Notify()
+	}
+
 	// Reset the arena state and realtime scores.
 	arena.soundsPlayed = make(map[*game.MatchSound]struct{})
 	arena.RedRealtimeScore = NewRealtimeScore()
 	arena.BlueRealtimeScore = NewRealtimeScore()
 	arena.ScoringPanelRegistry.resetScoreCommitted()
 	arena.Plc.ResetMatch()
+	arena.resetMatchTimeline()
+	arena.ScoreReview = nil
+	arena.CardsConfirmed = false
+	arena.resetStaffReadiness()
 
 	// Notify any listeners about the new match.
 	arena.MatchLoadNotifier.Notify()
+	arena.StationStopsNotifier.Notify()
 	arena.RealtimeScoreNotifier.Notify()
+	arena.LiveScoreNotifier.Notify()
 	arena.AllianceStationDisplayMode = "match"
 	arena.AllianceStationDisplayModeNotifier.Notify()
 	arena.ScoringStatusNotifier.Notify()
+	if match.Id == arena.NextMatchOverrideId {
+		arena.NextMatchOverrideId = 0
+	}
+	arena.UpdateOnDeckMatch()
+	arena.notifyUpcomingMatch()
+
+	// The other fields can no longer have this match on deck.
+	for _, fieldArena := range arena.Fields() {
+		if fieldArena != arena && fieldArena.OnDeck != nil && fieldArena.OnDeck.Match.Id == match.Id {
+			fieldArena.UpdateOnDeckMatch()
+		}
+	}
 
 	return nil
 }
 
+// Picks up changes made directly to the schedule in the database, such as later matches having been moved back to make
+// room for a replay, so that saving the loaded match afterwards doesn't write its old position back over them. Applies
+// to every field.
+func (arena *Arena) RefreshSchedule() error {
+	for _, fieldArena := range arena.Fields() {
+		if fieldArena.CurrentMatch.Type != model.Test {
+			match, err := arena.Database.GetMatchById(fieldArena.CurrentMatch.Id)
+			if err != nil {
+				return err
+			}
+			if match != nil {
+				fieldArena.CurrentMatch.TypeOrder = match.TypeOrder
+				fieldArena.CurrentMatch.Time = match.Time
+				fieldArena.CurrentMatch.ReplacedByMatchId = match.ReplacedByMatchId
+				fieldArena.CurrentMatch.ReplayReason = match.ReplayReason
+			}
+		}
+		fieldArena.UpdateOnDeckMatch()
+	}
+	return nil
+}
+
 // Sets a new test match containing no teams as the current match.
 func (arena *Arena) LoadTestMatch() error {
 	return arena.LoadMatch(&model.Match{Type: model.Test, ShortName: "T", LongName: "Test Match"})
 }
 
-// Loads the first unplayed match of the current match type.
+// Loads the match chosen to be played next, which is the first unplayed match of the current match type unless it
+// has been overridden.
 func (arena *Arena) LoadNextMatch(startScheduledBreak bool) error {
 	nextMatch, err := arena.getNextMatch(false)
 	if err != nil {
@@ -421,19 +737,22 @@ func (arena *Arena) StartMatch() error {
 			if allianceStation.DsConn != nil {
 				err = allianceStation.DsConn.signalMatchStart(arena.CurrentMatch, &allianceStation.WifiStatus)
 				if err != nil {
-					log.Println(err)
+					logging.Network.Error(
+						"Failed to signal match start to driver station.", "team", allianceStation.DsConn.TeamId,
+						"error", err,
+					)
 				}
 			}
 
 			// Save the teams that have successfully connected to the field.
-			if allianceStation.Team != nil && !allianceStation.Team.HasConnected && allianceStation.DsConn != nil &&
-				allianceStation.DsConn.RobotLinked {
-				allianceStation.Team.HasConnected = true
-				arena.Database.UpdateTeam(allianceStation.Team)
+			if allianceStation.Team != nil && allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked {
+				arena.markTeamConnected(allianceStation.Team.Id)
 			}
 		}
 
 		arena.MatchState = StartMatch
+		arena.resetStaffReadiness()
+		arena.NotifyWebhooks(partner.WebhookMatchStarted, NewWebhookMatchData(arena.CurrentMatch))
 	}
 	return err
 }
@@ -455,8 +774,8 @@ func (arena *Arena) AbortMatch() error {
 	}
 	arena.MatchState = PostMatch
 	arena.matchAborted = true
-	arena.AudienceDisplayMode = "blank"
-	arena.AudienceDisplayModeNotifier.Notify()
+	arena.recordVideoMarker(model.VideoMarkerMatchEnd, time.Now())
+	arena.setAudienceDisplayModes("blank")
 	arena.AllianceStationDisplayMode = "logo"
 	arena.AllianceStationDisplayModeNotifier.Notify()
 	return nil
@@ -469,16 +788,37 @@ func (arena *Arena) ResetMatch() error {
 	}
 	arena.MatchState = PreMatch
 	arena.matchAborted = false
-	arena.AllianceStations["R1"].Bypass = false
-	arena.AllianceStations["R2"].Bypass = false
-	arena.AllianceStations["R3"].Bypass = false
-	arena.AllianceStations["B1"].Bypass = false
-	arena.AllianceStations["B2"].Bypass = false
-	arena.AllianceStations["B3"].Bypass = false
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = false
+		allianceStation.BypassReason = ""
+	}
 	arena.MuteMatchSounds = false
 	return nil
 }
 
+// Sets the period durations and sound schedule from the event settings, using the demo match overrides if they are
+// enabled and the current match is a test match. Returns true if the period durations changed as a result.
+func (arena *Arena) applyMatchTiming() bool {
+	settings := arena.EventSettings
+	previousMatchTiming := game.MatchTiming
+	game.MatchTiming.WarmupDurationSec = settings.WarmupDurationSec
+	// The match timing is shared by all the fields, so the demo timing only applies when there is just one.
+	if settings.DemoMatchTimingEnabled && len(arena.Fields()) == 1 && arena.CurrentMatch != nil &&
+		arena.CurrentMatch.Type == model.Test {
+		game.MatchTiming.AutoDurationSec = settings.DemoAutoDurationSec
+		game.MatchTiming.PauseDurationSec = settings.DemoPauseDurationSec
+		game.MatchTiming.TeleopDurationSec = settings.DemoTeleopDurationSec
+		game.MatchTiming.WarningRemainingDurationSec = settings.DemoWarningRemainingDurationSec
+	} else {
+		game.MatchTiming.AutoDurationSec = settings.AutoDurationSec
+		game.MatchTiming.PauseDurationSec = settings.PauseDurationSec
+		game.MatchTiming.TeleopDurationSec = settings.TeleopDurationSec
+		game.MatchTiming.WarningRemainingDurationSec = settings.WarningRemainingDurationSec
+	}
+	game.UpdateMatchSounds()
+	return game.MatchTiming != previousMatchTiming
+}
+
 // Starts a timeout of the given duration.
 func (arena *Arena) StartTimeout(description string, durationSec int) error {
 	if arena.MatchState != PreMatch {
@@ -500,17 +840,58 @@ func (arena *Arena) StartTimeout(description string, durationSec int) error {
 	return nil
 }
 
-// Updates the audience display screen.
+// Updates the screen shown on all audience display outputs. The final score screen is held back while the scores are
+// pending review.
 func (arena *Arena) SetAudienceDisplayMode(mode string) {
-	if arena.AudienceDisplayMode != mode {
+	arena.updateAudienceDisplayModes(mode, true, true)
+}
+
+// Updates the screen shown on only the given audience display output, leaving the other one as it is.
+func (arena *Arena) SetAudienceDisplayOutputMode(output, mode string) error {
+	switch output {
+	case StageAudienceDisplay:
+		arena.updateAudienceDisplayModes(mode, true, false)
+	case StreamAudienceDisplay:
+		arena.updateAudienceDisplayModes(mode, false, true)
+	default:
+		return fmt.Errorf("Invalid audience display output '%s'.", output)
+	}
+	return nil
+}
+
+func (arena *Arena) updateAudienceDisplayModes(mode string, stage, stream bool) {
+	if mode == "score" && arena.ScoreReviewPending() {
+		return
+	}
+	if mode != videoStingerScreen && arena.activeVideoStinger != nil {
+		// Cut off any video stinger that is still playing.
+		arena.activeVideoStinger = nil
+		arena.VideoStingerNotifier.Notify()
+	}
+	changed := false
+	if stage && arena.AudienceDisplayMode != mode {
 		arena.AudienceDisplayMode = mode
 		arena.AudienceDisplayModeNotifier.Notify()
-		if mode == "score" {
-			arena.playSound("match_result")
-		}
+		changed = true
+	}
+	if stream && arena.StreamAudienceDisplayMode != mode {
+		arena.StreamAudienceDisplayMode = mode
+		arena.StreamAudienceDisplayModeNotifier.Notify()
+		changed = true
+	}
+	if changed && mode == "score" {
+		arena.playSound("match_result")
 	}
 }
 
+// Shows the given screen on all audience display outputs as part of the automatic progression of the match.
+func (arena *Arena) setAudienceDisplayModes(mode string) {
+	arena.AudienceDisplayMode = mode
+	arena.AudienceDisplayModeNotifier.Notify()
+	arena.StreamAudienceDisplayMode = mode
+	arena.StreamAudienceDisplayModeNotifier.Notify()
+}
+
 // Updates the alliance station display screen.
 func (arena *Arena) SetAllianceStationDisplayMode(mode string) {
 	if arena.AllianceStationDisplayMode != mode {
@@ -519,15 +900,36 @@ func (arena *Arena) SetAllianceStationDisplayMode(mode string) {
 	}
 }
 
-// Returns the fractional number of seconds since the start of the match.
+// Returns the fractional number of seconds since the start of the match, which stands still during a field fault.
 func (arena *Arena) MatchTimeSec() float64 {
 	if arena.MatchState == PreMatch || arena.MatchState == StartMatch || arena.MatchState == PostMatch {
 		return 0
+	} else if arena.MatchState == FieldFault {
+		return arena.fieldFaultStartTime.Sub(arena.MatchStartTime).Seconds()
 	} else {
 		return time.Since(arena.MatchStartTime).Seconds()
 	}
 }
 
+// Returns the number of whole seconds remaining in the current period of the match or timeout, as shown on the timer
+// signs and venue scoreboard.
+func (arena *Arena) countdownSec() int {
+	matchTimeSec := int(arena.MatchTimeSec())
+	switch arena.MatchState {
+	case PreMatch, StartMatch, WarmupPeriod:
+		return game.MatchTiming.AutoDurationSec
+	case AutoPeriod:
+		return game.MatchTiming.WarmupDurationSec + game.MatchTiming.AutoDurationSec - matchTimeSec
+	case TeleopPeriod:
+		return game.MatchTiming.WarmupDurationSec + game.MatchTiming.AutoDurationSec +
+			game.MatchTiming.TeleopDurationSec + game.MatchTiming.PauseDurationSec - matchTimeSec
+	case TimeoutActive:
+		return game.MatchTiming.TimeoutDurationSec - matchTimeSec
+	default:
+		return 0
+	}
+}
+
 // Performs a single iteration of checking inputs and timers and setting outputs accordingly to control the
 // flow of a match.
 func (arena *Arena) Update() {
@@ -543,9 +945,9 @@ func (arena *Arena) Update() {
 	case StartMatch:
 		arena.MatchStartTime = time.Now()
 		arena.LastMatchTimeSec = -1
+		arena.recordVideoMarker(model.VideoMarkerMatchStart, arena.MatchStartTime)
 		auto = true
-		arena.AudienceDisplayMode = "match"
-		arena.AudienceDisplayModeNotifier.Notify()
+		arena.setAudienceDisplayModes("match")
 		arena.AllianceStationDisplayMode = "match"
 		arena.AllianceStationDisplayModeNotifier.Notify()
 		if game.MatchTiming.WarmupDurationSec > 0 {
@@ -596,14 +998,14 @@ func (arena *Arena) Update() {
 		enabled = true
 		if matchTimeSec >= game.GetDurationToTeleopEnd().Seconds() {
 			arena.MatchState = PostMatch
+			arena.recordVideoMarker(model.VideoMarkerMatchEnd, time.Now())
 			auto = false
 			enabled = false
 			sendDsPacket = true
 			go func() {
 				// Leave the scores on the screen briefly at the end of the match.
 				time.Sleep(time.Second * matchEndScoreDwellSec)
-				arena.AudienceDisplayMode = "blank"
-				arena.AudienceDisplayModeNotifier.Notify()
+				arena.setAudienceDisplayModes("blank")
 				arena.AllianceStationDisplayMode = "logo"
 				arena.AllianceStationDisplayModeNotifier.Notify()
 			}()
@@ -619,8 +1021,7 @@ func (arena *Arena) Update() {
 			go func() {
 				// Leave the timer on the screen briefly at the end of the timeout period.
 				time.Sleep(time.Second * matchEndScoreDwellSec)
-				arena.AudienceDisplayMode = "blank"
-				arena.AudienceDisplayModeNotifier.Notify()
+				arena.setAudienceDisplayModes("blank")
 				arena.AllianceStationDisplayMode = "logo"
 				arena.AllianceStationDisplayModeNotifier.Notify()
 			}()
@@ -629,11 +1030,20 @@ func (arena *Arena) Update() {
 		if matchTimeSec >= float64(game.MatchTiming.TimeoutDurationSec+postTimeoutSec) {
 			arena.MatchState = PreMatch
 		}
+	case FieldFault:
+		auto = arena.faultedMatchState == WarmupPeriod || arena.faultedMatchState == AutoPeriod
+		enabled = false
+		sendDsPacket = arena.lastMatchState != FieldFault
 	}
 
 	// Send a match tick notification if passing an integer second threshold or if the match state changed.
 	if int(matchTimeSec) != int(arena.LastMatchTimeSec) || arena.MatchState != arena.lastMatchState {
 		arena.MatchTimeNotifier.Notify()
+		arena.MatchClockNotifier.Notify()
+	}
+	if arena.MatchState != arena.lastMatchState {
+		// Whether an A-stop blocks the robot from being enabled depends on the period of the match.
+		arena.StationStopsNotifier.Notify()
 	}
 
 	// Send a packet if at a period transition point or if it's been long enough since the last one.
@@ -647,9 +1057,37 @@ func (arena *Arena) Update() {
 	// Handle field sensors/lights/actuators.
 	arena.handlePlcInputOutput()
 
+	// Stand in for the driver stations and field inputs if there aren't any real ones.
+	arena.updateRobotSimulation(matchTimeSec)
+
 	// Handle the team number / timer displays.
 	arena.TeamSigns.Update(arena)
 
+	// Handle the standalone practice field timer, which runs independently of the match on the main field.
+	arena.PracticeField.Update()
+
+	arena.checkRadioPairings()
+
+	// Handle the DMX lighting.
+	arena.updateLighting()
+
+	// Handle the venue scoreboard.
+	arena.updateScoreboard()
+
+	arena.updateMatchCues()
+
+	arena.updateMatchTimeline()
+	arena.updateScoreReview()
+	arena.updateShowFlow()
+	arena.updateVideoStinger()
+
+	// Save the state of any match in progress so that it can be recovered if the server goes down.
+	arena.updateArenaSnapshot()
+
+	// Pick up any settings changes that were made during the match now that it's over.
+	arena.applyPendingSettings()
+	arena.applyScheduledUpdate()
+
 	arena.LastMatchTimeSec = matchTimeSec
 	arena.lastMatchState = arena.MatchState
 }
@@ -657,10 +1095,12 @@ func (arena *Arena) Update() {
 // Loops indefinitely to track and update the arena components.
 func (arena *Arena) Run() {
 	// Start other loops in goroutines.
-	go arena.listenForDriverStations()
-	go arena.listenForDsUdpPackets()
-	go arena.accessPoint.Run()
-	go arena.Plc.Run()
+	if arena.IsFirstField() {
+		go arena.listenForDriverStations()
+		go arena.listenForDsUdpPackets()
+		go arena.accessPoint.Run()
+		go arena.Plc.Run()
+	}
 
 	for {
 		arena.Update()
@@ -706,6 +1146,11 @@ func (arena *Arena) assignTeam(teamId int, station string) error {
 		return fmt.Errorf("Invalid alliance station '%s'.", station)
 	}
 
+	// Stations beyond the configured alliance size are left empty.
+	if !arena.isAllianceStationActive(station) {
+		teamId = 0
+	}
+
 	// Force the A-stop to be reset by the new team if it is already pressed (if the PLC is enabled).
 	arena.AllianceStations[station].aStopReset = !arena.Plc.IsEnabled()
 
@@ -720,6 +1165,8 @@ func (arena *Arena) assignTeam(teamId int, station string) error {
 		arena.AllianceStations[station].DsConn = nil
 	}
 
+	arena.AllianceStations[station].ExpectedRadioMacAddress = ""
+
 	// Leave the station empty if the team number is zero.
 	if teamId == 0 {
 		arena.AllianceStations[station].Team = nil
@@ -734,29 +1181,14 @@ func (arena *Arena) assignTeam(teamId int, station string) error {
 	if team == nil {
 		team = &model.Team{Id: teamId}
 	}
-
-	arena.AllianceStations[station].Team = team
-	return nil
-}
-
-// Returns the next match of the same type that is currently loaded, or nil if there are no more matches.
-func (arena *Arena) getNextMatch(excludeCurrent bool) (*model.Match, error) {
-	if arena.CurrentMatch.Type == model.Test {
-		return nil, nil
-	}
-
-	matches, err := arena.Database.GetMatchesByType(arena.CurrentMatch.Type, false)
+	teamStatus, err := arena.Database.GetTeamStatus(teamId)
 	if err != nil {
-		return nil, err
-	}
-	for _, match := range matches {
-		if !match.IsComplete() && !(excludeCurrent && match.Id == arena.CurrentMatch.Id) {
-			return &match, nil
-		}
+		return err
 	}
 
-	// There are no matches left of the same type.
-	return nil, nil
+	arena.AllianceStations[station].Team = team
+	arena.AllianceStations[station].ExpectedRadioMacAddress = teamStatus.RadioMacAddress
+	return nil
 }
 
 // Configures the field network for the next match in advance of the current match being scored and committed.
@@ -766,27 +1198,14 @@ func (arena *Arena) preLoadNextMatch() {
 		return
 	}
 
-	nextMatch, err := arena.getNextMatch(true)
-	if err != nil {
-		log.Printf("Failed to pre-load next match: %s", err.Error())
-	}
-	if nextMatch == nil {
+	// Refresh the on-deck match in case the schedule or teams have changed since the current match was loaded.
+	arena.UpdateOnDeckMatch()
+	if arena.OnDeck == nil {
 		return
 	}
 
-	var teams [6]*model.Team
-	for i, teamId := range []int{
-		nextMatch.Red1, nextMatch.Red2, nextMatch.Red3, nextMatch.Blue1, nextMatch.Blue2, nextMatch.Blue3,
-	} {
-		if teamId == 0 {
-			continue
-		}
-		if teams[i], err = arena.Database.GetTeamById(teamId); err != nil {
-			log.Printf("Failed to get model for Team %d while pre-loading next match: %s", teamId, err.Error())
-		}
-	}
-	arena.setupNetwork(teams, true)
-	arena.TeamSigns.SetNextMatchTeams(nextMatch)
+	arena.setupNetwork(arena.OnDeck.teamsInStationOrder(), true)
+	arena.TeamSigns.SetNextMatchTeams(arena.OnDeck.Match)
 }
 
 // Asynchronously reconfigures the networking hardware for the new set of teams.
@@ -802,13 +1221,13 @@ func (arena *Arena) setupNetwork(teams [6]*model.Team, isPreload bool) {
 		}
 	}
 
-	if arena.EventSettings.NetworkSecurityEnabled {
+	if arena.EventSettings.NetworkSecurityEnabled && arena.IsFirstField() {
 		if err := arena.accessPoint.ConfigureTeamWifi(teams); err != nil {
-			log.Printf("Failed to configure team WiFi: %s", err.Error())
+			logging.Network.Error("Failed to configure team WiFi.", "error", err)
 		}
 		go func() {
 			if err := arena.networkSwitch.ConfigureTeamEthernet(teams); err != nil {
-				log.Printf("Failed to configure team Ethernet: %s", err.Error())
+				logging.Network.Error("Failed to configure team Ethernet.", "error", err)
 			}
 		}()
 	}
@@ -825,6 +1244,10 @@ func (arena *Arena) checkCanStartMatch() error {
 		return err
 	}
 
+	if err = arena.checkStaffReady(); err != nil {
+		return err
+	}
+
 	if arena.Plc.IsEnabled() {
 		if !arena.Plc.IsHealthy() {
 			return fmt.Errorf("cannot start match while PLC is not healthy")
@@ -844,6 +1267,9 @@ func (arena *Arena) checkCanStartMatch() error {
 
 func (arena *Arena) checkAllianceStationsReady(stations ...string) error {
 	for _, station := range stations {
+		if !arena.isAllianceStationActive(station) {
+			continue
+		}
 		allianceStation := arena.AllianceStations[station]
 		if allianceStation.EStop {
 			return fmt.Errorf("cannot start match while an emergency stop is active")
@@ -861,18 +1287,28 @@ func (arena *Arena) checkAllianceStationsReady(stations ...string) error {
 	return nil
 }
 
+// Returns true if the given alliance station is used under the configured number of teams per alliance.
+func (arena *Arena) isAllianceStationActive(station string) bool {
+	position, err := strconv.Atoi(station[1:])
+	if err != nil {
+		return false
+	}
+	return position <= arena.EventSettings.TeamsPerAlliance
+}
+
 func (arena *Arena) sendDsPacket(auto bool, enabled bool) {
 	for _, allianceStation := range arena.AllianceStations {
 		dsConn := allianceStation.DsConn
 		if dsConn != nil {
 			dsConn.Auto = auto
-			dsConn.Enabled = enabled && !allianceStation.EStop && !(auto && allianceStation.AStop) &&
-				!allianceStation.Bypass
+			dsConn.Enabled = enabled && !allianceStation.robotEnableBlocked(auto)
 			dsConn.EStop = allianceStation.EStop
 			dsConn.AStop = allianceStation.AStop
 			err := dsConn.update(arena)
 			if err != nil {
-				log.Printf("Unable to send driver station packet for team %d.", allianceStation.Team.Id)
+				logging.Network.Warn(
+					"Unable to send driver station packet.", "team", allianceStation.Team.Id, "error", err,
+				)
 			}
 		}
 	}
@@ -891,6 +1327,18 @@ func (arena *Arena) getAssignedAllianceStation(teamId int) string {
 	return ""
 }
 
+// Records in the given team's status that its robot has successfully connected to the field.
+func (arena *Arena) markTeamConnected(teamId int) {
+	teamStatus, err := arena.Database.GetTeamStatus(teamId)
+	if err != nil || teamStatus.HasConnected {
+		return
+	}
+	teamStatus.HasConnected = true
+	if err = arena.Database.SaveTeamStatus(teamStatus); err != nil {
+		logging.Arena.Error("Failed to save team status.", "team", teamId, "error", err)
+	}
+}
+
 // Updates the score given new input information from the field PLC, and actuates PLC outputs accordingly.
 func (arena *Arena) handlePlcInputOutput() {
 	if !arena.Plc.IsEnabled() {
@@ -968,6 +1416,9 @@ func (arena *Arena) handlePlcInputOutput() {
 	case AutoPeriod, PausePeriod, TeleopPeriod:
 		arena.Plc.SetStackBuzzer(false)
 		arena.Plc.SetStackLights(!redAllianceReady, !blueAllianceReady, false, true)
+	case FieldFault:
+		arena.Plc.SetStackBuzzer(false)
+		arena.Plc.SetStackLights(!redAllianceReady, !blueAllianceReady, true, false)
 	}
 
 	// Get all the game-specific inputs and update the score.
@@ -985,10 +1436,21 @@ func (arena *Arena) handlePlcInputOutput() {
 	arena.RedRealtimeScore.AmplifiedTimeRemainingSec = int(math.Ceil(redAmplifiedTimeRemaining))
 	blueAmplifiedTimeRemaining := blueAmpSpeaker.AmplifiedTimeRemaining(currentTime)
 	arena.BlueRealtimeScore.AmplifiedTimeRemainingSec = int(math.Ceil(blueAmplifiedTimeRemaining))
+	if !oldRedScore.Equals(redScore) {
+		arena.RecordTimelineEvent(
+			model.TimelineScore, "red", fmt.Sprintf("Field sensors updated score to %d", arena.RedScoreSummary().Score),
+		)
+	}
+	if !oldBlueScore.Equals(blueScore) {
+		arena.RecordTimelineEvent(
+			model.TimelineScore, "blue", fmt.Sprintf("Field sensors updated score to %d", arena.BlueScoreSummary().Score),
+		)
+	}
 	if !oldRedScore.Equals(redScore) || !oldBlueScore.Equals(blueScore) ||
 		oldRedAmplifiedTimeRemainingSec != arena.RedRealtimeScore.AmplifiedTimeRemainingSec ||
 		oldBlueAmplifiedTimeRemainingSec != arena.BlueRealtimeScore.AmplifiedTimeRemainingSec {
 		arena.RealtimeScoreNotifier.Notify()
+		arena.LiveScoreNotifier.Notify()
 	}
 
 	// Handle the amp outputs.
@@ -1034,23 +1496,6 @@ func (arena *Arena) handlePlcInputOutput() {
 	arena.Plc.SetPostMatchSubwooferLights(inGracePeriod)
 }
 
-func (arena *Arena) handleTeamStop(station string, eStopState, aStopState bool) {
-	allianceStation := arena.AllianceStations[station]
-	if eStopState {
-		allianceStation.EStop = true
-	} else if arena.MatchTimeSec() == 0 {
-		// Keep the E-stop latched until the match is over.
-		allianceStation.EStop = false
-	}
-	if aStopState {
-		allianceStation.AStop = true
-	} else if arena.MatchState != AutoPeriod {
-		// Keep the A-stop latched until the autonomous period is over.
-		allianceStation.AStop = false
-		allianceStation.aStopReset = true
-	}
-}
-
 func (arena *Arena) handleSounds(matchTimeSec float64) {
 	if arena.MatchState == PreMatch || arena.MatchState == TimeoutActive || arena.MatchState == PostTimeout {
 		// Only apply this logic during a match.
@@ -1064,7 +1509,9 @@ func (arena *Arena) handleSounds(matchTimeSec float64) {
 		}
 		if _, ok := arena.soundsPlayed[sound]; !ok {
 			if matchTimeSec > sound.MatchTimeSec && matchTimeSec-sound.MatchTimeSec < 1 {
-				arena.playSound(sound.Name)
+				arena.playSoundAt(
+					sound.Name, arena.MatchStartTime.Add(time.Duration(sound.MatchTimeSec*float64(time.Second))),
+				)
 				arena.soundsPlayed[sound] = struct{}{}
 			}
 		}
@@ -1072,8 +1519,12 @@ func (arena *Arena) handleSounds(matchTimeSec float64) {
 }
 
 func (arena *Arena) playSound(name string) {
+	arena.playSoundAt(name, time.Now())
+}
+
+func (arena *Arena) playSoundAt(name string, scheduledTime time.Time) {
 	if !arena.MuteMatchSounds {
-		arena.PlaySoundNotifier.NotifyWithMessage(name)
+		arena.PlaySoundNotifier.NotifyWithMessage(PlaySoundMessage{name, scheduledTime.UnixMilli()})
 	}
 }
 
@@ -1085,5 +1536,10 @@ func (arena *Arena) alliancePostMatchScoreReady(alliance string) bool {
 // Performs any actions that need to run at the interval specified by periodicTaskPeriodSec.
 func (arena *Arena) runPeriodicTasks() {
 	arena.updateEarlyLateMessage()
+	arena.updateNextAgendaItem()
 	arena.purgeDisconnectedDisplays()
+	if arena.IsFirstField() {
+		arena.runPeriodicBackup()
+		arena.runPeriodicUpdateCheck()
+	}
 }
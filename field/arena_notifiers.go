@@ -6,29 +6,45 @@
 package field
 
 import (
+	"fmt"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/playoff"
 	"github.com/Team254/cheesy-arena/websocket"
+	"slices"
 	"strconv"
 )
 
 type ArenaNotifiers struct {
+	AgendaNotifier                     *websocket.Notifier
 	AllianceSelectionNotifier          *websocket.Notifier
 	AllianceStationDisplayModeNotifier *websocket.Notifier
 	ArenaStatusNotifier                *websocket.Notifier
 	AudienceDisplayModeNotifier        *websocket.Notifier
+	CaptionsNotifier                   *websocket.Notifier
+	DisplayAlertsNotifier              *websocket.Notifier
 	DisplayConfigurationNotifier       *websocket.Notifier
+	EventSettingsNotifier              *websocket.Notifier
 	EventStatusNotifier                *websocket.Notifier
+	LiveScoreNotifier                  *websocket.Notifier
 	LowerThirdNotifier                 *websocket.Notifier
+	MatchClockNotifier                 *websocket.Notifier
+	MatchCuesNotifier                  *websocket.Notifier
 	MatchLoadNotifier                  *websocket.Notifier
 	MatchTimeNotifier                  *websocket.Notifier
 	MatchTimingNotifier                *websocket.Notifier
+	OnDeckNotifier                     *websocket.Notifier
+	PitDisplayNotifier                 *websocket.Notifier
 	PlaySoundNotifier                  *websocket.Notifier
 	RealtimeScoreNotifier              *websocket.Notifier
 	ReloadDisplaysNotifier             *websocket.Notifier
+	ReplayNotifier                     *websocket.Notifier
 	ScorePostedNotifier                *websocket.Notifier
 	ScoringStatusNotifier              *websocket.Notifier
+	StationStopsNotifier               *websocket.Notifier
+	StreamAudienceDisplayModeNotifier  *websocket.Notifier
+	TeamSignsNotifier                  *websocket.Notifier
+	VideoStingerNotifier               *websocket.Notifier
 }
 
 type MatchTimeMessage struct {
@@ -36,32 +52,84 @@ type MatchTimeMessage struct {
 	MatchTimeSec int
 }
 
+// Represents a sound to be played by the displays, along with the server time at which it was scheduled to play so that
+// clients synchronized to the match clock can compensate for any delivery latency.
+type PlaySoundMessage struct {
+	Name            string
+	ScheduledTimeMs int64
+}
+
 type audienceAllianceScoreFields struct {
 	Score                     *game.Score
 	ScoreSummary              *game.ScoreSummary
 	AmplifiedTimeRemainingSec int
+	CanUndo                   bool
+	CanRedo                   bool
+	MachineScored             game.ScoreElementFlags
+	PanelValues               map[string]int  // Values of the scoring panel elements, keyed by element key.
+	PanelMachineScored        map[string]bool // Which of the scoring panel elements were set by the vision system.
+}
+
+// Represents the complete in-progress score of a match, as published to external consumers through the API.
+type LiveScoreMessage struct {
+	Match *model.Match
+	MatchState
+	MatchTimeSec  int
+	Red           *LiveAllianceScore
+	Blue          *LiveAllianceScore
+	RulesViolated map[int]*game.Rule
+}
+
+// Represents one alliance's portion of the live score, including the raw state of every scoring element.
+type LiveAllianceScore struct {
+	TeamIds                   [3]int
+	Score                     *game.Score
+	ScoreSummary              *game.ScoreSummary
+	Cards                     map[string]string
+	AmplifiedTimeRemainingSec int
 }
 
 // Instantiates notifiers and configures their message producing methods.
 func (arena *Arena) configureNotifiers() {
+	arena.AgendaNotifier = websocket.NewNotifier("agenda", arena.generateAgendaMessage)
 	arena.AllianceSelectionNotifier = websocket.NewNotifier("allianceSelection", arena.generateAllianceSelectionMessage)
 	arena.AllianceStationDisplayModeNotifier = websocket.NewNotifier("allianceStationDisplayMode",
 		arena.generateAllianceStationDisplayModeMessage)
 	arena.ArenaStatusNotifier = websocket.NewNotifier("arenaStatus", arena.generateArenaStatusMessage)
 	arena.AudienceDisplayModeNotifier = websocket.NewNotifier("audienceDisplayMode",
 		arena.generateAudienceDisplayModeMessage)
+	arena.CaptionsNotifier = websocket.NewNotifier("captions", arena.generateCaptionsMessage)
+	arena.DisplayAlertsNotifier = websocket.NewNotifier("displayAlerts", arena.generateDisplayAlertsMessage)
 	arena.DisplayConfigurationNotifier = websocket.NewNotifier("displayConfiguration",
 		arena.generateDisplayConfigurationMessage)
+	arena.EventSettingsNotifier = websocket.NewNotifier("eventSettings", arena.generateEventSettingsMessage)
 	arena.EventStatusNotifier = websocket.NewNotifier("eventStatus", arena.generateEventStatusMessage)
+	arena.LiveScoreNotifier = websocket.NewNotifier("liveScore", arena.GenerateLiveScoreMessage)
 	arena.LowerThirdNotifier = websocket.NewNotifier("lowerThird", arena.generateLowerThirdMessage)
+	arena.MatchClockNotifier = websocket.NewNotifier("matchClock", arena.GenerateMatchClockMessage)
+	arena.MatchCuesNotifier = websocket.NewNotifier("matchCues", arena.generateMatchCuesMessage)
 	arena.MatchLoadNotifier = websocket.NewNotifier("matchLoad", arena.GenerateMatchLoadMessage)
 	arena.MatchTimeNotifier = websocket.NewNotifier("matchTime", arena.generateMatchTimeMessage)
 	arena.MatchTimingNotifier = websocket.NewNotifier("matchTiming", arena.generateMatchTimingMessage)
+	arena.OnDeckNotifier = websocket.NewNotifier("onDeck", arena.generateOnDeckMessage)
+	arena.PitDisplayNotifier = websocket.NewNotifier("pitDisplay", arena.generatePitDisplayMessage)
 	arena.PlaySoundNotifier = websocket.NewNotifier("playSound", nil)
 	arena.RealtimeScoreNotifier = websocket.NewNotifier("realtimeScore", arena.generateRealtimeScoreMessage)
 	arena.ReloadDisplaysNotifier = websocket.NewNotifier("reload", nil)
+	arena.ReplayNotifier = websocket.NewNotifier("replay", arena.generateReplayMessage)
 	arena.ScorePostedNotifier = websocket.NewNotifier("scorePosted", arena.GenerateScorePostedMessage)
 	arena.ScoringStatusNotifier = websocket.NewNotifier("scoringStatus", arena.generateScoringStatusMessage)
+	arena.StationStopsNotifier = websocket.NewNotifier("stationStops", arena.generateStationStopsMessage)
+	arena.StreamAudienceDisplayModeNotifier = websocket.NewNotifier("streamAudienceDisplayMode",
+		arena.generateStreamAudienceDisplayModeMessage)
+	arena.TeamSignsNotifier = websocket.NewNotifier("teamSigns", arena.generateTeamSignsMessage)
+	arena.VideoStingerNotifier = websocket.NewNotifier("videoStinger", arena.generateVideoStingerMessage)
+}
+
+func (arena *Arena) generateAgendaMessage() any {
+	return &struct {
+		NextItem *model.AgendaItem
+	}{arena.NextAgendaItem}
 }
 
 func (arena *Arena) generateAllianceSelectionMessage() any {
@@ -87,12 +155,16 @@ func (arena *Arena) generateArenaStatusMessage() any {
 		MatchId          int
 		AllianceStations map[string]*AllianceStation
 		MatchState
-		CanStartMatch         bool
-		AccessPointStatus     string
-		SwitchStatus          string
-		PlcIsHealthy          bool
-		FieldEStop            bool
-		PlcArmorBlockStatuses map[string]bool
+		CanStartMatch          bool
+		AccessPointStatus      string
+		SwitchStatus           string
+		PlcIsHealthy           bool
+		FieldEStop             bool
+		PlcArmorBlockStatuses  map[string]bool
+		RobotSimulationEnabled bool
+		InterruptedMatchName   string
+		StaffReadiness         StaffReadiness
+		ShowFlowPosition       int
 	}{
 		arena.CurrentMatch.Id,
 		arena.AllianceStations,
@@ -103,6 +175,10 @@ func (arena *Arena) generateArenaStatusMessage() any {
 		arena.Plc.IsHealthy(),
 		arena.Plc.GetFieldEStop(),
 		arena.Plc.GetArmorBlockStatuses(),
+		arena.RobotSimulationEnabled,
+		arena.interruptedMatchName,
+		arena.StaffReadiness,
+		arena.ShowFlowPosition,
 	}
 }
 
@@ -121,29 +197,73 @@ func (arena *Arena) generateDisplayConfigurationMessage() any {
 	return displaysCopy
 }
 
+func (arena *Arena) generateEventSettingsMessage() any {
+	// The settings mutex is already held by whoever triggers this notification, so it isn't taken here.
+	return &struct {
+		ChangesPending bool
+	}{arena.pendingSettings != nil}
+}
+
 func (arena *Arena) generateEventStatusMessage() any {
 	return arena.EventStatus
 }
 
+// Returns a snapshot of the full in-progress score of the current match.
+func (arena *Arena) GenerateLiveScoreMessage() any {
+	return &LiveScoreMessage{
+		Match:        arena.CurrentMatch,
+		MatchState:   arena.MatchState,
+		MatchTimeSec: int(arena.MatchTimeSec()),
+		Red: &LiveAllianceScore{
+			TeamIds:                   [3]int{arena.CurrentMatch.Red1, arena.CurrentMatch.Red2, arena.CurrentMatch.Red3},
+			Score:                     &arena.RedRealtimeScore.CurrentScore,
+			ScoreSummary:              arena.RedScoreSummary(),
+			Cards:                     arena.RedRealtimeScore.Cards,
+			AmplifiedTimeRemainingSec: arena.RedRealtimeScore.AmplifiedTimeRemainingSec,
+		},
+		Blue: &LiveAllianceScore{
+			TeamIds:                   [3]int{arena.CurrentMatch.Blue1, arena.CurrentMatch.Blue2, arena.CurrentMatch.Blue3},
+			Score:                     &arena.BlueRealtimeScore.CurrentScore,
+			ScoreSummary:              arena.BlueScoreSummary(),
+			Cards:                     arena.BlueRealtimeScore.Cards,
+			AmplifiedTimeRemainingSec: arena.BlueRealtimeScore.AmplifiedTimeRemainingSec,
+		},
+		RulesViolated: getRulesViolated(
+			arena.RedRealtimeScore.CurrentScore.Fouls, arena.BlueRealtimeScore.CurrentScore.Fouls,
+		),
+	}
+}
+
 func (arena *Arena) generateLowerThirdMessage() any {
 	return &struct {
-		LowerThird     *model.LowerThird
-		ShowLowerThird bool
-	}{arena.LowerThird, arena.ShowLowerThird}
+		LowerThird       *model.LowerThird
+		ShowLowerThird   bool
+		PlaylistId       int
+		PlaylistPosition int
+	}{arena.LowerThird, arena.ShowLowerThird, arena.LowerThirdPlaylistId, arena.LowerThirdPlaylistPosition}
 }
 
 func (arena *Arena) GenerateMatchLoadMessage() any {
 	teams := make(map[string]*model.Team)
+	yellowCards := make(map[string]bool)
 	for station, allianceStation := range arena.AllianceStations {
 		teams[station] = allianceStation.Team
+		if allianceStation.Team != nil {
+			if teamStatus, _ := arena.Database.GetTeamStatus(allianceStation.Team.Id); teamStatus != nil {
+				yellowCards[station] = teamStatus.YellowCard
+			}
+		}
 	}
 
 	rankings := make(map[string]int)
+	records := make(map[string]string)
 	for _, allianceStation := range arena.AllianceStations {
 		if allianceStation.Team != nil {
 			ranking, _ := arena.Database.GetRankingForTeam(allianceStation.Team.Id)
 			if ranking != nil {
 				rankings[strconv.Itoa(allianceStation.Team.Id)] = ranking.Rank
+				records[strconv.Itoa(allianceStation.Team.Id)] =
+					fmt.Sprintf("%d-%d-%d", ranking.Wins, ranking.Losses, ranking.Ties)
 			}
 		}
 	}
@@ -154,7 +274,10 @@ func (arena *Arena) GenerateMatchLoadMessage() any {
 	var matchup *playoff.Matchup
 	redOffFieldTeams := []*model.Team{}
 	blueOffFieldTeams := []*model.Team{}
+	var redTimeoutsRemaining, blueTimeoutsRemaining int
 	if arena.CurrentMatch.Type == model.Playoff {
+		redTimeoutsRemaining = arena.allianceTimeoutsRemaining(arena.CurrentMatch.PlayoffRedAlliance)
+		blueTimeoutsRemaining = arena.allianceTimeoutsRemaining(arena.CurrentMatch.PlayoffBlueAlliance)
 		matchGroup := arena.PlayoffTournament.MatchGroups()[arena.CurrentMatch.PlayoffMatchGroupId]
 		matchup, _ = matchGroup.(*playoff.Matchup)
 		redOffFieldTeamIds, blueOffFieldTeamIds, _ := arena.Database.GetOffFieldTeamIds(arena.CurrentMatch)
@@ -169,28 +292,42 @@ func (arena *Arena) GenerateMatchLoadMessage() any {
 	}
 
 	return &struct {
-		Match             *model.Match
-		AllowSubstitution bool
-		IsReplay          bool
-		Teams             map[string]*model.Team
-		Rankings          map[string]int
-		Matchup           *playoff.Matchup
-		RedOffFieldTeams  []*model.Team
-		BlueOffFieldTeams []*model.Team
-		BreakDescription  string
+		Match                 *model.Match
+		AllowSubstitution     bool
+		IsReplay              bool
+		Teams                 map[string]*model.Team
+		YellowCards           map[string]bool
+		Rankings              map[string]int
+		Records               map[string]string
+		Matchup               *playoff.Matchup
+		RedOffFieldTeams      []*model.Team
+		BlueOffFieldTeams     []*model.Team
+		BreakDescription      string
+		TeamsPerAlliance      int
+		RedTimeoutsRemaining  int
+		BlueTimeoutsRemaining int
 	}{
 		arena.CurrentMatch,
 		arena.CurrentMatch.ShouldAllowSubstitution(),
 		isReplay,
 		teams,
+		yellowCards,
 		rankings,
+		records,
 		matchup,
 		redOffFieldTeams,
 		blueOffFieldTeams,
 		arena.breakDescription,
+		arena.EventSettings.TeamsPerAlliance,
+		redTimeoutsRemaining,
+		blueTimeoutsRemaining,
 	}
 }
 
+func (arena *Arena) generateMatchCuesMessage() any {
+	return arena.matchCues
+}
+
 func (arena *Arena) generateMatchTimeMessage() any {
 	return MatchTimeMessage{arena.MatchState, int(arena.MatchTimeSec())}
 }
@@ -199,18 +336,45 @@ func (arena *Arena) generateMatchTimingMessage() any {
 	return &game.MatchTiming
 }
 
+func (arena *Arena) generateOnDeckMessage() any {
+	return arena.OnDeck
+}
+
+func (arena *Arena) generatePitDisplayMessage() any {
+	// Only include the pages that are enabled, in their standard order, so that the display can simply cycle through.
+	var pages []string
+	for _, page := range model.PitDisplayPages {
+		if slices.Contains(arena.EventSettings.PitDisplayPages, page) {
+			pages = append(pages, page)
+		}
+	}
+	return &struct {
+		Pages           []string
+		PageDurationSec int
+		Announcement    string
+	}{pages, arena.EventSettings.PitDisplayPageDurationSec, arena.EventSettings.PitDisplayAnnouncement}
+}
+
 func (arena *Arena) generateRealtimeScoreMessage() any {
 	fields := struct {
-		Red       *audienceAllianceScoreFields
-		Blue      *audienceAllianceScoreFields
-		RedCards  map[string]string
-		BlueCards map[string]string
+		Red               *audienceAllianceScoreFields
+		Blue              *audienceAllianceScoreFields
+		RedCards          map[string]string
+		BlueCards         map[string]string
+		RedCardRules      map[string]int
+		BlueCardRules     map[string]int
+		RedFoulConflicts  []FoulConflict
+		BlueFoulConflicts []FoulConflict
 		MatchState
 	}{
 		getAudienceAllianceScoreFields(arena.RedRealtimeScore, arena.RedScoreSummary()),
 		getAudienceAllianceScoreFields(arena.BlueRealtimeScore, arena.BlueScoreSummary()),
 		arena.RedRealtimeScore.Cards,
 		arena.BlueRealtimeScore.Cards,
+		arena.RedRealtimeScore.CardRules,
+		arena.BlueRealtimeScore.CardRules,
+		arena.RedRealtimeScore.FoulConflicts,
+		arena.BlueRealtimeScore.FoulConflicts,
 		arena.MatchState,
 	}
 	return &fields
@@ -219,16 +383,19 @@ func (arena *Arena) generateRealtimeScoreMessage() any {
 func (arena *Arena) GenerateScorePostedMessage() any {
 	redScoreSummary := arena.SavedMatchResult.RedScoreSummary()
 	blueScoreSummary := arena.SavedMatchResult.BlueScoreSummary()
+	rankingPointConfig := game.CurrentRankingPointConfig()
 	redRankingPoints := redScoreSummary.BonusRankingPoints
 	blueRankingPoints := blueScoreSummary.BonusRankingPoints
 	switch arena.SavedMatch.Status {
 	case game.RedWonMatch:
-		redRankingPoints += 2
+		redRankingPoints += rankingPointConfig.WinRankingPoints
+		blueRankingPoints += rankingPointConfig.LossRankingPoints
 	case game.BlueWonMatch:
-		blueRankingPoints += 2
+		redRankingPoints += rankingPointConfig.LossRankingPoints
+		blueRankingPoints += rankingPointConfig.WinRankingPoints
 	case game.TieMatch:
-		redRankingPoints++
-		blueRankingPoints++
+		redRankingPoints += rankingPointConfig.TieRankingPoints
+		blueRankingPoints += rankingPointConfig.TieRankingPoints
 	}
 
 	// For playoff matches, summarize the state of the series.
@@ -316,10 +483,19 @@ func (arena *Arena) generateScoringStatusMessage() any {
 		NumRedScoringPanelsReady  int
 		NumBlueScoringPanels      int
 		NumBlueScoringPanelsReady int
+		ScoreReviewPending        bool
+		ScoreReview               *ScoreReview
+		CardsConfirmed            bool
+		CardConfirmationPending   bool
 	}{arena.RedRealtimeScore.FoulsCommitted && arena.BlueRealtimeScore.FoulsCommitted,
 		arena.alliancePostMatchScoreReady("red"), arena.alliancePostMatchScoreReady("blue"),
 		arena.ScoringPanelRegistry.GetNumPanels("red"), arena.ScoringPanelRegistry.GetNumScoreCommitted("red"),
-		arena.ScoringPanelRegistry.GetNumPanels("blue"), arena.ScoringPanelRegistry.GetNumScoreCommitted("blue")}
+		arena.ScoringPanelRegistry.GetNumPanels("blue"), arena.ScoringPanelRegistry.GetNumScoreCommitted("blue"),
+		arena.ScoreReviewPending(), arena.ScoreReview, arena.CardsConfirmed, arena.CardConfirmationPending()}
+}
+
+func (arena *Arena) generateStreamAudienceDisplayModeMessage() any {
+	return arena.StreamAudienceDisplayMode
 }
 
 // Constructs the data object for one alliance sent to the audience display for the realtime scoring overlay.
@@ -329,6 +505,11 @@ func getAudienceAllianceScoreFields(allianceScore *RealtimeScore,
 	fields.Score = &allianceScore.CurrentScore
 	fields.ScoreSummary = allianceScoreSummary
 	fields.AmplifiedTimeRemainingSec = allianceScore.AmplifiedTimeRemainingSec
+	fields.CanUndo = allianceScore.ScoringHistory.CanUndo()
+	fields.CanRedo = allianceScore.ScoringHistory.CanRedo()
+	fields.MachineScored = allianceScore.MachineScored
+	fields.PanelValues = game.GetScoringPanelValues(&allianceScore.CurrentScore)
+	fields.PanelMachineScored = game.GetScoringPanelFlags(&allianceScore.MachineScored)
 	return fields
 }
 
@@ -0,0 +1,199 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for periodically saving the state of the match in progress to the database, and for picking an interrupted
+// match back up after the server has been restarted.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// How often the state of a match in progress is saved to the database.
+const arenaSnapshotPeriodSec = 1
+
+// Loads any snapshot left behind by a previous run of the server that stopped while a match was in progress.
+func (arena *Arena) loadInterruptedMatch() error {
+	arenaSnapshot, err := arena.Database.GetArenaSnapshot()
+	if err != nil || arenaSnapshot == nil {
+		return err
+	}
+	match, err := arena.Database.GetMatchById(arenaSnapshot.MatchId)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		// The match has since been deleted, so there is nothing to go back to.
+		return arena.Database.DeleteArenaSnapshot()
+	}
+	arena.InterruptedMatch = arenaSnapshot
+	arena.interruptedMatchName = match.ShortName
+	return nil
+}
+
+// Returns true if the arena is in a state that should be saved so that it can be recovered after a restart.
+func (arena *Arena) shouldSaveSnapshot() bool {
+	if arena.CurrentMatch.Type == model.Test || !arena.IsFirstField() {
+		// There is room in the database for the snapshot of only one field.
+		return false
+	}
+	switch arena.MatchState {
+	case WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod, FieldFault, PostMatch:
+		return true
+	}
+	return false
+}
+
+// Saves the state of the match in progress if it has changed state or it has been long enough since the last save, and
+// clears the saved state once the arena is back to the pre-match state.
+func (arena *Arena) updateArenaSnapshot() {
+	if !arena.shouldSaveSnapshot() {
+		if arena.snapshotSaved {
+			if err := arena.Database.DeleteArenaSnapshot(); err != nil {
+				logging.Database.Error("Failed to delete arena snapshot.", "error", err)
+				return
+			}
+			arena.snapshotSaved = false
+		}
+		return
+	}
+
+	if arena.MatchState == arena.lastMatchState &&
+		time.Since(arena.lastSnapshotTime).Seconds() < arenaSnapshotPeriodSec {
+		return
+	}
+	if err := arena.saveArenaSnapshot(); err != nil {
+		logging.Database.Error("Failed to save arena snapshot.", "error", err)
+	}
+}
+
+// Writes the current state of the match to the database, replacing any previous snapshot.
+func (arena *Arena) saveArenaSnapshot() error {
+	arenaSnapshot := model.ArenaSnapshot{
+		MatchId:           arena.CurrentMatch.Id,
+		MatchState:        int(arena.MatchState),
+		FaultedMatchState: int(arena.faultedMatchState),
+		MatchTimeSec:      arena.MatchTimeSec(),
+		RedScore:          &arena.RedRealtimeScore.CurrentScore,
+		BlueScore:         &arena.BlueRealtimeScore.CurrentScore,
+		RedCards:          arena.RedRealtimeScore.Cards,
+		BlueCards:         arena.BlueRealtimeScore.Cards,
+		SavedAt:           time.Now(),
+	}
+	if err := arena.Database.SaveArenaSnapshot(&arenaSnapshot); err != nil {
+		return err
+	}
+	arena.lastSnapshotTime = arenaSnapshot.SavedAt
+	arena.snapshotSaved = true
+	if arena.InterruptedMatch != nil {
+		// The interrupted match's snapshot has been overwritten by that of a new match, so it can no longer be resumed.
+		arena.InterruptedMatch = nil
+		arena.interruptedMatchName = ""
+		arena.ArenaStatusNotifier.Notify()
+	}
+	return nil
+}
+
+// Reloads the match that was in progress when the server last stopped, along with its scores. A match that was
+// under way is put into a field fault at the point where it was interrupted, so that the robots stay disabled until the
+// scorekeeper resumes it; a match that had already ended goes back to awaiting the committing of its results.
+func (arena *Arena) ResumeInterruptedMatch() error {
+	if arena.InterruptedMatch == nil {
+		return fmt.Errorf("there is no interrupted match to resume")
+	}
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot resume an interrupted match while there is a match in progress or with results pending")
+	}
+	arenaSnapshot := arena.InterruptedMatch
+	match, err := arena.Database.GetMatchById(arenaSnapshot.MatchId)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		return fmt.Errorf("interrupted match %d no longer exists", arenaSnapshot.MatchId)
+	}
+	if err = arena.LoadMatch(match); err != nil {
+		return err
+	}
+
+	if arenaSnapshot.RedScore != nil {
+		arena.RedRealtimeScore.CurrentScore = *arenaSnapshot.RedScore
+	}
+	if arenaSnapshot.BlueScore != nil {
+		arena.BlueRealtimeScore.CurrentScore = *arenaSnapshot.BlueScore
+	}
+	if arenaSnapshot.RedCards != nil {
+		arena.RedRealtimeScore.Cards = arenaSnapshot.RedCards
+	}
+	if arenaSnapshot.BlueCards != nil {
+		arena.BlueRealtimeScore.Cards = arenaSnapshot.BlueCards
+	}
+
+	now := time.Now()
+	snapshotMatchState := MatchState(arenaSnapshot.MatchState)
+	if snapshotMatchState == PostMatch {
+		// Backdate the start of the match so that the end-of-match grace period for scoring is already over.
+		arena.MatchStartTime = now.Add(
+			-game.GetDurationToTeleopEnd() - (game.SpeakerTeleopGracePeriodSec+1)*time.Second,
+		)
+		arena.MatchState = PostMatch
+	} else {
+		arena.faultedMatchState = snapshotMatchState
+		if snapshotMatchState == FieldFault {
+			arena.faultedMatchState = MatchState(arenaSnapshot.FaultedMatchState)
+		}
+		arena.MatchStartTime = now.Add(-time.Duration(arenaSnapshot.MatchTimeSec * float64(time.Second)))
+		arena.fieldFaultStartTime = now
+		arena.MatchState = FieldFault
+	}
+	arena.setAudienceDisplayModes("match")
+	arena.AllianceStationDisplayMode = "match"
+	arena.AllianceStationDisplayModeNotifier.Notify()
+
+	arena.RecordTimelineEvent(
+		model.TimelineFieldFault,
+		"",
+		fmt.Sprintf(
+			"Match recovered after server restart at %.1f seconds, as of %s",
+			arenaSnapshot.MatchTimeSec,
+			arenaSnapshot.SavedAt.Format("15:04:05"),
+		),
+	)
+
+	// The snapshot will be overwritten on the next loop iteration now that the match is back in progress.
+	arena.InterruptedMatch = nil
+	arena.interruptedMatchName = ""
+	arena.RealtimeScoreNotifier.Notify()
+	arena.ScoringStatusNotifier.Notify()
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Throws away the match that was in progress when the server last stopped, leaving it to be replayed from scratch.
+func (arena *Arena) DiscardInterruptedMatch() error {
+	if arena.InterruptedMatch == nil {
+		return fmt.Errorf("there is no interrupted match to discard")
+	}
+	if err := arena.Database.DeleteArenaSnapshot(); err != nil {
+		return err
+	}
+	arena.InterruptedMatch = nil
+	arena.interruptedMatchName = ""
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Saves the state of any match in progress and closes the database, in preparation for the server process exiting.
+func (arena *Arena) Shutdown() error {
+	if arena.shouldSaveSnapshot() {
+		if err := arena.saveArenaSnapshot(); err != nil {
+			logging.Database.Error("Failed to save arena snapshot.", "error", err)
+		}
+	}
+	return arena.Database.Close()
+}
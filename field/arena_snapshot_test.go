@@ -0,0 +1,137 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Starts the given match with all stations bypassed and advances it to the given number of seconds in.
+func startSnapshotTestMatch(t *testing.T, arena *Arena, match *model.Match, matchTimeSec int) {
+	assert.Nil(t, arena.LoadMatch(match))
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(matchTimeSec) * time.Second)
+
+	// The arena only advances by one period per loop iteration.
+	for i := 0; i < 4; i++ {
+		arena.Update()
+	}
+}
+
+func TestArenaSnapshotResume(t *testing.T) {
+	arena := setupTestArena(t)
+	match := model.Match{Type: model.Qualification, ShortName: "Q7"}
+	assert.Nil(t, arena.Database.CreateMatch(&match))
+
+	// Nothing should be saved for a match that hasn't started.
+	arena.Update()
+	arenaSnapshot, _ := arena.Database.GetArenaSnapshot()
+	assert.Nil(t, arenaSnapshot)
+
+	teleopTimeSec := int(game.GetDurationToTeleopStart().Seconds()) + 10
+	startSnapshotTestMatch(t, arena, &match, teleopTimeSec)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	arena.RedRealtimeScore.CurrentScore = *game.TestScore1()
+	arena.BlueRealtimeScore.Cards["254"] = "yellow"
+	arena.lastSnapshotTime = time.Time{}
+	arena.Update()
+	arenaSnapshot, err := arena.Database.GetArenaSnapshot()
+	assert.Nil(t, err)
+	if assert.NotNil(t, arenaSnapshot) {
+		assert.Equal(t, match.Id, arenaSnapshot.MatchId)
+		assert.Equal(t, int(TeleopPeriod), arenaSnapshot.MatchState)
+		assert.InDelta(t, float64(teleopTimeSec), arenaSnapshot.MatchTimeSec, 0.1)
+	}
+
+	// Simulate the server going down and coming back up mid-match.
+	dbPath := filepath.Join(model.BaseDir, "field_test.db")
+	assert.Nil(t, arena.Database.Close())
+	arena, err = NewArena(dbPath)
+	assert.Nil(t, err)
+	if assert.NotNil(t, arena.InterruptedMatch) {
+		assert.Equal(t, match.Id, arena.InterruptedMatch.MatchId)
+	}
+	assert.Equal(t, "Q7", arena.interruptedMatchName)
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// Resuming should bring the match back in a field fault at the point where it was interrupted.
+	assert.Nil(t, arena.ResumeInterruptedMatch())
+	assert.Nil(t, arena.InterruptedMatch)
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	assert.Equal(t, FieldFault, arena.MatchState)
+	assert.InDelta(t, float64(teleopTimeSec), arena.MatchTimeSec(), 0.2)
+	assert.Equal(t, *game.TestScore1(), arena.RedRealtimeScore.CurrentScore)
+	assert.Equal(t, "yellow", arena.BlueRealtimeScore.Cards["254"])
+	assert.NotNil(t, arena.ResumeInterruptedMatch())
+	arena.Update()
+	assert.Nil(t, arena.ResumeMatch())
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+
+	// The snapshot should be cleared once the match is over and the arena is reset.
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd())
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	arenaSnapshot, _ = arena.Database.GetArenaSnapshot()
+	if assert.NotNil(t, arenaSnapshot) {
+		assert.Equal(t, int(PostMatch), arenaSnapshot.MatchState)
+	}
+	assert.Nil(t, arena.ResetMatch())
+	arena.Update()
+	arenaSnapshot, _ = arena.Database.GetArenaSnapshot()
+	assert.Nil(t, arenaSnapshot)
+}
+
+func TestArenaSnapshotDiscard(t *testing.T) {
+	arena := setupTestArena(t)
+	match := model.Match{Type: model.Practice, ShortName: "P3"}
+	assert.Nil(t, arena.Database.CreateMatch(&match))
+	assert.NotNil(t, arena.ResumeInterruptedMatch())
+	assert.NotNil(t, arena.DiscardInterruptedMatch())
+
+	// A graceful shutdown should save the state even if it's been less than a second since the last save.
+	startSnapshotTestMatch(t, arena, &match, game.MatchTiming.WarmupDurationSec+5)
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	arena.RedRealtimeScore.CurrentScore.LeaveStatuses[1] = true
+	dbPath := filepath.Join(model.BaseDir, "field_test.db")
+	assert.Nil(t, arena.Shutdown())
+	arena, err := NewArena(dbPath)
+	assert.Nil(t, err)
+	if assert.NotNil(t, arena.InterruptedMatch) {
+		assert.Equal(t, int(AutoPeriod), arena.InterruptedMatch.MatchState)
+		assert.True(t, arena.InterruptedMatch.RedScore.LeaveStatuses[1])
+	}
+
+	assert.Nil(t, arena.DiscardInterruptedMatch())
+	assert.Nil(t, arena.InterruptedMatch)
+	assert.NotNil(t, arena.ResumeInterruptedMatch())
+	arenaSnapshot, _ := arena.Database.GetArenaSnapshot()
+	assert.Nil(t, arenaSnapshot)
+}
+
+func TestArenaSnapshotTestMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.Update()
+
+	// Test matches aren't worth recovering.
+	arenaSnapshot, _ := arena.Database.GetArenaSnapshot()
+	assert.Nil(t, arenaSnapshot)
+}
@@ -89,6 +89,35 @@ func TestArenaCheckCanStartMatch(t *testing.T) {
 	assert.Nil(t, arena.checkCanStartMatch())
 }
 
+func TestArenaSmallMatchFormat(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.TeamsPerAlliance = 2
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.Database.CreateTeam(&model.Team{Id: 2056})
+	arena.Database.CreateTeam(&model.Team{Id: 1503})
+	arena.Database.CreateTeam(&model.Team{Id: 148})
+
+	// Teams in the unused positions should not be loaded into the third stations.
+	match := model.Match{Type: model.Practice, Red1: 254, Red2: 1114, Red3: 148, Blue1: 2056, Blue2: 1503}
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, 254, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, 1114, arena.AllianceStations["R2"].Team.Id)
+	assert.Nil(t, arena.AllianceStations["R3"].Team)
+	assert.Nil(t, arena.AllianceStations["B3"].Team)
+
+	// Only the active stations need to be ready to start the match.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	err := arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot start match until all robots are connected or bypassed")
+	}
+	arena.AllianceStations["B2"].Bypass = true
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
 func TestArenaMatchFlow(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -613,6 +642,114 @@ func TestArenaTimeout(t *testing.T) {
 	}
 }
 
+func TestArenaDemoMatchTiming(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.AutoDurationSec = 15
+	arena.EventSettings.TeleopDurationSec = 135
+	arena.EventSettings.DemoAutoDurationSec = 5
+	arena.EventSettings.DemoPauseDurationSec = 1
+	arena.EventSettings.DemoTeleopDurationSec = 30
+	arena.EventSettings.DemoWarningRemainingDurationSec = 10
+	arena.EventSettings.MatchSoundCues = []game.MatchSoundCue{{"warning", game.SoundAnchorTeleopEnd, -5}}
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.Equal(t, 135, game.MatchTiming.TeleopDurationSec)
+	if assert.Equal(t, 4, len(game.MatchSounds)) {
+		assert.Equal(t, "warning", game.MatchSounds[0].Name)
+		assert.Equal(t, float64(15+2+135-5), game.MatchSounds[0].MatchTimeSec)
+	}
+
+	// The demo timing shouldn't apply until it is enabled.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, 15, game.MatchTiming.AutoDurationSec)
+	arena.EventSettings.DemoMatchTimingEnabled = true
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, 5, game.MatchTiming.AutoDurationSec)
+	assert.Equal(t, 1, game.MatchTiming.PauseDurationSec)
+	assert.Equal(t, 30, game.MatchTiming.TeleopDurationSec)
+	assert.Equal(t, 10, game.MatchTiming.WarningRemainingDurationSec)
+	assert.Equal(t, float64(5+1+30-5), game.MatchSounds[0].MatchTimeSec)
+
+	// The state machine should follow the demo timing.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+6) * time.Second)
+	arena.Update()
+	arena.Update()
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Nil(t, arena.ResetMatch())
+
+	// Other match types should keep using the regular timing.
+	match := model.Match{Type: model.Practice}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, 15, game.MatchTiming.AutoDurationSec)
+	assert.Equal(t, 135, game.MatchTiming.TeleopDurationSec)
+}
+
+func TestArenaSettingsDeferredDuringMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	originalMatchTiming := game.MatchTiming
+	defer func() { game.MatchTiming = originalMatchTiming }()
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	originalTeleopDurationSec := game.MatchTiming.TeleopDurationSec
+
+	// Settings saved during the match should be held back.
+	settings := arena.SavedEventSettings()
+	settings.Name = "Changed Event"
+	settings.TeleopDurationSec = 100
+	assert.Nil(t, arena.Database.UpdateEventSettings(settings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.True(t, arena.SettingsChangesPending())
+	assert.NotEqual(t, "Changed Event", arena.EventSettings.Name)
+	assert.Equal(t, originalTeleopDurationSec, game.MatchTiming.TeleopDurationSec)
+	assert.Equal(t, "Changed Event", arena.SavedEventSettings().Name)
+	assert.Equal(t, 100, arena.SavedEventSettings().TeleopDurationSec)
+
+	// Further changes should build on the pending ones rather than on the settings in effect.
+	settings = arena.SavedEventSettings()
+	settings.AutoDurationSec = 10
+	assert.Nil(t, arena.Database.UpdateEventSettings(settings))
+	assert.Nil(t, arena.LoadSettings())
+	arena.Update()
+	assert.Equal(t, originalTeleopDurationSec, game.MatchTiming.TeleopDurationSec)
+
+	// The settings should all take effect together once the arena is back in the pre-match state.
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.True(t, arena.SettingsChangesPending())
+	assert.Nil(t, arena.ResetMatch())
+	arena.Update()
+	assert.False(t, arena.SettingsChangesPending())
+	assert.Equal(t, "Changed Event", arena.EventSettings.Name)
+	assert.Equal(t, 10, game.MatchTiming.AutoDurationSec)
+	assert.Equal(t, 100, game.MatchTiming.TeleopDurationSec)
+
+	// Settings saved outside of a match should take effect immediately.
+	settings = arena.SavedEventSettings()
+	settings.TeleopDurationSec = 120
+	assert.Nil(t, arena.Database.UpdateEventSettings(settings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.False(t, arena.SettingsChangesPending())
+	assert.Equal(t, 120, game.MatchTiming.TeleopDurationSec)
+}
+
 func TestSaveTeamHasConnected(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -621,7 +758,8 @@ func TestSaveTeamHasConnected(t *testing.T) {
 	arena.Database.CreateTeam(&model.Team{Id: 103})
 	arena.Database.CreateTeam(&model.Team{Id: 104})
 	arena.Database.CreateTeam(&model.Team{Id: 105})
-	arena.Database.CreateTeam(&model.Team{Id: 106, City: "San Jose", HasConnected: true})
+	arena.Database.CreateTeam(&model.Team{Id: 106})
+	arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 106, HasConnected: true})
 	match := model.Match{Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106}
 	arena.Database.CreateMatch(&match)
 	arena.LoadMatch(&match)
@@ -634,19 +772,12 @@ func TestSaveTeamHasConnected(t *testing.T) {
 	arena.AllianceStations["B1"].Bypass = true
 	arena.AllianceStations["B2"].DsConn = &DriverStationConnection{TeamId: 105, RobotLinked: true}
 	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 106, RobotLinked: true}
-	arena.AllianceStations["B3"].Team.City = "Sand Hosay" // Change some other field to verify that it isn't saved.
 	assert.Nil(t, arena.StartMatch())
 
 	// Check that the connection status was saved for the teams that just linked for the first time.
-	teams, _ := arena.Database.GetAllTeams()
-	if assert.Equal(t, 6, len(teams)) {
-		assert.False(t, teams[0].HasConnected)
-		assert.True(t, teams[1].HasConnected)
-		assert.False(t, teams[2].HasConnected)
-		assert.False(t, teams[3].HasConnected)
-		assert.True(t, teams[4].HasConnected)
-		assert.True(t, teams[5].HasConnected)
-		assert.Equal(t, "San Jose", teams[5].City)
+	for teamId, hasConnected := range map[int]bool{101: false, 102: true, 103: false, 104: false, 105: true, 106: true} {
+		teamStatus, _ := arena.Database.GetTeamStatus(teamId)
+		assert.Equal(t, hasConnected, teamStatus.HasConnected, teamId)
 	}
 }
 
@@ -1201,3 +1332,34 @@ func TestPlcMatchCycleGameSpecific(t *testing.T) {
 	assert.Equal(t, false, plc.speakerMotors)
 	assert.Equal(t, false, plc.postMatchSubwooferLights)
 }
+
+func TestSetAudienceDisplayOutputMode(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.SetAudienceDisplayMode("logo")
+	assert.Equal(t, "logo", arena.AudienceDisplayMode)
+	assert.Equal(t, "logo", arena.StreamAudienceDisplayMode)
+
+	assert.Nil(t, arena.SetAudienceDisplayOutputMode(StreamAudienceDisplay, "sponsor"))
+	assert.Equal(t, "logo", arena.AudienceDisplayMode)
+	assert.Equal(t, "sponsor", arena.StreamAudienceDisplayMode)
+	assert.Nil(t, arena.SetAudienceDisplayOutputMode(StageAudienceDisplay, "bracket"))
+	assert.Equal(t, "bracket", arena.AudienceDisplayMode)
+	assert.Equal(t, "sponsor", arena.StreamAudienceDisplayMode)
+	err := arena.SetAudienceDisplayOutputMode("lobby", "logo")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid audience display output 'lobby'.", err.Error())
+	}
+
+	// Starting a match brings both outputs back in sync.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Equal(t, "match", arena.AudienceDisplayMode)
+	assert.Equal(t, "match", arena.StreamAudienceDisplayMode)
+}
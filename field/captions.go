@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for keeping track of the text that the announcer pushes to the accessibility caption display.
+
+package field
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Number of the most recent captions that are kept and shown on the caption display.
+	MaxCaptions      = 3
+	maxCaptionLength = 500
+)
+
+type Caption struct {
+	Text string
+	Time time.Time
+}
+
+// Adds the given text to the captions shown on the caption display, dropping the oldest one if there are already too
+// many.
+func (arena *Arena) AddCaption(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("caption text cannot be blank")
+	}
+	if len(text) > maxCaptionLength {
+		return fmt.Errorf("caption text cannot be longer than %d characters", maxCaptionLength)
+	}
+
+	arena.captionsMutex.Lock()
+	arena.captions = append(arena.captions, Caption{Text: text, Time: time.Now()})
+	if len(arena.captions) > MaxCaptions {
+		arena.captions = arena.captions[len(arena.captions)-MaxCaptions:]
+	}
+	arena.captionsMutex.Unlock()
+
+	arena.CaptionsNotifier.Notify()
+	return nil
+}
+
+// Removes all captions from the caption display.
+func (arena *Arena) ClearCaptions() {
+	arena.captionsMutex.Lock()
+	arena.captions = nil
+	arena.captionsMutex.Unlock()
+
+	arena.CaptionsNotifier.Notify()
+}
+
+// Returns the captions currently shown on the caption display, from oldest to newest.
+func (arena *Arena) GetCaptions() []Caption {
+	arena.captionsMutex.Lock()
+	defer arena.captionsMutex.Unlock()
+	captions := make([]Caption, len(arena.captions))
+	copy(captions, arena.captions)
+	return captions
+}
+
+func (arena *Arena) generateCaptionsMessage() any {
+	return &struct {
+		Captions []Caption
+	}{arena.GetCaptions()}
+}
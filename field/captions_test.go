@@ -0,0 +1,45 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCaptions(t *testing.T) {
+	arena := setupTestArena(t)
+
+	getCaptionTexts := func() []string {
+		var texts []string
+		for _, caption := range arena.GetCaptions() {
+			texts = append(texts, caption.Text)
+		}
+		return texts
+	}
+	assert.Empty(t, getCaptionTexts())
+
+	assert.Nil(t, arena.AddCaption(" Welcome to the event! "))
+	assert.Equal(t, []string{"Welcome to the event!"}, getCaptionTexts())
+
+	// Only the most recent captions should be kept.
+	assert.Nil(t, arena.AddCaption("Two"))
+	assert.Nil(t, arena.AddCaption("Three"))
+	assert.Nil(t, arena.AddCaption("Four"))
+	assert.Equal(t, []string{"Two", "Three", "Four"}, getCaptionTexts())
+
+	err := arena.AddCaption("  ")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "caption text cannot be blank", err.Error())
+	}
+	err = arena.AddCaption(strings.Repeat("a", 501))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "caption text cannot be longer than 500 characters", err.Error())
+	}
+	assert.Equal(t, []string{"Two", "Three", "Four"}, getCaptionTexts())
+
+	arena.ClearCaptions()
+	assert.Empty(t, getCaptionTexts())
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for the head referee's assignment and confirmation of cards once a match has ended.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"strconv"
+)
+
+// Gives the given card to the given team for violating the given rule as part of the post-match card assignment, or
+// clears the team's card if the card is blank. Any change to the cards requires them to be confirmed again.
+func (arena *Arena) AssignCard(alliance string, teamId int, card string, ruleId int) error {
+	if arena.MatchState != PostMatch {
+		return fmt.Errorf("cannot assign cards until the match has ended")
+	}
+	if card != "" && card != "yellow" && card != "red" {
+		return fmt.Errorf("invalid card '%s'", card)
+	}
+	if !arena.teamIsOnAlliance(alliance, teamId) {
+		return fmt.Errorf("team %d is not on the %s alliance", teamId, alliance)
+	}
+	if card != "" && game.GetRuleById(ruleId) == nil {
+		return fmt.Errorf("a valid rule must be given for the %s card", card)
+	}
+
+	arena.foulMutex.Lock()
+	defer arena.foulMutex.Unlock()
+
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	if card == "" {
+		for _, id := range arena.getCardTeamIds(alliance, teamId) {
+			realtimeScore.Cards[strconv.Itoa(id)] = ""
+			delete(realtimeScore.CardRules, strconv.Itoa(id))
+			arena.recordCardTimelineEvent(alliance, id, "", 0)
+		}
+	} else {
+		// Start from a clean slate so that the head referee's decision replaces rather than adds to any card given
+		// during the match, subject only to escalation of a yellow card carried from a previous match.
+		for _, id := range arena.getCardTeamIds(alliance, teamId) {
+			delete(realtimeScore.Cards, strconv.Itoa(id))
+		}
+		arena.setCard(alliance, teamId, card, ruleId)
+	}
+	arena.CardsConfirmed = false
+	arena.ScoringStatusNotifier.Notify()
+	return nil
+}
+
+// Records the head referee's confirmation that the cards for the current match are final.
+func (arena *Arena) ConfirmCards() error {
+	if arena.MatchState != PostMatch {
+		return fmt.Errorf("cannot confirm cards until the match has ended")
+	}
+	arena.CardsConfirmed = true
+	arena.RecordTimelineEvent(model.TimelineCard, string(HeadRefereeRole), "Cards confirmed")
+	arena.ScoringStatusNotifier.Notify()
+	return nil
+}
+
+// Returns true if the current match has ended but the head referee has yet to confirm its cards, in which case its
+// results may not be committed. Test matches don't require confirmation.
+func (arena *Arena) CardConfirmationPending() bool {
+	return arena.MatchState == PostMatch && arena.CurrentMatch.Type != model.Test && !arena.CardsConfirmed
+}
+
+// Returns true if the given team is one of the given alliance's teams in the current match.
+func (arena *Arena) teamIsOnAlliance(alliance string, teamId int) bool {
+	if teamId == 0 {
+		return false
+	}
+	match := arena.CurrentMatch
+	if alliance == "red" {
+		return teamId == match.Red1 || teamId == match.Red2 || teamId == match.Red3
+	}
+	if alliance == "blue" {
+		return teamId == match.Blue1 || teamId == match.Blue2 || teamId == match.Blue3
+	}
+	return false
+}
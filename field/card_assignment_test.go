@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAssignCard(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 1114, YellowCard: true})
+	arena.CurrentMatch = &model.Match{Type: model.Qualification, Red1: 254, Red2: 1114, Red3: 2056, Blue1: 148}
+
+	err := arena.AssignCard("red", 254, "yellow", 19)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot assign cards until the match has ended", err.Error())
+	}
+
+	arena.MatchState = PostMatch
+	err = arena.AssignCard("red", 254, "green", 19)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid card 'green'", err.Error())
+	}
+	err = arena.AssignCard("red", 148, "yellow", 19)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "team 148 is not on the red alliance", err.Error())
+	}
+	err = arena.AssignCard("blue", 148, "red", 0)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "a valid rule must be given for the red card", err.Error())
+	}
+	assert.Empty(t, arena.RedRealtimeScore.Cards)
+	assert.Empty(t, arena.BlueRealtimeScore.Cards)
+
+	// Check that the assigned card replaces any card given during the match, other than for a carried yellow card.
+	arena.SetCard("red", 254, "red")
+	assert.Nil(t, arena.AssignCard("red", 254, "yellow", 19))
+	assert.Nil(t, arena.AssignCard("red", 1114, "yellow", 22))
+	assert.Equal(t, map[string]string{"254": "yellow", "1114": "red"}, arena.RedRealtimeScore.Cards)
+	assert.Equal(t, map[string]int{"254": 19, "1114": 22}, arena.RedRealtimeScore.CardRules)
+	events := arena.GetMatchTimeline()
+	assert.Equal(t, "Red card given to team 1114 for G418", events[len(events)-1].Description)
+
+	// Check that clearing a card also clears its rule.
+	assert.Nil(t, arena.AssignCard("red", 1114, "", 22))
+	assert.Equal(t, map[string]string{"254": "yellow", "1114": ""}, arena.RedRealtimeScore.Cards)
+	assert.Equal(t, map[string]int{"254": 19}, arena.RedRealtimeScore.CardRules)
+
+	// Check that cards apply to the whole alliance in playoffs.
+	arena.CurrentMatch.Type = model.Playoff
+	arena.CurrentMatch.Blue2, arena.CurrentMatch.Blue3 = 1, 2
+	assert.Nil(t, arena.AssignCard("blue", 1, "red", 23))
+	assert.Equal(t, map[string]string{"148": "red", "1": "red", "2": "red"}, arena.BlueRealtimeScore.Cards)
+	assert.Equal(t, map[string]int{"148": 23, "1": 23, "2": 23}, arena.BlueRealtimeScore.CardRules)
+}
+
+func TestConfirmCards(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.CurrentMatch = &model.Match{Type: model.Qualification, Red1: 254}
+
+	err := arena.ConfirmCards()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot confirm cards until the match has ended", err.Error())
+	}
+	assert.False(t, arena.CardConfirmationPending())
+
+	arena.MatchState = PostMatch
+	assert.True(t, arena.CardConfirmationPending())
+	assert.Nil(t, arena.ConfirmCards())
+	assert.True(t, arena.CardsConfirmed)
+	assert.False(t, arena.CardConfirmationPending())
+
+	// Check that changing a card requires the cards to be confirmed again.
+	assert.Nil(t, arena.AssignCard("red", 254, "yellow", 19))
+	assert.True(t, arena.CardConfirmationPending())
+	assert.Nil(t, arena.ConfirmCards())
+
+	// Check that loading a match resets the confirmation and that test matches don't require it.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: model.Test}))
+	assert.False(t, arena.CardsConfirmed)
+	arena.MatchState = PostMatch
+	assert.False(t, arena.CardConfirmationPending())
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for automatically backing up the event database on a schedule and after each match.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Backs up the event database for the given reason and then deletes the oldest automatic backups beyond the configured
+// retention limit.
+func (arena *Arena) AutoBackupDatabase(reason string) error {
+	if err := arena.Database.Backup(arena.EventSettings.Name, reason); err != nil {
+		return err
+	}
+	return model.PruneAutomaticBackups(arena.EventSettings.AutoBackupRetentionCount)
+}
+
+// Backs up the event database if the configured interval has elapsed since the last periodic backup.
+func (arena *Arena) runPeriodicBackup() {
+	intervalMin := arena.EventSettings.AutoBackupIntervalMin
+	if intervalMin <= 0 || time.Since(arena.lastAutoBackupTime) < time.Duration(intervalMin)*time.Minute {
+		return
+	}
+	arena.lastAutoBackupTime = time.Now()
+	if err := arena.AutoBackupDatabase(model.PeriodicBackupReason); err != nil {
+		logging.Database.Error("Failed to back up the event database.", "error", err)
+	}
+}
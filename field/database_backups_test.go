@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPeriodicBackup(t *testing.T) {
+	arena := setupTestArena(t)
+	model.BaseDir = t.TempDir()
+	defer func() { model.BaseDir = ".." }()
+	arena.EventSettings.AutoBackupIntervalMin = 10
+	arena.EventSettings.AutoBackupRetentionCount = 2
+
+	// No backup should be taken until the interval has elapsed.
+	arena.runPeriodicBackup()
+	backups, _ := model.ListBackups()
+	assert.Empty(t, backups)
+
+	arena.lastAutoBackupTime = time.Now().Add(-11 * time.Minute)
+	arena.runPeriodicBackup()
+	backups, _ = model.ListBackups()
+	if assert.Equal(t, 1, len(backups)) {
+		assert.Equal(t, model.PeriodicBackupReason, backups[0].Reason)
+	}
+	arena.runPeriodicBackup()
+	backups, _ = model.ListBackups()
+	assert.Equal(t, 1, len(backups))
+
+	// Backups beyond the retention limit should be pruned.
+	time.Sleep(time.Second)
+	assert.Nil(t, arena.AutoBackupDatabase("post_Qualification_match_Q1"))
+	time.Sleep(time.Second)
+	assert.Nil(t, arena.AutoBackupDatabase("post_Qualification_match_Q2"))
+	backups, _ = model.ListBackups()
+	if assert.Equal(t, 2, len(backups)) {
+		assert.Equal(t, "post_Qualification_match_Q2", backups[0].Reason)
+		assert.Equal(t, "post_Qualification_match_Q1", backups[1].Reason)
+	}
+
+	// Periodic backups can be turned off.
+	arena.EventSettings.AutoBackupIntervalMin = 0
+	arena.lastAutoBackupTime = time.Time{}
+	arena.runPeriodicBackup()
+	backups, _ = model.ListBackups()
+	assert.Equal(t, "post_Qualification_match_Q2", backups[0].Reason)
+}
@@ -31,10 +31,16 @@ const (
 	AnnouncerDisplay
 	AudienceDisplay
 	BracketDisplay
+	CaptionDisplay
+	CueDisplay
+	EmceeDisplay
 	FieldMonitorDisplay
+	LeagueDisplay
 	LogoDisplay
+	PitDisplay
 	QueueingDisplay
 	RankingsDisplay
+	TeamSignDisplay
 	TwitchStreamDisplay
 	WallDisplay
 	WebpageDisplay
@@ -46,10 +52,16 @@ var DisplayTypeNames = map[DisplayType]string{
 	AnnouncerDisplay:       "Announcer",
 	AudienceDisplay:        "Audience",
 	BracketDisplay:         "Bracket",
+	CaptionDisplay:         "Captions",
+	CueDisplay:             "Cues",
+	EmceeDisplay:           "Emcee",
 	FieldMonitorDisplay:    "Field Monitor",
+	LeagueDisplay:          "League",
 	LogoDisplay:            "Logo",
+	PitDisplay:             "Pit",
 	QueueingDisplay:        "Queueing",
 	RankingsDisplay:        "Rankings",
+	TeamSignDisplay:        "Team Sign",
 	TwitchStreamDisplay:    "Twitch Stream",
 	WallDisplay:            "Wall",
 	WebpageDisplay:         "Web Page",
@@ -61,10 +73,16 @@ var displayTypePaths = map[DisplayType]string{
 	AnnouncerDisplay:       "/displays/announcer",
 	AudienceDisplay:        "/displays/audience",
 	BracketDisplay:         "/displays/bracket",
+	CaptionDisplay:         "/displays/captions",
+	CueDisplay:             "/displays/cues",
+	EmceeDisplay:           "/displays/emcee",
 	FieldMonitorDisplay:    "/displays/field_monitor",
+	LeagueDisplay:          "/displays/league",
 	LogoDisplay:            "/displays/logo",
+	PitDisplay:             "/displays/pit",
 	QueueingDisplay:        "/displays/queueing",
 	RankingsDisplay:        "/displays/rankings",
+	TeamSignDisplay:        "/displays/team_sign",
 	TwitchStreamDisplay:    "/displays/twitch",
 	WallDisplay:            "/displays/wall",
 	WebpageDisplay:         "/displays/webpage",
@@ -78,6 +96,7 @@ type Display struct {
 	ConnectionCount      int
 	Notifier             *websocket.Notifier
 	lastConnectedTime    time.Time
+	pathPrefix           string // Prefix of the URLs of the pages for the field that the display belongs to.
 }
 
 type DisplayConfiguration struct {
@@ -125,6 +144,7 @@ func DisplayFromUrl(path string, query map[string][]string) (*DisplayConfigurati
 // Returns the URL string for the given display that includes all of its configuration parameters.
 func (display *Display) ToUrl() string {
 	var builder strings.Builder
+	builder.WriteString(display.pathPrefix)
 	builder.WriteString(displayTypePaths[display.DisplayConfiguration.Type])
 	builder.WriteString("?displayId=")
 	builder.WriteString(url.QueryEscape(display.DisplayConfiguration.Id))
@@ -181,7 +201,7 @@ func (arena *Arena) RegisterDisplay(displayConfig *DisplayConfiguration, ipAddre
 		arena.Displays[displayConfig.Id].IpAddress = ipAddress
 	} else {
 		if !ok {
-			display = new(Display)
+			display = &Display{pathPrefix: arena.PathPrefix()}
 			display.Notifier = websocket.NewNotifier("displayConfiguration",
 				display.generateDisplayConfigurationMessage)
 			arena.Displays[displayConfig.Id] = display
@@ -193,6 +213,7 @@ func (arena *Arena) RegisterDisplay(displayConfig *DisplayConfiguration, ipAddre
 		display.Notifier.Notify()
 	}
 	arena.DisplayConfigurationNotifier.Notify()
+	arena.updateDisplayAlerts()
 
 	return display
 }
@@ -210,6 +231,7 @@ func (arena *Arena) UpdateDisplay(displayConfig DisplayConfiguration) error {
 		display.DisplayConfiguration = displayConfig
 		display.Notifier.Notify()
 		arena.DisplayConfigurationNotifier.Notify()
+		arena.updateDisplayAlerts()
 	}
 	return nil
 }
@@ -227,6 +249,9 @@ func (arena *Arena) MarkDisplayDisconnected(displayId string) {
 			delete(arena.Displays, existingDisplay.DisplayConfiguration.Id)
 		} else {
 			existingDisplay.ConnectionCount -= 1
+			if existingDisplay.ConnectionCount == 0 {
+				arena.scheduleDisplayAlertCheck(existingDisplay)
+			}
 		}
 		existingDisplay.lastConnectedTime = time.Now()
 		arena.DisplayConfigurationNotifier.Notify()
@@ -248,5 +273,6 @@ func (arena *Arena) purgeDisconnectedDisplays() {
 	}
 	if deleted {
 		arena.DisplayConfigurationNotifier.Notify()
+		arena.updateDisplayAlerts()
 	}
 }
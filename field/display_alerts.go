@@ -0,0 +1,80 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for alerting the field staff when a display that the event can't run without loses its connection.
+
+package field
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Display types whose loss is disruptive enough to the event that the field staff should be alerted to it.
+var criticalDisplayTypes = map[DisplayType]bool{
+	AllianceStationDisplay: true,
+	AudienceDisplay:        true,
+}
+
+// Represents a critical display that has been disconnected for longer than the configured threshold.
+type DisplayAlert struct {
+	DisplayId        string
+	Nickname         string
+	Type             string
+	IpAddress        string // The address from which the display was last connected.
+	DisconnectedTime time.Time
+}
+
+// Schedules a check for whether the given display is still disconnected once the alert threshold has passed.
+func (arena *Arena) scheduleDisplayAlertCheck(display *Display) {
+	if !criticalDisplayTypes[display.DisplayConfiguration.Type] {
+		return
+	}
+	time.AfterFunc(arena.displayAlertThreshold(), func() {
+		displayRegistryMutex.Lock()
+		defer displayRegistryMutex.Unlock()
+		arena.updateDisplayAlerts()
+	})
+}
+
+// Recalculates which critical displays have been disconnected for longer than the alert threshold and sends out a
+// notification if that has changed. Must be called from a method that has a lock on the display mutex.
+func (arena *Arena) updateDisplayAlerts() {
+	threshold := arena.displayAlertThreshold()
+	var displayAlerts []DisplayAlert
+	for _, display := range arena.Displays {
+		if criticalDisplayTypes[display.DisplayConfiguration.Type] && display.ConnectionCount == 0 &&
+			time.Since(display.lastConnectedTime) >= threshold {
+			displayAlerts = append(
+				displayAlerts,
+				DisplayAlert{
+					DisplayId:        display.DisplayConfiguration.Id,
+					Nickname:         display.DisplayConfiguration.Nickname,
+					Type:             DisplayTypeNames[display.DisplayConfiguration.Type],
+					IpAddress:        display.IpAddress,
+					DisconnectedTime: display.lastConnectedTime,
+				},
+			)
+		}
+	}
+	sort.Slice(displayAlerts, func(i, j int) bool {
+		return displayAlerts[i].DisplayId < displayAlerts[j].DisplayId
+	})
+
+	if !reflect.DeepEqual(displayAlerts, arena.displayAlerts) {
+		arena.displayAlerts = displayAlerts
+		arena.DisplayAlertsNotifier.Notify()
+	}
+}
+
+func (arena *Arena) displayAlertThreshold() time.Duration {
+	return time.Duration(arena.EventSettings.DisplayAlertThresholdSec) * time.Second
+}
+
+func (arena *Arena) generateDisplayAlertsMessage() any {
+	// Notify() for this notifier must always called from a method that has a lock on the display mutex.
+	return &struct {
+		DisplayAlerts []DisplayAlert
+	}{arena.displayAlerts}
+}
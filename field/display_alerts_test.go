@@ -0,0 +1,50 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDisplayAlerts(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.DisplayAlertThresholdSec = 1
+
+	audienceConfig := &DisplayConfiguration{Id: "254", Nickname: "Main Screen", Type: AudienceDisplay}
+	arena.RegisterDisplay(audienceConfig, "10.0.100.21")
+	rankingsConfig := &DisplayConfiguration{Id: "1114", Type: RankingsDisplay}
+	arena.RegisterDisplay(rankingsConfig, "10.0.100.22")
+	assert.Empty(t, arena.displayAlerts)
+
+	// Non-critical displays shouldn't raise an alert.
+	arena.MarkDisplayDisconnected(rankingsConfig.Id)
+	arena.MarkDisplayDisconnected(audienceConfig.Id)
+	assert.Empty(t, arena.displayAlerts)
+
+	// The alert should be raised once the critical display has been gone for longer than the threshold.
+	time.Sleep(1100 * time.Millisecond)
+	displayRegistryMutex.Lock()
+	displayAlerts := arena.displayAlerts
+	displayRegistryMutex.Unlock()
+	if assert.Equal(t, 1, len(displayAlerts)) {
+		assert.Equal(t, "254", displayAlerts[0].DisplayId)
+		assert.Equal(t, "Main Screen", displayAlerts[0].Nickname)
+		assert.Equal(t, "Audience", displayAlerts[0].Type)
+		assert.Equal(t, "10.0.100.21", displayAlerts[0].IpAddress)
+	}
+
+	// The alert should be cleared when the display reconnects.
+	arena.RegisterDisplay(audienceConfig, "10.0.100.23")
+	assert.Empty(t, arena.displayAlerts)
+
+	// The alert should also be raised for a display that is changed to a critical type while disconnected.
+	arena.Displays[rankingsConfig.Id].lastConnectedTime = time.Now().Add(-2 * time.Second)
+	assert.Nil(t, arena.UpdateDisplay(DisplayConfiguration{Id: "1114", Type: AllianceStationDisplay}))
+	if assert.Equal(t, 1, len(arena.displayAlerts)) {
+		assert.Equal(t, "1114", arena.displayAlerts[0].DisplayId)
+		assert.Equal(t, "Alliance Station", arena.displayAlerts[0].Type)
+	}
+}
@@ -7,13 +7,14 @@ package field
 
 import (
 	"fmt"
-	"log"
 	"net"
+	"os"
 	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/network"
 )
@@ -51,6 +52,7 @@ type DriverStationConnection struct {
 	tcpConn                   net.Conn
 	udpConn                   net.Conn
 	log                       *TeamMatchLog
+	simulated                 bool // Whether this is a scripted stand-in for a driver station that isn't really there.
 
 	// WrongStation indicates if the team in the station is the incorrect team
 	// by being non-empty. If the team is in the correct station, or no team is
@@ -66,7 +68,7 @@ func newDriverStationConnection(teamId int, allianceStation string, tcpConn net.
 	if err != nil {
 		return nil, err
 	}
-	log.Printf("Driver station for Team %d connected from %s\n", teamId, ipAddress)
+	logging.Network.Info("Driver station connected.", "team", teamId, "address", ipAddress)
 
 	udpConn, err := net.Dial("udp4", fmt.Sprintf("%s:%d", ipAddress, driverStationUdpSendPort))
 	if err != nil {
@@ -80,9 +82,10 @@ func (arena *Arena) listenForDsUdpPackets() {
 	udpAddress, _ := net.ResolveUDPAddr("udp4", fmt.Sprintf(":%d", driverStationUdpReceivePort))
 	listener, err := net.ListenUDP("udp4", udpAddress)
 	if err != nil {
-		log.Fatalf("Error opening driver station UDP socket: %v", err)
+		logging.Network.Error("Error opening driver station UDP socket.", "error", err)
+		os.Exit(1)
 	}
-	log.Printf("Listening for driver stations on UDP port %d\n", driverStationUdpReceivePort)
+	logging.Network.Info("Listening for driver stations.", "protocol", "udp", "port", driverStationUdpReceivePort)
 
 	var data [50]byte
 	for {
@@ -91,11 +94,8 @@ func (arena *Arena) listenForDsUdpPackets() {
 		teamId := int(data[4])<<8 + int(data[5])
 
 		var dsConn *DriverStationConnection
-		for _, allianceStation := range arena.AllianceStations {
-			if allianceStation.Team != nil && allianceStation.Team.Id == teamId {
-				dsConn = allianceStation.DsConn
-				break
-			}
+		if fieldArena, station := arena.findAssignedAllianceStation(teamId); fieldArena != nil {
+			dsConn = fieldArena.AllianceStations[station].DsConn
 		}
 
 		if dsConn != nil {
@@ -150,6 +150,10 @@ func (dsConn *DriverStationConnection) close() {
 func (dsConn *DriverStationConnection) signalMatchStart(match *model.Match, wifiStatus *network.TeamWifiStatus) error {
 	// Zero out missed packet count and begin logging.
 	dsConn.missedPacketOffset = dsConn.MissedPacketCount
+	if dsConn.simulated {
+		// There is nothing worth logging for a driver station that doesn't exist.
+		return nil
+	}
 	var err error
 	dsConn.log, err = NewTeamMatchLog(dsConn.TeamId, match, wifiStatus)
 	return err
@@ -268,17 +272,19 @@ func (dsConn *DriverStationConnection) decodeStatusPacket(data [36]byte) {
 func (arena *Arena) listenForDriverStations() {
 	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", network.ServerIpAddress, driverStationTcpListenPort))
 	if err != nil {
-		log.Printf("Error opening driver station TCP socket: %v", err.Error())
-		log.Printf("Change IP address to %s and restart Cheesy Arena to fix.", network.ServerIpAddress)
+		logging.Network.Error(
+			"Error opening driver station TCP socket; change the IP address and restart Cheesy Arena to fix.",
+			"address", network.ServerIpAddress, "error", err,
+		)
 		return
 	}
 	defer l.Close()
 
-	log.Printf("Listening for driver stations on TCP port %d\n", driverStationTcpListenPort)
+	logging.Network.Info("Listening for driver stations.", "protocol", "tcp", "port", driverStationTcpListenPort)
 	for {
 		tcpConn, err := l.Accept()
 		if err != nil {
-			log.Println("Error accepting driver station connection: ", err.Error())
+			logging.Network.Error("Error accepting driver station connection.", "error", err)
 			continue
 		}
 
@@ -286,20 +292,20 @@ func (arena *Arena) listenForDriverStations() {
 		var packet [5]byte
 		_, err = tcpConn.Read(packet[:])
 		if err != nil {
-			log.Println("Error reading initial packet: ", err.Error())
+			logging.Network.Error("Error reading initial packet.", "error", err)
 			continue
 		}
 		if !(packet[0] == 0 && packet[1] == 3 && packet[2] == 24) {
-			log.Printf("Invalid initial packet received: %v", packet)
+			logging.Network.Warn("Invalid initial packet received.", "packet", packet)
 			tcpConn.Close()
 			continue
 		}
 		teamId := int(packet[3])<<8 + int(packet[4])
 
-		// Check to see if the team is supposed to be on the field, and notify the DS accordingly.
-		assignedStation := arena.getAssignedAllianceStation(teamId)
+		// Check to see if the team is supposed to be on any of the fields, and notify the DS accordingly.
+		fieldArena, assignedStation := arena.findAssignedAllianceStation(teamId)
 		if assignedStation == "" {
-			log.Printf("Rejecting connection from Team %d, who is not in the current match, soon.", teamId)
+			logging.Network.Warn("Rejecting connection from team that is not in the current match.", "team", teamId)
 			go func() {
 				// Wait a second and then close it so it doesn't chew up bandwidth constantly trying to reconnect.
 				time.Sleep(time.Second)
@@ -318,10 +324,10 @@ func (arena *Arena) listenForDriverStations() {
 		stationTeamId := teamDigit1*100 + teamDigit2
 		wrongAssignedStation := ""
 		if stationTeamId != teamId {
-			wrongAssignedStation = arena.getAssignedAllianceStation(stationTeamId)
+			wrongAssignedStation = fieldArena.getAssignedAllianceStation(stationTeamId)
 			if wrongAssignedStation != "" {
 				// The team is supposed to be in this match, but is plugged into the wrong station.
-				log.Printf("Team %d is in incorrect station %s.", teamId, wrongAssignedStation)
+				logging.Network.Warn("Team is in incorrect station.", "team", teamId, "station", wrongAssignedStation)
 				stationStatus = 1
 			}
 		}
@@ -330,30 +336,33 @@ func (arena *Arena) listenForDriverStations() {
 		assignmentPacket[0] = 0  // Packet size
 		assignmentPacket[1] = 3  // Packet size
 		assignmentPacket[2] = 25 // Packet type
-		log.Printf("Accepting connection from Team %d in station %s.", teamId, assignedStation)
+		logging.Network.Info(
+			"Accepting driver station connection.",
+			"team", teamId, "field", fieldArena.FieldId, "station", assignedStation,
+		)
 		assignmentPacket[3] = allianceStationPositionMap[assignedStation]
 		assignmentPacket[4] = stationStatus
 		_, err = tcpConn.Write(assignmentPacket[:])
 		if err != nil {
-			log.Printf("Error sending driver station assignment packet: %v", err)
+			logging.Network.Error("Error sending driver station assignment packet.", "team", teamId, "error", err)
 			tcpConn.Close()
 			continue
 		}
 
 		dsConn, err := newDriverStationConnection(teamId, assignedStation, tcpConn)
 		if err != nil {
-			log.Printf("Error registering driver station connection: %v", err)
+			logging.Network.Error("Error registering driver station connection.", "team", teamId, "error", err)
 			tcpConn.Close()
 			continue
 		}
-		arena.AllianceStations[assignedStation].DsConn = dsConn
+		fieldArena.AllianceStations[assignedStation].DsConn = dsConn
 
 		if wrongAssignedStation != "" {
 			dsConn.WrongStation = wrongAssignedStation
 		}
 
 		// Spin up a goroutine to handle further TCP communication with this driver station.
-		go dsConn.handleTcpConnection(arena)
+		go dsConn.handleTcpConnection(fieldArena)
 	}
 }
 
@@ -363,7 +372,7 @@ func (dsConn *DriverStationConnection) handleTcpConnection(arena *Arena) {
 		dsConn.tcpConn.SetReadDeadline(time.Now().Add(time.Second * driverStationTcpLinkTimeoutSec))
 		_, err := dsConn.tcpConn.Read(buffer)
 		if err != nil {
-			log.Printf("Error reading from connection for Team %d: %v", dsConn.TeamId, err)
+			logging.Network.Warn("Error reading from driver station connection.", "team", dsConn.TeamId, "error", err)
 			dsConn.close()
 			arena.AllianceStations[dsConn.AllianceStation].DsConn = nil
 			break
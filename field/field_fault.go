@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for pausing a match in progress due to a field fault and later resuming it from where it left off, as an
+// alternative to aborting and replaying the whole match.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Pauses the match clock and disables all robots until the match is either resumed or aborted.
+func (arena *Arena) DeclareFieldFault(reason string) error {
+	if arena.MatchState != WarmupPeriod && arena.MatchState != AutoPeriod && arena.MatchState != PausePeriod &&
+		arena.MatchState != TeleopPeriod {
+		return fmt.Errorf("cannot declare a field fault when a match is not in progress")
+	}
+
+	arena.faultedMatchState = arena.MatchState
+	arena.fieldFaultStartTime = time.Now()
+	arena.MatchState = FieldFault
+	description := fmt.Sprintf("Field fault declared during %s", matchStateNames[arena.faultedMatchState])
+	if reason != "" {
+		description += ": " + reason
+	}
+	arena.RecordTimelineEvent(model.TimelineFieldFault, "", description)
+	return nil
+}
+
+// Resumes the match from the point at which the field fault was declared, shifting the match start time forward so
+// that the time spent in the fault doesn't count against any of the periods.
+func (arena *Arena) ResumeMatch() error {
+	if arena.MatchState != FieldFault {
+		return fmt.Errorf("cannot resume match when there is no field fault")
+	}
+
+	faultDuration := time.Since(arena.fieldFaultStartTime)
+	arena.MatchStartTime = arena.MatchStartTime.Add(faultDuration)
+	arena.MatchState = arena.faultedMatchState
+	arena.fieldFaultStartTime = time.Time{}
+
+	// Force a packet on the next loop iteration so that the robots are re-enabled without delay.
+	arena.lastDsPacketTime = time.Time{}
+
+	arena.RecordTimelineEvent(
+		model.TimelineFieldFault,
+		"",
+		fmt.Sprintf("Match resumed after %.1f-second field fault", faultDuration.Seconds()),
+	)
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFieldFault(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "B3"))
+	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 254, RobotLinked: true}
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+
+	assert.NotNil(t, arena.DeclareFieldFault("Broken field element"))
+	assert.NotNil(t, arena.ResumeMatch())
+
+	// Declare a fault ten seconds into the match.
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+10) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Enabled)
+	assert.Nil(t, arena.DeclareFieldFault("Broken field element"))
+	arena.Update()
+	assert.Equal(t, FieldFault, arena.MatchState)
+	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Auto)
+	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Enabled)
+	faultMatchTimeSec := arena.MatchTimeSec()
+	assert.InDelta(t, float64(game.MatchTiming.WarmupDurationSec+10), faultMatchTimeSec, 0.1)
+	assert.NotNil(t, arena.DeclareFieldFault(""))
+
+	// The clock should stand still and the match should not advance while the fault lasts.
+	arena.fieldFaultStartTime = arena.fieldFaultStartTime.Add(-time.Minute)
+	arena.MatchStartTime = arena.MatchStartTime.Add(-time.Minute)
+	arena.Update()
+	assert.Equal(t, FieldFault, arena.MatchState)
+	assert.Equal(t, faultMatchTimeSec, arena.MatchTimeSec())
+
+	// Resuming should pick up from where the match left off.
+	assert.Nil(t, arena.ResumeMatch())
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Enabled)
+	assert.InDelta(t, faultMatchTimeSec, arena.MatchTimeSec(), 0.1)
+
+	timeline := arena.GetMatchTimeline()
+	var faultEvents []model.MatchTimelineEvent
+	for _, event := range timeline {
+		if event.Type == model.TimelineFieldFault {
+			faultEvents = append(faultEvents, event)
+		}
+	}
+	if assert.Equal(t, 2, len(faultEvents)) {
+		assert.Equal(t, "Field fault declared during Autonomous: Broken field element", faultEvents[0].Description)
+		assert.Contains(t, faultEvents[1].Description, "Match resumed after 60.")
+		assert.InDelta(t, faultMatchTimeSec*1000, faultEvents[1].MatchTimeMs, 100)
+	}
+
+	// A match can also be aborted from a field fault.
+	assert.Nil(t, arena.DeclareFieldFault(""))
+	arena.Update()
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Enabled)
+}
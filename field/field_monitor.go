@@ -0,0 +1,76 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for aggregating the driver station and network diagnostics of each alliance station into a single view for
+// the FTA.
+
+package field
+
+// Represents the connection diagnostics of a single alliance station, combining what the team's driver station
+// reports with what the field access point sees of the team's radio.
+type FieldMonitorStation struct {
+	Station                   string
+	TeamId                    int
+	Bypass                    bool
+	Ethernet                  bool
+	EStop                     bool
+	AStop                     bool
+	DsLinked                  bool
+	RadioLinked               bool
+	RioLinked                 bool
+	RobotLinked               bool
+	WrongStation              string
+	BatteryVoltage            float64
+	DsRobotTripTimeMs         int
+	MissedPacketCount         int
+	SecondsSinceLastRobotLink float64
+	WifiTeamId                int // Team whose SSID the access point has configured for this station.
+	WifiRadioLinked           bool
+	BandwidthMbps             float64
+	RxRateMbps                float64
+	TxRateMbps                float64
+	SignalNoiseRatio          int
+	WifiRadioMacAddress       string // Robot radio that the access point sees connected to the station's SSID.
+	ExpectedRadioMacAddress   string // Robot radio registered for the station's team at check-in.
+	RadioPairingIssue         string
+}
+
+// Returns the diagnostics for each alliance station, in order from R1 to B3.
+func (arena *Arena) GenerateFieldMonitorStations() []FieldMonitorStation {
+	stations := make([]FieldMonitorStation, 0, 6)
+	for _, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		allianceStation := arena.AllianceStations[station]
+		fieldMonitorStation := FieldMonitorStation{
+			Station:                 station,
+			Bypass:                  allianceStation.Bypass,
+			Ethernet:                allianceStation.Ethernet,
+			EStop:                   allianceStation.EStop,
+			AStop:                   allianceStation.AStop,
+			WifiTeamId:              allianceStation.WifiStatus.TeamId,
+			WifiRadioLinked:         allianceStation.WifiStatus.RadioLinked,
+			BandwidthMbps:           allianceStation.WifiStatus.MBits,
+			RxRateMbps:              allianceStation.WifiStatus.RxRate,
+			TxRateMbps:              allianceStation.WifiStatus.TxRate,
+			SignalNoiseRatio:        allianceStation.WifiStatus.SignalNoiseRatio,
+			WifiRadioMacAddress:     allianceStation.WifiStatus.RadioMacAddress,
+			ExpectedRadioMacAddress: allianceStation.ExpectedRadioMacAddress,
+			RadioPairingIssue:       allianceStation.RadioPairingIssue,
+		}
+		if allianceStation.Team != nil {
+			fieldMonitorStation.TeamId = allianceStation.Team.Id
+		}
+		if dsConn := allianceStation.DsConn; dsConn != nil {
+			fieldMonitorStation.DsLinked = dsConn.DsLinked
+			fieldMonitorStation.RadioLinked = dsConn.RadioLinked
+			fieldMonitorStation.RioLinked = dsConn.RioLinked
+			fieldMonitorStation.RobotLinked = dsConn.RobotLinked
+			fieldMonitorStation.WrongStation = dsConn.WrongStation
+			fieldMonitorStation.BatteryVoltage = dsConn.BatteryVoltage
+			fieldMonitorStation.DsRobotTripTimeMs = dsConn.DsRobotTripTimeMs
+			fieldMonitorStation.MissedPacketCount = dsConn.MissedPacketCount
+			fieldMonitorStation.SecondsSinceLastRobotLink = dsConn.SecondsSinceLastRobotLink
+		}
+		stations = append(stations, fieldMonitorStation)
+	}
+	return stations
+}
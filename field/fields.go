@@ -0,0 +1,91 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for running more than one field from the same server, with each field having its own arena that shares the
+// event database, settings and playoff bracket with the others.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// The arenas of all the fields run by the server, in order of field number.
+type fieldGroup struct {
+	arenas []*Arena
+}
+
+// Creates the arena for an additional field, numbered after the existing ones, which shares this arena's event
+// database and settings. Only the first field drives the field hardware; the driver station connections are accepted
+// by the first field and handed to whichever field the team is playing on.
+func (arena *Arena) AddField() (*Arena, error) {
+	firstField := arena.fieldGroup.arenas[0]
+	fieldArena := newArena()
+	fieldArena.FieldId = len(arena.fieldGroup.arenas) + 1
+	fieldArena.fieldGroup = arena.fieldGroup
+	fieldArena.Database = firstField.Database
+	fieldArena.PushNotifier = firstField.PushNotifier
+	fieldArena.TbaPublisher = firstField.TbaPublisher
+	fieldArena.Updater = firstField.Updater
+	fieldArena.restartRequests = firstField.restartRequests
+	if err := fieldArena.loadSettings(); err != nil {
+		return nil, err
+	}
+	fieldArena.initializeMatchState()
+	arena.fieldGroup.arenas = append(arena.fieldGroup.arenas, fieldArena)
+	return fieldArena, nil
+}
+
+// Returns the arenas of all the fields run by the server, in order of field number.
+func (arena *Arena) Fields() []*Arena {
+	return arena.fieldGroup.arenas
+}
+
+// Returns the arena of the field with the given number, or nil if there is no such field.
+func (arena *Arena) Field(fieldId int) *Arena {
+	if fieldId < 1 || fieldId > len(arena.fieldGroup.arenas) {
+		return nil
+	}
+	return arena.fieldGroup.arenas[fieldId-1]
+}
+
+// Returns true if the arena runs the first field, which is the one that drives the field hardware and owns the
+// event-wide tasks such as backups.
+func (arena *Arena) IsFirstField() bool {
+	return arena.FieldId == 1
+}
+
+// Returns the prefix of the URLs of the pages that are specific to the arena's field, which is empty for the first
+// field so that a single-field event keeps its usual URLs.
+func (arena *Arena) PathPrefix() string {
+	if arena.IsFirstField() {
+		return ""
+	}
+	return fmt.Sprintf("/fields/%d", arena.FieldId)
+}
+
+// Returns an error if the given match is currently loaded on a field other than this one, since a match can't be
+// played on two fields at once.
+func (arena *Arena) checkMatchNotOnOtherField(match *model.Match) error {
+	if match.Type == model.Test {
+		return nil
+	}
+	for _, fieldArena := range arena.Fields() {
+		if fieldArena != arena && fieldArena.CurrentMatch != nil && fieldArena.CurrentMatch.Id == match.Id {
+			return fmt.Errorf("match %s is already loaded on field %d", match.ShortName, fieldArena.FieldId)
+		}
+	}
+	return nil
+}
+
+// Returns the arena of the field whose current match includes the given team along with the team's alliance station
+// on that field, or nil and the empty string if the team isn't in the current match on any field.
+func (arena *Arena) findAssignedAllianceStation(teamId int) (*Arena, string) {
+	for _, fieldArena := range arena.Fields() {
+		if station := fieldArena.getAssignedAllianceStation(teamId); station != "" {
+			return fieldArena, station
+		}
+	}
+	return nil, ""
+}
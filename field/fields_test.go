@@ -0,0 +1,99 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAddField(t *testing.T) {
+	arena := setupTestArena(t)
+	settings, _ := arena.Database.GetEventSettings()
+	settings.PlcAddress = "10.0.100.40"
+	arena.Database.UpdateEventSettings(settings)
+	assert.Nil(t, arena.LoadSettings())
+
+	field2, err := arena.AddField()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, field2.FieldId)
+	assert.Equal(t, []*Arena{arena, field2}, arena.Fields())
+	assert.Equal(t, []*Arena{arena, field2}, field2.Fields())
+	assert.Same(t, field2, arena.Field(2))
+	assert.Nil(t, arena.Field(3))
+	assert.True(t, arena.IsFirstField())
+	assert.False(t, field2.IsFirstField())
+	assert.Equal(t, "", arena.PathPrefix())
+	assert.Equal(t, "/fields/2", field2.PathPrefix())
+
+	// Check that the fields share the event data but that only the first one drives the field hardware.
+	assert.Same(t, arena.Database, field2.Database)
+	assert.Same(t, arena.PlayoffTournament, field2.PlayoffTournament)
+	assert.Equal(t, "10.0.100.40", field2.EventSettings.PlcAddress)
+	assert.True(t, arena.Plc.IsEnabled())
+	assert.False(t, field2.Plc.IsEnabled())
+
+	// Check that reloading the settings on either field reloads them on both.
+	settings.Name = "Two Field Off-Season"
+	arena.Database.UpdateEventSettings(settings)
+	assert.Nil(t, field2.LoadSettings())
+	assert.Equal(t, "Two Field Off-Season", arena.EventSettings.Name)
+	assert.Equal(t, "Two Field Off-Season", field2.EventSettings.Name)
+	assert.Nil(t, arena.CreatePlayoffTournament())
+	assert.Same(t, arena.PlayoffTournament, field2.PlayoffTournament)
+
+	err = arena.ReplaceDatabase(func(dbPath string) error { return nil })
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the event database can't be replaced while running more than one field", err.Error())
+	}
+
+	// Check that a display connected to the second field is sent back to that field's pages.
+	display := field2.RegisterDisplay(&DisplayConfiguration{Id: "254", Type: AudienceDisplay}, "")
+	assert.Equal(t, "/fields/2/displays/audience?displayId=254", display.ToUrl())
+}
+
+func TestFieldsPlayDifferentMatches(t *testing.T) {
+	arena := setupTestArena(t)
+	field2, _ := arena.AddField()
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106, 201} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	q1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 101, Blue1: 104}
+	arena.Database.CreateMatch(&q1)
+	q2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 102, Blue1: 201}
+	arena.Database.CreateMatch(&q2)
+	q3 := model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Red1: 103, Blue1: 106}
+	arena.Database.CreateMatch(&q3)
+
+	assert.Nil(t, arena.LoadMatch(&q1))
+	assert.Equal(t, "Q2", arena.OnDeck.Match.ShortName)
+	err := field2.LoadMatch(&q1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match Q1 is already loaded on field 1", err.Error())
+	}
+	err = field2.SetNextMatchOverride(q1.Id)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match Q1 is already loaded on field 1", err.Error())
+	}
+
+	// Check that loading a match on the second field takes it off the first field's deck.
+	assert.Nil(t, field2.LoadMatch(&q2))
+	assert.Equal(t, "Q3", arena.OnDeck.Match.ShortName)
+	assert.Equal(t, "Q3", field2.OnDeck.Match.ShortName)
+	nextMatch, err := arena.getNextMatch(true)
+	assert.Nil(t, err)
+	assert.Equal(t, "Q3", nextMatch.ShortName)
+
+	// Check that a driver station is routed to whichever field its team is playing on.
+	fieldArena, station := arena.findAssignedAllianceStation(201)
+	assert.Same(t, field2, fieldArena)
+	assert.Equal(t, "B1", station)
+	fieldArena, station = arena.findAssignedAllianceStation(101)
+	assert.Same(t, arena, fieldArena)
+	assert.Equal(t, "R1", station)
+	fieldArena, station = arena.findAssignedAllianceStation(105)
+	assert.Nil(t, fieldArena)
+	assert.Equal(t, "", station)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for filling open practice match slots with teams from the self-service filler line.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Populates any empty team positions in the given practice match with the teams at the front of the filler line,
+// removing them from the line and persisting the updated match. Positions that aren't used under the configured number
+// of teams per alliance are left empty.
+func (arena *Arena) assignFillerTeams(match *model.Match) error {
+	if match.Type != model.Practice {
+		return nil
+	}
+
+	positions := match.ActiveTeamPositions(arena.EventSettings.TeamsPerAlliance)
+	teamsInMatch := make(map[int]struct{})
+	numOpenSlots := 0
+	for _, position := range positions {
+		if *position == 0 {
+			numOpenSlots++
+		} else {
+			teamsInMatch[*position] = struct{}{}
+		}
+	}
+	if numOpenSlots == 0 {
+		return nil
+	}
+
+	fillerTeams, err := arena.Database.GetAllFillerTeams()
+	if err != nil {
+		return err
+	}
+	fillerIndex := 0
+	updated := false
+	for _, position := range positions {
+		if *position != 0 {
+			continue
+		}
+		for fillerIndex < len(fillerTeams) {
+			fillerTeam := fillerTeams[fillerIndex]
+			fillerIndex++
+			if _, ok := teamsInMatch[fillerTeam.TeamId]; ok {
+				// Leave the team in line for a later match since it is already playing in this one.
+				continue
+			}
+			if err = arena.Database.DeleteFillerTeam(fillerTeam.Id); err != nil {
+				return err
+			}
+			*position = fillerTeam.TeamId
+			teamsInMatch[fillerTeam.TeamId] = struct{}{}
+			updated = true
+			logging.Arena.Info(
+				"Assigned filler team to practice match.", "team", fillerTeam.TeamId, "match", match.ShortName,
+			)
+			break
+		}
+	}
+
+	if updated && match.Id > 0 {
+		return arena.Database.UpdateMatch(match)
+	}
+	return nil
+}
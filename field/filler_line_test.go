@@ -0,0 +1,71 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAssignFillerTeams(t *testing.T) {
+	arena := setupTestArena(t)
+
+	for _, teamId := range []int{254, 1114, 2056, 148} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: 1114})
+	arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: 254})
+	arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: 2056})
+
+	// Non-practice matches should be left alone.
+	qualMatch := model.Match{Type: model.Qualification, Red1: 254}
+	arena.Database.CreateMatch(&qualMatch)
+	assert.Nil(t, arena.LoadMatch(&qualMatch))
+	assert.Equal(t, 0, qualMatch.Red2)
+	fillerTeams, _ := arena.Database.GetAllFillerTeams()
+	assert.Equal(t, 3, len(fillerTeams))
+
+	// Open slots should be filled in line order, skipping teams that are already in the match.
+	practiceMatch := model.Match{Type: model.Practice, Red1: 254, Red3: 148}
+	arena.Database.CreateMatch(&practiceMatch)
+	assert.Nil(t, arena.LoadMatch(&practiceMatch))
+	assert.Equal(t, 1114, practiceMatch.Red2)
+	assert.Equal(t, 2056, practiceMatch.Blue1)
+	assert.Equal(t, 0, practiceMatch.Blue2)
+	assert.Equal(t, 1114, arena.AllianceStations["R2"].Team.Id)
+	assert.Equal(t, 2056, arena.AllianceStations["B1"].Team.Id)
+	fillerTeams, _ = arena.Database.GetAllFillerTeams()
+	if assert.Equal(t, 1, len(fillerTeams)) {
+		assert.Equal(t, 254, fillerTeams[0].TeamId)
+	}
+	match, _ := arena.Database.GetMatchById(practiceMatch.Id)
+	assert.Equal(t, 1114, match.Red2)
+	assert.Equal(t, 2056, match.Blue1)
+}
+
+func TestAssignFillerTeamsSmallAlliances(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.TeamsPerAlliance = 2
+
+	for _, teamId := range []int{254, 1114, 2056, 148, 846} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+		arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: teamId})
+	}
+
+	// Only the positions in use should be filled, leaving the rest of the line for later matches.
+	practiceMatch := model.Match{Type: model.Practice, Red1: 604}
+	arena.Database.CreateMatch(&practiceMatch)
+	assert.Nil(t, arena.LoadMatch(&practiceMatch))
+	assert.Equal(t, 254, practiceMatch.Red2)
+	assert.Equal(t, 0, practiceMatch.Red3)
+	assert.Equal(t, 1114, practiceMatch.Blue1)
+	assert.Equal(t, 2056, practiceMatch.Blue2)
+	assert.Equal(t, 0, practiceMatch.Blue3)
+	fillerTeams, _ := arena.Database.GetAllFillerTeams()
+	if assert.Equal(t, 2, len(fillerTeams)) {
+		assert.Equal(t, 148, fillerTeams[0].TeamId)
+		assert.Equal(t, 846, fillerTeams[1].TeamId)
+	}
+}
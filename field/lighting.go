@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for choosing the DMX lighting scene to show based on the state of the arena.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
+	"github.com/Team254/cheesy-arena/logging"
+	"time"
+)
+
+// Sends the levels for the scene matching the current arena state to the configured lighting receiver, if any.
+func (arena *Arena) updateLighting() {
+	if !arena.Lighting.IsEnabled() {
+		return
+	}
+
+	scene := lighting.FindScene(arena.EventSettings.LightingScenes, arena.currentLightingScene())
+	if scene == nil {
+		// Leave the lights as they are if the event doesn't define a scene for this state.
+		return
+	}
+	if err := arena.Lighting.SetChannels(scene.Channels); err != nil {
+		logging.Hardware.Error("Failed to send lighting packet.", "error", err)
+	}
+}
+
+// Returns the name of the lighting scene that corresponds to the current arena state.
+func (arena *Arena) currentLightingScene() lighting.SceneName {
+	switch arena.MatchState {
+	case StartMatch, WarmupPeriod, AutoPeriod, PausePeriod:
+		return lighting.SceneAuto
+	case TeleopPeriod:
+		warningTime := game.GetDurationToTeleopEnd() -
+			time.Duration(game.MatchTiming.WarningRemainingDurationSec)*time.Second
+		if arena.MatchTimeSec() >= warningTime.Seconds() {
+			return lighting.SceneEndgame
+		}
+		return lighting.SceneTeleop
+	case FieldFault:
+		return lighting.SceneFieldFault
+	case PostMatch:
+		return lighting.ScenePostMatch
+	}
+
+	// Once the score of the previous match has been revealed, show the outcome until the next match starts.
+	if arena.AudienceDisplayMode == "score" {
+		switch arena.SavedMatch.Status {
+		case game.RedWonMatch:
+			return lighting.SceneRedWin
+		case game.BlueWonMatch:
+			return lighting.SceneBlueWin
+		case game.TieMatch:
+			return lighting.SceneTie
+		}
+	}
+	return lighting.ScenePreMatch
+}
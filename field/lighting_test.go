@@ -0,0 +1,47 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCurrentLightingScene(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.Equal(t, lighting.ScenePreMatch, arena.currentLightingScene())
+	arena.MatchState = AutoPeriod
+	assert.Equal(t, lighting.SceneAuto, arena.currentLightingScene())
+	arena.MatchState = PausePeriod
+	assert.Equal(t, lighting.SceneAuto, arena.currentLightingScene())
+
+	arena.MatchState = TeleopPeriod
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopStart())
+	assert.Equal(t, lighting.SceneTeleop, arena.currentLightingScene())
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd() + time.Second)
+	assert.Equal(t, lighting.SceneEndgame, arena.currentLightingScene())
+
+	arena.MatchState = FieldFault
+	assert.Equal(t, lighting.SceneFieldFault, arena.currentLightingScene())
+	arena.MatchState = PostMatch
+	assert.Equal(t, lighting.ScenePostMatch, arena.currentLightingScene())
+
+	// The outcome should only be shown once the score has been revealed.
+	arena.MatchState = PreMatch
+	arena.SavedMatch = &model.Match{Status: game.BlueWonMatch}
+	assert.Equal(t, lighting.ScenePreMatch, arena.currentLightingScene())
+	arena.AudienceDisplayMode = "score"
+	assert.Equal(t, lighting.SceneBlueWin, arena.currentLightingScene())
+	arena.SavedMatch.Status = game.RedWonMatch
+	assert.Equal(t, lighting.SceneRedWin, arena.currentLightingScene())
+	arena.SavedMatch.Status = game.TieMatch
+	assert.Equal(t, lighting.SceneTie, arena.currentLightingScene())
+	arena.MatchState = AutoPeriod
+	assert.Equal(t, lighting.SceneAuto, arena.currentLightingScene())
+}
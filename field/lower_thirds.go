@@ -0,0 +1,80 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for showing saved lower thirds on the audience display, either individually or by stepping through a
+// playlist of them.
+
+package field
+
+import (
+	"fmt"
+)
+
+// Shows the saved lower third having the given ID on the audience display.
+func (arena *Arena) ShowSavedLowerThird(id int) error {
+	lowerThird, err := arena.Database.GetLowerThirdById(id)
+	if err != nil {
+		return err
+	}
+	if lowerThird == nil {
+		return fmt.Errorf("lower third %d does not exist", id)
+	}
+
+	arena.LowerThird = lowerThird
+	arena.ShowLowerThird = true
+	arena.LowerThirdNotifier.Notify()
+	return nil
+}
+
+// Hides whichever lower third is showing on the audience display.
+func (arena *Arena) HideLowerThird() {
+	arena.ShowLowerThird = false
+	arena.LowerThirdNotifier.Notify()
+}
+
+// Makes the playlist having the given ID the one that is stepped through, starting again from before its first entry.
+// A zero ID deselects the current playlist.
+func (arena *Arena) SelectLowerThirdPlaylist(id int) error {
+	if id != 0 {
+		playlist, err := arena.Database.GetLowerThirdPlaylistById(id)
+		if err != nil {
+			return err
+		}
+		if playlist == nil {
+			return fmt.Errorf("lower third playlist %d does not exist", id)
+		}
+	}
+
+	arena.LowerThirdPlaylistId = id
+	arena.LowerThirdPlaylistPosition = -1
+	arena.LowerThirdNotifier.Notify()
+	return nil
+}
+
+// Shows the next lower third in the selected playlist on the audience display.
+func (arena *Arena) AdvanceLowerThirdPlaylist() error {
+	if arena.LowerThirdPlaylistId == 0 {
+		return fmt.Errorf("no lower third playlist has been selected")
+	}
+	playlist, err := arena.Database.GetLowerThirdPlaylistById(arena.LowerThirdPlaylistId)
+	if err != nil {
+		return err
+	}
+	if playlist == nil {
+		return fmt.Errorf("lower third playlist %d does not exist", arena.LowerThirdPlaylistId)
+	}
+	lowerThirds, err := arena.Database.GetLowerThirdPlaylistEntries(playlist)
+	if err != nil {
+		return err
+	}
+
+	nextPosition := arena.LowerThirdPlaylistPosition + 1
+	if nextPosition >= len(lowerThirds) {
+		return fmt.Errorf("the lower third playlist '%s' has no more entries", playlist.Name)
+	}
+	arena.LowerThird = &lowerThirds[nextPosition]
+	arena.ShowLowerThird = true
+	arena.LowerThirdPlaylistPosition = nextPosition
+	arena.LowerThirdNotifier.Notify()
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestShowSavedLowerThird(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.NotNil(t, arena.ShowSavedLowerThird(1))
+	assert.False(t, arena.ShowLowerThird)
+
+	arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Welcome", DisplayOrder: 1})
+	assert.Nil(t, arena.ShowSavedLowerThird(1))
+	assert.True(t, arena.ShowLowerThird)
+	assert.Equal(t, "Welcome", arena.LowerThird.TopText)
+
+	arena.HideLowerThird()
+	assert.False(t, arena.ShowLowerThird)
+}
+
+func TestLowerThirdPlaylist(t *testing.T) {
+	arena := setupTestArena(t)
+
+	err := arena.AdvanceLowerThirdPlaylist()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no lower third playlist has been selected")
+	}
+	assert.NotNil(t, arena.SelectLowerThirdPlaylist(1))
+
+	arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Welcome", DisplayOrder: 1})
+	arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Emcee", BottomText: "Jane Doe", DisplayOrder: 2})
+	arena.Database.CreateLowerThirdPlaylist(&model.LowerThirdPlaylist{Name: "Opening", LowerThirdIds: []int{2, 1}})
+	assert.Nil(t, arena.SelectLowerThirdPlaylist(1))
+	assert.Equal(t, 1, arena.LowerThirdPlaylistId)
+	assert.Equal(t, -1, arena.LowerThirdPlaylistPosition)
+
+	assert.Nil(t, arena.AdvanceLowerThirdPlaylist())
+	assert.Equal(t, 0, arena.LowerThirdPlaylistPosition)
+	assert.True(t, arena.ShowLowerThird)
+	assert.Equal(t, "Emcee", arena.LowerThird.TopText)
+	assert.Nil(t, arena.AdvanceLowerThirdPlaylist())
+	assert.Equal(t, 1, arena.LowerThirdPlaylistPosition)
+	assert.Equal(t, "Welcome", arena.LowerThird.TopText)
+	err = arena.AdvanceLowerThirdPlaylist()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "has no more entries")
+	}
+	assert.Equal(t, 1, arena.LowerThirdPlaylistPosition)
+
+	// Check that selecting the playlist again starts it over.
+	assert.Nil(t, arena.SelectLowerThirdPlaylist(1))
+	assert.Equal(t, -1, arena.LowerThirdPlaylistPosition)
+	assert.Nil(t, arena.SelectLowerThirdPlaylist(0))
+	assert.Equal(t, 0, arena.LowerThirdPlaylistId)
+}
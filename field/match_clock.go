@@ -0,0 +1,63 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for publishing the authoritative match clock to external devices such as scoreboard controllers and stream
+// overlays, which render it locally and use the server timestamps to stay in sync with the field.
+
+package field
+
+import (
+	"time"
+)
+
+// How often clients should re-run the clock synchronization exchange to correct for drift in their local clocks.
+const MatchClockResyncIntervalMs = 5000
+
+// Represents a snapshot of the match clock at the instant given by ServerTimeMs. Clients should estimate the offset
+// between their own clock and the server's using a sync exchange, and then extrapolate the match time from
+// MatchStartTimeMs while Running is true rather than waiting for the next snapshot.
+type MatchClockMessage struct {
+	MatchState
+	Running          bool
+	MatchStartTimeMs int64 // Server time at which the current match or timeout started, or zero if not running.
+	MatchTimeMs      int64 // Milliseconds elapsed since the start of the match at the moment of the snapshot.
+	ServerTimeMs     int64
+	ResyncIntervalMs int
+}
+
+// Represents the reply to a client's clock synchronization request, from which the client can calculate its clock
+// offset as ((ServerReceiveTimeMs - ClientTimeMs) + (ServerSendTimeMs - clientReceiveTimeMs)) / 2 and the round-trip
+// delay as (clientReceiveTimeMs - ClientTimeMs) - (ServerSendTimeMs - ServerReceiveTimeMs).
+type MatchClockSyncMessage struct {
+	ClientTimeMs        int64
+	ServerReceiveTimeMs int64
+	ServerSendTimeMs    int64
+}
+
+func (arena *Arena) GenerateMatchClockMessage() any {
+	now := time.Now()
+	message := MatchClockMessage{
+		MatchState:       arena.MatchState,
+		ServerTimeMs:     now.UnixMilli(),
+		ResyncIntervalMs: MatchClockResyncIntervalMs,
+	}
+	if arena.MatchState == FieldFault {
+		// The clock is stopped, so clients should show the time at which it stopped without extrapolating.
+		message.MatchTimeMs = int64(arena.MatchTimeSec() * 1000)
+	} else if arena.MatchTimeSec() > 0 {
+		message.Running = true
+		message.MatchStartTimeMs = arena.MatchStartTime.UnixMilli()
+		message.MatchTimeMs = now.Sub(arena.MatchStartTime).Milliseconds()
+	}
+	return message
+}
+
+// Returns the reply to a clock synchronization request sent by a client at the given time on its own clock and
+// received by the server at the given time.
+func GenerateMatchClockSyncMessage(clientTimeMs int64, serverReceiveTime time.Time) MatchClockSyncMessage {
+	return MatchClockSyncMessage{
+		ClientTimeMs:        clientTimeMs,
+		ServerReceiveTimeMs: serverReceiveTime.UnixMilli(),
+		ServerSendTimeMs:    time.Now().UnixMilli(),
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for working out the cues to show the human players and announcer as a match progresses.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"math"
+	"reflect"
+	"time"
+)
+
+type MatchCuesMessage struct {
+	Red   []game.MatchCue
+	Blue  []game.MatchCue
+	Field []game.MatchCue // Cues that relate to the match as a whole rather than to either alliance.
+}
+
+// Recalculates the cues for the current state of the match and sends out a notification if any of them changed.
+func (arena *Arena) updateMatchCues() {
+	var matchCues MatchCuesMessage
+	switch arena.MatchState {
+	case AutoPeriod, PausePeriod, TeleopPeriod:
+		currentTime := time.Now()
+		matchCues.Red = game.CurrentGame().MatchCues(
+			&arena.RedRealtimeScore.CurrentScore, arena.MatchStartTime, currentTime,
+		)
+		matchCues.Blue = game.CurrentGame().MatchCues(
+			&arena.BlueRealtimeScore.CurrentScore, arena.MatchStartTime, currentTime,
+		)
+
+		if arena.MatchState == TeleopPeriod {
+			remainingSec := int(math.Ceil(
+				game.GetDurationToTeleopEnd().Seconds() - currentTime.Sub(arena.MatchStartTime).Seconds(),
+			))
+			if remainingSec <= game.MatchTiming.WarningRemainingDurationSec {
+				matchCues.Field = append(
+					matchCues.Field,
+					game.MatchCue{Audience: game.AnnouncerCue, Text: fmt.Sprintf("ENDGAME: %ds left", remainingSec)},
+				)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(matchCues, arena.matchCues) {
+		arena.matchCues = matchCues
+		arena.MatchCuesNotifier.Notify()
+	}
+}
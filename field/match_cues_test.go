@@ -0,0 +1,47 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUpdateMatchCues(t *testing.T) {
+	arena := setupTestArena(t)
+	game.MelodyBonusThresholdWithCoop = 15
+
+	// There shouldn't be any cues outside of a match.
+	arena.RedRealtimeScore.CurrentScore.AmpSpeaker.BankedAmpNotes = 2
+	arena.updateMatchCues()
+	assert.Equal(t, MatchCuesMessage{}, arena.matchCues)
+
+	arena.MatchState = TeleopPeriod
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopStart() - 10*time.Second)
+	arena.updateMatchCues()
+	assert.Equal(
+		t,
+		[]game.MatchCue{
+			{Audience: game.HumanPlayerCue, Text: "Ready to AMPLIFY"},
+			{Audience: game.HumanPlayerCue, Text: "Co-op window open for 35s"},
+		},
+		arena.matchCues.Red,
+	)
+	assert.Empty(t, arena.matchCues.Blue)
+	assert.Empty(t, arena.matchCues.Field)
+
+	// The endgame cue should count down over the warning period.
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd() + 12*time.Second)
+	arena.updateMatchCues()
+	assert.Equal(
+		t, []game.MatchCue{{Audience: game.AnnouncerCue, Text: "ENDGAME: 12s left"}}, arena.matchCues.Field,
+	)
+	assert.Equal(t, []game.MatchCue{{Audience: game.HumanPlayerCue, Text: "Ready to AMPLIFY"}}, arena.matchCues.Red)
+
+	arena.MatchState = PostMatch
+	arena.updateMatchCues()
+	assert.Equal(t, MatchCuesMessage{}, arena.matchCues)
+}
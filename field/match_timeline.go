@@ -0,0 +1,102 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for recording a timeline of the events that occur during a match, for use in resolving disputes.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+var matchStateNames = map[MatchState]string{
+	PreMatch:      "Pre-match",
+	StartMatch:    "Start match",
+	WarmupPeriod:  "Warmup",
+	AutoPeriod:    "Autonomous",
+	PausePeriod:   "Pause",
+	TeleopPeriod:  "Teleoperated",
+	PostMatch:     "Post-match",
+	TimeoutActive: "Timeout",
+	PostTimeout:   "Post-timeout",
+	FieldFault:    "Field fault",
+}
+
+// Appends an event to the timeline of the match currently loaded into the arena.
+func (arena *Arena) RecordTimelineEvent(eventType model.MatchTimelineEventType, source, description string) {
+	arena.timelineMutex.Lock()
+	defer arena.timelineMutex.Unlock()
+
+	event := model.MatchTimelineEvent{Time: time.Now(), Type: eventType, Source: source, Description: description}
+	if !arena.MatchStartTime.IsZero() && arena.MatchState != PreMatch && arena.MatchState != TimeoutActive &&
+		arena.MatchState != PostTimeout {
+		event.MatchTimeMs = int(event.Time.Sub(arena.MatchStartTime).Milliseconds())
+		if arena.MatchState == FieldFault {
+			// The match clock is stopped for the duration of the fault.
+			event.MatchTimeMs = int(arena.fieldFaultStartTime.Sub(arena.MatchStartTime).Milliseconds())
+		}
+	}
+	arena.matchTimeline = append(arena.matchTimeline, event)
+}
+
+// Returns a copy of the timeline of the match currently loaded into the arena.
+func (arena *Arena) GetMatchTimeline() []model.MatchTimelineEvent {
+	arena.timelineMutex.Lock()
+	defer arena.timelineMutex.Unlock()
+
+	return append([]model.MatchTimelineEvent(nil), arena.matchTimeline...)
+}
+
+func (arena *Arena) resetMatchTimeline() {
+	arena.timelineMutex.Lock()
+	defer arena.timelineMutex.Unlock()
+
+	arena.matchTimeline = nil
+}
+
+// Records any change in the match state or in the driver station connections since the last loop iteration.
+func (arena *Arena) updateMatchTimeline() {
+	if arena.MatchState != arena.lastMatchState && arena.lastMatchState != -1 {
+		arena.RecordTimelineEvent(
+			model.TimelineMatchState, "", fmt.Sprintf("Match state changed to %s", matchStateNames[arena.MatchState]),
+		)
+	}
+
+	// Only track connections while a match is running, since they come and go freely before and after.
+	inMatch := arena.MatchState != PreMatch && arena.MatchState != TimeoutActive && arena.MatchState != PostTimeout
+	for _, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		allianceStation := arena.AllianceStations[station]
+		var dsLinked, robotLinked bool
+		if allianceStation.DsConn != nil {
+			dsLinked = allianceStation.DsConn.DsLinked
+			robotLinked = allianceStation.DsConn.RobotLinked
+		}
+		if inMatch && allianceStation.Team != nil {
+			if dsLinked != allianceStation.timelineDsLinked {
+				arena.RecordTimelineEvent(
+					model.TimelineDsConnection,
+					station,
+					fmt.Sprintf("Driver station for team %d %s", allianceStation.Team.Id, connectionVerb(dsLinked)),
+				)
+			}
+			if robotLinked != allianceStation.timelineRobotLinked {
+				arena.RecordTimelineEvent(
+					model.TimelineDsConnection,
+					station,
+					fmt.Sprintf("Robot for team %d %s", allianceStation.Team.Id, connectionVerb(robotLinked)),
+				)
+			}
+		}
+		allianceStation.timelineDsLinked = dsLinked
+		allianceStation.timelineRobotLinked = robotLinked
+	}
+}
+
+func connectionVerb(connected bool) string {
+	if connected {
+		return "connected"
+	}
+	return "disconnected"
+}
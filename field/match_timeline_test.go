@@ -0,0 +1,85 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMatchTimeline(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	match := model.Match{Red1: 254}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{
+		TeamId: 254, DsLinked: true, RobotLinked: true, lastPacketTime: time.Now(),
+	}
+	for _, station := range []string{"R2", "R3", "B1", "B2", "B3"} {
+		arena.AllianceStations[station].Bypass = true
+	}
+	arena.Update()
+
+	// Connection changes before the match aren't recorded.
+	assert.Empty(t, arena.GetMatchTimeline())
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+2) * time.Second)
+	arena.Update()
+	arena.AllianceStations["R1"].DsConn.RobotLinked = false
+	arena.Update()
+	arena.handleTeamStop("R1", true, false)
+	arena.handleTeamStop("R1", true, false)
+	arena.RecordTimelineEvent(model.TimelineFoul, "red", "Added foul 1 (head referee)")
+
+	events := arena.GetMatchTimeline()
+	if assert.Equal(t, 5, len(events)) {
+		assert.Equal(t, model.TimelineMatchState, events[0].Type)
+		assert.Equal(t, "Match state changed to Warmup", events[0].Description)
+		assert.Equal(t, "Match state changed to Autonomous", events[1].Description)
+		assert.Equal(t, model.TimelineDsConnection, events[2].Type)
+		assert.Equal(t, "R1", events[2].Source)
+		assert.Equal(t, "Robot for team 254 disconnected", events[2].Description)
+		assert.Equal(t, model.TimelineStop, events[3].Type)
+		assert.Equal(t, "E-stop activated", events[3].Description)
+		assert.Equal(t, model.TimelineFoul, events[4].Type)
+		assert.Equal(t, "red", events[4].Source)
+		assert.GreaterOrEqual(t, events[4].MatchTimeMs, (game.MatchTiming.WarmupDurationSec+2)*1000)
+	}
+
+	// Loading the next match starts a fresh timeline.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Empty(t, arena.GetMatchTimeline())
+}
+
+func TestMatchTimelineFouls(t *testing.T) {
+	arena := setupTestArena(t)
+	panel, _ := NewRefereePanel("head", "")
+
+	foul := arena.AddFoul(panel, "blue", true)
+	assert.Nil(t, arena.EditFoul(panel, "blue", foul.FoulId, 0, func(foul *game.Foul) error {
+		foul.TeamId = 148
+		return nil
+	}))
+	arena.SetCard("blue", 148, "yellow")
+	assert.Nil(t, arena.DeleteFoul(panel, "blue", foul.FoulId, 1))
+
+	events := arena.GetMatchTimeline()
+	if assert.Equal(t, 4, len(events)) {
+		assert.Equal(t, model.MatchTimelineEvent{
+			Time: events[0].Time, Type: model.TimelineFoul, Source: "blue", Description: "Added tech foul 1 (head referee)",
+		}, events[0])
+		assert.Equal(t, "Updated tech foul 1 by team 148 (head referee)", events[1].Description)
+		assert.Equal(t, model.TimelineCard, events[2].Type)
+		assert.Equal(t, "Yellow card given to team 148", events[2].Description)
+		assert.Equal(t, "Deleted tech foul 1 by team 148 (head referee)", events[3].Description)
+	}
+}
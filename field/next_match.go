@@ -0,0 +1,126 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for choosing which match should be loaded after the current one, including manual overrides.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Sets the match that should be loaded next instead of the next one in the schedule, or clears the override if the
+// given match ID is zero. The override is cleared automatically once the match is loaded.
+func (arena *Arena) SetNextMatchOverride(matchId int) error {
+	if matchId != 0 {
+		match, err := arena.Database.GetMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		if err = checkNextMatchCandidate(match); err != nil {
+			return err
+		}
+		if match.Id == arena.CurrentMatch.Id {
+			return fmt.Errorf("match %s is already loaded", match.ShortName)
+		}
+		if err = arena.checkMatchNotOnOtherField(match); err != nil {
+			return err
+		}
+	}
+
+	arena.NextMatchOverrideId = matchId
+	arena.UpdateOnDeckMatch()
+	return nil
+}
+
+// Marks the given match as a bye so that it is skipped when choosing the next match, or clears the mark.
+func (arena *Arena) SetMatchBye(matchId int, bye bool) error {
+	match, err := arena.Database.GetMatchById(matchId)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		return fmt.Errorf("match does not exist")
+	}
+	if bye && (match.Type == model.Test || match.Type == model.Playoff) {
+		return fmt.Errorf("cannot mark %s match %s as a bye", match.Type, match.ShortName)
+	}
+	if bye && match.Id == arena.CurrentMatch.Id {
+		return fmt.Errorf("match %s is already loaded", match.ShortName)
+	}
+	match.Bye = bye
+	if err = arena.Database.UpdateMatch(match); err != nil {
+		return err
+	}
+	if bye && arena.NextMatchOverrideId == match.Id {
+		arena.NextMatchOverrideId = 0
+	}
+	arena.UpdateOnDeckMatch()
+	return nil
+}
+
+// Returns the match that should be loaded after the current one, or nil if there are no more matches. This is the
+// manually chosen match if an override is in effect, and otherwise the first unplayed match of the same type as the
+// one currently loaded, skipping byes, replaced matches and any matches loaded on other fields. In practice sandbox
+// mode, practice matches are never marked as played, so those that already have a sandbox result are skipped instead.
+func (arena *Arena) getNextMatch(excludeCurrent bool) (*model.Match, error) {
+	if arena.NextMatchOverrideId != 0 {
+		match, err := arena.Database.GetMatchById(arena.NextMatchOverrideId)
+		if err != nil {
+			return nil, err
+		}
+		if checkNextMatchCandidate(match) == nil && !(excludeCurrent && match.Id == arena.CurrentMatch.Id) &&
+			arena.checkMatchNotOnOtherField(match) == nil {
+			return match, nil
+		}
+	}
+
+	if arena.CurrentMatch.Type == model.Test {
+		return nil, nil
+	}
+
+	matches, err := arena.Database.GetMatchesByType(arena.CurrentMatch.Type, false)
+	if err != nil {
+		return nil, err
+	}
+	sandboxPlayedMatchIds := make(map[int]bool)
+	if arena.CurrentMatch.Type == model.Practice && arena.EventSettings.PracticeSandboxEnabled {
+		sandboxMatchResults, err := arena.Database.GetAllSandboxMatchResults()
+		if err != nil {
+			return nil, err
+		}
+		for _, sandboxMatchResult := range sandboxMatchResults {
+			sandboxPlayedMatchIds[sandboxMatchResult.MatchId] = true
+		}
+	}
+	for _, match := range matches {
+		if checkNextMatchCandidate(&match) == nil && !(excludeCurrent && match.Id == arena.CurrentMatch.Id) &&
+			!sandboxPlayedMatchIds[match.Id] && arena.checkMatchNotOnOtherField(&match) == nil {
+			return &match, nil
+		}
+	}
+
+	// There are no matches left of the same type.
+	return nil, nil
+}
+
+// Returns an error explaining why the given match can't be played next, or nil if it can.
+func checkNextMatchCandidate(match *model.Match) error {
+	switch {
+	case match == nil:
+		return fmt.Errorf("match does not exist")
+	case match.Type == model.Test:
+		return fmt.Errorf("cannot choose a test match to be played next")
+	case match.Status == game.MatchHidden:
+		return fmt.Errorf("match %s is hidden", match.ShortName)
+	case match.IsComplete():
+		return fmt.Errorf("match %s has already been played", match.ShortName)
+	case match.IsReplaced():
+		return fmt.Errorf("match %s has been replaced by a replay", match.ShortName)
+	case match.IsBye():
+		return fmt.Errorf("match %s is a bye", match.ShortName)
+	}
+	return nil
+}
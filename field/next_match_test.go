@@ -0,0 +1,152 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNextMatchSkipsByesAndReplacedMatches(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Bye: true})
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Red1: 254, ReplacedByMatchId: 5},
+	)
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 4, ShortName: "Q4", Blue1: 254, Status: game.MatchHidden},
+	)
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 5, ShortName: "Q3R", Blue2: 254})
+
+	match, _ := arena.Database.GetMatchById(1)
+	assert.Nil(t, arena.LoadMatch(match))
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, "Q3R", arena.OnDeck.Match.ShortName)
+		assert.False(t, arena.OnDeck.IsOverride)
+	}
+
+	match.Status = game.RedWonMatch
+	arena.Database.UpdateMatch(match)
+	assert.Nil(t, arena.LoadNextMatch(false))
+	assert.Equal(t, "Q3R", arena.CurrentMatch.ShortName)
+	assert.Nil(t, arena.OnDeck)
+}
+
+func TestNextMatchOverride(t *testing.T) {
+	arena := setupTestArena(t)
+	for i := 1; i <= 4; i++ {
+		arena.Database.CreateMatch(
+			&model.Match{Type: model.Qualification, TypeOrder: i, ShortName: fmt.Sprintf("Q%d", i), Red1: 254},
+		)
+	}
+	arena.Database.CreateMatch(&model.Match{Type: model.Practice, TypeOrder: 1, Blue1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: model.Practice, TypeOrder: 2, ShortName: "P2", Bye: true})
+	match, _ := arena.Database.GetMatchById(1)
+	assert.Nil(t, arena.LoadMatch(match))
+
+	// Check that invalid overrides are rejected.
+	err := arena.SetNextMatchOverride(1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match Q1 is already loaded", err.Error())
+	}
+	err = arena.SetNextMatchOverride(6)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match P2 is a bye", err.Error())
+	}
+	err = arena.SetNextMatchOverride(254)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match does not exist", err.Error())
+	}
+	assert.Equal(t, 0, arena.NextMatchOverrideId)
+
+	// Check that the override takes the place of the next match in the schedule until it is loaded.
+	assert.Nil(t, arena.SetNextMatchOverride(3))
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, 3, arena.OnDeck.Match.Id)
+		assert.True(t, arena.OnDeck.IsOverride)
+	}
+	assert.Nil(t, arena.LoadNextMatch(false))
+	assert.Equal(t, 3, arena.CurrentMatch.Id)
+	assert.Equal(t, 0, arena.NextMatchOverrideId)
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, 1, arena.OnDeck.Match.Id)
+		assert.False(t, arena.OnDeck.IsOverride)
+	}
+
+	// Check that an override can switch to a different match type and can be cleared.
+	assert.Nil(t, arena.SetNextMatchOverride(5))
+	assert.Equal(t, 5, arena.OnDeck.Match.Id)
+	assert.Nil(t, arena.SetNextMatchOverride(0))
+	assert.Equal(t, 1, arena.OnDeck.Match.Id)
+	assert.Nil(t, arena.SetNextMatchOverride(5))
+	assert.Nil(t, arena.LoadNextMatch(false))
+	assert.Equal(t, model.Practice, arena.CurrentMatch.Type)
+
+	// Check that an override that has since been played is ignored.
+	assert.Nil(t, arena.SetNextMatchOverride(4))
+	match, _ = arena.Database.GetMatchById(4)
+	match.Status = game.BlueWonMatch
+	arena.Database.UpdateMatch(match)
+	assert.Nil(t, arena.LoadNextMatch(false))
+	assert.Equal(t, 5, arena.CurrentMatch.Id)
+}
+
+func TestSetMatchBye(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Red1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: model.Playoff, TypeOrder: 1, ShortName: "F1", Red1: 254})
+	match, _ := arena.Database.GetMatchById(1)
+	assert.Nil(t, arena.LoadMatch(match))
+
+	err := arena.SetMatchBye(1, true)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match Q1 is already loaded", err.Error())
+	}
+	err = arena.SetMatchBye(4, true)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot mark Playoff match F1 as a bye", err.Error())
+	}
+	err = arena.SetMatchBye(254, true)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match does not exist", err.Error())
+	}
+
+	// Check that marking the on-deck match as a bye moves on to the one after it, and that it can be unmarked.
+	assert.Nil(t, arena.SetNextMatchOverride(2))
+	assert.Nil(t, arena.SetMatchBye(2, true))
+	assert.Equal(t, 0, arena.NextMatchOverrideId)
+	assert.Equal(t, "Q3", arena.OnDeck.Match.ShortName)
+	assert.Nil(t, arena.SetMatchBye(2, false))
+	assert.Equal(t, "Q2", arena.OnDeck.Match.ShortName)
+}
+
+func TestLoadNextMatchFillsEmptyPracticeMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateMatch(&model.Match{Type: model.Practice, TypeOrder: 1, ShortName: "P1", Red1: 1, Red2: 2,
+		Red3: 3, Blue1: 4, Blue2: 5, Blue3: 6})
+	arena.Database.CreateMatch(&model.Match{Type: model.Practice, TypeOrder: 2, ShortName: "P2"})
+	arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: 1114})
+	arena.Database.CreateFillerTeam(&model.FillerTeam{TeamId: 2056})
+	match, _ := arena.Database.GetMatchById(1)
+	assert.Nil(t, arena.LoadMatch(match))
+
+	// An empty practice match isn't a bye, so it should be loaded and filled from the filler line.
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, "P2", arena.OnDeck.Match.ShortName)
+	}
+	match.Status = game.RedWonMatch
+	arena.Database.UpdateMatch(match)
+	assert.Nil(t, arena.LoadNextMatch(false))
+	assert.Equal(t, "P2", arena.CurrentMatch.ShortName)
+	assert.Equal(t, 1114, arena.CurrentMatch.Red1)
+	assert.Equal(t, 2056, arena.CurrentMatch.Red2)
+	fillerTeams, _ := arena.Database.GetAllFillerTeams()
+	assert.Empty(t, fillerTeams)
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for tracking and pre-validating the match that will follow the one currently loaded.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Represents the match that is up next after the current one, along with anything that would hold it up.
+type OnDeckMatch struct {
+	Match      *model.Match
+	Teams      map[string]*model.Team
+	Issues     []string
+	IsOverride bool // Whether the match was chosen manually instead of being the next one in the schedule.
+}
+
+// Returns the teams of the on-deck match in alliance station order, for use in configuring the field network.
+func (onDeck *OnDeckMatch) teamsInStationOrder() [6]*model.Team {
+	return [6]*model.Team{
+		onDeck.Teams["R1"], onDeck.Teams["R2"], onDeck.Teams["R3"],
+		onDeck.Teams["B1"], onDeck.Teams["B2"], onDeck.Teams["B3"],
+	}
+}
+
+// Finds the match following the current one and checks its teams for anything that would prevent it from being
+// started promptly once loaded, such as a team that has not yet passed inspection.
+func (arena *Arena) UpdateOnDeckMatch() {
+	arena.OnDeck = nil
+	defer arena.OnDeckNotifier.Notify()
+
+	nextMatch, err := arena.getNextMatch(true)
+	if err != nil {
+		logging.Arena.Error("Failed to get on-deck match.", "error", err)
+		return
+	}
+	if nextMatch == nil {
+		return
+	}
+
+	onDeck := OnDeckMatch{
+		Match:      nextMatch,
+		Teams:      make(map[string]*model.Team),
+		Issues:     []string{},
+		IsOverride: nextMatch.Id == arena.NextMatchOverrideId,
+	}
+	teamIds := [6]int{
+		nextMatch.Red1, nextMatch.Red2, nextMatch.Red3, nextMatch.Blue1, nextMatch.Blue2, nextMatch.Blue3,
+	}
+	for i, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		onDeck.Teams[station] = nil
+		if teamIds[i] == 0 {
+			continue
+		}
+		team, err := arena.Database.GetTeamById(teamIds[i])
+		if err != nil {
+			logging.Arena.Error("Failed to get team in on-deck match.", "team", teamIds[i], "error", err)
+			continue
+		}
+		if team == nil {
+			onDeck.Issues = append(onDeck.Issues, fmt.Sprintf("Team %d is not in the team list.", teamIds[i]))
+			continue
+		}
+		onDeck.Teams[station] = team
+
+		teamStatus, err := arena.Database.GetTeamStatus(team.Id)
+		if err != nil {
+			logging.Arena.Error("Failed to get status of team in on-deck match.", "team", team.Id, "error", err)
+			continue
+		}
+		if !teamStatus.InspectionPassed {
+			onDeck.Issues = append(onDeck.Issues, fmt.Sprintf("Team %d has not passed inspection.", team.Id))
+		}
+		if !teamStatus.Weighed {
+			onDeck.Issues = append(onDeck.Issues, fmt.Sprintf("Team %d has not been weighed.", team.Id))
+		}
+		if !teamStatus.HasConnected && (nextMatch.Type == model.Qualification || nextMatch.Type == model.Playoff) {
+			onDeck.Issues = append(
+				onDeck.Issues, fmt.Sprintf("Team %d has not connected to the field yet.", team.Id),
+			)
+		}
+		if teamStatus.YellowCard && nextMatch.ShouldUpdateCards() {
+			onDeck.Issues = append(onDeck.Issues, fmt.Sprintf("Team %d is carrying a yellow card.", team.Id))
+		}
+		if arena.getAssignedAllianceStation(team.Id) != "" {
+			onDeck.Issues = append(
+				onDeck.Issues, fmt.Sprintf("Team %d is playing back-to-back from the current match.", team.Id),
+			)
+		}
+	}
+	arena.OnDeck = &onDeck
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnDeckMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106, 107} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+		arena.Database.SaveTeamStatus(
+			&model.TeamStatus{TeamId: teamId, InspectionPassed: true, Weighed: true, HasConnected: true},
+		)
+	}
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 1, Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105},
+	)
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 2, Red1: 106, Red2: 107, Blue1: 105, Blue2: 108},
+	)
+
+	// A test match has nothing on deck.
+	assert.Nil(t, arena.OnDeck)
+
+	matches, _ := arena.Database.GetMatchesByType(model.Qualification, false)
+	assert.Nil(t, arena.LoadMatch(&matches[0]))
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, matches[1].Id, arena.OnDeck.Match.Id)
+		assert.Equal(t, 106, arena.OnDeck.Teams["R1"].Id)
+		assert.Nil(t, arena.OnDeck.Teams["R3"])
+		assert.Equal(t, 105, arena.OnDeck.Teams["B1"].Id)
+		assert.Equal(t, []string{
+			"Team 105 is playing back-to-back from the current match.",
+			"Team 108 is not in the team list.",
+		}, arena.OnDeck.Issues)
+	}
+
+	arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 107, YellowCard: true})
+	arena.UpdateOnDeckMatch()
+	if assert.NotNil(t, arena.OnDeck) {
+		assert.Equal(t, []string{
+			"Team 107 has not passed inspection.",
+			"Team 107 has not been weighed.",
+			"Team 107 has not connected to the field yet.",
+			"Team 107 is carrying a yellow card.",
+			"Team 105 is playing back-to-back from the current match.",
+			"Team 108 is not in the team list.",
+		}, arena.OnDeck.Issues)
+	}
+
+	// The on-deck match should be staged once the current match is over.
+	arena.MatchState = PostMatch
+	arena.preLoadNextMatch()
+	if assert.NotNil(t, arena.preloadedTeams) {
+		assert.Equal(t, 106, arena.preloadedTeams[0].Id)
+		assert.Equal(t, 105, arena.preloadedTeams[3].Id)
+		assert.Nil(t, arena.preloadedTeams[4])
+	}
+
+	// Nothing is on deck once the last match is loaded.
+	matches[0].Status = game.RedWonMatch
+	arena.Database.UpdateMatch(&matches[0])
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&matches[1]))
+	assert.Nil(t, arena.OnDeck)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and logic for running the match timer and sounds on a standalone practice field, independently of the main
+// field and without any of its driver station, network, or scoring effects.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/websocket"
+	"time"
+)
+
+// Represents the timer of the practice field, which runs through the same periods as a match on the main field.
+type PracticeField struct {
+	MatchState        MatchState
+	MatchStartTime    time.Time
+	MatchTimeNotifier *websocket.Notifier
+	PlaySoundNotifier *websocket.Notifier
+	lastMatchTimeSec  float64
+	lastMatchState    MatchState
+	soundsPlayed      map[*game.MatchSound]struct{}
+}
+
+func NewPracticeField() *PracticeField {
+	practiceField := &PracticeField{MatchState: PreMatch, lastMatchState: -1}
+	practiceField.MatchTimeNotifier = websocket.NewNotifier("matchTime", practiceField.generateMatchTimeMessage)
+	practiceField.PlaySoundNotifier = websocket.NewNotifier("playSound", nil)
+	return practiceField
+}
+
+// Starts the practice field timer from the beginning of the match, as long as it isn't already running.
+func (practiceField *PracticeField) StartMatch() error {
+	if practiceField.MatchState != PreMatch && practiceField.MatchState != PostMatch {
+		return fmt.Errorf("cannot start practice match while one is already in progress")
+	}
+	practiceField.MatchStartTime = time.Now()
+	practiceField.lastMatchTimeSec = -1
+	practiceField.soundsPlayed = make(map[*game.MatchSound]struct{})
+	if game.MatchTiming.WarmupDurationSec > 0 {
+		practiceField.MatchState = WarmupPeriod
+	} else {
+		practiceField.MatchState = AutoPeriod
+	}
+	return nil
+}
+
+// Stops the practice field timer before the end of the match.
+func (practiceField *PracticeField) AbortMatch() error {
+	if !practiceField.isRunning() {
+		return fmt.Errorf("cannot abort practice match when it is not in progress")
+	}
+	practiceField.MatchState = PostMatch
+	practiceField.playSoundAt("abort", time.Now())
+	return nil
+}
+
+// Returns the fractional number of seconds since the start of the practice match.
+func (practiceField *PracticeField) MatchTimeSec() float64 {
+	if !practiceField.isRunning() {
+		return 0
+	}
+	return time.Since(practiceField.MatchStartTime).Seconds()
+}
+
+// Advances the practice field timer through the periods of the match and plays the sounds that are due.
+func (practiceField *PracticeField) Update() {
+	matchTimeSec := practiceField.MatchTimeSec()
+	switch practiceField.MatchState {
+	case WarmupPeriod:
+		if matchTimeSec >= float64(game.MatchTiming.WarmupDurationSec) {
+			practiceField.MatchState = AutoPeriod
+		}
+	case AutoPeriod:
+		if matchTimeSec >= game.GetDurationToAutoEnd().Seconds() {
+			if game.MatchTiming.PauseDurationSec > 0 {
+				practiceField.MatchState = PausePeriod
+			} else {
+				practiceField.MatchState = TeleopPeriod
+			}
+		}
+	case PausePeriod:
+		if matchTimeSec >= game.GetDurationToTeleopStart().Seconds() {
+			practiceField.MatchState = TeleopPeriod
+		}
+	case TeleopPeriod:
+		if matchTimeSec >= game.GetDurationToTeleopEnd().Seconds() {
+			practiceField.MatchState = PostMatch
+		}
+	}
+
+	// Send a match tick notification if passing an integer second threshold or if the match state changed.
+	if int(matchTimeSec) != int(practiceField.lastMatchTimeSec) ||
+		practiceField.MatchState != practiceField.lastMatchState {
+		practiceField.MatchTimeNotifier.Notify()
+	}
+
+	if practiceField.isRunning() {
+		for _, sound := range game.MatchSounds {
+			if sound.MatchTimeSec < 0 {
+				// Skip sounds with negative timestamps; they are meant to only be triggered explicitly.
+				continue
+			}
+			if _, ok := practiceField.soundsPlayed[sound]; !ok {
+				if matchTimeSec > sound.MatchTimeSec && matchTimeSec-sound.MatchTimeSec < 1 {
+					practiceField.playSoundAt(
+						sound.Name,
+						practiceField.MatchStartTime.Add(time.Duration(sound.MatchTimeSec*float64(time.Second))),
+					)
+					practiceField.soundsPlayed[sound] = struct{}{}
+				}
+			}
+		}
+	}
+
+	practiceField.lastMatchTimeSec = matchTimeSec
+	practiceField.lastMatchState = practiceField.MatchState
+}
+
+func (practiceField *PracticeField) isRunning() bool {
+	return practiceField.MatchState != PreMatch && practiceField.MatchState != PostMatch
+}
+
+func (practiceField *PracticeField) playSoundAt(name string, scheduledTime time.Time) {
+	practiceField.PlaySoundNotifier.NotifyWithMessage(PlaySoundMessage{name, scheduledTime.UnixMilli()})
+}
+
+func (practiceField *PracticeField) generateMatchTimeMessage() any {
+	return MatchTimeMessage{practiceField.MatchState, int(practiceField.MatchTimeSec())}
+}
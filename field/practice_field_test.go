@@ -0,0 +1,50 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPracticeField(t *testing.T) {
+	arena := setupTestArena(t)
+	practiceField := arena.PracticeField
+	game.UpdateMatchSounds()
+
+	assert.Equal(t, PreMatch, practiceField.MatchState)
+	assert.NotNil(t, practiceField.AbortMatch())
+	assert.Nil(t, practiceField.StartMatch())
+	assert.NotNil(t, practiceField.StartMatch())
+	assert.Equal(t, WarmupPeriod, practiceField.MatchState)
+
+	// Check that the practice field runs through the periods of the match and plays all the cued sounds without
+	// touching the main field.
+	var matchStates []MatchState
+	for matchTimeSec := 0.5; matchTimeSec < game.GetDurationToTeleopEnd().Seconds()+1; matchTimeSec += 0.5 {
+		setPracticeFieldMatchTime(practiceField, matchTimeSec)
+		arena.Update()
+		if len(matchStates) == 0 || matchStates[len(matchStates)-1] != practiceField.MatchState {
+			matchStates = append(matchStates, practiceField.MatchState)
+		}
+	}
+	assert.Equal(t, []MatchState{WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod, PostMatch}, matchStates)
+	assert.Equal(t, len(game.MatchSoundCues), len(practiceField.soundsPlayed))
+	assert.Equal(t, 0.0, practiceField.MatchTimeSec())
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// Check that a practice match can be aborted and then started over.
+	assert.Nil(t, practiceField.StartMatch())
+	arena.Update()
+	assert.Nil(t, practiceField.AbortMatch())
+	assert.Equal(t, PostMatch, practiceField.MatchState)
+	assert.Nil(t, practiceField.StartMatch())
+}
+
+// Shifts the start of the practice match back so that the given number of seconds has elapsed since.
+func setPracticeFieldMatchTime(practiceField *PracticeField, matchTimeSec float64) {
+	practiceField.MatchStartTime = time.Now().Add(-time.Duration(matchTimeSec * float64(time.Second)))
+}
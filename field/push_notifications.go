@@ -0,0 +1,188 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for notifying spectators and team members via web push when something happens that affects the teams they
+// follow.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/notification"
+	"github.com/Team254/cheesy-arena/partner"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Number of matches ahead of a followed team's match at which its followers are notified.
+	SpectatorPushMatchesAhead = 3
+	// Granularity in minutes at which followers are notified about the event running increasingly late.
+	delayNotificationIncrementMin = 15
+	pushNotificationUrl           = "/spectator"
+)
+
+// Returns the unplayed matches of the type currently being played, starting from the current match, such that the
+// index of each is the number of matches away that it is.
+func (arena *Arena) GetUpcomingMatches() ([]model.Match, error) {
+	if arena.CurrentMatch.Type == model.Test {
+		return []model.Match{}, nil
+	}
+	matches, err := arena.Database.GetMatchesByType(arena.CurrentMatch.Type, false)
+	if err != nil {
+		return nil, err
+	}
+	upcomingMatches := []model.Match{}
+	for _, match := range matches {
+		if !match.IsComplete() && match.TypeOrder >= arena.CurrentMatch.TypeOrder {
+			upcomingMatches = append(upcomingMatches, match)
+		}
+	}
+	return upcomingMatches, nil
+}
+
+// Returns the public key that browsers need in order to subscribe to push notifications from the arena, generating the
+// underlying key pair the first time it is needed.
+func (arena *Arena) GetVapidPublicKey() (string, error) {
+	vapidPrivateKey, err := arena.getVapidPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	return partner.GetVapidPublicKey(vapidPrivateKey)
+}
+
+func (arena *Arena) getVapidPrivateKey() (string, error) {
+	arena.settingsMutex.Lock()
+	defer arena.settingsMutex.Unlock()
+	if arena.EventSettings.WebPushVapidPrivateKey != "" {
+		return arena.EventSettings.WebPushVapidPrivateKey, nil
+	}
+
+	vapidPrivateKey, err := partner.GenerateVapidPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	settings, err := arena.Database.GetEventSettings()
+	if err != nil {
+		return "", err
+	}
+	settings.WebPushVapidPrivateKey = vapidPrivateKey
+	if err = arena.Database.UpdateEventSettings(settings); err != nil {
+		return "", err
+	}
+
+	// Update the settings in memory directly rather than reloading them, since nothing else depends on the key.
+	arena.EventSettings.WebPushVapidPrivateKey = vapidPrivateKey
+	if arena.pendingSettings != nil {
+		arena.pendingSettings.WebPushVapidPrivateKey = vapidPrivateKey
+	}
+	return vapidPrivateKey, nil
+}
+
+// Returns the VAPID private key and contact email that the push notifier uses to identify the arena.
+func (arena *Arena) getWebPushCredentials() (string, string, error) {
+	vapidPrivateKey, err := arena.getVapidPrivateKey()
+	if err != nil {
+		return "", "", err
+	}
+	return vapidPrivateKey, arena.EventSettings.WebPushContactEmail, nil
+}
+
+// Notifies the followers of the teams in the match that is now a few matches away, unless they have already been
+// notified about that match.
+func (arena *Arena) notifyUpcomingMatch() {
+	upcomingMatches, err := arena.GetUpcomingMatches()
+	if err != nil || len(upcomingMatches) <= SpectatorPushMatchesAhead {
+		return
+	}
+	match := upcomingMatches[SpectatorPushMatchesAhead]
+	arena.PushNotifier.Notify(notification.Notification{
+		Topic:   notification.UpcomingMatchTopic,
+		TeamIds: []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3},
+		Key:     fmt.Sprintf("%s:%d", notification.UpcomingMatchTopic, match.Id),
+		Url:     pushNotificationUrl,
+		Message: func(followedTeamIds []int) (string, string) {
+			verb := "is"
+			if len(followedTeamIds) > 1 {
+				verb = "are"
+			}
+			return fmt.Sprintf("%s %s up soon", formatPushTeams(followedTeamIds), verb),
+				fmt.Sprintf("%s is %d matches away.", match.LongName, SpectatorPushMatchesAhead)
+		},
+	})
+}
+
+// Notifies the followers of the teams in the given schedule that it has been published or changed.
+func (arena *Arena) notifyScheduleChangedSubscribers(matchType model.MatchType, matches []model.Match) {
+	var teamIds []int
+	for _, match := range matches {
+		teamIds = append(teamIds, match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3)
+	}
+	arena.PushNotifier.Notify(notification.Notification{
+		Topic:   notification.ScheduleChangedTopic,
+		TeamIds: teamIds,
+		Url:     pushNotificationUrl,
+		Message: func(followedTeamIds []int) (string, string) {
+			return fmt.Sprintf("The %s schedule has been updated", strings.ToLower(matchType.String())),
+				fmt.Sprintf("Check the new match times for %s.", formatPushTeams(followedTeamIds))
+		},
+	})
+}
+
+// Notifies the followers of the teams in the remaining matches each time that the event falls another increment
+// further behind schedule.
+func (arena *Arena) notifyDelay(minutesLate float64) {
+	increments := int(minutesLate / delayNotificationIncrementMin)
+	if increments < 1 {
+		return
+	}
+	upcomingMatches, err := arena.GetUpcomingMatches()
+	if err != nil || len(upcomingMatches) == 0 {
+		return
+	}
+
+	// Note the next match that each team is in, so that its followers can be told when to expect it.
+	nextMatchByTeam := make(map[int]model.Match)
+	var teamIds []int
+	for _, match := range upcomingMatches {
+		for _, teamId := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+			if _, ok := nextMatchByTeam[teamId]; !ok && teamId > 0 {
+				nextMatchByTeam[teamId] = match
+				teamIds = append(teamIds, teamId)
+			}
+		}
+	}
+	delayMin := increments * delayNotificationIncrementMin
+	arena.PushNotifier.Notify(notification.Notification{
+		Topic:   notification.DelayTopic,
+		TeamIds: teamIds,
+		Key:     fmt.Sprintf("%s:%s:%d", notification.DelayTopic, arena.CurrentMatch.Type.String(), delayMin),
+		Url:     pushNotificationUrl,
+		Message: func(followedTeamIds []int) (string, string) {
+			nextMatch := nextMatchByTeam[followedTeamIds[0]]
+			for _, teamId := range followedTeamIds[1:] {
+				if nextMatchByTeam[teamId].TypeOrder < nextMatch.TypeOrder {
+					nextMatch = nextMatchByTeam[teamId]
+				}
+			}
+			expectedTime := nextMatch.Time.Add(time.Duration(minutesLate) * time.Minute)
+			formattedTime := expectedTime.In(arena.EventSettings.Location()).Format("3:04 PM")
+			return fmt.Sprintf("Event is running %d minutes late", int(minutesLate)),
+				fmt.Sprintf("Expect %s around %s.", nextMatch.LongName, formattedTime)
+		},
+	})
+}
+
+// Returns a description of the given teams for use in a notification.
+func formatPushTeams(teamIds []int) string {
+	if len(teamIds) == 1 {
+		return fmt.Sprintf("Team %d", teamIds[0])
+	}
+	teams := make([]string, len(teamIds))
+	for i, teamId := range teamIds {
+		teams[i] = strconv.Itoa(teamId)
+	}
+	return "Teams " + strings.Join(teams, ", ")
+}
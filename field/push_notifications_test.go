@@ -0,0 +1,129 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/notification"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetUpcomingMatches(t *testing.T) {
+	arena := setupTestArena(t)
+
+	upcomingMatches, err := arena.GetUpcomingMatches()
+	assert.Nil(t, err)
+	assert.Empty(t, upcomingMatches)
+
+	for i := 1; i <= 4; i++ {
+		arena.Database.CreateMatch(
+			&model.Match{Type: model.Qualification, TypeOrder: i, ShortName: fmt.Sprintf("Q%d", i)},
+		)
+	}
+	match, _ := arena.Database.GetMatchByTypeOrder(model.Qualification, 1)
+	match.Status = game.RedWonMatch
+	arena.Database.UpdateMatch(match)
+	match, _ = arena.Database.GetMatchByTypeOrder(model.Qualification, 2)
+	assert.Nil(t, arena.LoadMatch(match))
+	upcomingMatches, err = arena.GetUpcomingMatches()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(upcomingMatches)) {
+		assert.Equal(t, "Q2", upcomingMatches[0].ShortName)
+		assert.Equal(t, "Q4", upcomingMatches[2].ShortName)
+	}
+}
+
+func TestPushNotifications(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Mock the push service.
+	paths := make(chan string, 10)
+	pushServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.WriteHeader(201)
+	}))
+	defer pushServer.Close()
+	createPushSubscription := func(path string, topic string, teamIds ...int) {
+		privateKey, _ := ecdh.P256().GenerateKey(rand.Reader)
+		authSecret := make([]byte, 16)
+		rand.Read(authSecret)
+		arena.Database.CreatePushSubscription(&model.PushSubscription{
+			Endpoint:   pushServer.URL + path,
+			P256dhKey:  base64.RawURLEncoding.EncodeToString(privateKey.PublicKey().Bytes()),
+			AuthSecret: base64.RawURLEncoding.EncodeToString(authSecret),
+			TeamIds:    teamIds,
+			Topics:     []string{topic},
+		})
+	}
+	createPushSubscription("/upcoming", notification.UpcomingMatchTopic, 254)
+	createPushSubscription("/schedule", notification.ScheduleChangedTopic, 254)
+	createPushSubscription("/delay", notification.DelayTopic, 254)
+
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1"})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2"})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3"})
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 4, ShortName: "Q4", LongName: "Qualification 4", Blue2: 254},
+	)
+	arena.NotifyScheduleChanged(model.Qualification)
+	assert.Equal(t, "/schedule", receivePushPath(t, paths))
+
+	match, _ := arena.Database.GetMatchByTypeOrder(model.Qualification, 1)
+	assert.Nil(t, arena.LoadMatch(match))
+	assert.Equal(t, "/upcoming", receivePushPath(t, paths))
+	assert.NotEqual(t, "", arena.EventSettings.WebPushVapidPrivateKey)
+
+	// Check that the follower isn't notified again if the match is reloaded.
+	assert.Nil(t, arena.LoadMatch(match))
+	assertNoPushPath(t, paths)
+
+	// Check that the follower is notified as the event falls further behind, but not before.
+	arena.notifyDelay(14)
+	assertNoPushPath(t, paths)
+	arena.notifyDelay(16)
+	assert.Equal(t, "/delay", receivePushPath(t, paths))
+	arena.notifyDelay(25)
+	assertNoPushPath(t, paths)
+	arena.notifyDelay(31)
+	assert.Equal(t, "/delay", receivePushPath(t, paths))
+
+	pushSubscriptions, _ := arena.Database.GetAllPushSubscriptions()
+	if assert.Equal(t, 3, len(pushSubscriptions)) {
+		assert.Equal(t, []string{"upcoming_match:4"}, pushSubscriptions[0].NotifiedKeys)
+		assert.Empty(t, pushSubscriptions[1].NotifiedKeys)
+		assert.Equal(t, []string{"delay:Qualification:15", "delay:Qualification:30"}, pushSubscriptions[2].NotifiedKeys)
+	}
+}
+
+func TestFormatPushTeams(t *testing.T) {
+	assert.Equal(t, "Team 254", formatPushTeams([]int{254}))
+	assert.Equal(t, "Teams 254, 1114", formatPushTeams([]int{254, 1114}))
+}
+
+func receivePushPath(t *testing.T, paths chan string) string {
+	select {
+	case path := <-paths:
+		return path
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timed out waiting for web push")
+		return ""
+	}
+}
+
+func assertNoPushPath(t *testing.T, paths chan string) {
+	select {
+	case path := <-paths:
+		assert.Fail(t, "Unexpected web push", path)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
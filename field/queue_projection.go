@@ -0,0 +1,105 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Projection of upcoming match start times for calling teams to the queue.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+const (
+	maxProjectedMatches   = 10
+	queueOverduePeriodMin = 3
+)
+
+// Queueing status of a team in an upcoming match.
+type QueueStatus string
+
+const (
+	QueueNotYetDue QueueStatus = ""
+	QueueDue       QueueStatus = "due"
+	QueueOverdue   QueueStatus = "overdue"
+)
+
+// Represents the projected timing of an upcoming match and the queueing status of the teams playing in it.
+type ProjectedMatch struct {
+	MatchId            int
+	ShortName          string
+	ScheduledTime      time.Time
+	ProjectedStartTime time.Time
+	QueueTime          time.Time
+	Teams              []QueueingTeam
+}
+
+// Represents a team that is playing in an upcoming match.
+type QueueingTeam struct {
+	TeamId  int
+	Station string
+	Status  QueueStatus
+}
+
+// Returns the projected start and queueing times of the upcoming matches of the type currently being played, based on
+// how late the event is running as of the given time. Teams are due in the queue once the lead time configured in the
+// event settings has been reached, and overdue a few minutes after that.
+func (arena *Arena) GetProjectedMatches(now time.Time) ([]ProjectedMatch, error) {
+	if arena.CurrentMatch.Type == model.Test {
+		return []ProjectedMatch{}, nil
+	}
+	matches, err := arena.Database.GetMatchesByType(arena.CurrentMatch.Type, false)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesLate, _ := arena.getMinutesLate()
+	offset := time.Duration(minutesLate * float64(time.Minute))
+	leadTime := time.Duration(arena.EventSettings.QueueLeadTimeMin) * time.Minute
+	projectedMatches := []ProjectedMatch{}
+	var previousMatch *model.Match
+	for i, match := range matches {
+		if match.IsComplete() || match.TypeOrder < arena.CurrentMatch.TypeOrder {
+			continue
+		}
+		if previousMatch != nil && match.Time.Sub(previousMatch.Time) > MaxMatchGapMin*time.Minute {
+			// Assume that the schedule will have caught up by the end of a significant break.
+			offset = 0
+		}
+		previousMatch = &matches[i]
+
+		projectedMatch := ProjectedMatch{
+			MatchId:            match.Id,
+			ShortName:          match.ShortName,
+			ScheduledTime:      match.Time,
+			ProjectedStartTime: match.Time.Add(offset),
+		}
+		projectedMatch.QueueTime = projectedMatch.ProjectedStartTime.Add(-leadTime)
+		status := QueueNotYetDue
+		if !now.Before(projectedMatch.QueueTime.Add(queueOverduePeriodMin * time.Minute)) {
+			status = QueueOverdue
+		} else if !now.Before(projectedMatch.QueueTime) {
+			status = QueueDue
+		}
+		stations := []struct {
+			name   string
+			teamId int
+		}{
+			{"R1", match.Red1}, {"R2", match.Red2}, {"R3", match.Red3},
+			{"B1", match.Blue1}, {"B2", match.Blue2}, {"B3", match.Blue3},
+		}
+		for _, station := range stations {
+			if station.teamId > 0 {
+				projectedMatch.Teams = append(
+					projectedMatch.Teams, QueueingTeam{TeamId: station.teamId, Station: station.name, Status: status},
+				)
+			}
+		}
+		projectedMatches = append(projectedMatches, projectedMatch)
+
+		if len(projectedMatches) == maxProjectedMatches {
+			break
+		}
+	}
+	return projectedMatches, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetProjectedMatches(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.LoadTestMatch()
+	projectedMatches, err := arena.GetProjectedMatches(time.Now())
+	assert.Nil(t, err)
+	assert.Empty(t, projectedMatches)
+
+	now := time.Now()
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Time: now.Add(-5 * time.Minute),
+			Red1: 254, Blue1: 1114},
+	)
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Time: now.Add(6 * time.Minute),
+			Red1: 1678, Red2: 846, Blue3: 971},
+	)
+	arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Time: now.Add(60 * time.Minute),
+			Red1: 148},
+	)
+	matches, _ := arena.Database.GetMatchesByType(model.Qualification, false)
+	arena.CurrentMatch = &matches[0]
+	arena.MatchState = PreMatch
+
+	projectedMatches, err = arena.GetProjectedMatches(now)
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(projectedMatches)) {
+		// The event is running five minutes late, so the current match is past its queueing time.
+		assert.Equal(t, "Q1", projectedMatches[0].ShortName)
+		assert.Equal(t, matches[0].Time, projectedMatches[0].ScheduledTime)
+		assert.Equal(t, []QueueingTeam{{254, "R1", QueueOverdue}, {1114, "B1", QueueOverdue}},
+			projectedMatches[0].Teams)
+
+		assert.Equal(t, "Q2", projectedMatches[1].ShortName)
+		assert.InDelta(t, 11*60, projectedMatches[1].ProjectedStartTime.Sub(now).Seconds(), 1)
+		assert.InDelta(t, 60, projectedMatches[1].QueueTime.Sub(now).Seconds(), 1)
+		assert.Equal(t, []QueueingTeam{{1678, "R1", QueueNotYetDue}, {846, "R2", QueueNotYetDue},
+			{971, "B3", QueueNotYetDue}}, projectedMatches[1].Teams)
+
+		// The lateness shouldn't carry over past a significant break in the schedule.
+		assert.Equal(t, matches[2].Time, projectedMatches[2].ProjectedStartTime)
+		assert.Equal(t, QueueNotYetDue, projectedMatches[2].Teams[0].Status)
+	}
+
+	projectedMatches, err = arena.GetProjectedMatches(now.Add(2 * time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, QueueDue, projectedMatches[1].Teams[0].Status)
+
+	// Check that a longer lead time calls teams earlier.
+	arena.EventSettings.QueueLeadTimeMin = 15
+	projectedMatches, err = arena.GetProjectedMatches(now)
+	assert.Nil(t, err)
+	assert.Equal(t, QueueOverdue, projectedMatches[1].Teams[0].Status)
+
+	// Check that completed matches are skipped.
+	matches[0].Status = game.RedWonMatch
+	arena.Database.UpdateMatch(&matches[0])
+	arena.CurrentMatch = &matches[1]
+	projectedMatches, err = arena.GetProjectedMatches(now)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(projectedMatches)) {
+		assert.Equal(t, "Q2", projectedMatches[0].ShortName)
+	}
+}
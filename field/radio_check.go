@@ -0,0 +1,71 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for remotely verifying that each robot radio is programmed for and connected to its team's field network.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+)
+
+// Checks, during the pre-match period, that the field access point is serving each station's team SSID and that the
+// robot radio in the station is connected to it, recording any discrepancy on the station so that it can be fixed
+// before the match starts.
+func (arena *Arena) checkRadioPairings() {
+	for _, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+		allianceStation := arena.AllianceStations[station]
+		issue := ""
+		if arena.MatchState == PreMatch && arena.EventSettings.NetworkSecurityEnabled &&
+			arena.accessPoint.Status == "ACTIVE" {
+			issue = allianceStation.radioPairingIssue()
+		}
+		if issue != allianceStation.RadioPairingIssue {
+			if issue != "" {
+				logging.Network.Warn("Radio pairing issue.", "station", station, "issue", issue)
+			}
+			allianceStation.RadioPairingIssue = issue
+		}
+	}
+}
+
+// Updates the robot radio MAC address expected from the given team, if it is in the current match, after it has been
+// re-recorded at check-in.
+func (arena *Arena) UpdateExpectedRadioMacAddress(teamId int, macAddress string) {
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.Team != nil && allianceStation.Team.Id == teamId {
+			allianceStation.ExpectedRadioMacAddress = macAddress
+		}
+	}
+}
+
+// Returns a description of what is wrong with the pairing between the station's team and the field network, or the
+// empty string if there is nothing wrong.
+func (allianceStation *AllianceStation) radioPairingIssue() string {
+	if allianceStation.Team == nil || allianceStation.Bypass {
+		return ""
+	}
+
+	teamId := allianceStation.Team.Id
+	wifiStatus := allianceStation.WifiStatus
+	if wifiStatus.TeamId == 0 {
+		return fmt.Sprintf("Access point has no SSID configured for team %d.", teamId)
+	}
+	if wifiStatus.TeamId != teamId {
+		return fmt.Sprintf("Access point is serving SSID %d instead of %d.", wifiStatus.TeamId, teamId)
+	}
+	if expected := allianceStation.ExpectedRadioMacAddress; expected != "" && wifiStatus.RadioLinked &&
+		wifiStatus.RadioMacAddress != "" && wifiStatus.RadioMacAddress != expected {
+		return fmt.Sprintf(
+			"Radio %s is connected to SSID %d instead of the %s registered for the team.",
+			wifiStatus.RadioMacAddress,
+			teamId,
+			expected,
+		)
+	}
+	if dsConn := allianceStation.DsConn; dsConn != nil && dsConn.RadioLinked && !wifiStatus.RadioLinked {
+		return fmt.Sprintf("Robot radio is reachable but not connected to SSID %d; check its programming.", teamId)
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/network"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckRadioPairings(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Nil(t, arena.assignTeam(1114, "B2"))
+	r1 := arena.AllianceStations["R1"]
+	b2 := arena.AllianceStations["B2"]
+
+	// Nothing should be flagged unless the access point is managing the team networks.
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+	arena.EventSettings.NetworkSecurityEnabled = true
+	arena.accessPoint.Status = "CONFIGURING"
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+
+	arena.accessPoint.Status = "ACTIVE"
+	arena.checkRadioPairings()
+	assert.Equal(t, "Access point has no SSID configured for team 254.", r1.RadioPairingIssue)
+	assert.Equal(t, "Access point has no SSID configured for team 1114.", b2.RadioPairingIssue)
+	assert.Equal(t, "", arena.AllianceStations["R2"].RadioPairingIssue)
+
+	r1.WifiStatus.TeamId = 1678
+	b2.WifiStatus.TeamId = 1114
+	arena.checkRadioPairings()
+	assert.Equal(t, "Access point is serving SSID 1678 instead of 254.", r1.RadioPairingIssue)
+	assert.Equal(t, "", b2.RadioPairingIssue)
+
+	// A robot radio that the driver station can reach but that isn't on the field network is on the wrong one.
+	r1.WifiStatus.TeamId = 254
+	r1.DsConn = &DriverStationConnection{TeamId: 254, RadioLinked: true}
+	arena.checkRadioPairings()
+	assert.Equal(
+		t, "Robot radio is reachable but not connected to SSID 254; check its programming.", r1.RadioPairingIssue,
+	)
+	r1.WifiStatus.RadioLinked = true
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+
+	// Bypassed stations and stations outside of the pre-match period aren't checked.
+	r1.WifiStatus.TeamId = 0
+	r1.Bypass = true
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+	r1.Bypass = false
+	arena.MatchState = AutoPeriod
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+	stations := arena.GenerateFieldMonitorStations()
+	assert.Equal(t, "", stations[0].RadioPairingIssue)
+	arena.MatchState = PreMatch
+	arena.checkRadioPairings()
+	stations = arena.GenerateFieldMonitorStations()
+	assert.Equal(t, "Access point has no SSID configured for team 254.", stations[0].RadioPairingIssue)
+}
+
+func TestCheckRadioPairingsWithRegisteredMacAddress(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 254, RadioMacAddress: "00:80:2f:00:02:54"})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Nil(t, arena.assignTeam(1114, "R2"))
+	r1 := arena.AllianceStations["R1"]
+	r2 := arena.AllianceStations["R2"]
+	assert.Equal(t, "00:80:2f:00:02:54", r1.ExpectedRadioMacAddress)
+	assert.Equal(t, "", r2.ExpectedRadioMacAddress)
+	arena.EventSettings.NetworkSecurityEnabled = true
+	arena.accessPoint.Status = "ACTIVE"
+	r1.WifiStatus = network.TeamWifiStatus{TeamId: 254, RadioLinked: true, RadioMacAddress: "00:80:2f:00:02:54"}
+	r2.WifiStatus = network.TeamWifiStatus{TeamId: 1114, RadioLinked: true, RadioMacAddress: "00:80:2f:00:11:14"}
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+	assert.Equal(t, "", r2.RadioPairingIssue)
+
+	// A different radio on the team's SSID is flagged, as long as a radio was registered for the team.
+	r1.WifiStatus.RadioMacAddress = "00:80:2f:00:16:78"
+	arena.checkRadioPairings()
+	assert.Equal(
+		t,
+		"Radio 00:80:2f:00:16:78 is connected to SSID 254 instead of the 00:80:2f:00:02:54 registered for the team.",
+		r1.RadioPairingIssue,
+	)
+	stations := arena.GenerateFieldMonitorStations()
+	assert.Equal(t, "00:80:2f:00:16:78", stations[0].WifiRadioMacAddress)
+	assert.Equal(t, "00:80:2f:00:02:54", stations[0].ExpectedRadioMacAddress)
+
+	// Re-registering the radio at check-in takes effect without reloading the match.
+	arena.UpdateExpectedRadioMacAddress(254, "00:80:2f:00:16:78")
+	arena.checkRadioPairings()
+	assert.Equal(t, "", r1.RadioPairingIssue)
+
+	assert.Nil(t, arena.assignTeam(0, "R1"))
+	assert.Equal(t, "", r1.ExpectedRadioMacAddress)
+}
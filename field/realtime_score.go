@@ -10,10 +10,16 @@ import "github.com/Team254/cheesy-arena/game"
 type RealtimeScore struct {
 	CurrentScore              game.Score
 	Cards                     map[string]string
+	CardRules                 map[string]int // IDs of the rules for which each team's card was given, if known.
 	FoulsCommitted            bool
 	AmplifiedTimeRemainingSec int
+	ScoringHistory            game.ScoringHistory
+	FoulConflicts             []FoulConflict
+	MachineScored             game.ScoreElementFlags // Elements whose value was provided by the vision scoring system.
+	HumanScored               game.ScoreElementFlags // Elements set by a human scorer, which vision updates won't change.
+	nextFoulId                int
 }
 
 func NewRealtimeScore() *RealtimeScore {
-	return &RealtimeScore{Cards: make(map[string]string)}
+	return &RealtimeScore{Cards: make(map[string]string), CardRules: make(map[string]int)}
 }
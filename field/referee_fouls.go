@@ -0,0 +1,258 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for merging the foul inputs from multiple simultaneously connected referee panels.
+
+package field
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"strconv"
+	"strings"
+)
+
+// Identifies a referee panel and the rules it is responsible for.
+type RefereePanel struct {
+	Position     string   // One of "head", "near", or "far".
+	RulePrefixes []string // Rule number prefixes the panel may assign; empty means all rules.
+}
+
+// Represents an edit from a referee panel that was rejected because another panel changed the foul first.
+type FoulConflict struct {
+	FoulId   int
+	Position string
+	Message  string
+}
+
+// Returns a new referee panel for the given position, scoped to the given comma-separated list of rule number
+// prefixes.
+func NewRefereePanel(position, rulePrefixes string) (*RefereePanel, error) {
+	if position == "" {
+		position = "head"
+	}
+	if position != "head" && position != "near" && position != "far" {
+		return nil, fmt.Errorf("Invalid referee position '%s'.", position)
+	}
+	panel := &RefereePanel{Position: position}
+	for _, prefix := range strings.Split(rulePrefixes, ",") {
+		if prefix = strings.ToUpper(strings.TrimSpace(prefix)); prefix != "" {
+			panel.RulePrefixes = append(panel.RulePrefixes, prefix)
+		}
+	}
+	return panel, nil
+}
+
+// Returns true if the panel belongs to the head referee, who alone may assign and confirm cards and commit the match.
+func (panel *RefereePanel) IsHeadReferee() bool {
+	return panel.Position == "head"
+}
+
+// Returns true if the panel is allowed to assign fouls for the given rule.
+func (panel *RefereePanel) CanAssignRule(rule *game.Rule) bool {
+	if len(panel.RulePrefixes) == 0 || rule == nil {
+		return true
+	}
+	for _, prefix := range panel.RulePrefixes {
+		if strings.HasPrefix(rule.RuleNumber, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds a new foul to the given alliance's list on behalf of the given referee panel.
+func (arena *Arena) AddFoul(panel *RefereePanel, alliance string, isTechnical bool) game.Foul {
+	arena.foulMutex.Lock()
+	defer arena.foulMutex.Unlock()
+
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	realtimeScore.nextFoulId++
+	foul := game.Foul{IsTechnical: isTechnical, FoulId: realtimeScore.nextFoulId}
+	realtimeScore.CurrentScore.Fouls = append(realtimeScore.CurrentScore.Fouls, foul)
+	arena.RecordTimelineEvent(
+		model.TimelineFoul, alliance, fmt.Sprintf("Added %s (%s referee)", describeFoul(&foul), panel.Position),
+	)
+	if arena.MatchState != PreMatch && arena.MatchState != PostMatch {
+		arena.playSound("foul")
+	}
+	return foul
+}
+
+// Applies the given edit to the foul having the given ID, provided that it is still at the revision the referee panel
+// last saw. Returns an error without making any changes if the edit conflicts with one from another panel or is
+// outside of the panel's scope.
+func (arena *Arena) EditFoul(
+	panel *RefereePanel, alliance string, foulId, revision int, edit func(foul *game.Foul) error,
+) error {
+	arena.foulMutex.Lock()
+	defer arena.foulMutex.Unlock()
+
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	index, err := arena.checkFoulEdit(panel, realtimeScore, foulId, revision)
+	if err != nil {
+		return err
+	}
+	foul := realtimeScore.CurrentScore.Fouls[index]
+	if err = edit(&foul); err != nil {
+		return err
+	}
+	if !panel.CanAssignRule(foul.Rule()) {
+		return fmt.Errorf("Rule %s is not assigned to the %s referee.", foul.Rule().RuleNumber, panel.Position)
+	}
+	foul.Revision++
+	oldFoul := realtimeScore.CurrentScore.Fouls[index]
+	realtimeScore.CurrentScore.Fouls[index] = foul
+	arena.RecordTimelineEvent(
+		model.TimelineFoul, alliance, fmt.Sprintf("Updated %s (%s referee)", describeFoul(&foul), panel.Position),
+	)
+
+	// Give the offending team any card that the rule carries, but only when the foul first becomes attributed to that
+	// team and rule so that unrelated edits don't escalate the card again.
+	if card := foul.Card(); card != "" && foul.TeamId != 0 &&
+		(foul.TeamId != oldFoul.TeamId || foul.RuleId != oldFoul.RuleId) {
+		arena.setCard(alliance, foul.TeamId, card, foul.RuleId)
+	}
+	return nil
+}
+
+// Gives the given card to the given team, or clears its card if the card is blank. A yellow card given to a team that
+// is already carrying one from a previous match is escalated to a red card. In playoffs the card is applied to the
+// whole alliance instead.
+func (arena *Arena) SetCard(alliance string, teamId int, card string) {
+	arena.foulMutex.Lock()
+	defer arena.foulMutex.Unlock()
+
+	if card == "yellow" {
+		card = game.EscalateCard("", arena.teamHasYellowCard(teamId), card)
+	}
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	for _, id := range arena.getCardTeamIds(alliance, teamId) {
+		realtimeScore.Cards[strconv.Itoa(id)] = card
+		if card == "" {
+			delete(realtimeScore.CardRules, strconv.Itoa(id))
+		}
+		arena.recordCardTimelineEvent(alliance, id, card, 0)
+	}
+}
+
+// Gives the given card to the given team for violating the given rule on top of any card it already holds, escalating
+// it as necessary.
+func (arena *Arena) setCard(alliance string, teamId int, card string, ruleId int) {
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	newCard := game.EscalateCard(realtimeScore.Cards[strconv.Itoa(teamId)], arena.teamHasYellowCard(teamId), card)
+	for _, id := range arena.getCardTeamIds(alliance, teamId) {
+		realtimeScore.Cards[strconv.Itoa(id)] = newCard
+		realtimeScore.CardRules[strconv.Itoa(id)] = ruleId
+		arena.recordCardTimelineEvent(alliance, id, newCard, ruleId)
+	}
+}
+
+// Returns true if the given team is carrying a yellow card from a previous match.
+func (arena *Arena) teamHasYellowCard(teamId int) bool {
+	if teamStatus, _ := arena.Database.GetTeamStatus(teamId); teamStatus != nil {
+		return teamStatus.YellowCard
+	}
+	return false
+}
+
+func (arena *Arena) recordCardTimelineEvent(alliance string, teamId int, card string, ruleId int) {
+	description := fmt.Sprintf("Card cleared for team %d", teamId)
+	if card != "" {
+		description = fmt.Sprintf("%s%s card given to team %d", strings.ToUpper(card[:1]), card[1:], teamId)
+		if rule := game.GetRuleById(ruleId); rule != nil {
+			description += " for " + rule.RuleNumber
+		}
+	}
+	arena.RecordTimelineEvent(model.TimelineCard, alliance, description)
+}
+
+// Returns a human-readable summary of the given foul for use in the match timeline.
+func describeFoul(foul *game.Foul) string {
+	description := fmt.Sprintf("foul %d", foul.FoulId)
+	if foul.IsTechnical {
+		description = "tech " + description
+	}
+	if foul.TeamId != 0 {
+		description += fmt.Sprintf(" by team %d", foul.TeamId)
+	}
+	if rule := foul.Rule(); rule != nil {
+		description += " for " + rule.RuleNumber
+	}
+	return description
+}
+
+// Returns the IDs of the teams that a card given to the given team applies to.
+func (arena *Arena) getCardTeamIds(alliance string, teamId int) []int {
+	if arena.CurrentMatch.Type != model.Playoff {
+		return []int{teamId}
+	}
+	if alliance == "red" {
+		return []int{arena.CurrentMatch.Red1, arena.CurrentMatch.Red2, arena.CurrentMatch.Red3}
+	}
+	return []int{arena.CurrentMatch.Blue1, arena.CurrentMatch.Blue2, arena.CurrentMatch.Blue3}
+}
+
+// Removes the foul having the given ID, provided that it is still at the revision the referee panel last saw.
+func (arena *Arena) DeleteFoul(panel *RefereePanel, alliance string, foulId, revision int) error {
+	arena.foulMutex.Lock()
+	defer arena.foulMutex.Unlock()
+
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	index, err := arena.checkFoulEdit(panel, realtimeScore, foulId, revision)
+	if err != nil {
+		return err
+	}
+	fouls := realtimeScore.CurrentScore.Fouls
+	arena.RecordTimelineEvent(
+		model.TimelineFoul, alliance, fmt.Sprintf("Deleted %s (%s referee)", describeFoul(&fouls[index]), panel.Position),
+	)
+	realtimeScore.CurrentScore.Fouls = append(fouls[:index], fouls[index+1:]...)
+	return nil
+}
+
+// Returns the index of the foul to be edited, or an error if the edit is not allowed. Conflicting edits are recorded
+// so that the head referee can review them.
+func (arena *Arena) checkFoulEdit(
+	panel *RefereePanel, realtimeScore *RealtimeScore, foulId, revision int,
+) (int, error) {
+	for i, foul := range realtimeScore.CurrentScore.Fouls {
+		if foul.FoulId != foulId {
+			continue
+		}
+		if foul.Revision != revision {
+			return -1, realtimeScore.addFoulConflict(
+				panel, foulId, "was changed from another referee panel before this edit was received",
+			)
+		}
+		if !panel.CanAssignRule(foul.Rule()) {
+			return -1, fmt.Errorf(
+				"Foul %d is for rule %s, which is not assigned to the %s referee.",
+				foulId,
+				foul.Rule().RuleNumber,
+				panel.Position,
+			)
+		}
+		return i, nil
+	}
+	return -1, realtimeScore.addFoulConflict(panel, foulId, "was deleted from another referee panel")
+}
+
+func (realtimeScore *RealtimeScore) addFoulConflict(panel *RefereePanel, foulId int, reason string) error {
+	conflict := FoulConflict{
+		FoulId:   foulId,
+		Position: panel.Position,
+		Message:  fmt.Sprintf("Foul %d %s; the %s referee's edit was not applied.", foulId, reason, panel.Position),
+	}
+	realtimeScore.FoulConflicts = append(realtimeScore.FoulConflicts, conflict)
+	return errors.New(conflict.Message)
+}
+
+func (arena *Arena) getAllianceRealtimeScore(alliance string) *RealtimeScore {
+	if alliance == "red" {
+		return arena.RedRealtimeScore
+	}
+	return arena.BlueRealtimeScore
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewRefereePanel(t *testing.T) {
+	panel, err := NewRefereePanel("", "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "head", panel.Position)
+		assert.True(t, panel.CanAssignRule(game.GetRuleById(1)))
+	}
+	panel, err = NewRefereePanel("near", " g40, G41 ,")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"G40", "G41"}, panel.RulePrefixes)
+		assert.True(t, panel.CanAssignRule(game.GetRuleById(4)))  // G401
+		assert.True(t, panel.CanAssignRule(game.GetRuleById(13))) // G410
+		assert.False(t, panel.CanAssignRule(game.GetRuleById(1))) // G211
+		assert.False(t, panel.CanAssignRule(game.GetRuleById(3))) // G301
+		assert.True(t, panel.CanAssignRule(nil))
+	}
+	_, err = NewRefereePanel("blorpy", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid referee position 'blorpy'.", err.Error())
+	}
+}
+
+func TestArenaFoulMerging(t *testing.T) {
+	arena := setupTestArena(t)
+	nearPanel, _ := NewRefereePanel("near", "G40")
+	farPanel, _ := NewRefereePanel("far", "")
+
+	foul := arena.AddFoul(nearPanel, "blue", true)
+	assert.Equal(t, 1, foul.FoulId)
+	foul = arena.AddFoul(farPanel, "blue", false)
+	assert.Equal(t, 2, foul.FoulId)
+	foul = arena.AddFoul(farPanel, "red", false)
+	assert.Equal(t, 1, foul.FoulId)
+
+	setTeam := func(foul *game.Foul) error {
+		foul.TeamId = 254
+		return nil
+	}
+	assert.Nil(t, arena.EditFoul(farPanel, "blue", 1, 0, setTeam))
+	assert.Equal(t, 254, arena.BlueRealtimeScore.CurrentScore.Fouls[0].TeamId)
+	assert.Equal(t, 1, arena.BlueRealtimeScore.CurrentScore.Fouls[0].Revision)
+
+	// A second edit based on the same revision should be rejected and recorded.
+	err := arena.EditFoul(nearPanel, "blue", 1, 0, func(foul *game.Foul) error {
+		foul.TeamId = 1114
+		return nil
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "was changed from another referee panel")
+	}
+	assert.Equal(t, 254, arena.BlueRealtimeScore.CurrentScore.Fouls[0].TeamId)
+	assert.Equal(t, []FoulConflict{{1, "near", err.Error()}}, arena.BlueRealtimeScore.FoulConflicts)
+
+	// Edits to a foul that has been deleted should also be flagged.
+	assert.Nil(t, arena.DeleteFoul(farPanel, "blue", 2, 0))
+	err = arena.EditFoul(nearPanel, "blue", 2, 0, setTeam)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "was deleted from another referee panel")
+	}
+	assert.Equal(t, 2, len(arena.BlueRealtimeScore.FoulConflicts))
+
+	// Panels should not be able to assign or edit fouls outside of their rules.
+	err = arena.EditFoul(nearPanel, "red", 1, 0, func(foul *game.Foul) error {
+		foul.RuleId = 22
+		return nil
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Rule G418 is not assigned to the near referee.", err.Error())
+	}
+	assert.Equal(t, 0, arena.RedRealtimeScore.CurrentScore.Fouls[0].RuleId)
+	assert.Nil(t, arena.EditFoul(farPanel, "red", 1, 0, func(foul *game.Foul) error {
+		foul.RuleId = 22
+		return nil
+	}))
+	err = arena.DeleteFoul(nearPanel, "red", 1, 1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Foul 1 is for rule G418, which is not assigned to the near referee.", err.Error())
+	}
+	assert.Equal(t, 1, len(arena.RedRealtimeScore.CurrentScore.Fouls))
+	assert.Empty(t, arena.RedRealtimeScore.FoulConflicts)
+}
+
+func TestArenaFoulCards(t *testing.T) {
+	arena := setupTestArena(t)
+	panel, _ := NewRefereePanel("head", "")
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 1114, YellowCard: true})
+	arena.CurrentMatch = &model.Match{Type: model.Qualification, Red1: 254, Red2: 1114, Red3: 2056}
+	assignRule := func(ruleId int) func(foul *game.Foul) error {
+		return func(foul *game.Foul) error {
+			return foul.AssignRule(ruleId)
+		}
+	}
+	setTeam := func(teamId int) func(foul *game.Foul) error {
+		return func(foul *game.Foul) error {
+			foul.TeamId = teamId
+			return nil
+		}
+	}
+
+	// A foul for a rule that carries no card shouldn't affect the team's card.
+	arena.AddFoul(panel, "red", false)
+	assert.Nil(t, arena.EditFoul(panel, "red", 1, 0, setTeam(254)))
+	assert.Nil(t, arena.EditFoul(panel, "red", 1, 1, assignRule(1)))
+	assert.Empty(t, arena.RedRealtimeScore.Cards)
+
+	// The card should be given once the foul is attributed to both a team and a carding rule.
+	arena.AddFoul(panel, "red", true)
+	assert.Nil(t, arena.EditFoul(panel, "red", 2, 0, assignRule(22)))
+	assert.Empty(t, arena.RedRealtimeScore.Cards)
+	assert.Nil(t, arena.EditFoul(panel, "red", 2, 1, setTeam(254)))
+	assert.Equal(t, map[string]string{"254": "yellow"}, arena.RedRealtimeScore.Cards)
+
+	// Toggling the team away and back shouldn't clear the card, but a second carding foul should escalate it.
+	assert.Nil(t, arena.EditFoul(panel, "red", 2, 2, setTeam(0)))
+	assert.Equal(t, map[string]string{"254": "yellow"}, arena.RedRealtimeScore.Cards)
+	arena.AddFoul(panel, "red", true)
+	assert.Nil(t, arena.EditFoul(panel, "red", 3, 0, setTeam(254)))
+	assert.Nil(t, arena.EditFoul(panel, "red", 3, 1, assignRule(23)))
+	assert.Equal(t, map[string]string{"254": "red"}, arena.RedRealtimeScore.Cards)
+	assert.Equal(t, map[string]int{"254": 23}, arena.RedRealtimeScore.CardRules)
+
+	// A team carrying a yellow card from a previous match should go straight to red.
+	arena.AddFoul(panel, "red", true)
+	assert.Nil(t, arena.EditFoul(panel, "red", 4, 0, setTeam(1114)))
+	assert.Nil(t, arena.EditFoul(panel, "red", 4, 1, assignRule(19)))
+	assert.Equal(t, "red", arena.RedRealtimeScore.Cards["1114"])
+	assert.NotContains(t, arena.RedRealtimeScore.Cards, "2056")
+
+	// A yellow card given directly by the head referee should also be escalated for a team carrying one, but the head
+	// referee should still be able to override or clear any card.
+	arena.SetCard("red", 1114, "")
+	assert.Equal(t, "", arena.RedRealtimeScore.Cards["1114"])
+	arena.SetCard("red", 1114, "yellow")
+	assert.Equal(t, "red", arena.RedRealtimeScore.Cards["1114"])
+	arena.SetCard("red", 2056, "yellow")
+	assert.Equal(t, "yellow", arena.RedRealtimeScore.Cards["2056"])
+	arena.SetCard("red", 254, "yellow")
+	assert.Equal(t, "yellow", arena.RedRealtimeScore.Cards["254"])
+
+	// Cards should apply to the whole alliance in playoffs.
+	arena.CurrentMatch.Type = model.Playoff
+	arena.CurrentMatch.Blue1, arena.CurrentMatch.Blue2, arena.CurrentMatch.Blue3 = 1, 2, 3
+	arena.SetCard("blue", 2, "yellow")
+	assert.Equal(t, map[string]string{"1": "yellow", "2": "yellow", "3": "yellow"}, arena.BlueRealtimeScore.Cards)
+	arena.AddFoul(panel, "blue", true)
+	assert.Nil(t, arena.EditFoul(panel, "blue", 1, 0, setTeam(2)))
+	assert.Nil(t, arena.EditFoul(panel, "blue", 1, 1, assignRule(22)))
+	assert.Equal(t, "red", arena.BlueRealtimeScore.Cards["1"])
+	assert.Equal(t, "red", arena.BlueRealtimeScore.Cards["3"])
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for marking instant replay clips in an external replay system and flagging them on the audience display.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"math"
+	"time"
+)
+
+// Tells the replay system to mark a clip of the configured length leading up to now, records it against the video
+// stream, and shows the replay banner on the audience display for as long as the clip plays back if so configured.
+func (arena *Arena) MarkReplay() error {
+	if !arena.EventSettings.ReplayEnabled {
+		return fmt.Errorf("the replay system integration is not enabled")
+	}
+	now := time.Now()
+	durationSec := arena.EventSettings.ReplayClipDurationSec
+	arena.recordVideoMarker(model.VideoMarkerReplay, now)
+
+	// Send the command asynchronously so that an unreachable replay system can't hold up the scorekeeper.
+	replayClient := arena.ReplayClient
+	matchName := arena.CurrentMatch.ShortName
+	go func() {
+		if err := replayClient.MarkReplay(now, durationSec, matchName); err != nil {
+			logging.Partner.Error("Failed to mark replay.", "match", matchName, "error", err)
+		}
+	}()
+
+	if arena.EventSettings.ReplayBannerEnabled {
+		arena.replayBannerEndTime = now.Add(time.Duration(durationSec) * time.Second)
+		arena.ReplayNotifier.Notify()
+	}
+	return nil
+}
+
+func (arena *Arena) generateReplayMessage() any {
+	return &struct {
+		MatchName          string
+		BannerRemainingSec float64
+	}{arena.CurrentMatch.ShortName, math.Max(time.Until(arena.replayBannerEndTime).Seconds(), 0)}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMarkReplay(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Mock the replay system.
+	commands := make(chan partner.ReplayCommand, 10)
+	replayServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var command partner.ReplayCommand
+		json.Unmarshal(body, &command)
+		commands <- command
+	}))
+	defer replayServer.Close()
+
+	err := arena.MarkReplay()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the replay system integration is not enabled", err.Error())
+	}
+
+	arena.EventSettings.ReplayEnabled = true
+	arena.EventSettings.ReplayAddress = replayServer.URL
+	arena.EventSettings.ReplayClipDurationSec = 12
+	arena.ReplayClient = partner.NewReplayClient(replayServer.URL)
+	match := model.Match{Type: model.Qualification, ShortName: "Q7"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Nil(t, arena.MarkReplay())
+	select {
+	case command := <-commands:
+		assert.Equal(t, "markReplay", command.Command)
+		assert.Equal(t, 12, command.DurationSec)
+		assert.Equal(t, "Q7", command.Match)
+		assert.InDelta(t, time.Now().UnixMilli(), command.Timestamp, 1000)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timed out waiting for replay command")
+	}
+	videoMarker, _ := arena.Database.GetLatestVideoMarker(model.VideoMarkerReplay)
+	if assert.NotNil(t, videoMarker) {
+		assert.Equal(t, match.Id, videoMarker.MatchId)
+	}
+
+	// The banner is only shown if enabled.
+	message := arena.generateReplayMessage().(*struct {
+		MatchName          string
+		BannerRemainingSec float64
+	})
+	assert.Equal(t, 0.0, message.BannerRemainingSec)
+	arena.EventSettings.ReplayBannerEnabled = true
+	assert.Nil(t, arena.MarkReplay())
+	message = arena.generateReplayMessage().(*struct {
+		MatchName          string
+		BannerRemainingSec float64
+	})
+	assert.Equal(t, "Q7", message.MatchName)
+	assert.InDelta(t, 12, message.BannerRemainingSec, 0.5)
+}
@@ -0,0 +1,266 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Scripted stand-ins for driver stations, robots and field scoring inputs, for running complete fake matches when
+// rehearsing the A/V production or training volunteers without any robots on the field.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Odds and timing used to generate the scripted actions of the simulated robots.
+const (
+	simulatedLeaveProbability     = 0.85
+	simulatedAmpNoteProbability   = 0.35
+	simulatedCoopProbability      = 0.5
+	simulatedMinCycleSec          = 8
+	simulatedMaxCycleSec          = 15
+	simulatedEndgameLeadTimeSec   = 2
+	simulatedMaxAutoNotesPerRobot = 2
+)
+
+type simulatedActionType int
+
+const (
+	simulatedLeave simulatedActionType = iota
+	simulatedAmpNote
+	simulatedSpeakerNote
+	simulatedAmplify
+	simulatedCoop
+	simulatedEndgame
+)
+
+// Represents a single scripted action by one of an alliance's simulated robots.
+type simulatedAction struct {
+	matchTimeSec  float64
+	actionType    simulatedActionType
+	robot         int
+	endgameStatus game.EndgameStatus
+}
+
+// Tracks the progress of an alliance's simulated robots through their script for the current match.
+type simulatedAlliance struct {
+	actions          []simulatedAction
+	nextAction       int
+	ampNoteCount     int
+	speakerNoteCount int
+}
+
+// Holds the scripts being followed by both alliances for the match in progress.
+type robotSimulation struct {
+	red  *simulatedAlliance
+	blue *simulatedAlliance
+}
+
+// Turns the simulation of driver stations, robots and scoring inputs on or off.
+func (arena *Arena) SetRobotSimulationEnabled(enabled bool) error {
+	if enabled == arena.RobotSimulationEnabled {
+		return nil
+	}
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot change robot simulation while there is a match in progress or with results pending")
+	}
+	if enabled && arena.Plc.IsEnabled() {
+		return fmt.Errorf("cannot simulate robots while the field PLC is enabled")
+	}
+
+	arena.RobotSimulationEnabled = enabled
+	arena.robotSimulation = nil
+	if !enabled {
+		// Drop the fake driver station connections so that the stations show as disconnected again.
+		for _, allianceStation := range arena.AllianceStations {
+			if allianceStation.DsConn != nil && allianceStation.DsConn.simulated {
+				allianceStation.DsConn = nil
+			}
+		}
+	}
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Advances the simulated driver stations and scoring inputs, if the simulation is enabled.
+func (arena *Arena) updateRobotSimulation(matchTimeSec float64) {
+	if !arena.RobotSimulationEnabled {
+		return
+	}
+
+	arena.simulateDriverStations()
+
+	switch arena.MatchState {
+	case PreMatch:
+		arena.robotSimulation = nil
+	case WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod:
+		if arena.robotSimulation == nil {
+			arena.robotSimulation = &robotSimulation{
+				red:  newSimulatedAlliance(arena.simulationRandom(), arena.EventSettings.TeamsPerAlliance),
+				blue: newSimulatedAlliance(arena.simulationRandom(), arena.EventSettings.TeamsPerAlliance),
+			}
+		}
+		redChanged := arena.robotSimulation.red.update(arena.RedRealtimeScore, arena.MatchStartTime, matchTimeSec)
+		blueChanged := arena.robotSimulation.blue.update(arena.BlueRealtimeScore, arena.MatchStartTime, matchTimeSec)
+		if redChanged || blueChanged {
+			arena.RealtimeScoreNotifier.Notify()
+		}
+	case PostMatch:
+		// Stand in for the referee so that the match can be committed straight away.
+		if !arena.RedRealtimeScore.FoulsCommitted || !arena.BlueRealtimeScore.FoulsCommitted {
+			arena.RedRealtimeScore.FoulsCommitted = true
+			arena.BlueRealtimeScore.FoulsCommitted = true
+			arena.ScoringStatusNotifier.Notify()
+		}
+	}
+}
+
+// Gives each station that has a team but no real driver station a fake connection with a healthy robot link.
+func (arena *Arena) simulateDriverStations() {
+	for station, allianceStation := range arena.AllianceStations {
+		if allianceStation.Team == nil {
+			continue
+		}
+		dsConn := allianceStation.DsConn
+		if dsConn == nil {
+			dsConn = &DriverStationConnection{
+				TeamId: allianceStation.Team.Id, AllianceStation: station, simulated: true,
+			}
+			allianceStation.DsConn = dsConn
+		}
+		if !dsConn.simulated {
+			continue
+		}
+
+		now := time.Now()
+		dsConn.DsLinked = true
+		dsConn.RadioLinked = true
+		dsConn.RioLinked = true
+		dsConn.RobotLinked = true
+		dsConn.BatteryVoltage = 12.2 + arena.simulationRandom().Float64()*0.6
+		dsConn.DsRobotTripTimeMs = 2 + arena.simulationRandom().Intn(4)
+		dsConn.lastPacketTime = now
+		dsConn.lastRobotLinkedTime = now
+	}
+}
+
+// Returns the source of randomness for the simulation, creating it on first use.
+func (arena *Arena) simulationRandom() *rand.Rand {
+	if arena.simulationRand == nil {
+		arena.simulationRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return arena.simulationRand
+}
+
+// Generates a plausible script of scoring actions over the course of a match for an alliance with the given number of
+// robots.
+func newSimulatedAlliance(random *rand.Rand, numRobots int) *simulatedAlliance {
+	autoStartSec := float64(game.MatchTiming.WarmupDurationSec)
+	autoEndSec := game.GetDurationToAutoEnd().Seconds()
+	teleopStartSec := game.GetDurationToTeleopStart().Seconds()
+	teleopEndSec := game.GetDurationToTeleopEnd().Seconds()
+	randomTime := func(startSec, endSec float64) float64 {
+		return startSec + random.Float64()*(endSec-startSec)
+	}
+
+	var actions []simulatedAction
+	for robot := 0; robot < numRobots; robot++ {
+		// Autonomous period.
+		if random.Float64() < simulatedLeaveProbability {
+			actions = append(
+				actions, simulatedAction{matchTimeSec: randomTime(autoStartSec+1, autoStartSec+4), robot: robot},
+			)
+		}
+		for i := random.Intn(simulatedMaxAutoNotesPerRobot + 1); i > 0; i-- {
+			actionType := simulatedSpeakerNote
+			if random.Float64() < simulatedAmpNoteProbability/2 {
+				actionType = simulatedAmpNote
+			}
+			actions = append(
+				actions,
+				simulatedAction{matchTimeSec: randomTime(autoStartSec+2, autoEndSec), actionType: actionType, robot: robot},
+			)
+		}
+
+		// Teleoperated period; each robot cycles back and forth scoring a note at a time.
+		cycleTimeSec := teleopStartSec
+		for {
+			cycleTimeSec += randomTime(simulatedMinCycleSec, simulatedMaxCycleSec)
+			if cycleTimeSec >= teleopEndSec-simulatedEndgameLeadTimeSec {
+				break
+			}
+			actionType := simulatedSpeakerNote
+			if random.Float64() < simulatedAmpNoteProbability {
+				actionType = simulatedAmpNote
+			}
+			actions = append(actions, simulatedAction{matchTimeSec: cycleTimeSec, actionType: actionType, robot: robot})
+		}
+
+		// Endgame.
+		endgameStatus := game.EndgameStatus(random.Intn(int(game.EndgameStageRight) + 1))
+		actions = append(
+			actions,
+			simulatedAction{
+				matchTimeSec:  teleopEndSec - random.Float64()*simulatedEndgameLeadTimeSec,
+				actionType:    simulatedEndgame,
+				robot:         robot,
+				endgameStatus: endgameStatus,
+			},
+		)
+	}
+
+	// The human player presses the amplify button whenever there are enough notes banked, and maybe the co-op button.
+	for cycleTimeSec := teleopStartSec + 5; cycleTimeSec < teleopEndSec; cycleTimeSec += 5 {
+		actions = append(actions, simulatedAction{matchTimeSec: cycleTimeSec, actionType: simulatedAmplify})
+	}
+	if random.Float64() < simulatedCoopProbability {
+		actions = append(
+			actions,
+			simulatedAction{matchTimeSec: randomTime(teleopStartSec, teleopStartSec+30), actionType: simulatedCoop},
+		)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].matchTimeSec < actions[j].matchTimeSec
+	})
+	return &simulatedAlliance{actions: actions}
+}
+
+// Carries out any scripted actions that are due as of the given time into the match, in the same way that the PLC
+// inputs would be applied. Returns true if the score changed.
+func (alliance *simulatedAlliance) update(
+	realtimeScore *RealtimeScore, matchStartTime time.Time, matchTimeSec float64,
+) bool {
+	score := &realtimeScore.CurrentScore
+	oldScore := *score
+	amplifyButton := false
+	coopButton := false
+	for ; alliance.nextAction < len(alliance.actions); alliance.nextAction++ {
+		action := alliance.actions[alliance.nextAction]
+		if action.matchTimeSec > matchTimeSec {
+			break
+		}
+		switch action.actionType {
+		case simulatedLeave:
+			score.LeaveStatuses[action.robot] = true
+		case simulatedAmpNote:
+			alliance.ampNoteCount++
+		case simulatedSpeakerNote:
+			alliance.speakerNoteCount++
+		case simulatedAmplify:
+			amplifyButton = true
+		case simulatedCoop:
+			coopButton = true
+		case simulatedEndgame:
+			score.EndgameStatuses[action.robot] = action.endgameStatus
+		}
+	}
+
+	currentTime := matchStartTime.Add(time.Duration(matchTimeSec * float64(time.Second)))
+	score.AmpSpeaker.UpdateState(
+		alliance.ampNoteCount, alliance.speakerNoteCount, amplifyButton, coopButton, matchStartTime, currentTime,
+	)
+	return !score.Equals(&oldScore)
+}
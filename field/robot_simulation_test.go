@@ -0,0 +1,102 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRobotSimulation(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.simulationRand = rand.New(rand.NewSource(254))
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	match := model.Match{Type: model.Practice, Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.Update()
+	assert.NotNil(t, arena.checkCanStartMatch())
+
+	// The simulated driver stations should allow the match to start.
+	assert.Nil(t, arena.SetRobotSimulationEnabled(true))
+	arena.Update()
+	for _, allianceStation := range arena.AllianceStations {
+		if assert.NotNil(t, allianceStation.DsConn) {
+			assert.True(t, allianceStation.DsConn.simulated)
+			assert.True(t, allianceStation.DsConn.RobotLinked)
+			assert.Greater(t, allianceStation.DsConn.BatteryVoltage, 12.0)
+		}
+	}
+	assert.Nil(t, arena.checkCanStartMatch())
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.EqualError(
+		t,
+		arena.SetRobotSimulationEnabled(false),
+		"cannot change robot simulation while there is a match in progress or with results pending",
+	)
+
+	// The scores should build up over the course of the match.
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToAutoEnd() + time.Second)
+	arena.Update()
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	redAutoSummary := arena.RedRealtimeScore.CurrentScore.Summarize(&arena.BlueRealtimeScore.CurrentScore)
+	blueAutoSummary := arena.BlueRealtimeScore.CurrentScore.Summarize(&arena.RedRealtimeScore.CurrentScore)
+	assert.Greater(t, redAutoSummary.Score+blueAutoSummary.Score, 0)
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd() + time.Second)
+	arena.Update()
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd())
+	for arena.MatchState != PostMatch {
+		arena.Update()
+	}
+	redSummary := arena.RedRealtimeScore.CurrentScore.Summarize(&arena.BlueRealtimeScore.CurrentScore)
+	blueSummary := arena.BlueRealtimeScore.CurrentScore.Summarize(&arena.RedRealtimeScore.CurrentScore)
+	assert.Greater(t, redSummary.Score, redAutoSummary.Score)
+	assert.Greater(t, blueSummary.Score, blueAutoSummary.Score)
+	assert.Greater(t, arena.RedRealtimeScore.CurrentScore.AmpSpeaker.TeleopAmpNotes, 0)
+
+	// The referee's part should be taken care of after the match so that it can be committed.
+	assert.True(t, arena.RedRealtimeScore.FoulsCommitted)
+	assert.True(t, arena.BlueRealtimeScore.FoulsCommitted)
+
+	// Turning the simulation off should drop the fake connections.
+	assert.Nil(t, arena.ResetMatch())
+	arena.Update()
+	assert.Nil(t, arena.robotSimulation)
+	assert.Nil(t, arena.SetRobotSimulationEnabled(false))
+	for _, allianceStation := range arena.AllianceStations {
+		assert.Nil(t, allianceStation.DsConn)
+	}
+	arena.Update()
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+
+	// Real driver stations shouldn't be touched.
+	realDsConn := &DriverStationConnection{TeamId: 101, AllianceStation: "R1"}
+	arena.AllianceStations["R1"].DsConn = realDsConn
+	assert.Nil(t, arena.SetRobotSimulationEnabled(true))
+	arena.Update()
+	assert.Same(t, realDsConn, arena.AllianceStations["R1"].DsConn)
+	assert.False(t, realDsConn.RobotLinked)
+	assert.True(t, arena.AllianceStations["R2"].DsConn.simulated)
+}
+
+func TestRobotSimulationWithPlcEnabled(t *testing.T) {
+	arena := setupTestArena(t)
+	var plc FakePlc
+	plc.isEnabled = true
+	arena.Plc = &plc
+
+	assert.EqualError(t, arena.SetRobotSimulationEnabled(true), "cannot simulate robots while the field PLC is enabled")
+	assert.False(t, arena.RobotSimulationEnabled)
+}
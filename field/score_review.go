@@ -0,0 +1,106 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for holding a match's scores for review by the scorekeeper and head referee before they are committed.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+type ScoreReviewRole string
+
+const (
+	ScorekeeperRole ScoreReviewRole = "scorekeeper"
+	HeadRefereeRole ScoreReviewRole = "headReferee"
+)
+
+// Represents the review of the scores of a match that has ended but whose results have not yet been committed.
+type ScoreReview struct {
+	ProvisionalRedScore  game.Score
+	ProvisionalBlueScore game.Score
+	ProvisionalRedCards  map[string]string
+	ProvisionalBlueCards map[string]string
+	ScorekeeperApproved  bool
+	HeadRefereeApproved  bool
+}
+
+// Returns true if the scores of the current match are awaiting approval and should not yet be committed or shown.
+func (arena *Arena) ScoreReviewPending() bool {
+	review := arena.ScoreReview
+	return review != nil && !(review.ScorekeeperApproved && review.HeadRefereeApproved)
+}
+
+// Records the approval of the current match's scores by the given role.
+func (arena *Arena) ApproveScores(role ScoreReviewRole) error {
+	if arena.ScoreReview == nil {
+		return fmt.Errorf("there are no scores pending review")
+	}
+	switch role {
+	case ScorekeeperRole:
+		arena.ScoreReview.ScorekeeperApproved = true
+	case HeadRefereeRole:
+		arena.ScoreReview.HeadRefereeApproved = true
+	default:
+		return fmt.Errorf("invalid score review role '%s'", role)
+	}
+	arena.RecordTimelineEvent(model.TimelineScore, string(role), "Scores approved")
+	arena.ScoringStatusNotifier.Notify()
+	return nil
+}
+
+// Returns the differences between the provisional scores captured at the end of the match and the current, possibly
+// edited, scores.
+func (arena *Arena) ScoreReviewDifferences() []model.ScoreEditChange {
+	if arena.ScoreReview == nil {
+		return nil
+	}
+	provisionalResult := model.MatchResult{
+		RedScore:  &arena.ScoreReview.ProvisionalRedScore,
+		BlueScore: &arena.ScoreReview.ProvisionalBlueScore,
+		RedCards:  arena.ScoreReview.ProvisionalRedCards,
+		BlueCards: arena.ScoreReview.ProvisionalBlueCards,
+	}
+	editedResult := model.MatchResult{
+		RedScore:  &arena.RedRealtimeScore.CurrentScore,
+		BlueScore: &arena.BlueRealtimeScore.CurrentScore,
+		RedCards:  arena.RedRealtimeScore.Cards,
+		BlueCards: arena.BlueRealtimeScore.Cards,
+	}
+	return model.DiffMatchResults(&provisionalResult, &editedResult)
+}
+
+// Captures the provisional scores for review if the current match has just ended and review is required.
+func (arena *Arena) updateScoreReview() {
+	if arena.MatchState != PostMatch || arena.lastMatchState == PostMatch {
+		return
+	}
+	if !arena.EventSettings.ScoreReviewEnabled || arena.CurrentMatch.Type == model.Test {
+		return
+	}
+	arena.ScoreReview = &ScoreReview{
+		ProvisionalRedScore:  copyScore(&arena.RedRealtimeScore.CurrentScore),
+		ProvisionalBlueScore: copyScore(&arena.BlueRealtimeScore.CurrentScore),
+		ProvisionalRedCards:  copyCards(arena.RedRealtimeScore.Cards),
+		ProvisionalBlueCards: copyCards(arena.BlueRealtimeScore.Cards),
+	}
+	arena.ScoringStatusNotifier.Notify()
+}
+
+// Returns a copy of the given score that doesn't share its list of fouls.
+func copyScore(score *game.Score) game.Score {
+	scoreCopy := *score
+	scoreCopy.Fouls = append([]game.Foul(nil), score.Fouls...)
+	return scoreCopy
+}
+
+func copyCards(cards map[string]string) map[string]string {
+	cardsCopy := make(map[string]string, len(cards))
+	for teamId, card := range cards {
+		cardsCopy[teamId] = card
+	}
+	return cardsCopy
+}
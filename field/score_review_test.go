@@ -0,0 +1,62 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoreReview(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.ScoreReviewEnabled = true
+	match := model.Match{Type: model.Qualification}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.False(t, arena.ScoreReviewPending())
+	assert.NotNil(t, arena.ApproveScores(ScorekeeperRole))
+
+	// Ending the match captures the provisional scores.
+	arena.RedRealtimeScore.CurrentScore.LeaveStatuses[0] = true
+	arena.lastMatchState = TeleopPeriod
+	arena.MatchState = PostMatch
+	arena.updateScoreReview()
+	assert.True(t, arena.ScoreReviewPending())
+	assert.Empty(t, arena.ScoreReviewDifferences())
+
+	// The audience display is held back until both approvals are in.
+	arena.SetAudienceDisplayMode("score")
+	assert.NotEqual(t, "score", arena.AudienceDisplayMode)
+
+	arena.RedRealtimeScore.CurrentScore.LeaveStatuses[1] = true
+	assert.NotEmpty(t, arena.ScoreReviewDifferences())
+	assert.True(t, arena.ScoreReview.ProvisionalRedScore.LeaveStatuses[0])
+	assert.False(t, arena.ScoreReview.ProvisionalRedScore.LeaveStatuses[1])
+
+	assert.NotNil(t, arena.ApproveScores("announcer"))
+	assert.Nil(t, arena.ApproveScores(ScorekeeperRole))
+	assert.True(t, arena.ScoreReviewPending())
+	assert.Nil(t, arena.ApproveScores(HeadRefereeRole))
+	assert.False(t, arena.ScoreReviewPending())
+	arena.SetAudienceDisplayMode("score")
+	assert.Equal(t, "score", arena.AudienceDisplayMode)
+
+	// Loading the next match clears the review.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Nil(t, arena.ScoreReview)
+}
+
+func TestScoreReviewDisabled(t *testing.T) {
+	arena := setupTestArena(t)
+	match := model.Match{Type: model.Qualification}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.lastMatchState = TeleopPeriod
+	arena.MatchState = PostMatch
+	arena.updateScoreReview()
+	assert.Nil(t, arena.ScoreReview)
+	assert.False(t, arena.ScoreReviewPending())
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Logic for working out what to show on the venue scoreboard based on the state of the arena.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/scoreboard"
+)
+
+// Sends the current match clock and scores to the configured venue scoreboard controller, if any.
+func (arena *Arena) updateScoreboard() {
+	if !arena.Scoreboard.IsEnabled() {
+		return
+	}
+	if err := arena.Scoreboard.Update(arena.currentScoreboardState()); err != nil {
+		logging.Hardware.Error("Failed to send scoreboard packet.", "error", err)
+	}
+}
+
+// Returns the scoreboard representation of the current arena state.
+func (arena *Arena) currentScoreboardState() scoreboard.State {
+	state := scoreboard.State{MatchName: arena.CurrentMatch.ShortName, ClockSec: arena.countdownSec()}
+	switch arena.MatchState {
+	case AutoPeriod:
+		state.Period = 1
+	case PausePeriod, TeleopPeriod:
+		state.Period = 2
+	}
+	if arena.MatchState != PreMatch {
+		state.RedScore = arena.RedScoreSummary().Score
+		state.BlueScore = arena.BlueScoreSummary().Score
+	}
+	return state
+}
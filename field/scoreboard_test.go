@@ -0,0 +1,44 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/scoreboard"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCurrentScoreboardState(t *testing.T) {
+	arena := setupTestArena(t)
+	match := model.Match{Type: model.Qualification, ShortName: "Q3"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.RedRealtimeScore.CurrentScore.AmpSpeaker.AutoSpeakerNotes = 2
+
+	assert.Equal(
+		t,
+		scoreboard.State{MatchName: "Q3", ClockSec: game.MatchTiming.AutoDurationSec},
+		arena.currentScoreboardState(),
+	)
+
+	arena.MatchState = AutoPeriod
+	arena.MatchStartTime = time.Now().Add(-5 * time.Second)
+	state := arena.currentScoreboardState()
+	assert.Equal(t, 1, state.Period)
+	assert.Equal(t, game.MatchTiming.WarmupDurationSec+game.MatchTiming.AutoDurationSec-5, state.ClockSec)
+	assert.Equal(t, arena.RedScoreSummary().Score, state.RedScore)
+	assert.Greater(t, state.RedScore, 0)
+	assert.Equal(t, 0, state.BlueScore)
+
+	arena.MatchState = TeleopPeriod
+	assert.Equal(t, 2, arena.currentScoreboardState().Period)
+	arena.MatchState = PostMatch
+	state = arena.currentScoreboardState()
+	assert.Equal(t, 0, state.Period)
+	assert.Equal(t, 0, state.ClockSec)
+	assert.Greater(t, state.RedScore, 0)
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for stepping the audience display through the producer's configured sequence of screens.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"time"
+)
+
+// Moves the audience display on to the next step of the show flow, wrapping back around to the first step after the
+// last one so that the same sequence can be run for every match.
+func (arena *Arena) AdvanceShowFlow() error {
+	showFlowSteps, err := arena.Database.GetAllShowFlowSteps()
+	if err != nil {
+		return err
+	}
+	if len(showFlowSteps) == 0 {
+		return fmt.Errorf("no show flow steps have been configured")
+	}
+
+	nextPosition := (arena.ShowFlowPosition + 1) % len(showFlowSteps)
+	showFlowStep := showFlowSteps[nextPosition]
+	if showFlowStep.Screen == "score" && arena.ScoreReviewPending() {
+		return fmt.Errorf("cannot show the final score while it is pending review")
+	}
+	arena.SetAudienceDisplayMode(showFlowStep.Screen)
+	arena.ShowFlowPosition = nextPosition
+	arena.showFlowStepTime = time.Now()
+	arena.showFlowAutoAdvanceSec = showFlowStep.AutoAdvanceSec
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Returns the show flow to before its first step, without changing what the audience display is showing.
+func (arena *Arena) ResetShowFlow() {
+	arena.ShowFlowPosition = -1
+	arena.showFlowAutoAdvanceSec = 0
+	arena.ArenaStatusNotifier.Notify()
+}
+
+// Advances the show flow by itself once the current step's hold time has elapsed.
+func (arena *Arena) updateShowFlow() {
+	if arena.showFlowAutoAdvanceSec <= 0 ||
+		time.Since(arena.showFlowStepTime) < time.Duration(arena.showFlowAutoAdvanceSec)*time.Second {
+		return
+	}
+
+	// Only try once per step, leaving it to the producer to move on manually if the automatic advance fails.
+	arena.showFlowAutoAdvanceSec = 0
+	if err := arena.AdvanceShowFlow(); err != nil {
+		logging.Arena.Error("Failed to automatically advance show flow.", "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestShowFlow(t *testing.T) {
+	arena := setupTestArena(t)
+
+	err := arena.AdvanceShowFlow()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no show flow steps")
+	}
+	assert.Equal(t, -1, arena.ShowFlowPosition)
+
+	arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "intro", DisplayOrder: 1})
+	arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "match", DisplayOrder: 2})
+	arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "score", AutoAdvanceSec: 5, DisplayOrder: 3})
+	arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "sponsor", DisplayOrder: 4})
+
+	assert.Nil(t, arena.AdvanceShowFlow())
+	assert.Equal(t, 0, arena.ShowFlowPosition)
+	assert.Equal(t, "intro", arena.AudienceDisplayMode)
+	assert.Nil(t, arena.AdvanceShowFlow())
+	assert.Equal(t, 1, arena.ShowFlowPosition)
+	assert.Equal(t, "match", arena.AudienceDisplayMode)
+
+	// The final score step should be held back while the scores are under review.
+	arena.ScoreReview = &ScoreReview{}
+	err = arena.AdvanceShowFlow()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "pending review")
+	}
+	assert.Equal(t, 1, arena.ShowFlowPosition)
+	arena.ScoreReview = nil
+	assert.Nil(t, arena.AdvanceShowFlow())
+	assert.Equal(t, "score", arena.AudienceDisplayMode)
+
+	// The step with a hold time should move on to the next one by itself once the time is up.
+	arena.Update()
+	assert.Equal(t, "score", arena.AudienceDisplayMode)
+	arena.showFlowStepTime = time.Now().Add(-6 * time.Second)
+	arena.Update()
+	assert.Equal(t, 3, arena.ShowFlowPosition)
+	assert.Equal(t, "sponsor", arena.AudienceDisplayMode)
+
+	// The flow should wrap around to the start after the last step.
+	assert.Nil(t, arena.AdvanceShowFlow())
+	assert.Equal(t, 0, arena.ShowFlowPosition)
+	assert.Equal(t, "intro", arena.AudienceDisplayMode)
+
+	arena.ResetShowFlow()
+	assert.Equal(t, -1, arena.ShowFlowPosition)
+	assert.Equal(t, "intro", arena.AudienceDisplayMode)
+	assert.Nil(t, arena.AdvanceShowFlow())
+	assert.Equal(t, 0, arena.ShowFlowPosition)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for periodically checking for newer builds of the server and applying a downloaded one between matches.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Interval at which to check the release endpoint for a newer build.
+const updateCheckInterval = 6 * time.Hour
+
+// Checks for a newer build of the server if checking is enabled and it has been long enough since the last check.
+func (arena *Arena) runPeriodicUpdateCheck() {
+	if !arena.Updater.IsEnabled() || time.Since(arena.Updater.Status().LastCheckTime) < updateCheckInterval {
+		return
+	}
+	if err := arena.Updater.Check(); err != nil {
+		logging.Arena.Warn("Failed to check for a software update.", "error", err)
+	}
+}
+
+// Unpacks a downloaded software update that has been scheduled to be applied once the arena is back in the pre-match
+// state, and then asks for the server to be restarted to run it.
+func (arena *Arena) applyScheduledUpdate() {
+	if arena.MatchState != PreMatch || !arena.Updater.ApplyScheduled() {
+		return
+	}
+	version := arena.Updater.Status().StagedRelease.Version
+	logging.Arena.Info("Applying software update.", "version", version)
+	if err := arena.Updater.Apply(model.BaseDir); err != nil {
+		logging.Arena.Error("Failed to apply the software update.", "version", version, "error", err)
+		return
+	}
+	logging.Arena.Info("Software update applied; restarting to run it.", "version", version)
+	select {
+	case arena.restartRequests <- struct{}{}:
+	default:
+	}
+}
+
+// Returns a channel that receives a value when the server needs to be restarted to run an update that has just been
+// applied.
+func (arena *Arena) RestartRequests() <-chan struct{} {
+	return arena.restartRequests
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/updater"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPeriodicUpdateCheck(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Nothing should be checked unless a release endpoint has been configured.
+	arena.runPeriodicUpdateCheck()
+	assert.True(t, arena.Updater.Status().LastCheckTime.IsZero())
+
+	server := updater.NewTestReleaseServer(t, "v2024.2.0", nil)
+	arena.Updater = updater.NewUpdater(server.URL+"/releases/latest", "v2024.1.0", t.TempDir())
+	arena.runPeriodicUpdateCheck()
+	status := arena.Updater.Status()
+	assert.True(t, status.UpdateAvailable)
+	lastCheckTime := status.LastCheckTime
+
+	// The next check should wait for the interval to elapse.
+	arena.runPeriodicUpdateCheck()
+	assert.Equal(t, lastCheckTime, arena.Updater.Status().LastCheckTime)
+}
+
+func TestApplyScheduledUpdate(t *testing.T) {
+	arena := setupTestArena(t)
+	model.BaseDir = t.TempDir()
+	defer func() { model.BaseDir = ".." }()
+	server := updater.NewTestReleaseServer(t, "v2024.2.0", map[string]string{"README.md": "new readme"})
+	arena.Updater = updater.NewUpdater(server.URL+"/releases/latest", "v2024.1.0", t.TempDir())
+	assert.Nil(t, arena.Updater.Check())
+	assert.Nil(t, arena.Updater.Stage())
+	assert.Nil(t, arena.Updater.ScheduleApply(true))
+
+	// The update should be held back while a match is under way.
+	arena.MatchState = TeleopPeriod
+	arena.applyScheduledUpdate()
+	assert.True(t, arena.Updater.ApplyScheduled())
+	assert.NoFileExists(t, filepath.Join(model.BaseDir, "README.md"))
+	assert.Empty(t, arena.RestartRequests())
+
+	arena.MatchState = PreMatch
+	arena.applyScheduledUpdate()
+	assert.False(t, arena.Updater.ApplyScheduled())
+	readme, err := os.ReadFile(filepath.Join(model.BaseDir, "README.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "new readme", string(readme))
+	assert.Equal(t, "v2024.2.0", arena.Updater.Status().CurrentVersion)
+	assert.Len(t, arena.RestartRequests(), 1)
+}
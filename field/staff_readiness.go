@@ -0,0 +1,84 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for tracking whether the key field staff have confirmed that they are ready for the next match to start,
+// either from their own interfaces or from a venue intercom or paging system.
+
+package field
+
+import (
+	"fmt"
+)
+
+type StaffRole string
+
+const (
+	StaffHeadReferee StaffRole = "headReferee"
+	StaffScorekeeper StaffRole = "scorekeeper"
+	StaffFta         StaffRole = "fta"
+)
+
+// Human-readable names of each field staff role that must confirm readiness.
+var StaffRoleNames = map[StaffRole]string{
+	StaffHeadReferee: "Head Referee",
+	StaffScorekeeper: "Scorekeeper",
+	StaffFta:         "FTA",
+}
+
+// Represents the readiness of the field staff for the upcoming match.
+type StaffReadiness struct {
+	HeadRefereeReady bool
+	ScorekeeperReady bool
+	FtaReady         bool
+}
+
+// Returns true if the given field staff role has confirmed that they are ready.
+func (readiness *StaffReadiness) IsReady(role StaffRole) bool {
+	switch role {
+	case StaffHeadReferee:
+		return readiness.HeadRefereeReady
+	case StaffScorekeeper:
+		return readiness.ScorekeeperReady
+	case StaffFta:
+		return readiness.FtaReady
+	}
+	return false
+}
+
+// Records whether the given field staff role is ready for the match to start.
+func (arena *Arena) SetStaffReady(role StaffRole, ready bool) error {
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot change staff readiness while there is a match in progress or with results pending")
+	}
+	switch role {
+	case StaffHeadReferee:
+		arena.StaffReadiness.HeadRefereeReady = ready
+	case StaffScorekeeper:
+		arena.StaffReadiness.ScorekeeperReady = ready
+	case StaffFta:
+		arena.StaffReadiness.FtaReady = ready
+	default:
+		return fmt.Errorf("invalid staff role '%s'", role)
+	}
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Clears all the field staff confirmations so that they must be given again before the next match.
+func (arena *Arena) resetStaffReadiness() {
+	arena.StaffReadiness = StaffReadiness{}
+}
+
+// Returns nil if the staff readiness check is disabled or all the field staff have confirmed that they are ready, and
+// an error naming the first role that hasn't otherwise.
+func (arena *Arena) checkStaffReady() error {
+	if !arena.EventSettings.StaffReadyCheckEnabled {
+		return nil
+	}
+	for _, role := range []StaffRole{StaffHeadReferee, StaffScorekeeper, StaffFta} {
+		if !arena.StaffReadiness.IsReady(role) {
+			return fmt.Errorf("cannot start match until the %s has confirmed ready", StaffRoleNames[role])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStaffReadiness(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	// The check shouldn't get in the way unless it's enabled.
+	assert.Nil(t, arena.checkCanStartMatch())
+	arena.EventSettings.StaffReadyCheckEnabled = true
+	assert.EqualError(
+		t, arena.checkCanStartMatch(), "cannot start match until the Head Referee has confirmed ready",
+	)
+
+	assert.EqualError(t, arena.SetStaffReady("announcer", true), "invalid staff role 'announcer'")
+	assert.Nil(t, arena.SetStaffReady(StaffHeadReferee, true))
+	assert.Nil(t, arena.SetStaffReady(StaffFta, true))
+	assert.EqualError(t, arena.checkCanStartMatch(), "cannot start match until the Scorekeeper has confirmed ready")
+	assert.Nil(t, arena.SetStaffReady(StaffScorekeeper, true))
+	assert.Nil(t, arena.SetStaffReady(StaffFta, false))
+	assert.EqualError(t, arena.checkCanStartMatch(), "cannot start match until the FTA has confirmed ready")
+	assert.Nil(t, arena.SetStaffReady(StaffFta, true))
+	assert.Equal(t, StaffReadiness{true, true, true}, arena.StaffReadiness)
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	// Starting the match should clear the confirmations so that they need to be given again for the next one.
+	assert.Nil(t, arena.StartMatch())
+	assert.Equal(t, StaffReadiness{}, arena.StaffReadiness)
+	arena.Update()
+	assert.EqualError(
+		t,
+		arena.SetStaffReady(StaffFta, true),
+		"cannot change staff readiness while there is a match in progress or with results pending",
+	)
+
+	// So should loading a different match.
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.SetStaffReady(StaffHeadReferee, true))
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.False(t, arena.StaffReadiness.HeadRefereeReady)
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for bypassing alliance stations and keeping track of the reason for each bypass, which is recorded in the
+// match result and affects how the match counts towards the team's ranking.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"strconv"
+)
+
+// Bypasses the given alliance station for the given reason, or clears its bypass if it is already bypassed.
+func (arena *Arena) ToggleBypass(station, reason string) error {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return fmt.Errorf("invalid alliance station '%s'", station)
+	}
+
+	if allianceStation.Bypass {
+		allianceStation.Bypass = false
+		allianceStation.BypassReason = ""
+	} else {
+		if _, ok = model.BypassReasonNames[reason]; !ok {
+			return fmt.Errorf("invalid bypass reason '%s'", reason)
+		}
+		allianceStation.Bypass = true
+		allianceStation.BypassReason = reason
+	}
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Returns a map of the bypass reason for each team on the given alliance whose station is bypassed, keyed by team ID
+// in the same way as the cards are.
+func (arena *Arena) BypassReasons(alliance string) map[string]string {
+	bypasses := make(map[string]string)
+	for _, position := range []string{"1", "2", "3"} {
+		allianceStation := arena.AllianceStations[alliance+position]
+		if allianceStation.Bypass && allianceStation.Team != nil && allianceStation.BypassReason != "" {
+			bypasses[strconv.Itoa(allianceStation.Team.Id)] = allianceStation.BypassReason
+		}
+	}
+	return bypasses
+}
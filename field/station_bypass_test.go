@@ -0,0 +1,43 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToggleBypass(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.assignTeam(254, "R2"))
+	assert.Nil(t, arena.assignTeam(1114, "B3"))
+
+	assert.EqualError(t, arena.ToggleBypass("R4", model.BypassNoShow), "invalid alliance station 'R4'")
+	assert.EqualError(t, arena.ToggleBypass("R2", ""), "invalid bypass reason ''")
+	assert.False(t, arena.AllianceStations["R2"].Bypass)
+
+	assert.Nil(t, arena.ToggleBypass("R2", model.BypassNoShow))
+	assert.Nil(t, arena.ToggleBypass("B3", model.BypassRedCard))
+	assert.Nil(t, arena.ToggleBypass("B1", model.BypassRobotFailure))
+	assert.True(t, arena.AllianceStations["R2"].Bypass)
+	assert.Equal(t, model.BypassNoShow, arena.AllianceStations["R2"].BypassReason)
+	assert.Equal(t, map[string]string{"254": model.BypassNoShow}, arena.BypassReasons("R"))
+
+	// Empty stations shouldn't show up in the recorded bypasses.
+	assert.Equal(t, map[string]string{"1114": model.BypassRedCard}, arena.BypassReasons("B"))
+
+	// Toggling a bypassed station should clear it regardless of the reason given.
+	assert.Nil(t, arena.ToggleBypass("B3", ""))
+	assert.False(t, arena.AllianceStations["B3"].Bypass)
+	assert.Equal(t, "", arena.AllianceStations["B3"].BypassReason)
+	assert.Empty(t, arena.BypassReasons("B"))
+
+	assert.Nil(t, arena.ResetMatch())
+	assert.False(t, arena.AllianceStations["R2"].Bypass)
+	assert.Equal(t, "", arena.AllianceStations["R2"].BypassReason)
+	assert.Empty(t, arena.BypassReasons("R"))
+}
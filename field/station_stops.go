@@ -0,0 +1,80 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for tracking the emergency-stop and autonomous-stop state of each alliance station.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Represents the stop state of a single alliance station, as published to the displays.
+type StationStopStatus struct {
+	TeamId             int
+	EStop              bool
+	AStop              bool
+	RobotEnableBlocked bool
+}
+
+// Returns true if the robot in the station must not be enabled, given whether the match is in the autonomous period.
+// An E-stop disables the robot for the remainder of the match, while an A-stop only does so until the end of the
+// autonomous period.
+func (allianceStation *AllianceStation) robotEnableBlocked(auto bool) bool {
+	return allianceStation.EStop || auto && allianceStation.AStop || allianceStation.Bypass
+}
+
+// Updates the latched stop state of the given station from the current state of its hardware inputs, logging and
+// publishing any change.
+func (arena *Arena) handleTeamStop(station string, eStopState, aStopState bool) {
+	allianceStation := arena.AllianceStations[station]
+	wasEStopped, wasAStopped := allianceStation.EStop, allianceStation.AStop
+	if eStopState {
+		allianceStation.EStop = true
+	} else if arena.MatchTimeSec() == 0 {
+		// Keep the E-stop latched until the match is over.
+		allianceStation.EStop = false
+	}
+	if aStopState {
+		allianceStation.AStop = true
+	} else if arena.MatchState != AutoPeriod {
+		// Keep the A-stop latched until the autonomous period is over.
+		allianceStation.AStop = false
+		allianceStation.aStopReset = true
+	}
+
+	if allianceStation.EStop != wasEStopped {
+		if allianceStation.EStop {
+			arena.RecordTimelineEvent(model.TimelineStop, station, "E-stop activated")
+		} else {
+			arena.RecordTimelineEvent(model.TimelineStop, station, "E-stop cleared")
+		}
+	}
+	if allianceStation.AStop != wasAStopped {
+		if allianceStation.AStop {
+			arena.RecordTimelineEvent(model.TimelineStop, station, "A-stop activated")
+		} else {
+			arena.RecordTimelineEvent(model.TimelineStop, station, "A-stop cleared")
+		}
+	}
+	if allianceStation.EStop != wasEStopped || allianceStation.AStop != wasAStopped {
+		arena.StationStopsNotifier.Notify()
+	}
+}
+
+func (arena *Arena) generateStationStopsMessage() any {
+	auto := arena.MatchState == AutoPeriod
+	stationStops := make(map[string]StationStopStatus, len(arena.AllianceStations))
+	for station, allianceStation := range arena.AllianceStations {
+		stationStop := StationStopStatus{
+			EStop:              allianceStation.EStop,
+			AStop:              allianceStation.AStop,
+			RobotEnableBlocked: allianceStation.robotEnableBlocked(auto),
+		}
+		if allianceStation.Team != nil {
+			stationStop.TeamId = allianceStation.Team.Id
+		}
+		stationStops[station] = stationStop
+	}
+	return stationStops
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHandleTeamStop(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+
+	// An E-stop before the match clears as soon as it is released.
+	arena.handleTeamStop("R1", true, false)
+	stationStops := arena.generateStationStopsMessage().(map[string]StationStopStatus)
+	assert.Equal(t, StationStopStatus{TeamId: 254, EStop: true, RobotEnableBlocked: true}, stationStops["R1"])
+	assert.Equal(t, StationStopStatus{}, stationStops["B3"])
+	arena.handleTeamStop("R1", false, false)
+	assert.False(t, arena.AllianceStations["R1"].EStop)
+
+	// During autonomous, an A-stop blocks the robot but an E-stop stays latched after release.
+	arena.MatchState = AutoPeriod
+	arena.MatchStartTime = time.Now().Add(-time.Second)
+	arena.handleTeamStop("R1", false, true)
+	arena.handleTeamStop("R1", false, false)
+	stationStops = arena.generateStationStopsMessage().(map[string]StationStopStatus)
+	assert.Equal(t, StationStopStatus{TeamId: 254, AStop: true, RobotEnableBlocked: true}, stationStops["R1"])
+	arena.handleTeamStop("R1", true, false)
+	arena.handleTeamStop("R1", false, false)
+	assert.True(t, arena.AllianceStations["R1"].EStop)
+
+	// The A-stop is released once teleop starts, but the E-stop still blocks the robot.
+	arena.MatchState = TeleopPeriod
+	arena.handleTeamStop("R1", false, false)
+	stationStops = arena.generateStationStopsMessage().(map[string]StationStopStatus)
+	assert.Equal(t, StationStopStatus{TeamId: 254, EStop: true, RobotEnableBlocked: true}, stationStops["R1"])
+
+	events := arena.GetMatchTimeline()
+	var descriptions []string
+	for _, event := range events {
+		assert.Equal(t, model.TimelineStop, event.Type)
+		assert.Equal(t, "R1", event.Source)
+		descriptions = append(descriptions, event.Description)
+	}
+	assert.Equal(
+		t,
+		[]string{"E-stop activated", "E-stop cleared", "A-stop activated", "E-stop activated", "A-stop cleared"},
+		descriptions,
+	)
+}
@@ -7,10 +7,10 @@ package field
 
 import (
 	"fmt"
-	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"image/color"
-	"log"
+	"maps"
 	"net"
 	"strconv"
 	"strings"
@@ -26,6 +26,18 @@ type TeamSigns struct {
 	Blue2     TeamSign
 	Blue3     TeamSign
 	BlueTimer TeamSign
+	lastState map[string]TeamSignState
+}
+
+// Represents the content shown on a team number sign, as published to web-based and serial-driven signs.
+type TeamSignState struct {
+	TeamId     int
+	FrontText  string
+	FrontColor string
+	RearText   string
+	Countdown  string
+	EStop      bool
+	AStop      bool
 }
 
 // Represents a team number or timer sign.
@@ -74,25 +86,7 @@ func NewTeamSigns() *TeamSigns {
 // Updates the state of all signs with the latest data and sends packets to the signs if anything has changed.
 func (signs *TeamSigns) Update(arena *Arena) {
 	// Generate the countdown string which is used in multiple places.
-	matchTimeSec := int(arena.MatchTimeSec())
-	var countdownSec int
-	switch arena.MatchState {
-	case PreMatch:
-		fallthrough
-	case StartMatch:
-		fallthrough
-	case WarmupPeriod:
-		countdownSec = game.MatchTiming.AutoDurationSec
-	case AutoPeriod:
-		countdownSec = game.MatchTiming.WarmupDurationSec + game.MatchTiming.AutoDurationSec - matchTimeSec
-	case TeleopPeriod:
-		countdownSec = game.MatchTiming.WarmupDurationSec + game.MatchTiming.AutoDurationSec +
-			game.MatchTiming.TeleopDurationSec + game.MatchTiming.PauseDurationSec - matchTimeSec
-	case TimeoutActive:
-		countdownSec = game.MatchTiming.TimeoutDurationSec - matchTimeSec
-	default:
-		countdownSec = 0
-	}
+	countdownSec := arena.countdownSec()
 	countdown := fmt.Sprintf("%02d:%02d", countdownSec/60, countdownSec%60)
 
 	// Generate the in-match rear text which is common to a whole alliance.
@@ -107,6 +101,24 @@ func (signs *TeamSigns) Update(arena *Arena) {
 	signs.Blue2.update(arena, arena.AllianceStations["B2"], false, countdown, blueInMatchRearText)
 	signs.Blue3.update(arena, arena.AllianceStations["B3"], false, countdown, blueInMatchRearText)
 	signs.BlueTimer.update(arena, nil, false, countdown, blueInMatchRearText)
+
+	// Publish the sign content to any web-based or serial-driven signs if it has changed.
+	state := map[string]TeamSignState{
+		"R1": signs.Red1.getState(arena.AllianceStations["R1"], countdown),
+		"R2": signs.Red2.getState(arena.AllianceStations["R2"], countdown),
+		"R3": signs.Red3.getState(arena.AllianceStations["R3"], countdown),
+		"B1": signs.Blue1.getState(arena.AllianceStations["B1"], countdown),
+		"B2": signs.Blue2.getState(arena.AllianceStations["B2"], countdown),
+		"B3": signs.Blue3.getState(arena.AllianceStations["B3"], countdown),
+	}
+	if !maps.Equal(state, signs.lastState) {
+		signs.lastState = state
+		arena.TeamSignsNotifier.Notify()
+	}
+}
+
+func (arena *Arena) generateTeamSignsMessage() any {
+	return arena.TeamSigns.lastState
 }
 
 // Sets the team numbers for the next match on all signs.
@@ -133,12 +145,12 @@ func (sign *TeamSign) SetAddress(ipAddress string) {
 	var err error
 	sign.udpConn, err = net.Dial("udp4", fmt.Sprintf("%s:%d", ipAddress, teamSignPort))
 	if err != nil {
-		log.Printf("Failed to connect to team sign at %s: %v", ipAddress, err)
+		logging.Hardware.Error("Failed to connect to team sign.", "address", ipAddress, "error", err)
 		return
 	}
 	addressParts := strings.Split(ipAddress, ".")
 	if len(addressParts) != 4 {
-		log.Printf("Failed to configure team sign: invalid IP address: %s", ipAddress)
+		logging.Hardware.Error("Failed to configure team sign: invalid IP address.", "address", ipAddress)
 		return
 	}
 	address, _ := strconv.Atoi(addressParts[3])
@@ -153,11 +165,6 @@ func (sign *TeamSign) SetAddress(ipAddress string) {
 func (sign *TeamSign) update(
 	arena *Arena, allianceStation *AllianceStation, isRed bool, countdown, inMatchRearText string,
 ) {
-	if sign.address == 0 {
-		// Don't do anything if there is no sign configured in this position.
-		return
-	}
-
 	if sign.isTimer {
 		sign.frontText, sign.frontColor = generateTimerText(arena.FieldReset, countdown)
 		sign.rearText = inMatchRearText
@@ -167,9 +174,29 @@ func (sign *TeamSign) update(
 		)
 	}
 
+	if sign.address == 0 {
+		// Don't send anything if there is no physical sign configured in this position.
+		return
+	}
 	if err := sign.sendPacket(); err != nil {
-		log.Printf("Failed to send team sign packet: %v", err)
+		logging.Hardware.Error("Failed to send team sign packet.", "error", err)
+	}
+}
+
+// Returns the content currently shown on the sign, for publishing to web-based and serial-driven signs.
+func (sign *TeamSign) getState(allianceStation *AllianceStation, countdown string) TeamSignState {
+	state := TeamSignState{
+		FrontText:  strings.TrimSpace(sign.frontText),
+		FrontColor: fmt.Sprintf("#%02x%02x%02x", sign.frontColor.R, sign.frontColor.G, sign.frontColor.B),
+		RearText:   strings.TrimSpace(sign.rearText),
+		Countdown:  countdown,
+		EStop:      allianceStation.EStop,
+		AStop:      allianceStation.AStop,
+	}
+	if allianceStation.Team != nil {
+		state.TeamId = allianceStation.Team.Id
 	}
+	return state
 }
 
 // Returns the in-match rear text that is common to a whole alliance.
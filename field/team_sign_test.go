@@ -129,3 +129,28 @@ func TestTeamSign_TeamNumber(t *testing.T) {
 	arena.assignTeam(1503, "R1")
 	assertSign(false, " 1503", blueColor, "1503      Connect PC")
 }
+
+func TestTeamSigns_State(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+
+	arena.TeamSigns.Update(arena)
+	state := arena.generateTeamSignsMessage().(map[string]TeamSignState)
+	assert.Equal(t, 6, len(state))
+	assert.Equal(t, 0, state["R1"].TeamId)
+	assert.Equal(t, "No Team Assigned", state["R1"].RearText)
+
+	// Check that the state follows changes to the lineup and E-stop status.
+	arena.FieldReset = false
+	arena.assignTeam(254, "B2")
+	arena.AllianceStations["B2"].EStop = true
+	arena.TeamSigns.Update(arena)
+	state = arena.generateTeamSignsMessage().(map[string]TeamSignState)
+	assert.Equal(t, 254, state["B2"].TeamId)
+	assert.Equal(t, "254", state["B2"].FrontText)
+	assert.Equal(t, "#ffa500", state["B2"].FrontColor)
+	assert.Equal(t, "254           E-STOP", state["B2"].RearText)
+	assert.Equal(t, "00:15", state["B2"].Countdown)
+	assert.True(t, state["B2"].EStop)
+	assert.False(t, state["B2"].AStop)
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for recording the start and end of each match against the timeline of the video stream, so that the match
+// videos can be cut out of the stream automatically after the event.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Records that the video stream is at the given position as of now, as the reference for all subsequent markers.
+func (arena *Arena) SetVideoStreamOffset(offsetSec float64) error {
+	if offsetSec < 0 {
+		return fmt.Errorf("invalid stream offset %.1f; must not be negative", offsetSec)
+	}
+	streamSync := model.VideoMarker{
+		Type: model.VideoMarkerStreamSync, Time: time.Now(), StreamSynced: true, StreamOffsetSec: offsetSec,
+	}
+	if err := arena.Database.CreateVideoMarker(&streamSync); err != nil {
+		return err
+	}
+	arena.videoStreamSync = &streamSync
+	return nil
+}
+
+// Saves a marker of the given type for the current match at the given time, unless it is a test match.
+func (arena *Arena) recordVideoMarker(markerType model.VideoMarkerType, markerTime time.Time) {
+	if arena.CurrentMatch.Type == model.Test {
+		return
+	}
+	videoMarker := model.VideoMarker{MatchId: arena.CurrentMatch.Id, Type: markerType, Time: markerTime}
+	if arena.videoStreamSync != nil {
+		videoMarker.StreamSynced = true
+		videoMarker.StreamOffsetSec =
+			arena.videoStreamSync.StreamOffsetSec + markerTime.Sub(arena.videoStreamSync.Time).Seconds()
+	}
+	if err := arena.Database.CreateVideoMarker(&videoMarker); err != nil {
+		logging.Arena.Error("Failed to save video marker.", "type", markerType, "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVideoMarkers(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	// Test matches shouldn't be marked.
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Nil(t, arena.AbortMatch())
+	videoMarkers, _ := arena.Database.GetAllVideoMarkers()
+	assert.Empty(t, videoMarkers)
+	arena.Update()
+	assert.Nil(t, arena.ResetMatch())
+
+	// Markers made before the stream offset is known should still have the wall-clock time.
+	match := model.Match{Type: model.Qualification, ShortName: "Q1"}
+	assert.Nil(t, arena.Database.CreateMatch(&match))
+	assert.Nil(t, arena.LoadMatch(&match))
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	startMarker, endMarker, err := arena.Database.GetVideoMarkersForMatch(match.Id)
+	assert.Nil(t, err)
+	if assert.NotNil(t, startMarker) {
+		assert.Equal(t, model.VideoMarkerMatchStart, startMarker.Type)
+		assert.Equal(t, arena.MatchStartTime.UnixNano(), startMarker.Time.UnixNano())
+		assert.False(t, startMarker.StreamSynced)
+	}
+	assert.Nil(t, endMarker)
+
+	// Once the offset is given, markers should be placed relative to it.
+	assert.NotNil(t, arena.SetVideoStreamOffset(-1))
+	assert.Nil(t, arena.SetVideoStreamOffset(3600))
+	arena.videoStreamSync.Time = arena.videoStreamSync.Time.Add(-10 * time.Second)
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd())
+	for arena.MatchState != PostMatch {
+		arena.Update()
+	}
+	_, endMarker, err = arena.Database.GetVideoMarkersForMatch(match.Id)
+	assert.Nil(t, err)
+	if assert.NotNil(t, endMarker) {
+		assert.True(t, endMarker.StreamSynced)
+		assert.InDelta(t, 3610, endMarker.StreamOffsetSec, 0.5)
+	}
+
+	// The stream offset should survive a restart.
+	dbPath := filepath.Join(model.BaseDir, "field_test.db")
+	assert.Nil(t, arena.Database.Close())
+	arena, err = NewArena(dbPath)
+	assert.Nil(t, err)
+	if assert.NotNil(t, arena.videoStreamSync) {
+		assert.Equal(t, 3600.0, arena.videoStreamSync.StreamOffsetSec)
+	}
+}
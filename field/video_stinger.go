@@ -0,0 +1,71 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for playing uploaded video stingers full-screen on the audience display.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+const (
+	// Audience display screen that shows the video stinger that is currently playing.
+	videoStingerScreen = "stinger"
+
+	// Longest a stinger is allowed to hold the audience display before moving on, in case no display reports back.
+	maxVideoStingerDurationSec = 120
+)
+
+// Starts playing the given video stinger on the audience displays.
+func (arena *Arena) PlayVideoStinger(id int) error {
+	videoStinger, err := arena.Database.GetVideoStingerById(id)
+	if err != nil {
+		return err
+	}
+	if videoStinger == nil {
+		return fmt.Errorf("video stinger %d does not exist", id)
+	}
+
+	// Let the audience displays know which video to play before they are told to switch over to it.
+	arena.activeVideoStinger = videoStinger
+	arena.videoStingerStartTime = time.Now()
+	arena.VideoStingerNotifier.Notify()
+	arena.SetAudienceDisplayMode(videoStingerScreen)
+	return nil
+}
+
+// Moves the audience display on from the given video stinger once a display reports that it has finished playing it
+// or had to skip it because it couldn't be buffered in time. Reports for any stinger other than the one that is
+// playing are ignored, so that the first of several audience displays to finish wins.
+func (arena *Arena) CompleteVideoStinger(id int) {
+	if arena.activeVideoStinger == nil || arena.activeVideoStinger.Id != id {
+		return
+	}
+	arena.SetAudienceDisplayMode(arena.activeVideoStinger.NextScreen)
+}
+
+// Moves on from the current video stinger if it has been playing for too long.
+func (arena *Arena) updateVideoStinger() {
+	if arena.activeVideoStinger != nil &&
+		time.Since(arena.videoStingerStartTime).Seconds() >= maxVideoStingerDurationSec {
+		logging.Arena.Warn(
+			"Video stinger did not finish playing in time; moving on.", "stinger", arena.activeVideoStinger.Name,
+		)
+		arena.CompleteVideoStinger(arena.activeVideoStinger.Id)
+	}
+}
+
+func (arena *Arena) generateVideoStingerMessage() any {
+	videoStingers, err := arena.Database.GetAllVideoStingers()
+	if err != nil {
+		logging.Arena.Error("Failed to get video stingers.", "error", err)
+	}
+	return &struct {
+		VideoStingers []model.VideoStinger
+		Active        *model.VideoStinger
+	}{videoStingers, arena.activeVideoStinger}
+}
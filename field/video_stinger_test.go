@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVideoStinger(t *testing.T) {
+	arena := setupTestArena(t)
+
+	err := arena.PlayVideoStinger(1)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+
+	videoStinger1 := model.VideoStinger{Name: "Match Start", VideoFile: "match_start.mp4", NextScreen: "match"}
+	arena.Database.CreateVideoStinger(&videoStinger1)
+	videoStinger2 := model.VideoStinger{Name: "Award Intro", VideoFile: "award.mp4", NextScreen: "logo"}
+	arena.Database.CreateVideoStinger(&videoStinger2)
+
+	assert.Nil(t, arena.PlayVideoStinger(videoStinger1.Id))
+	assert.Equal(t, "stinger", arena.AudienceDisplayMode)
+	assert.Equal(t, videoStinger1, *arena.activeVideoStinger)
+
+	// Completion reports for a stinger other than the one playing should be ignored.
+	arena.CompleteVideoStinger(videoStinger2.Id)
+	assert.Equal(t, "stinger", arena.AudienceDisplayMode)
+	arena.CompleteVideoStinger(videoStinger1.Id)
+	assert.Equal(t, "match", arena.AudienceDisplayMode)
+	assert.Nil(t, arena.activeVideoStinger)
+	arena.CompleteVideoStinger(videoStinger1.Id)
+	assert.Equal(t, "match", arena.AudienceDisplayMode)
+
+	// Switching the audience display to another screen should cut off the stinger.
+	assert.Nil(t, arena.PlayVideoStinger(videoStinger2.Id))
+	arena.SetAudienceDisplayMode("blank")
+	assert.Nil(t, arena.activeVideoStinger)
+
+	// The stinger should be moved on from if no display reports back in time.
+	assert.Nil(t, arena.PlayVideoStinger(videoStinger2.Id))
+	arena.Update()
+	assert.Equal(t, "stinger", arena.AudienceDisplayMode)
+	arena.videoStingerStartTime = time.Now().Add(-maxVideoStingerDurationSec * time.Second)
+	arena.Update()
+	assert.Equal(t, "logo", arena.AudienceDisplayMode)
+	assert.Nil(t, arena.activeVideoStinger)
+}
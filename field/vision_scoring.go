@@ -0,0 +1,83 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for merging provisional scores from an automated vision scoring system into the realtime score.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Represents a provisional update of the score elements of one alliance from an automated vision scoring system.
+// Elements that are omitted are left unchanged.
+type VisionScoreUpdate struct {
+	LeaveStatuses      *[3]bool
+	EndgameStatuses    *[3]game.EndgameStatus
+	MicrophoneStatuses *[3]bool
+	TrapStatuses       *[3]bool
+}
+
+// Applies the given provisional score update to the given alliance's realtime score, marking each element it sets as
+// machine-scored. Elements that have already been set by a human scorer are left as they are.
+func (arena *Arena) ApplyVisionScoreUpdate(alliance string, update *VisionScoreUpdate) error {
+	if alliance != "red" && alliance != "blue" {
+		return fmt.Errorf("Invalid alliance '%s'.", alliance)
+	}
+	if arena.MatchState == PreMatch || arena.MatchState == TimeoutActive || arena.MatchState == PostTimeout {
+		return fmt.Errorf("Cannot accept vision scores while no match is in progress.")
+	}
+	if update.EndgameStatuses != nil {
+		for _, status := range update.EndgameStatuses {
+			if status < game.EndgameNone || status > game.EndgameStageRight {
+				return fmt.Errorf("Invalid endgame status %d.", status)
+			}
+		}
+	}
+
+	realtimeScore := arena.getAllianceRealtimeScore(alliance)
+	applyElement := func(command string, teamPosition, stageIndex, value int) {
+		scoringCommand := game.ScoringCommand{
+			Command: command, TeamPosition: teamPosition, StageIndex: stageIndex, NewValue: value,
+		}
+		if realtimeScore.HumanScored.Get(&scoringCommand) {
+			return
+		}
+		scoringCommand.Apply(&realtimeScore.CurrentScore)
+		realtimeScore.MachineScored.Set(&scoringCommand, true)
+	}
+	for i := 0; i < 3; i++ {
+		if update.LeaveStatuses != nil {
+			applyElement("leave", i+1, 0, visionBoolToInt(update.LeaveStatuses[i]))
+		}
+		if update.EndgameStatuses != nil {
+			applyElement("park", i+1, 0, int(update.EndgameStatuses[i]))
+		}
+		if update.MicrophoneStatuses != nil {
+			applyElement("microphone", 0, i, visionBoolToInt(update.MicrophoneStatuses[i]))
+		}
+		if update.TrapStatuses != nil {
+			applyElement("trap", 0, i, visionBoolToInt(update.TrapStatuses[i]))
+		}
+	}
+
+	scoreSummary := arena.RedScoreSummary()
+	if alliance == "blue" {
+		scoreSummary = arena.BlueScoreSummary()
+	}
+	arena.RecordTimelineEvent(
+		model.TimelineScore, alliance, fmt.Sprintf("Vision system updated score to %d", scoreSummary.Score),
+	)
+	arena.RealtimeScoreNotifier.Notify()
+	arena.LiveScoreNotifier.Notify()
+	return nil
+}
+
+func visionBoolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
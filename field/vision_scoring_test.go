@@ -0,0 +1,56 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyVisionScoreUpdate(t *testing.T) {
+	arena := setupTestArena(t)
+	leaveStatuses := [3]bool{true, false, true}
+	endgameStatuses := [3]game.EndgameStatus{game.EndgameParked, game.EndgameNone, game.EndgameStageRight}
+	update := VisionScoreUpdate{LeaveStatuses: &leaveStatuses, EndgameStatuses: &endgameStatuses}
+
+	err := arena.ApplyVisionScoreUpdate("red", &update)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot accept vision scores while no match is in progress.", err.Error())
+	}
+	arena.MatchState = TeleopPeriod
+	err = arena.ApplyVisionScoreUpdate("purple", &update)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid alliance 'purple'.", err.Error())
+	}
+	assert.Equal(t, [3]bool{}, arena.RedRealtimeScore.CurrentScore.LeaveStatuses)
+
+	assert.Nil(t, arena.ApplyVisionScoreUpdate("red", &update))
+	score := &arena.RedRealtimeScore.CurrentScore
+	assert.Equal(t, leaveStatuses, score.LeaveStatuses)
+	assert.Equal(t, endgameStatuses, score.EndgameStatuses)
+	assert.Equal(t, [3]bool{true, true, true}, arena.RedRealtimeScore.MachineScored.LeaveStatuses)
+	assert.Equal(t, [3]bool{true, true, true}, arena.RedRealtimeScore.MachineScored.EndgameStatuses)
+	assert.Equal(t, [3]bool{}, arena.RedRealtimeScore.MachineScored.TrapStatuses)
+	assert.Equal(t, [3]bool{}, arena.BlueRealtimeScore.CurrentScore.LeaveStatuses)
+
+	// Elements set by a human scorer should not be changed by subsequent updates.
+	arena.RedRealtimeScore.HumanScored.LeaveStatuses[0] = true
+	score.LeaveStatuses[0] = false
+	leaveStatuses = [3]bool{true, true, false}
+	trapStatuses := [3]bool{false, true, false}
+	assert.Nil(t, arena.ApplyVisionScoreUpdate("red", &VisionScoreUpdate{
+		LeaveStatuses: &leaveStatuses, TrapStatuses: &trapStatuses,
+	}))
+	assert.Equal(t, [3]bool{false, true, false}, score.LeaveStatuses)
+	assert.Equal(t, endgameStatuses, score.EndgameStatuses)
+	assert.Equal(t, trapStatuses, score.TrapStatuses)
+
+	endgameStatuses[1] = 7
+	err = arena.ApplyVisionScoreUpdate("red", &VisionScoreUpdate{EndgameStatuses: &endgameStatuses})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid endgame status 7.", err.Error())
+	}
+	assert.Equal(t, game.EndgameNone, score.EndgameStatuses[1])
+}
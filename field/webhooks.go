@@ -0,0 +1,86 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for firing the configured outgoing webhooks when notable arena events occur.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+)
+
+// Represents the match that an event pertains to, as sent in a webhook payload.
+type WebhookMatchData struct {
+	MatchId   int    `json:"matchId"`
+	Type      string `json:"type"`
+	ShortName string `json:"shortName"`
+	LongName  string `json:"longName"`
+	RedTeams  [3]int `json:"redTeams"`
+	BlueTeams [3]int `json:"blueTeams"`
+	RedScore  *int   `json:"redScore,omitempty"`
+	BlueScore *int   `json:"blueScore,omitempty"`
+	Winner    string `json:"winner,omitempty"`
+}
+
+// Represents the change in the schedule of a given type of match, as sent in a webhook payload.
+type WebhookScheduleData struct {
+	MatchType  string `json:"matchType"`
+	NumMatches int    `json:"numMatches"`
+}
+
+// Returns the webhook representation of the given match, excluding its scores.
+func NewWebhookMatchData(match *model.Match) WebhookMatchData {
+	data := WebhookMatchData{
+		MatchId:   match.Id,
+		Type:      match.Type.String(),
+		ShortName: match.ShortName,
+		LongName:  match.LongName,
+		RedTeams:  [3]int{match.Red1, match.Red2, match.Red3},
+		BlueTeams: [3]int{match.Blue1, match.Blue2, match.Blue3},
+	}
+	switch match.Status {
+	case game.RedWonMatch:
+		data.Winner = "red"
+	case game.BlueWonMatch:
+		data.Winner = "blue"
+	case game.TieMatch:
+		data.Winner = "tie"
+	}
+	return data
+}
+
+// Sends the given event to each of the webhooks that are subscribed to it. Delivery happens asynchronously so that a
+// slow or unreachable receiver can't hold up the field; failures are logged and otherwise ignored.
+func (arena *Arena) NotifyWebhooks(event string, data any) {
+	webhooks, err := arena.Database.GetAllWebhooks()
+	if err != nil {
+		logging.Notification.Error("Failed to get webhooks.", "error", err)
+		return
+	}
+	for _, webhook := range webhooks {
+		if !webhook.IsSubscribedTo(event) {
+			continue
+		}
+		go func() {
+			if err := partner.SendWebhook(&webhook, event, data); err != nil {
+				logging.Notification.Error("Failed to send webhook.", "url", webhook.Url, "event", event, "error", err)
+			}
+		}()
+	}
+}
+
+// Notifies the webhooks and push subscribers that the schedule for the given type of match has changed.
+func (arena *Arena) NotifyScheduleChanged(matchType model.MatchType) {
+	matches, err := arena.Database.GetMatchesByType(matchType, false)
+	if err != nil {
+		logging.Notification.Error("Failed to get matches.", "error", err)
+		return
+	}
+	arena.NotifyWebhooks(
+		partner.WebhookScheduleChanged, WebhookScheduleData{MatchType: matchType.String(), NumMatches: len(matches)},
+	)
+	arena.notifyScheduleChangedSubscribers(matchType, matches)
+}
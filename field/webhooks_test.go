@@ -0,0 +1,81 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package field
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotifyWebhooks(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Mock the webhook receiver.
+	payloads := make(chan map[string]any, 10)
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]any
+		json.Unmarshal(body, &payload)
+		payload["path"] = r.URL.Path
+		payloads <- payload
+	}))
+	defer webhookServer.Close()
+	arena.Database.CreateWebhook(
+		&model.Webhook{Url: webhookServer.URL + "/start", Events: []string{partner.WebhookMatchStarted}},
+	)
+	arena.Database.CreateWebhook(&model.Webhook{
+		Url: webhookServer.URL + "/all", Events: []string{partner.WebhookMatchStarted, partner.WebhookScheduleChanged},
+	})
+
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q1"})
+	arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q2"})
+	arena.NotifyScheduleChanged(model.Qualification)
+	payload := receiveWebhookPayload(t, payloads)
+	assert.Equal(t, "/all", payload["path"])
+	assert.Equal(t, "schedule_changed", payload["event"])
+	assert.Equal(t, map[string]any{"matchType": "Qualification", "numMatches": 2.0}, payload["data"])
+
+	match := model.Match{
+		Id: 12, Type: model.Playoff, ShortName: "F1", Red1: 254, Blue3: 1114, Status: game.BlueWonMatch,
+	}
+	arena.NotifyWebhooks(partner.WebhookMatchStarted, NewWebhookMatchData(&match))
+	paths := map[any]bool{}
+	for i := 0; i < 2; i++ {
+		payload = receiveWebhookPayload(t, payloads)
+		paths[payload["path"]] = true
+		assert.Equal(t, "match_started", payload["event"])
+		data := payload["data"].(map[string]any)
+		assert.Equal(t, "F1", data["shortName"])
+		assert.Equal(t, []any{254.0, 0.0, 0.0}, data["redTeams"])
+		assert.Equal(t, []any{0.0, 0.0, 1114.0}, data["blueTeams"])
+		assert.Equal(t, "blue", data["winner"])
+		assert.NotContains(t, data, "redScore")
+	}
+	assert.Equal(t, map[any]bool{"/start": true, "/all": true}, paths)
+
+	arena.NotifyWebhooks(partner.WebhookScoreCommitted, nil)
+	select {
+	case payload = <-payloads:
+		assert.Fail(t, "Unexpected webhook", payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func receiveWebhookPayload(t *testing.T, payloads chan map[string]any) map[string]any {
+	select {
+	case payload := <-payloads:
+		return payload
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timed out waiting for webhook")
+		return map[string]any{}
+	}
+}
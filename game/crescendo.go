@@ -0,0 +1,299 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Game definition for the 2024 game, CRESCENDO.
+
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+type crescendo struct{}
+
+var crescendoRankingPointConfig = RankingPointConfig{
+	WinRankingPoints:  2,
+	TieRankingPoints:  1,
+	LossRankingPoints: 0,
+	BonusRankingPoints: []BonusRankingPoint{
+		{"Melody", func(summary *ScoreSummary) bool { return summary.MelodyBonusRankingPoint }},
+		{"Ensemble", func(summary *ScoreSummary) bool { return summary.EnsembleBonusRankingPoint }},
+	},
+	FoulBonusRankingPoint: "Ensemble",
+}
+
+var crescendoScoringPanelLayout = []ScoringPanelSection{
+	{
+		Name: "Autonomous",
+		Elements: []ScoringPanelElement{
+			{Name: "Leave", Command: "leave", Type: ScoringPanelToggle, Period: ScoringPeriodAuto, PerTeam: true},
+		},
+	},
+	{
+		Name: "Stage",
+		Elements: []ScoringPanelElement{
+			{Name: "Park", Command: "park", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop, PerTeam: true,
+				TargetValue: int(EndgameParked)},
+			{Name: "Stage Left", Command: "onStage", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				PerTeam: true, StageIndex: 0, TargetValue: int(EndgameStageLeft)},
+			{Name: "Center Stage", Command: "onStage", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				PerTeam: true, StageIndex: 1, TargetValue: int(EndgameCenterStage)},
+			{Name: "Stage Right", Command: "onStage", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				PerTeam: true, StageIndex: 2, TargetValue: int(EndgameStageRight)},
+			{Name: "Mic Left", Command: "microphone", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				StageIndex: 0},
+			{Name: "Mic Center", Command: "microphone", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				StageIndex: 1},
+			{Name: "Mic Right", Command: "microphone", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				StageIndex: 2},
+			{Name: "Trap Left", Command: "trap", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop, StageIndex: 0},
+			{Name: "Trap Center", Command: "trap", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop,
+				StageIndex: 1},
+			{Name: "Trap Right", Command: "trap", Type: ScoringPanelToggle, Period: ScoringPeriodTeleop, StageIndex: 2},
+		},
+	},
+}
+
+func init() {
+	// The columns are filled in here rather than above since the ranking points column refers back to the rest of the
+	// configuration.
+	crescendoRankingPointConfig.Columns = []RankingColumn{
+		{"RankingPoints", "RP", crescendoRankingPointConfig.MatchRankingPoints},
+		{"CoopertitionPoints", "Coop", func(summary, opponentSummary *ScoreSummary) int {
+			if summary.CoopertitionBonus {
+				return 1
+			}
+			return 0
+		}},
+		{"MatchPoints", "Match", func(summary, opponentSummary *ScoreSummary) int { return summary.MatchPoints }},
+		{"AutoPoints", "Auto", func(summary, opponentSummary *ScoreSummary) int { return summary.AutoPoints }},
+		{"StagePoints", "Stage", func(summary, opponentSummary *ScoreSummary) int { return summary.StagePoints }},
+	}
+	RegisterGameDefinition(crescendo{})
+}
+
+func (crescendo) Key() string {
+	return "crescendo2024"
+}
+
+func (crescendo) Name() string {
+	return "2024 CRESCENDO"
+}
+
+func (crescendo) DefaultMatchTiming() MatchTimingSettings {
+	return MatchTimingSettings{0, 15, 3, 135, 20, 0}
+}
+
+func (crescendo) SummarizeScore(score, opponentScore *Score) *ScoreSummary {
+	summary := new(ScoreSummary)
+
+	// Leave the score at zero if the alliance was disqualified.
+	if score.PlayoffDq {
+		summary.PlayoffDq = true
+		return summary
+	}
+
+	// Calculate autonomous period points.
+	for _, status := range score.LeaveStatuses {
+		if status {
+			summary.LeavePoints += 2
+		}
+	}
+	autoNotePoints := score.AmpSpeaker.AutoNotePoints()
+	summary.AutoPoints = summary.LeavePoints + autoNotePoints
+
+	// Calculate Amp and Speaker points.
+	summary.AmpPoints = score.AmpSpeaker.AmpPoints()
+	summary.SpeakerPoints = score.AmpSpeaker.SpeakerPoints()
+
+	// Calculate endgame points.
+	robotsByPosition := map[StagePosition]int{StageLeft: 0, CenterStage: 0, StageRight: 0}
+	for _, status := range score.EndgameStatuses {
+		switch status {
+		case EndgameParked:
+			summary.ParkPoints += 1
+		case EndgameStageLeft:
+			summary.OnStagePoints += 3
+			robotsByPosition[StageLeft]++
+		case EndgameCenterStage:
+			summary.OnStagePoints += 3
+			robotsByPosition[CenterStage]++
+		case EndgameStageRight:
+			summary.OnStagePoints += 3
+			robotsByPosition[StageRight]++
+		default:
+		}
+	}
+	totalOnstageRobots := 0
+	for i := 0; i < 3; i++ {
+		stagePosition := StagePosition(i)
+		onstageRobots := robotsByPosition[stagePosition]
+		totalOnstageRobots += onstageRobots
+
+		// Handle Harmony (multiple robots climbing on the same chain).
+		if onstageRobots > 1 {
+			summary.HarmonyPoints += 2 * (onstageRobots - 1)
+		}
+
+		// Handle microphones.
+		if score.MicrophoneStatuses[i] && onstageRobots > 0 {
+			summary.SpotlightPoints += onstageRobots
+		}
+
+		// Handle traps.
+		if score.TrapStatuses[i] {
+			summary.TrapPoints += 5
+		}
+	}
+	summary.StagePoints = summary.ParkPoints + summary.OnStagePoints + summary.HarmonyPoints + summary.SpotlightPoints +
+		summary.TrapPoints
+
+	summary.MatchPoints = summary.LeavePoints + summary.AmpPoints + summary.SpeakerPoints + summary.StagePoints
+
+	// Calculate penalty points.
+	for _, foul := range opponentScore.Fouls {
+		summary.FoulPoints += foul.PointValue()
+		// Store the number of tech fouls since it is used to break ties in playoffs.
+		if foul.IsTechnical {
+			summary.NumOpponentTechFouls++
+		}
+
+		// Check for the opponent fouls that automatically trigger a ranking point.
+		if rule := foul.Rule(); rule != nil && rule.IsRankingPoint {
+			summary.OpponentRankingPointFoul = true
+		}
+	}
+
+	summary.Score = summary.MatchPoints + summary.FoulPoints
+
+	// Calculate bonus ranking points.
+	summary.NumNotes = score.AmpSpeaker.TotalNotesScored()
+	summary.NumNotesGoal = MelodyBonusThresholdWithoutCoop
+	if MelodyBonusThresholdWithCoop > 0 {
+		// A MelodyBonusThresholdWithCoop of 0 disables the coopertition bonus.
+		summary.CoopertitionCriteriaMet = score.AmpSpeaker.CoopActivated
+		summary.CoopertitionBonus = summary.CoopertitionCriteriaMet && opponentScore.AmpSpeaker.CoopActivated
+		if summary.CoopertitionBonus {
+			summary.NumNotesGoal = MelodyBonusThresholdWithCoop
+		}
+	}
+	if summary.NumNotes >= summary.NumNotesGoal {
+		summary.MelodyBonusRankingPoint = true
+	}
+	if summary.StagePoints >= ensembleBonusPointThreshold && totalOnstageRobots >= ensembleBonusRobotThreshold {
+		summary.EnsembleBonusRankingPoint = true
+	}
+
+	summary.FoulRankingPoints = crescendoRankingPointConfig.FoulRankingPoints(summary)
+	summary.BonusRankingPoints = crescendoRankingPointConfig.CountBonusRankingPoints(summary)
+	summary.EnsembleBonusRankingPoint = crescendoRankingPointConfig.IsBonusEarned("Ensemble", summary)
+
+	return summary
+}
+
+func (crescendo) AddScoreSummary(fields *RankingFields, ownScore, opponentScore *ScoreSummary, disqualified bool) {
+	fields.Played += 1
+
+	// Store a random value to be used as the last tiebreaker if necessary.
+	fields.Random = rand.Float64()
+
+	if disqualified {
+		// Don't award any points.
+		fields.Disqualifications += 1
+		return
+	}
+
+	// Assign ranking points and wins/losses/ties.
+	if ownScore.Score > opponentScore.Score {
+		fields.Wins += 1
+	} else if ownScore.Score == opponentScore.Score {
+		fields.Ties += 1
+	} else {
+		fields.Losses += 1
+	}
+	fields.RankingPoints += crescendoRankingPointConfig.MatchRankingPoints(ownScore, opponentScore)
+	fields.FoulRankingPoints += ownScore.FoulRankingPoints
+
+	// Assign tiebreaker points.
+	if ownScore.CoopertitionBonus {
+		fields.CoopertitionPoints++
+	}
+	fields.MatchPoints += ownScore.MatchPoints
+	fields.AutoPoints += ownScore.AutoPoints
+	fields.StagePoints += ownScore.StagePoints
+	crescendoRankingPointConfig.AddColumnValues(fields, ownScore, opponentScore)
+}
+
+func (crescendo) RankingLess(a, b *RankingFields) bool {
+	// Use cross-multiplication to keep it in integer math.
+	if a.RankingPoints*b.Played == b.RankingPoints*a.Played {
+		if a.CoopertitionPoints*b.Played == b.CoopertitionPoints*a.Played {
+			if a.MatchPoints*b.Played == b.MatchPoints*a.Played {
+				if a.AutoPoints*b.Played == b.AutoPoints*a.Played {
+					if a.StagePoints*b.Played == b.StagePoints*a.Played {
+						return a.Random > b.Random
+					}
+					return a.StagePoints*b.Played > b.StagePoints*a.Played
+				}
+				return a.AutoPoints*b.Played > b.AutoPoints*a.Played
+			}
+			return a.MatchPoints*b.Played > b.MatchPoints*a.Played
+		}
+		return a.CoopertitionPoints*b.Played > b.CoopertitionPoints*a.Played
+	}
+	return a.RankingPoints*b.Played > b.RankingPoints*a.Played
+}
+
+func (crescendo) RankingPointConfig() *RankingPointConfig {
+	return &crescendoRankingPointConfig
+}
+
+func (crescendo) Rules() []*Rule {
+	return rules
+}
+
+func (crescendo) AudienceScoreWidgets() []string {
+	return []string{"crescendo_notes.html", "crescendo_amp.html"}
+}
+
+func (crescendo) ScoringPanelLayout() []ScoringPanelSection {
+	return crescendoScoringPanelLayout
+}
+
+func (crescendo) MatchCues(score *Score, matchStartTime, currentTime time.Time) []MatchCue {
+	var cues []MatchCue
+	ampSpeaker := &score.AmpSpeaker
+	inTeleop := currentTime.After(matchStartTime.Add(GetDurationToTeleopStart())) &&
+		currentTime.Before(matchStartTime.Add(GetDurationToTeleopEnd()))
+
+	if amplifiedTimeRemaining := ampSpeaker.AmplifiedTimeRemaining(currentTime); amplifiedTimeRemaining > 0 {
+		remainingSec := int(math.Ceil(amplifiedTimeRemaining))
+		cues = append(
+			cues,
+			MatchCue{HumanPlayerCue, fmt.Sprintf("AMPLIFY active for %ds", remainingSec)},
+			MatchCue{AnnouncerCue, fmt.Sprintf("Speaker AMPLIFIED for %d more seconds", remainingSec)},
+		)
+	} else if inTeleop && ampSpeaker.BankedAmpNotes >= 2 {
+		cues = append(cues, MatchCue{HumanPlayerCue, "Ready to AMPLIFY"})
+	}
+
+	if inTeleop && ampSpeaker.IsCoopWindowOpen(matchStartTime, currentTime) && !ampSpeaker.CoopActivated &&
+		ampSpeaker.BankedAmpNotes >= 1 {
+		coopWindowEnd := matchStartTime.Add(GetDurationToTeleopStart() + coopTeleopWindowSec*time.Second)
+		cues = append(
+			cues,
+			MatchCue{
+				HumanPlayerCue,
+				fmt.Sprintf("Co-op window open for %ds", int(math.Ceil(coopWindowEnd.Sub(currentTime).Seconds()))),
+			},
+		)
+	}
+	if ampSpeaker.CoopActivated {
+		cues = append(cues, MatchCue{AnnouncerCue, "COOPERTITION button pressed"})
+	}
+
+	return cues
+}
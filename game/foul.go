@@ -5,10 +5,20 @@
 
 package game
 
+import "fmt"
+
+// Number of points awarded to the opposing alliance for each type of foul.
+const (
+	FoulPoints     = 2
+	TechFoulPoints = 5
+)
+
 type Foul struct {
 	IsTechnical bool
 	TeamId      int
 	RuleId      int
+	FoulId      int // Identifies the foul within the alliance's list while the match is in progress.
+	Revision    int // Incremented on each edit so that concurrent edits from referee panels can be detected.
 }
 
 // Returns the rule for which the foul was assigned.
@@ -16,11 +26,50 @@ func (foul *Foul) Rule() *Rule {
 	return GetRuleById(foul.RuleId)
 }
 
+// Assigns the rule having the given ID to the foul, or clears it if the ID is zero. The foul takes on the rule's type
+// so that its point value always follows from the rule that was violated.
+func (foul *Foul) AssignRule(ruleId int) error {
+	if ruleId == 0 {
+		foul.RuleId = 0
+		return nil
+	}
+	rule := GetRuleById(ruleId)
+	if rule == nil {
+		return fmt.Errorf("Invalid rule ID %d.", ruleId)
+	}
+	foul.RuleId = rule.Id
+	foul.IsTechnical = rule.IsTechnical
+	return nil
+}
+
+// Returns the card that the foul carries for the offending team, or an empty string if none.
+func (foul *Foul) Card() string {
+	if rule := foul.Rule(); rule != nil {
+		return rule.Card
+	}
+	return ""
+}
+
 // Returns the number of points that the foul adds to the opposing alliance's score.
 func (foul *Foul) PointValue() int {
 	if foul.IsTechnical {
-		return 5
-	} else {
-		return 2
+		return TechFoulPoints
+	}
+	return FoulPoints
+}
+
+// Returns the card that a team should hold after receiving the given card, taking into account the card it already
+// has in the current match and whether it is carrying a yellow card from a previous match. A second yellow card is
+// escalated to a red card, and cards are never downgraded.
+func EscalateCard(currentCard string, hasYellowCard bool, newCard string) string {
+	switch newCard {
+	case "yellow":
+		if currentCard == "yellow" || currentCard == "red" || hasYellowCard {
+			return "red"
+		}
+		return "yellow"
+	case "red":
+		return "red"
 	}
+	return currentCard
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFoulAssignRule(t *testing.T) {
+	foul := Foul{TeamId: 254}
+	assert.Equal(t, FoulPoints, foul.PointValue())
+
+	// Assigning a tech foul rule should make the foul technical.
+	assert.Nil(t, foul.AssignRule(22))
+	assert.Equal(t, 22, foul.RuleId)
+	assert.True(t, foul.IsTechnical)
+	assert.Equal(t, TechFoulPoints, foul.PointValue())
+	assert.Equal(t, TechFoulPoints, foul.Rule().PointValue())
+	assert.Equal(t, "yellow", foul.Card())
+
+	assert.Nil(t, foul.AssignRule(1))
+	assert.False(t, foul.IsTechnical)
+	assert.Equal(t, FoulPoints, foul.PointValue())
+	assert.Equal(t, "", foul.Card())
+
+	err := foul.AssignRule(1000)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid rule ID 1000.", err.Error())
+	}
+	assert.Equal(t, 1, foul.RuleId)
+
+	assert.Nil(t, foul.AssignRule(0))
+	assert.Equal(t, 0, foul.RuleId)
+	assert.Nil(t, foul.Rule())
+	assert.Equal(t, "", foul.Card())
+}
+
+func TestEscalateCard(t *testing.T) {
+	assert.Equal(t, "", EscalateCard("", false, ""))
+	assert.Equal(t, "yellow", EscalateCard("yellow", false, ""))
+	assert.Equal(t, "yellow", EscalateCard("", false, "yellow"))
+	assert.Equal(t, "red", EscalateCard("", true, "yellow"))
+	assert.Equal(t, "red", EscalateCard("yellow", false, "yellow"))
+	assert.Equal(t, "red", EscalateCard("red", false, "yellow"))
+	assert.Equal(t, "red", EscalateCard("", false, "red"))
+	assert.Equal(t, "red", EscalateCard("red", false, ""))
+}
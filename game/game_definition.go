@@ -0,0 +1,123 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Interface through which the season-specific scoring, ranking, and timing rules are looked up, allowing the game to
+// be selected in the event settings.
+
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Key of the game that is used when none has been selected.
+const DefaultGameKey = "crescendo2024"
+
+// Defines the rules of a particular game. Each game should implement this interface in its own file and register
+// itself from an init() function.
+type GameDefinition interface {
+	// Returns the unique identifier under which the game is stored in the event settings.
+	Key() string
+
+	// Returns the human-readable name of the game.
+	Name() string
+
+	// Returns the default durations of the match periods.
+	DefaultMatchTiming() MatchTimingSettings
+
+	// Calculates the summary fields used for ranking and display from the given alliance scores.
+	SummarizeScore(score, opponentScore *Score) *ScoreSummary
+
+	// Accumulates the result of a single match into the given team's ranking fields.
+	AddScoreSummary(fields *RankingFields, ownScore, opponentScore *ScoreSummary, disqualified bool)
+
+	// Returns true if a team with the first set of ranking fields should be ranked ahead of one with the second set.
+	RankingLess(a, b *RankingFields) bool
+
+	// Returns the ranking points awarded for match outcomes and bonuses, and the ranking fields to report.
+	RankingPointConfig() *RankingPointConfig
+
+	// Returns all rules that carry point penalties.
+	Rules() []*Rule
+
+	// Returns the file names of the templates under templates/audience_score_widgets that make up the game-specific
+	// part of the audience display's realtime score bar, in order from the alliance's avatars towards its score.
+	AudienceScoreWidgets() []string
+
+	// Returns the sections of elements that make up the scoring panel, in the order in which they are shown.
+	ScoringPanelLayout() []ScoringPanelSection
+
+	// Returns the cues to show to an alliance's human players and the announcer, given the alliance's score at the
+	// given time into a match in progress.
+	MatchCues(score *Score, matchStartTime, currentTime time.Time) []MatchCue
+}
+
+var gameDefinitions = make(map[string]GameDefinition)
+var currentGame GameDefinition
+
+// Makes the given game available for selection. Panics if a game with the same key has already been registered.
+func RegisterGameDefinition(gameDefinition GameDefinition) {
+	if _, ok := gameDefinitions[gameDefinition.Key()]; ok {
+		panic(fmt.Sprintf("Game definition %s is already registered", gameDefinition.Key()))
+	}
+	columnNames := make(map[string]bool)
+	for _, column := range gameDefinition.RankingPointConfig().Columns {
+		if column.Name == "" || column.MatchValue == nil || columnNames[column.Name] {
+			panic(fmt.Sprintf("Game definition %s has invalid ranking column %s", gameDefinition.Key(), column.Name))
+		}
+		columnNames[column.Name] = true
+	}
+	rankingPointConfig := gameDefinition.RankingPointConfig()
+	foulBonus := rankingPointConfig.FoulBonusRankingPoint
+	if foulBonus != "" && rankingPointConfig.getBonusRankingPoint(foulBonus) == nil {
+		panic(fmt.Sprintf("Game definition %s has invalid foul bonus ranking point %s", gameDefinition.Key(), foulBonus))
+	}
+	if err := validateScoringPanelLayout(gameDefinition.ScoringPanelLayout()); err != nil {
+		panic(fmt.Sprintf("Game definition %s has invalid scoring panel layout: %s", gameDefinition.Key(), err))
+	}
+	gameDefinitions[gameDefinition.Key()] = gameDefinition
+}
+
+// Returns the registered game having the given key.
+func GetGameDefinition(key string) (GameDefinition, error) {
+	gameDefinition, ok := gameDefinitions[key]
+	if !ok {
+		return nil, fmt.Errorf("Invalid game: %s", key)
+	}
+	return gameDefinition, nil
+}
+
+// Returns all registered games, sorted by key.
+func GetAllGameDefinitions() []GameDefinition {
+	var allGameDefinitions []GameDefinition
+	for _, gameDefinition := range gameDefinitions {
+		allGameDefinitions = append(allGameDefinitions, gameDefinition)
+	}
+	sort.Slice(allGameDefinitions, func(i, j int) bool {
+		return allGameDefinitions[i].Key() < allGameDefinitions[j].Key()
+	})
+	return allGameDefinitions
+}
+
+// Returns the game currently in use, falling back to the default game if none has been selected.
+func CurrentGame() GameDefinition {
+	if currentGame == nil {
+		return gameDefinitions[DefaultGameKey]
+	}
+	return currentGame
+}
+
+// Sets the game currently in use to the one having the given key, or the default game if the key is blank.
+func SetCurrentGame(key string) error {
+	if key == "" {
+		key = DefaultGameKey
+	}
+	gameDefinition, err := GetGameDefinition(key)
+	if err != nil {
+		return err
+	}
+	currentGame = gameDefinition
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Minimal game in which the score is the number of robots that left the starting zone.
+type fakeGame struct{}
+
+func (fakeGame) Key() string {
+	return "fake"
+}
+
+func (fakeGame) Name() string {
+	return "Fake Game"
+}
+
+func (fakeGame) DefaultMatchTiming() MatchTimingSettings {
+	return MatchTimingSettings{AutoDurationSec: 10, TeleopDurationSec: 50}
+}
+
+func (fakeGame) SummarizeScore(score, opponentScore *Score) *ScoreSummary {
+	summary := new(ScoreSummary)
+	for _, status := range score.LeaveStatuses {
+		if status {
+			summary.Score++
+		}
+	}
+	return summary
+}
+
+func (fakeGame) AddScoreSummary(fields *RankingFields, ownScore, opponentScore *ScoreSummary, disqualified bool) {
+	fields.Played++
+	fields.MatchPoints += ownScore.Score
+	fakeGame{}.RankingPointConfig().AddColumnValues(fields, ownScore, opponentScore)
+}
+
+func (fakeGame) RankingLess(a, b *RankingFields) bool {
+	return a.MatchPoints > b.MatchPoints
+}
+
+func (fakeGame) RankingPointConfig() *RankingPointConfig {
+	return &RankingPointConfig{
+		WinRankingPoints: 3,
+		Columns: []RankingColumn{
+			{"Margin", "Diff", func(summary, opponentSummary *ScoreSummary) int {
+				return summary.Score - opponentSummary.Score
+			}},
+		},
+	}
+}
+
+type invalidScoringPanelGame struct {
+	fakeGame
+}
+
+func (invalidScoringPanelGame) Key() string {
+	return "invalidPanel"
+}
+
+func (invalidScoringPanelGame) ScoringPanelLayout() []ScoringPanelSection {
+	return []ScoringPanelSection{{Elements: []ScoringPanelElement{{Name: "Spin", Command: "spin", Type: "dial"}}}}
+}
+
+type invalidColumnGame struct {
+	fakeGame
+}
+
+func (invalidColumnGame) Key() string {
+	return "invalid"
+}
+
+func (invalidColumnGame) RankingPointConfig() *RankingPointConfig {
+	return &RankingPointConfig{Columns: []RankingColumn{{"Random", "Rand", nil}}}
+}
+
+type invalidFoulBonusGame struct {
+	fakeGame
+}
+
+func (invalidFoulBonusGame) Key() string {
+	return "invalid"
+}
+
+func (invalidFoulBonusGame) RankingPointConfig() *RankingPointConfig {
+	return &RankingPointConfig{FoulBonusRankingPoint: "Harmony"}
+}
+
+func (fakeGame) Rules() []*Rule {
+	return []*Rule{{Id: 1, RuleNumber: "F1", Description: "Be nice."}}
+}
+
+func (fakeGame) AudienceScoreWidgets() []string {
+	return nil
+}
+
+func (fakeGame) ScoringPanelLayout() []ScoringPanelSection {
+	return []ScoringPanelSection{
+		{
+			Name: "Game",
+			Elements: []ScoringPanelElement{
+				{Name: "Leave", Command: "leave", Type: ScoringPanelToggle, Period: ScoringPeriodAuto, PerTeam: true},
+				{Name: "Balls", Command: "balls", Type: ScoringPanelCounter, Period: ScoringPeriodTeleop},
+				{Name: "Hang Time", Command: "hang", Type: ScoringPanelTimer, Period: ScoringPeriodEndgame,
+					PerTeam: true},
+			},
+		},
+	}
+}
+
+func (fakeGame) MatchCues(score *Score, matchStartTime, currentTime time.Time) []MatchCue {
+	return nil
+}
+
+func TestGameDefinitionRegistry(t *testing.T) {
+	assert.Equal(t, DefaultGameKey, CurrentGame().Key())
+	gameDefinition, err := GetGameDefinition("crescendo2024")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "2024 CRESCENDO", gameDefinition.Name())
+		assert.Equal(t, 135, gameDefinition.DefaultMatchTiming().TeleopDurationSec)
+	}
+	_, err = GetGameDefinition("blorpy")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid game: blorpy", err.Error())
+	}
+	assert.NotNil(t, SetCurrentGame("blorpy"))
+	assert.Equal(t, DefaultGameKey, CurrentGame().Key())
+	assert.Panics(t, func() { RegisterGameDefinition(crescendo{}) })
+	assert.Panics(t, func() { RegisterGameDefinition(invalidColumnGame{}) })
+	assert.Panics(t, func() { RegisterGameDefinition(invalidFoulBonusGame{}) })
+	assert.Panics(t, func() { RegisterGameDefinition(invalidScoringPanelGame{}) })
+}
+
+func TestCustomGameDefinition(t *testing.T) {
+	RegisterGameDefinition(fakeGame{})
+	defer delete(gameDefinitions, "fake")
+	defer SetCurrentGame("")
+
+	allGameDefinitions := GetAllGameDefinitions()
+	if assert.Equal(t, 2, len(allGameDefinitions)) {
+		assert.Equal(t, "crescendo2024", allGameDefinitions[0].Key())
+		assert.Equal(t, "fake", allGameDefinitions[1].Key())
+	}
+
+	assert.Nil(t, SetCurrentGame("fake"))
+	assert.Equal(t, "fake", CurrentGame().Key())
+	redScore := &Score{LeaveStatuses: [3]bool{true, false, true}}
+	blueScore := &Score{LeaveStatuses: [3]bool{false, false, true}}
+	assert.Equal(t, 2, redScore.Summarize(blueScore).Score)
+	assert.Equal(t, "F1", GetRuleById(1).RuleNumber)
+
+	rankings := Rankings{{TeamId: 254}, {TeamId: 1114}}
+	rankings[0].AddScoreSummary(blueScore.Summarize(redScore), redScore.Summarize(blueScore), false)
+	rankings[1].AddScoreSummary(redScore.Summarize(blueScore), blueScore.Summarize(redScore), false)
+	assert.True(t, rankings.Less(1, 0))
+	assert.Equal(t, 0, rankings[0].RankingPoints)
+	assert.Equal(t, []int{-1}, CurrentRankingPointConfig().ColumnValues(&rankings[0].RankingFields))
+	assert.Equal(t, []int{1}, CurrentRankingPointConfig().ColumnValues(&rankings[1].RankingFields))
+
+	assert.Nil(t, SetCurrentGame(""))
+	assert.Equal(t, DefaultGameKey, CurrentGame().Key())
+	assert.Equal(t, "G211", GetRuleById(1).RuleNumber)
+}
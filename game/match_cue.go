@@ -0,0 +1,19 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model representing a short prompt for the human players or announcer about what is happening in a match.
+
+package game
+
+// Identifies who a cue is meant for.
+type CueAudience string
+
+const (
+	HumanPlayerCue CueAudience = "humanPlayer"
+	AnnouncerCue   CueAudience = "announcer"
+)
+
+type MatchCue struct {
+	Audience CueAudience
+	Text     string
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCrescendoMatchCues(t *testing.T) {
+	MelodyBonusThresholdWithCoop = 15
+	var score Score
+	teleopTime := func(sec float64) time.Time {
+		return matchStartTime.Add(GetDurationToTeleopStart() + time.Duration(sec*float64(time.Second)))
+	}
+
+	// Nothing should be cued before anything is banked in the amp.
+	assert.Empty(t, crescendo{}.MatchCues(&score, matchStartTime, matchStartTime.Add(5*time.Second)))
+	assert.Empty(t, crescendo{}.MatchCues(&score, matchStartTime, teleopTime(10)))
+
+	score.AmpSpeaker.BankedAmpNotes = 1
+	assert.Equal(
+		t,
+		[]MatchCue{{HumanPlayerCue, "Co-op window open for 35s"}},
+		crescendo{}.MatchCues(&score, matchStartTime, teleopTime(10)),
+	)
+	assert.Empty(t, crescendo{}.MatchCues(&score, matchStartTime, teleopTime(50)))
+
+	score.AmpSpeaker.BankedAmpNotes = 2
+	score.AmpSpeaker.CoopActivated = true
+	assert.Equal(
+		t,
+		[]MatchCue{{HumanPlayerCue, "Ready to AMPLIFY"}, {AnnouncerCue, "COOPERTITION button pressed"}},
+		crescendo{}.MatchCues(&score, matchStartTime, teleopTime(50)),
+	)
+
+	score.AmpSpeaker.BankedAmpNotes = 0
+	score.AmpSpeaker.LastAmplifiedTime = teleopTime(50)
+	assert.Equal(
+		t,
+		[]MatchCue{
+			{HumanPlayerCue, "AMPLIFY active for 8s"},
+			{AnnouncerCue, "Speaker AMPLIFIED for 8 more seconds"},
+			{AnnouncerCue, "COOPERTITION button pressed"},
+		},
+		crescendo{}.MatchCues(&score, matchStartTime, teleopTime(52.5)),
+	)
+}
@@ -5,55 +5,154 @@
 
 package game
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type MatchSound struct {
 	Name          string
 	FileExtension string
 	MatchTimeSec  float64
 }
 
+// Identifies the point in the match from which the offset of a sound cue is measured.
+type MatchSoundAnchor string
+
+const (
+	SoundAnchorAutoStart   MatchSoundAnchor = "autoStart"
+	SoundAnchorAutoEnd     MatchSoundAnchor = "autoEnd"
+	SoundAnchorTeleopStart MatchSoundAnchor = "teleopStart"
+	SoundAnchorWarning     MatchSoundAnchor = "warning"
+	SoundAnchorTeleopEnd   MatchSoundAnchor = "teleopEnd"
+)
+
+// Represents a sound that is played automatically at a given number of seconds after (or before, if negative) a point
+// in the match.
+type MatchSoundCue struct {
+	Name      string
+	Anchor    MatchSoundAnchor
+	OffsetSec int
+}
+
+// Names of the sound files available to be cued; all are expected to be in WAV format.
+var MatchSoundNames = []string{"start", "end", "resume", "warning", "warning_guitar", "abort", "match_result", "foul"}
+
+// The standard schedule of sounds played during a match.
+var DefaultMatchSoundCues = []MatchSoundCue{
+	{"start", SoundAnchorAutoStart, 0},
+	{"end", SoundAnchorAutoEnd, 0},
+	{"resume", SoundAnchorTeleopStart, 0},
+	{"warning_guitar", SoundAnchorWarning, 0},
+	{"end", SoundAnchorTeleopEnd, 0},
+}
+
+// Starts out with the default schedule until it is overridden by the event settings.
+var MatchSoundCues = DefaultMatchSoundCues
+
 // List of sounds and how many seconds into the match they are played. A negative time indicates that the sound can only
 // be triggered explicitly.
 var MatchSounds []*MatchSound
 
 func UpdateMatchSounds() {
-	MatchSounds = []*MatchSound{
-		{
-			"start",
-			"wav",
-			0,
-		},
-		{
-			"end",
-			"wav",
-			float64(MatchTiming.AutoDurationSec),
-		},
-		{
-			"resume",
-			"wav",
-			float64(MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec),
-		},
-		{
-			"warning_guitar",
-			"wav",
-			float64(
-				MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec -
-					MatchTiming.WarningRemainingDurationSec,
-			),
-		},
-		{
-			"end",
-			"wav",
-			float64(MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec),
-		},
-		{
+	MatchSounds = nil
+	for _, cue := range MatchSoundCues {
+		MatchSounds = append(MatchSounds, &MatchSound{cue.Name, "wav", cue.matchTimeSec()})
+	}
+	MatchSounds = append(
+		MatchSounds,
+		&MatchSound{
 			"abort",
 			"wav",
 			-1,
 		},
-		{
+		&MatchSound{
 			"match_result",
 			"wav",
 			-1,
 		},
+		&MatchSound{
+			"foul",
+			"wav",
+			-1,
+		},
+	)
+}
+
+// Parses a sound cue schedule having one cue per line in the format "name,anchor,offsetSec".
+func ParseMatchSoundCues(text string) ([]MatchSoundCue, error) {
+	var cues []MatchSoundCue
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Invalid sound cue '%s'; expected 'name,anchor,offsetSec'.", line)
+		}
+		cue := MatchSoundCue{Name: strings.TrimSpace(fields[0]), Anchor: MatchSoundAnchor(strings.TrimSpace(fields[1]))}
+		if !isValidMatchSoundName(cue.Name) {
+			return nil, fmt.Errorf("Invalid sound name '%s' in sound cue '%s'.", cue.Name, line)
+		}
+		if !cue.Anchor.isValid() {
+			return nil, fmt.Errorf("Invalid anchor '%s' in sound cue '%s'.", cue.Anchor, line)
+		}
+		offsetSec, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid offset '%s' in sound cue '%s'.", fields[2], line)
+		}
+		if cue.Anchor == SoundAnchorAutoStart && offsetSec < 0 {
+			return nil, fmt.Errorf("Sound cue '%s' cannot be played before the start of the match.", line)
+		}
+		cue.OffsetSec = offsetSec
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// Formats the given sound cue schedule in the format accepted by ParseMatchSoundCues.
+func FormatMatchSoundCues(cues []MatchSoundCue) string {
+	lines := make([]string, len(cues))
+	for i, cue := range cues {
+		lines[i] = fmt.Sprintf("%s,%s,%d", cue.Name, cue.Anchor, cue.OffsetSec)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the number of seconds into the match at which the cue should be played, given the current match timing.
+func (cue *MatchSoundCue) matchTimeSec() float64 {
+	var anchorSec int
+	switch cue.Anchor {
+	case SoundAnchorAutoStart:
+		anchorSec = 0
+	case SoundAnchorAutoEnd:
+		anchorSec = MatchTiming.AutoDurationSec
+	case SoundAnchorTeleopStart:
+		anchorSec = MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec
+	case SoundAnchorWarning:
+		anchorSec = MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec -
+			MatchTiming.WarningRemainingDurationSec
+	case SoundAnchorTeleopEnd:
+		anchorSec = MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec
+	}
+	return float64(anchorSec + cue.OffsetSec)
+}
+
+func (anchor MatchSoundAnchor) isValid() bool {
+	switch anchor {
+	case SoundAnchorAutoStart, SoundAnchorAutoEnd, SoundAnchorTeleopStart, SoundAnchorWarning, SoundAnchorTeleopEnd:
+		return true
+	}
+	return false
+}
+
+func isValidMatchSoundName(name string) bool {
+	for _, soundName := range MatchSoundNames {
+		if name == soundName {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,72 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUpdateMatchSounds(t *testing.T) {
+	originalTiming, originalCues := MatchTiming, MatchSoundCues
+	defer func() {
+		MatchTiming, MatchSoundCues = originalTiming, originalCues
+		UpdateMatchSounds()
+	}()
+	MatchTiming = MatchTimingSettings{AutoDurationSec: 15, PauseDurationSec: 3, TeleopDurationSec: 135,
+		WarningRemainingDurationSec: 20}
+
+	MatchSoundCues = DefaultMatchSoundCues
+	UpdateMatchSounds()
+	if assert.Equal(t, 8, len(MatchSounds)) {
+		assert.Equal(t, MatchSound{"start", "wav", 0}, *MatchSounds[0])
+		assert.Equal(t, MatchSound{"end", "wav", 15}, *MatchSounds[1])
+		assert.Equal(t, MatchSound{"resume", "wav", 18}, *MatchSounds[2])
+		assert.Equal(t, MatchSound{"warning_guitar", "wav", 133}, *MatchSounds[3])
+		assert.Equal(t, MatchSound{"end", "wav", 153}, *MatchSounds[4])
+		assert.Equal(t, MatchSound{"abort", "wav", -1}, *MatchSounds[5])
+		assert.Equal(t, MatchSound{"match_result", "wav", -1}, *MatchSounds[6])
+		assert.Equal(t, MatchSound{"foul", "wav", -1}, *MatchSounds[7])
+	}
+
+	MatchSoundCues = []MatchSoundCue{{"warning", SoundAnchorTeleopEnd, -30}, {"start", SoundAnchorAutoEnd, 2}}
+	MatchTiming.TeleopDurationSec = 60
+	UpdateMatchSounds()
+	if assert.Equal(t, 5, len(MatchSounds)) {
+		assert.Equal(t, MatchSound{"warning", "wav", 48}, *MatchSounds[0])
+		assert.Equal(t, MatchSound{"start", "wav", 17}, *MatchSounds[1])
+	}
+}
+
+func TestParseMatchSoundCues(t *testing.T) {
+	cues, err := ParseMatchSoundCues(FormatMatchSoundCues(DefaultMatchSoundCues))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultMatchSoundCues, cues)
+
+	cues, err = ParseMatchSoundCues(" warning , teleopEnd, -10\r\n\nend,autoEnd,0\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []MatchSoundCue{{"warning", SoundAnchorTeleopEnd, -10}, {"end", SoundAnchorAutoEnd, 0}}, cues)
+	assert.Equal(t, "warning,teleopEnd,-10\nend,autoEnd,0", FormatMatchSoundCues(cues))
+
+	_, err = ParseMatchSoundCues("start,autoStart")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "expected 'name,anchor,offsetSec'")
+	}
+	_, err = ParseMatchSoundCues("blorpy,autoStart,0")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid sound name 'blorpy'")
+	}
+	_, err = ParseMatchSoundCues("start,halftime,0")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid anchor 'halftime'")
+	}
+	_, err = ParseMatchSoundCues("start,autoEnd,soon")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid offset 'soon'")
+	}
+	_, err = ParseMatchSoundCues("start,autoStart,-1")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot be played before the start of the match")
+	}
+}
@@ -14,14 +14,17 @@ const (
 	coopTeleopWindowSec            = 45
 )
 
-var MatchTiming = struct {
+type MatchTimingSettings struct {
 	WarmupDurationSec           int
 	AutoDurationSec             int
 	PauseDurationSec            int
 	TeleopDurationSec           int
 	WarningRemainingDurationSec int
 	TimeoutDurationSec          int
-}{0, 15, 3, 135, 20, 0}
+}
+
+// Starts out with the default game's timing until it is overridden by the event settings.
+var MatchTiming = crescendo{}.DefaultMatchTiming()
 
 func GetDurationToAutoEnd() time.Duration {
 	return time.Duration(MatchTiming.WarmupDurationSec+MatchTiming.AutoDurationSec) * time.Second
@@ -18,7 +18,10 @@ type RankingFields struct {
 	Losses             int
 	Ties               int
 	Disqualifications  int
+	NoShows            int
+	FoulRankingPoints  int // Ranking points earned only because of opponent fouls; already included in RankingPoints.
 	Played             int
+	ColumnTotals       map[string]int // Running totals of the game's ranking columns, keyed by column name.
 }
 
 type Ranking struct {
@@ -30,37 +33,17 @@ type Ranking struct {
 
 type Rankings []Ranking
 
+// Updates the ranking fields with the result of a match according to the rules of the current game.
 func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentScore *ScoreSummary, disqualified bool) {
-	fields.Played += 1
+	CurrentGame().AddScoreSummary(fields, ownScore, opponentScore, disqualified)
+}
 
-	// Store a random value to be used as the last tiebreaker if necessary.
+// Updates the ranking fields for a match in which the team didn't show up, which counts as played but earns nothing,
+// without counting against the team as a disqualification.
+func (fields *RankingFields) AddNoShow() {
+	fields.Played += 1
+	fields.NoShows += 1
 	fields.Random = rand.Float64()
-
-	if disqualified {
-		// Don't award any points.
-		fields.Disqualifications += 1
-		return
-	}
-
-	// Assign ranking points and wins/losses/ties.
-	if ownScore.Score > opponentScore.Score {
-		fields.RankingPoints += 2
-		fields.Wins += 1
-	} else if ownScore.Score == opponentScore.Score {
-		fields.RankingPoints += 1
-		fields.Ties += 1
-	} else {
-		fields.Losses += 1
-	}
-	fields.RankingPoints += ownScore.BonusRankingPoints
-
-	// Assign tiebreaker points.
-	if ownScore.CoopertitionBonus {
-		fields.CoopertitionPoints++
-	}
-	fields.MatchPoints += ownScore.MatchPoints
-	fields.AutoPoints += ownScore.AutoPoints
-	fields.StagePoints += ownScore.StagePoints
 }
 
 // Helper function to implement the required interface for Sort.
@@ -70,26 +53,7 @@ func (rankings Rankings) Len() int {
 
 // Helper function to implement the required interface for Sort.
 func (rankings Rankings) Less(i, j int) bool {
-	a := rankings[i]
-	b := rankings[j]
-
-	// Use cross-multiplication to keep it in integer math.
-	if a.RankingPoints*b.Played == b.RankingPoints*a.Played {
-		if a.CoopertitionPoints*b.Played == b.CoopertitionPoints*a.Played {
-			if a.MatchPoints*b.Played == b.MatchPoints*a.Played {
-				if a.AutoPoints*b.Played == b.AutoPoints*a.Played {
-					if a.StagePoints*b.Played == b.StagePoints*a.Played {
-						return a.Random > b.Random
-					}
-					return a.StagePoints*b.Played > b.StagePoints*a.Played
-				}
-				return a.AutoPoints*b.Played > b.AutoPoints*a.Played
-			}
-			return a.MatchPoints*b.Played > b.MatchPoints*a.Played
-		}
-		return a.CoopertitionPoints*b.Played > b.CoopertitionPoints*a.Played
-	}
-	return a.RankingPoints*b.Played > b.RankingPoints*a.Played
+	return CurrentGame().RankingLess(&rankings[i].RankingFields, &rankings[j].RankingFields)
 }
 
 // Helper function to implement the required interface for Sort.
@@ -38,36 +38,72 @@ func TestAddScoreSummary(t *testing.T) {
 
 	// Add a loss.
 	rankingFields.AddScoreSummary(redSummary, blueSummary, false)
-	assert.Equal(t, RankingFields{1, 0, 67, 30, 19, 0.9451961492941164, 0, 1, 0, 0, 1}, rankingFields)
+	assert.Equal(
+		t,
+		RankingFields{
+			1, 0, 67, 30, 19, 0.9451961492941164, 0, 1, 0, 0, 0, 0, 1,
+			map[string]int{
+				"RankingPoints": 1, "CoopertitionPoints": 0, "MatchPoints": 67, "AutoPoints": 30, "StagePoints": 19,
+			},
+		},
+		rankingFields,
+	)
 
 	// Add a win.
 	rankingFields.AddScoreSummary(blueSummary, redSummary, false)
-	assert.Equal(t, RankingFields{4, 1, 128, 46, 33, 0.24496508529377975, 1, 1, 0, 0, 2}, rankingFields)
+	assert.Equal(
+		t,
+		RankingFields{
+			4, 1, 128, 46, 33, 0.24496508529377975, 1, 1, 0, 0, 0, 0, 2,
+			map[string]int{
+				"RankingPoints": 4, "CoopertitionPoints": 1, "MatchPoints": 128, "AutoPoints": 46, "StagePoints": 33,
+			},
+		},
+		rankingFields,
+	)
 
 	// Add a tie.
 	rankingFields.AddScoreSummary(redSummary, redSummary, false)
-	assert.Equal(t, RankingFields{6, 1, 195, 76, 52, 0.6559562651954052, 1, 1, 1, 0, 3}, rankingFields)
+	assert.Equal(
+		t,
+		RankingFields{
+			6, 1, 195, 76, 52, 0.6559562651954052, 1, 1, 1, 0, 0, 0, 3,
+			map[string]int{
+				"RankingPoints": 6, "CoopertitionPoints": 1, "MatchPoints": 195, "AutoPoints": 76, "StagePoints": 52,
+			},
+		},
+		rankingFields,
+	)
 
 	// Add a disqualification.
 	rankingFields.AddScoreSummary(blueSummary, redSummary, true)
-	assert.Equal(t, RankingFields{6, 1, 195, 76, 52, 0.05434383959970039, 1, 1, 1, 1, 4}, rankingFields)
+	assert.Equal(
+		t,
+		RankingFields{
+			6, 1, 195, 76, 52, 0.05434383959970039, 1, 1, 1, 1, 0, 0, 4,
+			map[string]int{
+				"RankingPoints": 6, "CoopertitionPoints": 1, "MatchPoints": 195, "AutoPoints": 76, "StagePoints": 52,
+			},
+		},
+		rankingFields,
+	)
 }
 
 func TestSortRankings(t *testing.T) {
 	// Check tiebreakers.
 	rankings := make(Rankings, 12)
-	rankings[0] = Ranking{1, 0, 0, RankingFields{50, 50, 50, 50, 50, 0.49, 3, 2, 1, 0, 10}}
-	rankings[1] = Ranking{2, 0, 0, RankingFields{50, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 10}}
-	rankings[2] = Ranking{3, 0, 0, RankingFields{50, 50, 50, 50, 49, 0.50, 3, 2, 1, 0, 10}}
-	rankings[3] = Ranking{4, 0, 0, RankingFields{50, 50, 50, 50, 51, 0.50, 3, 2, 1, 0, 10}}
-	rankings[4] = Ranking{5, 0, 0, RankingFields{50, 50, 50, 49, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[5] = Ranking{6, 0, 0, RankingFields{50, 50, 50, 51, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[6] = Ranking{7, 0, 0, RankingFields{50, 50, 49, 50, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[7] = Ranking{8, 0, 0, RankingFields{50, 50, 51, 50, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[8] = Ranking{9, 0, 0, RankingFields{50, 49, 50, 50, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[9] = Ranking{10, 0, 0, RankingFields{50, 51, 50, 50, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[10] = Ranking{11, 0, 0, RankingFields{49, 50, 50, 50, 50, 0.50, 3, 2, 1, 0, 10}}
-	rankings[11] = Ranking{12, 0, 0, RankingFields{51, 50, 50, 50, 50, 0.50, 3, 2, 1, 0, 10}}
+	rankings[0] = Ranking{1, 0, 0, RankingFields{50, 50, 50, 50, 50, 0.49, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[1] = Ranking{2, 0, 0, RankingFields{50, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[2] = Ranking{3, 0, 0, RankingFields{50, 50, 50, 50, 49, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[3] = Ranking{4, 0, 0, RankingFields{50, 50, 50, 50, 51, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[4] = Ranking{5, 0, 0, RankingFields{50, 50, 50, 49, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[5] = Ranking{6, 0, 0, RankingFields{50, 50, 50, 51, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[6] = Ranking{7, 0, 0, RankingFields{50, 50, 49, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[7] = Ranking{8, 0, 0, RankingFields{50, 50, 51, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[8] = Ranking{9, 0, 0, RankingFields{50, 49, 50, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[9] = Ranking{10, 0, 0, RankingFields{50, 51, 50, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[10] = Ranking{11, 0, 0, RankingFields{49, 50, 50, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
+	rankings[11] = Ranking{12, 0, 0, RankingFields{51, 50, 50, 50, 50, 0.50, 3, 2, 1, 0, 0, 0, 10, nil}}
 	sort.Sort(rankings)
 	assert.Equal(t, 12, rankings[0].TeamId)
 	assert.Equal(t, 10, rankings[1].TeamId)
@@ -84,9 +120,9 @@ func TestSortRankings(t *testing.T) {
 
 	// Check with unequal number of matches played.
 	rankings = make(Rankings, 3)
-	rankings[0] = Ranking{1, 0, 0, RankingFields{10, 25, 25, 25, 25, 0.49, 3, 2, 1, 0, 5}}
-	rankings[1] = Ranking{2, 0, 0, RankingFields{19, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 9}}
-	rankings[2] = Ranking{3, 0, 0, RankingFields{20, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 10}}
+	rankings[0] = Ranking{1, 0, 0, RankingFields{10, 25, 25, 25, 25, 0.49, 3, 2, 1, 0, 0, 0, 5, nil}}
+	rankings[1] = Ranking{2, 0, 0, RankingFields{19, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 0, 0, 9, nil}}
+	rankings[2] = Ranking{3, 0, 0, RankingFields{20, 50, 50, 50, 50, 0.51, 3, 2, 1, 0, 0, 0, 10, nil}}
 	sort.Sort(rankings)
 	assert.Equal(t, 2, rankings[0].TeamId)
 	assert.Equal(t, 3, rankings[1].TeamId)
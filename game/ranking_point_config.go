@@ -0,0 +1,123 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model describing how a game awards ranking points and which ranking fields it reports.
+
+package game
+
+// Describes how ranking points are awarded in a game and which ranking fields are shown alongside them.
+type RankingPointConfig struct {
+	WinRankingPoints   int
+	TieRankingPoints   int
+	LossRankingPoints  int
+	BonusRankingPoints []BonusRankingPoint
+	Columns            []RankingColumn
+
+	// Name of the bonus ranking point that an alliance is credited with when its opponent commits a foul for a rule
+	// flagged IsRankingPoint, or blank if such a foul instead awards a ranking point of its own.
+	FoulBonusRankingPoint string
+}
+
+// Represents a ranking point that an alliance earns by reaching a scoring goal, regardless of the match outcome.
+type BonusRankingPoint struct {
+	Name     string
+	IsEarned func(summary *ScoreSummary) bool `json:"-"`
+}
+
+// Represents a game-specific ranking field shown in the rankings reports and displays, in order of precedence.
+type RankingColumn struct {
+	Name   string // Key of the column's running total within RankingFields.ColumnTotals, also used as the CSV header.
+	Header string // Abbreviated name used in table headers.
+
+	// Returns the amount that a single match adds to the column for the alliance having the given score summaries.
+	MatchValue func(summary, opponentSummary *ScoreSummary) int `json:"-"`
+}
+
+// Returns the ranking point configuration of the current game.
+func CurrentRankingPointConfig() *RankingPointConfig {
+	return CurrentGame().RankingPointConfig()
+}
+
+// Returns the number of ranking points awarded for the given match outcome, excluding any bonus ranking points.
+func (config *RankingPointConfig) OutcomeRankingPoints(ownScore, opponentScore int) int {
+	if ownScore > opponentScore {
+		return config.WinRankingPoints
+	} else if ownScore == opponentScore {
+		return config.TieRankingPoints
+	}
+	return config.LossRankingPoints
+}
+
+// Returns the number of bonus ranking points earned by the alliance having the given score summary, including any
+// credited to it because of its opponent's fouls.
+func (config *RankingPointConfig) CountBonusRankingPoints(summary *ScoreSummary) int {
+	count := 0
+	for _, bonus := range config.BonusRankingPoints {
+		if bonus.IsEarned(summary) {
+			count++
+		}
+	}
+	return count + config.FoulRankingPoints(summary)
+}
+
+// Returns the number of ranking points that the alliance having the given score summary earns only because its
+// opponent committed a foul for a rule flagged IsRankingPoint; a foul that credits a bonus the alliance earned anyway
+// is worth nothing extra.
+func (config *RankingPointConfig) FoulRankingPoints(summary *ScoreSummary) int {
+	if !summary.OpponentRankingPointFoul {
+		return 0
+	}
+	if bonus := config.getBonusRankingPoint(config.FoulBonusRankingPoint); bonus != nil && bonus.IsEarned(summary) {
+		return 0
+	}
+	return 1
+}
+
+// Returns true if the alliance having the given score summary earns the bonus ranking point having the given name,
+// either by reaching its goal or because of its opponent's fouls.
+func (config *RankingPointConfig) IsBonusEarned(name string, summary *ScoreSummary) bool {
+	bonus := config.getBonusRankingPoint(name)
+	if bonus == nil {
+		return false
+	}
+	return bonus.IsEarned(summary) || name == config.FoulBonusRankingPoint && summary.OpponentRankingPointFoul
+}
+
+// Returns the number of ranking points earned in a single match by the alliance having the given score summary,
+// including any bonus ranking points.
+func (config *RankingPointConfig) MatchRankingPoints(summary, opponentSummary *ScoreSummary) int {
+	return config.OutcomeRankingPoints(summary.Score, opponentSummary.Score) + summary.BonusRankingPoints
+}
+
+// Adds the result of a single match to the running totals of the configured ranking columns within the given ranking
+// fields.
+func (config *RankingPointConfig) AddColumnValues(fields *RankingFields, summary, opponentSummary *ScoreSummary) {
+	if fields.ColumnTotals == nil {
+		fields.ColumnTotals = make(map[string]int, len(config.Columns))
+	}
+	for _, column := range config.Columns {
+		fields.ColumnTotals[column.Name] += column.MatchValue(summary, opponentSummary)
+	}
+}
+
+// Returns the values of the configured ranking columns for the given ranking fields.
+func (config *RankingPointConfig) ColumnValues(fields *RankingFields) []int {
+	values := make([]int, len(config.Columns))
+	for i, column := range config.Columns {
+		values[i] = fields.ColumnTotals[column.Name]
+	}
+	return values
+}
+
+// Returns the bonus ranking point having the given name, or nil if there is none.
+func (config *RankingPointConfig) getBonusRankingPoint(name string) *BonusRankingPoint {
+	if name == "" {
+		return nil
+	}
+	for i, bonus := range config.BonusRankingPoints {
+		if bonus.Name == name {
+			return &config.BonusRankingPoints[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRankingPointConfig(t *testing.T) {
+	config := CurrentRankingPointConfig()
+	assert.Equal(t, 2, config.OutcomeRankingPoints(30, 20))
+	assert.Equal(t, 1, config.OutcomeRankingPoints(20, 20))
+	assert.Equal(t, 0, config.OutcomeRankingPoints(20, 30))
+
+	assert.Equal(t, 0, config.CountBonusRankingPoints(&ScoreSummary{}))
+	assert.Equal(t, 1, config.CountBonusRankingPoints(&ScoreSummary{MelodyBonusRankingPoint: true}))
+	assert.Equal(
+		t,
+		2,
+		config.CountBonusRankingPoints(&ScoreSummary{MelodyBonusRankingPoint: true, EnsembleBonusRankingPoint: true}),
+	)
+
+	// Check that a foul for a ranking point rule credits the configured bonus unless it was earned anyway.
+	summary := &ScoreSummary{MelodyBonusRankingPoint: true, OpponentRankingPointFoul: true}
+	assert.Equal(t, 1, config.FoulRankingPoints(summary))
+	assert.Equal(t, 2, config.CountBonusRankingPoints(summary))
+	assert.True(t, config.IsBonusEarned("Ensemble", summary))
+	assert.False(t, config.IsBonusEarned("Harmony", summary))
+	summary.EnsembleBonusRankingPoint = true
+	assert.Equal(t, 0, config.FoulRankingPoints(summary))
+	assert.Equal(t, 2, config.CountBonusRankingPoints(summary))
+
+	// Check that such a foul awards a ranking point of its own if the game doesn't credit it to a bonus.
+	standaloneConfig := RankingPointConfig{BonusRankingPoints: config.BonusRankingPoints}
+	assert.Equal(t, 1, standaloneConfig.FoulRankingPoints(summary))
+	assert.Equal(t, 3, standaloneConfig.CountBonusRankingPoints(summary))
+	assert.False(t, standaloneConfig.IsBonusEarned("Melody", &ScoreSummary{OpponentRankingPointFoul: true}))
+
+	// Check that the column values are accumulated from the score summaries of each match.
+	fields := RankingFields{}
+	assert.Equal(t, []int{0, 0, 0, 0, 0}, config.ColumnValues(&fields))
+	redSummary := &ScoreSummary{Score: 60, MatchPoints: 55, AutoPoints: 20, StagePoints: 10, CoopertitionBonus: true}
+	blueSummary := &ScoreSummary{Score: 40, BonusRankingPoints: 1}
+	config.AddColumnValues(&fields, redSummary, blueSummary)
+	config.AddColumnValues(&fields, blueSummary, redSummary)
+	assert.Equal(t, []int{3, 1, 55, 20, 10}, config.ColumnValues(&fields))
+	assert.Equal(t, 3, fields.ColumnTotals["RankingPoints"])
+	assert.Equal(t, "Coop", config.Columns[1].Header)
+}
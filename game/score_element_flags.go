@@ -0,0 +1,44 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model for marking which of the human-scored elements of a score meet some condition, such as having been set by an
+// automated scoring system.
+
+package game
+
+// Holds one flag for each of the score elements that can be set from the scoring panel.
+type ScoreElementFlags struct {
+	LeaveStatuses      [3]bool
+	EndgameStatuses    [3]bool
+	MicrophoneStatuses [3]bool
+	TrapStatuses       [3]bool
+}
+
+// Returns the flag for the element affected by the given scoring command.
+func (flags *ScoreElementFlags) Get(command *ScoringCommand) bool {
+	if flag := flags.getFlag(command); flag != nil {
+		return *flag
+	}
+	return false
+}
+
+// Sets the flag for the element affected by the given scoring command.
+func (flags *ScoreElementFlags) Set(command *ScoringCommand, value bool) {
+	if flag := flags.getFlag(command); flag != nil {
+		*flag = value
+	}
+}
+
+func (flags *ScoreElementFlags) getFlag(command *ScoringCommand) *bool {
+	switch command.Command {
+	case "leave":
+		return &flags.LeaveStatuses[command.TeamPosition-1]
+	case "onStage", "park":
+		return &flags.EndgameStatuses[command.TeamPosition-1]
+	case "microphone":
+		return &flags.MicrophoneStatuses[command.StageIndex]
+	case "trap":
+		return &flags.TrapStatuses[command.StageIndex]
+	}
+	return nil
+}
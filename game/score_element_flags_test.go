@@ -0,0 +1,38 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoreElementFlags(t *testing.T) {
+	var flags ScoreElementFlags
+	leave := &ScoringCommand{Command: "leave", TeamPosition: 2}
+	park := &ScoringCommand{Command: "park", TeamPosition: 3}
+	onStage := &ScoringCommand{Command: "onStage", TeamPosition: 3, StageIndex: 1}
+	trap := &ScoringCommand{Command: "trap", StageIndex: 0}
+
+	assert.False(t, flags.Get(leave))
+	flags.Set(leave, true)
+	assert.True(t, flags.Get(leave))
+	assert.Equal(t, [3]bool{false, true, false}, flags.LeaveStatuses)
+
+	// Parking and climbing share the same endgame element.
+	flags.Set(park, true)
+	assert.True(t, flags.Get(onStage))
+	assert.Equal(t, [3]bool{false, false, true}, flags.EndgameStatuses)
+
+	flags.Set(trap, true)
+	flags.Set(&ScoringCommand{Command: "microphone", StageIndex: 2}, true)
+	assert.Equal(t, [3]bool{true, false, false}, flags.TrapStatuses)
+	assert.Equal(t, [3]bool{false, false, true}, flags.MicrophoneStatuses)
+	flags.Set(trap, false)
+	assert.False(t, flags.Get(trap))
+
+	// Unknown commands should be ignored.
+	flags.Set(&ScoringCommand{Command: "blorpy"}, true)
+	assert.False(t, flags.Get(&ScoringCommand{Command: "blorpy"}))
+}
@@ -21,7 +21,10 @@ type ScoreSummary struct {
 	MelodyBonusRankingPoint   bool
 	EnsembleBonusRankingPoint bool
 	BonusRankingPoints        int
+	OpponentRankingPointFoul  bool // Whether the opponent committed a foul for a rule flagged IsRankingPoint.
+	FoulRankingPoints         int  // Ranking points earned only because of the opponent's fouls.
 	NumOpponentTechFouls      int
+	PlayoffDq                 bool
 
 	// Fields only needed for TBA.
 	ParkPoints      int
@@ -45,28 +48,54 @@ func (t MatchStatus) Get() MatchStatus {
 	return t
 }
 
+// Represents the basis on which the winner of a match was decided.
+type MatchWinReason int
+
+const (
+	WinReasonNone MatchWinReason = iota
+	WinReasonScore
+	WinReasonTiebreaker
+	WinReasonForfeit
+)
+
 // Determines the winner of the match given the score summaries for both alliances.
 func DetermineMatchStatus(redScoreSummary, blueScoreSummary *ScoreSummary, applyPlayoffTiebreakers bool) MatchStatus {
+	status, _, _ := DetermineMatchOutcome(redScoreSummary, blueScoreSummary, applyPlayoffTiebreakers)
+	return status
+}
+
+// Determines the winner of the match given the score summaries for both alliances, along with the reason for the win
+// and, if the win was decided by a playoff tiebreaker, the 1-based level of the tiebreaker that decided it. An alliance
+// facing a disqualified one wins by forfeit regardless of the score, even if neither alliance scored any points.
+func DetermineMatchOutcome(
+	redScoreSummary, blueScoreSummary *ScoreSummary, applyPlayoffTiebreakers bool,
+) (MatchStatus, MatchWinReason, int) {
+	if blueScoreSummary.PlayoffDq && !redScoreSummary.PlayoffDq {
+		return RedWonMatch, WinReasonForfeit, 0
+	}
+	if redScoreSummary.PlayoffDq && !blueScoreSummary.PlayoffDq {
+		return BlueWonMatch, WinReasonForfeit, 0
+	}
+
 	if status := comparePoints(redScoreSummary.Score, blueScoreSummary.Score); status != TieMatch {
-		return status
+		return status, WinReasonScore, 0
 	}
 
 	if applyPlayoffTiebreakers {
-		// Check scoring breakdowns to resolve playoff ties.
-		if status := comparePoints(
-			redScoreSummary.NumOpponentTechFouls, blueScoreSummary.NumOpponentTechFouls,
-		); status != TieMatch {
-			return status
+		// Check scoring breakdowns to resolve playoff ties, in order of precedence.
+		tiebreakers := [][2]int{
+			{redScoreSummary.NumOpponentTechFouls, blueScoreSummary.NumOpponentTechFouls},
+			{redScoreSummary.AutoPoints, blueScoreSummary.AutoPoints},
+			{redScoreSummary.StagePoints, blueScoreSummary.StagePoints},
 		}
-		if status := comparePoints(redScoreSummary.AutoPoints, blueScoreSummary.AutoPoints); status != TieMatch {
-			return status
-		}
-		if status := comparePoints(redScoreSummary.StagePoints, blueScoreSummary.StagePoints); status != TieMatch {
-			return status
+		for i, tiebreaker := range tiebreakers {
+			if status := comparePoints(tiebreaker[0], tiebreaker[1]); status != TieMatch {
+				return status, WinReasonTiebreaker, i + 1
+			}
 		}
 	}
 
-	return TieMatch
+	return TieMatch, WinReasonNone, 0
 }
 
 // Helper method to compare the red and blue alliance point totals and return the appropriate MatchStatus.
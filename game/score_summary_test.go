@@ -56,3 +56,59 @@ func TestScoreSummaryDetermineMatchStatus(t *testing.T) {
 	assert.Equal(t, TieMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary, false))
 	assert.Equal(t, TieMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary, true))
 }
+
+func TestScoreSummaryDetermineMatchOutcome(t *testing.T) {
+	redScoreSummary := &ScoreSummary{Score: 10}
+	blueScoreSummary := &ScoreSummary{Score: 10}
+	status, reason, tiebreakLevel := DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, TieMatch, status)
+	assert.Equal(t, WinReasonNone, reason)
+	assert.Equal(t, 0, tiebreakLevel)
+
+	redScoreSummary.Score = 11
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, WinReasonScore, reason)
+	assert.Equal(t, 0, tiebreakLevel)
+
+	redScoreSummary.Score = 10
+	blueScoreSummary.NumOpponentTechFouls = 1
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, BlueWonMatch, status)
+	assert.Equal(t, WinReasonTiebreaker, reason)
+	assert.Equal(t, 1, tiebreakLevel)
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, false)
+	assert.Equal(t, TieMatch, status)
+	assert.Equal(t, WinReasonNone, reason)
+
+	redScoreSummary.NumOpponentTechFouls = 1
+	redScoreSummary.StagePoints = 5
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, WinReasonTiebreaker, reason)
+	assert.Equal(t, 3, tiebreakLevel)
+
+	redScoreSummary = &ScoreSummary{PlayoffDq: true}
+	blueScoreSummary = &ScoreSummary{Score: 5}
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, BlueWonMatch, status)
+	assert.Equal(t, WinReasonForfeit, reason)
+	assert.Equal(t, 0, tiebreakLevel)
+
+	// Check that a disqualification decides the match even when neither alliance scored.
+	redScoreSummary = &ScoreSummary{}
+	blueScoreSummary = &ScoreSummary{PlayoffDq: true}
+	status, reason, tiebreakLevel = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, WinReasonForfeit, reason)
+	assert.Equal(t, 0, tiebreakLevel)
+	status, reason, _ = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, false)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, WinReasonForfeit, reason)
+
+	// Check that the match is decided as usual if both alliances were disqualified.
+	redScoreSummary.PlayoffDq = true
+	status, reason, _ = DetermineMatchOutcome(redScoreSummary, blueScoreSummary, true)
+	assert.Equal(t, TieMatch, status)
+	assert.Equal(t, WinReasonNone, reason)
+}
@@ -226,13 +226,32 @@ func TestScoreFreeEnsembleBonusRankingPointFromFoul(t *testing.T) {
 
 	summary := score1.Summarize(&score2)
 	assert.Equal(t, 5, summary.Score)
+	assert.Equal(t, true, summary.OpponentRankingPointFoul)
 	assert.Equal(t, true, summary.EnsembleBonusRankingPoint)
+	assert.Equal(t, 1, summary.FoulRankingPoints)
 	assert.Equal(t, 1, summary.BonusRankingPoints)
 
 	summary = score2.Summarize(&score1)
 	assert.Equal(t, 0, summary.Score)
+	assert.Equal(t, false, summary.OpponentRankingPointFoul)
 	assert.Equal(t, false, summary.EnsembleBonusRankingPoint)
+	assert.Equal(t, 0, summary.FoulRankingPoints)
 	assert.Equal(t, 0, summary.BonusRankingPoints)
+
+	// The foul shouldn't count for anything extra if the alliance earned the bonus anyway.
+	score1.EndgameStatuses = [3]EndgameStatus{EndgameStageLeft, EndgameStageLeft, EndgameParked}
+	score1.TrapStatuses = [3]bool{true, false, false}
+	summary = score1.Summarize(&score2)
+	assert.Equal(t, true, summary.EnsembleBonusRankingPoint)
+	assert.Equal(t, 0, summary.FoulRankingPoints)
+	assert.Equal(t, 1, summary.BonusRankingPoints)
+
+	// Check that the ranking points earned through fouls are tallied in the rankings.
+	var fields RankingFields
+	fields.AddScoreSummary(score2.Summarize(&score1), score1.Summarize(&score2), false)
+	score1 = Score{}
+	fields.AddScoreSummary(score1.Summarize(&score2), score2.Summarize(&score1), false)
+	assert.Equal(t, 1, fields.FoulRankingPoints)
 }
 
 func TestScoreEquals(t *testing.T) {
@@ -0,0 +1,170 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model representing reversible scoring panel actions and the undo/redo history built from them.
+
+package game
+
+// Represents a single scoring panel action, along with the values needed to apply and revert it.
+type ScoringCommand struct {
+	Command      string
+	TeamPosition int
+	StageIndex   int
+	OldValue     int
+	NewValue     int
+}
+
+type ScoringAction string
+
+const (
+	ScoringActionExecute ScoringAction = "execute"
+	ScoringActionUndo    ScoringAction = "undo"
+	ScoringActionRedo    ScoringAction = "redo"
+)
+
+// Represents an entry in the audit log of everything the scorekeeper did during a match.
+type ScoringLogEntry struct {
+	Action       ScoringAction
+	Command      ScoringCommand
+	MatchTimeSec float64
+}
+
+// Tracks the commands executed against an alliance's score so that they can be stepped back through and reapplied.
+type ScoringHistory struct {
+	commands []ScoringCommand
+	position int
+	Log      []ScoringLogEntry
+}
+
+// Builds the command for the given scoring panel action based on the current state of the score and the element of the
+// current game's scoring panel that it belongs to. The delta is the amount by which to change the value of a counter or
+// timer element and is ignored for toggles. Returns false if the command or its arguments are invalid or if the command
+// would leave the score unchanged.
+func NewScoringCommand(command string, teamPosition, stageIndex, delta int, score *Score) (*ScoringCommand, bool) {
+	element := GetScoringPanelElement(command, stageIndex)
+	if element == nil {
+		return nil, false
+	}
+	stageIndex = element.StageIndex
+	if !element.PerTeam {
+		// Elements that aren't per-team are always addressed as team position zero.
+		teamPosition = 0
+	} else if teamPosition < 1 || teamPosition > 3 {
+		return nil, false
+	}
+
+	scoringCommand := &ScoringCommand{Command: command, TeamPosition: teamPosition, StageIndex: stageIndex}
+	scoringCommand.OldValue = scoringCommand.getValue(score)
+	switch element.Type {
+	case ScoringPanelToggle:
+		scoringCommand.NewValue = toggleValue(scoringCommand.OldValue, element.ToggleValue())
+	case ScoringPanelCounter, ScoringPanelTimer:
+		scoringCommand.NewValue = max(scoringCommand.OldValue+delta, 0)
+		if scoringCommand.NewValue == scoringCommand.OldValue {
+			return nil, false
+		}
+	}
+	return scoringCommand, true
+}
+
+// Sets the field affected by the command to its new value.
+func (command *ScoringCommand) Apply(score *Score) {
+	command.setValue(score, command.NewValue)
+}
+
+// Sets the field affected by the command back to the value it had before the command was applied.
+func (command *ScoringCommand) Revert(score *Score) {
+	command.setValue(score, command.OldValue)
+}
+
+// Applies the given command to the score and records it, discarding any commands that had been undone.
+func (history *ScoringHistory) Execute(score *Score, command *ScoringCommand, matchTimeSec float64) {
+	command.Apply(score)
+	history.commands = append(history.commands[:history.position], *command)
+	history.position++
+	history.Log = append(history.Log, ScoringLogEntry{ScoringActionExecute, *command, matchTimeSec})
+}
+
+// Reverts the most recently applied command. Returns false if there is nothing to undo.
+func (history *ScoringHistory) Undo(score *Score, matchTimeSec float64) bool {
+	if !history.CanUndo() {
+		return false
+	}
+	history.position--
+	command := history.commands[history.position]
+	command.Revert(score)
+	history.Log = append(history.Log, ScoringLogEntry{ScoringActionUndo, command, matchTimeSec})
+	return true
+}
+
+// Reapplies the most recently undone command. Returns false if there is nothing to redo.
+func (history *ScoringHistory) Redo(score *Score, matchTimeSec float64) bool {
+	if !history.CanRedo() {
+		return false
+	}
+	command := history.commands[history.position]
+	command.Apply(score)
+	history.position++
+	history.Log = append(history.Log, ScoringLogEntry{ScoringActionRedo, command, matchTimeSec})
+	return true
+}
+
+func (history *ScoringHistory) CanUndo() bool {
+	return history.position > 0
+}
+
+func (history *ScoringHistory) CanRedo() bool {
+	return history.position < len(history.commands)
+}
+
+func (command *ScoringCommand) getValue(score *Score) int {
+	switch command.Command {
+	case "leave":
+		return boolToInt(score.LeaveStatuses[command.TeamPosition-1])
+	case "onStage", "park":
+		return int(score.EndgameStatuses[command.TeamPosition-1])
+	case "microphone":
+		return boolToInt(score.MicrophoneStatuses[command.StageIndex])
+	case "trap":
+		return boolToInt(score.TrapStatuses[command.StageIndex])
+	}
+	return score.PanelValues[command.key()]
+}
+
+func (command *ScoringCommand) setValue(score *Score, value int) {
+	switch command.Command {
+	case "leave":
+		score.LeaveStatuses[command.TeamPosition-1] = value != 0
+	case "onStage", "park":
+		score.EndgameStatuses[command.TeamPosition-1] = EndgameStatus(value)
+	case "microphone":
+		score.MicrophoneStatuses[command.StageIndex] = value != 0
+	case "trap":
+		score.TrapStatuses[command.StageIndex] = value != 0
+	default:
+		if score.PanelValues == nil {
+			score.PanelValues = make(map[string]int)
+		}
+		score.PanelValues[command.key()] = value
+	}
+}
+
+// Returns the key of the scoring panel element that the command affects.
+func (command *ScoringCommand) key() string {
+	return scoringPanelKey(command.Command, command.TeamPosition, command.StageIndex)
+}
+
+// Returns the given target value, or zero if the current value already equals it.
+func toggleValue(currentValue, targetValue int) int {
+	if currentValue == targetValue {
+		return 0
+	}
+	return targetValue
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewScoringCommand(t *testing.T) {
+	score := new(Score)
+
+	command, ok := NewScoringCommand("leave", 2, 0, 0, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"leave", 2, 0, 0, 1}, *command)
+	}
+	command, ok = NewScoringCommand("onStage", 1, 2, 0, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"onStage", 1, 2, 0, int(EndgameStageRight)}, *command)
+	}
+	score.EndgameStatuses[0] = EndgameStageRight
+	command, ok = NewScoringCommand("onStage", 1, 2, 0, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"onStage", 1, 2, int(EndgameStageRight), int(EndgameNone)}, *command)
+	}
+	command, ok = NewScoringCommand("park", 1, 0, 0, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"park", 1, 0, int(EndgameStageRight), int(EndgameParked)}, *command)
+	}
+	score.TrapStatuses[1] = true
+	command, ok = NewScoringCommand("trap", 0, 1, 0, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"trap", 0, 1, 1, 0}, *command)
+	}
+
+	for _, args := range []struct {
+		command      string
+		teamPosition int
+		stageIndex   int
+	}{{"leave", 0, 0}, {"park", 4, 0}, {"onStage", 1, 3}, {"microphone", 0, -1}, {"trap", 0, 3}, {"blorpy", 1, 1}} {
+		_, ok = NewScoringCommand(args.command, args.teamPosition, args.stageIndex, 0, score)
+		assert.False(t, ok)
+	}
+}
+
+func TestScoringHistory(t *testing.T) {
+	score := new(Score)
+	var history ScoringHistory
+	assert.False(t, history.CanUndo())
+	assert.False(t, history.CanRedo())
+	assert.False(t, history.Undo(score, 0))
+	assert.False(t, history.Redo(score, 0))
+
+	execute := func(command string, teamPosition, stageIndex int, matchTimeSec float64) {
+		scoringCommand, ok := NewScoringCommand(command, teamPosition, stageIndex, 0, score)
+		if assert.True(t, ok) {
+			history.Execute(score, scoringCommand, matchTimeSec)
+		}
+	}
+	execute("leave", 1, 0, 5)
+	execute("microphone", 0, 2, 100)
+	execute("onStage", 3, 1, 140)
+	assert.Equal(t, [3]bool{true, false, false}, score.LeaveStatuses)
+	assert.Equal(t, [3]bool{false, false, true}, score.MicrophoneStatuses)
+	assert.Equal(t, [3]EndgameStatus{EndgameNone, EndgameNone, EndgameCenterStage}, score.EndgameStatuses)
+	assert.True(t, history.CanUndo())
+	assert.False(t, history.CanRedo())
+
+	assert.True(t, history.Undo(score, 141))
+	assert.True(t, history.Undo(score, 142))
+	assert.Equal(t, [3]bool{true, false, false}, score.LeaveStatuses)
+	assert.Equal(t, [3]bool{false, false, false}, score.MicrophoneStatuses)
+	assert.Equal(t, [3]EndgameStatus{EndgameNone, EndgameNone, EndgameNone}, score.EndgameStatuses)
+	assert.True(t, history.CanRedo())
+
+	assert.True(t, history.Redo(score, 143))
+	assert.Equal(t, [3]bool{false, false, true}, score.MicrophoneStatuses)
+
+	// Changes made outside of the history (e.g. by the referee) should not be clobbered by undoing.
+	score.Fouls = []Foul{{RuleId: 1}}
+	assert.True(t, history.Undo(score, 144))
+	assert.Equal(t, []Foul{{RuleId: 1}}, score.Fouls)
+
+	// Executing a new command should discard the commands that were undone.
+	execute("park", 2, 0, 145)
+	assert.False(t, history.CanRedo())
+	assert.Equal(t, [3]EndgameStatus{EndgameNone, EndgameParked, EndgameNone}, score.EndgameStatuses)
+
+	actions := make([]ScoringAction, len(history.Log))
+	for i, entry := range history.Log {
+		actions[i] = entry.Action
+	}
+	assert.Equal(
+		t,
+		[]ScoringAction{
+			ScoringActionExecute, ScoringActionExecute, ScoringActionExecute, ScoringActionUndo, ScoringActionUndo,
+			ScoringActionRedo, ScoringActionUndo, ScoringActionExecute,
+		},
+		actions,
+	)
+	assert.Equal(t, 142.0, history.Log[4].MatchTimeSec)
+	assert.Equal(t, "microphone", history.Log[4].Command.Command)
+}
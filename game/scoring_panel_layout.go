@@ -0,0 +1,180 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model describing the elements shown on the scoring panel, so that each game can lay out its own panel without any
+// changes to the panel's markup or scripts.
+
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind of input that an element of the scoring panel takes.
+type ScoringPanelElementType string
+
+const (
+	// Switches the element between zero and its target value each time it is tapped.
+	ScoringPanelToggle ScoringPanelElementType = "toggle"
+	// Steps the element's value up or down by one.
+	ScoringPanelCounter ScoringPanelElementType = "counter"
+	// Accumulates the number of seconds for which the scorer has had the element's stopwatch running.
+	ScoringPanelTimer ScoringPanelElementType = "timer"
+)
+
+// Portion of a match from which an element of the scoring panel can be scored. While a match is running, an element
+// can't be scored before its period has started; outside of a match every element can be scored so that the scorer
+// can make corrections.
+type ScoringPeriod string
+
+const (
+	ScoringPeriodAny     ScoringPeriod = ""
+	ScoringPeriodAuto    ScoringPeriod = "auto"
+	ScoringPeriodTeleop  ScoringPeriod = "teleop"
+	ScoringPeriodEndgame ScoringPeriod = "endgame"
+)
+
+// Represents a single input on the scoring panel.
+type ScoringPanelElement struct {
+	Name        string
+	Command     string // Name of the scoring command that the element sends.
+	Type        ScoringPanelElementType
+	Period      ScoringPeriod
+	PerTeam     bool // Whether the element is repeated for each of the alliance's teams rather than shown once.
+	StageIndex  int  // Distinguishes between elements sharing the same command, such as one per side of a structure.
+	TargetValue int  // Value that a toggle sets when switched on; defaults to 1 if left at zero.
+}
+
+// Represents a group of elements that are shown together on the scoring panel under a common heading.
+type ScoringPanelSection struct {
+	Name     string
+	Elements []ScoringPanelElement
+}
+
+// Returns the key under which the value of the element for the given team position (or zero if the element isn't
+// per-team) is published to the scoring panel and, for elements not backed by a dedicated score field, stored in the
+// score.
+func (element ScoringPanelElement) Key(teamPosition int) string {
+	return scoringPanelKey(element.Command, teamPosition, element.StageIndex)
+}
+
+// Returns the value that the element sets when switched on, if it is a toggle.
+func (element ScoringPanelElement) ToggleValue() int {
+	if element.TargetValue == 0 {
+		return 1
+	}
+	return element.TargetValue
+}
+
+// Returns true if the per-team elements of the section should be laid out in a row for each team.
+func (section ScoringPanelSection) HasPerTeamElements() bool {
+	for _, element := range section.Elements {
+		if element.PerTeam {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the number of seconds into a match at which the period starts, given the current match timing.
+func (period ScoringPeriod) StartTimeSec() float64 {
+	switch period {
+	case ScoringPeriodAuto:
+		return float64(MatchTiming.WarmupDurationSec)
+	case ScoringPeriodTeleop:
+		return GetDurationToTeleopStart().Seconds()
+	case ScoringPeriodEndgame:
+		return (GetDurationToTeleopEnd() - time.Duration(MatchTiming.WarningRemainingDurationSec)*time.Second).Seconds()
+	}
+	return 0
+}
+
+// Returns the values of all the elements of the current game's scoring panel for the given score, keyed by element
+// key.
+func GetScoringPanelValues(score *Score) map[string]int {
+	values := make(map[string]int)
+	forEachScoringPanelCommand(func(command *ScoringCommand) {
+		values[command.key()] = command.getValue(score)
+	})
+	return values
+}
+
+// Returns the given flags for all the elements of the current game's scoring panel, keyed by element key.
+func GetScoringPanelFlags(flags *ScoreElementFlags) map[string]bool {
+	values := make(map[string]bool)
+	forEachScoringPanelCommand(func(command *ScoringCommand) {
+		values[command.key()] = flags.Get(command)
+	})
+	return values
+}
+
+// Returns the element of the current game's scoring panel that sends the given command with the given stage index, or
+// nil if there is no such element. The stage index is disregarded if only one element sends the command.
+func GetScoringPanelElement(command string, stageIndex int) *ScoringPanelElement {
+	var matchingElements []*ScoringPanelElement
+	for _, section := range CurrentGame().ScoringPanelLayout() {
+		for i, element := range section.Elements {
+			if element.Command == command {
+				matchingElements = append(matchingElements, &section.Elements[i])
+			}
+		}
+	}
+	if len(matchingElements) == 1 {
+		return matchingElements[0]
+	}
+	for _, element := range matchingElements {
+		if element.StageIndex == stageIndex {
+			return element
+		}
+	}
+	return nil
+}
+
+// Invokes the given function with a command addressing each element of the current game's scoring panel, once for
+// each team if the element is per-team.
+func forEachScoringPanelCommand(function func(command *ScoringCommand)) {
+	for _, section := range CurrentGame().ScoringPanelLayout() {
+		for _, element := range section.Elements {
+			if element.PerTeam {
+				for teamPosition := 1; teamPosition <= 3; teamPosition++ {
+					function(&ScoringCommand{
+						Command: element.Command, TeamPosition: teamPosition, StageIndex: element.StageIndex,
+					})
+				}
+			} else {
+				function(&ScoringCommand{Command: element.Command, StageIndex: element.StageIndex})
+			}
+		}
+	}
+}
+
+// Returns an error if the given scoring panel layout has an element of an unknown type or period or has more than one
+// element sending the same command with the same stage index.
+func validateScoringPanelLayout(layout []ScoringPanelSection) error {
+	elementKeys := make(map[string]struct{})
+	for _, section := range layout {
+		for _, element := range section.Elements {
+			switch element.Type {
+			case ScoringPanelToggle, ScoringPanelCounter, ScoringPanelTimer:
+			default:
+				return fmt.Errorf("element %s has invalid type '%s'", element.Name, element.Type)
+			}
+			switch element.Period {
+			case ScoringPeriodAny, ScoringPeriodAuto, ScoringPeriodTeleop, ScoringPeriodEndgame:
+			default:
+				return fmt.Errorf("element %s has invalid period '%s'", element.Name, element.Period)
+			}
+			key := element.Key(0)
+			if _, ok := elementKeys[key]; ok {
+				return fmt.Errorf("element %s duplicates command %s", element.Name, element.Command)
+			}
+			elementKeys[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func scoringPanelKey(command string, teamPosition, stageIndex int) string {
+	return fmt.Sprintf("%s:%d:%d", command, teamPosition, stageIndex)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoringPeriodStartTimeSec(t *testing.T) {
+	oldMatchTiming := MatchTiming
+	defer func() { MatchTiming = oldMatchTiming }()
+	MatchTiming = MatchTimingSettings{WarmupDurationSec: 2, AutoDurationSec: 15, PauseDurationSec: 3,
+		TeleopDurationSec: 135, WarningRemainingDurationSec: 20}
+
+	assert.Equal(t, 0.0, ScoringPeriodAny.StartTimeSec())
+	assert.Equal(t, 2.0, ScoringPeriodAuto.StartTimeSec())
+	assert.Equal(t, 20.0, ScoringPeriodTeleop.StartTimeSec())
+	assert.Equal(t, 135.0, ScoringPeriodEndgame.StartTimeSec())
+}
+
+func TestGetScoringPanelValues(t *testing.T) {
+	score := &Score{
+		LeaveStatuses:      [3]bool{false, true, false},
+		EndgameStatuses:    [3]EndgameStatus{EndgameParked, EndgameNone, EndgameStageRight},
+		MicrophoneStatuses: [3]bool{true, false, false},
+	}
+	values := GetScoringPanelValues(score)
+	assert.Equal(t, 1, values["leave:2:0"])
+	assert.Equal(t, 0, values["leave:3:0"])
+	assert.Equal(t, int(EndgameParked), values["park:1:0"])
+	assert.Equal(t, int(EndgameStageRight), values["onStage:3:2"])
+	assert.Equal(t, 1, values["microphone:0:0"])
+	assert.Equal(t, 0, values["trap:0:2"])
+	assert.Equal(t, 3*(1+1+3)+6, len(values))
+
+	flags := ScoreElementFlags{EndgameStatuses: [3]bool{false, true, false}}
+	flagValues := GetScoringPanelFlags(&flags)
+	assert.True(t, flagValues["park:2:0"])
+	assert.True(t, flagValues["onStage:2:1"])
+	assert.False(t, flagValues["leave:2:0"])
+}
+
+func TestScoringPanelCustomElements(t *testing.T) {
+	RegisterGameDefinition(fakeGame{})
+	defer delete(gameDefinitions, "fake")
+	defer SetCurrentGame("")
+	assert.Nil(t, SetCurrentGame("fake"))
+	score := new(Score)
+
+	// Check that counters step up and down without going below zero.
+	command, ok := NewScoringCommand("balls", 0, 0, 1, score)
+	if assert.True(t, ok) {
+		command.Apply(score)
+	}
+	assert.Equal(t, map[string]int{"balls:0:0": 1}, score.PanelValues)
+	command, ok = NewScoringCommand("balls", 0, 0, -1, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, ScoringCommand{"balls", 0, 0, 1, 0}, *command)
+		command.Apply(score)
+	}
+	_, ok = NewScoringCommand("balls", 0, 0, -1, score)
+	assert.False(t, ok)
+
+	// Check that timers accumulate seconds for each team.
+	command, ok = NewScoringCommand("hang", 2, 0, 7, score)
+	if assert.True(t, ok) {
+		command.Apply(score)
+	}
+	command, ok = NewScoringCommand("hang", 2, 0, 4, score)
+	if assert.True(t, ok) {
+		command.Apply(score)
+	}
+	assert.Equal(t, 11, GetScoringPanelValues(score)["hang:2:0"])
+	assert.Equal(t, 0, GetScoringPanelValues(score)["hang:1:0"])
+	assert.True(t, score.Equals(&Score{PanelValues: map[string]int{"balls:0:0": 0, "hang:2:0": 11}}))
+	assert.False(t, score.Equals(&Score{PanelValues: map[string]int{"balls:0:0": 0, "hang:2:0": 10}}))
+
+	// Check that the elements of the default game are no longer available.
+	_, ok = NewScoringCommand("trap", 0, 1, 0, score)
+	assert.False(t, ok)
+	_, ok = NewScoringCommand("hang", 0, 0, 1, score)
+	assert.False(t, ok)
+	command, ok = NewScoringCommand("balls", 1, 0, 1, score)
+	if assert.True(t, ok) {
+		assert.Equal(t, 0, command.TeamPosition)
+	}
+}
+
+func TestValidateScoringPanelLayout(t *testing.T) {
+	assert.Nil(t, validateScoringPanelLayout(crescendoScoringPanelLayout))
+
+	err := validateScoringPanelLayout(
+		[]ScoringPanelSection{{Elements: []ScoringPanelElement{{Name: "Spin", Command: "spin", Type: "dial"}}}},
+	)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "element Spin has invalid type 'dial'", err.Error())
+	}
+	err = validateScoringPanelLayout(
+		[]ScoringPanelSection{
+			{
+				Elements: []ScoringPanelElement{
+					{Name: "Spin", Command: "spin", Type: ScoringPanelToggle, Period: "halftime"},
+				},
+			},
+		},
+	)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "element Spin has invalid period 'halftime'", err.Error())
+	}
+	err = validateScoringPanelLayout(
+		[]ScoringPanelSection{
+			{Elements: []ScoringPanelElement{{Name: "Spin", Command: "spin", Type: ScoringPanelToggle}}},
+			{Elements: []ScoringPanelElement{{Name: "Twirl", Command: "spin", Type: ScoringPanelCounter}}},
+		},
+	)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "element Twirl duplicates command spin", err.Error())
+	}
+}
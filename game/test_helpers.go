@@ -7,13 +7,13 @@ package game
 
 func TestScore1() *Score {
 	fouls := []Foul{
-		{true, 25, 13},
-		{false, 1868, 14},
-		{false, 1868, 14},
-		{true, 25, 15},
-		{true, 25, 15},
-		{true, 25, 15},
-		{true, 25, 15},
+		{IsTechnical: true, TeamId: 25, RuleId: 13},
+		{IsTechnical: false, TeamId: 1868, RuleId: 14},
+		{IsTechnical: false, TeamId: 1868, RuleId: 14},
+		{IsTechnical: true, TeamId: 25, RuleId: 15},
+		{IsTechnical: true, TeamId: 25, RuleId: 15},
+		{IsTechnical: true, TeamId: 25, RuleId: 15},
+		{IsTechnical: true, TeamId: 25, RuleId: 15},
 	}
 	return &Score{
 		LeaveStatuses: [3]bool{true, true, false},
@@ -53,9 +53,29 @@ func TestScore2() *Score {
 }
 
 func TestRanking1() *Ranking {
-	return &Ranking{254, 1, 0, RankingFields{20, 625, 90, 554, 12, 0.254, 3, 2, 1, 0, 10}}
+	return &Ranking{
+		254,
+		1,
+		0,
+		RankingFields{
+			20, 625, 90, 554, 12, 0.254, 3, 2, 1, 0, 0, 0, 10,
+			map[string]int{
+				"RankingPoints": 20, "CoopertitionPoints": 625, "MatchPoints": 90, "AutoPoints": 554, "StagePoints": 12,
+			},
+		},
+	}
 }
 
 func TestRanking2() *Ranking {
-	return &Ranking{1114, 2, 1, RankingFields{18, 700, 625, 90, 23, 0.1114, 1, 3, 2, 0, 10}}
+	return &Ranking{
+		1114,
+		2,
+		1,
+		RankingFields{
+			18, 700, 625, 90, 23, 0.1114, 1, 3, 2, 0, 0, 0, 10,
+			map[string]int{
+				"RankingPoints": 18, "CoopertitionPoints": 700, "MatchPoints": 625, "AutoPoints": 90, "StagePoints": 23,
+			},
+		},
+	}
 }
@@ -0,0 +1,106 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Settings of the league whose standings are accumulated across several events. They are kept in a file alongside the
+// event databases rather than in any one of them, so that they carry over from event to event.
+
+package league
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"os"
+	"path/filepath"
+)
+
+const settingsFile = "db/league.json"
+
+type Settings struct {
+	Name               string
+	EventNames         []string // The stored event databases whose results count towards the league.
+	IncludeActiveEvent bool
+	CountedEvents      int // The number of each team's best events that count towards its total, or 0 for all of them.
+	Rules              PointRules
+}
+
+// Determines how many league points a team earns from its qualification results at a single event.
+type PointRules struct {
+	ParticipationPoints    int
+	WinPoints              int
+	TiePoints              int
+	LossPoints             int
+	RankingPointMultiplier int
+	PlacementPoints        []int // Awarded by final qualification rank, starting with first place.
+}
+
+// Returns the settings used until the league has been configured.
+func DefaultSettings() *Settings {
+	return &Settings{
+		Name:               "League",
+		IncludeActiveEvent: true,
+		Rules:              PointRules{WinPoints: 2, TiePoints: 1},
+	}
+}
+
+// Returns the settings under which a team's standing is simply its total ranking points across all of the events it
+// has played in, as used for the season standings.
+func SeasonSettings() *Settings {
+	return &Settings{Name: "Season", Rules: PointRules{RankingPointMultiplier: 1}}
+}
+
+// Reads the league settings, falling back to the defaults if they haven't been saved yet.
+func LoadSettings() (*Settings, error) {
+	settingsJson, err := os.ReadFile(getSettingsPath())
+	if os.IsNotExist(err) {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings Settings
+	if err = json.Unmarshal(settingsJson, &settings); err != nil {
+		return nil, fmt.Errorf("Could not parse league settings: %v", err)
+	}
+	return &settings, nil
+}
+
+// Validates and stores the given league settings.
+func SaveSettings(settings *Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	settingsJson, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(getSettingsPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(getSettingsPath(), settingsJson, 0644)
+}
+
+// Returns an error describing the first problem with the settings, if there is one.
+func (settings *Settings) Validate() error {
+	if settings.Name == "" {
+		return fmt.Errorf("The league name cannot be blank.")
+	}
+	if settings.CountedEvents < 0 {
+		return fmt.Errorf("The number of counted events cannot be negative.")
+	}
+	if settings.Rules.RankingPointMultiplier < 0 {
+		return fmt.Errorf("The ranking point multiplier cannot be negative.")
+	}
+	seenEventNames := make(map[string]bool)
+	for _, eventName := range settings.EventNames {
+		if seenEventNames[eventName] {
+			return fmt.Errorf("Event '%s' is included in the league more than once.", eventName)
+		}
+		seenEventNames[eventName] = true
+	}
+	return nil
+}
+
+func getSettingsPath() string {
+	return filepath.Join(model.BaseDir, settingsFile)
+}
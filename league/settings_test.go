@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package league
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestLoadAndSaveSettings(t *testing.T) {
+	model.BaseDir = t.TempDir()
+	t.Cleanup(func() { model.BaseDir = "." })
+
+	settings, err := LoadSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultSettings(), settings)
+
+	settings.Name = "Bay Area League"
+	settings.EventNames = []string{"Week_1", "Week_2"}
+	settings.CountedEvents = 2
+	settings.Rules.PlacementPoints = []int{10, 8, 6}
+	assert.Nil(t, SaveSettings(settings))
+	savedSettings, err := LoadSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, settings, savedSettings)
+
+	// Check that invalid settings aren't saved.
+	settings.Name = ""
+	err = SaveSettings(settings)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The league name cannot be blank.", err.Error())
+	}
+	settings.Name = "Bay Area League"
+	settings.EventNames = []string{"Week_1", "Week_1"}
+	err = SaveSettings(settings)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Event 'Week_1' is included in the league more than once.", err.Error())
+	}
+	settings.EventNames = nil
+	settings.CountedEvents = -1
+	assert.NotNil(t, SaveSettings(settings))
+	savedSettings, _ = LoadSettings()
+	assert.Equal(t, "Bay Area League", savedSettings.Name)
+	assert.Equal(t, 2, savedSettings.CountedEvents)
+
+	assert.Nil(t, os.WriteFile(getSettingsPath(), []byte("{"), 0644))
+	_, err = LoadSettings()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Could not parse league settings")
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Calculation of the cumulative league standings from the qualification results of each event.
+
+package league
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"sort"
+)
+
+// The qualification results of a single event that counts towards the league.
+type EventResults struct {
+	EventName     string
+	Rankings      game.Rankings
+	TeamNicknames map[int]string
+}
+
+// The league points that a team earned at a single event.
+type EventPoints struct {
+	EventName string
+	Rank      int
+	Points    int
+	Counted   bool // Whether the event is among the team's best ones that count towards its total.
+}
+
+// Represents a team's position in the league.
+type Standing struct {
+	Rank     int
+	TeamId   int
+	Nickname string
+	Points   int
+	Wins     int
+	Losses   int
+	Ties     int
+	Played   int
+	Events   []EventPoints
+}
+
+// Returns the number of league points earned by a team with the given qualification ranking at a single event.
+func (rules *PointRules) EventPoints(ranking *game.Ranking) int {
+	points := rules.ParticipationPoints + rules.WinPoints*ranking.Wins + rules.TiePoints*ranking.Ties +
+		rules.LossPoints*ranking.Losses + rules.RankingPointMultiplier*ranking.RankingPoints
+	if ranking.Rank > 0 && ranking.Rank <= len(rules.PlacementPoints) {
+		points += rules.PlacementPoints[ranking.Rank-1]
+	}
+	return points
+}
+
+// Combines the results of the given events, in the order in which they were played, into the league standings. Teams
+// are ordered by total points, then by their best single-event result, then by wins.
+func CalculateStandings(settings *Settings, events []EventResults) []Standing {
+	standingsByTeam := make(map[int]*Standing)
+	for _, event := range events {
+		for _, ranking := range event.Rankings {
+			if ranking.Played == 0 {
+				continue
+			}
+			standing, ok := standingsByTeam[ranking.TeamId]
+			if !ok {
+				standing = &Standing{TeamId: ranking.TeamId}
+				standingsByTeam[ranking.TeamId] = standing
+			}
+			if nickname := event.TeamNicknames[ranking.TeamId]; nickname != "" {
+				// Prefer the nickname from the most recent event in case the team has changed it.
+				standing.Nickname = nickname
+			}
+			standing.Wins += ranking.Wins
+			standing.Losses += ranking.Losses
+			standing.Ties += ranking.Ties
+			standing.Played += ranking.Played
+			standing.Events = append(
+				standing.Events,
+				EventPoints{
+					EventName: event.EventName, Rank: ranking.Rank, Points: settings.Rules.EventPoints(&ranking),
+				},
+			)
+		}
+	}
+
+	standings := make([]Standing, 0, len(standingsByTeam))
+	for _, standing := range standingsByTeam {
+		standing.countBestEvents(settings.CountedEvents)
+		standings = append(standings, *standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := &standings[i], &standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.bestEventPoints() != b.bestEventPoints() {
+			return a.bestEventPoints() > b.bestEventPoints()
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.TeamId < b.TeamId
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// Returns the league points the team earned at the event with the given name, and whether it attended that event.
+func (standing *Standing) EventPoints(eventName string) (EventPoints, bool) {
+	for _, eventPoints := range standing.Events {
+		if eventPoints.EventName == eventName {
+			return eventPoints, true
+		}
+	}
+	return EventPoints{}, false
+}
+
+// Marks the given number of the team's highest-scoring events (or all of them if zero) as counted and totals them up.
+func (standing *Standing) countBestEvents(countedEvents int) {
+	indexes := make([]int, len(standing.Events))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return standing.Events[indexes[i]].Points > standing.Events[indexes[j]].Points
+	})
+	standing.Points = 0
+	for i, index := range indexes {
+		if countedEvents == 0 || i < countedEvents {
+			standing.Events[index].Counted = true
+			standing.Points += standing.Events[index].Points
+		}
+	}
+}
+
+func (standing *Standing) bestEventPoints() int {
+	bestPoints := 0
+	for i, eventPoints := range standing.Events {
+		if i == 0 || eventPoints.Points > bestPoints {
+			bestPoints = eventPoints.Points
+		}
+	}
+	return bestPoints
+}
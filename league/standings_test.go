@@ -0,0 +1,122 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package league
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventPoints(t *testing.T) {
+	rules := PointRules{
+		ParticipationPoints: 5, WinPoints: 3, TiePoints: 1, LossPoints: -1, RankingPointMultiplier: 2,
+		PlacementPoints: []int{10, 5},
+	}
+	ranking := game.Ranking{
+		TeamId: 254, Rank: 1, RankingFields: game.RankingFields{RankingPoints: 12, Wins: 4, Losses: 1, Ties: 1},
+	}
+	assert.Equal(t, 5+12+1-1+24+10, rules.EventPoints(&ranking))
+	ranking.Rank = 3
+	assert.Equal(t, 5+12+1-1+24, rules.EventPoints(&ranking))
+	ranking.Rank = 0
+	assert.Equal(t, 5+12+1-1+24, rules.EventPoints(&ranking))
+}
+
+func TestCalculateStandings(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Rules = PointRules{WinPoints: 2, TiePoints: 1, PlacementPoints: []int{3, 2, 1}}
+	events := []EventResults{
+		{
+			EventName: "Week_1",
+			Rankings: game.Rankings{
+				{TeamId: 254, Rank: 1, RankingFields: game.RankingFields{Wins: 3, Played: 3}},
+				{TeamId: 1114, Rank: 2, RankingFields: game.RankingFields{Wins: 2, Losses: 1, Played: 3}},
+				{TeamId: 846, Rank: 3, RankingFields: game.RankingFields{Losses: 3}},
+			},
+			TeamNicknames: map[int]string{254: "Cheesy Poofs", 1114: "Simbotics"},
+		},
+		{
+			EventName: "Week_2",
+			Rankings: game.Rankings{
+				{TeamId: 1114, Rank: 1, RankingFields: game.RankingFields{Wins: 3, Played: 3}},
+				{TeamId: 846, Rank: 2, RankingFields: game.RankingFields{Wins: 1, Ties: 1, Losses: 1, Played: 3}},
+			},
+			TeamNicknames: map[int]string{254: "Cheesy Poofs", 1114: "Simbotics 2.0", 846: "Funky Monkeys"},
+		},
+	}
+
+	standings := CalculateStandings(settings, events)
+	if assert.Equal(t, 3, len(standings)) {
+		assert.Equal(
+			t,
+			Standing{
+				Rank:     1,
+				TeamId:   1114,
+				Nickname: "Simbotics 2.0",
+				Points:   15,
+				Wins:     5,
+				Losses:   1,
+				Played:   6,
+				Events: []EventPoints{
+					{EventName: "Week_1", Rank: 2, Points: 6, Counted: true},
+					{EventName: "Week_2", Rank: 1, Points: 9, Counted: true},
+				},
+			},
+			standings[0],
+		)
+		assert.Equal(t, 254, standings[1].TeamId)
+		assert.Equal(t, 9, standings[1].Points)
+		assert.Equal(t, 846, standings[2].TeamId)
+		assert.Equal(t, 5, standings[2].Points)
+		assert.Equal(t, 1, len(standings[2].Events))
+		_, ok := standings[2].EventPoints("Week_1")
+		assert.False(t, ok)
+		eventPoints, ok := standings[2].EventPoints("Week_2")
+		assert.True(t, ok)
+		assert.Equal(t, 2, eventPoints.Rank)
+	}
+
+	// Check that only each team's best events count when limited, with ties then broken by the best event and wins.
+	settings.CountedEvents = 1
+	standings = CalculateStandings(settings, events)
+	if assert.Equal(t, 3, len(standings)) {
+		assert.Equal(t, 1114, standings[0].TeamId)
+		assert.Equal(t, 9, standings[0].Points)
+		assert.False(t, standings[0].Events[0].Counted)
+		assert.True(t, standings[0].Events[1].Counted)
+		assert.Equal(t, 254, standings[1].TeamId)
+		assert.Equal(t, 9, standings[1].Points)
+	}
+
+	assert.Empty(t, CalculateStandings(settings, nil))
+}
+
+func TestCalculateSeasonStandings(t *testing.T) {
+	events := []EventResults{
+		{
+			EventName: "Week_1",
+			Rankings: game.Rankings{
+				{TeamId: 254, Rank: 1, RankingFields: game.RankingFields{RankingPoints: 10, Wins: 3, Played: 3}},
+				{TeamId: 1114, Rank: 2, RankingFields: game.RankingFields{RankingPoints: 7, Wins: 2, Played: 3}},
+			},
+		},
+		{
+			EventName: "Week_2",
+			Rankings: game.Rankings{
+				{TeamId: 1114, Rank: 5, RankingFields: game.RankingFields{RankingPoints: 4, Wins: 1, Played: 3}},
+			},
+		},
+	}
+
+	// Check that each team's points are just its total ranking points across every event, regardless of placement.
+	standings := CalculateStandings(SeasonSettings(), events)
+	if assert.Equal(t, 2, len(standings)) {
+		assert.Equal(t, 1114, standings[0].TeamId)
+		assert.Equal(t, 11, standings[0].Points)
+		assert.Equal(t, 2, len(standings[0].Events))
+		assert.Equal(t, 254, standings[1].TeamId)
+		assert.Equal(t, 10, standings[1].Points)
+	}
+}
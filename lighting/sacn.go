@@ -0,0 +1,118 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for driving DMX lighting fixtures over the network using the sACN (ANSI E1.31) protocol.
+
+package lighting
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/Team254/cheesy-arena/logging"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	sacnPort             = 5568
+	sacnSourceName       = "Cheesy Arena"
+	sacnPriority         = 100
+	sacnHeaderLength     = 126
+	sacnKeepAlivePeriod  = time.Second
+	dmxUniverseSize      = 512
+	sacnRootVector       = 0x00000004
+	sacnFramingVector    = 0x00000002
+	sacnDmpVector        = 0x02
+	sacnDmpAddressType   = 0xa1
+	sacnFlagsLengthFlags = 0x7000
+)
+
+// Component identifier sent with every packet; receivers use it to tell sources apart.
+var sacnCid = [16]byte{0x43, 0x68, 0x65, 0x65, 0x73, 0x79, 0x20, 0x41, 0x72, 0x65, 0x6e, 0x61, 0x00, 0x02, 0x05, 0x04}
+
+// Represents a connection to an sACN receiver, such as a DMX node or a lighting console.
+type SacnController struct {
+	address      string
+	universe     uint16
+	udpConn      net.Conn
+	sequence     byte
+	lastChannels []byte
+	lastSendTime time.Time
+}
+
+// Sets the IP address of the receiver and the DMX universe to send to. An empty address disables output.
+func (controller *SacnController) SetAddress(address string, universe int) {
+	if controller.udpConn != nil {
+		_ = controller.udpConn.Close()
+		controller.udpConn = nil
+	}
+	controller.address = address
+	controller.universe = uint16(universe)
+	controller.lastChannels = nil
+	if address == "" {
+		return
+	}
+
+	var err error
+	controller.udpConn, err = net.Dial("udp4", net.JoinHostPort(address, strconv.Itoa(sacnPort)))
+	if err != nil {
+		logging.Hardware.Error("Failed to connect to sACN receiver.", "address", address, "error", err)
+	}
+}
+
+// Returns true if a receiver has been configured.
+func (controller *SacnController) IsEnabled() bool {
+	return controller.udpConn != nil
+}
+
+// Outputs the given channel levels, starting from DMX channel 1. A packet is only sent when the levels change or when
+// needed to keep the receiver from timing out.
+func (controller *SacnController) SetChannels(channels []byte) error {
+	if controller.udpConn == nil {
+		return nil
+	}
+	if bytes.Equal(channels, controller.lastChannels) && time.Since(controller.lastSendTime) < sacnKeepAlivePeriod {
+		return nil
+	}
+
+	controller.sequence++
+	if _, err := controller.udpConn.Write(
+		generateSacnPacket(controller.universe, controller.sequence, channels),
+	); err != nil {
+		return err
+	}
+	controller.lastChannels = bytes.Clone(channels)
+	controller.lastSendTime = time.Now()
+	return nil
+}
+
+// Returns an E1.31 data packet containing the given channel levels, padded out to a full universe.
+func generateSacnPacket(universe uint16, sequence byte, channels []byte) []byte {
+	packet := make([]byte, sacnHeaderLength+dmxUniverseSize)
+
+	// Root layer.
+	binary.BigEndian.PutUint16(packet[0:2], 0x0010)
+	copy(packet[4:16], "ASC-E1.17\x00\x00\x00")
+	binary.BigEndian.PutUint16(packet[16:18], sacnFlagsLengthFlags|uint16(len(packet)-16))
+	binary.BigEndian.PutUint32(packet[18:22], sacnRootVector)
+	copy(packet[22:38], sacnCid[:])
+
+	// Framing layer.
+	binary.BigEndian.PutUint16(packet[38:40], sacnFlagsLengthFlags|uint16(len(packet)-38))
+	binary.BigEndian.PutUint32(packet[40:44], sacnFramingVector)
+	copy(packet[44:108], sacnSourceName)
+	packet[108] = sacnPriority
+	packet[111] = sequence
+	binary.BigEndian.PutUint16(packet[113:115], universe)
+
+	// DMP layer.
+	binary.BigEndian.PutUint16(packet[115:117], sacnFlagsLengthFlags|uint16(len(packet)-115))
+	packet[117] = sacnDmpVector
+	packet[118] = sacnDmpAddressType
+	binary.BigEndian.PutUint16(packet[121:123], 1)
+	binary.BigEndian.PutUint16(packet[123:125], dmxUniverseSize+1)
+	copy(packet[sacnHeaderLength:], channels)
+
+	return packet
+}
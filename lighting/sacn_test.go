@@ -0,0 +1,80 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package lighting
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGenerateSacnPacket(t *testing.T) {
+	packet := generateSacnPacket(7, 42, []byte{255, 128, 0})
+	assert.Equal(t, 638, len(packet))
+	assert.Equal(t, "ASC-E1.17", string(packet[4:13]))
+	assert.Equal(t, uint16(0x7000|622), binary.BigEndian.Uint16(packet[16:18]))
+	assert.Equal(t, uint32(4), binary.BigEndian.Uint32(packet[18:22]))
+	assert.Equal(t, uint16(0x7000|600), binary.BigEndian.Uint16(packet[38:40]))
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(packet[40:44]))
+	assert.Equal(t, "Cheesy Arena", string(packet[44:56]))
+	assert.Equal(t, byte(100), packet[108])
+	assert.Equal(t, byte(42), packet[111])
+	assert.Equal(t, uint16(7), binary.BigEndian.Uint16(packet[113:115]))
+	assert.Equal(t, uint16(0x7000|523), binary.BigEndian.Uint16(packet[115:117]))
+	assert.Equal(t, []byte{0x02, 0xa1}, packet[117:119])
+	assert.Equal(t, uint16(513), binary.BigEndian.Uint16(packet[123:125]))
+	assert.Equal(t, []byte{0, 255, 128, 0, 0}, packet[125:130])
+}
+
+func TestSacnController(t *testing.T) {
+	// Listen where the controller will send its packets.
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: sacnPort})
+	if err != nil {
+		t.Skipf("Unable to listen on the sACN port: %v", err)
+	}
+	defer udpConn.Close()
+	readPacket := func() []byte {
+		packet := make([]byte, 1024)
+		udpConn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		length, err := udpConn.Read(packet)
+		if err != nil {
+			return nil
+		}
+		return packet[:length]
+	}
+
+	var controller SacnController
+	assert.False(t, controller.IsEnabled())
+	assert.Nil(t, controller.SetChannels([]byte{255}))
+
+	controller.SetAddress("127.0.0.1", 3)
+	assert.True(t, controller.IsEnabled())
+	assert.Nil(t, controller.SetChannels([]byte{255, 0, 0}))
+	packet := readPacket()
+	if assert.Equal(t, 638, len(packet)) {
+		assert.Equal(t, byte(1), packet[111])
+		assert.Equal(t, uint16(3), binary.BigEndian.Uint16(packet[113:115]))
+		assert.Equal(t, []byte{255, 0, 0}, packet[126:129])
+	}
+
+	// Unchanged levels shouldn't be resent until the keepalive period elapses.
+	assert.Nil(t, controller.SetChannels([]byte{255, 0, 0}))
+	assert.Nil(t, readPacket())
+	controller.lastSendTime = time.Now().Add(-2 * time.Second)
+	assert.Nil(t, controller.SetChannels([]byte{255, 0, 0}))
+	packet = readPacket()
+	if assert.NotNil(t, packet) {
+		assert.Equal(t, byte(2), packet[111])
+	}
+	assert.Nil(t, controller.SetChannels([]byte{0, 0, 255}))
+	packet = readPacket()
+	if assert.NotNil(t, packet) {
+		assert.Equal(t, []byte{0, 0, 255}, packet[126:129])
+	}
+
+	controller.SetAddress("", 1)
+	assert.False(t, controller.IsEnabled())
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Definitions of the lighting scenes that are shown in response to the state of the arena.
+
+package lighting
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Identifies the point in the match cycle that a lighting scene is shown for.
+type SceneName string
+
+const (
+	ScenePreMatch   SceneName = "preMatch"
+	SceneAuto       SceneName = "auto"
+	SceneTeleop     SceneName = "teleop"
+	SceneEndgame    SceneName = "endgame"
+	SceneFieldFault SceneName = "fieldFault"
+	ScenePostMatch  SceneName = "postMatch"
+	SceneRedWin     SceneName = "redWin"
+	SceneBlueWin    SceneName = "blueWin"
+	SceneTie        SceneName = "tie"
+)
+
+// Ordered list of all the valid scene names.
+var SceneNames = []SceneName{
+	ScenePreMatch,
+	SceneAuto,
+	SceneTeleop,
+	SceneEndgame,
+	SceneFieldFault,
+	ScenePostMatch,
+	SceneRedWin,
+	SceneBlueWin,
+	SceneTie,
+}
+
+// Represents the DMX channel levels to output for a given scene, starting from channel 1.
+type Scene struct {
+	Name     SceneName
+	Channels []byte
+}
+
+// The standard scenes, which assume a single RGB fixture occupying channels 1 through 3.
+var DefaultScenes = []Scene{
+	{ScenePreMatch, []byte{255, 255, 255}},
+	{SceneAuto, []byte{255, 255, 0}},
+	{SceneTeleop, []byte{0, 255, 0}},
+	{SceneEndgame, []byte{255, 165, 0}},
+	{SceneFieldFault, []byte{255, 80, 0}},
+	{ScenePostMatch, []byte{0, 0, 0}},
+	{SceneRedWin, []byte{255, 0, 0}},
+	{SceneBlueWin, []byte{0, 0, 255}},
+	{SceneTie, []byte{255, 0, 255}},
+}
+
+// Parses a set of scenes having one scene per line in the format "name:level1,level2,...".
+func ParseScenes(text string) ([]Scene, error) {
+	var scenes []Scene
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, levels, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("Invalid lighting scene '%s'; expected 'name:level1,level2,...'.", line)
+		}
+		scene := Scene{Name: SceneName(strings.TrimSpace(name))}
+		if !slices.Contains(SceneNames, scene.Name) {
+			return nil, fmt.Errorf("Invalid scene name '%s' in lighting scene '%s'.", scene.Name, line)
+		}
+		if slices.ContainsFunc(scenes, func(other Scene) bool { return other.Name == scene.Name }) {
+			return nil, fmt.Errorf("Lighting scene '%s' is defined more than once.", scene.Name)
+		}
+		for _, level := range strings.Split(levels, ",") {
+			value, err := strconv.Atoi(strings.TrimSpace(level))
+			if err != nil || value < 0 || value > 255 {
+				return nil, fmt.Errorf("Invalid level '%s' in lighting scene '%s'.", level, line)
+			}
+			scene.Channels = append(scene.Channels, byte(value))
+		}
+		if len(scene.Channels) > dmxUniverseSize {
+			return nil, fmt.Errorf("Lighting scene '%s' has more than %d channels.", scene.Name, dmxUniverseSize)
+		}
+		scenes = append(scenes, scene)
+	}
+	return scenes, nil
+}
+
+// Formats the given scenes in the format accepted by ParseScenes.
+func FormatScenes(scenes []Scene) string {
+	lines := make([]string, len(scenes))
+	for i, scene := range scenes {
+		levels := make([]string, len(scene.Channels))
+		for j, level := range scene.Channels {
+			levels[j] = strconv.Itoa(int(level))
+		}
+		lines[i] = fmt.Sprintf("%s:%s", scene.Name, strings.Join(levels, ","))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the scene having the given name, or nil if there is none.
+func FindScene(scenes []Scene, name SceneName) *Scene {
+	for i := range scenes {
+		if scenes[i].Name == name {
+			return &scenes[i]
+		}
+	}
+	return nil
+}
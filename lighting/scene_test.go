@@ -0,0 +1,43 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package lighting
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseScenes(t *testing.T) {
+	scenes, err := ParseScenes("preMatch:255,255,255\n\n  redWin: 255, 0, 0 ,10\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []Scene{{ScenePreMatch, []byte{255, 255, 255}}, {SceneRedWin, []byte{255, 0, 0, 10}}}, scenes)
+	assert.Equal(t, "preMatch:255,255,255\nredWin:255,0,0,10", FormatScenes(scenes))
+	assert.Equal(t, []byte{255, 0, 0, 10}, FindScene(scenes, SceneRedWin).Channels)
+	assert.Nil(t, FindScene(scenes, SceneBlueWin))
+
+	scenes, err = ParseScenes(FormatScenes(DefaultScenes))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultScenes, scenes)
+
+	_, err = ParseScenes("preMatch=255")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid lighting scene 'preMatch=255'; expected 'name:level1,level2,...'.", err.Error())
+	}
+	_, err = ParseScenes("blorpy:255")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid scene name 'blorpy' in lighting scene 'blorpy:255'.", err.Error())
+	}
+	_, err = ParseScenes("auto:255,256")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid level '256' in lighting scene 'auto:255,256'.", err.Error())
+	}
+	_, err = ParseScenes("auto:255,,0")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid level ''")
+	}
+	_, err = ParseScenes("auto:1\nauto:2")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Lighting scene 'auto' is defined more than once.", err.Error())
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Leveled, structured logging for the server, tagged by the subsystem that each message comes from. Messages go to the
+// console, to a rotating log file if one is configured, and to an in-memory buffer of recent entries that the log
+// viewer page reads from and can follow live.
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ArenaSubsystem        = "arena"
+	DatabaseSubsystem     = "database"
+	HardwareSubsystem     = "hardware"
+	NetworkSubsystem      = "network"
+	NotificationSubsystem = "notification"
+	PartnerSubsystem      = "partner"
+	PlcSubsystem          = "plc"
+	ScoringSubsystem      = "scoring"
+	WebSubsystem          = "web"
+
+	// Subsystem under which messages logged through the standard library's log package are recorded.
+	generalSubsystem = "general"
+
+	// Number of recent entries kept in memory for the log viewer.
+	recentEntriesCapacity = 2000
+
+	// Number of entries that can be waiting to go out to a live tail before further ones are dropped.
+	subscriberBufferSize = 100
+)
+
+// Ordered list of the subsystems that messages can be logged under.
+var Subsystems = []string{
+	ArenaSubsystem,
+	DatabaseSubsystem,
+	HardwareSubsystem,
+	NetworkSubsystem,
+	NotificationSubsystem,
+	PartnerSubsystem,
+	PlcSubsystem,
+	ScoringSubsystem,
+	WebSubsystem,
+	generalSubsystem,
+}
+
+// Loggers for each subsystem.
+var (
+	Arena        = NewLogger(ArenaSubsystem)
+	Database     = NewLogger(DatabaseSubsystem)
+	Hardware     = NewLogger(HardwareSubsystem)
+	Network      = NewLogger(NetworkSubsystem)
+	Notification = NewLogger(NotificationSubsystem)
+	Partner      = NewLogger(PartnerSubsystem)
+	Plc          = NewLogger(PlcSubsystem)
+	Scoring      = NewLogger(ScoringSubsystem)
+	Web          = NewLogger(WebSubsystem)
+)
+
+// A single logged message along with its context.
+type Entry struct {
+	Time      time.Time
+	Level     string
+	Subsystem string
+	Message   string
+	Attrs     []Attr `json:",omitempty"`
+}
+
+// A key/value pair of context attached to a logged message.
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Criteria for selecting log entries; empty fields match everything.
+type Filter struct {
+	Subsystem string
+	MinLevel  string
+	Search    string
+}
+
+var sink = &logSink{console: os.Stderr, subscribers: make(map[chan Entry]struct{})}
+
+var minLevel = new(slog.LevelVar)
+
+func init() {
+	// Route anything logged through the standard library's log package, including by other libraries, through here too.
+	log.SetFlags(0)
+	slog.SetDefault(NewLogger(generalSubsystem))
+}
+
+// Returns a logger that tags its messages with the given subsystem.
+func NewLogger(subsystem string) *slog.Logger {
+	return slog.New(&handler{subsystem: subsystem})
+}
+
+// Sets the lowest level of message that is logged.
+func SetLevel(level slog.Level) {
+	minLevel.Set(level)
+}
+
+// Additionally writes all messages as JSON lines to the given file, which is rotated once it grows past the given size,
+// keeping the given number of older files alongside it.
+func EnableFileOutput(path string, maxBytes int64, maxBackups int) error {
+	file, err := openRotatingFile(path, maxBytes, maxBackups)
+	if err != nil {
+		return err
+	}
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.file != nil {
+		sink.file.Close()
+	}
+	sink.file = file
+	return nil
+}
+
+// Stops writing messages to the log file, if one was configured.
+func Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.file == nil {
+		return nil
+	}
+	err := sink.file.Close()
+	sink.file = nil
+	return err
+}
+
+// Returns the most recent entries matching the given filter, oldest first.
+func RecentEntries(filter Filter) []Entry {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	var entries []Entry
+	for _, entry := range sink.recentEntries {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Returns a channel that receives every entry logged from now on, and a function to call to stop receiving them.
+// Entries are dropped rather than holding up logging if the channel isn't being read quickly enough.
+func Subscribe() (<-chan Entry, func()) {
+	entries := make(chan Entry, subscriberBufferSize)
+	sink.mutex.Lock()
+	sink.subscribers[entries] = struct{}{}
+	sink.mutex.Unlock()
+
+	var once sync.Once
+	return entries, func() {
+		once.Do(func() {
+			sink.mutex.Lock()
+			defer sink.mutex.Unlock()
+			delete(sink.subscribers, entries)
+			close(entries)
+		})
+	}
+}
+
+// Returns true if the given entry satisfies all the criteria of the filter.
+func (filter Filter) Matches(entry Entry) bool {
+	if filter.Subsystem != "" && entry.Subsystem != filter.Subsystem {
+		return false
+	}
+	if filter.MinLevel != "" {
+		var minLevel, level slog.Level
+		if minLevel.UnmarshalText([]byte(filter.MinLevel)) == nil && level.UnmarshalText([]byte(entry.Level)) == nil &&
+			level < minLevel {
+			return false
+		}
+	}
+	if filter.Search != "" && !strings.Contains(strings.ToLower(entry.String()), strings.ToLower(filter.Search)) {
+		return false
+	}
+	return true
+}
+
+// Formats the entry as a single line of text.
+func (entry Entry) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(
+		&builder, "%s %-5s [%s] %s", entry.Time.Format("2006-01-02 15:04:05.000"), entry.Level, entry.Subsystem,
+		entry.Message,
+	)
+	for _, attr := range entry.Attrs {
+		value := attr.Value
+		if value == "" || strings.ContainsAny(value, " \"=\n") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&builder, " %s=%s", attr.Key, value)
+	}
+	return builder.String()
+}
+
+// Destination shared by all the loggers.
+type logSink struct {
+	console       io.Writer
+	file          *rotatingFile
+	recentEntries []Entry
+	subscribers   map[chan Entry]struct{}
+	mutex         sync.Mutex
+}
+
+func (sink *logSink) write(entry Entry) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	fmt.Fprintln(sink.console, entry.String())
+	if sink.file != nil {
+		if err := sink.file.writeEntry(entry); err != nil {
+			fmt.Fprintf(sink.console, "Failed to write to log file: %v\n", err)
+		}
+	}
+	if len(sink.recentEntries) >= recentEntriesCapacity {
+		sink.recentEntries = slices.Delete(sink.recentEntries, 0, len(sink.recentEntries)-recentEntriesCapacity+1)
+	}
+	sink.recentEntries = append(sink.recentEntries, entry)
+	for subscriber := range sink.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+		}
+	}
+}
+
+// Implementation of slog.Handler that converts records into entries for the log sink.
+type handler struct {
+	subsystem string
+	attrs     []Attr
+	group     string
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= minLevel.Level()
+}
+
+func (h *handler) Handle(_ context.Context, record slog.Record) error {
+	entry := Entry{
+		Time:      record.Time,
+		Level:     record.Level.String(),
+		Subsystem: h.subsystem,
+		Message:   strings.TrimSpace(record.Message),
+		Attrs:     slices.Clone(h.attrs),
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		entry.Attrs = appendAttr(entry.Attrs, h.group, attr)
+		return true
+	})
+	sink.write(entry)
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	newHandler := *h
+	newHandler.attrs = slices.Clone(h.attrs)
+	for _, attr := range attrs {
+		newHandler.attrs = appendAttr(newHandler.attrs, h.group, attr)
+	}
+	return &newHandler
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	newHandler := *h
+	newHandler.group = joinAttrKey(h.group, name)
+	return &newHandler
+}
+
+// Flattens the given attribute, and any that it groups together, onto the given list.
+func appendAttr(attrs []Attr, group string, attr slog.Attr) []Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, groupAttr := range attr.Value.Group() {
+			attrs = appendAttr(attrs, joinAttrKey(group, attr.Key), groupAttr)
+		}
+		return attrs
+	}
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	return append(attrs, Attr{Key: joinAttrKey(group, attr.Key), Value: attr.Value.String()})
+}
+
+func joinAttrKey(group, key string) string {
+	if group == "" {
+		return key
+	}
+	if key == "" {
+		return group
+	}
+	return group + "." + key
+}
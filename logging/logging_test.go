@@ -0,0 +1,162 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoggerEntries(t *testing.T) {
+	setupTestSink(t)
+
+	Arena.Info("Loaded match.", "match", "Q12", "teams", []int{254, 1114})
+	Network.With("station", "R1").WithGroup("ds").Warn("Driver station dropped.", "team", 254)
+	log.Printf("From the standard library")
+	Scoring.Debug("Not logged at the default level.")
+
+	entries := RecentEntries(Filter{})
+	if assert.Equal(t, 3, len(entries)) {
+		assert.Equal(t, "INFO", entries[0].Level)
+		assert.Equal(t, ArenaSubsystem, entries[0].Subsystem)
+		assert.Equal(t, "Loaded match.", entries[0].Message)
+		assert.Equal(t, []Attr{{"match", "Q12"}, {"teams", "[254 1114]"}}, entries[0].Attrs)
+		assert.Equal(t, "WARN", entries[1].Level)
+		assert.Equal(t, []Attr{{"station", "R1"}, {"ds.team", "254"}}, entries[1].Attrs)
+		assert.Equal(t, generalSubsystem, entries[2].Subsystem)
+		assert.Equal(t, "From the standard library", entries[2].Message)
+	}
+
+	SetLevel(slog.LevelDebug)
+	defer SetLevel(slog.LevelInfo)
+	Scoring.Debug("Logged at the debug level.")
+	assert.Equal(t, 4, len(RecentEntries(Filter{})))
+}
+
+func TestFilter(t *testing.T) {
+	setupTestSink(t)
+
+	Arena.Info("Loaded match.", "match", "Q12")
+	Network.Warn("Driver station dropped.", "team", 254)
+	Network.Error("Failed to configure team WiFi.")
+
+	assert.Equal(t, 3, len(RecentEntries(Filter{})))
+	assert.Equal(t, 2, len(RecentEntries(Filter{Subsystem: NetworkSubsystem})))
+	assert.Equal(t, 2, len(RecentEntries(Filter{MinLevel: "WARN"})))
+	assert.Equal(t, 1, len(RecentEntries(Filter{Subsystem: NetworkSubsystem, MinLevel: "error"})))
+	entries := RecentEntries(Filter{Search: "team=254"})
+	if assert.Equal(t, 1, len(entries)) {
+		assert.Equal(t, "Driver station dropped.", entries[0].Message)
+	}
+	assert.Equal(t, 1, len(RecentEntries(Filter{Search: "q12"})))
+	assert.Empty(t, RecentEntries(Filter{Search: "blorpy"}))
+}
+
+func TestRecentEntriesCapacity(t *testing.T) {
+	setupTestSink(t)
+
+	for i := 0; i < recentEntriesCapacity+10; i++ {
+		Arena.Info("Tick.", "index", i)
+	}
+	entries := RecentEntries(Filter{})
+	if assert.Equal(t, recentEntriesCapacity, len(entries)) {
+		assert.Equal(t, "10", entries[0].Attrs[0].Value)
+		assert.Equal(t, "2009", entries[len(entries)-1].Attrs[0].Value)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	setupTestSink(t)
+
+	entries, unsubscribe := Subscribe()
+	Plc.Error("PLC error.", "error", io.EOF)
+	select {
+	case entry := <-entries:
+		assert.Equal(t, "PLC error.", entry.Message)
+		assert.Equal(t, []Attr{{"error", "EOF"}}, entry.Attrs)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timed out waiting for log entry")
+	}
+
+	// Check that a subscriber that isn't keeping up doesn't hold up logging.
+	for i := 0; i < subscriberBufferSize+10; i++ {
+		Plc.Info("Tick.")
+	}
+	assert.Equal(t, subscriberBufferSize, len(entries))
+
+	unsubscribe()
+	unsubscribe()
+	for range entries {
+	}
+	Plc.Info("After unsubscribing.")
+}
+
+func TestEntryString(t *testing.T) {
+	entry := Entry{
+		Time:      time.Date(2024, 3, 9, 14, 5, 6, 7000000, time.UTC),
+		Level:     "WARN",
+		Subsystem: NetworkSubsystem,
+		Message:   "Radio pairing issue.",
+		Attrs:     []Attr{{"station", "R1"}, {"issue", "wrong SSID"}, {"note", ""}},
+	}
+	assert.Equal(
+		t,
+		"2024-03-09 14:05:06.007 WARN  [network] Radio pairing issue. station=R1 issue=\"wrong SSID\" note=\"\"",
+		entry.String(),
+	)
+}
+
+func TestFileOutput(t *testing.T) {
+	setupTestSink(t)
+	path := filepath.Join(t.TempDir(), "logs", "test.log")
+
+	assert.Nil(t, EnableFileOutput(path, 300, 2))
+	for i := 0; i < 10; i++ {
+		Arena.Info("Writing a line to the log file.", "index", i)
+	}
+	assert.Nil(t, Close())
+
+	// Check that the file was rotated, keeping only the given number of older files.
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var lastEntry Entry
+	lineCount := 0
+	for scanner.Scan() {
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &lastEntry))
+		lineCount++
+	}
+	assert.True(t, lineCount > 0)
+	assert.Equal(t, "Writing a line to the log file.", lastEntry.Message)
+	assert.Equal(t, []Attr{{"index", "9"}}, lastEntry.Attrs)
+	info, _ := file.Stat()
+	assert.LessOrEqual(t, info.Size(), int64(300))
+}
+
+// Clears out the entries logged by any previous tests and silences the console output for the duration of the test.
+func setupTestSink(t *testing.T) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.recentEntries = nil
+	console := sink.console
+	sink.console = io.Discard
+	t.Cleanup(func() {
+		sink.mutex.Lock()
+		defer sink.mutex.Unlock()
+		sink.console = console
+	})
+}
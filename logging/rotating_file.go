@@ -0,0 +1,93 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Log file that is set aside and replaced with a fresh one once it reaches a given size.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	rotatingFile := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := rotatingFile.open(); err != nil {
+		return nil, err
+	}
+	return rotatingFile, nil
+}
+
+// Appends the given entry to the file as a line of JSON, rotating the file first if the entry wouldn't fit.
+func (rotatingFile *rotatingFile) writeEntry(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if rotatingFile.size > 0 && rotatingFile.size+int64(len(line)) > rotatingFile.maxBytes {
+		if err = rotatingFile.rotate(); err != nil {
+			return err
+		}
+	}
+	written, err := rotatingFile.file.Write(line)
+	rotatingFile.size += int64(written)
+	return err
+}
+
+func (rotatingFile *rotatingFile) Close() error {
+	return rotatingFile.file.Close()
+}
+
+func (rotatingFile *rotatingFile) open() error {
+	file, err := os.OpenFile(rotatingFile.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rotatingFile.file = file
+	rotatingFile.size = info.Size()
+	return nil
+}
+
+// Shifts each older file along by one, discarding the oldest, and starts a new file in place of the current one.
+func (rotatingFile *rotatingFile) rotate() error {
+	if err := rotatingFile.file.Close(); err != nil {
+		return err
+	}
+	for i := rotatingFile.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(rotatingFile.backupPath(i), rotatingFile.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if rotatingFile.maxBackups > 0 {
+		if err := os.Rename(rotatingFile.path, rotatingFile.backupPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(rotatingFile.path); err != nil {
+		return err
+	}
+	return rotatingFile.open()
+}
+
+func (rotatingFile *rotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", rotatingFile.path, index)
+}
@@ -7,25 +7,109 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"github.com/Team254/cheesy-arena/config"
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/updater"
 	"github.com/Team254/cheesy-arena/web"
-	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Embeds the time zone database so that the event's time zone can be loaded on any OS.
 )
 
-const eventDbPath = "./event.db"
-const httpPort = 8080
+const (
+	logFileMaxBytes   = 10 * 1024 * 1024
+	logFileMaxBackups = 5
+	shutdownTimeout   = 5 * time.Second
+
+	// Exit code used when shutting down to run a newly applied software update.
+	updateRestartExitCode = 3
+)
 
 // Main entry point for the application.
 func main() {
-	arena, err := field.NewArena(eventDbPath)
+	config, err := config.Load(os.Args[1:], os.Getenv, os.Stderr)
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
+	if err = os.Chdir(config.BaseDir); err != nil {
+		exitWithStartupError(err)
+	}
+	if err = logging.EnableFileOutput(config.LogFile, logFileMaxBytes, logFileMaxBackups); err != nil {
+		exitWithStartupError(err)
+	}
+	logging.SetLevel(config.LogLevel)
+
+	arena, err := field.NewArena(config.DbPath)
 	if err != nil {
-		log.Fatalln("Error during startup: ", err)
+		exitWithStartupError(err)
+	}
+	arena.Updater = updater.NewUpdater(config.UpdateUrl, updater.Version, updater.StagingDir)
+	for i := 1; i < config.Fields; i++ {
+		if _, err = arena.AddField(); err != nil {
+			exitWithStartupError(err)
+		}
+	}
+	if config.RobotSimulation {
+		for _, fieldArena := range arena.Fields() {
+			if err = fieldArena.SetRobotSimulationEnabled(true); err != nil {
+				exitWithStartupError(err)
+			}
+		}
 	}
 
 	// Start the web server in a separate goroutine.
 	web := web.NewWeb(arena)
-	go web.ServeWebInterface(httpPort)
+	if err = web.SetTrustedProxies(config.TrustedProxies); err != nil {
+		exitWithStartupError(err)
+	}
+	if config.HttpsEnabled() {
+		if err = web.EnableHttps(config.HttpsPort, config.TlsCertFile, config.TlsKeyFile); err != nil {
+			exitWithStartupError(err)
+		}
+	}
+	go web.ServeWebInterface(config.HttpPort)
 
-	// Run the arena state machine in the main thread.
+	// Save the state of any match in progress before exiting so that it can be resumed after a restart, and let the
+	// connected clients know that the server is going down so that they reconnect and reload as soon as it is back. A
+	// software update that has just been applied exits with a distinct code so that a supervisor such as systemd
+	// starts the new build.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		exitCode := 0
+		select {
+		case <-signals:
+			logging.Arena.Info("Shutting down.")
+		case <-arena.RestartRequests():
+			logging.Arena.Info("Shutting down to restart with the updated software.")
+			exitCode = updateRestartExitCode
+		}
+		web.Shutdown(shutdownTimeout)
+		if err := arena.Shutdown(); err != nil {
+			logging.Arena.Error("Error during shutdown.", "error", err)
+		}
+		logging.Close()
+		os.Exit(exitCode)
+	}()
+
+	// Run the arena state machine of the first field in the main thread and those of any other fields alongside it.
+	for _, fieldArena := range arena.Fields()[1:] {
+		go fieldArena.Run()
+	}
 	arena.Run()
 }
+
+func exitWithStartupError(err error) {
+	logging.Arena.Error("Error during startup.", "error", err)
+	logging.Close()
+	os.Exit(1)
+}
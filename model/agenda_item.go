@@ -0,0 +1,76 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a timed item on the event day's run of show, such as the opening ceremony.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type AgendaItem struct {
+	Id          int `db:"id"`
+	Name        string
+	StartTime   time.Time
+	DurationMin int
+}
+
+func (database *Database) CreateAgendaItem(agendaItem *AgendaItem) error {
+	if err := agendaItem.validate(); err != nil {
+		return err
+	}
+	return database.agendaItemTable.create(agendaItem)
+}
+
+func (database *Database) GetAgendaItemById(id int) (*AgendaItem, error) {
+	return database.agendaItemTable.getById(id)
+}
+
+func (database *Database) UpdateAgendaItem(agendaItem *AgendaItem) error {
+	if err := agendaItem.validate(); err != nil {
+		return err
+	}
+	return database.agendaItemTable.update(agendaItem)
+}
+
+func (database *Database) DeleteAgendaItem(id int) error {
+	return database.agendaItemTable.delete(id)
+}
+
+func (database *Database) TruncateAgendaItems() error {
+	return database.agendaItemTable.truncate()
+}
+
+// Returns all the agenda items in the order in which they start.
+func (database *Database) GetAllAgendaItems() ([]AgendaItem, error) {
+	agendaItems, err := database.agendaItemTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(agendaItems, func(i, j int) bool {
+		return agendaItems[i].StartTime.Before(agendaItems[j].StartTime)
+	})
+	return agendaItems, nil
+}
+
+// Returns the time at which the item is scheduled to be over.
+func (agendaItem *AgendaItem) EndTime() time.Time {
+	return agendaItem.StartTime.Add(time.Duration(agendaItem.DurationMin) * time.Minute)
+}
+
+func (agendaItem *AgendaItem) validate() error {
+	if strings.TrimSpace(agendaItem.Name) == "" {
+		return fmt.Errorf("agenda item name must not be blank")
+	}
+	if agendaItem.StartTime.IsZero() {
+		return fmt.Errorf("agenda item start time must be set")
+	}
+	if agendaItem.DurationMin < 0 {
+		return fmt.Errorf("agenda item duration must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAgendaItemCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	agendaItem, err := db.GetAgendaItemById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, agendaItem)
+
+	agendaItem1 := AgendaItem{Name: "Awards", StartTime: time.Unix(5000, 0).UTC(), DurationMin: 45}
+	assert.Nil(t, db.CreateAgendaItem(&agendaItem1))
+	agendaItem2 := AgendaItem{Name: "Opening Ceremony", StartTime: time.Unix(1000, 0).UTC(), DurationMin: 15}
+	assert.Nil(t, db.CreateAgendaItem(&agendaItem2))
+	assert.NotNil(t, db.CreateAgendaItem(&AgendaItem{Name: " ", StartTime: time.Unix(1000, 0)}))
+	assert.NotNil(t, db.CreateAgendaItem(&AgendaItem{Name: "Lunch"}))
+	assert.NotNil(t, db.CreateAgendaItem(&AgendaItem{Name: "Lunch", StartTime: time.Unix(1000, 0), DurationMin: -1}))
+
+	agendaItems, err := db.GetAllAgendaItems()
+	assert.Nil(t, err)
+	assert.Equal(t, []AgendaItem{agendaItem2, agendaItem1}, agendaItems)
+	assert.Equal(t, time.Unix(1900, 0).UTC(), agendaItems[0].EndTime())
+
+	agendaItem1.Name = "Closing Ceremony"
+	assert.Nil(t, db.UpdateAgendaItem(&agendaItem1))
+	agendaItem, err = db.GetAgendaItemById(agendaItem1.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, agendaItem1, *agendaItem)
+
+	assert.Nil(t, db.DeleteAgendaItem(agendaItem1.Id))
+	agendaItem, err = db.GetAgendaItemById(agendaItem1.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, agendaItem)
+
+	assert.Nil(t, db.TruncateAgendaItems())
+	agendaItems, err = db.GetAllAgendaItems()
+	assert.Nil(t, err)
+	assert.Empty(t, agendaItems)
+}
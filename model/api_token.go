@@ -0,0 +1,73 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a token granting an external tool access to the versioned REST API.
+
+package model
+
+import "sort"
+
+const (
+	ApiReadScope          = "read"
+	ApiWriteScope         = "write"
+	ApiStaffReadyScope    = "staff_ready"
+	ApiCaptionsScope      = "captions"
+	ApiVisionScoringScope = "vision_scoring"
+)
+
+// Ordered list of the scopes that an API token can be granted. Beyond read and write, each scope grants access only to
+// the integration API of the same name.
+var ApiScopes = []string{ApiReadScope, ApiWriteScope, ApiStaffReadyScope, ApiCaptionsScope, ApiVisionScoringScope}
+
+type ApiToken struct {
+	Id    int `db:"id"`
+	Name  string
+	Token string
+	Scope string
+}
+
+func (database *Database) CreateApiToken(apiToken *ApiToken) error {
+	return database.apiTokenTable.create(apiToken)
+}
+
+func (database *Database) GetApiTokenById(id int) (*ApiToken, error) {
+	return database.apiTokenTable.getById(id)
+}
+
+func (database *Database) GetApiTokenByToken(token string) (*ApiToken, error) {
+	apiTokens, err := database.apiTokenTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiToken := range apiTokens {
+		if apiToken.Token == token {
+			return &apiToken, nil
+		}
+	}
+	return nil, nil
+}
+
+func (database *Database) DeleteApiToken(id int) error {
+	return database.apiTokenTable.delete(id)
+}
+
+func (database *Database) TruncateApiTokens() error {
+	return database.apiTokenTable.truncate()
+}
+
+func (database *Database) GetAllApiTokens() ([]ApiToken, error) {
+	apiTokens, err := database.apiTokenTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(apiTokens, func(i, j int) bool {
+		return apiTokens[i].Id < apiTokens[j].Id
+	})
+	return apiTokens, nil
+}
+
+// Returns true if the token grants the given scope; the write scope also grants read access.
+func (apiToken *ApiToken) HasScope(scope string) bool {
+	return apiToken.Scope == scope || apiToken.Scope == ApiWriteScope && scope == ApiReadScope
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiTokenCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	apiToken := ApiToken{Name: "Stream Graphics", Token: "abc123", Scope: ApiReadScope}
+	assert.Nil(t, db.CreateApiToken(&apiToken))
+	apiToken2, err := db.GetApiTokenById(apiToken.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, apiToken, *apiToken2)
+
+	apiToken2, err = db.GetApiTokenByToken("abc123")
+	assert.Nil(t, err)
+	assert.Equal(t, apiToken, *apiToken2)
+	apiToken2, err = db.GetApiTokenByToken("blorpy")
+	assert.Nil(t, err)
+	assert.Nil(t, apiToken2)
+
+	assert.Nil(t, db.DeleteApiToken(apiToken.Id))
+	apiToken2, err = db.GetApiTokenById(apiToken.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, apiToken2)
+}
+
+func TestTruncateApiTokens(t *testing.T) {
+	db := setupTestDb(t)
+
+	db.CreateApiToken(&ApiToken{Name: "Stream Graphics", Token: "abc123", Scope: ApiReadScope})
+	db.CreateApiToken(&ApiToken{Name: "Inspection App", Token: "def456", Scope: ApiWriteScope})
+	apiTokens, err := db.GetAllApiTokens()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(apiTokens)) {
+		assert.Equal(t, "Stream Graphics", apiTokens[0].Name)
+		assert.Equal(t, "Inspection App", apiTokens[1].Name)
+	}
+
+	assert.Nil(t, db.TruncateApiTokens())
+	apiTokens, err = db.GetAllApiTokens()
+	assert.Nil(t, err)
+	assert.Empty(t, apiTokens)
+}
+
+func TestApiTokenHasScope(t *testing.T) {
+	readToken := ApiToken{Scope: ApiReadScope}
+	assert.True(t, readToken.HasScope(ApiReadScope))
+	assert.False(t, readToken.HasScope(ApiWriteScope))
+
+	writeToken := ApiToken{Scope: ApiWriteScope}
+	assert.True(t, writeToken.HasScope(ApiReadScope))
+	assert.True(t, writeToken.HasScope(ApiWriteScope))
+}
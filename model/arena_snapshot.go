@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore methods for the periodically saved state of the match in progress, which allows a match that was
+// interrupted by the server stopping to be picked up again after a restart.
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"time"
+)
+
+type ArenaSnapshot struct {
+	Id                int `db:"id"`
+	MatchId           int
+	MatchState        int     // Raw value of the arena's match state, which is defined in the field package.
+	FaultedMatchState int     // Match state that a field fault interrupted, if the snapshot was taken during one.
+	MatchTimeSec      float64 // Time elapsed into the match, not counting any time spent in a field fault.
+	RedScore          *game.Score
+	BlueScore         *game.Score
+	RedCards          map[string]string
+	BlueCards         map[string]string
+	SavedAt           time.Time
+}
+
+// Returns the saved snapshot of the match in progress, or nil if there isn't one.
+func (database *Database) GetArenaSnapshot() (*ArenaSnapshot, error) {
+	arenaSnapshots, err := database.arenaSnapshotTable.getAll()
+	if err != nil || len(arenaSnapshots) == 0 {
+		return nil, err
+	}
+	return &arenaSnapshots[0], nil
+}
+
+// Saves the given snapshot in place of any existing one.
+func (database *Database) SaveArenaSnapshot(arenaSnapshot *ArenaSnapshot) error {
+	existingSnapshot, err := database.GetArenaSnapshot()
+	if err != nil {
+		return err
+	}
+	if existingSnapshot == nil {
+		arenaSnapshot.Id = 0
+		return database.arenaSnapshotTable.create(arenaSnapshot)
+	}
+	arenaSnapshot.Id = existingSnapshot.Id
+	return database.arenaSnapshotTable.update(arenaSnapshot)
+}
+
+func (database *Database) DeleteArenaSnapshot() error {
+	return database.arenaSnapshotTable.truncate()
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetNonexistentArenaSnapshot(t *testing.T) {
+	db := setupTestDb(t)
+
+	arenaSnapshot, err := db.GetArenaSnapshot()
+	assert.Nil(t, err)
+	assert.Nil(t, arenaSnapshot)
+}
+
+func TestArenaSnapshotCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	arenaSnapshot := ArenaSnapshot{
+		MatchId:      254,
+		MatchState:   4,
+		MatchTimeSec: 42.5,
+		RedScore:     game.TestScore1(),
+		BlueScore:    game.TestScore2(),
+		RedCards:     map[string]string{"1114": "yellow"},
+		SavedAt:      time.Unix(1000, 0).UTC(),
+	}
+	assert.Nil(t, db.SaveArenaSnapshot(&arenaSnapshot))
+	arenaSnapshot2, err := db.GetArenaSnapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, arenaSnapshot, *arenaSnapshot2)
+
+	// Saving again should replace the existing snapshot rather than adding another.
+	arenaSnapshot3 := ArenaSnapshot{MatchId: 255, MatchState: 6, SavedAt: time.Unix(2000, 0).UTC()}
+	assert.Nil(t, db.SaveArenaSnapshot(&arenaSnapshot3))
+	arenaSnapshots, err := db.arenaSnapshotTable.getAll()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(arenaSnapshots)) {
+		assert.Equal(t, arenaSnapshot3, arenaSnapshots[0])
+	}
+
+	assert.Nil(t, db.DeleteArenaSnapshot())
+	arenaSnapshot2, err = db.GetArenaSnapshot()
+	assert.Nil(t, err)
+	assert.Nil(t, arenaSnapshot2)
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the log of state-changing actions taken through the web interface.
+
+package model
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Value shown in place of sensitive fields such as passwords and API keys, which are never written to the log.
+const AuditRedactedValue = "(hidden)"
+
+// Matches the names of fields and form parameters whose values must not be written to the log.
+var auditSensitiveNameRe = regexp.MustCompile(
+	"(?i)(password|passwordhash|passwordsalt|secret|apikey|privatekey|wpakey)$",
+)
+
+type AuditEntry struct {
+	Id       int `db:"id"`
+	Time     time.Time
+	Username string
+	Action   string
+	Details  string
+	Status   int
+	Changes  []AuditChange
+}
+
+// Represents a single field of a record that was changed by an audited action.
+type AuditChange struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// Criteria for selecting audit log entries; zero-valued criteria match all entries.
+type AuditFilter struct {
+	Username string
+	Search   string
+	Since    time.Time
+	Until    time.Time
+}
+
+func (database *Database) CreateAuditEntry(auditEntry *AuditEntry) error {
+	return database.auditEntryTable.create(auditEntry)
+}
+
+// Returns the audit log entries matching the given filter, most recent first.
+func (database *Database) GetAuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	auditEntries, err := database.auditEntryTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingAuditEntries []AuditEntry
+	for _, auditEntry := range auditEntries {
+		if filter.matches(&auditEntry) {
+			matchingAuditEntries = append(matchingAuditEntries, auditEntry)
+		}
+	}
+	sort.SliceStable(matchingAuditEntries, func(i, j int) bool {
+		return matchingAuditEntries[i].Time.After(matchingAuditEntries[j].Time)
+	})
+	return matchingAuditEntries, nil
+}
+
+// Returns the distinct usernames that appear in the audit log, in alphabetical order.
+func (database *Database) GetAuditUsernames() ([]string, error) {
+	auditEntries, err := database.auditEntryTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	usernameSet := make(map[string]bool)
+	for _, auditEntry := range auditEntries {
+		usernameSet[auditEntry.Username] = true
+	}
+	var usernames []string
+	for username := range usernameSet {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames, nil
+}
+
+func (database *Database) TruncateAuditEntries() error {
+	return database.auditEntryTable.truncate()
+}
+
+// Returns true if the given entry satisfies all the criteria of the filter. The search text is matched against the
+// action, details and changed fields without regard to case.
+func (filter *AuditFilter) matches(auditEntry *AuditEntry) bool {
+	if filter.Username != "" && auditEntry.Username != filter.Username {
+		return false
+	}
+	if !filter.Since.IsZero() && auditEntry.Time.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !auditEntry.Time.Before(filter.Until) {
+		return false
+	}
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		if strings.Contains(strings.ToLower(auditEntry.Action), search) ||
+			strings.Contains(strings.ToLower(auditEntry.Details), search) {
+			return true
+		}
+		for _, change := range auditEntry.Changes {
+			if strings.Contains(strings.ToLower(change.Field), search) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Returns the list of fields that differ between the two values, which must be of the same type, with the values of
+// sensitive fields hidden.
+func DiffAuditValues(oldValue, newValue any) []AuditChange {
+	var fieldChanges [][3]string
+	diffValues("", reflect.ValueOf(oldValue), reflect.ValueOf(newValue), &fieldChanges)
+	var changes []AuditChange
+	for _, fieldChange := range fieldChanges {
+		change := AuditChange{fieldChange[0], fieldChange[1], fieldChange[2]}
+		if IsAuditSensitiveName(change.Field) {
+			change.OldValue = AuditRedactedValue
+			change.NewValue = AuditRedactedValue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Returns true if the field or parameter with the given name holds a value that must not be written to the log.
+func IsAuditSensitiveName(name string) bool {
+	return auditSensitiveNameRe.MatchString(name)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuditEntryCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	startTime := time.Unix(1000, 0).UTC()
+	auditEntry1 := AuditEntry{
+		Time: startTime, Username: "admin", Action: "POST /setup/teams", Details: "teamNumbers=254",
+	}
+	auditEntry2 := AuditEntry{
+		Time:     startTime.Add(time.Minute),
+		Username: "Bertha",
+		Action:   "POST /setup/teams/254/edit",
+		Changes:  []AuditChange{{"Nickname", "Poofs", "The Cheesy Poofs"}},
+	}
+	auditEntry3 := AuditEntry{Time: startTime.Add(2 * time.Minute), Username: "admin", Action: "POST /setup/breaks"}
+	assert.Nil(t, db.CreateAuditEntry(&auditEntry2))
+	assert.Nil(t, db.CreateAuditEntry(&auditEntry1))
+	assert.Nil(t, db.CreateAuditEntry(&auditEntry3))
+
+	auditEntries, err := db.GetAuditEntries(AuditFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []AuditEntry{auditEntry3, auditEntry2, auditEntry1}, auditEntries)
+
+	auditEntries, _ = db.GetAuditEntries(AuditFilter{Username: "admin"})
+	assert.Equal(t, []AuditEntry{auditEntry3, auditEntry1}, auditEntries)
+	auditEntries, _ = db.GetAuditEntries(AuditFilter{Search: "TEAMS"})
+	assert.Equal(t, []AuditEntry{auditEntry2, auditEntry1}, auditEntries)
+	auditEntries, _ = db.GetAuditEntries(AuditFilter{Search: "254"})
+	assert.Equal(t, []AuditEntry{auditEntry2, auditEntry1}, auditEntries)
+	auditEntries, _ = db.GetAuditEntries(AuditFilter{Search: "nickname"})
+	assert.Equal(t, []AuditEntry{auditEntry2}, auditEntries)
+	auditEntries, _ = db.GetAuditEntries(AuditFilter{Since: auditEntry2.Time, Until: auditEntry3.Time})
+	assert.Equal(t, []AuditEntry{auditEntry2}, auditEntries)
+
+	usernames, err := db.GetAuditUsernames()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bertha", "admin"}, usernames)
+
+	assert.Nil(t, db.TruncateAuditEntries())
+	auditEntries, err = db.GetAuditEntries(AuditFilter{})
+	assert.Nil(t, err)
+	assert.Empty(t, auditEntries)
+}
+
+func TestDiffAuditValues(t *testing.T) {
+	oldSettings := EventSettings{Name: "Chezy Champs", ApPassword: "old", PitDisplayPages: []string{"rankings"}}
+	newSettings := oldSettings
+	newSettings.Name = "Madtown Throwdown"
+	newSettings.ApPassword = "new"
+	newSettings.PitDisplayPages = []string{"rankings", "schedule"}
+	assert.Equal(
+		t,
+		[]AuditChange{
+			{"Name", "Chezy Champs", "Madtown Throwdown"},
+			{"ApPassword", AuditRedactedValue, AuditRedactedValue},
+			{"PitDisplayPages[1]", "", "schedule"},
+		},
+		DiffAuditValues(oldSettings, newSettings),
+	)
+	assert.Empty(t, DiffAuditValues(oldSettings, oldSettings))
+
+	assert.True(t, IsAuditSensitiveName("adminPassword"))
+	assert.True(t, IsAuditSensitiveName("PasswordHash"))
+	assert.True(t, IsAuditSensitiveName("FirstApiKey"))
+	assert.True(t, IsAuditSensitiveName("wpaKey"))
+	assert.False(t, IsAuditSensitiveName("GameKey"))
+	assert.False(t, IsAuditSensitiveName("TbaSecretId"))
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for finding, pruning and restoring the database backups saved by Backup().
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reason given for the backups taken on a schedule.
+const PeriodicBackupReason = "periodic"
+
+const backupTimeFormat = "20060102150405"
+
+var backupFilenameRegex = regexp.MustCompile(`^(.*?)_(\d{14})_(.+)\.db$`)
+var postMatchBackupReasonRegex = regexp.MustCompile(`^post_.+_match_.+$`)
+
+// Represents a database backup file in the backups directory.
+type BackupFile struct {
+	Filename  string
+	EventName string
+	Time      time.Time
+	Reason    string
+}
+
+// Returns true if the backup was taken automatically, either on a schedule or after a match, rather than around an
+// administrative action. Only automatic backups are subject to the retention limit.
+func (backup *BackupFile) IsAutomatic() bool {
+	return backup.Reason == PeriodicBackupReason || postMatchBackupReasonRegex.MatchString(backup.Reason)
+}
+
+// Returns the reason to give for the backup taken after committing the given match.
+func PostMatchBackupReason(match *Match) string {
+	return fmt.Sprintf("post_%s_match_%s", match.Type, match.ShortName)
+}
+
+// Returns all the backups in the backups directory, newest first.
+func ListBackups() ([]BackupFile, error) {
+	paths, err := filepath.Glob(filepath.Join(BaseDir, backupsDir, "*.db"))
+	if err != nil {
+		return nil, err
+	}
+	var backups []BackupFile
+	for _, path := range paths {
+		if backup, ok := parseBackupFilename(filepath.Base(path)); ok {
+			backups = append(backups, backup)
+		}
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// Deletes the oldest automatic backups so that no more than the given number of them remain.
+func PruneAutomaticBackups(retentionCount int) error {
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+	numKept := 0
+	for _, backup := range backups {
+		if !backup.IsAutomatic() {
+			continue
+		}
+		if numKept < retentionCount {
+			numKept++
+			continue
+		}
+		if err = os.Remove(filepath.Join(BaseDir, backupsDir, backup.Filename)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the path to the backup having the given filename, or an error if there is no such backup.
+func GetBackupPath(filename string) (string, error) {
+	if _, ok := parseBackupFilename(filename); !ok || filepath.Base(filename) != filename {
+		return "", fmt.Errorf("invalid backup filename '%s'", filename)
+	}
+	path := filepath.Join(BaseDir, backupsDir, filename)
+	if !fileExists(path) {
+		return "", fmt.Errorf("backup '%s' does not exist", filename)
+	}
+	return path, nil
+}
+
+// Overwrites the closed database at the given path with a copy of the backup having the given filename.
+func RestoreBackup(filename, dbPath string) error {
+	backupPath, err := GetBackupPath(filename)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(backupPath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	dest, err := os.Create(dbPath)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+	if _, err = io.Copy(dest, source); err != nil {
+		return err
+	}
+	return dest.Sync()
+}
+
+func parseBackupFilename(filename string) (BackupFile, bool) {
+	matches := backupFilenameRegex.FindStringSubmatch(filename)
+	if matches == nil {
+		return BackupFile{}, false
+	}
+	backupTime, err := time.ParseInLocation(backupTimeFormat, matches[2], time.Local)
+	if err != nil {
+		return BackupFile{}, false
+	}
+	return BackupFile{
+		Filename:  filename,
+		EventName: strings.Replace(matches[1], "_", " ", -1),
+		Time:      backupTime,
+		Reason:    matches[3],
+	}, true
+}
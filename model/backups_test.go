@@ -0,0 +1,86 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListAndPruneBackups(t *testing.T) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	backupsPath := filepath.Join(BaseDir, backupsDir)
+	assert.Nil(t, os.MkdirAll(backupsPath, 0755))
+	for _, filename := range []string{
+		"Chezy_Champs_20240928090000_periodic.db",
+		"Chezy_Champs_20240928091000_periodic.db",
+		"Chezy_Champs_20240928091500_post_Qualification_match_Q1.db",
+		"Chezy_Champs_20240928092000_periodic.db",
+		"Chezy_Champs_20240928080000_post_scheduling.db",
+		"Chezy_Champs_20240928085000_pre_clear.db",
+		"blorpy.db",
+	} {
+		assert.Nil(t, os.WriteFile(filepath.Join(backupsPath, filename), []byte{}, 0644))
+	}
+
+	backups, err := ListBackups()
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(backups)) {
+		assert.Equal(t, "Chezy_Champs_20240928092000_periodic.db", backups[0].Filename)
+		assert.Equal(t, "Chezy Champs", backups[0].EventName)
+		assert.Equal(t, time.Date(2024, 9, 28, 9, 20, 0, 0, time.Local), backups[0].Time)
+		assert.Equal(t, PeriodicBackupReason, backups[0].Reason)
+		assert.True(t, backups[0].IsAutomatic())
+		assert.Equal(t, "post_Qualification_match_Q1", backups[1].Reason)
+		assert.True(t, backups[1].IsAutomatic())
+		assert.Equal(t, "pre_clear", backups[4].Reason)
+		assert.False(t, backups[4].IsAutomatic())
+		assert.False(t, backups[5].IsAutomatic())
+	}
+
+	// Only the oldest automatic backups should be deleted.
+	assert.Nil(t, PruneAutomaticBackups(2))
+	backups, _ = ListBackups()
+	if assert.Equal(t, 4, len(backups)) {
+		assert.Equal(t, "Chezy_Champs_20240928092000_periodic.db", backups[0].Filename)
+		assert.Equal(t, "Chezy_Champs_20240928091500_post_Qualification_match_Q1.db", backups[1].Filename)
+		assert.Equal(t, "Chezy_Champs_20240928085000_pre_clear.db", backups[2].Filename)
+		assert.Equal(t, "Chezy_Champs_20240928080000_post_scheduling.db", backups[3].Filename)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	database, err := OpenDatabase(filepath.Join(BaseDir, "event.db"))
+	assert.Nil(t, err)
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254}))
+	assert.Nil(t, database.Backup("Chezy Champs", PeriodicBackupReason))
+	assert.Nil(t, database.Close())
+	backups, _ := ListBackups()
+	if !assert.Equal(t, 1, len(backups)) {
+		return
+	}
+
+	restoredPath := filepath.Join(BaseDir, "restored.db")
+	assert.Nil(t, RestoreBackup(backups[0].Filename, restoredPath))
+	database, err = OpenDatabase(restoredPath)
+	assert.Nil(t, err)
+	team, _ := database.GetTeamById(254)
+	assert.NotNil(t, team)
+	database.Close()
+
+	err = RestoreBackup("../event.db", restoredPath)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid backup filename")
+	}
+	err = RestoreBackup("Chezy_Champs_20240928092000_periodic.db", restoredPath)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+}
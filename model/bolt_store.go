@@ -0,0 +1,312 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Store implementation backed by an embedded Bolt database file, with one bucket per table.
+
+package model
+
+import (
+	"fmt"
+	"go.etcd.io/bbolt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+type boltStore struct {
+	bolt *bbolt.DB
+}
+
+func openBoltStore(path string) (*boltStore, error) {
+	bolt, err := bbolt.Open(path, 0644, &bbolt.Options{NoSync: true, Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStore{bolt: bolt}, nil
+}
+
+func (store *boltStore) createTable(tableName string) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return txStore.createTable(tableName)
+	})
+}
+
+func (store *boltStore) tableNames() ([]string, error) {
+	var tableNames []string
+	err := store.readTransaction(func(txStore *boltTxStore) error {
+		var err error
+		tableNames, err = txStore.tableNames()
+		return err
+	})
+	return tableNames, err
+}
+
+func (store *boltStore) get(tableName string, id int) ([]byte, error) {
+	var recordJson []byte
+	err := store.readTransaction(func(txStore *boltTxStore) error {
+		var err error
+		recordJson, err = txStore.get(tableName, id)
+		return err
+	})
+	return recordJson, err
+}
+
+func (store *boltStore) forEach(tableName string, handleRecord func(id int, recordJson []byte) error) error {
+	return store.readTransaction(func(txStore *boltTxStore) error {
+		return txStore.forEach(tableName, handleRecord)
+	})
+}
+
+func (store *boltStore) nextId(tableName string) (int, error) {
+	var id int
+	err := store.writeTransaction(func(txStore *boltTxStore) error {
+		var err error
+		id, err = txStore.nextId(tableName)
+		return err
+	})
+	return id, err
+}
+
+func (store *boltStore) insert(tableName string, id int, recordJson []byte) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return txStore.insert(tableName, id, recordJson)
+	})
+}
+
+func (store *boltStore) update(tableName string, id int, recordJson []byte) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return txStore.update(tableName, id, recordJson)
+	})
+}
+
+func (store *boltStore) delete(tableName string, id int) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return txStore.delete(tableName, id)
+	})
+}
+
+func (store *boltStore) truncate(tableName string) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return txStore.truncate(tableName)
+	})
+}
+
+func (store *boltStore) transaction(handleTransaction func(txStore store) error) error {
+	return store.writeTransaction(func(txStore *boltTxStore) error {
+		return handleTransaction(txStore)
+	})
+}
+
+func (store *boltStore) writeSnapshot(writer io.Writer) error {
+	return store.readTransaction(func(txStore *boltTxStore) error {
+		return txStore.writeSnapshot(writer)
+	})
+}
+
+func (store *boltStore) close() error {
+	// Writes aren't synced to disk as they happen, so make sure they all get there before closing.
+	if err := store.bolt.Sync(); err != nil {
+		return err
+	}
+	return store.bolt.Close()
+}
+
+// Runs the given function within a read-only Bolt transaction.
+func (store *boltStore) readTransaction(handleTransaction func(txStore *boltTxStore) error) error {
+	return store.bolt.View(func(tx *bbolt.Tx) error {
+		return handleTransaction(&boltTxStore{tx: tx})
+	})
+}
+
+// Runs the given function within a read-write Bolt transaction, which is rolled back if it returns an error.
+func (store *boltStore) writeTransaction(handleTransaction func(txStore *boltTxStore) error) error {
+	return store.bolt.Update(func(tx *bbolt.Tx) error {
+		return handleTransaction(&boltTxStore{tx: tx})
+	})
+}
+
+// Store implementation that carries out every operation within a single open Bolt transaction. Only one read-write
+// transaction can be open at a time, so any other writers wait until it is committed or rolled back.
+type boltTxStore struct {
+	tx *bbolt.Tx
+}
+
+func (store *boltTxStore) createTable(tableName string) error {
+	_, err := store.tx.CreateBucketIfNotExists([]byte(tableName))
+	return err
+}
+
+func (store *boltTxStore) tableNames() ([]string, error) {
+	var tableNames []string
+	err := store.tx.ForEach(func(name []byte, bucket *bbolt.Bucket) error {
+		tableNames = append(tableNames, string(name))
+		return nil
+	})
+	return tableNames, err
+}
+
+func (store *boltTxStore) get(tableName string, id int) ([]byte, error) {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The value is only valid until the transaction ends or the bucket is next written, so it needs to be copied.
+	var recordJson []byte
+	if value := bucket.Get(idToKey(id)); value != nil {
+		recordJson = append([]byte{}, value...)
+	}
+	return recordJson, nil
+}
+
+func (store *boltTxStore) forEach(tableName string, handleRecord func(id int, recordJson []byte) error) error {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return err
+	}
+
+	return bucket.ForEach(func(key, value []byte) error {
+		id, err := strconv.Atoi(string(key))
+		if err != nil {
+			return err
+		}
+		return handleRecord(id, value)
+	})
+}
+
+func (store *boltTxStore) nextId(tableName string) (int, error) {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return 0, err
+	}
+
+	newSequence, err := bucket.NextSequence()
+	return int(newSequence), err
+}
+
+func (store *boltTxStore) insert(tableName string, id int, recordJson []byte) error {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return err
+	}
+
+	key := idToKey(id)
+	if bucket.Get(key) != nil {
+		return errRecordExists
+	}
+	return bucket.Put(key, recordJson)
+}
+
+func (store *boltTxStore) update(tableName string, id int, recordJson []byte) error {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return err
+	}
+
+	key := idToKey(id)
+	if bucket.Get(key) == nil {
+		return errRecordNotFound
+	}
+	return bucket.Put(key, recordJson)
+}
+
+func (store *boltTxStore) delete(tableName string, id int) error {
+	bucket, err := getBucket(store.tx, tableName)
+	if err != nil {
+		return err
+	}
+
+	key := idToKey(id)
+	if bucket.Get(key) == nil {
+		return errRecordNotFound
+	}
+	return bucket.Delete(key)
+}
+
+func (store *boltTxStore) truncate(tableName string) error {
+	if _, err := getBucket(store.tx, tableName); err != nil {
+		return err
+	}
+
+	// Carry out the truncation by way of deleting the whole bucket and then recreate it.
+	if err := store.tx.DeleteBucket([]byte(tableName)); err != nil {
+		return err
+	}
+	_, err := store.tx.CreateBucket([]byte(tableName))
+	return err
+}
+
+func (store *boltTxStore) transaction(handleTransaction func(txStore store) error) error {
+	// Bolt doesn't support nested transactions, so the inner one simply becomes part of this one.
+	return handleTransaction(store)
+}
+
+func (store *boltTxStore) writeSnapshot(writer io.Writer) error {
+	_, err := store.tx.WriteTo(writer)
+	return err
+}
+
+func (store *boltTxStore) close() error {
+	return fmt.Errorf("can't close the database from within a transaction")
+}
+
+// Writes the contents of the given store to the given writer as a Bolt database file, by way of a temporary file.
+func writeBoltSnapshot(source store, writer io.Writer) error {
+	tempFile, err := os.CreateTemp("", "snapshot-db-")
+	if err != nil {
+		return err
+	}
+	tempFile.Close()
+	defer os.Remove(tempFile.Name())
+	snapshot, err := openBoltStore(tempFile.Name())
+	if err != nil {
+		return err
+	}
+	defer snapshot.bolt.Close()
+
+	tableNames, err := source.tableNames()
+	if err != nil {
+		return err
+	}
+	err = snapshot.bolt.Update(func(tx *bbolt.Tx) error {
+		for _, tableName := range tableNames {
+			bucket, err := tx.CreateBucket([]byte(tableName))
+			if err != nil {
+				return err
+			}
+			maxId := 0
+			err = source.forEach(tableName, func(id int, recordJson []byte) error {
+				maxId = max(maxId, id)
+				return bucket.Put(idToKey(id), recordJson)
+			})
+			if err != nil {
+				return err
+			}
+
+			// Continue the ID generation from where it left off.
+			if err = bucket.SetSequence(uint64(maxId)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return snapshot.writeSnapshot(writer)
+}
+
+// Obtains the Bolt bucket belonging to the given table.
+func getBucket(tx *bbolt.Tx, tableName string) (*bbolt.Bucket, error) {
+	bucket := tx.Bucket([]byte(tableName))
+	if bucket == nil {
+		return nil, fmt.Errorf("unknown table %s", tableName)
+	}
+	return bucket, nil
+}
+
+// Serializes the given integer ID to a byte array containing its Base-10 string representation.
+func idToKey(id int) []byte {
+	return []byte(strconv.Itoa(id))
+}
@@ -1,14 +1,13 @@
 // Copyright 2014 Team 254. All Rights Reserved.
 // Author: pat@patfairbank.com (Patrick Fairbank)
 //
-// Functions for manipulating the per-event Bolt datastore.
+// Functions for manipulating the per-event datastore, which is either an embedded Bolt database or a Postgres server.
 
 package model
 
 import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/game"
-	"go.etcd.io/bbolt"
 	"io"
 	"os"
 	"path/filepath"
@@ -21,74 +20,219 @@ const backupsDir = "db/backups"
 var BaseDir = "." // Mutable for testing
 
 type Database struct {
-	Path                string
-	bolt                *bbolt.DB
-	allianceTable       *table[Alliance]
-	awardTable          *table[Award]
-	eventSettingsTable  *table[EventSettings]
-	lowerThirdTable     *table[LowerThird]
-	matchTable          *table[Match]
-	matchResultTable    *table[MatchResult]
-	rankingTable        *table[game.Ranking]
-	scheduleBlockTable  *table[ScheduleBlock]
-	scheduledBreakTable *table[ScheduledBreak]
-	sponsorSlideTable   *table[SponsorSlide]
-	teamTable           *table[Team]
-	userSessionTable    *table[UserSession]
+	Path                     string
+	store                    store
+	agendaItemTable          *table[AgendaItem]
+	allianceTable            *table[Alliance]
+	apiTokenTable            *table[ApiToken]
+	arenaSnapshotTable       *table[ArenaSnapshot]
+	auditEntryTable          *table[AuditEntry]
+	awardTable               *table[Award]
+	eventSettingsTable       *table[EventSettings]
+	fillerTeamTable          *table[FillerTeam]
+	lowerThirdTable          *table[LowerThird]
+	lowerThirdPlaylistTable  *table[LowerThirdPlaylist]
+	matchTable               *table[Match]
+	matchResultTable         *table[MatchResult]
+	matchTimelineTable       *table[MatchTimeline]
+	panelDeviceTable         *table[PanelDevice]
+	pushSubscriptionTable    *table[PushSubscription]
+	rankingTable             *table[game.Ranking]
+	sandboxMatchResultTable  *table[SandboxMatchResult]
+	scheduleBlockTable       *table[ScheduleBlock]
+	scheduledBreakTable      *table[ScheduledBreak]
+	schemaMigrationTable     *table[SchemaMigration]
+	scoreEditTable           *table[ScoreEdit]
+	scoutingAppTable         *table[ScoutingApp]
+	scoutingObservationTable *table[ScoutingObservation]
+	showFlowStepTable        *table[ShowFlowStep]
+	soundPackTable           *table[SoundPack]
+	sponsorSlideTable        *table[SponsorSlide]
+	teamTable                *table[Team]
+	teamStatTable            *table[TeamStat]
+	teamStatusTable          *table[TeamStatus]
+	userAccountTable         *table[UserAccount]
+	userSessionTable         *table[UserSession]
+	videoMarkerTable         *table[VideoMarker]
+	videoStingerTable        *table[VideoStinger]
+	webhookTable             *table[Webhook]
 }
 
-// Opens the Bolt database at the given path, creating it if it doesn't exist.
+// Opens the database at the given path, creating it if it doesn't exist. The path is either that of an embedded Bolt
+// database file or a postgres:// URL.
 func OpenDatabase(filename string) (*Database, error) {
 	database := Database{Path: filename}
 	var err error
-	database.bolt, err = bbolt.Open(database.Path, 0644, &bbolt.Options{NoSync: true, Timeout: time.Second})
+	database.store, err = openStore(database.Path)
 	if err != nil {
 		return nil, err
 	}
-
-	// Register tables.
-	if database.allianceTable, err = newTable[Alliance](&database); err != nil {
+	isNewDatabase, err := database.isEmpty()
+	if err != nil {
+		database.store.close()
 		return nil, err
 	}
-	if database.awardTable, err = newTable[Award](&database); err != nil {
+
+	if err = database.registerTables(); err != nil {
+		database.store.close()
 		return nil, err
 	}
-	if database.eventSettingsTable, err = newTable[EventSettings](&database); err != nil {
+
+	if err = database.migrate(migrations, isNewDatabase); err != nil {
+		database.store.close()
 		return nil, err
 	}
-	if database.lowerThirdTable, err = newTable[LowerThird](&database); err != nil {
-		return nil, err
+
+	// Cache the tables that every display poll and report reads, to keep those reads off the store.
+	database.matchTable.enableCache()
+	database.rankingTable.enableCache()
+
+	return &database, nil
+}
+
+// Creates the wrappers through which the records of each type are accessed, along with their storage if it doesn't
+// already exist.
+func (database *Database) registerTables() error {
+	var err error
+	if database.agendaItemTable, err = newTable[AgendaItem](database); err != nil {
+		return err
 	}
-	if database.matchTable, err = newTable[Match](&database); err != nil {
-		return nil, err
+	if database.allianceTable, err = newTable[Alliance](database); err != nil {
+		return err
 	}
-	if database.matchResultTable, err = newTable[MatchResult](&database); err != nil {
-		return nil, err
+	if database.apiTokenTable, err = newTable[ApiToken](database); err != nil {
+		return err
 	}
-	if database.rankingTable, err = newTable[game.Ranking](&database); err != nil {
-		return nil, err
+	if database.arenaSnapshotTable, err = newTable[ArenaSnapshot](database); err != nil {
+		return err
 	}
-	if database.scheduleBlockTable, err = newTable[ScheduleBlock](&database); err != nil {
-		return nil, err
+	if database.auditEntryTable, err = newTable[AuditEntry](database); err != nil {
+		return err
 	}
-	if database.scheduledBreakTable, err = newTable[ScheduledBreak](&database); err != nil {
-		return nil, err
+	if database.awardTable, err = newTable[Award](database); err != nil {
+		return err
 	}
-	if database.sponsorSlideTable, err = newTable[SponsorSlide](&database); err != nil {
-		return nil, err
+	if database.eventSettingsTable, err = newTable[EventSettings](database); err != nil {
+		return err
 	}
-	if database.teamTable, err = newTable[Team](&database); err != nil {
-		return nil, err
+	if database.fillerTeamTable, err = newTable[FillerTeam](database); err != nil {
+		return err
 	}
-	if database.userSessionTable, err = newTable[UserSession](&database); err != nil {
-		return nil, err
+	if database.lowerThirdTable, err = newTable[LowerThird](database); err != nil {
+		return err
 	}
-
-	return &database, nil
+	if database.lowerThirdPlaylistTable, err = newTable[LowerThirdPlaylist](database); err != nil {
+		return err
+	}
+	if database.matchTable, err = newTable[Match](database); err != nil {
+		return err
+	}
+	if database.matchResultTable, err = newTable[MatchResult](database); err != nil {
+		return err
+	}
+	if database.matchTimelineTable, err = newTable[MatchTimeline](database); err != nil {
+		return err
+	}
+	if database.panelDeviceTable, err = newTable[PanelDevice](database); err != nil {
+		return err
+	}
+	if database.pushSubscriptionTable, err = newTable[PushSubscription](database); err != nil {
+		return err
+	}
+	if database.rankingTable, err = newTable[game.Ranking](database); err != nil {
+		return err
+	}
+	if database.sandboxMatchResultTable, err = newTable[SandboxMatchResult](database); err != nil {
+		return err
+	}
+	if database.scheduleBlockTable, err = newTable[ScheduleBlock](database); err != nil {
+		return err
+	}
+	if database.scheduledBreakTable, err = newTable[ScheduledBreak](database); err != nil {
+		return err
+	}
+	if database.schemaMigrationTable, err = newTable[SchemaMigration](database); err != nil {
+		return err
+	}
+	if database.scoreEditTable, err = newTable[ScoreEdit](database); err != nil {
+		return err
+	}
+	if database.scoutingAppTable, err = newTable[ScoutingApp](database); err != nil {
+		return err
+	}
+	if database.scoutingObservationTable, err = newTable[ScoutingObservation](database); err != nil {
+		return err
+	}
+	if database.showFlowStepTable, err = newTable[ShowFlowStep](database); err != nil {
+		return err
+	}
+	if database.soundPackTable, err = newTable[SoundPack](database); err != nil {
+		return err
+	}
+	if database.sponsorSlideTable, err = newTable[SponsorSlide](database); err != nil {
+		return err
+	}
+	if database.teamTable, err = newTable[Team](database); err != nil {
+		return err
+	}
+	if database.teamStatTable, err = newTable[TeamStat](database); err != nil {
+		return err
+	}
+	if database.teamStatusTable, err = newTable[TeamStatus](database); err != nil {
+		return err
+	}
+	if database.userAccountTable, err = newTable[UserAccount](database); err != nil {
+		return err
+	}
+	if database.userSessionTable, err = newTable[UserSession](database); err != nil {
+		return err
+	}
+	if database.videoMarkerTable, err = newTable[VideoMarker](database); err != nil {
+		return err
+	}
+	if database.videoStingerTable, err = newTable[VideoStinger](database); err != nil {
+		return err
+	}
+	if database.webhookTable, err = newTable[Webhook](database); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (database *Database) Close() error {
-	return database.bolt.Close()
+	return database.store.close()
+}
+
+// Calls the given function with a view of the database whose reads and writes all belong to a single transaction, which
+// is committed if the function returns nil and rolled back otherwise, so that a failure or crash partway through a
+// series of related writes can't leave only some of them saved. Other writers are held off until the function returns,
+// so it must only access the database through the view it is given.
+func (database *Database) RunInTransaction(handleTransaction func(txDatabase *Database) error) error {
+	err := database.store.transaction(func(txStore store) error {
+		txDatabase := Database{Path: database.Path, store: txStore}
+		if err := txDatabase.registerTables(); err != nil {
+			return err
+		}
+		return handleTransaction(&txDatabase)
+	})
+
+	// The transaction's writes bypass the cached tables, so make sure they get reloaded.
+	database.matchTable.invalidateCache()
+	database.rankingTable.invalidateCache()
+	return err
+}
+
+// Returns true if the database is an embedded file rather than a connection to a database server, meaning that it can
+// be replaced on disk.
+func (database *Database) IsEmbedded() bool {
+	_, ok := database.store.(*boltStore)
+	return ok
+}
+
+// Returns true if the database has no tables, meaning that it has just been created.
+func (database *Database) isEmpty() (bool, error) {
+	tableNames, err := database.store.tableNames()
+	return len(tableNames) == 0, err
 }
 
 // Creates a copy of the current database and saves it to the backups directory.
@@ -99,7 +243,7 @@ func (database *Database) Backup(eventName, reason string) error {
 		return err
 	}
 	filename := fmt.Sprintf("%s/%s_%s_%s.db", backupsPath, strings.Replace(eventName, " ", "_", -1),
-		time.Now().Format("20060102150405"), reason)
+		time.Now().Format(backupTimeFormat), reason)
 
 	dest, err := os.Create(filename)
 	if err != nil {
@@ -113,10 +257,8 @@ func (database *Database) Backup(eventName, reason string) error {
 	return nil
 }
 
-// Takes a snapshot of Bolt database and writes it to the given writer.
+// Takes a snapshot of the database and writes it to the given writer in the form of a Bolt database file, regardless of
+// which type of store is in use.
 func (database *Database) WriteBackup(writer io.Writer) error {
-	return database.bolt.View(func(tx *bbolt.Tx) error {
-		_, err := tx.WriteTo(writer)
-		return err
-	})
+	return database.store.writeSnapshot(writer)
 }
@@ -4,6 +4,8 @@
 package model
 
 import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -13,6 +15,52 @@ func TestOpenUnreachableDatabase(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRunInTransaction(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	// Prime the cache of the matches so that it can be checked that it picks up the transaction's writes.
+	matches, _ := db.GetMatchesByType(Qualification, false)
+	assert.Empty(t, matches)
+
+	err := db.RunInTransaction(func(txDatabase *Database) error {
+		if err := txDatabase.CreateMatch(&Match{Type: Qualification, ShortName: "Q1"}); err != nil {
+			return err
+		}
+		match, err := txDatabase.GetMatchById(1)
+		if assert.NotNil(t, match) {
+			assert.Equal(t, "Q1", match.ShortName)
+		}
+		return err
+	})
+	assert.Nil(t, err)
+	matches, _ = db.GetMatchesByType(Qualification, false)
+	assert.Equal(t, 1, len(matches))
+
+	// Check that none of the writes are kept if the transaction fails partway through.
+	err = db.RunInTransaction(func(txDatabase *Database) error {
+		assert.Nil(t, txDatabase.CreateMatchResult(&MatchResult{MatchId: 1, PlayNumber: 1}))
+		assert.Nil(
+			t, txDatabase.UpdateMatch(&Match{Id: 1, Type: Qualification, ShortName: "Q1", Status: game.RedWonMatch}),
+		)
+
+		// Nested transactions should join the outer one.
+		assert.Nil(t, txDatabase.RunInTransaction(func(nestedDatabase *Database) error {
+			return nestedDatabase.CreateAward(&Award{AwardName: "Winner"})
+		}))
+		return fmt.Errorf("something went wrong")
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "something went wrong", err.Error())
+	}
+	matchResult, _ := db.GetMatchResultForMatch(1)
+	assert.Nil(t, matchResult)
+	match, _ := db.GetMatchById(1)
+	assert.Equal(t, game.MatchScheduled, match.Status)
+	awards, _ := db.GetAllAwards()
+	assert.Empty(t, awards)
+}
+
 func setupTestDb(t *testing.T) *Database {
 	return SetupTestDb(t, "model")
 }
@@ -0,0 +1,251 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Management of the inactive event databases stored alongside the active one, so that a single installation can be
+// used for several events and switched between them.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	eventDatabasesDir         = "db/events"
+	archivedEventDatabasesDir = "db/events/archive"
+)
+
+var eventDatabaseNameRegex = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+var eventDatabaseNameReplaceRegex = regexp.MustCompile("[^A-Za-z0-9_-]+")
+
+// Represents an event database that is stored on disk but not currently in use.
+type EventDatabaseFile struct {
+	Name         string
+	Archived     bool
+	ModifiedTime time.Time
+}
+
+// Returns the name under which to store the database for the event with the given name.
+func EventDatabaseName(eventName string) string {
+	name := strings.Trim(eventDatabaseNameReplaceRegex.ReplaceAllString(eventName, "_"), "_")
+	if name == "" {
+		return "Untitled_Event"
+	}
+	return name
+}
+
+// Returns the stored event databases, either the ones in regular use or the archived ones, ordered by name.
+func ListEventDatabases(archived bool) ([]EventDatabaseFile, error) {
+	paths, err := filepath.Glob(filepath.Join(getEventDatabasesPath(archived), "*.db"))
+	if err != nil {
+		return nil, err
+	}
+	eventDatabases := make([]EventDatabaseFile, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		eventDatabases = append(
+			eventDatabases,
+			EventDatabaseFile{
+				Name:         strings.TrimSuffix(filepath.Base(path), ".db"),
+				Archived:     archived,
+				ModifiedTime: info.ModTime(),
+			},
+		)
+	}
+	sort.Slice(eventDatabases, func(i, j int) bool {
+		return eventDatabases[i].Name < eventDatabases[j].Name
+	})
+	return eventDatabases, nil
+}
+
+// Returns true if a stored event database with the given name exists, archived or not.
+func EventDatabaseExists(name string) bool {
+	return fileExists(getEventDatabasePath(name, false)) || fileExists(getEventDatabasePath(name, true))
+}
+
+// Returns the stored event database with the given name, archived or not, or nil if there is no such database.
+func FindEventDatabase(name string) (*EventDatabaseFile, error) {
+	if err := validateEventDatabaseName(name); err != nil {
+		return nil, err
+	}
+	for _, archived := range []bool{false, true} {
+		info, err := os.Stat(getEventDatabasePath(name, archived))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &EventDatabaseFile{Name: name, Archived: archived, ModifiedTime: info.ModTime()}, nil
+	}
+	return nil, nil
+}
+
+// Moves the closed database at the given path into storage under the given name.
+func StoreEventDatabase(dbPath, name string) error {
+	if err := validateEventDatabaseName(name); err != nil {
+		return err
+	}
+	if EventDatabaseExists(name) {
+		return fmt.Errorf("an event database named '%s' already exists", name)
+	}
+	if err := os.MkdirAll(getEventDatabasesPath(false), 0755); err != nil {
+		return err
+	}
+	return os.Rename(dbPath, getEventDatabasePath(name, false))
+}
+
+// Moves the stored event database with the given name out of storage to the given path, which must not be open.
+func ActivateEventDatabase(dbPath, name string) error {
+	if err := validateEventDatabaseName(name); err != nil {
+		return err
+	}
+	storedPath := getEventDatabasePath(name, false)
+	if !fileExists(storedPath) {
+		return fmt.Errorf("event database '%s' does not exist", name)
+	}
+	return os.Rename(storedPath, dbPath)
+}
+
+// Moves the stored event database with the given name into or out of the archive.
+func SetEventDatabaseArchived(name string, archived bool) error {
+	if err := validateEventDatabaseName(name); err != nil {
+		return err
+	}
+	sourcePath := getEventDatabasePath(name, !archived)
+	if !fileExists(sourcePath) {
+		return fmt.Errorf("event database '%s' does not exist", name)
+	}
+	if err := os.MkdirAll(getEventDatabasesPath(archived), 0755); err != nil {
+		return err
+	}
+	return os.Rename(sourcePath, getEventDatabasePath(name, archived))
+}
+
+// Copies the team list from the stored event database with the given name into this one, replacing any teams that
+// already exist. The fields that only pertain to one event are cleared. Returns the number of teams copied.
+func (database *Database) CopyTeamsFromEventDatabase(name string) (int, error) {
+	if err := validateEventDatabaseName(name); err != nil {
+		return 0, err
+	}
+	sourcePath := getEventDatabasePath(name, false)
+	if !fileExists(sourcePath) {
+		sourcePath = getEventDatabasePath(name, true)
+		if !fileExists(sourcePath) {
+			return 0, fmt.Errorf("event database '%s' does not exist", name)
+		}
+	}
+	sourceDatabase, err := OpenDatabase(sourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer sourceDatabase.Close()
+	teams, err := sourceDatabase.GetAllTeams()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, team := range teams {
+		team.FtaNotes = ""
+		existingTeam, err := database.GetTeamById(team.Id)
+		if err != nil {
+			return 0, err
+		}
+		if existingTeam == nil {
+			err = database.CreateTeam(&team)
+		} else {
+			err = database.UpdateTeam(&team)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(teams), nil
+}
+
+// Represents a stored event database that has been opened for reading alongside the active one.
+type EventArchive struct {
+	EventDatabaseFile
+	Database     *Database
+	snapshotPath string
+}
+
+// Opens the stored event database with the given name, archived or not, for reading. A scratch copy of it is opened
+// rather than the stored file itself, so that the stored file is never modified (including by the migrations needed to
+// read an event saved by an older version) and can still be switched to or archived while it is open.
+func OpenEventArchive(name string) (*EventArchive, error) {
+	eventDatabase, err := FindEventDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+	if eventDatabase == nil {
+		return nil, fmt.Errorf("event database '%s' does not exist", name)
+	}
+
+	source, err := os.Open(getEventDatabasePath(name, eventDatabase.Archived))
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+	snapshot, err := os.CreateTemp("", "event-archive-*.db")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(snapshot, source)
+	if closeErr := snapshot.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(snapshot.Name())
+		return nil, err
+	}
+
+	database, err := OpenDatabase(snapshot.Name())
+	if err != nil {
+		os.Remove(snapshot.Name())
+		return nil, err
+	}
+	return &EventArchive{EventDatabaseFile: *eventDatabase, Database: database, snapshotPath: snapshot.Name()}, nil
+}
+
+// Closes the archived event's database and discards its scratch copy.
+func (archive *EventArchive) Close() error {
+	err := archive.Database.Close()
+	if removeErr := os.Remove(archive.snapshotPath); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+func validateEventDatabaseName(name string) error {
+	if !eventDatabaseNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid event database name '%s'", name)
+	}
+	return nil
+}
+
+func getEventDatabasesPath(archived bool) string {
+	if archived {
+		return filepath.Join(BaseDir, archivedEventDatabasesDir)
+	}
+	return filepath.Join(BaseDir, eventDatabasesDir)
+}
+
+func getEventDatabasePath(name string, archived bool) string {
+	return filepath.Join(getEventDatabasesPath(archived), name+".db")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
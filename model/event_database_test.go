@@ -0,0 +1,154 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEventDatabaseName(t *testing.T) {
+	assert.Equal(t, "Chezy_Champs_2024", EventDatabaseName("Chezy Champs 2024"))
+	assert.Equal(t, "Silicon_Valley_Regional", EventDatabaseName("  Silicon Valley Regional!"))
+	assert.Equal(t, "Untitled_Event", EventDatabaseName("???"))
+}
+
+func TestStoreAndActivateEventDatabase(t *testing.T) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	dbPath := filepath.Join(BaseDir, "event.db")
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254}))
+	assert.Nil(t, database.Close())
+
+	eventDatabases, err := ListEventDatabases(false)
+	assert.Nil(t, err)
+	assert.Empty(t, eventDatabases)
+	assert.Nil(t, StoreEventDatabase(dbPath, "Chezy_Champs"))
+	assert.False(t, fileExists(dbPath))
+	assert.True(t, EventDatabaseExists("Chezy_Champs"))
+	eventDatabases, _ = ListEventDatabases(false)
+	if assert.Equal(t, 1, len(eventDatabases)) {
+		assert.Equal(t, "Chezy_Champs", eventDatabases[0].Name)
+		assert.False(t, eventDatabases[0].Archived)
+	}
+
+	// A name can't be reused or escape the events directory.
+	assert.Nil(t, os.WriteFile(dbPath, []byte{}, 0644))
+	err = StoreEventDatabase(dbPath, "Chezy_Champs")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "already exists")
+	}
+	err = StoreEventDatabase(dbPath, "../Chezy_Champs")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid event database name")
+	}
+	assert.Nil(t, os.Remove(dbPath))
+
+	assert.Nil(t, SetEventDatabaseArchived("Chezy_Champs", true))
+	eventDatabases, _ = ListEventDatabases(false)
+	assert.Empty(t, eventDatabases)
+	eventDatabases, _ = ListEventDatabases(true)
+	if assert.Equal(t, 1, len(eventDatabases)) {
+		assert.True(t, eventDatabases[0].Archived)
+	}
+	assert.NotNil(t, ActivateEventDatabase(dbPath, "Chezy_Champs"))
+	assert.NotNil(t, SetEventDatabaseArchived("Blorpy", false))
+	assert.Nil(t, SetEventDatabaseArchived("Chezy_Champs", false))
+
+	assert.Nil(t, ActivateEventDatabase(dbPath, "Chezy_Champs"))
+	assert.False(t, EventDatabaseExists("Chezy_Champs"))
+	database, err = OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	team, _ := database.GetTeamById(254)
+	assert.NotNil(t, team)
+	database.Close()
+}
+
+func TestCopyTeamsFromEventDatabase(t *testing.T) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	dbPath := filepath.Join(BaseDir, "event.db")
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	team := Team{Id: 254, Nickname: "The Cheesy Poofs", FtaNotes: "Radio"}
+	assert.Nil(t, database.CreateTeam(&team))
+	assert.Nil(t, database.SaveTeamStatus(&TeamStatus{TeamId: 254, InspectionPassed: true, YellowCard: true}))
+	assert.Nil(t, database.CreateTeam(&Team{Id: 1114, Nickname: "Simbotics"}))
+	assert.Nil(t, database.Close())
+	assert.Nil(t, StoreEventDatabase(dbPath, "Chezy_Champs"))
+
+	database, err = OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	defer database.Close()
+	assert.Nil(t, database.CreateTeam(&Team{Id: 1114, Nickname: "Old Name"}))
+	count, err := database.CopyTeamsFromEventDatabase("Chezy_Champs")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	teams, _ := database.GetAllTeams()
+	if assert.Equal(t, 2, len(teams)) {
+		assert.Equal(t, Team{Id: 254, Nickname: "The Cheesy Poofs"}, teams[0])
+		assert.Equal(t, "Simbotics", teams[1].Nickname)
+	}
+	teamStatus, _ := database.GetTeamStatus(254)
+	assert.False(t, teamStatus.InspectionPassed)
+	assert.False(t, teamStatus.YellowCard)
+
+	_, err = database.CopyTeamsFromEventDatabase("Blorpy")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+}
+
+func TestOpenEventArchive(t *testing.T) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	dbPath := filepath.Join(BaseDir, "event.db")
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254}))
+	assert.Nil(t, database.Close())
+	assert.Nil(t, StoreEventDatabase(dbPath, "Chezy_Champs"))
+	assert.Nil(t, SetEventDatabaseArchived("Chezy_Champs", true))
+
+	eventDatabase, err := FindEventDatabase("Chezy_Champs")
+	assert.Nil(t, err)
+	if assert.NotNil(t, eventDatabase) {
+		assert.True(t, eventDatabase.Archived)
+	}
+	storedModifiedTime := eventDatabase.ModifiedTime
+
+	archive, err := OpenEventArchive("Chezy_Champs")
+	assert.Nil(t, err)
+	assert.Equal(t, "Chezy_Champs", archive.Name)
+	team, _ := archive.Database.GetTeamById(254)
+	assert.NotNil(t, team)
+
+	// Writes go to the scratch copy rather than to the stored file.
+	assert.Nil(t, archive.Database.CreateTeam(&Team{Id: 1114}))
+	assert.Nil(t, archive.Close())
+	assert.False(t, fileExists(archive.snapshotPath))
+	eventDatabase, _ = FindEventDatabase("Chezy_Champs")
+	assert.Equal(t, storedModifiedTime, eventDatabase.ModifiedTime)
+	archive, err = OpenEventArchive("Chezy_Champs")
+	assert.Nil(t, err)
+	team, _ = archive.Database.GetTeamById(1114)
+	assert.Nil(t, team)
+	assert.Nil(t, archive.Close())
+
+	eventDatabase, err = FindEventDatabase("Blorpy")
+	assert.Nil(t, err)
+	assert.Nil(t, eventDatabase)
+	_, err = OpenEventArchive("Blorpy")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+	_, err = OpenEventArchive("../Chezy_Champs")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid event database name")
+	}
+}
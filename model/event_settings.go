@@ -5,7 +5,15 @@
 
 package model
 
-import "github.com/Team254/cheesy-arena/game"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
+	"sync"
+	"time"
+)
 
 type PlayoffType int
 
@@ -14,20 +22,61 @@ const (
 	SingleEliminationPlayoff
 )
 
+const (
+	defaultPlayoffTimeoutsPerAlliance = 1
+	defaultPlayoffTimeoutDurationSec  = 360
+	defaultPitDisplayPageDurationSec  = 15
+	defaultQueueLeadTimeMin           = 10
+	defaultThemeAccentColor           = "#ffcc00"
+	defaultReplayClipDurationSec      = 10
+	defaultDisplayAlertThresholdSec   = 10
+	defaultScoreboardFormat           = "daktronics"
+	defaultAutoBackupIntervalMin      = 10
+	defaultAutoBackupRetentionCount   = 100
+)
+
+// Pages that the pit display can rotate among, in the order in which they are shown.
+var PitDisplayPages = []string{"rankings", "schedule", "results", "season", "announcement"}
+
+// Human-readable names of the pit display pages.
+var PitDisplayPageNames = map[string]string{
+	"rankings":     "Rankings",
+	"schedule":     "Upcoming Matches",
+	"results":      "Recent Results",
+	"season":       "Season Standings",
+	"announcement": "Announcement",
+}
+
+var defaultPitDisplayPages = []string{"rankings", "schedule", "results"}
+
+// Time zones that have already been loaded, keyed by name, since loading one reads the time zone database.
+var (
+	timeZones      = make(map[string]*time.Location)
+	timeZonesMutex sync.Mutex
+)
+
 type EventSettings struct {
 	Id                              int `db:"id"`
 	Name                            string
+	TimeZone                        string // IANA name of the event's time zone, or blank to use the server's.
+	GameKey                         string
+	TeamsPerAlliance                int
 	PlayoffType                     PlayoffType
 	NumPlayoffAlliances             int
 	SelectionRound2Order            string
 	SelectionRound3Order            string
 	SelectionShowUnpickedTeams      bool
+	PlayoffTimeoutsPerAlliance      int
+	PlayoffTimeoutDurationSec       int
 	TbaDownloadEnabled              bool
 	TbaPublishingEnabled            bool
 	TbaEventCode                    string
 	TbaSecretId                     string
 	TbaSecret                       string
 	NexusEnabled                    bool
+	FirstApiUsername                string
+	FirstApiKey                     string
+	FirstEventCode                  string
 	NetworkSecurityEnabled          bool
 	ApAddress                       string
 	ApPassword                      string
@@ -35,6 +84,10 @@ type EventSettings struct {
 	SwitchAddress                   string
 	SwitchPassword                  string
 	PlcAddress                      string
+	StaffReadyCheckEnabled          bool
+	ScoreReviewEnabled              bool
+	PracticeSandboxEnabled          bool
+	SoundPackId                     int
 	AdminPassword                   string
 	TeamSignRed1Address             string
 	TeamSignRed2Address             string
@@ -44,15 +97,44 @@ type EventSettings struct {
 	TeamSignBlue2Address            string
 	TeamSignBlue3Address            string
 	TeamSignBlueTimerAddress        string
+	LightingAddress                 string
+	LightingUniverse                int
+	LightingScenes                  []lighting.Scene
 	WarmupDurationSec               int
 	AutoDurationSec                 int
 	PauseDurationSec                int
 	TeleopDurationSec               int
 	WarningRemainingDurationSec     int
+	DemoMatchTimingEnabled          bool
+	DemoAutoDurationSec             int
+	DemoPauseDurationSec            int
+	DemoTeleopDurationSec           int
+	DemoWarningRemainingDurationSec int
+	MatchSoundCues                  []game.MatchSoundCue
 	MelodyBonusThresholdWithoutCoop int
 	MelodyBonusThresholdWithCoop    int
 	AmplificationNoteLimit          int
 	AmplificationDurationSec        int
+	PitDisplayPages                 []string
+	PitDisplayPageDurationSec       int
+	PitDisplayAnnouncement          string
+	QueueLeadTimeMin                int
+	ThemeAccentColor                string
+	ThemeFontFile                   string
+	ThemeLogoFile                   string
+	ThemeBackgroundFile             string
+	WebPushContactEmail             string
+	WebPushVapidPrivateKey          string
+	ReplayEnabled                   bool
+	ReplayAddress                   string
+	ReplayClipDurationSec           int
+	ReplayBannerEnabled             bool
+	DisplayAlertThresholdSec        int
+	ScoreboardAddress               string
+	ScoreboardFormat                string
+	ScoreboardTemplate              string
+	AutoBackupIntervalMin           int
+	AutoBackupRetentionCount        int
 }
 
 func (database *Database) GetEventSettings() (*EventSettings, error) {
@@ -61,17 +143,73 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 		return nil, err
 	}
 	if len(allEventSettings) == 1 {
-		return &allEventSettings[0], nil
+		eventSettings := &allEventSettings[0]
+		if eventSettings.TeamsPerAlliance == 0 {
+			// Records saved before the alliance size was configurable use the standard three-team format.
+			eventSettings.TeamsPerAlliance = 3
+		}
+		if eventSettings.GameKey == "" {
+			eventSettings.GameKey = game.DefaultGameKey
+		}
+		if eventSettings.PlayoffTimeoutDurationSec == 0 {
+			// Records saved before alliance timeouts were configurable use the standard allotment.
+			eventSettings.PlayoffTimeoutsPerAlliance = defaultPlayoffTimeoutsPerAlliance
+			eventSettings.PlayoffTimeoutDurationSec = defaultPlayoffTimeoutDurationSec
+		}
+		if eventSettings.MatchSoundCues == nil {
+			// Records saved before the sound cue schedule was configurable use the standard schedule.
+			eventSettings.MatchSoundCues = game.DefaultMatchSoundCues
+		}
+		if eventSettings.LightingScenes == nil {
+			// Records saved before DMX lighting was supported use the standard scenes.
+			eventSettings.LightingUniverse = 1
+			eventSettings.LightingScenes = lighting.DefaultScenes
+		}
+		if eventSettings.PitDisplayPageDurationSec == 0 {
+			// Records saved before the pit display rotation was configurable use the standard rotation.
+			eventSettings.PitDisplayPages = defaultPitDisplayPages
+			eventSettings.PitDisplayPageDurationSec = defaultPitDisplayPageDurationSec
+		}
+		if eventSettings.QueueLeadTimeMin == 0 {
+			// Records saved before the queueing lead time was configurable use the standard lead time.
+			eventSettings.QueueLeadTimeMin = defaultQueueLeadTimeMin
+		}
+		if eventSettings.ThemeAccentColor == "" {
+			// Records saved before the displays could be themed use the standard accent color.
+			eventSettings.ThemeAccentColor = defaultThemeAccentColor
+		}
+		if eventSettings.ReplayClipDurationSec == 0 {
+			// Records saved before the replay system integration existed use the standard clip length.
+			eventSettings.ReplayClipDurationSec = defaultReplayClipDurationSec
+		}
+		if eventSettings.ScoreboardFormat == "" {
+			// Records saved before the venue scoreboard output existed use the standard format.
+			eventSettings.ScoreboardFormat = defaultScoreboardFormat
+		}
+		if eventSettings.DisplayAlertThresholdSec == 0 {
+			// Records saved before display monitoring existed use the standard threshold.
+			eventSettings.DisplayAlertThresholdSec = defaultDisplayAlertThresholdSec
+		}
+		if eventSettings.AutoBackupRetentionCount == 0 {
+			// Records saved before automatic backups existed use the standard schedule and retention.
+			eventSettings.AutoBackupIntervalMin = defaultAutoBackupIntervalMin
+			eventSettings.AutoBackupRetentionCount = defaultAutoBackupRetentionCount
+		}
+		return eventSettings, nil
 	}
 
 	// Database record doesn't exist yet; create it now.
 	eventSettings := EventSettings{
 		Name:                            "Untitled Event",
+		GameKey:                         game.DefaultGameKey,
+		TeamsPerAlliance:                3,
 		PlayoffType:                     DoubleEliminationPlayoff,
 		NumPlayoffAlliances:             8,
 		SelectionRound2Order:            "L",
 		SelectionRound3Order:            "",
 		SelectionShowUnpickedTeams:      false,
+		PlayoffTimeoutsPerAlliance:      defaultPlayoffTimeoutsPerAlliance,
+		PlayoffTimeoutDurationSec:       defaultPlayoffTimeoutDurationSec,
 		TbaDownloadEnabled:              true,
 		ApChannel:                       36,
 		WarmupDurationSec:               game.MatchTiming.WarmupDurationSec,
@@ -79,10 +217,26 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 		PauseDurationSec:                game.MatchTiming.PauseDurationSec,
 		TeleopDurationSec:               game.MatchTiming.TeleopDurationSec,
 		WarningRemainingDurationSec:     game.MatchTiming.WarningRemainingDurationSec,
+		DemoAutoDurationSec:             game.MatchTiming.AutoDurationSec,
+		DemoPauseDurationSec:            game.MatchTiming.PauseDurationSec,
+		DemoTeleopDurationSec:           game.MatchTiming.TeleopDurationSec,
+		DemoWarningRemainingDurationSec: game.MatchTiming.WarningRemainingDurationSec,
+		LightingUniverse:                1,
+		LightingScenes:                  lighting.DefaultScenes,
+		MatchSoundCues:                  game.DefaultMatchSoundCues,
 		MelodyBonusThresholdWithoutCoop: game.MelodyBonusThresholdWithoutCoop,
 		MelodyBonusThresholdWithCoop:    game.MelodyBonusThresholdWithCoop,
 		AmplificationNoteLimit:          game.AmplificationNoteLimit,
 		AmplificationDurationSec:        game.AmplificationDurationSec,
+		PitDisplayPages:                 defaultPitDisplayPages,
+		PitDisplayPageDurationSec:       defaultPitDisplayPageDurationSec,
+		QueueLeadTimeMin:                defaultQueueLeadTimeMin,
+		ThemeAccentColor:                defaultThemeAccentColor,
+		ReplayClipDurationSec:           defaultReplayClipDurationSec,
+		DisplayAlertThresholdSec:        defaultDisplayAlertThresholdSec,
+		ScoreboardFormat:                defaultScoreboardFormat,
+		AutoBackupIntervalMin:           defaultAutoBackupIntervalMin,
+		AutoBackupRetentionCount:        defaultAutoBackupRetentionCount,
 	}
 
 	if err := database.eventSettingsTable.create(&eventSettings); err != nil {
@@ -94,3 +248,63 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 func (database *Database) UpdateEventSettings(eventSettings *EventSettings) error {
 	return database.eventSettingsTable.update(eventSettings)
 }
+
+// Serializes the event settings to a JSON document that can be loaded into another event's database using
+// ImportEventSettingsJson().
+func (eventSettings *EventSettings) ExportJson() ([]byte, error) {
+	settings := *eventSettings
+	settings.Id = 0
+	return json.MarshalIndent(settings, "", "  ")
+}
+
+// Returns a copy of the given event settings overwritten with the fields present in the given JSON document. The record
+// ID and the event name are kept as they are, since the document is expected to come from a different event.
+func ImportEventSettingsJson(eventSettings *EventSettings, settingsJson []byte) (*EventSettings, error) {
+	// Round-trip the existing settings through JSON to avoid decoding into slices shared with the original.
+	existingJson, err := json.Marshal(eventSettings)
+	if err != nil {
+		return nil, err
+	}
+	var settings EventSettings
+	if err = json.Unmarshal(existingJson, &settings); err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(settingsJson))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("Invalid event settings file: %v", err)
+	}
+	settings.Id = eventSettings.Id
+	settings.Name = eventSettings.Name
+	return &settings, nil
+}
+
+// Returns the time zone in which the event is taking place, in which the schedule and other times of day are shown
+// regardless of the time zone that the server is set to. Falls back to the server's time zone if none is set.
+func (eventSettings *EventSettings) Location() *time.Location {
+	location, err := LoadTimeZone(eventSettings.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// Returns the time zone having the given IANA name, such as "America/Los_Angeles", or the server's time zone if the
+// name is blank.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	timeZonesMutex.Lock()
+	defer timeZonesMutex.Unlock()
+	if location, ok := timeZones[name]; ok {
+		return location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("Unknown time zone '%s'.", name)
+	}
+	timeZones[name] = location
+	return location, nil
+}
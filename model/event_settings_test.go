@@ -4,8 +4,11 @@
 package model
 
 import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestEventSettingsReadWrite(t *testing.T) {
@@ -19,21 +22,41 @@ func TestEventSettingsReadWrite(t *testing.T) {
 		EventSettings{
 			Id:                              1,
 			Name:                            "Untitled Event",
+			GameKey:                         "crescendo2024",
+			TeamsPerAlliance:                3,
 			PlayoffType:                     DoubleEliminationPlayoff,
 			NumPlayoffAlliances:             8,
 			SelectionRound2Order:            "L",
 			SelectionRound3Order:            "",
+			PlayoffTimeoutsPerAlliance:      1,
+			PlayoffTimeoutDurationSec:       360,
 			TbaDownloadEnabled:              true,
 			ApChannel:                       36,
+			LightingUniverse:                1,
+			LightingScenes:                  lighting.DefaultScenes,
 			WarmupDurationSec:               0,
 			AutoDurationSec:                 15,
 			PauseDurationSec:                3,
 			TeleopDurationSec:               135,
 			WarningRemainingDurationSec:     20,
+			DemoAutoDurationSec:             15,
+			DemoPauseDurationSec:            3,
+			DemoTeleopDurationSec:           135,
+			DemoWarningRemainingDurationSec: 20,
+			MatchSoundCues:                  game.DefaultMatchSoundCues,
 			MelodyBonusThresholdWithoutCoop: 18,
 			MelodyBonusThresholdWithCoop:    15,
 			AmplificationNoteLimit:          4,
 			AmplificationDurationSec:        10,
+			PitDisplayPages:                 []string{"rankings", "schedule", "results"},
+			PitDisplayPageDurationSec:       15,
+			QueueLeadTimeMin:                10,
+			ThemeAccentColor:                "#ffcc00",
+			ReplayClipDurationSec:           10,
+			DisplayAlertThresholdSec:        10,
+			ScoreboardFormat:                "daktronics",
+			AutoBackupIntervalMin:           10,
+			AutoBackupRetentionCount:        100,
 		},
 		*eventSettings,
 	)
@@ -48,3 +71,60 @@ func TestEventSettingsReadWrite(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, eventSettings, eventSettings2)
 }
+
+func TestEventSettingsExportImportJson(t *testing.T) {
+	db := setupTestDb(t)
+	eventSettings, _ := db.GetEventSettings()
+	eventSettings.Name = "Chezy Champs"
+	eventSettings.ApChannel = 36
+	eventSettings.PitDisplayPages = []string{"rankings"}
+	eventSettings.LightingScenes = []lighting.Scene{{Name: lighting.ScenePreMatch, Channels: []byte{1, 2, 3}}}
+	settingsJson, err := eventSettings.ExportJson()
+	assert.Nil(t, err)
+	assert.Contains(t, string(settingsJson), "\"ApChannel\": 36")
+
+	otherSettings, _ := setupTestDb(t).GetEventSettings()
+	otherSettings.Name = "Madtown Throwdown"
+	otherPitDisplayPages := otherSettings.PitDisplayPages
+	importedSettings, err := ImportEventSettingsJson(otherSettings, settingsJson)
+	assert.Nil(t, err)
+	expectedSettings := *eventSettings
+	expectedSettings.Id = otherSettings.Id
+	expectedSettings.Name = "Madtown Throwdown"
+	assert.Equal(t, expectedSettings, *importedSettings)
+	assert.Equal(t, otherPitDisplayPages, otherSettings.PitDisplayPages)
+
+	// Check that fields missing from the document keep their existing values.
+	importedSettings, err = ImportEventSettingsJson(otherSettings, []byte("{\"QueueLeadTimeMin\": 15}"))
+	assert.Nil(t, err)
+	assert.Equal(t, 15, importedSettings.QueueLeadTimeMin)
+	assert.Equal(t, otherSettings.ApChannel, importedSettings.ApChannel)
+	assert.Equal(t, otherSettings.PitDisplayPages, importedSettings.PitDisplayPages)
+
+	_, err = ImportEventSettingsJson(otherSettings, []byte("{\"QueueLeadTime\": 15}"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown field \"QueueLeadTime\"")
+	}
+	_, err = ImportEventSettingsJson(otherSettings, []byte("blorpy"))
+	assert.NotNil(t, err)
+}
+
+func TestEventSettingsLocation(t *testing.T) {
+	eventSettings := EventSettings{}
+	assert.Equal(t, time.Local, eventSettings.Location())
+
+	eventSettings.TimeZone = "America/Chicago"
+	assert.Equal(t, "America/Chicago", eventSettings.Location().String())
+	matchTime := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	assert.Equal(t, "9:30 AM", matchTime.In(eventSettings.Location()).Format("3:04 PM"))
+
+	// An invalid time zone should fall back to that of the server.
+	eventSettings.TimeZone = "Mars/Olympus_Mons"
+	assert.Equal(t, time.Local, eventSettings.Location())
+	_, err := LoadTimeZone("Mars/Olympus_Mons")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Unknown time zone 'Mars/Olympus_Mons'.", err.Error())
+	}
+	_, err = LoadTimeZone("Local")
+	assert.NotNil(t, err)
+}
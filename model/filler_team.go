@@ -0,0 +1,45 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a team waiting in the practice match filler line.
+
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type FillerTeam struct {
+	Id         int `db:"id"`
+	TeamId     int
+	SignedUpAt time.Time
+}
+
+func (database *Database) CreateFillerTeam(fillerTeam *FillerTeam) error {
+	return database.fillerTeamTable.create(fillerTeam)
+}
+
+func (database *Database) GetFillerTeamById(id int) (*FillerTeam, error) {
+	return database.fillerTeamTable.getById(id)
+}
+
+func (database *Database) DeleteFillerTeam(id int) error {
+	return database.fillerTeamTable.delete(id)
+}
+
+func (database *Database) TruncateFillerTeams() error {
+	return database.fillerTeamTable.truncate()
+}
+
+// Returns all teams in the filler line, in the order in which they signed up.
+func (database *Database) GetAllFillerTeams() ([]FillerTeam, error) {
+	fillerTeams, err := database.fillerTeamTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(fillerTeams, func(i, j int) bool {
+		return fillerTeams[i].Id < fillerTeams[j].Id
+	})
+	return fillerTeams, nil
+}
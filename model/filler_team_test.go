@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetNonexistentFillerTeam(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	fillerTeam, err := db.GetFillerTeamById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, fillerTeam)
+}
+
+func TestFillerTeamCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	fillerTeam1 := FillerTeam{TeamId: 254, SignedUpAt: time.Unix(1000, 0).UTC()}
+	assert.Nil(t, db.CreateFillerTeam(&fillerTeam1))
+	fillerTeam2 := FillerTeam{TeamId: 1114, SignedUpAt: time.Unix(900, 0).UTC()}
+	assert.Nil(t, db.CreateFillerTeam(&fillerTeam2))
+
+	fillerTeam, err := db.GetFillerTeamById(1)
+	assert.Nil(t, err)
+	assert.Equal(t, fillerTeam1, *fillerTeam)
+
+	fillerTeams, err := db.GetAllFillerTeams()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(fillerTeams)) {
+		assert.Equal(t, fillerTeam1, fillerTeams[0])
+		assert.Equal(t, fillerTeam2, fillerTeams[1])
+	}
+
+	assert.Nil(t, db.DeleteFillerTeam(fillerTeam1.Id))
+	fillerTeams, err = db.GetAllFillerTeams()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(fillerTeams)) {
+		assert.Equal(t, fillerTeam2, fillerTeams[0])
+	}
+
+	assert.Nil(t, db.TruncateFillerTeams())
+	fillerTeams, err = db.GetAllFillerTeams()
+	assert.Nil(t, err)
+	assert.Empty(t, fillerTeams)
+}
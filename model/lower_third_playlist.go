@@ -0,0 +1,77 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a named sequence of saved lower thirds, such as the running order of an awards
+// ceremony.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+type LowerThirdPlaylist struct {
+	Id            int `db:"id"`
+	Name          string
+	LowerThirdIds []int
+}
+
+func (database *Database) CreateLowerThirdPlaylist(playlist *LowerThirdPlaylist) error {
+	if err := playlist.validate(); err != nil {
+		return err
+	}
+	return database.lowerThirdPlaylistTable.create(playlist)
+}
+
+func (database *Database) GetLowerThirdPlaylistById(id int) (*LowerThirdPlaylist, error) {
+	return database.lowerThirdPlaylistTable.getById(id)
+}
+
+func (database *Database) UpdateLowerThirdPlaylist(playlist *LowerThirdPlaylist) error {
+	if err := playlist.validate(); err != nil {
+		return err
+	}
+	return database.lowerThirdPlaylistTable.update(playlist)
+}
+
+func (database *Database) DeleteLowerThirdPlaylist(id int) error {
+	return database.lowerThirdPlaylistTable.delete(id)
+}
+
+func (database *Database) TruncateLowerThirdPlaylists() error {
+	return database.lowerThirdPlaylistTable.truncate()
+}
+
+func (database *Database) GetAllLowerThirdPlaylists() ([]LowerThirdPlaylist, error) {
+	playlists, err := database.lowerThirdPlaylistTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(playlists, func(i, j int) bool {
+		return playlists[i].Name < playlists[j].Name
+	})
+	return playlists, nil
+}
+
+// Returns the lower thirds in the given playlist in order, skipping any that have since been deleted.
+func (database *Database) GetLowerThirdPlaylistEntries(playlist *LowerThirdPlaylist) ([]LowerThird, error) {
+	lowerThirds := []LowerThird{}
+	for _, lowerThirdId := range playlist.LowerThirdIds {
+		lowerThird, err := database.GetLowerThirdById(lowerThirdId)
+		if err != nil {
+			return nil, err
+		}
+		if lowerThird != nil {
+			lowerThirds = append(lowerThirds, *lowerThird)
+		}
+	}
+	return lowerThirds, nil
+}
+
+func (playlist *LowerThirdPlaylist) validate() error {
+	if playlist.Name == "" {
+		return fmt.Errorf("lower third playlist name must not be blank")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLowerThirdPlaylistCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	playlist, err := db.GetLowerThirdPlaylistById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, playlist)
+
+	playlist1 := LowerThirdPlaylist{Name: "Opening Ceremony", LowerThirdIds: []int{2, 1}}
+	assert.Nil(t, db.CreateLowerThirdPlaylist(&playlist1))
+	playlist2 := LowerThirdPlaylist{Name: "Awards", LowerThirdIds: []int{}}
+	assert.Nil(t, db.CreateLowerThirdPlaylist(&playlist2))
+	assert.NotNil(t, db.CreateLowerThirdPlaylist(&LowerThirdPlaylist{}))
+
+	playlists, err := db.GetAllLowerThirdPlaylists()
+	assert.Nil(t, err)
+	assert.Equal(t, []LowerThirdPlaylist{playlist2, playlist1}, playlists)
+
+	playlist1.LowerThirdIds = []int{1, 2, 3}
+	assert.Nil(t, db.UpdateLowerThirdPlaylist(&playlist1))
+	playlist, err = db.GetLowerThirdPlaylistById(playlist1.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, playlist1, *playlist)
+
+	assert.Nil(t, db.DeleteLowerThirdPlaylist(playlist1.Id))
+	playlist, err = db.GetLowerThirdPlaylistById(playlist1.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, playlist)
+
+	assert.Nil(t, db.TruncateLowerThirdPlaylists())
+	playlists, err = db.GetAllLowerThirdPlaylists()
+	assert.Nil(t, err)
+	assert.Empty(t, playlists)
+}
+
+func TestGetLowerThirdPlaylistEntries(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	lowerThird1 := LowerThird{TopText: "Welcome", DisplayOrder: 1}
+	db.CreateLowerThird(&lowerThird1)
+	lowerThird2 := LowerThird{TopText: "Emcee", BottomText: "Jane Doe", DisplayOrder: 2}
+	db.CreateLowerThird(&lowerThird2)
+
+	// Check that deleted lower thirds are skipped.
+	playlist := LowerThirdPlaylist{Name: "Opening", LowerThirdIds: []int{2, 254, 1}}
+	lowerThirds, err := db.GetLowerThirdPlaylistEntries(&playlist)
+	assert.Nil(t, err)
+	assert.Equal(t, []LowerThird{lowerThird2, lowerThird1}, lowerThirds)
+}
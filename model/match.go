@@ -8,6 +8,7 @@ package model
 import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/game"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -27,6 +28,31 @@ func (t MatchType) Get() MatchType {
 	return t
 }
 
+// Represents the reason that a match was flagged to be replayed.
+type ReplayReason int
+
+const (
+	NoReplay ReplayReason = iota
+	ReplayFieldFault
+	ReplayScoringError
+	ReplayRefereeDecision
+	ReplayOther
+)
+
+func (reason ReplayReason) String() string {
+	switch reason {
+	case ReplayFieldFault:
+		return "Field Fault"
+	case ReplayScoringError:
+		return "Scoring Error"
+	case ReplayRefereeDecision:
+		return "Referee Decision"
+	case ReplayOther:
+		return "Other"
+	}
+	return ""
+}
+
 type Match struct {
 	Id                  int `db:"id"`
 	Type                MatchType
@@ -54,8 +80,25 @@ type Match struct {
 	ScoreCommittedAt    time.Time
 	FieldReadyAt        time.Time
 	Status              game.MatchStatus
+	WinReason           game.MatchWinReason
+	TiebreakLevel       int
 	UseTiebreakCriteria bool
 	TbaMatchKey         TbaMatchKey
+	ReplayOfMatchId     int
+	ReplacedByMatchId   int
+	ReplayReason        ReplayReason
+	Bye                 bool // Whether the scorekeeper has marked the match as having nothing to play.
+}
+
+// Criteria for selecting a page of matches; fields left at their zero value don't restrict the selection.
+type MatchFilter struct {
+	Types         []MatchType
+	TeamId        int
+	PlayedOnly    bool
+	UnplayedOnly  bool
+	IncludeHidden bool
+	Offset        int
+	Limit         int
 }
 
 type TbaMatchKey struct {
@@ -117,10 +160,56 @@ func (database *Database) GetMatchesByType(matchType MatchType, includeHidden bo
 	return matchingMatches, nil
 }
 
+// Returns the page of matches selected by the given filter, ordered by type and then by order within the type, along
+// with the total number of matches that the filter selects across all pages.
+func (database *Database) GetMatches(filter MatchFilter) ([]Match, int, error) {
+	matches, err := database.matchTable.getAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matchingMatches []Match
+	for _, match := range matches {
+		if filter.matches(&match) {
+			matchingMatches = append(matchingMatches, match)
+		}
+	}
+	sort.Slice(matchingMatches, func(i, j int) bool {
+		if matchingMatches[i].Type != matchingMatches[j].Type {
+			return matchingMatches[i].Type < matchingMatches[j].Type
+		}
+		return matchingMatches[i].TypeOrder < matchingMatches[j].TypeOrder
+	})
+
+	total := len(matchingMatches)
+	start := min(filter.Offset, total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+	return matchingMatches[start:end], total, nil
+}
+
 func (match *Match) IsComplete() bool {
 	return match.Status == game.RedWonMatch || match.Status == game.BlueWonMatch || match.Status == game.TieMatch
 }
 
+// Returns true if the match has been flagged for replay, in which case its result is superseded by that of the replay.
+func (match *Match) IsReplaced() bool {
+	return match.ReplacedByMatchId > 0
+}
+
+// Returns true if the match has been marked as a bye, in which case there is nothing to play. A match is never deemed a
+// bye just for having no teams, since open practice match slots are filled from the filler line when it is loaded.
+func (match *Match) IsBye() bool {
+	return match.Bye
+}
+
+// Returns true if the match is of a type that can be flagged for replay and cloned into the schedule.
+func (match *Match) ShouldAllowReplay() bool {
+	return match.Type == Practice || match.Type == Qualification
+}
+
 // Returns true if the match is of a type that allows substitution of teams.
 func (match *Match) ShouldAllowSubstitution() bool {
 	return match.Type != Qualification
@@ -146,6 +235,70 @@ func (match *Match) ShouldUpdatePlayoffMatches() bool {
 	return match.Type == Playoff
 }
 
+// Returns true if the given team is playing in the match, whether on the red or blue alliance.
+func (match *Match) HasTeam(teamId int) bool {
+	return teamId > 0 && (match.Red1 == teamId || match.Red2 == teamId || match.Red3 == teamId ||
+		match.Blue1 == teamId || match.Blue2 == teamId || match.Blue3 == teamId)
+}
+
+// Returns the IDs of the red alliance's teams in the positions that are used with the given number of teams per
+// alliance.
+func (match *Match) RedTeamIds(teamsPerAlliance int) []int {
+	return activePositions([]int{match.Red1, match.Red2, match.Red3}, teamsPerAlliance)
+}
+
+// Returns the IDs of the blue alliance's teams in the positions that are used with the given number of teams per
+// alliance.
+func (match *Match) BlueTeamIds(teamsPerAlliance int) []int {
+	return activePositions([]int{match.Blue1, match.Blue2, match.Blue3}, teamsPerAlliance)
+}
+
+// Returns the pointers to the team positions of both alliances that are used with the given number of teams per
+// alliance, in alliance station order.
+func (match *Match) ActiveTeamPositions(teamsPerAlliance int) []*int {
+	redPositions := activePositions([]*int{&match.Red1, &match.Red2, &match.Red3}, teamsPerAlliance)
+	bluePositions := activePositions([]*int{&match.Blue1, &match.Blue2, &match.Blue3}, teamsPerAlliance)
+	return append(redPositions, bluePositions...)
+}
+
+// Trims the given alliance positions to those used with the given number of teams per alliance; an invalid number is
+// taken to mean that all the positions are used.
+func activePositions[T any](positions []T, teamsPerAlliance int) []T {
+	if teamsPerAlliance > 0 && teamsPerAlliance < len(positions) {
+		return positions[:teamsPerAlliance]
+	}
+	return positions
+}
+
+// Returns true if the given match is selected by the filter, disregarding the paging.
+func (filter *MatchFilter) matches(match *Match) bool {
+	if len(filter.Types) > 0 && !slices.Contains(filter.Types, match.Type) {
+		return false
+	}
+	if filter.TeamId > 0 && !match.HasTeam(filter.TeamId) {
+		return false
+	}
+	if filter.PlayedOnly && !match.IsComplete() || filter.UnplayedOnly && match.IsComplete() {
+		return false
+	}
+	return filter.IncludeHidden || match.Status != game.MatchHidden
+}
+
+// Returns the enum equivalent of the given replay reason string.
+func ReplayReasonFromString(reasonString string) (ReplayReason, error) {
+	switch strings.ToLower(reasonString) {
+	case "fieldfault":
+		return ReplayFieldFault, nil
+	case "scoringerror":
+		return ReplayScoringError, nil
+	case "refereedecision":
+		return ReplayRefereeDecision, nil
+	case "other":
+		return ReplayOther, nil
+	}
+	return NoReplay, fmt.Errorf("invalid replay reason %q", reasonString)
+}
+
 // Returns the enum equivalent of the given match type string.
 func MatchTypeFromString(matchTypeString string) (MatchType, error) {
 	switch strings.ToLower(matchTypeString) {
@@ -10,14 +10,34 @@ import (
 )
 
 type MatchResult struct {
-	Id         int `db:"id"`
-	MatchId    int
-	PlayNumber int
-	MatchType  MatchType
-	RedScore   *game.Score
-	BlueScore  *game.Score
-	RedCards   map[string]string
-	BlueCards  map[string]string
+	Id             int `db:"id"`
+	MatchId        int
+	PlayNumber     int
+	MatchType      MatchType
+	RedScore       *game.Score
+	BlueScore      *game.Score
+	RedCards       map[string]string
+	BlueCards      map[string]string
+	RedCardRules   map[string]int // IDs of the rules for which each red team's card was given, if known.
+	BlueCardRules  map[string]int // IDs of the rules for which each blue team's card was given, if known.
+	RedScoringLog  []game.ScoringLogEntry
+	BlueScoringLog []game.ScoringLogEntry
+	RedBypasses    map[string]string // Reasons for which each bypassed red team's station was bypassed.
+	BlueBypasses   map[string]string // Reasons for which each bypassed blue team's station was bypassed.
+}
+
+// Reasons for which an alliance station can be bypassed.
+const (
+	BypassNoShow       = "noShow"
+	BypassRedCard      = "redCard"
+	BypassRobotFailure = "robotFailure"
+)
+
+// Human-readable descriptions of each bypass reason.
+var BypassReasonNames = map[string]string{
+	BypassNoShow:       "No-show",
+	BypassRedCard:      "Red card",
+	BypassRobotFailure: "Robot failure",
 }
 
 // Returns a new match result object with empty slices instead of nil.
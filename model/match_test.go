@@ -4,6 +4,7 @@
 package model
 
 import (
+	"fmt"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -166,6 +167,85 @@ func TestGetMatchesByType(t *testing.T) {
 	}
 }
 
+func TestGetMatchesWithFilter(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	for i := 1; i <= 5; i++ {
+		match := Match{Type: Qualification, TypeOrder: i, ShortName: fmt.Sprintf("Q%d", i), Red1: 100 + i, Blue3: 254}
+		if i <= 3 {
+			match.Status = game.RedWonMatch
+		}
+		assert.Nil(t, db.CreateMatch(&match))
+	}
+	assert.Nil(t, db.CreateMatch(&Match{Type: Practice, TypeOrder: 1, ShortName: "P1", Red2: 254}))
+	assert.Nil(t, db.CreateMatch(&Match{Type: Practice, TypeOrder: 2, ShortName: "P2", Status: game.MatchHidden}))
+
+	getShortNames := func(filter MatchFilter) ([]string, int) {
+		matches, total, err := db.GetMatches(filter)
+		assert.Nil(t, err)
+		var shortNames []string
+		for _, match := range matches {
+			shortNames = append(shortNames, match.ShortName)
+		}
+		return shortNames, total
+	}
+
+	shortNames, total := getShortNames(MatchFilter{})
+	assert.Equal(t, []string{"P1", "Q1", "Q2", "Q3", "Q4", "Q5"}, shortNames)
+	assert.Equal(t, 6, total)
+	shortNames, total = getShortNames(MatchFilter{IncludeHidden: true, Types: []MatchType{Practice}})
+	assert.Equal(t, []string{"P1", "P2"}, shortNames)
+	assert.Equal(t, 2, total)
+	shortNames, _ = getShortNames(MatchFilter{TeamId: 254})
+	assert.Equal(t, []string{"P1", "Q1", "Q2", "Q3", "Q4", "Q5"}, shortNames)
+	shortNames, _ = getShortNames(MatchFilter{TeamId: 102})
+	assert.Equal(t, []string{"Q2"}, shortNames)
+	shortNames, _ = getShortNames(MatchFilter{Types: []MatchType{Qualification}, TeamId: 254, PlayedOnly: true})
+	assert.Equal(t, []string{"Q1", "Q2", "Q3"}, shortNames)
+	shortNames, _ = getShortNames(MatchFilter{UnplayedOnly: true})
+	assert.Equal(t, []string{"P1", "Q4", "Q5"}, shortNames)
+
+	// Test paging.
+	shortNames, total = getShortNames(MatchFilter{Types: []MatchType{Qualification}, Offset: 1, Limit: 2})
+	assert.Equal(t, []string{"Q2", "Q3"}, shortNames)
+	assert.Equal(t, 5, total)
+	shortNames, total = getShortNames(MatchFilter{Types: []MatchType{Qualification}, Offset: 4, Limit: 2})
+	assert.Equal(t, []string{"Q5"}, shortNames)
+	assert.Equal(t, 5, total)
+	shortNames, total = getShortNames(MatchFilter{Offset: 10})
+	assert.Empty(t, shortNames)
+	assert.Equal(t, 6, total)
+}
+
+func TestMatchHasTeam(t *testing.T) {
+	match := Match{Red1: 254, Red3: 1114, Blue2: 2056}
+	assert.True(t, match.HasTeam(254))
+	assert.True(t, match.HasTeam(1114))
+	assert.True(t, match.HasTeam(2056))
+	assert.False(t, match.HasTeam(846))
+	assert.False(t, match.HasTeam(0))
+}
+
+func TestMatchTeamIds(t *testing.T) {
+	match := Match{Red1: 254, Red2: 1114, Red3: 2056, Blue1: 846, Blue2: 971, Blue3: 604}
+	assert.Equal(t, []int{254, 1114, 2056}, match.RedTeamIds(3))
+	assert.Equal(t, []int{846, 971, 604}, match.BlueTeamIds(3))
+	assert.Equal(t, []int{254, 1114}, match.RedTeamIds(2))
+	assert.Equal(t, []int{846}, match.BlueTeamIds(1))
+	assert.Equal(t, []int{254, 1114, 2056}, match.RedTeamIds(0))
+
+	positions := match.ActiveTeamPositions(2)
+	assert.Equal(t, []*int{&match.Red1, &match.Red2, &match.Blue1, &match.Blue2}, positions)
+}
+
+func TestMatchIsBye(t *testing.T) {
+	match := Match{}
+	assert.False(t, match.IsBye())
+	match.Bye = true
+	assert.True(t, match.IsBye())
+}
+
 func TestMatchTypeFromString(t *testing.T) {
 	matchType, err := MatchTypeFromString("test")
 	assert.Nil(t, err)
@@ -211,3 +291,27 @@ func TestTbaMatchKeyString(t *testing.T) {
 	key = TbaMatchKey{CompLevel: "f", SetNumber: 1, MatchNumber: 4}
 	assert.Equal(t, "f1m4", key.String())
 }
+
+func TestReplayReasonFromString(t *testing.T) {
+	reason, err := ReplayReasonFromString("fieldFault")
+	assert.Nil(t, err)
+	assert.Equal(t, ReplayFieldFault, reason)
+	assert.Equal(t, "Field Fault", reason.String())
+
+	reason, err = ReplayReasonFromString("scoringError")
+	assert.Nil(t, err)
+	assert.Equal(t, ReplayScoringError, reason)
+
+	reason, err = ReplayReasonFromString("refereeDecision")
+	assert.Nil(t, err)
+	assert.Equal(t, ReplayRefereeDecision, reason)
+
+	reason, err = ReplayReasonFromString("other")
+	assert.Nil(t, err)
+	assert.Equal(t, ReplayOther, reason)
+
+	_, err = ReplayReasonFromString("blorpy")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid replay reason \"blorpy\"", err.Error())
+	}
+}
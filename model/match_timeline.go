@@ -0,0 +1,95 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the timeline of events recorded during each play of a match.
+
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type MatchTimelineEventType string
+
+const (
+	TimelineMatchState   MatchTimelineEventType = "matchState"
+	TimelineStop         MatchTimelineEventType = "stop"
+	TimelineFoul         MatchTimelineEventType = "foul"
+	TimelineCard         MatchTimelineEventType = "card"
+	TimelineScore        MatchTimelineEventType = "score"
+	TimelineDsConnection MatchTimelineEventType = "dsConnection"
+	TimelineFieldFault   MatchTimelineEventType = "fieldFault"
+)
+
+type MatchTimeline struct {
+	Id         int `db:"id"`
+	MatchId    int
+	PlayNumber int
+	Events     []MatchTimelineEvent
+}
+
+// Represents a single occurrence during a match, such as a period transition or a change to the score.
+type MatchTimelineEvent struct {
+	Time        time.Time
+	MatchTimeMs int // Milliseconds since the start of the match, or zero if it had not yet started.
+	Type        MatchTimelineEventType
+	Source      string // The alliance, alliance station, or panel that the event relates to, if any.
+	Description string
+}
+
+func (database *Database) CreateMatchTimeline(matchTimeline *MatchTimeline) error {
+	return database.matchTimelineTable.create(matchTimeline)
+}
+
+// Returns the timelines of all plays of the given match, ordered by play number.
+func (database *Database) GetMatchTimelinesForMatch(matchId int) ([]MatchTimeline, error) {
+	matchTimelines, err := database.matchTimelineTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingTimelines []MatchTimeline
+	for _, matchTimeline := range matchTimelines {
+		if matchTimeline.MatchId == matchId {
+			matchingTimelines = append(matchingTimelines, matchTimeline)
+		}
+	}
+	sort.Slice(matchingTimelines, func(i, j int) bool {
+		return matchingTimelines[i].PlayNumber < matchingTimelines[j].PlayNumber
+	})
+	return matchingTimelines, nil
+}
+
+func (database *Database) GetMatchTimelineById(id int) (*MatchTimeline, error) {
+	return database.matchTimelineTable.getById(id)
+}
+
+// Returns the IDs of the timelines of every match, keyed by match ID and ordered by play number, so that the timelines
+// can then be loaded one at a time.
+func (database *Database) GetMatchTimelineIdsByMatch() (map[int][]int, error) {
+	playNumbers := make(map[int]int)
+	matchTimelineIds := make(map[int][]int)
+	err := database.matchTimelineTable.forEach(func(matchTimeline *MatchTimeline) error {
+		playNumbers[matchTimeline.Id] = matchTimeline.PlayNumber
+		matchTimelineIds[matchTimeline.MatchId] = append(matchTimelineIds[matchTimeline.MatchId], matchTimeline.Id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ids := range matchTimelineIds {
+		sort.Slice(ids, func(i, j int) bool {
+			return playNumbers[ids[i]] < playNumbers[ids[j]]
+		})
+	}
+	return matchTimelineIds, nil
+}
+
+func (database *Database) DeleteMatchTimeline(id int) error {
+	return database.matchTimelineTable.delete(id)
+}
+
+func (database *Database) TruncateMatchTimelines() error {
+	return database.matchTimelineTable.truncate()
+}
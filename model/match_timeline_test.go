@@ -0,0 +1,68 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMatchTimelineCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	matchTimelines, err := db.GetMatchTimelinesForMatch(254)
+	assert.Nil(t, err)
+	assert.Empty(t, matchTimelines)
+
+	now := time.Now().UTC()
+	matchTimeline1 := MatchTimeline{
+		MatchId:    254,
+		PlayNumber: 2,
+		Events: []MatchTimelineEvent{
+			{now, 0, TimelineMatchState, "", "Match state changed to Autonomous"},
+			{now.Add(1500 * time.Millisecond), 1500, TimelineStop, "R1", "E-stop activated"},
+		},
+	}
+	matchTimeline2 := MatchTimeline{MatchId: 1114, PlayNumber: 1}
+	matchTimeline3 := MatchTimeline{MatchId: 254, PlayNumber: 1}
+	assert.Nil(t, db.CreateMatchTimeline(&matchTimeline1))
+	assert.Nil(t, db.CreateMatchTimeline(&matchTimeline2))
+	assert.Nil(t, db.CreateMatchTimeline(&matchTimeline3))
+
+	matchTimelines, err = db.GetMatchTimelinesForMatch(254)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matchTimelines)) {
+		assert.Equal(t, matchTimeline3.Id, matchTimelines[0].Id)
+		assert.Equal(t, matchTimeline1.Id, matchTimelines[1].Id)
+		if assert.Equal(t, 2, len(matchTimelines[1].Events)) {
+			assert.True(t, now.Add(1500*time.Millisecond).Equal(matchTimelines[1].Events[1].Time))
+			assert.Equal(t, 1500, matchTimelines[1].Events[1].MatchTimeMs)
+			assert.Equal(t, TimelineStop, matchTimelines[1].Events[1].Type)
+			assert.Equal(t, "R1", matchTimelines[1].Events[1].Source)
+			assert.Equal(t, "E-stop activated", matchTimelines[1].Events[1].Description)
+		}
+	}
+
+	matchTimelineIds, err := db.GetMatchTimelineIdsByMatch()
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		map[int][]int{254: {matchTimeline3.Id, matchTimeline1.Id}, 1114: {matchTimeline2.Id}},
+		matchTimelineIds,
+	)
+	matchTimeline, err := db.GetMatchTimelineById(matchTimeline1.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(matchTimeline.Events))
+
+	assert.Nil(t, db.DeleteMatchTimeline(matchTimeline1.Id))
+	matchTimelines, err = db.GetMatchTimelinesForMatch(254)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(matchTimelines))
+
+	assert.Nil(t, db.TruncateMatchTimelines())
+	matchTimelines, err = db.GetMatchTimelinesForMatch(1114)
+	assert.Nil(t, err)
+	assert.Empty(t, matchTimelines)
+}
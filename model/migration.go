@@ -0,0 +1,151 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Framework for upgrading the schema of an existing database in place when it is opened by a newer version of the
+// software, so that an event can upgrade mid-season without losing its data.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"slices"
+	"time"
+)
+
+// Represents a single step in the evolution of the database schema.
+type migration struct {
+	Version     int
+	Description string
+	// Transforms the data in the database from the previous version to this one.
+	Migrate func(database *Database) error
+}
+
+// Records a migration that has been applied to the database.
+type SchemaMigration struct {
+	Version     int `db:"id,manual"`
+	Description string
+	AppliedAt   time.Time
+}
+
+// Returns the schema version of the database, which is that of the latest migration applied to it.
+func (database *Database) GetSchemaVersion() (int, error) {
+	schemaMigrations, err := database.schemaMigrationTable.getAll()
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, schemaMigration := range schemaMigrations {
+		version = max(version, schemaMigration.Version)
+	}
+	return version, nil
+}
+
+// Brings the database up to the latest schema version by applying any of the given migrations that haven't been
+// applied yet, in order. A newly created database is already at the latest version so is just stamped as such. The
+// database is backed up before anything is changed so that it can be restored if a migration goes wrong.
+func (database *Database) migrate(migrations []migration, isNewDatabase bool) error {
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return fmt.Errorf("migration %q has version %d; expected %d", migration.Description, migration.Version, i+1)
+		}
+	}
+	currentVersion, err := database.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if currentVersion > len(migrations) {
+		return fmt.Errorf(
+			"database schema version %d is newer than the latest version %d supported by this software; upgrade the "+
+				"software or restore an older backup",
+			currentVersion,
+			len(migrations),
+		)
+	}
+	pendingMigrations := migrations[currentVersion:]
+	if len(pendingMigrations) == 0 {
+		return nil
+	}
+
+	if isNewDatabase {
+		for _, migration := range pendingMigrations {
+			if err = database.recordMigration(migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err = database.Backup(database.getRawEventName(), fmt.Sprintf("pre_migration_v%d", currentVersion)); err != nil {
+		return fmt.Errorf("failed to back up database before migrating it: %v", err)
+	}
+	for _, migration := range pendingMigrations {
+		logging.Database.Info(
+			"Migrating database.", "schemaVersion", migration.Version, "description", migration.Description,
+		)
+		if err = migration.Migrate(database); err != nil {
+			return fmt.Errorf("failed to migrate database to schema version %d: %v", migration.Version, err)
+		}
+		if err = database.recordMigration(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (database *Database) recordMigration(migration migration) error {
+	return database.schemaMigrationTable.create(
+		&SchemaMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()},
+	)
+}
+
+// Applies the given function to the raw JSON of each record in the given table, saving the records that it reports
+// having changed. Migrations should use this rather than the typed table methods, since the structs will have moved on
+// from the schema that the migration was written against.
+func (database *Database) migrateRecords(tableName string, migrateRecord func(record map[string]any) bool) error {
+	tableNames, err := database.store.tableNames()
+	if err != nil || !slices.Contains(tableNames, tableName) {
+		return err
+	}
+	updatedRecords := make(map[int][]byte)
+	err = database.store.forEach(tableName, func(id int, recordJson []byte) error {
+		var record map[string]any
+		if err := json.Unmarshal(recordJson, &record); err != nil {
+			return err
+		}
+		if migrateRecord(record) {
+			updatedRecordJson, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			updatedRecords[id] = updatedRecordJson
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Bolt doesn't allow modifying a bucket while iterating over it, so write the changes afterward.
+	for id, recordJson := range updatedRecords {
+		if err = database.store.update(tableName, id, recordJson); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the event name from the stored event settings without depending on their current schema, for use in naming
+// the pre-migration backup.
+func (database *Database) getRawEventName() string {
+	eventName := "Untitled Event"
+	_ = database.store.forEach("EventSettings", func(id int, recordJson []byte) error {
+		var record struct{ Name string }
+		if json.Unmarshal(recordJson, &record) == nil && record.Name != "" {
+			eventName = record.Name
+		}
+		return nil
+	})
+	return eventName
+}
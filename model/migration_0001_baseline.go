@@ -0,0 +1,11 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Migration 1: establishes the schema version of databases created before migrations were tracked.
+
+package model
+
+// The schema at the time that migrations were introduced is the baseline, so there is nothing to change.
+func migrateBaseline(database *Database) error {
+	return nil
+}
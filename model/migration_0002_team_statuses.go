@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Migration 2: moves the flags tracking each team's progress at the event out of the team records and into team
+// statuses.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Fields of the team records that have moved into the team statuses.
+var migratedTeamStatusFields = []string{"InspectionPassed", "HasConnected", "YellowCard"}
+
+// Team status record as of this migration, which is declared here so that it doesn't change along with the model.
+type migratedTeamStatus struct {
+	TeamId           int
+	InspectionPassed bool
+	HasConnected     bool
+	YellowCard       bool
+	UpdatedAt        time.Time
+}
+
+func migrateTeamStatuses(database *Database) error {
+	var teamStatuses []migratedTeamStatus
+	err := database.migrateRecords("Team", func(record map[string]any) bool {
+		teamId, _ := record["Id"].(float64)
+		teamStatus := migratedTeamStatus{TeamId: int(teamId), UpdatedAt: time.Now()}
+		teamStatus.InspectionPassed, _ = record["InspectionPassed"].(bool)
+		teamStatus.HasConnected, _ = record["HasConnected"].(bool)
+		teamStatus.YellowCard, _ = record["YellowCard"].(bool)
+		if teamStatus.InspectionPassed || teamStatus.HasConnected || teamStatus.YellowCard {
+			teamStatuses = append(teamStatuses, teamStatus)
+		}
+
+		changed := false
+		for _, field := range migratedTeamStatusFields {
+			if _, ok := record[field]; ok {
+				delete(record, field)
+				changed = true
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, teamStatus := range teamStatuses {
+		recordJson, err := json.Marshal(teamStatus)
+		if err != nil {
+			return err
+		}
+		if err = database.store.insert("TeamStatus", teamStatus.TeamId, recordJson); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Opens a database in a scratch directory that also receives any backups, so that they don't end up in the source tree.
+func setupMigrationTestDb(t *testing.T) (*Database, string) {
+	BaseDir = t.TempDir()
+	t.Cleanup(func() { BaseDir = ".." })
+	dbPath := filepath.Join(BaseDir, "migration_test.db")
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	return database, dbPath
+}
+
+func getBackups(t *testing.T) []string {
+	backups, _ := filepath.Glob(filepath.Join(BaseDir, backupsDir, "*.db"))
+	return backups
+}
+
+func TestMigrateNewDatabase(t *testing.T) {
+	database, _ := setupMigrationTestDb(t)
+
+	version, err := database.GetSchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, len(migrations), version)
+	assert.Empty(t, getBackups(t))
+}
+
+func TestMigrateExistingDatabase(t *testing.T) {
+	database, dbPath := setupMigrationTestDb(t)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.Name = "Chezy Champs"
+	assert.Nil(t, database.UpdateEventSettings(eventSettings))
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254}))
+
+	// Simulate a database created before migrations were tracked.
+	assert.Nil(t, database.schemaMigrationTable.truncate())
+	assert.Nil(t, database.Close())
+
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	version, err := database.GetSchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, len(migrations), version)
+	team, _ := database.GetTeamById(254)
+	assert.NotNil(t, team)
+	backups := getBackups(t)
+	if assert.Equal(t, 1, len(backups)) {
+		assert.Contains(t, backups[0], "Chezy_Champs_")
+		assert.Contains(t, backups[0], "pre_migration_v0")
+	}
+	assert.Nil(t, database.Close())
+
+	// Reopening an up-to-date database shouldn't back it up again.
+	database, err = OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(getBackups(t)))
+	database.Close()
+}
+
+func TestMigrateRecords(t *testing.T) {
+	database, _ := setupMigrationTestDb(t)
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254, Nickname: "The Cheesy Poofs"}))
+	assert.Nil(t, database.CreateTeam(&Team{Id: 1114, Nickname: "Simbotics", RobotName: "Simbot"}))
+
+	testMigrations := append(slices.Clone(migrations), migration{
+		Version:     len(migrations) + 1,
+		Description: "Give every team a robot name",
+		Migrate: func(database *Database) error {
+			return database.migrateRecords("Team", func(record map[string]any) bool {
+				if record["RobotName"] != "" {
+					return false
+				}
+				record["RobotName"] = record["Nickname"].(string) + " Robot"
+				return true
+			})
+		},
+	})
+	assert.Nil(t, database.migrate(testMigrations, false))
+	version, _ := database.GetSchemaVersion()
+	assert.Equal(t, len(testMigrations), version)
+	team, _ := database.GetTeamById(254)
+	assert.Equal(t, "The Cheesy Poofs Robot", team.RobotName)
+	team, _ = database.GetTeamById(1114)
+	assert.Equal(t, "Simbot", team.RobotName)
+	assert.Equal(t, 1, len(getBackups(t)))
+
+	// Migrating records of a table that doesn't exist should do nothing.
+	assert.Nil(t, database.migrateRecords("Blorpy", func(record map[string]any) bool { return true }))
+}
+
+func TestMigrateErrors(t *testing.T) {
+	database, _ := setupMigrationTestDb(t)
+
+	// Check that a failed migration isn't recorded as having been applied.
+	failingMigrations := append(slices.Clone(migrations), migration{
+		len(migrations) + 1, "Fail", func(database *Database) error { return errors.New("oh noes") },
+	})
+	err := database.migrate(failingMigrations, false)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "oh noes")
+	}
+	version, _ := database.GetSchemaVersion()
+	assert.Equal(t, len(migrations), version)
+
+	misorderedMigrations := append(slices.Clone(migrations), migration{len(migrations) + 2, "Skip", migrateBaseline})
+	err = database.migrate(misorderedMigrations, false)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "expected")
+	}
+
+	// Check that a database from a newer version of the software is refused.
+	assert.Nil(t, database.schemaMigrationTable.create(&SchemaMigration{Version: len(migrations) + 1}))
+	assert.Nil(t, database.Close())
+	_, err = OpenDatabase(database.Path)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "is newer than the latest version")
+	}
+	_, err = os.Stat(database.Path)
+	assert.Nil(t, err)
+}
+
+func TestMigrateTeamStatuses(t *testing.T) {
+	database, dbPath := setupMigrationTestDb(t)
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254}))
+	assert.Nil(t, database.CreateTeam(&Team{Id: 1114}))
+
+	// Simulate a database from before the team statuses were split out of the team records.
+	oldTeamJson := `{"Id": 254, "Nickname": "The Cheesy Poofs", "InspectionPassed": true, "YellowCard": true}`
+	assert.Nil(t, database.store.update("Team", 254, []byte(oldTeamJson)))
+	assert.Nil(t, database.store.update("Team", 1114, []byte(`{"Id": 1114, "HasConnected": false}`)))
+	assert.Nil(t, database.schemaMigrationTable.truncate())
+	assert.Nil(t, database.schemaMigrationTable.create(&SchemaMigration{Version: 1}))
+	assert.Nil(t, database.Close())
+
+	database, err := OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	defer database.Close()
+	version, _ := database.GetSchemaVersion()
+	assert.Equal(t, len(migrations), version)
+	team, _ := database.GetTeamById(254)
+	assert.Equal(t, "The Cheesy Poofs", team.Nickname)
+	teamStatus, _ := database.GetTeamStatus(254)
+	assert.True(t, teamStatus.InspectionPassed)
+	assert.False(t, teamStatus.HasConnected)
+	assert.True(t, teamStatus.YellowCard)
+	teamStatuses, _ := database.GetAllTeamStatuses()
+	assert.Equal(t, 1, len(teamStatuses))
+
+	// Check that the old fields are gone from the team records.
+	teamJson, _ := database.store.get("Team", 1114)
+	assert.NotContains(t, string(teamJson), "HasConnected")
+}
@@ -0,0 +1,14 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Ordered list of the migrations that make up the database schema.
+
+package model
+
+// To change the schema in a way that existing data can't simply be backfilled at load time, add a new migration to the
+// end of this list and implement it in its own file named after its version. Never change or reorder a migration once
+// it has been released, since databases in the field will already have applied it.
+var migrations = []migration{
+	{1, "Record the baseline schema", migrateBaseline},
+	{2, "Move the team inspection, connection and card flags into team statuses", migrateTeamStatuses},
+}
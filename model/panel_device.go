@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a tablet that has been enrolled to run a scoring or referee panel, which keeps
+// its own session so that it can be revoked individually and can resume where it left off after reconnecting.
+
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type PanelDevice struct {
+	Id             int `db:"id"`
+	Token          string
+	Name           string
+	Username       string
+	Role           string
+	SessionToken   string
+	PanelPath      string
+	MatchId        int
+	ScoreCommitted bool
+	CreatedAt      time.Time
+	LastSeenAt     time.Time
+}
+
+func (database *Database) CreatePanelDevice(panelDevice *PanelDevice) error {
+	return database.panelDeviceTable.create(panelDevice)
+}
+
+func (database *Database) GetPanelDeviceById(id int) (*PanelDevice, error) {
+	return database.panelDeviceTable.getById(id)
+}
+
+// Returns the device with the given session token, or nil if there isn't one.
+func (database *Database) GetPanelDeviceByToken(token string) (*PanelDevice, error) {
+	panelDevices, err := database.panelDeviceTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, panelDevice := range panelDevices {
+		if panelDevice.Token == token {
+			return &panelDevice, nil
+		}
+	}
+	return nil, nil
+}
+
+func (database *Database) UpdatePanelDevice(panelDevice *PanelDevice) error {
+	return database.panelDeviceTable.update(panelDevice)
+}
+
+func (database *Database) DeletePanelDevice(id int) error {
+	return database.panelDeviceTable.delete(id)
+}
+
+func (database *Database) TruncatePanelDevices() error {
+	return database.panelDeviceTable.truncate()
+}
+
+// Returns all enrolled devices, ordered by the panel they run and then by name.
+func (database *Database) GetAllPanelDevices() ([]PanelDevice, error) {
+	panelDevices, err := database.panelDeviceTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(panelDevices, func(i, j int) bool {
+		if panelDevices[i].PanelPath != panelDevices[j].PanelPath {
+			return panelDevices[i].PanelPath < panelDevices[j].PanelPath
+		}
+		return panelDevices[i].Name < panelDevices[j].Name
+	})
+	return panelDevices, nil
+}
+
+// Returns true if the device was enrolled with a role that grants access to pages that are open to any of the given
+// roles.
+func (panelDevice *PanelDevice) HasRole(roles ...string) bool {
+	userAccount := UserAccount{Role: panelDevice.Role}
+	return userAccount.HasRole(roles...)
+}
+
+// Returns whether the device had committed its score for the given match before it last disconnected.
+func (panelDevice *PanelDevice) IsScoreCommitted(matchId int) bool {
+	return panelDevice.MatchId == matchId && panelDevice.ScoreCommitted
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetNonexistentPanelDevice(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	panelDevice, err := db.GetPanelDeviceById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, panelDevice)
+	panelDevice, err = db.GetPanelDeviceByToken("blorpy")
+	assert.Nil(t, err)
+	assert.Nil(t, panelDevice)
+}
+
+func TestPanelDeviceCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	panelDevice := PanelDevice{
+		Token:     "token1",
+		Name:      "10.0.100.201",
+		Username:  "Bertha",
+		Role:      RefereeRole,
+		PanelPath: "/panels/scoring/red",
+		CreatedAt: time.Unix(1000, 0).UTC(),
+	}
+	assert.Nil(t, db.CreatePanelDevice(&panelDevice))
+	panelDevice2, err := db.GetPanelDeviceById(panelDevice.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, panelDevice, *panelDevice2)
+	panelDevice2, err = db.GetPanelDeviceByToken("token1")
+	assert.Nil(t, err)
+	assert.Equal(t, panelDevice, *panelDevice2)
+
+	panelDevice.PanelPath = "/panels/referee?position=head"
+	assert.Nil(t, db.UpdatePanelDevice(&panelDevice))
+	panelDevice2, err = db.GetPanelDeviceById(panelDevice.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "/panels/referee?position=head", panelDevice2.PanelPath)
+
+	assert.Nil(t, db.DeletePanelDevice(panelDevice.Id))
+	panelDevice2, err = db.GetPanelDeviceById(panelDevice.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, panelDevice2)
+}
+
+func TestGetAllPanelDevices(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	db.CreatePanelDevice(&PanelDevice{Token: "token1", Name: "Tablet B", PanelPath: "/panels/scoring/red"})
+	db.CreatePanelDevice(&PanelDevice{Token: "token2", Name: "Tablet C", PanelPath: "/panels/referee"})
+	db.CreatePanelDevice(&PanelDevice{Token: "token3", Name: "Tablet A", PanelPath: "/panels/scoring/red"})
+	panelDevices, err := db.GetAllPanelDevices()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(panelDevices)) {
+		assert.Equal(t, "Tablet C", panelDevices[0].Name)
+		assert.Equal(t, "Tablet A", panelDevices[1].Name)
+		assert.Equal(t, "Tablet B", panelDevices[2].Name)
+	}
+
+	assert.Nil(t, db.TruncatePanelDevices())
+	panelDevices, err = db.GetAllPanelDevices()
+	assert.Nil(t, err)
+	assert.Empty(t, panelDevices)
+}
+
+func TestPanelDeviceRolesAndScoreCommitted(t *testing.T) {
+	panelDevice := PanelDevice{Role: RefereeRole, MatchId: 12, ScoreCommitted: true}
+	assert.True(t, panelDevice.HasRole(RefereeRole))
+	assert.False(t, panelDevice.HasRole(ScorekeeperRole))
+	assert.True(t, panelDevice.IsScoreCommitted(12))
+	assert.False(t, panelDevice.IsScoreCommitted(13))
+
+	panelDevice.Role = AdminRole
+	assert.True(t, panelDevice.HasRole(ScorekeeperRole))
+	panelDevice.ScoreCommitted = false
+	assert.False(t, panelDevice.IsScoreCommitted(12))
+}
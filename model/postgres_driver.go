@@ -0,0 +1,11 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Links in the Postgres driver, which is left out of the default build to keep the embedded-only binary free of the
+// extra dependency. Build with '-tags postgres' to enable it.
+
+//go:build postgres
+
+package model
+
+import _ "github.com/lib/pq"
@@ -0,0 +1,220 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Store implementation backed by a Postgres server, for events that want to keep their data in a central database that
+// external reporting tools can query. Each table holds the records as JSONB alongside their IDs, and lives in whichever
+// schema the connection's search_path points at, so that several fields can share one server.
+
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Name of the database/sql driver used to connect to Postgres. The driver itself is only linked in when building with
+// the "postgres" tag; see postgres_driver.go.
+const postgresDriverName = "postgres"
+
+type postgresStore struct {
+	db       *sql.DB     // Connection pool, or nil if the store belongs to a transaction.
+	executor sqlExecutor // Either the connection pool or the transaction, through which statements are run.
+}
+
+// Methods shared by a connection pool and a transaction that the store uses to run its statements.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func openPostgresStore(url string) (*postgresStore, error) {
+	if !slices.Contains(sql.Drivers(), postgresDriverName) {
+		return nil, fmt.Errorf("this build doesn't include Postgres support; rebuild it with '-tags postgres'")
+	}
+	db, err := sql.Open(postgresDriverName, url)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &postgresStore{db: db, executor: db}, nil
+}
+
+func (store *postgresStore) createTable(tableName string) error {
+	_, err := store.executor.Exec(
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY, record JSONB NOT NULL)", quoteIdentifier(tableName),
+		),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = store.executor.Exec(
+		fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s", quoteIdentifier(sequenceName(tableName))),
+	)
+	return err
+}
+
+func (store *postgresStore) tableNames() ([]string, error) {
+	// Only consider the tables that look like they were created by this store, to leave room for any others that
+	// reporting tools might add to the same schema.
+	rows, err := store.executor.Query(
+		"SELECT table_name FROM information_schema.columns WHERE table_schema = current_schema() AND " +
+			"column_name = 'record' AND data_type = 'jsonb' ORDER BY table_name",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err = rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	return tableNames, rows.Err()
+}
+
+func (store *postgresStore) get(tableName string, id int) ([]byte, error) {
+	var recordJson []byte
+	err := store.executor.QueryRow(
+		fmt.Sprintf("SELECT record FROM %s WHERE id = $1", quoteIdentifier(tableName)), id,
+	).Scan(&recordJson)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return recordJson, err
+}
+
+func (store *postgresStore) forEach(tableName string, handleRecord func(id int, recordJson []byte) error) error {
+	// Match the ordering of the embedded store, which sorts by the string representation of the ID.
+	rows, err := store.executor.Query(
+		fmt.Sprintf("SELECT id, record FROM %s ORDER BY id::text", quoteIdentifier(tableName)),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var recordJson []byte
+		if err = rows.Scan(&id, &recordJson); err != nil {
+			return err
+		}
+		if err = handleRecord(id, recordJson); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (store *postgresStore) nextId(tableName string) (int, error) {
+	var id int
+	err := store.executor.QueryRow("SELECT nextval($1)", quoteIdentifier(sequenceName(tableName))).Scan(&id)
+	return id, err
+}
+
+func (store *postgresStore) insert(tableName string, id int, recordJson []byte) error {
+	result, err := store.executor.Exec(
+		fmt.Sprintf(
+			"INSERT INTO %s (id, record) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING", quoteIdentifier(tableName),
+		),
+		id,
+		string(recordJson),
+	)
+	return checkRowAffected(result, err, errRecordExists)
+}
+
+func (store *postgresStore) update(tableName string, id int, recordJson []byte) error {
+	result, err := store.executor.Exec(
+		fmt.Sprintf("UPDATE %s SET record = $2 WHERE id = $1", quoteIdentifier(tableName)), id, string(recordJson),
+	)
+	return checkRowAffected(result, err, errRecordNotFound)
+}
+
+func (store *postgresStore) delete(tableName string, id int) error {
+	result, err := store.executor.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = $1", quoteIdentifier(tableName)), id)
+	return checkRowAffected(result, err, errRecordNotFound)
+}
+
+func (store *postgresStore) truncate(tableName string) error {
+	return store.runInTransaction(func(txStore *postgresStore) error {
+		_, err := txStore.executor.Exec(fmt.Sprintf("TRUNCATE %s", quoteIdentifier(tableName)))
+		if err != nil {
+			return err
+		}
+		_, err = txStore.executor.Exec(
+			fmt.Sprintf("ALTER SEQUENCE %s RESTART", quoteIdentifier(sequenceName(tableName))),
+		)
+		return err
+	})
+}
+
+func (store *postgresStore) transaction(handleTransaction func(txStore store) error) error {
+	return store.runInTransaction(func(txStore *postgresStore) error {
+		return handleTransaction(txStore)
+	})
+}
+
+// Runs the given function with a store bound to a new transaction, which is committed if the function returns nil and
+// rolled back otherwise.
+func (store *postgresStore) runInTransaction(handleTransaction func(txStore *postgresStore) error) error {
+	if store.db == nil {
+		// Postgres doesn't support nested transactions, so the inner one simply becomes part of this one.
+		return handleTransaction(store)
+	}
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = handleTransaction(&postgresStore{executor: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (store *postgresStore) writeSnapshot(writer io.Writer) error {
+	return writeBoltSnapshot(store, writer)
+}
+
+func (store *postgresStore) close() error {
+	if store.db == nil {
+		return fmt.Errorf("can't close the database from within a transaction")
+	}
+	return store.db.Close()
+}
+
+// Returns the given error if the statement executed successfully but didn't touch any rows.
+func checkRowAffected(result sql.Result, err error, noRowsErr error) error {
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return noRowsErr
+	}
+	return nil
+}
+
+// Returns the name of the sequence from which IDs are generated for the given table.
+func sequenceName(tableName string) string {
+	return tableName + "_id_seq"
+}
+
+// Quotes the given table or sequence name for use in a statement, preserving its case.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+//go:build postgres
+
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+// Returns a database backed by the Postgres server given in the CHEESY_ARENA_TEST_POSTGRES_URL environment variable,
+// with its teams table emptied, or skips the test if there isn't one.
+func setupTestPostgresDb(t *testing.T) *Database {
+	url := os.Getenv("CHEESY_ARENA_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("CHEESY_ARENA_TEST_POSTGRES_URL is not set")
+	}
+	database, err := OpenDatabase(url)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { database.Close() })
+	assert.Nil(t, database.TruncateTeams())
+	return database
+}
+
+func TestPostgresDriverLinked(t *testing.T) {
+	assert.Contains(t, sql.Drivers(), postgresDriverName)
+}
+
+func TestPostgresStore(t *testing.T) {
+	database := setupTestPostgresDb(t)
+	assert.False(t, database.IsEmbedded())
+
+	team := Team{Id: 254, Nickname: "The Cheesy Poofs"}
+	assert.Nil(t, database.CreateTeam(&team))
+	team2, err := database.GetTeamById(254)
+	assert.Nil(t, err)
+	assert.Equal(t, team, *team2)
+	team.Nickname = "Cheesy Poofs"
+	assert.Nil(t, database.UpdateTeam(&team))
+	team2, _ = database.GetTeamById(254)
+	assert.Equal(t, "Cheesy Poofs", team2.Nickname)
+
+	// Check that a failed transaction leaves nothing behind.
+	err = database.RunInTransaction(func(database *Database) error {
+		if err := database.CreateTeam(&Team{Id: 1114}); err != nil {
+			return err
+		}
+		return fmt.Errorf("blorpy")
+	})
+	assert.Equal(t, "blorpy", err.Error())
+	team2, err = database.GetTeamById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, team2)
+
+	assert.Nil(t, database.DeleteTeam(254))
+	teams, err := database.GetAllTeams()
+	assert.Nil(t, err)
+	assert.Empty(t, teams)
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the web push subscriptions of spectators and team members following teams.
+
+package model
+
+import (
+	"slices"
+	"sort"
+)
+
+type PushSubscription struct {
+	Id           int `db:"id"`
+	Endpoint     string
+	P256dhKey    string
+	AuthSecret   string
+	TeamIds      []int
+	Topics       []string
+	NotifiedKeys []string
+}
+
+func (database *Database) CreatePushSubscription(pushSubscription *PushSubscription) error {
+	return database.pushSubscriptionTable.create(pushSubscription)
+}
+
+func (database *Database) GetPushSubscriptionById(id int) (*PushSubscription, error) {
+	return database.pushSubscriptionTable.getById(id)
+}
+
+// Returns the subscription for the given push service endpoint, or nil if there isn't one.
+func (database *Database) GetPushSubscriptionByEndpoint(endpoint string) (*PushSubscription, error) {
+	pushSubscriptions, err := database.pushSubscriptionTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, pushSubscription := range pushSubscriptions {
+		if pushSubscription.Endpoint == endpoint {
+			return &pushSubscription, nil
+		}
+	}
+	return nil, nil
+}
+
+func (database *Database) UpdatePushSubscription(pushSubscription *PushSubscription) error {
+	return database.pushSubscriptionTable.update(pushSubscription)
+}
+
+func (database *Database) DeletePushSubscription(id int) error {
+	return database.pushSubscriptionTable.delete(id)
+}
+
+func (database *Database) TruncatePushSubscriptions() error {
+	return database.pushSubscriptionTable.truncate()
+}
+
+func (database *Database) GetAllPushSubscriptions() ([]PushSubscription, error) {
+	pushSubscriptions, err := database.pushSubscriptionTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pushSubscriptions, func(i, j int) bool {
+		return pushSubscriptions[i].Id < pushSubscriptions[j].Id
+	})
+	return pushSubscriptions, nil
+}
+
+// Returns true if the subscriber has chosen to be notified about the given team.
+func (pushSubscription *PushSubscription) IsFollowing(teamId int) bool {
+	return slices.Contains(pushSubscription.TeamIds, teamId)
+}
+
+// Returns true if the subscriber has chosen to be notified about the given topic.
+func (pushSubscription *PushSubscription) IsSubscribedTo(topic string) bool {
+	return slices.Contains(pushSubscription.Topics, topic)
+}
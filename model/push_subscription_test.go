@@ -0,0 +1,64 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentPushSubscription(t *testing.T) {
+	db := setupTestDb(t)
+
+	pushSubscription, err := db.GetPushSubscriptionById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, pushSubscription)
+	pushSubscription, err = db.GetPushSubscriptionByEndpoint("https://push.example/abc")
+	assert.Nil(t, err)
+	assert.Nil(t, pushSubscription)
+}
+
+func TestPushSubscriptionCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	pushSubscription := PushSubscription{
+		Endpoint:   "https://push.example/abc",
+		P256dhKey:  "key",
+		AuthSecret: "secret",
+		TeamIds:    []int{254, 1114},
+		Topics:     []string{"upcoming_match"},
+	}
+	assert.Nil(t, db.CreatePushSubscription(&pushSubscription))
+	pushSubscription2, err := db.GetPushSubscriptionById(pushSubscription.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, pushSubscription, *pushSubscription2)
+	assert.True(t, pushSubscription2.IsFollowing(1114))
+	assert.False(t, pushSubscription2.IsFollowing(2056))
+	assert.True(t, pushSubscription2.IsSubscribedTo("upcoming_match"))
+	assert.False(t, pushSubscription2.IsSubscribedTo("delay"))
+
+	pushSubscription.NotifiedKeys = []string{"upcoming_match:12"}
+	assert.Nil(t, db.UpdatePushSubscription(&pushSubscription))
+	pushSubscription2, err = db.GetPushSubscriptionByEndpoint("https://push.example/abc")
+	assert.Nil(t, err)
+	assert.Equal(t, pushSubscription, *pushSubscription2)
+
+	assert.Nil(t, db.CreatePushSubscription(&PushSubscription{Endpoint: "https://push.example/def"}))
+	pushSubscriptions, err := db.GetAllPushSubscriptions()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(pushSubscriptions)) {
+		assert.Equal(t, pushSubscription.Id, pushSubscriptions[0].Id)
+		assert.Equal(t, "https://push.example/def", pushSubscriptions[1].Endpoint)
+	}
+
+	assert.Nil(t, db.DeletePushSubscription(pushSubscription.Id))
+	pushSubscription2, err = db.GetPushSubscriptionById(pushSubscription.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, pushSubscription2)
+
+	assert.Nil(t, db.TruncatePushSubscriptions())
+	pushSubscriptions, err = db.GetAllPushSubscriptions()
+	assert.Nil(t, err)
+	assert.Empty(t, pushSubscriptions)
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the results of practice matches scored in sandbox mode, which are kept apart
+// from the real match results so that they never affect the match record or rankings.
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"sort"
+	"time"
+)
+
+type SandboxMatchResult struct {
+	Id          int `db:"id"`
+	MatchId     int
+	MatchName   string
+	CommittedAt time.Time
+	RedScore    *game.Score
+	BlueScore   *game.Score
+	RedCards    map[string]string
+	BlueCards   map[string]string
+}
+
+func (database *Database) CreateSandboxMatchResult(sandboxMatchResult *SandboxMatchResult) error {
+	return database.sandboxMatchResultTable.create(sandboxMatchResult)
+}
+
+// Returns all sandbox match results, in chronological order.
+func (database *Database) GetAllSandboxMatchResults() ([]SandboxMatchResult, error) {
+	sandboxMatchResults, err := database.sandboxMatchResultTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sandboxMatchResults, func(i, j int) bool {
+		return sandboxMatchResults[i].CommittedAt.Before(sandboxMatchResults[j].CommittedAt)
+	})
+	return sandboxMatchResults, nil
+}
+
+func (database *Database) TruncateSandboxMatchResults() error {
+	return database.sandboxMatchResultTable.truncate()
+}
+
+// Returns the equivalent match result, for calculating score summaries.
+func (sandboxMatchResult *SandboxMatchResult) MatchResult() *MatchResult {
+	return &MatchResult{
+		MatchId:   sandboxMatchResult.MatchId,
+		MatchType: Practice,
+		RedScore:  sandboxMatchResult.RedScore,
+		BlueScore: sandboxMatchResult.BlueScore,
+		RedCards:  sandboxMatchResult.RedCards,
+		BlueCards: sandboxMatchResult.BlueCards,
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSandboxMatchResultCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	sandboxMatchResults, err := db.GetAllSandboxMatchResults()
+	assert.Nil(t, err)
+	assert.Empty(t, sandboxMatchResults)
+
+	now := time.Now()
+	matchResult := BuildTestMatchResult(254, 1)
+	sandboxMatchResult1 := SandboxMatchResult{
+		MatchId:     254,
+		MatchName:   "P1",
+		CommittedAt: now.Add(time.Minute),
+		RedScore:    matchResult.RedScore,
+		BlueScore:   matchResult.BlueScore,
+		RedCards:    map[string]string{"1868": "yellow"},
+	}
+	sandboxMatchResult2 := SandboxMatchResult{MatchId: 254, MatchName: "P1", CommittedAt: now}
+	assert.Nil(t, db.CreateSandboxMatchResult(&sandboxMatchResult1))
+	assert.Nil(t, db.CreateSandboxMatchResult(&sandboxMatchResult2))
+
+	sandboxMatchResults, err = db.GetAllSandboxMatchResults()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(sandboxMatchResults)) {
+		assert.Equal(t, sandboxMatchResult2.Id, sandboxMatchResults[0].Id)
+		assert.Equal(t, sandboxMatchResult1.Id, sandboxMatchResults[1].Id)
+		assert.Equal(t, *matchResult.RedScore, *sandboxMatchResults[1].RedScore)
+		assert.Equal(t, sandboxMatchResult1.RedCards, sandboxMatchResults[1].RedCards)
+		assert.Equal(
+			t,
+			matchResult.RedScoreSummary().Score,
+			sandboxMatchResults[1].MatchResult().RedScoreSummary().Score,
+		)
+	}
+
+	// Sandbox results are never visible as real match results.
+	realMatchResult, err := db.GetMatchResultForMatch(254)
+	assert.Nil(t, err)
+	assert.Nil(t, realMatchResult)
+
+	assert.Nil(t, db.TruncateSandboxMatchResults())
+	sandboxMatchResults, _ = db.GetAllSandboxMatchResults()
+	assert.Empty(t, sandboxMatchResults)
+}
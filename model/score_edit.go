@@ -0,0 +1,200 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the audit trail of edits made to match scores.
+
+package model
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type ScoreEdit struct {
+	Id         int `db:"id"`
+	MatchId    int
+	PlayNumber int
+	EditedBy   string
+	EditedAt   time.Time
+	Changes    []ScoreEditChange
+}
+
+// Represents a single field of the score or its breakdown that was changed by an edit.
+type ScoreEditChange struct {
+	Alliance string
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+func (database *Database) CreateScoreEdit(scoreEdit *ScoreEdit) error {
+	return database.scoreEditTable.create(scoreEdit)
+}
+
+// Returns all edits made to the given match's score, in chronological order.
+func (database *Database) GetScoreEditsForMatch(matchId int) ([]ScoreEdit, error) {
+	scoreEdits, err := database.GetAllScoreEdits()
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingScoreEdits []ScoreEdit
+	for _, scoreEdit := range scoreEdits {
+		if scoreEdit.MatchId == matchId {
+			matchingScoreEdits = append(matchingScoreEdits, scoreEdit)
+		}
+	}
+	return matchingScoreEdits, nil
+}
+
+// Returns all score edits, in chronological order.
+func (database *Database) GetAllScoreEdits() ([]ScoreEdit, error) {
+	scoreEdits, err := database.scoreEditTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(scoreEdits, func(i, j int) bool {
+		return scoreEdits[i].EditedAt.Before(scoreEdits[j].EditedAt)
+	})
+	return scoreEdits, nil
+}
+
+func (database *Database) DeleteScoreEdit(id int) error {
+	return database.scoreEditTable.delete(id)
+}
+
+func (database *Database) TruncateScoreEdits() error {
+	return database.scoreEditTable.truncate()
+}
+
+// Returns the list of differences between the two match results, covering both the raw score fields and the
+// resulting score breakdown.
+func DiffMatchResults(oldResult, newResult *MatchResult) []ScoreEditChange {
+	var changes []ScoreEditChange
+	diffAlliance := func(alliance string, oldScore, newScore *game.Score, oldSummary, newSummary *game.ScoreSummary,
+		oldCards, newCards map[string]string) {
+		var fieldChanges [][3]string
+		diffValues("", reflect.ValueOf(*oldScore), reflect.ValueOf(*newScore), &fieldChanges)
+		diffValues("Cards", reflect.ValueOf(oldCards), reflect.ValueOf(newCards), &fieldChanges)
+		diffValues("Summary", reflect.ValueOf(*oldSummary), reflect.ValueOf(*newSummary), &fieldChanges)
+		for _, fieldChange := range fieldChanges {
+			changes = append(changes, ScoreEditChange{alliance, fieldChange[0], fieldChange[1], fieldChange[2]})
+		}
+	}
+	diffAlliance(
+		"red",
+		oldResult.RedScore,
+		newResult.RedScore,
+		oldResult.RedScoreSummary(),
+		newResult.RedScoreSummary(),
+		oldResult.RedCards,
+		newResult.RedCards,
+	)
+	diffAlliance(
+		"blue",
+		oldResult.BlueScore,
+		newResult.BlueScore,
+		oldResult.BlueScoreSummary(),
+		newResult.BlueScoreSummary(),
+		oldResult.BlueCards,
+		newResult.BlueCards,
+	)
+	return changes
+}
+
+// Recursively compares the two values and appends the name, old value, and new value of each differing field.
+func diffValues(name string, oldValue, newValue reflect.Value, changes *[][3]string) {
+	joinName := func(suffix string) string {
+		if name == "" {
+			return suffix
+		}
+		return name + "." + suffix
+	}
+
+	switch oldValue.Kind() {
+	case reflect.Struct:
+		if foul, ok := oldValue.Interface().(game.Foul); ok {
+			oldDescription := describeFoul(foul)
+			newDescription := describeFoul(newValue.Interface().(game.Foul))
+			if oldDescription != newDescription {
+				*changes = append(*changes, [3]string{name, oldDescription, newDescription})
+			}
+			return
+		}
+		for i := 0; i < oldValue.NumField(); i++ {
+			if field := oldValue.Type().Field(i); field.IsExported() {
+				diffValues(joinName(field.Name), oldValue.Field(i), newValue.Field(i), changes)
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		length := max(oldValue.Len(), newValue.Len())
+		for i := 0; i < length; i++ {
+			elementName := fmt.Sprintf("%s[%d]", name, i)
+			if i >= oldValue.Len() {
+				*changes = append(*changes, [3]string{elementName, "", formatValue(newValue.Index(i))})
+			} else if i >= newValue.Len() {
+				*changes = append(*changes, [3]string{elementName, formatValue(oldValue.Index(i)), ""})
+			} else {
+				diffValues(elementName, oldValue.Index(i), newValue.Index(i), changes)
+			}
+		}
+	case reflect.Map:
+		keys := make(map[string]bool)
+		for _, key := range oldValue.MapKeys() {
+			keys[key.String()] = true
+		}
+		for _, key := range newValue.MapKeys() {
+			keys[key.String()] = true
+		}
+		var sortedKeys []string
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+		for _, key := range sortedKeys {
+			oldElement := oldValue.MapIndex(reflect.ValueOf(key))
+			newElement := newValue.MapIndex(reflect.ValueOf(key))
+			oldString, newString := "", ""
+			if oldElement.IsValid() {
+				oldString = formatValue(oldElement)
+			}
+			if newElement.IsValid() {
+				newString = formatValue(newElement)
+			}
+			if oldString != newString {
+				*changes = append(*changes, [3]string{fmt.Sprintf("%s[%s]", name, key), oldString, newString})
+			}
+		}
+	default:
+		oldString := formatValue(oldValue)
+		newString := formatValue(newValue)
+		if oldString != newString {
+			*changes = append(*changes, [3]string{name, oldString, newString})
+		}
+	}
+}
+
+func formatValue(value reflect.Value) string {
+	if foul, ok := value.Interface().(game.Foul); ok {
+		return describeFoul(foul)
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Returns a human-readable description of the foul, leaving out the bookkeeping fields used during the match.
+func describeFoul(foul game.Foul) string {
+	description := "Foul"
+	if foul.IsTechnical {
+		description = "Tech Foul"
+	}
+	if foul.TeamId > 0 {
+		description += fmt.Sprintf(" by %d", foul.TeamId)
+	}
+	if rule := foul.Rule(); rule != nil {
+		description += " for " + rule.RuleNumber
+	}
+	return description
+}
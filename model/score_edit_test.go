@@ -0,0 +1,73 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestScoreEditCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	scoreEdits, err := db.GetAllScoreEdits()
+	assert.Nil(t, err)
+	assert.Empty(t, scoreEdits)
+
+	now := time.Now()
+	scoreEdit1 := ScoreEdit{
+		MatchId:  254,
+		EditedBy: "admin",
+		EditedAt: now.Add(time.Minute),
+		Changes:  []ScoreEditChange{{"red", "Summary.Score", "10", "12"}},
+	}
+	scoreEdit2 := ScoreEdit{MatchId: 1114, EditedBy: "admin", EditedAt: now}
+	scoreEdit3 := ScoreEdit{MatchId: 254, EditedBy: "10.0.100.5", EditedAt: now.Add(2 * time.Minute)}
+	assert.Nil(t, db.CreateScoreEdit(&scoreEdit1))
+	assert.Nil(t, db.CreateScoreEdit(&scoreEdit2))
+	assert.Nil(t, db.CreateScoreEdit(&scoreEdit3))
+
+	scoreEdits, err = db.GetAllScoreEdits()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(scoreEdits)) {
+		assert.Equal(t, scoreEdit2.Id, scoreEdits[0].Id)
+		assert.Equal(t, scoreEdit1.Id, scoreEdits[1].Id)
+		assert.Equal(t, scoreEdit3.Id, scoreEdits[2].Id)
+		assert.Equal(t, scoreEdit1.Changes, scoreEdits[1].Changes)
+	}
+	scoreEdits, err = db.GetScoreEditsForMatch(254)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(scoreEdits)) {
+		assert.Equal(t, "admin", scoreEdits[0].EditedBy)
+		assert.Equal(t, "10.0.100.5", scoreEdits[1].EditedBy)
+	}
+
+	assert.Nil(t, db.DeleteScoreEdit(scoreEdit1.Id))
+	scoreEdits, _ = db.GetScoreEditsForMatch(254)
+	assert.Equal(t, 1, len(scoreEdits))
+	assert.Nil(t, db.TruncateScoreEdits())
+	scoreEdits, _ = db.GetAllScoreEdits()
+	assert.Empty(t, scoreEdits)
+}
+
+func TestDiffMatchResults(t *testing.T) {
+	oldResult := BuildTestMatchResult(1, 1)
+	assert.Empty(t, DiffMatchResults(oldResult, BuildTestMatchResult(1, 1)))
+
+	newResult := BuildTestMatchResult(1, 2)
+	newResult.RedScore.LeaveStatuses[2] = true
+	newResult.BlueScore.Fouls = append(newResult.BlueScore.Fouls, game.Foul{IsTechnical: true, TeamId: 148, RuleId: 1})
+	newResult.BlueCards = map[string]string{"1114": "yellow"}
+	changes := DiffMatchResults(oldResult, newResult)
+	assert.Contains(t, changes, ScoreEditChange{"red", "LeaveStatuses[2]", "false", "true"})
+	assert.Contains(t, changes, ScoreEditChange{"red", "Summary.LeavePoints", "4", "6"})
+	assert.Contains(t, changes, ScoreEditChange{"red", "Summary.FoulPoints", "0", "5"})
+	assert.Contains(t, changes, ScoreEditChange{"blue", "Fouls[0]", "", "Tech Foul by 148 for G211"})
+	assert.Contains(t, changes, ScoreEditChange{"blue", "Cards[1114]", "", "yellow"})
+	for _, change := range changes {
+		assert.NotEqual(t, change.OldValue, change.NewValue)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for an external scouting app registered to use the scouting API.
+
+package model
+
+import "sort"
+
+type ScoutingApp struct {
+	Id     int `db:"id"`
+	Name   string
+	ApiKey string
+}
+
+func (database *Database) CreateScoutingApp(scoutingApp *ScoutingApp) error {
+	return database.scoutingAppTable.create(scoutingApp)
+}
+
+func (database *Database) GetScoutingAppById(id int) (*ScoutingApp, error) {
+	return database.scoutingAppTable.getById(id)
+}
+
+func (database *Database) GetScoutingAppByApiKey(apiKey string) (*ScoutingApp, error) {
+	scoutingApps, err := database.scoutingAppTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scoutingApp := range scoutingApps {
+		if scoutingApp.ApiKey == apiKey {
+			return &scoutingApp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (database *Database) DeleteScoutingApp(id int) error {
+	return database.scoutingAppTable.delete(id)
+}
+
+func (database *Database) TruncateScoutingApps() error {
+	return database.scoutingAppTable.truncate()
+}
+
+func (database *Database) GetAllScoutingApps() ([]ScoutingApp, error) {
+	scoutingApps, err := database.scoutingAppTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(scoutingApps, func(i, j int) bool {
+		return scoutingApps[i].Id < scoutingApps[j].Id
+	})
+	return scoutingApps, nil
+}
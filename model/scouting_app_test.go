@@ -0,0 +1,49 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoutingAppCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	scoutingApp := ScoutingApp{Name: "Tablets", ApiKey: "abc123"}
+	assert.Nil(t, db.CreateScoutingApp(&scoutingApp))
+	scoutingApp2, err := db.GetScoutingAppById(scoutingApp.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, scoutingApp, *scoutingApp2)
+
+	scoutingApp2, err = db.GetScoutingAppByApiKey("abc123")
+	assert.Nil(t, err)
+	assert.Equal(t, scoutingApp, *scoutingApp2)
+	scoutingApp2, err = db.GetScoutingAppByApiKey("blorpy")
+	assert.Nil(t, err)
+	assert.Nil(t, scoutingApp2)
+
+	assert.Nil(t, db.DeleteScoutingApp(scoutingApp.Id))
+	scoutingApp2, err = db.GetScoutingAppById(scoutingApp.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, scoutingApp2)
+}
+
+func TestTruncateScoutingApps(t *testing.T) {
+	db := setupTestDb(t)
+
+	db.CreateScoutingApp(&ScoutingApp{Name: "Tablets", ApiKey: "abc123"})
+	db.CreateScoutingApp(&ScoutingApp{Name: "Phones", ApiKey: "def456"})
+	scoutingApps, err := db.GetAllScoutingApps()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(scoutingApps)) {
+		assert.Equal(t, "Tablets", scoutingApps[0].Name)
+		assert.Equal(t, "Phones", scoutingApps[1].Name)
+	}
+
+	assert.Nil(t, db.TruncateScoutingApps())
+	scoutingApps, err = db.GetAllScoutingApps()
+	assert.Nil(t, err)
+	assert.Empty(t, scoutingApps)
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a scout's observations of a team in a match.
+
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type ScoutingObservation struct {
+	Id            int `db:"id"`
+	ScoutingAppId int
+	MatchId       int
+	TeamId        int
+	ScoutName     string
+	SubmittedAt   time.Time
+	Values        map[string]int // Numeric observations keyed by metric name, as defined by the scouting app.
+	Notes         string
+}
+
+func (database *Database) CreateScoutingObservation(observation *ScoutingObservation) error {
+	return database.scoutingObservationTable.create(observation)
+}
+
+func (database *Database) UpdateScoutingObservation(observation *ScoutingObservation) error {
+	return database.scoutingObservationTable.update(observation)
+}
+
+func (database *Database) DeleteScoutingObservation(id int) error {
+	return database.scoutingObservationTable.delete(id)
+}
+
+func (database *Database) TruncateScoutingObservations() error {
+	return database.scoutingObservationTable.truncate()
+}
+
+// Returns all scouting observations, ordered by the time at which they were submitted.
+func (database *Database) GetAllScoutingObservations() ([]ScoutingObservation, error) {
+	observations, err := database.scoutingObservationTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].SubmittedAt.Before(observations[j].SubmittedAt)
+	})
+	return observations, nil
+}
+
+// Returns all scouting observations for the given match, ordered by the time at which they were submitted.
+func (database *Database) GetScoutingObservationsForMatch(matchId int) ([]ScoutingObservation, error) {
+	observations, err := database.GetAllScoutingObservations()
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingObservations []ScoutingObservation
+	for _, observation := range observations {
+		if observation.MatchId == matchId {
+			matchingObservations = append(matchingObservations, observation)
+		}
+	}
+	return matchingObservations, nil
+}
+
+// Returns the observation previously submitted by the given app and scout for the given team and match, or nil if
+// there is none.
+func (database *Database) GetScoutingObservation(
+	scoutingAppId, matchId, teamId int, scoutName string,
+) (*ScoutingObservation, error) {
+	observations, err := database.scoutingObservationTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, observation := range observations {
+		if observation.ScoutingAppId == scoutingAppId && observation.MatchId == matchId &&
+			observation.TeamId == teamId && observation.ScoutName == scoutName {
+			return &observation, nil
+		}
+	}
+	return nil, nil
+}
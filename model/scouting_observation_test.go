@@ -0,0 +1,68 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestScoutingObservationCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	now := time.Now().UTC()
+	observation1 := ScoutingObservation{
+		ScoutingAppId: 1,
+		MatchId:       12,
+		TeamId:        254,
+		ScoutName:     "Alex",
+		SubmittedAt:   now.Add(time.Minute),
+		Values:        map[string]int{"notesScored": 7},
+		Notes:         "Fast cycles",
+	}
+	observation2 := ScoutingObservation{ScoutingAppId: 1, MatchId: 13, TeamId: 254, ScoutName: "Alex", SubmittedAt: now}
+	observation3 := ScoutingObservation{ScoutingAppId: 2, MatchId: 12, TeamId: 254, ScoutName: "Alex", SubmittedAt: now}
+	assert.Nil(t, db.CreateScoutingObservation(&observation1))
+	assert.Nil(t, db.CreateScoutingObservation(&observation2))
+	assert.Nil(t, db.CreateScoutingObservation(&observation3))
+
+	observations, err := db.GetAllScoutingObservations()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(observations)) {
+		assert.Equal(t, observation2.Id, observations[0].Id)
+		assert.Equal(t, observation3.Id, observations[1].Id)
+		assert.Equal(t, observation1.Id, observations[2].Id)
+		assert.Equal(t, observation1.Values, observations[2].Values)
+	}
+
+	observations, err = db.GetScoutingObservationsForMatch(12)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(observations)) {
+		assert.Equal(t, observation3.Id, observations[0].Id)
+		assert.Equal(t, observation1.Id, observations[1].Id)
+	}
+
+	observation, err := db.GetScoutingObservation(1, 12, 254, "Alex")
+	assert.Nil(t, err)
+	if assert.NotNil(t, observation) {
+		assert.Equal(t, observation1.Id, observation.Id)
+	}
+	observation, err = db.GetScoutingObservation(1, 12, 254, "Sam")
+	assert.Nil(t, err)
+	assert.Nil(t, observation)
+
+	observation1.Notes = "Slow cycles"
+	assert.Nil(t, db.UpdateScoutingObservation(&observation1))
+	observation, _ = db.GetScoutingObservation(1, 12, 254, "Alex")
+	assert.Equal(t, "Slow cycles", observation.Notes)
+
+	assert.Nil(t, db.DeleteScoutingObservation(observation1.Id))
+	observations, _ = db.GetScoutingObservationsForMatch(12)
+	assert.Equal(t, 1, len(observations))
+
+	assert.Nil(t, db.TruncateScoutingObservations())
+	observations, _ = db.GetAllScoutingObservations()
+	assert.Empty(t, observations)
+}
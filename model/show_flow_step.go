@@ -0,0 +1,95 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a step in the producer's sequence of audience display screens.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Audience display screens that can be used as show flow steps, along with their human-readable names.
+var ShowFlowScreenNames = map[string]string{
+	"blank":             "Blank",
+	"intro":             "Match Intro",
+	"match":             "Match Play",
+	"score":             "Final Score",
+	"bracket":           "Bracket",
+	"logo":              "Logo With BG",
+	"logoLuma":          "Logo Without BG",
+	"sponsor":           "Sponsor Reel",
+	"allianceSelection": "Alliance Selection",
+	"timeout":           "Timeout",
+}
+
+type ShowFlowStep struct {
+	Id             int `db:"id"`
+	Screen         string
+	AutoAdvanceSec int // Hold time after which the flow moves on to the next step by itself; zero waits for the producer.
+	DisplayOrder   int
+}
+
+func (database *Database) CreateShowFlowStep(showFlowStep *ShowFlowStep) error {
+	if err := showFlowStep.validate(); err != nil {
+		return err
+	}
+	return database.showFlowStepTable.create(showFlowStep)
+}
+
+func (database *Database) GetShowFlowStepById(id int) (*ShowFlowStep, error) {
+	return database.showFlowStepTable.getById(id)
+}
+
+func (database *Database) UpdateShowFlowStep(showFlowStep *ShowFlowStep) error {
+	if err := showFlowStep.validate(); err != nil {
+		return err
+	}
+	return database.showFlowStepTable.update(showFlowStep)
+}
+
+func (database *Database) DeleteShowFlowStep(id int) error {
+	return database.showFlowStepTable.delete(id)
+}
+
+func (database *Database) TruncateShowFlowSteps() error {
+	return database.showFlowStepTable.truncate()
+}
+
+func (database *Database) GetAllShowFlowSteps() ([]ShowFlowStep, error) {
+	showFlowSteps, err := database.showFlowStepTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(showFlowSteps, func(i, j int) bool {
+		return showFlowSteps[i].DisplayOrder < showFlowSteps[j].DisplayOrder
+	})
+	return showFlowSteps, nil
+}
+
+func (database *Database) GetNextShowFlowStepDisplayOrder() int {
+	showFlowSteps, err := database.GetAllShowFlowSteps()
+	if err != nil {
+		return 0
+	}
+	if len(showFlowSteps) == 0 {
+		return 1
+	}
+	return showFlowSteps[len(showFlowSteps)-1].DisplayOrder + 1
+}
+
+// Returns the human-readable name of the step's audience display screen.
+func (showFlowStep *ShowFlowStep) ScreenName() string {
+	return ShowFlowScreenNames[showFlowStep.Screen]
+}
+
+func (showFlowStep *ShowFlowStep) validate() error {
+	if _, ok := ShowFlowScreenNames[showFlowStep.Screen]; !ok {
+		return fmt.Errorf("invalid audience display screen '%s'", showFlowStep.Screen)
+	}
+	if showFlowStep.AutoAdvanceSec < 0 {
+		return fmt.Errorf("auto-advance time must not be negative")
+	}
+	return nil
+}
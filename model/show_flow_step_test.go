@@ -0,0 +1,48 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestShowFlowStepCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	showFlowStep, err := db.GetShowFlowStepById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, showFlowStep)
+	assert.Equal(t, 1, db.GetNextShowFlowStepDisplayOrder())
+
+	showFlowStep1 := ShowFlowStep{Screen: "score", DisplayOrder: 2}
+	assert.Nil(t, db.CreateShowFlowStep(&showFlowStep1))
+	showFlowStep2 := ShowFlowStep{Screen: "intro", DisplayOrder: 1}
+	assert.Nil(t, db.CreateShowFlowStep(&showFlowStep2))
+	assert.NotNil(t, db.CreateShowFlowStep(&ShowFlowStep{Screen: "rankings"}))
+	assert.NotNil(t, db.CreateShowFlowStep(&ShowFlowStep{Screen: "sponsor", AutoAdvanceSec: -1}))
+
+	showFlowSteps, err := db.GetAllShowFlowSteps()
+	assert.Nil(t, err)
+	assert.Equal(t, []ShowFlowStep{showFlowStep2, showFlowStep1}, showFlowSteps)
+	assert.Equal(t, "Match Intro", showFlowSteps[0].ScreenName())
+	assert.Equal(t, 3, db.GetNextShowFlowStepDisplayOrder())
+
+	showFlowStep1.AutoAdvanceSec = 10
+	assert.Nil(t, db.UpdateShowFlowStep(&showFlowStep1))
+	showFlowStep, err = db.GetShowFlowStepById(showFlowStep1.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, showFlowStep1, *showFlowStep)
+
+	assert.Nil(t, db.DeleteShowFlowStep(showFlowStep1.Id))
+	showFlowStep, err = db.GetShowFlowStepById(showFlowStep1.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, showFlowStep)
+
+	assert.Nil(t, db.TruncateShowFlowSteps())
+	showFlowSteps, err = db.GetAllShowFlowSteps()
+	assert.Nil(t, err)
+	assert.Empty(t, showFlowSteps)
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a pack of alternate match sounds uploaded for an event.
+
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+type SoundPack struct {
+	Id     int `db:"id"`
+	Name   string
+	Sounds map[string][]byte // WAV file contents, keyed by the name of the match sound they replace.
+}
+
+func (database *Database) CreateSoundPack(soundPack *SoundPack) error {
+	return database.soundPackTable.create(soundPack)
+}
+
+func (database *Database) GetSoundPackById(id int) (*SoundPack, error) {
+	return database.soundPackTable.getById(id)
+}
+
+func (database *Database) UpdateSoundPack(soundPack *SoundPack) error {
+	return database.soundPackTable.update(soundPack)
+}
+
+func (database *Database) DeleteSoundPack(id int) error {
+	return database.soundPackTable.delete(id)
+}
+
+// Returns all sound packs, in alphabetical order by name.
+func (database *Database) GetAllSoundPacks() ([]SoundPack, error) {
+	soundPacks, err := database.soundPackTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(soundPacks, func(i, j int) bool {
+		return strings.ToLower(soundPacks[i].Name) < strings.ToLower(soundPacks[j].Name)
+	})
+	return soundPacks, nil
+}
+
+// Returns the names of the sounds that the pack replaces, in alphabetical order.
+func (soundPack *SoundPack) SoundNames() []string {
+	names := make([]string, 0, len(soundPack.Sounds))
+	for name := range soundPack.Sounds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentSoundPack(t *testing.T) {
+	db := setupTestDb(t)
+
+	soundPack, err := db.GetSoundPackById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, soundPack)
+}
+
+func TestSoundPackCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	soundPack := SoundPack{Name: "Retro", Sounds: map[string][]byte{"start": []byte("RIFF1"), "end": []byte("RIFF2")}}
+	assert.Nil(t, db.CreateSoundPack(&soundPack))
+	soundPack2, err := db.GetSoundPackById(soundPack.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, soundPack, *soundPack2)
+	assert.Equal(t, []string{"end", "start"}, soundPack2.SoundNames())
+
+	soundPack.Sounds["abort"] = []byte("RIFF3")
+	assert.Nil(t, db.UpdateSoundPack(&soundPack))
+	soundPack2, err = db.GetSoundPackById(soundPack.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("RIFF3"), soundPack2.Sounds["abort"])
+
+	assert.Nil(t, db.CreateSoundPack(&SoundPack{Name: "beeps"}))
+	soundPacks, err := db.GetAllSoundPacks()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(soundPacks)) {
+		assert.Equal(t, "beeps", soundPacks[0].Name)
+		assert.Equal(t, "Retro", soundPacks[1].Name)
+	}
+
+	assert.Nil(t, db.DeleteSoundPack(soundPack.Id))
+	soundPack2, err = db.GetSoundPackById(soundPack.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, soundPack2)
+}
@@ -5,19 +5,32 @@
 
 package model
 
-import "sort"
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File extensions of uploaded media that are played as video rather than shown as a still image.
+var videoExtensions = []string{".mp4", ".m4v", ".mov", ".webm"}
 
 type SponsorSlide struct {
-	Id             int `db:"id"`
-	Subtitle       string
-	Line1          string
-	Line2          string
-	Image          string
-	DisplayTimeSec int
-	DisplayOrder   int
+	Id              int `db:"id"`
+	Subtitle        string
+	Line1           string
+	Line2           string
+	Image           string
+	DisplayTimeSec  int
+	DisplayOrder    int
+	ActiveStartTime time.Time // The slide is left out of the rotation before this time, unless it is zero.
+	ActiveEndTime   time.Time // The slide is left out of the rotation from this time on, unless it is zero.
+	IsVideo         bool
 }
 
 func (database *Database) CreateSponsorSlide(sponsorSlide *SponsorSlide) error {
+	sponsorSlide.IsVideo = IsVideoFile(sponsorSlide.Image)
 	return database.sponsorSlideTable.create(sponsorSlide)
 }
 
@@ -26,6 +39,7 @@ func (database *Database) GetSponsorSlideById(id int) (*SponsorSlide, error) {
 }
 
 func (database *Database) UpdateSponsorSlide(sponsorSlide *SponsorSlide) error {
+	sponsorSlide.IsVideo = IsVideoFile(sponsorSlide.Image)
 	return database.sponsorSlideTable.update(sponsorSlide)
 }
 
@@ -58,3 +72,34 @@ func (database *Database) GetNextSponsorSlideDisplayOrder() int {
 	}
 	return sponsorSlides[len(sponsorSlides)-1].DisplayOrder + 1
 }
+
+// Returns the slides that should be in the rotation at the given time, in display order.
+func (database *Database) GetActiveSponsorSlides(now time.Time) ([]SponsorSlide, error) {
+	sponsorSlides, err := database.GetAllSponsorSlides()
+	if err != nil {
+		return nil, err
+	}
+	var activeSponsorSlides []SponsorSlide
+	for _, sponsorSlide := range sponsorSlides {
+		if sponsorSlide.IsActive(now) {
+			activeSponsorSlides = append(activeSponsorSlides, sponsorSlide)
+		}
+	}
+	return activeSponsorSlides, nil
+}
+
+// Returns true if the given time falls within the slide's active window.
+func (sponsorSlide *SponsorSlide) IsActive(now time.Time) bool {
+	if !sponsorSlide.ActiveStartTime.IsZero() && now.Before(sponsorSlide.ActiveStartTime) {
+		return false
+	}
+	if !sponsorSlide.ActiveEndTime.IsZero() && !now.Before(sponsorSlide.ActiveEndTime) {
+		return false
+	}
+	return true
+}
+
+// Returns true if the given media file is a video, based on its extension.
+func IsVideoFile(fileName string) bool {
+	return slices.Contains(videoExtensions, strings.ToLower(filepath.Ext(fileName)))
+}
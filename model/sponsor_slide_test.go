@@ -6,6 +6,7 @@ package model
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetNonexistentSponsorSlide(t *testing.T) {
@@ -23,7 +24,7 @@ func TestSponsorSlideCrud(t *testing.T) {
 
 	assert.Equal(t, 1, db.GetNextSponsorSlideDisplayOrder())
 
-	sponsorSlide := SponsorSlide{0, "Subtitle", "Line 1", "Line 2", "", 10, 1}
+	sponsorSlide := SponsorSlide{0, "Subtitle", "Line 1", "Line 2", "", 10, 1, time.Time{}, time.Time{}, false}
 	assert.Nil(t, db.CreateSponsorSlide(&sponsorSlide))
 	sponsorSlide2, err := db.GetSponsorSlideById(1)
 	assert.Nil(t, err)
@@ -46,7 +47,7 @@ func TestTruncateSponsorSlides(t *testing.T) {
 	db := setupTestDb(t)
 	defer db.Close()
 
-	sponsorSlide := SponsorSlide{0, "Subtitle", "Line 1", "Line 2", "", 10, 0}
+	sponsorSlide := SponsorSlide{0, "Subtitle", "Line 1", "Line 2", "", 10, 0, time.Time{}, time.Time{}, false}
 	db.CreateSponsorSlide(&sponsorSlide)
 	db.TruncateSponsorSlides()
 	sponsorSlide2, err := db.GetSponsorSlideById(1)
@@ -54,3 +55,41 @@ func TestTruncateSponsorSlides(t *testing.T) {
 	assert.Nil(t, sponsorSlide2)
 	assert.Equal(t, 1, db.GetNextSponsorSlideDisplayOrder())
 }
+
+func TestGetActiveSponsorSlides(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	now := time.Date(2024, 4, 6, 12, 0, 0, 0, time.Local)
+	sponsorSlide1 := SponsorSlide{Image: "always.png", DisplayOrder: 1}
+	sponsorSlide2 := SponsorSlide{Image: "lunch.mp4", DisplayOrder: 2, ActiveStartTime: now.Add(-time.Hour),
+		ActiveEndTime: now.Add(time.Hour)}
+	sponsorSlide3 := SponsorSlide{Image: "playoffs.png", DisplayOrder: 3, ActiveStartTime: now.Add(time.Hour)}
+	sponsorSlide4 := SponsorSlide{Image: "morning.WEBM", DisplayOrder: 4, ActiveEndTime: now}
+	assert.Nil(t, db.CreateSponsorSlide(&sponsorSlide1))
+	assert.Nil(t, db.CreateSponsorSlide(&sponsorSlide2))
+	assert.Nil(t, db.CreateSponsorSlide(&sponsorSlide3))
+	assert.Nil(t, db.CreateSponsorSlide(&sponsorSlide4))
+	assert.False(t, sponsorSlide1.IsVideo)
+	assert.True(t, sponsorSlide2.IsVideo)
+	assert.True(t, sponsorSlide4.IsVideo)
+
+	sponsorSlides, err := db.GetActiveSponsorSlides(now)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(sponsorSlides)) {
+		assert.Equal(t, "always.png", sponsorSlides[0].Image)
+		assert.Equal(t, "lunch.mp4", sponsorSlides[1].Image)
+	}
+	sponsorSlides, err = db.GetActiveSponsorSlides(now.Add(-2 * time.Hour))
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(sponsorSlides)) {
+		assert.Equal(t, "always.png", sponsorSlides[0].Image)
+		assert.Equal(t, "morning.WEBM", sponsorSlides[1].Image)
+	}
+	sponsorSlides, err = db.GetActiveSponsorSlides(now.Add(2 * time.Hour))
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(sponsorSlides)) {
+		assert.Equal(t, "always.png", sponsorSlides[0].Image)
+		assert.Equal(t, "playoffs.png", sponsorSlides[1].Image)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Interface for the storage backends that persist the JSON-serialized records of each table.
+
+package model
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	errRecordExists   = errors.New("record already exists")
+	errRecordNotFound = errors.New("record does not exist")
+)
+
+// Stores records as JSON, grouped into named tables and keyed by integer ID.
+type store interface {
+	// Creates the table with the given name if it doesn't already exist.
+	createTable(tableName string) error
+
+	// Returns the names of all the tables that exist.
+	tableNames() ([]string, error)
+
+	// Returns the JSON of the record with the given ID, or nil if it doesn't exist.
+	get(tableName string, id int) ([]byte, error)
+
+	// Calls the given function with the JSON of each record in the table, ordered by string representation of ID.
+	forEach(tableName string, handleRecord func(id int, recordJson []byte) error) error
+
+	// Returns a newly generated ID for a record in the given table. IDs are never reused until the table is truncated.
+	nextId(tableName string) (int, error)
+
+	// Adds a record with the given ID to the table, returning errRecordExists if it is already taken.
+	insert(tableName string, id int, recordJson []byte) error
+
+	// Replaces the record with the given ID, returning errRecordNotFound if it doesn't exist.
+	update(tableName string, id int, recordJson []byte) error
+
+	// Removes the record with the given ID, returning errRecordNotFound if it doesn't exist.
+	delete(tableName string, id int) error
+
+	// Removes all records from the table and resets its ID generation.
+	truncate(tableName string) error
+
+	// Calls the given function with a store whose operations all belong to a single transaction, which is committed if
+	// the function returns nil and rolled back otherwise. Calling this on a transaction's store joins that transaction.
+	transaction(handleTransaction func(txStore store) error) error
+
+	// Writes a consistent snapshot of all the data to the given writer in the form of a Bolt database file, which can
+	// be opened as an embedded database.
+	writeSnapshot(writer io.Writer) error
+
+	close() error
+}
+
+// Returns true if the given database path refers to a Postgres server rather than to an embedded database file.
+func isPostgresUrl(path string) bool {
+	return strings.HasPrefix(path, "postgres://") || strings.HasPrefix(path, "postgresql://")
+}
+
+// Opens the appropriate type of store for the given database path.
+func openStore(path string) (store, error) {
+	if isPostgresUrl(path) {
+		return openPostgresStore(path)
+	}
+	return openBoltStore(path)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestOpenPostgresDatabaseWithoutDriver(t *testing.T) {
+	if slices.Contains(sql.Drivers(), postgresDriverName) {
+		t.Skip("Postgres driver is linked in")
+	}
+
+	assert.True(t, isPostgresUrl("postgres://arena@localhost/events"))
+	assert.True(t, isPostgresUrl("postgresql://arena@localhost/events"))
+	assert.False(t, isPostgresUrl("event.db"))
+	_, err := OpenDatabase("postgres://arena@localhost/events")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "rebuild it with '-tags postgres'")
+	}
+}
+
+func TestWriteBoltSnapshot(t *testing.T) {
+	database := setupTestDb(t)
+	assert.True(t, database.IsEmbedded())
+	assert.Nil(t, database.CreateTeam(&Team{Id: 254, Nickname: "The Cheesy Poofs"}))
+	assert.Nil(t, database.CreateAward(&Award{AwardName: "Winner"}))
+	assert.Nil(t, database.CreateAward(&Award{AwardName: "Finalist"}))
+
+	// Take the snapshot by way of copying each table, as is done for stores that aren't already Bolt files.
+	var snapshot bytes.Buffer
+	assert.Nil(t, writeBoltSnapshot(database.store, &snapshot))
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+	assert.Nil(t, os.WriteFile(snapshotPath, snapshot.Bytes(), 0644))
+	snapshotDatabase, err := OpenDatabase(snapshotPath)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer snapshotDatabase.Close()
+
+	team, _ := snapshotDatabase.GetTeamById(254)
+	if assert.NotNil(t, team) {
+		assert.Equal(t, "The Cheesy Poofs", team.Nickname)
+	}
+	version, _ := snapshotDatabase.GetSchemaVersion()
+	assert.Equal(t, len(migrations), version)
+	award := Award{AwardName: "Volunteer of the Year"}
+	assert.Nil(t, snapshotDatabase.CreateAward(&award))
+	assert.Equal(t, 3, award.Id)
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, `"Team"`, quoteIdentifier("Team"))
+	assert.Equal(t, `"Team""; DROP TABLE x"`, quoteIdentifier(`Team"; DROP TABLE x`))
+	assert.Equal(t, "Team_id_seq", sequenceName("Team"))
+}
@@ -1,27 +1,33 @@
 // Copyright 2021 Team 254. All Rights Reserved.
 // Author: pat@patfairbank.com (Patrick Fairbank)
 //
-// Defines a "table" wrapper struct and helper methods for persisting data using Bolt.
+// Defines a "table" wrapper struct and helper methods for persisting data using the database's store.
 
 package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"go.etcd.io/bbolt"
 	"reflect"
-	"strconv"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // Encapsulates all persistence operations for a particular data type represented by a struct.
 type table[R any] struct {
-	bolt         *bbolt.DB
+	store        store
 	recordType   reflect.Type
 	name         string
-	bucketKey    []byte
 	idFieldIndex *int
 	manualId     bool
+
+	// In-memory copy of all the records, for tables that are read far more often than they are written. It is nil
+	// when caching is disabled or when it has been invalidated by a write.
+	cacheEnabled bool
+	cache        []R
+	cacheMutex   sync.Mutex
 }
 
 // Registers a new table for a struct.
@@ -33,10 +39,9 @@ func newTable[R any](database *Database) (*table[R], error) {
 	}
 
 	var table table[R]
-	table.bolt = database.bolt
+	table.store = database.store
 	table.recordType = reflect.TypeOf(recordType)
 	table.name = table.recordType.Name()
-	table.bucketKey = []byte(table.name)
 
 	// Determine which field in the struct is tagged as the ID and cache its index.
 	idFound := false
@@ -64,12 +69,8 @@ func newTable[R any](database *Database) (*table[R], error) {
 		return nil, fmt.Errorf("struct %s has no field tagged as the id", table.name)
 	}
 
-	// Create the Bolt bucket corresponding to the struct.
-	err := table.bolt.Update(func(tx *bbolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists(table.bucketKey)
-		return err
-	})
-	if err != nil {
+	// Create the storage corresponding to the struct.
+	if err := table.store.createTable(table.name); err != nil {
 		return nil, err
 	}
 
@@ -78,46 +79,83 @@ func newTable[R any](database *Database) (*table[R], error) {
 
 // Returns the record with the given ID, or nil if it doesn't exist.
 func (table *table[R]) getById(id int) (*R, error) {
+	recordJson, err := table.store.get(table.name, id)
+	if err != nil || recordJson == nil {
+		return nil, err
+	}
 	record := new(R)
-	err := table.bolt.View(func(tx *bbolt.Tx) error {
-		bucket, err := table.getBucket(tx)
+	if err = json.Unmarshal(recordJson, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Turns on caching of the records returned by getAll(), which is then invalidated whenever the table is written.
+func (table *table[R]) enableCache() {
+	table.cacheMutex.Lock()
+	defer table.cacheMutex.Unlock()
+	table.cacheEnabled = true
+	table.cache = nil
+}
+
+// Returns a slice containing every record in the table, ordered by string representation of ID.
+func (table *table[R]) getAll() ([]R, error) {
+	if !table.cacheEnabled {
+		return table.loadAll()
+	}
+
+	// Hold the lock while loading so that a concurrent write can't be followed by stale data being cached.
+	table.cacheMutex.Lock()
+	defer table.cacheMutex.Unlock()
+	if table.cache == nil {
+		records, err := table.loadAll()
 		if err != nil {
-			return err
+			return nil, err
 		}
+		table.cache = records
+	}
 
-		if recordJson := bucket.Get(idToKey(id)); recordJson != nil {
-			return json.Unmarshal(recordJson, record)
-		}
+	// Return a copy so that callers are free to modify or reorder the records.
+	return slices.Clone(table.cache), nil
+}
 
-		// If the record does not exist, set the record pointer to nil.
-		record = nil
-		return nil
-	})
-	return record, err
+// Discards the cached records, if any, after the table has been written.
+func (table *table[R]) invalidateCache() {
+	if !table.cacheEnabled {
+		return
+	}
+	table.cacheMutex.Lock()
+	defer table.cacheMutex.Unlock()
+	table.cache = nil
 }
 
-// Returns a slice containing every record in the table, ordered by string representation of ID.
-func (table *table[R]) getAll() ([]R, error) {
+// Reads every record in the table from the store.
+func (table *table[R]) loadAll() ([]R, error) {
 	records := []R{}
-	err := table.bolt.View(func(tx *bbolt.Tx) error {
-		bucket, err := table.getBucket(tx)
+	err := table.store.forEach(table.name, func(id int, recordJson []byte) error {
+		var record R
+		err := json.Unmarshal(recordJson, &record)
 		if err != nil {
 			return err
 		}
-
-		return bucket.ForEach(func(key, value []byte) error {
-			var record R
-			err := json.Unmarshal(value, &record)
-			if err != nil {
-				return err
-			}
-			records = append(records, record)
-			return nil
-		})
+		records = append(records, record)
+		return nil
 	})
 	return records, err
 }
 
+// Decodes each record in the table in turn and passes it to the given function, so that large tables can be processed
+// without holding every record in memory at once.
+func (table *table[R]) forEach(handleRecord func(record *R) error) error {
+	return table.store.forEach(table.name, func(id int, recordJson []byte) error {
+		record := new(R)
+		if err := json.Unmarshal(recordJson, record); err != nil {
+			return err
+		}
+		return handleRecord(record)
+	})
+}
+
 // Persists the given record as a new row in the table.
 func (table *table[R]) create(record *R) error {
 	// Validate that the record has its ID set to zero or not as expected, depending on whether it is configured for
@@ -132,35 +170,27 @@ func (table *table[R]) create(record *R) error {
 		)
 	}
 
-	return table.bolt.Update(func(tx *bbolt.Tx) error {
-		bucket, err := table.getBucket(tx)
-		if err != nil {
+	if !table.manualId {
+		// Generate a new ID for the record.
+		var err error
+		if id, err = table.store.nextId(table.name); err != nil {
 			return err
 		}
+		value.Field(*table.idFieldIndex).SetInt(int64(id))
+	}
 
-		if !table.manualId {
-			// Generate a new ID for the record.
-			newSequence, err := bucket.NextSequence()
-			if err != nil {
-				return err
-			}
-			id = int(newSequence)
-			value.Field(*table.idFieldIndex).SetInt(int64(id))
-		}
-
-		// Ensure that a record having the same ID does not already exist in the table.
-		key := idToKey(id)
-		oldRecord := bucket.Get(key)
-		if oldRecord != nil {
-			return fmt.Errorf("%s with ID %d already exists: %s", table.name, id, string(oldRecord))
-		}
-
-		recordJson, err := json.Marshal(record)
-		if err != nil {
-			return err
-		}
-		return bucket.Put(key, recordJson)
-	})
+	recordJson, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	err = table.store.insert(table.name, id, recordJson)
+	table.invalidateCache()
+	if errors.Is(err, errRecordExists) {
+		// Include the record that is already using the ID in the error.
+		oldRecord, _ := table.store.get(table.name, id)
+		return fmt.Errorf("%s with ID %d already exists: %s", table.name, id, string(oldRecord))
+	}
+	return err
 }
 
 // Persists the given record as an update to the existing row in the table. Returns an error if the record does not
@@ -173,74 +203,31 @@ func (table *table[R]) update(record *R) error {
 		return fmt.Errorf("can't update %s with zero ID", table.name)
 	}
 
-	return table.bolt.Update(func(tx *bbolt.Tx) error {
-		bucket, err := table.getBucket(tx)
-		if err != nil {
-			return err
-		}
-
-		// Ensure that a record having the same ID exists in the table.
-		key := idToKey(id)
-		oldRecord := bucket.Get(key)
-		if oldRecord == nil {
-			return fmt.Errorf("can't update non-existent %s with ID %d", table.name, id)
-		}
-
-		recordJson, err := json.Marshal(record)
-		if err != nil {
-			return err
-		}
-		return bucket.Put(key, recordJson)
-	})
+	recordJson, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	err = table.store.update(table.name, id, recordJson)
+	table.invalidateCache()
+	if errors.Is(err, errRecordNotFound) {
+		return fmt.Errorf("can't update non-existent %s with ID %d", table.name, id)
+	}
+	return err
 }
 
 // Deletes the record having the given ID from the table. Returns an error if the record does not exist.
 func (table *table[R]) delete(id int) error {
-	return table.bolt.Update(func(tx *bbolt.Tx) error {
-		bucket, err := table.getBucket(tx)
-		if err != nil {
-			return err
-		}
-
-		// Ensure that a record having the same ID exists in the table.
-		key := idToKey(id)
-		oldRecord := bucket.Get(key)
-		if oldRecord == nil {
-			return fmt.Errorf("can't delete non-existent %s with ID %d", table.name, id)
-		}
-
-		return bucket.Delete(key)
-	})
+	err := table.store.delete(table.name, id)
+	table.invalidateCache()
+	if errors.Is(err, errRecordNotFound) {
+		return fmt.Errorf("can't delete non-existent %s with ID %d", table.name, id)
+	}
+	return err
 }
 
 // Deletes all records from the table.
 func (table *table[R]) truncate() error {
-	return table.bolt.Update(func(tx *bbolt.Tx) error {
-		_, err := table.getBucket(tx)
-		if err != nil {
-			return err
-		}
-
-		// Carry out the truncation by way of deleting the whole bucket and then recreate it.
-		err = tx.DeleteBucket(table.bucketKey)
-		if err != nil {
-			return err
-		}
-		_, err = tx.CreateBucket(table.bucketKey)
-		return err
-	})
-}
-
-// Obtains the Bolt bucket belonging to the table.
-func (table *table[R]) getBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
-	bucket := tx.Bucket(table.bucketKey)
-	if bucket == nil {
-		return nil, fmt.Errorf("unknown table %s", table.name)
-	}
-	return bucket, nil
-}
-
-// Serializes the given integer ID to a byte array containing its Base-10 string representation.
-func idToKey(id int) []byte {
-	return []byte(strconv.Itoa(id))
+	err := table.store.truncate(table.name)
+	table.invalidateCache()
+	return err
 }
@@ -78,6 +78,14 @@ func TestTableMultipleCrud(t *testing.T) {
 		assert.Equal(t, record3, records[2])
 	}
 
+	// Iterate over all records.
+	var intData []int
+	assert.Nil(t, table.forEach(func(record *validRecord) error {
+		intData = append(intData, record.IntData)
+		return nil
+	}))
+	assert.Equal(t, []int{1, 2, 3}, intData)
+
 	// Truncate the table and verify that the records no longer exist.
 	assert.Nil(t, table.truncate())
 	records, err = table.getAll()
@@ -204,3 +212,40 @@ func TestTableCrudErrors(t *testing.T) {
 		assert.Equal(t, "can't delete non-existent validRecord with ID 12345", err.Error())
 	}
 }
+
+func TestTableCache(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	table, err := newTable[validRecord](db)
+	if !assert.Nil(t, err) {
+		return
+	}
+	table.enableCache()
+	record := validRecord{IntData: 254, StringData: "The Cheesy Poofs"}
+	assert.Nil(t, table.create(&record))
+	records, _ := table.getAll()
+	assert.Equal(t, []validRecord{record}, records)
+
+	// Reads should be served from the cache rather than the store, and be safe for the caller to modify.
+	assert.Nil(t, db.store.update(table.name, record.Id, []byte(`{"Id": 1, "StringData": "Bypassed"}`)))
+	records[0].StringData = "Modified"
+	records, _ = table.getAll()
+	assert.Equal(t, []validRecord{record}, records)
+
+	// Writes should invalidate the cache.
+	record.StringData = "Teh Chezy Pofs"
+	assert.Nil(t, table.update(&record))
+	records, _ = table.getAll()
+	assert.Equal(t, []validRecord{record}, records)
+	record2 := validRecord{IntData: 1114}
+	assert.Nil(t, table.create(&record2))
+	records, _ = table.getAll()
+	assert.Equal(t, []validRecord{record, record2}, records)
+	assert.Nil(t, table.delete(record.Id))
+	records, _ = table.getAll()
+	assert.Equal(t, []validRecord{record2}, records)
+	assert.Nil(t, table.truncate())
+	records, _ = table.getAll()
+	assert.Empty(t, records)
+}
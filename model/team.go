@@ -5,7 +5,16 @@
 
 package model
 
-import "sort"
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 type Team struct {
 	Id              int `db:"id,manual"`
@@ -19,9 +28,8 @@ type Team struct {
 	RobotName       string
 	Accomplishments string
 	WpaKey          string
-	YellowCard      bool
-	HasConnected    bool
 	FtaNotes        string
+	AnnouncerNotes  string
 }
 
 func (database *Database) CreateTeam(team *Team) error {
@@ -54,3 +62,190 @@ func (database *Database) GetAllTeams() ([]Team, error) {
 	})
 	return teams, nil
 }
+
+// Parses a CSV file of announcer notes in which each row contains a team number followed by the notes for that team.
+// A leading header row is ignored if one is present.
+func ParseAnnouncerNotesCsv(reader io.Reader) (map[int]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvLines, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make(map[int]string)
+	for i, line := range csvLines {
+		if len(line) == 0 || strings.TrimSpace(line[0]) == "" {
+			continue
+		}
+		teamId, err := strconv.Atoi(strings.TrimSpace(line[0]))
+		if err != nil {
+			if i == 0 {
+				// Treat a non-numeric first row as a header.
+				continue
+			}
+			return nil, fmt.Errorf("Invalid team number '%s' on line %d", line[0], i+1)
+		}
+		if len(line) < 2 {
+			return nil, fmt.Errorf("Missing notes for team %d on line %d", teamId, i+1)
+		}
+		notes[teamId] = strings.TrimSpace(line[1])
+	}
+	return notes, nil
+}
+
+// A row from a team import file, holding either the team to import or the reason it can't be imported.
+type TeamImportRow struct {
+	LineNumber int
+	Team       Team
+	Error      string
+}
+
+// Columns that are assumed to be present, in order, when a team import file doesn't have a header row.
+var defaultTeamImportColumns = []string{"team", "nickname", "name", "city", "stateprov", "country", "rookieyear"}
+
+// Alternative header names that are recognized for each column of a team import file.
+var teamImportColumnAliases = map[string]string{
+	"number":     "team",
+	"teamnumber": "team",
+	"fullname":   "name",
+	"state":      "stateprov",
+	"province":   "stateprov",
+	"rookie":     "rookieyear",
+	"robot":      "robotname",
+	"school":     "schoolname",
+}
+
+// Parses a CSV or TSV file of teams to import, in which each row contains a team number followed by optional details.
+// If the first row is a header, its column names determine which details are present; otherwise the columns are taken
+// to be the team number, nickname, name, city, state/province, country and rookie year. Problems with individual rows
+// are reported on those rows rather than failing the whole file, and rows repeating an earlier team are rejected.
+func ParseTeamImportCsv(reader io.Reader) ([]TeamImportRow, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	csvReader := csv.NewReader(bytes.NewReader(contents))
+	firstLine, _, _ := bytes.Cut(contents, []byte("\n"))
+	if bytes.Contains(firstLine, []byte("\t")) {
+		csvReader.Comma = '\t'
+	}
+	csvReader.FieldsPerRecord = -1
+
+	columns := defaultTeamImportColumns
+	var rows []TeamImportRow
+	lineNumbers := make(map[int]int)
+	for i := 0; ; i++ {
+		line, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlankCsvLine(line) {
+			continue
+		}
+		lineNumber, _ := csvReader.FieldPos(0)
+		if i == 0 {
+			if _, err := strconv.Atoi(strings.TrimSpace(line[0])); err != nil {
+				// Treat a non-numeric first row as a header.
+				columns, err = parseTeamImportHeader(line)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		row := TeamImportRow{LineNumber: lineNumber}
+		row.Error = populateImportedTeam(&row.Team, columns, line)
+		if row.Error == "" {
+			if previousLineNumber, ok := lineNumbers[row.Team.Id]; ok {
+				row.Error = fmt.Sprintf("Team %d is already listed on line %d", row.Team.Id, previousLineNumber)
+			} else {
+				lineNumbers[row.Team.Id] = row.LineNumber
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Returns the normalized column names from the given header row of a team import file.
+func parseTeamImportHeader(line []string) ([]string, error) {
+	nonLetterRe := regexp.MustCompile("[^a-z]")
+	columns := make([]string, len(line))
+	hasTeamColumn := false
+	for i, header := range line {
+		column := nonLetterRe.ReplaceAllString(strings.ToLower(header), "")
+		if alias, ok := teamImportColumnAliases[column]; ok {
+			column = alias
+		}
+		columns[i] = column
+		hasTeamColumn = hasTeamColumn || column == "team"
+	}
+	if !hasTeamColumn {
+		return nil, fmt.Errorf("The header row doesn't have a team number column")
+	}
+	return columns, nil
+}
+
+// Fills in the given team from the given row of a team import file, returning a description of the problem if the row
+// isn't valid. Unrecognized columns are ignored.
+func populateImportedTeam(team *Team, columns []string, line []string) string {
+	for i, value := range line {
+		if i >= len(columns) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		switch columns[i] {
+		case "team":
+			if value == "" {
+				continue
+			}
+			teamId, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "frc"))
+			if err != nil || teamId <= 0 {
+				return fmt.Sprintf("Invalid team number '%s'", value)
+			}
+			team.Id = teamId
+		case "nickname":
+			team.Nickname = value
+		case "name":
+			team.Name = value
+		case "city":
+			team.City = value
+		case "stateprov":
+			team.StateProv = value
+		case "country":
+			team.Country = value
+		case "schoolname":
+			team.SchoolName = value
+		case "robotname":
+			team.RobotName = value
+		case "rookieyear":
+			if value == "" {
+				continue
+			}
+			rookieYear, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Sprintf("Invalid rookie year '%s'", value)
+			}
+			team.RookieYear = rookieYear
+		}
+	}
+	if team.Id == 0 {
+		return "Missing team number"
+	}
+	return ""
+}
+
+// Returns true if every field of the given CSV line is empty.
+func isBlankCsvLine(line []string) bool {
+	for _, field := range line {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the calculated offensive and defensive contributions of each team.
+
+package model
+
+import (
+	"sort"
+)
+
+// Represents the least-squares estimates of a team's contribution to its alliance's score in qualification matches,
+// broken down by scoring component.
+type TeamStat struct {
+	TeamId        int `db:"id,manual"`
+	MatchesPlayed int
+	Opr           float64 // Offensive power rating: contribution to the alliance's total score.
+	Dpr           float64 // Defensive power rating: contribution to the opposing alliance's total score.
+	Ccwm          float64 // Calculated contribution to winning margin.
+	AutoOpr       float64
+	AmpOpr        float64
+	SpeakerOpr    float64
+	StageOpr      float64
+	FoulOpr       float64
+}
+
+func (database *Database) GetTeamStatForTeam(teamId int) (*TeamStat, error) {
+	return database.teamStatTable.getById(teamId)
+}
+
+// Returns the stats for all teams, ordered from highest to lowest OPR.
+func (database *Database) GetAllTeamStats() ([]TeamStat, error) {
+	teamStats, err := database.teamStatTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(teamStats, func(i, j int) bool {
+		if teamStats[i].Opr == teamStats[j].Opr {
+			return teamStats[i].TeamId < teamStats[j].TeamId
+		}
+		return teamStats[i].Opr > teamStats[j].Opr
+	})
+	return teamStats, nil
+}
+
+func (database *Database) TruncateTeamStats() error {
+	return database.teamStatTable.truncate()
+}
+
+// Deletes the existing team stats and inserts the given ones as a replacement.
+func (database *Database) ReplaceAllTeamStats(teamStats []TeamStat) error {
+	if err := database.teamStatTable.truncate(); err != nil {
+		return err
+	}
+
+	for _, teamStat := range teamStats {
+		if err := database.teamStatTable.create(&teamStat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
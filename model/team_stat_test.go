@@ -0,0 +1,42 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTeamStatCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	teamStat, err := db.GetTeamStatForTeam(254)
+	assert.Nil(t, err)
+	assert.Nil(t, teamStat)
+
+	teamStats := []TeamStat{
+		{TeamId: 1114, MatchesPlayed: 2, Opr: 20.5, Dpr: 4, Ccwm: 16.5},
+		{TeamId: 254, MatchesPlayed: 2, Opr: 31.25, Dpr: 10, Ccwm: 21.25, AmpOpr: 3},
+		{TeamId: 2056, MatchesPlayed: 1, Opr: 20.5},
+	}
+	assert.Nil(t, db.ReplaceAllTeamStats(teamStats))
+	teamStat, err = db.GetTeamStatForTeam(254)
+	assert.Nil(t, err)
+	assert.Equal(t, teamStats[1], *teamStat)
+
+	allTeamStats, err := db.GetAllTeamStats()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(allTeamStats)) {
+		assert.Equal(t, 254, allTeamStats[0].TeamId)
+		assert.Equal(t, 1114, allTeamStats[1].TeamId)
+		assert.Equal(t, 2056, allTeamStats[2].TeamId)
+	}
+
+	assert.Nil(t, db.ReplaceAllTeamStats(teamStats[2:]))
+	allTeamStats, _ = db.GetAllTeamStats()
+	assert.Equal(t, 1, len(allTeamStats))
+	assert.Nil(t, db.TruncateTeamStats())
+	allTeamStats, _ = db.GetAllTeamStats()
+	assert.Empty(t, allTeamStats)
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a team's progress through the pit checks at an event and the card it is
+// carrying, which are kept apart from its registration data so that they don't follow the team to other events.
+
+package model
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TeamStatus struct {
+	TeamId           int `db:"id,manual"`
+	InspectionPassed bool
+	Weighed          bool
+	HasConnected     bool   // Whether the team's robot has linked up with the field, whether in a test or a match.
+	YellowCard       bool   // Whether the team is carrying a yellow card into its next match.
+	RadioMacAddress  string // MAC address of the robot radio that the team brought to check-in, if recorded.
+	UpdatedAt        time.Time
+}
+
+// Returns the given robot radio MAC address in canonical lowercase, colon-separated form, or an error if it isn't a
+// valid MAC address. A blank address is returned as is.
+func NormalizeRadioMacAddress(macAddress string) (string, error) {
+	macAddress = strings.TrimSpace(macAddress)
+	if macAddress == "" {
+		return "", nil
+	}
+	hardwareAddr, err := net.ParseMAC(macAddress)
+	if err != nil || len(hardwareAddr) != 6 {
+		return "", fmt.Errorf("Invalid radio MAC address '%s'.", macAddress)
+	}
+	return hardwareAddr.String(), nil
+}
+
+// Returns the status of the given team, or a blank status if none has been recorded yet.
+func (database *Database) GetTeamStatus(teamId int) (*TeamStatus, error) {
+	teamStatus, err := database.teamStatusTable.getById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	if teamStatus == nil {
+		teamStatus = &TeamStatus{TeamId: teamId}
+	}
+	return teamStatus, nil
+}
+
+// Stores the given status, replacing any existing one for the same team.
+func (database *Database) SaveTeamStatus(teamStatus *TeamStatus) error {
+	teamStatus.UpdatedAt = time.Now()
+	existingTeamStatus, err := database.teamStatusTable.getById(teamStatus.TeamId)
+	if err != nil {
+		return err
+	}
+	if existingTeamStatus == nil {
+		return database.teamStatusTable.create(teamStatus)
+	}
+	return database.teamStatusTable.update(teamStatus)
+}
+
+// Removes the status of the given team, if there is one.
+func (database *Database) DeleteTeamStatus(teamId int) error {
+	existingTeamStatus, err := database.teamStatusTable.getById(teamId)
+	if err != nil || existingTeamStatus == nil {
+		return err
+	}
+	return database.teamStatusTable.delete(teamId)
+}
+
+func (database *Database) TruncateTeamStatuses() error {
+	return database.teamStatusTable.truncate()
+}
+
+// Returns all the recorded team statuses, ordered by team number.
+func (database *Database) GetAllTeamStatuses() ([]TeamStatus, error) {
+	teamStatuses, err := database.teamStatusTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(teamStatuses, func(i, j int) bool {
+		return teamStatuses[i].TeamId < teamStatuses[j].TeamId
+	})
+	return teamStatuses, nil
+}
+
+// Returns the statuses of all the teams that have one, keyed by team number.
+func (database *Database) GetTeamStatusesByTeamId() (map[int]TeamStatus, error) {
+	teamStatuses, err := database.teamStatusTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	teamStatusMap := make(map[int]TeamStatus, len(teamStatuses))
+	for _, teamStatus := range teamStatuses {
+		teamStatusMap[teamStatus.TeamId] = teamStatus
+	}
+	return teamStatusMap, nil
+}
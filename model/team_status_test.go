@@ -0,0 +1,81 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentTeamStatus(t *testing.T) {
+	db := setupTestDb(t)
+
+	teamStatus, err := db.GetTeamStatus(254)
+	assert.Nil(t, err)
+	assert.Equal(t, TeamStatus{TeamId: 254}, *teamStatus)
+	assert.Nil(t, db.DeleteTeamStatus(254))
+}
+
+func TestTeamStatusCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	teamStatus := TeamStatus{TeamId: 254, InspectionPassed: true}
+	assert.Nil(t, db.SaveTeamStatus(&teamStatus))
+	assert.False(t, teamStatus.UpdatedAt.IsZero())
+	teamStatus2, err := db.GetTeamStatus(254)
+	assert.Nil(t, err)
+	assert.True(t, teamStatus2.InspectionPassed)
+	assert.True(t, teamStatus.UpdatedAt.Equal(teamStatus2.UpdatedAt))
+
+	teamStatus2.Weighed = true
+	teamStatus2.YellowCard = true
+	assert.Nil(t, db.SaveTeamStatus(teamStatus2))
+	teamStatus3, _ := db.GetTeamStatus(254)
+	assert.True(t, teamStatus3.InspectionPassed)
+	assert.True(t, teamStatus3.Weighed)
+	assert.True(t, teamStatus3.YellowCard)
+	assert.False(t, teamStatus3.HasConnected)
+
+	assert.Nil(t, db.DeleteTeamStatus(254))
+	teamStatus3, _ = db.GetTeamStatus(254)
+	assert.Equal(t, TeamStatus{TeamId: 254}, *teamStatus3)
+}
+
+func TestGetAllTeamStatuses(t *testing.T) {
+	db := setupTestDb(t)
+
+	assert.Nil(t, db.SaveTeamStatus(&TeamStatus{TeamId: 1114, Weighed: true}))
+	assert.Nil(t, db.SaveTeamStatus(&TeamStatus{TeamId: 254, HasConnected: true}))
+	teamStatuses, err := db.GetAllTeamStatuses()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(teamStatuses)) {
+		assert.Equal(t, 254, teamStatuses[0].TeamId)
+		assert.Equal(t, 1114, teamStatuses[1].TeamId)
+	}
+	teamStatusMap, err := db.GetTeamStatusesByTeamId()
+	assert.Nil(t, err)
+	assert.True(t, teamStatusMap[254].HasConnected)
+	assert.True(t, teamStatusMap[1114].Weighed)
+	assert.False(t, teamStatusMap[2056].Weighed)
+
+	assert.Nil(t, db.TruncateTeamStatuses())
+	teamStatuses, _ = db.GetAllTeamStatuses()
+	assert.Empty(t, teamStatuses)
+}
+
+func TestNormalizeRadioMacAddress(t *testing.T) {
+	macAddress, err := NormalizeRadioMacAddress(" 00-80-2F-AB-cd-01 ")
+	assert.Nil(t, err)
+	assert.Equal(t, "00:80:2f:ab:cd:01", macAddress)
+	macAddress, err = NormalizeRadioMacAddress("")
+	assert.Nil(t, err)
+	assert.Equal(t, "", macAddress)
+
+	_, err = NormalizeRadioMacAddress("00:80:2f:ab:cd")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid radio MAC address '00:80:2f:ab:cd'.", err.Error())
+	}
+	_, err = NormalizeRadioMacAddress("0080.2fab.cd01.0203")
+	assert.NotNil(t, err)
+}
@@ -5,6 +5,7 @@ package model
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -72,3 +73,70 @@ func TestGetAllTeams(t *testing.T) {
 		assert.Equal(t, i+1, teams[i].Id)
 	}
 }
+
+func TestParseAnnouncerNotesCsv(t *testing.T) {
+	notes, err := ParseAnnouncerNotesCsv(strings.NewReader(
+		"Team,Notes\n254,\"Won Einstein in 2018, 2022\"\n1114, Has a robot named Simbot \n\n",
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]string{254: "Won Einstein in 2018, 2022", 1114: "Has a robot named Simbot"}, notes)
+
+	_, err = ParseAnnouncerNotesCsv(strings.NewReader("254,Notes\nabc,More notes\n"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid team number 'abc' on line 2", err.Error())
+	}
+
+	_, err = ParseAnnouncerNotesCsv(strings.NewReader("254\n"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Missing notes for team 254 on line 1", err.Error())
+	}
+}
+
+func TestParseTeamImportCsv(t *testing.T) {
+	rows, err := ParseTeamImportCsv(strings.NewReader(
+		"254,The Cheesy Poofs,NASA Ames,San Jose,CA,USA,1999\n1114\n\nabc,Not a team\n254,Duplicate\n" +
+			"33,Killer Bees,,,,,19ninety\n,No number\n",
+	))
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(rows)) {
+		assert.Equal(
+			t,
+			TeamImportRow{
+				LineNumber: 1,
+				Team: Team{
+					Id:         254,
+					Name:       "NASA Ames",
+					Nickname:   "The Cheesy Poofs",
+					City:       "San Jose",
+					StateProv:  "CA",
+					Country:    "USA",
+					RookieYear: 1999,
+				},
+			},
+			rows[0],
+		)
+		assert.Equal(t, TeamImportRow{LineNumber: 2, Team: Team{Id: 1114}}, rows[1])
+		assert.Equal(t, 4, rows[2].LineNumber)
+		assert.Equal(t, "Invalid team number 'abc'", rows[2].Error)
+		assert.Equal(t, "Team 254 is already listed on line 1", rows[3].Error)
+		assert.Equal(t, "Invalid rookie year '19ninety'", rows[4].Error)
+		assert.Equal(t, "Missing team number", rows[5].Error)
+	}
+
+	// Check that a header row and tab separators are recognized.
+	rows, err = ParseTeamImportCsv(strings.NewReader(
+		"Robot Name\tTeam #\tRookie Year\tNotes\nSimbot\tfrc1114\t2003\tIgnored\n\t973\t\n",
+	))
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(rows)) {
+		assert.Equal(
+			t, TeamImportRow{LineNumber: 2, Team: Team{Id: 1114, RobotName: "Simbot", RookieYear: 2003}}, rows[0],
+		)
+		assert.Equal(t, TeamImportRow{LineNumber: 3, Team: Team{Id: 973}}, rows[1])
+	}
+
+	_, err = ParseTeamImportCsv(strings.NewReader("Nickname,City\nPoofs,San Jose\n"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The header row doesn't have a team number column", err.Error())
+	}
+}
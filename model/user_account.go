@@ -0,0 +1,139 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a volunteer's login account, which grants access to the pages for their role.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	AdminRole       = "admin"
+	ScorekeeperRole = "scorekeeper"
+	RefereeRole     = "referee"
+	FtaRole         = "fta"
+	AvRole          = "av"
+	ReadOnlyRole    = "readonly"
+)
+
+const (
+	passwordHashScheme     = "pbkdf2-sha256"
+	passwordHashIterations = 600000
+	passwordHashKeyLength  = 32
+)
+
+// Ordered list of the roles that a user account can be given.
+var UserRoles = []string{AdminRole, ScorekeeperRole, RefereeRole, FtaRole, AvRole, ReadOnlyRole}
+
+// Human-readable names of the user roles.
+var UserRoleNames = map[string]string{
+	AdminRole:       "Admin",
+	ScorekeeperRole: "Scorekeeper",
+	RefereeRole:     "Referee",
+	FtaRole:         "FTA",
+	AvRole:          "A/V",
+	ReadOnlyRole:    "Read-Only",
+}
+
+type UserAccount struct {
+	Id           int `db:"id"`
+	Username     string
+	Role         string
+	PasswordSalt string
+	PasswordHash string
+}
+
+func (database *Database) CreateUserAccount(userAccount *UserAccount) error {
+	return database.userAccountTable.create(userAccount)
+}
+
+func (database *Database) GetUserAccountById(id int) (*UserAccount, error) {
+	return database.userAccountTable.getById(id)
+}
+
+// Returns the account with the given username, ignoring case, or nil if there isn't one.
+func (database *Database) GetUserAccountByUsername(username string) (*UserAccount, error) {
+	userAccounts, err := database.userAccountTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userAccount := range userAccounts {
+		if strings.EqualFold(userAccount.Username, username) {
+			return &userAccount, nil
+		}
+	}
+	return nil, nil
+}
+
+func (database *Database) UpdateUserAccount(userAccount *UserAccount) error {
+	return database.userAccountTable.update(userAccount)
+}
+
+func (database *Database) DeleteUserAccount(id int) error {
+	return database.userAccountTable.delete(id)
+}
+
+func (database *Database) TruncateUserAccounts() error {
+	return database.userAccountTable.truncate()
+}
+
+func (database *Database) GetAllUserAccounts() ([]UserAccount, error) {
+	userAccounts, err := database.userAccountTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(userAccounts, func(i, j int) bool {
+		return userAccounts[i].Id < userAccounts[j].Id
+	})
+	return userAccounts, nil
+}
+
+// Sets the account's password, storing only a salted PBKDF2 hash of it. The hash is stored along with the scheme and
+// iteration count used to compute it so that accounts keep working if the iteration count is later raised.
+func (userAccount *UserAccount) SetPassword(password string) error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	userAccount.PasswordSalt = hex.EncodeToString(salt)
+	hash := hashPassword(userAccount.PasswordSalt, password, passwordHashIterations)
+	userAccount.PasswordHash = fmt.Sprintf("%s$%d$%s", passwordHashScheme, passwordHashIterations, hash)
+	return nil
+}
+
+// Returns true if the given password matches the one that was set for the account.
+func (userAccount *UserAccount) CheckPassword(password string) bool {
+	parts := strings.Split(userAccount.PasswordHash, "$")
+	if len(parts) != 3 || parts[0] != passwordHashScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	hash := hashPassword(userAccount.PasswordSalt, password, iterations)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(parts[2])) == 1
+}
+
+// Returns true if the account's role grants access to pages that are open to any of the given roles. Admins have access
+// to everything.
+func (userAccount *UserAccount) HasRole(roles ...string) bool {
+	return userAccount.Role == AdminRole || slices.Contains(roles, userAccount.Role)
+}
+
+// Derives a key from the given password using PBKDF2 with HMAC-SHA256.
+func hashPassword(salt, password string, iterations int) string {
+	return hex.EncodeToString(pbkdf2.Key([]byte(password), []byte(salt), iterations, passwordHashKeyLength, sha256.New))
+}
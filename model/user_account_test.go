@@ -0,0 +1,110 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestUserAccountCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	userAccount := UserAccount{Username: "Bertha", Role: RefereeRole}
+	assert.Nil(t, userAccount.SetPassword("hunter2"))
+	assert.Nil(t, db.CreateUserAccount(&userAccount))
+	userAccount2, err := db.GetUserAccountById(userAccount.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, userAccount, *userAccount2)
+
+	userAccount2, err = db.GetUserAccountByUsername("bertha")
+	assert.Nil(t, err)
+	assert.Equal(t, userAccount, *userAccount2)
+	userAccount2, err = db.GetUserAccountByUsername("blorpy")
+	assert.Nil(t, err)
+	assert.Nil(t, userAccount2)
+
+	userAccount.Role = FtaRole
+	assert.Nil(t, db.UpdateUserAccount(&userAccount))
+	userAccount2, _ = db.GetUserAccountById(userAccount.Id)
+	assert.Equal(t, FtaRole, userAccount2.Role)
+
+	assert.Nil(t, db.DeleteUserAccount(userAccount.Id))
+	userAccount2, err = db.GetUserAccountById(userAccount.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, userAccount2)
+}
+
+func TestTruncateUserAccounts(t *testing.T) {
+	db := setupTestDb(t)
+
+	db.CreateUserAccount(&UserAccount{Username: "Bertha", Role: RefereeRole})
+	db.CreateUserAccount(&UserAccount{Username: "Alfred", Role: AvRole})
+	userAccounts, err := db.GetAllUserAccounts()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(userAccounts)) {
+		assert.Equal(t, "Bertha", userAccounts[0].Username)
+		assert.Equal(t, "Alfred", userAccounts[1].Username)
+	}
+
+	assert.Nil(t, db.TruncateUserAccounts())
+	userAccounts, err = db.GetAllUserAccounts()
+	assert.Nil(t, err)
+	assert.Empty(t, userAccounts)
+}
+
+func TestUserAccountPassword(t *testing.T) {
+	userAccount := UserAccount{}
+	assert.False(t, userAccount.CheckPassword(""))
+
+	assert.Nil(t, userAccount.SetPassword("hunter2"))
+	assert.NotContains(t, userAccount.PasswordHash, "hunter2")
+	assert.True(t, userAccount.CheckPassword("hunter2"))
+	assert.False(t, userAccount.CheckPassword("Hunter2"))
+	assert.False(t, userAccount.CheckPassword(""))
+
+	// Check that the same password is hashed differently each time it is set.
+	previousHash := userAccount.PasswordHash
+	assert.Nil(t, userAccount.SetPassword("hunter2"))
+	assert.NotEqual(t, previousHash, userAccount.PasswordHash)
+	assert.True(t, userAccount.CheckPassword("hunter2"))
+	assert.True(t, strings.HasPrefix(userAccount.PasswordHash, "pbkdf2-sha256$600000$"))
+
+	// Check that a hash computed with a different iteration count is still accepted.
+	hash := hashPassword(userAccount.PasswordSalt, "hunter2", 1000)
+	userAccount.PasswordHash = "pbkdf2-sha256$1000$" + hash
+	assert.True(t, userAccount.CheckPassword("hunter2"))
+	assert.False(t, userAccount.CheckPassword("hunter3"))
+
+	// Check that a malformed hash is never accepted.
+	userAccount.PasswordHash = "pbkdf2-sha256$zero$" + hash
+	assert.False(t, userAccount.CheckPassword("hunter2"))
+	userAccount.PasswordHash = hash
+	assert.False(t, userAccount.CheckPassword("hunter2"))
+}
+
+func TestHashPassword(t *testing.T) {
+	// Check against the PBKDF2-HMAC-SHA256 test vectors from RFC 7914.
+	assert.Equal(
+		t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc",
+		hashPassword("salt", "passwd", 1),
+	)
+	assert.Equal(
+		t, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56",
+		hashPassword("NaCl", "Password", 80000),
+	)
+}
+
+func TestUserAccountHasRole(t *testing.T) {
+	admin := UserAccount{Role: AdminRole}
+	assert.True(t, admin.HasRole())
+	assert.True(t, admin.HasRole(RefereeRole))
+
+	referee := UserAccount{Role: RefereeRole}
+	assert.False(t, referee.HasRole())
+	assert.True(t, referee.HasRole(RefereeRole))
+	assert.True(t, referee.HasRole(ScorekeeperRole, RefereeRole))
+	assert.False(t, referee.HasRole(AvRole, ReadOnlyRole))
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore methods for the timestamps of match starts and ends and of replay clips, relative to both the
+// wall clock and the video stream, for use in cutting the event's stream into individual match and highlight videos.
+
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type VideoMarkerType string
+
+const (
+	VideoMarkerStreamSync VideoMarkerType = "streamSync"
+	VideoMarkerMatchStart VideoMarkerType = "matchStart"
+	VideoMarkerMatchEnd   VideoMarkerType = "matchEnd"
+	VideoMarkerReplay     VideoMarkerType = "replay"
+)
+
+type VideoMarker struct {
+	Id              int `db:"id"`
+	MatchId         int
+	Type            VideoMarkerType
+	Time            time.Time
+	StreamSynced    bool    // Whether the A/V operator had given the stream offset by the time of the marker.
+	StreamOffsetSec float64 // Position in the video stream at the time of the marker, if the stream has been synced.
+}
+
+func (database *Database) CreateVideoMarker(videoMarker *VideoMarker) error {
+	return database.videoMarkerTable.create(videoMarker)
+}
+
+// Returns all video markers, in chronological order.
+func (database *Database) GetAllVideoMarkers() ([]VideoMarker, error) {
+	videoMarkers, err := database.videoMarkerTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(videoMarkers, func(i, j int) bool {
+		return videoMarkers[i].Time.Before(videoMarkers[j].Time)
+	})
+	return videoMarkers, nil
+}
+
+// Returns the most recent marker of the given type, or nil if there isn't one.
+func (database *Database) GetLatestVideoMarker(markerType VideoMarkerType) (*VideoMarker, error) {
+	videoMarkers, err := database.GetAllVideoMarkers()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(videoMarkers) - 1; i >= 0; i-- {
+		if videoMarkers[i].Type == markerType {
+			return &videoMarkers[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns the start and end markers of the most recent play of the given match, either of which may be nil if it
+// wasn't recorded.
+func (database *Database) GetVideoMarkersForMatch(matchId int) (*VideoMarker, *VideoMarker, error) {
+	videoMarkers, err := database.GetAllVideoMarkers()
+	if err != nil {
+		return nil, nil, err
+	}
+	var startMarker, endMarker *VideoMarker
+	for i, videoMarker := range videoMarkers {
+		if videoMarker.MatchId != matchId {
+			continue
+		}
+		switch videoMarker.Type {
+		case VideoMarkerMatchStart:
+			// A new start means that the match was replayed, so any end from the earlier play no longer applies.
+			startMarker = &videoMarkers[i]
+			endMarker = nil
+		case VideoMarkerMatchEnd:
+			endMarker = &videoMarkers[i]
+		}
+	}
+	return startMarker, endMarker, nil
+}
+
+func (database *Database) TruncateVideoMarkers() error {
+	return database.videoMarkerTable.truncate()
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVideoMarkers(t *testing.T) {
+	db := setupTestDb(t)
+
+	videoMarkers, err := db.GetAllVideoMarkers()
+	assert.Nil(t, err)
+	assert.Empty(t, videoMarkers)
+	streamSync, err := db.GetLatestVideoMarker(VideoMarkerStreamSync)
+	assert.Nil(t, err)
+	assert.Nil(t, streamSync)
+	startMarker, endMarker, err := db.GetVideoMarkersForMatch(1)
+	assert.Nil(t, err)
+	assert.Nil(t, startMarker)
+	assert.Nil(t, endMarker)
+
+	baseTime := time.Unix(1000, 0).UTC()
+	marker := func(matchId int, markerType VideoMarkerType, offsetSec int) *VideoMarker {
+		return &VideoMarker{
+			MatchId:         matchId,
+			Type:            markerType,
+			Time:            baseTime.Add(time.Duration(offsetSec) * time.Second),
+			StreamSynced:    true,
+			StreamOffsetSec: float64(offsetSec),
+		}
+	}
+	// Create them out of order to check the sorting.
+	assert.Nil(t, db.CreateVideoMarker(marker(1, VideoMarkerMatchEnd, 160)))
+	assert.Nil(t, db.CreateVideoMarker(marker(0, VideoMarkerStreamSync, 0)))
+	assert.Nil(t, db.CreateVideoMarker(marker(1, VideoMarkerMatchStart, 10)))
+	assert.Nil(t, db.CreateVideoMarker(marker(2, VideoMarkerMatchStart, 400)))
+	assert.Nil(t, db.CreateVideoMarker(marker(0, VideoMarkerStreamSync, 300)))
+
+	videoMarkers, err = db.GetAllVideoMarkers()
+	assert.Nil(t, err)
+	if assert.Equal(t, 5, len(videoMarkers)) {
+		assert.Equal(t, VideoMarkerStreamSync, videoMarkers[0].Type)
+		assert.Equal(t, 10.0, videoMarkers[1].StreamOffsetSec)
+		assert.Equal(t, 400.0, videoMarkers[4].StreamOffsetSec)
+	}
+	streamSync, err = db.GetLatestVideoMarker(VideoMarkerStreamSync)
+	assert.Nil(t, err)
+	if assert.NotNil(t, streamSync) {
+		assert.Equal(t, 300.0, streamSync.StreamOffsetSec)
+	}
+
+	startMarker, endMarker, err = db.GetVideoMarkersForMatch(1)
+	assert.Nil(t, err)
+	if assert.NotNil(t, startMarker) && assert.NotNil(t, endMarker) {
+		assert.Equal(t, 10.0, startMarker.StreamOffsetSec)
+		assert.Equal(t, 160.0, endMarker.StreamOffsetSec)
+	}
+
+	// A replay should supersede the markers from the earlier play.
+	assert.Nil(t, db.CreateVideoMarker(marker(1, VideoMarkerMatchStart, 500)))
+	startMarker, endMarker, err = db.GetVideoMarkersForMatch(1)
+	assert.Nil(t, err)
+	if assert.NotNil(t, startMarker) {
+		assert.Equal(t, 500.0, startMarker.StreamOffsetSec)
+	}
+	assert.Nil(t, endMarker)
+
+	assert.Nil(t, db.TruncateVideoMarkers())
+	videoMarkers, err = db.GetAllVideoMarkers()
+	assert.Nil(t, err)
+	assert.Empty(t, videoMarkers)
+}
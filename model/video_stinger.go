@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for a short video played full-screen on the audience display, such as a match start
+// animation or an award intro.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+type VideoStinger struct {
+	Id         int `db:"id"`
+	Name       string
+	VideoFile  string
+	NextScreen string // Audience display screen to switch to once the video has finished playing.
+}
+
+func (database *Database) CreateVideoStinger(videoStinger *VideoStinger) error {
+	if err := videoStinger.validate(); err != nil {
+		return err
+	}
+	return database.videoStingerTable.create(videoStinger)
+}
+
+func (database *Database) GetVideoStingerById(id int) (*VideoStinger, error) {
+	return database.videoStingerTable.getById(id)
+}
+
+func (database *Database) UpdateVideoStinger(videoStinger *VideoStinger) error {
+	if err := videoStinger.validate(); err != nil {
+		return err
+	}
+	return database.videoStingerTable.update(videoStinger)
+}
+
+func (database *Database) DeleteVideoStinger(id int) error {
+	return database.videoStingerTable.delete(id)
+}
+
+func (database *Database) TruncateVideoStingers() error {
+	return database.videoStingerTable.truncate()
+}
+
+func (database *Database) GetAllVideoStingers() ([]VideoStinger, error) {
+	videoStingers, err := database.videoStingerTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(videoStingers, func(i, j int) bool {
+		return videoStingers[i].Name < videoStingers[j].Name
+	})
+	return videoStingers, nil
+}
+
+func (videoStinger *VideoStinger) validate() error {
+	if videoStinger.Name == "" {
+		return fmt.Errorf("video stinger name must not be blank")
+	}
+	if !IsVideoFile(videoStinger.VideoFile) {
+		return fmt.Errorf("'%s' is not a supported video file", videoStinger.VideoFile)
+	}
+	if _, ok := ShowFlowScreenNames[videoStinger.NextScreen]; !ok {
+		return fmt.Errorf("invalid audience display screen '%s'", videoStinger.NextScreen)
+	}
+	return nil
+}
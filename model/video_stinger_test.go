@@ -0,0 +1,46 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVideoStingerCrud(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	videoStinger, err := db.GetVideoStingerById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, videoStinger)
+
+	videoStinger1 := VideoStinger{Name: "Match Start", VideoFile: "match_start.mp4", NextScreen: "match"}
+	assert.Nil(t, db.CreateVideoStinger(&videoStinger1))
+	videoStinger2 := VideoStinger{Name: "Award Intro", VideoFile: "award.webm", NextScreen: "blank"}
+	assert.Nil(t, db.CreateVideoStinger(&videoStinger2))
+	assert.NotNil(t, db.CreateVideoStinger(&VideoStinger{VideoFile: "award.webm", NextScreen: "blank"}))
+	assert.NotNil(t, db.CreateVideoStinger(&VideoStinger{Name: "Logo", VideoFile: "logo.png", NextScreen: "blank"}))
+	assert.NotNil(t, db.CreateVideoStinger(&VideoStinger{Name: "Intro", VideoFile: "intro.mp4", NextScreen: "rankings"}))
+
+	videoStingers, err := db.GetAllVideoStingers()
+	assert.Nil(t, err)
+	assert.Equal(t, []VideoStinger{videoStinger2, videoStinger1}, videoStingers)
+
+	videoStinger1.NextScreen = "intro"
+	assert.Nil(t, db.UpdateVideoStinger(&videoStinger1))
+	videoStinger, err = db.GetVideoStingerById(videoStinger1.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, videoStinger1, *videoStinger)
+
+	assert.Nil(t, db.DeleteVideoStinger(videoStinger1.Id))
+	videoStinger, err = db.GetVideoStingerById(videoStinger1.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, videoStinger)
+
+	assert.Nil(t, db.TruncateVideoStingers())
+	videoStingers, err = db.GetAllVideoStingers()
+	assert.Nil(t, err)
+	assert.Empty(t, videoStingers)
+}
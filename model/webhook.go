@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Model and datastore CRUD methods for the outgoing webhooks that are fired on arena events.
+
+package model
+
+import (
+	"slices"
+	"sort"
+)
+
+type Webhook struct {
+	Id     int `db:"id"`
+	Url    string
+	Secret string
+	Events []string
+}
+
+func (database *Database) CreateWebhook(webhook *Webhook) error {
+	return database.webhookTable.create(webhook)
+}
+
+func (database *Database) GetWebhookById(id int) (*Webhook, error) {
+	return database.webhookTable.getById(id)
+}
+
+func (database *Database) UpdateWebhook(webhook *Webhook) error {
+	return database.webhookTable.update(webhook)
+}
+
+func (database *Database) DeleteWebhook(id int) error {
+	return database.webhookTable.delete(id)
+}
+
+func (database *Database) TruncateWebhooks() error {
+	return database.webhookTable.truncate()
+}
+
+func (database *Database) GetAllWebhooks() ([]Webhook, error) {
+	webhooks, err := database.webhookTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Id < webhooks[j].Id
+	})
+	return webhooks, nil
+}
+
+// Returns true if the webhook should be fired for the given type of event.
+func (webhook *Webhook) IsSubscribedTo(event string) bool {
+	return slices.Contains(webhook.Events, event)
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNonexistentWebhook(t *testing.T) {
+	db := setupTestDb(t)
+
+	webhook, err := db.GetWebhookById(1114)
+	assert.Nil(t, err)
+	assert.Nil(t, webhook)
+}
+
+func TestWebhookCrud(t *testing.T) {
+	db := setupTestDb(t)
+
+	webhook := Webhook{Url: "http://localhost:9000/hook", Secret: "abc123", Events: []string{"match_started"}}
+	assert.Nil(t, db.CreateWebhook(&webhook))
+	webhook2, err := db.GetWebhookById(webhook.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, webhook, *webhook2)
+	assert.True(t, webhook2.IsSubscribedTo("match_started"))
+	assert.False(t, webhook2.IsSubscribedTo("score_committed"))
+
+	webhook.Events = append(webhook.Events, "score_committed")
+	assert.Nil(t, db.UpdateWebhook(&webhook))
+	webhook2, err = db.GetWebhookById(webhook.Id)
+	assert.Nil(t, err)
+	assert.True(t, webhook2.IsSubscribedTo("score_committed"))
+
+	assert.Nil(t, db.CreateWebhook(&Webhook{Url: "https://discord.example/hook"}))
+	webhooks, err := db.GetAllWebhooks()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(webhooks)) {
+		assert.Equal(t, webhook.Id, webhooks[0].Id)
+		assert.Equal(t, "https://discord.example/hook", webhooks[1].Url)
+	}
+
+	assert.Nil(t, db.DeleteWebhook(webhook.Id))
+	webhook2, err = db.GetWebhookById(webhook.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, webhook2)
+
+	assert.Nil(t, db.TruncateWebhooks())
+	webhooks, err = db.GetAllWebhooks()
+	assert.Nil(t, err)
+	assert.Empty(t, webhooks)
+}
@@ -12,12 +12,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 )
 
@@ -41,6 +41,7 @@ type TeamWifiStatus struct {
 	RxRate           float64
 	TxRate           float64
 	SignalNoiseRatio int
+	RadioMacAddress  string // MAC address of the robot radio associated with the team's SSID, if any.
 }
 
 type configurationRequest struct {
@@ -68,6 +69,7 @@ type stationStatus struct {
 	TxRateMbps        float64 `json:"txRateMbps"`
 	SignalNoiseRatio  int     `json:"signalNoiseRatio"`
 	BandwidthUsedMbps float64 `json:"bandwidthUsedMbps"`
+	MacAddress        string  `json:"macAddress"`
 }
 
 func (ap *AccessPoint) SetSettings(
@@ -89,7 +91,7 @@ func (ap *AccessPoint) Run() {
 	for {
 		time.Sleep(time.Second * accessPointPollPeriodSec)
 		if err := ap.updateMonitoring(); err != nil {
-			log.Printf("Failed to update access point monitoring: %v", err)
+			logging.Network.Error("Failed to update access point monitoring.", "error", err)
 		}
 	}
 }
@@ -136,7 +138,7 @@ func (ap *AccessPoint) ConfigureTeamWifi(teams [6]*model.Team) error {
 		return fmt.Errorf("access point returned status %d: %s", httpResponse.StatusCode, string(body))
 	}
 
-	log.Println("Access point accepted the new configuration and will apply it asynchronously.")
+	logging.Network.Info("Access point accepted the new configuration and will apply it asynchronously.")
 	return nil
 }
 
@@ -176,10 +178,10 @@ func (ap *AccessPoint) updateMonitoring() error {
 		return fmt.Errorf("failed to parse access point status: %v", err)
 	}
 	if ap.Status != apStatus.Status {
-		log.Printf("Access point status changed from %s to %s.", ap.Status, apStatus.Status)
+		logging.Network.Info("Access point status changed.", "oldStatus", ap.Status, "newStatus", apStatus.Status)
 		ap.Status = apStatus.Status
 		if ap.Status == "ACTIVE" {
-			log.Printf("Access point detailed status:\n%s", apStatus.toLogString())
+			logging.Network.Info("Access point is active.", "details", apStatus.toLogString())
 		}
 	}
 	updateTeamWifiStatus(ap.TeamWifiStatuses[0], apStatus.StationStatuses["red1"])
@@ -194,12 +196,11 @@ func (ap *AccessPoint) updateMonitoring() error {
 
 func (ap *AccessPoint) checkAndLogApiError(err error) {
 	if errors.Is(err, syscall.ECONNREFUSED) {
-		log.Printf(
-			"\x1b[31mThe access point appears to be present at %s but is refusing API connection requests. Note that "+
-				"from 2024 onwards, you must manually install the API server on the Linksys API before it can be used "+
-				"with Cheesy Arena. See https://github.com/patfair/frc-radio-api for installation instructions."+
-				"\u001B[0m",
-			ap.apiUrl,
+		logging.Network.Error(
+			"The access point appears to be present but is refusing API connection requests. Note that from 2024 "+
+				"onwards, you must manually install the API server on the Linksys API before it can be used with "+
+				"Cheesy Arena. See https://github.com/patfair/frc-radio-api for installation instructions.",
+			"url", ap.apiUrl,
 		)
 	}
 }
@@ -225,6 +226,7 @@ func updateTeamWifiStatus(teamWifiStatus *TeamWifiStatus, stationStatus *station
 		teamWifiStatus.RxRate = 0
 		teamWifiStatus.TxRate = 0
 		teamWifiStatus.SignalNoiseRatio = 0
+		teamWifiStatus.RadioMacAddress = ""
 	} else {
 		teamWifiStatus.TeamId, _ = strconv.Atoi(stationStatus.Ssid)
 		teamWifiStatus.RadioLinked = stationStatus.IsLinked
@@ -232,6 +234,15 @@ func updateTeamWifiStatus(teamWifiStatus *TeamWifiStatus, stationStatus *station
 		teamWifiStatus.RxRate = stationStatus.RxRateMbps
 		teamWifiStatus.TxRate = stationStatus.TxRateMbps
 		teamWifiStatus.SignalNoiseRatio = stationStatus.SignalNoiseRatio
+		teamWifiStatus.RadioMacAddress = ""
+		if stationStatus.IsLinked {
+			// Fall back to the address as reported if the access point sends it in an unexpected format.
+			macAddress, err := model.NormalizeRadioMacAddress(stationStatus.MacAddress)
+			if err != nil {
+				macAddress = stationStatus.MacAddress
+			}
+			teamWifiStatus.RadioMacAddress = macAddress
+		}
 	}
 }
 
@@ -89,12 +89,12 @@ func TestAccessPoint_updateMonitoring(t *testing.T) {
 		Channel: 456,
 		Status:  "ACTIVE",
 		StationStatuses: map[string]*stationStatus{
-			"red1":  {"254", "hash111", "salt1", true, 1, 2, 3, 4},
-			"red2":  {"1114", "hash222", "salt2", false, 5, 6, 7, 8},
-			"red3":  {"469", "hash333", "salt3", true, 9, 10, 11, 12},
-			"blue1": {"2046", "hash444", "salt4", false, 13, 14, 15, 16},
-			"blue2": {"2056", "hash555", "salt5", true, 17, 18, 19, 20},
-			"blue3": {"1678", "hash666", "salt6", false, 21, 22, 23, 24},
+			"red1":  {"254", "hash111", "salt1", true, 1, 2, 3, 4, "00-80-2F-00-02-54"},
+			"red2":  {"1114", "hash222", "salt2", false, 5, 6, 7, 8, ""},
+			"red3":  {"469", "hash333", "salt3", true, 9, 10, 11, 12, "00:80:2f:00:04:69"},
+			"blue1": {"2046", "hash444", "salt4", false, 13, 14, 15, 16, "00:80:2f:00:20:46"},
+			"blue2": {"2056", "hash555", "salt5", true, 17, 18, 19, 20, "bogus"},
+			"blue3": {"1678", "hash666", "salt6", false, 21, 22, 23, 24, ""},
 		},
 	}
 
@@ -110,30 +110,30 @@ func TestAccessPoint_updateMonitoring(t *testing.T) {
 	assert.Nil(t, ap.updateMonitoring())
 	assert.Equal(t, 123, ap.channel) // Should not have changed to reflect the radio API.
 	assert.Equal(t, "ACTIVE", ap.Status)
-	assert.Equal(t, TeamWifiStatus{254, true, 4, 1, 2, 3}, *wifiStatuses[0])
-	assert.Equal(t, TeamWifiStatus{1114, false, 8, 5, 6, 7}, *wifiStatuses[1])
-	assert.Equal(t, TeamWifiStatus{469, true, 12, 9, 10, 11}, *wifiStatuses[2])
-	assert.Equal(t, TeamWifiStatus{2046, false, 16, 13, 14, 15}, *wifiStatuses[3])
-	assert.Equal(t, TeamWifiStatus{2056, true, 20, 17, 18, 19}, *wifiStatuses[4])
-	assert.Equal(t, TeamWifiStatus{1678, false, 24, 21, 22, 23}, *wifiStatuses[5])
+	assert.Equal(t, TeamWifiStatus{254, true, 4, 1, 2, 3, "00:80:2f:00:02:54"}, *wifiStatuses[0])
+	assert.Equal(t, TeamWifiStatus{1114, false, 8, 5, 6, 7, ""}, *wifiStatuses[1])
+	assert.Equal(t, TeamWifiStatus{469, true, 12, 9, 10, 11, "00:80:2f:00:04:69"}, *wifiStatuses[2])
+	assert.Equal(t, TeamWifiStatus{2046, false, 16, 13, 14, 15, ""}, *wifiStatuses[3])
+	assert.Equal(t, TeamWifiStatus{2056, true, 20, 17, 18, 19, "bogus"}, *wifiStatuses[4])
+	assert.Equal(t, TeamWifiStatus{1678, false, 24, 21, 22, 23, ""}, *wifiStatuses[5])
 
 	// Only some stations assigned.
 	apStatus.Status = "CONFIGURING"
 	apStatus.StationStatuses = map[string]*stationStatus{
 		"red1":  nil,
 		"red2":  nil,
-		"red3":  {"469", "hash333", "salt3", true, 9, 10, 11, 12},
+		"red3":  {"469", "hash333", "salt3", true, 9, 10, 11, 12, "00:80:2f:00:04:69"},
 		"blue1": nil,
-		"blue2": {"2056", "hash555", "salt5", true, 17, 18, 19, 20},
+		"blue2": {"2056", "hash555", "salt5", true, 17, 18, 19, 20, "bogus"},
 		"blue3": nil,
 	}
 	assert.Nil(t, ap.updateMonitoring())
 	assert.Equal(t, "CONFIGURING", ap.Status)
 	assert.Equal(t, TeamWifiStatus{}, *wifiStatuses[0])
 	assert.Equal(t, TeamWifiStatus{}, *wifiStatuses[1])
-	assert.Equal(t, TeamWifiStatus{469, true, 12, 9, 10, 11}, *wifiStatuses[2])
+	assert.Equal(t, TeamWifiStatus{469, true, 12, 9, 10, 11, "00:80:2f:00:04:69"}, *wifiStatuses[2])
 	assert.Equal(t, TeamWifiStatus{}, *wifiStatuses[3])
-	assert.Equal(t, TeamWifiStatus{2056, true, 20, 17, 18, 19}, *wifiStatuses[4])
+	assert.Equal(t, TeamWifiStatus{2056, true, 20, 17, 18, 19, "bogus"}, *wifiStatuses[4])
 	assert.Equal(t, TeamWifiStatus{}, *wifiStatuses[5])
 
 	// Radio API returns an error.
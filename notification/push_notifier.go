@@ -0,0 +1,108 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for fanning notifications about the event out to the web push subscriptions of spectators and team members.
+
+package notification
+
+import (
+	"errors"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"slices"
+)
+
+const (
+	UpcomingMatchTopic   = "upcoming_match"
+	ScheduleChangedTopic = "schedule_changed"
+	DelayTopic           = "delay"
+)
+
+// Ordered list of the topics that a push subscription can opt into.
+var Topics = []string{UpcomingMatchTopic, ScheduleChangedTopic, DelayTopic}
+
+// Represents a notification to be pushed to the subscribers of a topic who follow any of the given teams.
+type Notification struct {
+	Topic   string
+	TeamIds []int
+	// If set, each subscriber is notified at most once about notifications having the same key.
+	Key string
+	Url string
+	// Returns the title and body of the notification, given the subset of TeamIds that the subscriber follows.
+	Message func(followedTeamIds []int) (string, string)
+}
+
+// Delivers notifications to the push subscriptions stored in the database.
+type PushNotifier struct {
+	database *model.Database
+	// Returns the VAPID private key and contact email with which to identify the arena to push services.
+	getCredentials func() (string, string, error)
+}
+
+func NewPushNotifier(database *model.Database, getCredentials func() (string, string, error)) *PushNotifier {
+	return &PushNotifier{database: database, getCredentials: getCredentials}
+}
+
+// Returns true if the given string is one of the known topics.
+func IsValidTopic(topic string) bool {
+	return slices.Contains(Topics, topic)
+}
+
+// Sends the given notification to each subscription that has opted into its topic and follows any of its teams,
+// skipping those that have already received a notification having the same key. Delivery happens asynchronously so
+// that slow push services can't hold up the caller.
+func (notifier *PushNotifier) Notify(notification Notification) {
+	pushSubscriptions, err := notifier.database.GetAllPushSubscriptions()
+	if err != nil {
+		logging.Notification.Error("Failed to get push subscriptions.", "error", err)
+		return
+	}
+	var vapidPrivateKey, contactEmail string
+	for _, pushSubscription := range pushSubscriptions {
+		if !pushSubscription.IsSubscribedTo(notification.Topic) {
+			continue
+		}
+		if notification.Key != "" && slices.Contains(pushSubscription.NotifiedKeys, notification.Key) {
+			continue
+		}
+		var followedTeamIds []int
+		for _, teamId := range notification.TeamIds {
+			if teamId > 0 && pushSubscription.IsFollowing(teamId) && !slices.Contains(followedTeamIds, teamId) {
+				followedTeamIds = append(followedTeamIds, teamId)
+			}
+		}
+		if len(followedTeamIds) == 0 {
+			continue
+		}
+
+		if vapidPrivateKey == "" {
+			if vapidPrivateKey, contactEmail, err = notifier.getCredentials(); err != nil {
+				logging.Notification.Error("Failed to get web push credentials.", "error", err)
+				return
+			}
+		}
+		if notification.Key != "" {
+			// Record the notification up front so that repeating the triggering action doesn't result in duplicates.
+			pushSubscription.NotifiedKeys = append(pushSubscription.NotifiedKeys, notification.Key)
+			if err = notifier.database.UpdatePushSubscription(&pushSubscription); err != nil {
+				logging.Notification.Error("Failed to update push subscription.", "error", err)
+				continue
+			}
+		}
+
+		title, body := notification.Message(followedTeamIds)
+		payload := partner.WebPushPayload{Title: title, Body: body, Url: notification.Url}
+		go func() {
+			err := partner.SendWebPush(&pushSubscription, vapidPrivateKey, contactEmail, payload)
+			if errors.Is(err, partner.ErrPushSubscriptionGone) {
+				// The subscriber has unsubscribed or the subscription has expired, so it won't work again.
+				if err = notifier.database.DeletePushSubscription(pushSubscription.Id); err != nil {
+					logging.Notification.Error("Failed to delete push subscription.", "error", err)
+				}
+			} else if err != nil {
+				logging.Notification.Error("Failed to send web push.", "error", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package notification
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsValidTopic(t *testing.T) {
+	assert.True(t, IsValidTopic(UpcomingMatchTopic))
+	assert.True(t, IsValidTopic(ScheduleChangedTopic))
+	assert.True(t, IsValidTopic(DelayTopic))
+	assert.False(t, IsValidTopic("blorpy"))
+	assert.False(t, IsValidTopic(""))
+}
+
+func TestPushNotifierNotify(t *testing.T) {
+	database := model.SetupTestDb(t, "notification")
+	vapidPrivateKey, _ := partner.GenerateVapidPrivateKey()
+	credentialRequests := 0
+	notifier := NewPushNotifier(database, func() (string, string, error) {
+		credentialRequests++
+		return vapidPrivateKey, "fta@example.com", nil
+	})
+
+	// Mock the push service.
+	paths := make(chan string, 10)
+	pushServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		if r.URL.Path == "/expired" {
+			http.Error(w, "Subscription has expired", 410)
+			return
+		}
+		w.WriteHeader(201)
+	}))
+	defer pushServer.Close()
+	createPushSubscription := func(path string, topics []string, teamIds ...int) {
+		privateKey, _ := ecdh.P256().GenerateKey(rand.Reader)
+		authSecret := make([]byte, 16)
+		rand.Read(authSecret)
+		database.CreatePushSubscription(&model.PushSubscription{
+			Endpoint:   pushServer.URL + path,
+			P256dhKey:  base64.RawURLEncoding.EncodeToString(privateKey.PublicKey().Bytes()),
+			AuthSecret: base64.RawURLEncoding.EncodeToString(authSecret),
+			TeamIds:    teamIds,
+			Topics:     topics,
+		})
+	}
+	createPushSubscription("/all", Topics, 254)
+	createPushSubscription("/delays", []string{DelayTopic}, 254, 1114)
+	createPushSubscription("/other", Topics, 1678)
+	createPushSubscription("/expired", Topics, 1114)
+
+	var messageTeamIds [][]int
+	newNotification := func(topic, key string) Notification {
+		return Notification{
+			Topic:   topic,
+			TeamIds: []int{254, 0, 1114, 254},
+			Key:     key,
+			Url:     "/spectator",
+			Message: func(followedTeamIds []int) (string, string) {
+				messageTeamIds = append(messageTeamIds, followedTeamIds)
+				return fmt.Sprintf("Teams %v", followedTeamIds), "Body"
+			},
+		}
+	}
+
+	// Check that only the subscriptions having the topic and following a team are notified.
+	notifier.Notify(newNotification(UpcomingMatchTopic, "upcoming_match:12"))
+	assert.Equal(t, map[string]bool{"/all": true, "/expired": true}, receivePushPaths(t, paths, 2))
+	assert.Equal(t, [][]int{{254}, {1114}}, messageTeamIds)
+	assert.Equal(t, 1, credentialRequests)
+
+	// Check that a notification having the same key isn't sent again, and that the expired subscription is gone.
+	notifier.Notify(newNotification(UpcomingMatchTopic, "upcoming_match:12"))
+	assertNoPush(t, paths)
+	pushSubscriptions, _ := database.GetAllPushSubscriptions()
+	if assert.Equal(t, 3, len(pushSubscriptions)) {
+		assert.Equal(t, []string{"upcoming_match:12"}, pushSubscriptions[0].NotifiedKeys)
+		assert.Empty(t, pushSubscriptions[1].NotifiedKeys)
+	}
+
+	// Check that notifications without a key can be repeated.
+	messageTeamIds = nil
+	notifier.Notify(newNotification(DelayTopic, ""))
+	assert.Equal(t, map[string]bool{"/all": true, "/delays": true}, receivePushPaths(t, paths, 2))
+	notifier.Notify(newNotification(DelayTopic, ""))
+	assert.Equal(t, map[string]bool{"/all": true, "/delays": true}, receivePushPaths(t, paths, 2))
+	assert.Contains(t, messageTeamIds, []int{254, 1114})
+
+	// Check that nobody is notified if nobody follows the teams.
+	notifier.Notify(Notification{Topic: ScheduleChangedTopic, TeamIds: []int{2056}})
+	assertNoPush(t, paths)
+}
+
+func receivePushPaths(t *testing.T, paths chan string, count int) map[string]bool {
+	receivedPaths := make(map[string]bool)
+	for i := 0; i < count; i++ {
+		select {
+		case path := <-paths:
+			receivedPaths[path] = true
+		case <-time.After(time.Second):
+			assert.Fail(t, "Timed out waiting for web push")
+		}
+	}
+	return receivedPaths
+}
+
+func assertNoPush(t *testing.T, paths chan string) {
+	select {
+	case path := <-paths:
+		assert.Fail(t, "Unexpected web push", path)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
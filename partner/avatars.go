@@ -0,0 +1,105 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for storing the avatar images that teams register with FIRST, which the displays show alongside the team
+// numbers.
+
+package partner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Largest avatar image that will be accepted. Official avatars are 40x40 pixels, so this leaves plenty of headroom.
+const MaxAvatarBytes = 256 * 1024
+
+// Stores the given PNG image as the avatar of the given team, replacing any existing one.
+func SaveTeamAvatar(teamId int, avatarBytes []byte) error {
+	if teamId <= 0 {
+		return fmt.Errorf("Invalid team number %d.", teamId)
+	}
+	if len(avatarBytes) > MaxAvatarBytes {
+		return fmt.Errorf("Avatar for team %d is larger than %d KB.", teamId, MaxAvatarBytes/1024)
+	}
+	if _, format, err := image.DecodeConfig(bytes.NewReader(avatarBytes)); err != nil || format != "png" {
+		return fmt.Errorf("Avatar for team %d is not a valid PNG image.", teamId)
+	}
+	if err := os.MkdirAll(AvatarsDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(getAvatarPath(teamId), avatarBytes, 0644)
+}
+
+// Removes the avatar of the given team, if it has one, so that the displays fall back to the default image.
+func DeleteTeamAvatar(teamId int) error {
+	if err := os.Remove(getAvatarPath(teamId)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Returns true if an avatar has been stored for the given team.
+func HasTeamAvatar(teamId int) bool {
+	_, err := os.Stat(getAvatarPath(teamId))
+	return err == nil
+}
+
+// Stores the avatars contained in a file saved from the FIRST Events API avatars endpoint, which can be either a single
+// page of the response or an array of pages. Returns the number of avatars stored.
+func ImportFirstAvatars(reader io.Reader) (int, error) {
+	dumpJson, err := io.ReadAll(reader)
+	if err != nil {
+		return 0, err
+	}
+	var pages []firstAvatarsPage
+	if trimmedJson := bytes.TrimSpace(dumpJson); len(trimmedJson) > 0 && trimmedJson[0] == '[' {
+		err = json.Unmarshal(trimmedJson, &pages)
+	} else {
+		pages = make([]firstAvatarsPage, 1)
+		err = json.Unmarshal(trimmedJson, &pages[0])
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse avatars file: %v", err)
+	}
+
+	// Decode and check all the avatars before storing any of them, so that a bad file doesn't leave a partial import.
+	avatars := make(map[int][]byte)
+	for _, page := range pages {
+		for _, team := range page.Teams {
+			if team.EncodedAvatar == "" {
+				continue
+			}
+			avatarBytes, err := base64.StdEncoding.DecodeString(team.EncodedAvatar)
+			if err != nil {
+				return 0, fmt.Errorf("Could not decode avatar for team %d: %v", team.TeamNumber, err)
+			}
+			if _, format, err := image.DecodeConfig(bytes.NewReader(avatarBytes)); err != nil || format != "png" {
+				return 0, fmt.Errorf("Avatar for team %d is not a valid PNG image.", team.TeamNumber)
+			}
+			avatars[team.TeamNumber] = avatarBytes
+		}
+	}
+	if len(avatars) == 0 {
+		return 0, fmt.Errorf("The avatars file doesn't contain any avatars.")
+	}
+	for teamId, avatarBytes := range avatars {
+		if err = SaveTeamAvatar(teamId, avatarBytes); err != nil {
+			return 0, err
+		}
+	}
+	return len(avatars), nil
+}
+
+// Returns the path of the file in which the avatar of the given team is stored.
+func getAvatarPath(teamId int) string {
+	return filepath.Join(AvatarsDir, strconv.Itoa(teamId)+".png")
+}
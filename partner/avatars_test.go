@@ -0,0 +1,121 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndDeleteTeamAvatar(t *testing.T) {
+	useScratchAvatarsDir(t)
+	avatar := createTestAvatar(t)
+
+	assert.False(t, HasTeamAvatar(254))
+	assert.Nil(t, SaveTeamAvatar(254, avatar))
+	assert.True(t, HasTeamAvatar(254))
+	storedAvatar, err := os.ReadFile(filepath.Join(AvatarsDir, "254.png"))
+	assert.Nil(t, err)
+	assert.Equal(t, avatar, storedAvatar)
+
+	err = SaveTeamAvatar(1114, []byte("GIF89a"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Avatar for team 1114 is not a valid PNG image.", err.Error())
+	}
+	err = SaveTeamAvatar(1114, make([]byte, MaxAvatarBytes+1))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Avatar for team 1114 is larger than 256 KB.", err.Error())
+	}
+	assert.NotNil(t, SaveTeamAvatar(0, avatar))
+	assert.False(t, HasTeamAvatar(1114))
+
+	assert.Nil(t, DeleteTeamAvatar(254))
+	assert.False(t, HasTeamAvatar(254))
+	assert.Nil(t, DeleteTeamAvatar(254))
+}
+
+func TestImportFirstAvatars(t *testing.T) {
+	useScratchAvatarsDir(t)
+	encodedAvatar := base64.StdEncoding.EncodeToString(createTestAvatar(t))
+
+	// Check a single page of the API response.
+	count, err := ImportFirstAvatars(
+		strings.NewReader(
+			fmt.Sprintf(
+				`{"teams": [{"teamNumber": 254, "encodedAvatar": "%s"}, {"teamNumber": 1114, "encodedAvatar": null}],
+				"pageCurrent": 1, "pageTotal": 1}`,
+				encodedAvatar,
+			),
+		),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, HasTeamAvatar(254))
+	assert.False(t, HasTeamAvatar(1114))
+
+	// Check an array of pages.
+	count, err = ImportFirstAvatars(
+		strings.NewReader(
+			fmt.Sprintf(
+				`[{"teams": [{"teamNumber": 846, "encodedAvatar": "%s"}]},
+				{"teams": [{"teamNumber": 8033, "encodedAvatar": "%s"}]}]`,
+				encodedAvatar,
+				encodedAvatar,
+			),
+		),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, HasTeamAvatar(846))
+	assert.True(t, HasTeamAvatar(8033))
+
+	// Check that nothing is stored if any of the avatars are invalid.
+	_, err = ImportFirstAvatars(
+		strings.NewReader(
+			fmt.Sprintf(
+				`{"teams": [{"teamNumber": 604, "encodedAvatar": "%s"},
+				{"teamNumber": 1678, "encodedAvatar": "aGVsbG8="}]}`,
+				encodedAvatar,
+			),
+		),
+	)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Avatar for team 1678 is not a valid PNG image.", err.Error())
+	}
+	assert.False(t, HasTeamAvatar(604))
+
+	_, err = ImportFirstAvatars(strings.NewReader(`{"teams": [{"teamNumber": 604, "encodedAvatar": "!!!"}]}`))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Could not decode avatar for team 604")
+	}
+	_, err = ImportFirstAvatars(strings.NewReader("teamNumber,avatar"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Could not parse avatars file")
+	}
+	_, err = ImportFirstAvatars(strings.NewReader(`{"teams": []}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The avatars file doesn't contain any avatars.", err.Error())
+	}
+}
+
+// Runs the test from a scratch directory so that the avatars don't end up in the source tree.
+func useScratchAvatarsDir(t *testing.T) {
+	workingDir, _ := os.Getwd()
+	assert.Nil(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(workingDir) })
+}
+
+func createTestAvatar(t *testing.T) []byte {
+	var buffer bytes.Buffer
+	assert.Nil(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 40, 40))))
+	return buffer.Bytes()
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for pulling the official team list for an event from the FIRST Events API.
+
+package partner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"os"
+)
+
+const firstBaseUrl = "https://frc-api.firstinspires.org"
+
+type FirstClient struct {
+	BaseUrl   string
+	username  string
+	apiKey    string
+	eventCode string
+}
+
+// Represents a team as registered for an event in the FIRST Events API.
+type FirstTeam struct {
+	TeamNumber int    `json:"teamNumber"`
+	NameFull   string `json:"nameFull"`
+	NameShort  string `json:"nameShort"`
+	City       string `json:"city"`
+	StateProv  string `json:"stateProv"`
+	Country    string `json:"country"`
+	RookieYear int    `json:"rookieYear"`
+	RobotName  string `json:"robotName"`
+	SchoolName string `json:"schoolName"`
+}
+
+type firstTeamsPage struct {
+	Teams     []FirstTeam `json:"teams"`
+	PageTotal int         `json:"pageTotal"`
+}
+
+type firstAvatarsPage struct {
+	Teams []struct {
+		TeamNumber    int    `json:"teamNumber"`
+		EncodedAvatar string `json:"encodedAvatar"`
+	} `json:"teams"`
+	PageTotal int `json:"pageTotal"`
+}
+
+func NewFirstClient(username, apiKey, eventCode string) *FirstClient {
+	return &FirstClient{BaseUrl: firstBaseUrl, username: username, apiKey: apiKey, eventCode: eventCode}
+}
+
+// Returns true if the credentials and event code needed to call the API have been configured.
+func (client *FirstClient) IsConfigured() bool {
+	return client.username != "" && client.apiKey != "" && client.eventCode != ""
+}
+
+// Returns the full list of teams registered for the event in the given season.
+func (client *FirstClient) GetEventTeams(season int) ([]FirstTeam, error) {
+	var teams []FirstTeam
+	for page := 1; ; page++ {
+		var teamsPage firstTeamsPage
+		path := fmt.Sprintf("/v3.0/%d/teams?eventCode=%s&page=%d", season, client.eventCode, page)
+		if err := client.getJson(path, &teamsPage); err != nil {
+			return nil, err
+		}
+		teams = append(teams, teamsPage.Teams...)
+		if page >= teamsPage.PageTotal {
+			return teams, nil
+		}
+	}
+}
+
+// Downloads the avatar of each team at the event in the given season that has one and stores it to disk, returning the
+// number of avatars saved.
+func (client *FirstClient) DownloadEventAvatars(season int) (int, error) {
+	count := 0
+	for page := 1; ; page++ {
+		var avatarsPage firstAvatarsPage
+		path := fmt.Sprintf("/v3.0/%d/avatars?eventCode=%s&page=%d", season, client.eventCode, page)
+		if err := client.getJson(path, &avatarsPage); err != nil {
+			return count, err
+		}
+		for _, team := range avatarsPage.Teams {
+			if team.EncodedAvatar == "" {
+				continue
+			}
+			avatarBytes, err := base64.StdEncoding.DecodeString(team.EncodedAvatar)
+			if err != nil {
+				return count, fmt.Errorf("Could not decode avatar for team %d: %v", team.TeamNumber, err)
+			}
+			avatarPath := fmt.Sprintf("%s/%d.png", AvatarsDir, team.TeamNumber)
+			if err = os.WriteFile(avatarPath, avatarBytes, 0644); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if page >= avatarsPage.PageTotal {
+			return count, nil
+		}
+	}
+}
+
+// Copies the official registration data for the given team into the given team model, leaving the fields that are
+// specific to this event untouched.
+func (firstTeam *FirstTeam) PopulateTeam(team *model.Team) {
+	team.Id = firstTeam.TeamNumber
+	team.Name = firstTeam.NameFull
+	team.Nickname = firstTeam.NameShort
+	team.City = firstTeam.City
+	team.StateProv = firstTeam.StateProv
+	team.Country = firstTeam.Country
+	team.SchoolName = firstTeam.SchoolName
+	team.RookieYear = firstTeam.RookieYear
+	if firstTeam.RobotName != "" {
+		team.RobotName = firstTeam.RobotName
+	}
+}
+
+// Sends a GET request to the given path and decodes the JSON response into the given value.
+func (client *FirstClient) getJson(path string, value any) error {
+	request, err := http.NewRequest("GET", client.BaseUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(client.username, client.apiKey)
+	request.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Error getting data from the FIRST Events API: %d, %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, value)
+}
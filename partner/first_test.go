@@ -0,0 +1,101 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFirstGetEventTeams(t *testing.T) {
+	// Mock the FIRST Events API, spreading the teams across two pages.
+	firstServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "my_username", username)
+		assert.Equal(t, "my_api_key", password)
+		assert.Equal(t, "/v3.0/2024/teams", r.URL.Path)
+		assert.Equal(t, "CASJ", r.URL.Query().Get("eventCode"))
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprintln(w, `{"teams": [{"teamNumber": 254, "nameShort": "The Cheesy Poofs", "rookieYear": 1999,
+				"schoolName": "Bellarmine College Preparatory"}], "pageCurrent": 1, "pageTotal": 2}`)
+		case "2":
+			fmt.Fprintln(w, `{"teams": [{"teamNumber": 1114, "nameShort": "Simbotics"}], "pageCurrent": 2,
+				"pageTotal": 2}`)
+		default:
+			http.Error(w, "Unexpected page", 500)
+		}
+	}))
+	defer firstServer.Close()
+	client := NewFirstClient("my_username", "my_api_key", "CASJ")
+	client.BaseUrl = firstServer.URL
+
+	teams, err := client.GetEventTeams(2024)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(teams)) {
+		assert.Equal(t, 254, teams[0].TeamNumber)
+		assert.Equal(t, "The Cheesy Poofs", teams[0].NameShort)
+		assert.Equal(t, 1114, teams[1].TeamNumber)
+	}
+
+	team := model.Team{Id: 254, RobotName: "Barrage", WpaKey: "12345678"}
+	teams[0].PopulateTeam(&team)
+	assert.Equal(t, "The Cheesy Poofs", team.Nickname)
+	assert.Equal(t, "Bellarmine College Preparatory", team.SchoolName)
+	assert.Equal(t, 1999, team.RookieYear)
+	assert.Equal(t, "Barrage", team.RobotName)
+	assert.Equal(t, "12345678", team.WpaKey)
+}
+
+func TestFirstDownloadEventAvatars(t *testing.T) {
+	// Run from a scratch directory so that the avatars don't end up in the source tree.
+	workingDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(tempDir, AvatarsDir), 0755))
+	assert.Nil(t, os.Chdir(tempDir))
+	defer os.Chdir(workingDir)
+
+	firstServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3.0/2024/avatars", r.URL.Path)
+		fmt.Fprintln(w, `{"teams": [{"teamNumber": 254, "encodedAvatar": "aGVsbG8="},
+			{"teamNumber": 1114, "encodedAvatar": null}], "pageCurrent": 1, "pageTotal": 1}`)
+	}))
+	defer firstServer.Close()
+	client := NewFirstClient("my_username", "my_api_key", "CASJ")
+	client.BaseUrl = firstServer.URL
+
+	count, err := client.DownloadEventAvatars(2024)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	avatar, err := os.ReadFile(filepath.Join(AvatarsDir, "254.png"))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(avatar))
+	_, err = os.Stat(filepath.Join(AvatarsDir, "1114.png"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFirstErrors(t *testing.T) {
+	firstServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", 401)
+	}))
+	defer firstServer.Close()
+	client := NewFirstClient("my_username", "wrong_key", "CASJ")
+	client.BaseUrl = firstServer.URL
+
+	assert.True(t, client.IsConfigured())
+	assert.False(t, NewFirstClient("", "", "CASJ").IsConfigured())
+	_, err := client.GetEventTeams(2024)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "401")
+	}
+	_, err = client.DownloadEventAvatars(2024)
+	assert.NotNil(t, err)
+}
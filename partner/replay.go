@@ -0,0 +1,97 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for telling an external instant replay system to mark a clip, over either UDP or HTTP depending on what the
+// replay system listens for.
+
+package partner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const replayTimeout = 2 * time.Second
+
+type ReplayClient struct {
+	address string
+}
+
+// Represents the JSON command sent to the replay system, asking it to mark the clip of the given duration that ends at
+// the given time.
+type ReplayCommand struct {
+	Command     string `json:"command"`
+	Timestamp   int64  `json:"timestamp"` // In milliseconds since the Unix epoch.
+	DurationSec int    `json:"durationSec"`
+	Match       string `json:"match"`
+}
+
+func NewReplayClient(address string) *ReplayClient {
+	return &ReplayClient{address: address}
+}
+
+// Returns an error if the given address isn't of the form udp://host:port or an HTTP(S) URL.
+func ValidateReplayAddress(address string) error {
+	replayUrl, err := url.Parse(address)
+	if err != nil || replayUrl.Host == "" {
+		return fmt.Errorf(
+			"Replay system address '%s' must be of the form udp://host:port or http://host/path.", address,
+		)
+	}
+	switch replayUrl.Scheme {
+	case "udp":
+		if replayUrl.Port() == "" {
+			return fmt.Errorf("Replay system address '%s' is missing a port.", address)
+		}
+	case "http", "https":
+	default:
+		return fmt.Errorf(
+			"Replay system address '%s' must be of the form udp://host:port or http://host/path.", address,
+		)
+	}
+	return nil
+}
+
+// Sends a command to the replay system to mark the clip of the given duration leading up to the given time.
+func (client *ReplayClient) MarkReplay(timestamp time.Time, durationSec int, matchName string) error {
+	if err := ValidateReplayAddress(client.address); err != nil {
+		return err
+	}
+	body, err := json.Marshal(ReplayCommand{
+		Command: "markReplay", Timestamp: timestamp.UnixMilli(), DurationSec: durationSec, Match: matchName,
+	})
+	if err != nil {
+		return err
+	}
+
+	replayUrl, _ := url.Parse(client.address)
+	if replayUrl.Scheme == "udp" {
+		conn, err := net.DialTimeout("udp", replayUrl.Host, replayTimeout)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = conn.Write(body)
+		return err
+	}
+
+	httpClient := &http.Client{Timeout: replayTimeout}
+	response, err := httpClient.Post(client.address, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return fmt.Errorf(
+			"Error marking replay at %s: %d, %s", client.address, response.StatusCode, string(responseBody),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateReplayAddress(t *testing.T) {
+	assert.Nil(t, ValidateReplayAddress("udp://10.0.100.20:9000"))
+	assert.Nil(t, ValidateReplayAddress("http://10.0.100.20/replay"))
+	assert.Nil(t, ValidateReplayAddress("https://replay.example.com/api/mark"))
+	assert.NotNil(t, ValidateReplayAddress(""))
+	assert.NotNil(t, ValidateReplayAddress("10.0.100.20:9000"))
+	assert.NotNil(t, ValidateReplayAddress("udp://10.0.100.20"))
+	assert.NotNil(t, ValidateReplayAddress("tcp://10.0.100.20:9000"))
+}
+
+func TestMarkReplayHttp(t *testing.T) {
+	// Mock the replay system.
+	var command ReplayCommand
+	replayServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "Replay system is down", 503)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &command)
+	}))
+	defer replayServer.Close()
+
+	client := NewReplayClient(replayServer.URL + "/mark")
+	assert.Nil(t, client.MarkReplay(time.UnixMilli(1700000000123), 12, "Q12"))
+	assert.Equal(
+		t, ReplayCommand{Command: "markReplay", Timestamp: 1700000000123, DurationSec: 12, Match: "Q12"}, command,
+	)
+
+	client = NewReplayClient(replayServer.URL + "/broken")
+	err := client.MarkReplay(time.Now(), 12, "Q12")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "503, Replay system is down")
+	}
+}
+
+func TestMarkReplayUdp(t *testing.T) {
+	// Mock the replay system.
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	client := NewReplayClient("udp://" + listener.LocalAddr().String())
+	assert.Nil(t, client.MarkReplay(time.UnixMilli(1700000000123), 8, "F1"))
+	buffer := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	if assert.Nil(t, err) {
+		var command ReplayCommand
+		assert.Nil(t, json.Unmarshal(buffer[:n], &command))
+		assert.Equal(
+			t, ReplayCommand{Command: "markReplay", Timestamp: 1700000000123, DurationSec: 8, Match: "F1"}, command,
+		)
+	}
+
+	err = NewReplayClient("udp://localhost").MarkReplay(time.Now(), 8, "F1")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "missing a port")
+	}
+}
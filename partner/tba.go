@@ -17,7 +17,10 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -28,6 +31,7 @@ const (
 
 type TbaClient struct {
 	BaseUrl         string
+	Location        *time.Location // Time zone in which the match times are published.
 	eventCode       string
 	secretId        string
 	secret          string
@@ -117,6 +121,11 @@ type TbaRankings struct {
 	Rankings   []TbaRanking `json:"rankings"`
 }
 
+type TbaEventAlliance struct {
+	Name  string   `json:"name"`
+	Picks []string `json:"picks"`
+}
+
 type TbaTeam struct {
 	TeamNumber int    `json:"team_number"`
 	Name       string `json:"name"`
@@ -164,8 +173,8 @@ var endGameStatusMapping = map[game.EndgameStatus]string{
 }
 
 func NewTbaClient(eventCode, secretId, secret string) *TbaClient {
-	return &TbaClient{BaseUrl: tbaBaseUrl, eventCode: eventCode, secretId: secretId, secret: secret,
-		eventNamesCache: make(map[string]string)}
+	return &TbaClient{BaseUrl: tbaBaseUrl, Location: time.Local, eventCode: eventCode, secretId: secretId,
+		secret: secret, eventNamesCache: make(map[string]string)}
 }
 
 func (client *TbaClient) GetTeam(teamNumber int) (*TbaTeam, error) {
@@ -332,7 +341,13 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 	if err != nil {
 		return err
 	}
-	matches := append(qualMatches, playoffMatches...)
+	var matches []model.Match
+	for _, match := range append(qualMatches, playoffMatches...) {
+		// Replayed matches share the TBA key of their replay, which supersedes them.
+		if !match.IsReplaced() {
+			matches = append(matches, match)
+		}
+	}
 	tbaMatches := make([]TbaMatch, len(matches))
 
 	// Build a JSON array of TBA-format matches.
@@ -370,7 +385,7 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 			MatchNumber:    match.TbaMatchKey.MatchNumber,
 			Alliances:      alliances,
 			ScoreBreakdown: scoreBreakdown,
-			TimeString:     match.Time.Local().Format("3:04 PM"),
+			TimeString:     match.Time.In(client.Location).Format("3:04 PM"),
 			TimeUtc:        match.Time.UTC().Format("2006-01-02T15:04:05"),
 		}
 	}
@@ -530,6 +545,79 @@ func (client *TbaClient) DeletePublishedMatches() error {
 	return nil
 }
 
+// Returns the team numbers of the given TBA event's qualification rankings, in rank order.
+func (client *TbaClient) GetEventRankings(eventKey string) ([]int, error) {
+	path := fmt.Sprintf("/api/v3/event/%s/rankings", eventKey)
+	resp, err := client.getRequest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the response and handle errors
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Got status code %d from TBA: %s", resp.StatusCode, body)
+	}
+
+	var rankings TbaRankings
+	err = json.Unmarshal(body, &rankings)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rankings.Rankings, func(i, j int) bool {
+		return rankings.Rankings[i].Rank < rankings.Rankings[j].Rank
+	})
+
+	teamIds := make([]int, len(rankings.Rankings))
+	for i, ranking := range rankings.Rankings {
+		if teamIds[i], err = parseTbaTeam(ranking.TeamKey); err != nil {
+			return nil, err
+		}
+	}
+	return teamIds, nil
+}
+
+// Returns the team numbers of each of the given TBA event's playoff alliances, in pick order.
+func (client *TbaClient) GetEventAlliances(eventKey string) ([][]int, error) {
+	path := fmt.Sprintf("/api/v3/event/%s/alliances", eventKey)
+	resp, err := client.getRequest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the response and handle errors
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Got status code %d from TBA: %s", resp.StatusCode, body)
+	}
+
+	var tbaAlliances []TbaEventAlliance
+	err = json.Unmarshal(body, &tbaAlliances)
+	if err != nil {
+		return nil, err
+	}
+
+	alliances := make([][]int, len(tbaAlliances))
+	for i, tbaAlliance := range tbaAlliances {
+		for _, teamKey := range tbaAlliance.Picks {
+			teamId, err := parseTbaTeam(teamKey)
+			if err != nil {
+				return nil, err
+			}
+			alliances[i] = append(alliances[i], teamId)
+		}
+	}
+	return alliances, nil
+}
+
 func (client *TbaClient) getEventName(eventCode string) (string, error) {
 	path := fmt.Sprintf("/api/v3/event/%s", eventCode)
 	resp, err := client.getRequest(path)
@@ -558,6 +646,15 @@ func getTbaTeam(team int) string {
 	return fmt.Sprintf("frc%d", team)
 }
 
+// Converts a team key in the "frcXXXX" format TBA uses into an integer team number.
+func parseTbaTeam(teamKey string) (int, error) {
+	teamId, err := strconv.Atoi(strings.TrimPrefix(teamKey, "frc"))
+	if err != nil {
+		return 0, fmt.Errorf("Invalid TBA team key %q", teamKey)
+	}
+	return teamId, nil
+}
+
 // Sends a GET request to the TBA API.
 func (client *TbaClient) getRequest(path string) (*http.Response, error) {
 	url := client.BaseUrl + path
@@ -0,0 +1,233 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Queue for publishing event data to The Blue Alliance in the background, retrying with backoff so that flaky venue
+// internet doesn't result in data silently going missing.
+
+package partner
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	TbaPublishTeams     = "teams"
+	TbaPublishMatches   = "matches"
+	TbaPublishRankings  = "rankings"
+	TbaPublishAlliances = "alliances"
+	TbaPublishAwards    = "awards"
+
+	tbaPublishInitialBackoff = 5 * time.Second
+	tbaPublishMaxBackoff     = 5 * time.Minute
+)
+
+var ErrTbaPublishingDisabled = errors.New("TBA publishing is not enabled")
+
+// Ordered list of the kinds of data that can be published, which is also the order in which simultaneously queued kinds
+// are published.
+var TbaPublishKinds = []string{
+	TbaPublishTeams, TbaPublishMatches, TbaPublishRankings, TbaPublishAlliances, TbaPublishAwards,
+}
+
+// Represents the publishing state of one kind of data.
+type TbaPublishStatus struct {
+	Kind            string
+	Pending         bool
+	InProgress      bool
+	FailedAttempts  int // Number of consecutive failures since the last success.
+	LastAttemptTime time.Time
+	LastSuccessTime time.Time
+	LastError       string
+	NextAttemptTime time.Time
+}
+
+// Publishes queued kinds of data to The Blue Alliance one at a time, retrying any that fail.
+type TbaPublisher struct {
+	database *model.Database
+	// Returns the client to publish with, or nil if publishing is disabled.
+	getClient func() *TbaClient
+	statuses  map[string]*TbaPublishStatus
+	running   bool
+	stopped   bool
+	wake      chan struct{}
+	mutex     sync.Mutex
+}
+
+func NewTbaPublisher(database *model.Database, getClient func() *TbaClient) *TbaPublisher {
+	publisher := &TbaPublisher{
+		database:  database,
+		getClient: getClient,
+		statuses:  make(map[string]*TbaPublishStatus),
+		wake:      make(chan struct{}, 1),
+	}
+	for _, kind := range TbaPublishKinds {
+		publisher.statuses[kind] = &TbaPublishStatus{Kind: kind}
+	}
+	return publisher
+}
+
+// Returns true if the given string is one of the known kinds of data.
+func IsValidTbaPublishKind(kind string) bool {
+	return slices.Contains(TbaPublishKinds, kind)
+}
+
+// Queues the given kinds of data to be published in the background as soon as possible.
+func (publisher *TbaPublisher) Enqueue(kinds ...string) {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	if publisher.stopped {
+		return
+	}
+	for _, kind := range kinds {
+		status, ok := publisher.statuses[kind]
+		if !ok {
+			continue
+		}
+		// Queueing fresh data supersedes any backoff from earlier failures.
+		status.Pending = true
+		status.NextAttemptTime = time.Now()
+	}
+
+	if publisher.running {
+		select {
+		case publisher.wake <- struct{}{}:
+		default:
+		}
+	} else {
+		publisher.running = true
+		go publisher.run()
+	}
+}
+
+// Publishes the given kind of data immediately and returns the result, removing it from the queue if successful.
+func (publisher *TbaPublisher) Publish(kind string) error {
+	if !IsValidTbaPublishKind(kind) {
+		return fmt.Errorf("invalid TBA publish kind '%s'", kind)
+	}
+	err := publisher.publish(kind)
+	publisher.mutex.Lock()
+	publisher.recordAttempt(kind, err)
+	publisher.mutex.Unlock()
+	return err
+}
+
+// Returns a copy of the publishing state of each kind of data, in order.
+func (publisher *TbaPublisher) Statuses() []TbaPublishStatus {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	statuses := make([]TbaPublishStatus, len(TbaPublishKinds))
+	for i, kind := range TbaPublishKinds {
+		statuses[i] = *publisher.statuses[kind]
+	}
+	return statuses
+}
+
+// Abandons any queued publishing, for use once the event database that the data would come from has been closed.
+func (publisher *TbaPublisher) Stop() {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	publisher.stopped = true
+	for _, status := range publisher.statuses {
+		status.Pending = false
+	}
+	select {
+	case publisher.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Works through the queue until it is empty, waiting out the backoff of any kinds that have failed.
+func (publisher *TbaPublisher) run() {
+	for {
+		publisher.mutex.Lock()
+		var next *TbaPublishStatus
+		for _, kind := range TbaPublishKinds {
+			status := publisher.statuses[kind]
+			if status.Pending && (next == nil || status.NextAttemptTime.Before(next.NextAttemptTime)) {
+				next = status
+			}
+		}
+		if next == nil {
+			publisher.running = false
+			publisher.mutex.Unlock()
+			return
+		}
+		if wait := time.Until(next.NextAttemptTime); wait > 0 {
+			publisher.mutex.Unlock()
+			select {
+			case <-time.After(wait):
+			case <-publisher.wake:
+			}
+			continue
+		}
+		kind := next.Kind
+		next.Pending = false
+		next.InProgress = true
+		publisher.mutex.Unlock()
+
+		err := publisher.publish(kind)
+		if err != nil {
+			logging.Partner.Error("Failed to publish to TBA.", "kind", kind, "error", err)
+		}
+
+		publisher.mutex.Lock()
+		next.InProgress = false
+		publisher.recordAttempt(kind, err)
+		// There is no point retrying if publishing has since been turned off.
+		if err != nil && !next.Pending && !publisher.stopped && !errors.Is(err, ErrTbaPublishingDisabled) {
+			next.Pending = true
+			next.NextAttemptTime = time.Now().Add(tbaPublishBackoff(next.FailedAttempts))
+		}
+		publisher.mutex.Unlock()
+	}
+}
+
+// Updates the status of the given kind with the result of an attempt to publish it. Must be called with the mutex held.
+func (publisher *TbaPublisher) recordAttempt(kind string, err error) {
+	status := publisher.statuses[kind]
+	status.LastAttemptTime = time.Now()
+	if err == nil {
+		status.Pending = false
+		status.FailedAttempts = 0
+		status.LastSuccessTime = status.LastAttemptTime
+		status.LastError = ""
+	} else {
+		status.FailedAttempts++
+		status.LastError = err.Error()
+	}
+}
+
+func (publisher *TbaPublisher) publish(kind string) error {
+	client := publisher.getClient()
+	if client == nil {
+		return ErrTbaPublishingDisabled
+	}
+	switch kind {
+	case TbaPublishTeams:
+		return client.PublishTeams(publisher.database)
+	case TbaPublishMatches:
+		return client.PublishMatches(publisher.database)
+	case TbaPublishRankings:
+		return client.PublishRankings(publisher.database)
+	case TbaPublishAlliances:
+		return client.PublishAlliances(publisher.database)
+	case TbaPublishAwards:
+		return client.PublishAwards(publisher.database)
+	}
+	return fmt.Errorf("invalid TBA publish kind '%s'", kind)
+}
+
+// Returns how long to wait before retrying after the given number of consecutive failures.
+func tbaPublishBackoff(failedAttempts int) time.Duration {
+	backoff := tbaPublishInitialBackoff
+	for i := 1; i < failedAttempts && backoff < tbaPublishMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, tbaPublishMaxBackoff)
+}
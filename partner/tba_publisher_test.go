@@ -0,0 +1,119 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTbaPublisherRetries(t *testing.T) {
+	database := setupTestDb(t)
+	database.CreateTeam(&model.Team{Id: 254})
+
+	// Mock a TBA server that is unreachable until told otherwise.
+	var online atomic.Bool
+	var requests atomic.Int32
+	tbaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !online.Load() {
+			http.Error(w, "venue internet is down", 503)
+		}
+	}))
+	defer tbaServer.Close()
+	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
+	client.BaseUrl = tbaServer.URL
+	publisher := NewTbaPublisher(database, func() *TbaClient { return client })
+
+	publisher.Enqueue(TbaPublishTeams)
+	assert.Eventually(t, func() bool { return publisher.Statuses()[0].FailedAttempts == 1 }, time.Second, time.Millisecond)
+	status := publisher.Statuses()[0]
+	assert.Equal(t, TbaPublishTeams, status.Kind)
+	assert.True(t, status.Pending)
+	assert.Contains(t, status.LastError, "venue internet is down")
+	assert.True(t, status.LastSuccessTime.IsZero())
+	assert.True(t, status.NextAttemptTime.After(time.Now().Add(tbaPublishInitialBackoff-time.Second)))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Queueing the data again should skip the backoff.
+	online.Store(true)
+	publisher.Enqueue(TbaPublishTeams)
+	assert.Eventually(t, func() bool { return !publisher.Statuses()[0].LastSuccessTime.IsZero() }, time.Second,
+		time.Millisecond)
+	status = publisher.Statuses()[0]
+	assert.False(t, status.Pending)
+	assert.Equal(t, 0, status.FailedAttempts)
+	assert.Equal(t, "", status.LastError)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Other kinds should be unaffected.
+	for _, status := range publisher.Statuses()[1:] {
+		assert.False(t, status.Pending)
+		assert.True(t, status.LastAttemptTime.IsZero())
+	}
+
+	// Stopping the publisher should abandon the retries and ignore anything queued afterward.
+	online.Store(false)
+	publisher.Enqueue(TbaPublishTeams)
+	assert.Eventually(t, func() bool { return publisher.Statuses()[0].FailedAttempts == 1 }, time.Second, time.Millisecond)
+	publisher.Stop()
+	publisher.Enqueue(TbaPublishMatches)
+	for _, status := range publisher.Statuses() {
+		assert.False(t, status.Pending)
+	}
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestTbaPublisherPublish(t *testing.T) {
+	database := setupTestDb(t)
+	tbaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oh noes", 500)
+	}))
+	defer tbaServer.Close()
+	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
+	client.BaseUrl = tbaServer.URL
+	enabled := true
+	publisher := NewTbaPublisher(database, func() *TbaClient {
+		if enabled {
+			return client
+		}
+		return nil
+	})
+
+	err := publisher.Publish(TbaPublishRankings)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "oh noes")
+	}
+	status := publisher.Statuses()[2]
+	assert.Equal(t, TbaPublishRankings, status.Kind)
+	assert.Equal(t, 1, status.FailedAttempts)
+	assert.False(t, status.Pending)
+
+	err = publisher.Publish("blorpy")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid TBA publish kind")
+	}
+
+	// Queued data shouldn't be retried once publishing is disabled.
+	enabled = false
+	publisher.Enqueue(TbaPublishAwards)
+	assert.Eventually(t, func() bool { return publisher.Statuses()[4].FailedAttempts == 1 }, time.Second,
+		time.Millisecond)
+	assert.Equal(t, ErrTbaPublishingDisabled.Error(), publisher.Statuses()[4].LastError)
+	assert.False(t, publisher.Statuses()[4].Pending)
+}
+
+func TestTbaPublishBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, tbaPublishBackoff(1))
+	assert.Equal(t, 10*time.Second, tbaPublishBackoff(2))
+	assert.Equal(t, 20*time.Second, tbaPublishBackoff(3))
+	assert.Equal(t, 160*time.Second, tbaPublishBackoff(6))
+	assert.Equal(t, 5*time.Minute, tbaPublishBackoff(7))
+	assert.Equal(t, 5*time.Minute, tbaPublishBackoff(100))
+}
@@ -172,3 +172,42 @@ func TestPublishAwards(t *testing.T) {
 func setupTestDb(t *testing.T) *model.Database {
 	return model.SetupTestDb(t, "partner")
 }
+
+func TestGetEventRankings(t *testing.T) {
+	// Mock the TBA server.
+	tbaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/event/2024cmptx/rankings", r.URL.String())
+		w.Write([]byte(`{"rankings":[{"rank":2,"team_key":"frc1114"},{"rank":1,"team_key":"frc254"}]}`))
+	}))
+	defer tbaServer.Close()
+	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
+	client.BaseUrl = tbaServer.URL
+
+	teamIds, err := client.GetEventRankings("2024cmptx")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{254, 1114}, teamIds)
+}
+
+func TestGetEventAlliances(t *testing.T) {
+	// Mock the TBA server.
+	tbaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.String(), "2024cmptx") {
+			w.Write([]byte(`[{"name":"Alliance 1","picks":["frc254","frc1114","frc2056"]},` +
+				`{"name":"Alliance 2","picks":["frc1503","frc148"]}]`))
+		} else {
+			w.WriteHeader(404)
+		}
+	}))
+	defer tbaServer.Close()
+	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
+	client.BaseUrl = tbaServer.URL
+
+	alliances, err := client.GetEventAlliances("2024cmptx")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{254, 1114, 2056}, {1503, 148}}, alliances)
+
+	_, err = client.GetEventAlliances("2024nope")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Got status code 404 from TBA")
+	}
+}
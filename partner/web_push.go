@@ -0,0 +1,235 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for sending web push notifications to spectators' browsers, encrypted per RFC 8291 and authenticated using
+// VAPID per RFC 8292.
+
+package partner
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	webPushTimeout          = 5 * time.Second
+	webPushTtlSec           = 300
+	webPushRecordSize       = 4096
+	webPushVapidValidityHrs = 12
+)
+
+// Returned when the push service reports that the subscription no longer exists and should be discarded.
+var ErrPushSubscriptionGone = errors.New("push subscription has expired or been unsubscribed")
+
+// Represents the JSON body of a web push notification, as shown by the spectator site's service worker.
+type WebPushPayload struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Url   string `json:"url"`
+}
+
+// Generates a new VAPID key pair for identifying the arena to push services, and returns the private key in the
+// encoded form that is stored in the event settings.
+func GenerateVapidPrivateKey() (string, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// Returns the public half of the given VAPID private key, in the URL-safe base64 form that browsers expect as the
+// application server key when subscribing.
+func GetVapidPublicKey(vapidPrivateKey string) (string, error) {
+	privateKey, err := parseVapidPrivateKey(vapidPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := privateKey.PublicKey.ECDH()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(publicKey.Bytes()), nil
+}
+
+// Encrypts the given payload for the subscription's browser and delivers it via the subscription's push service.
+// Returns ErrPushSubscriptionGone if the push service no longer recognizes the subscription.
+func SendWebPush(
+	subscription *model.PushSubscription, vapidPrivateKey, contactEmail string, payload WebPushPayload,
+) error {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	body, err := encryptWebPushPayload(subscription, plaintext)
+	if err != nil {
+		return err
+	}
+	authorization, err := getVapidAuthorization(subscription.Endpoint, vapidPrivateKey, contactEmail)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", fmt.Sprintf("%d", webPushTtlSec))
+	request.Header.Set("Urgency", "high")
+	httpClient := &http.Client{Timeout: webPushTimeout}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone {
+		return ErrPushSubscriptionGone
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("Error sending web push to %s: %d, %s", subscription.Endpoint, response.StatusCode,
+			string(responseBody))
+	}
+	return nil
+}
+
+// Encrypts the given plaintext for the subscription's browser using the aes128gcm content encoding, returning the
+// full request body including the header that the browser needs to derive the decryption key.
+func encryptWebPushPayload(subscription *model.PushSubscription, plaintext []byte) ([]byte, error) {
+	userAgentPublicKeyBytes, err := base64.RawURLEncoding.DecodeString(subscription.P256dhKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push subscription key: %v", err)
+	}
+	userAgentPublicKey, err := ecdh.P256().NewPublicKey(userAgentPublicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push subscription key: %v", err)
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(subscription.AuthSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push subscription auth secret: %v", err)
+	}
+
+	// Derive the content encryption key and nonce from a shared secret with a fresh key pair of our own.
+	privateKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := privateKey.ECDH(userAgentPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyBytes := privateKey.PublicKey().Bytes()
+	keyInfo := append([]byte("WebPush: info\x00"), userAgentPublicKeyBytes...)
+	keyInfo = append(keyInfo, publicKeyBytes...)
+	inputKeyMaterial := hkdf(authSecret, sharedSecret, keyInfo, 32)
+	salt := make([]byte, 16)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+	contentEncryptionKey := hkdf(salt, inputKeyMaterial, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, inputKeyMaterial, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(contentEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The whole payload goes in a single record, which is terminated by the delimiter that marks it as the last one.
+	record := append(plaintext, 0x02)
+	var body bytes.Buffer
+	body.Write(salt)
+	binary.Write(&body, binary.BigEndian, uint32(webPushRecordSize))
+	body.WriteByte(byte(len(publicKeyBytes)))
+	body.Write(publicKeyBytes)
+	body.Write(gcm.Seal(nil, nonce, record, nil))
+	return body.Bytes(), nil
+}
+
+// Returns the value of the Authorization header identifying the arena to the push service of the given endpoint.
+func getVapidAuthorization(endpoint, vapidPrivateKey, contactEmail string) (string, error) {
+	privateKey, err := parseVapidPrivateKey(vapidPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := GetVapidPublicKey(vapidPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]any{
+		"aud": endpointUrl.Scheme + "://" + endpointUrl.Host,
+		"exp": time.Now().Add(webPushVapidValidityHrs * time.Hour).Unix(),
+	}
+	if contactEmail != "" {
+		claims["sub"] = "mailto:" + contactEmail
+	}
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsignedToken := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJson)
+	hash := sha256.Sum256([]byte(unsignedToken))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	// ES256 signatures are the two 32-byte integers concatenated, rather than the ASN.1 form that Go produces.
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	token := unsignedToken + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return fmt.Sprintf("vapid t=%s, k=%s", token, publicKey), nil
+}
+
+func parseVapidPrivateKey(vapidPrivateKey string) (*ecdsa.PrivateKey, error) {
+	der, err := base64.StdEncoding.DecodeString(vapidPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %v", err)
+	}
+	return x509.ParseECPrivateKey(der)
+}
+
+// Derives a key of the given length (at most 32 bytes) from the given input key material using HKDF-SHA256.
+func hkdf(salt, inputKeyMaterial, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(inputKeyMaterial)
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+	return expand.Sum(nil)[:length]
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendWebPush(t *testing.T) {
+	vapidPrivateKey, err := GenerateVapidPrivateKey()
+	assert.Nil(t, err)
+	vapidPublicKey, err := GetVapidPublicKey(vapidPrivateKey)
+	assert.Nil(t, err)
+
+	// Act as the browser, with its own key pair and auth secret.
+	userAgentPrivateKey, _ := ecdh.P256().GenerateKey(rand.Reader)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	// Mock the push service.
+	var receivedBody []byte
+	var receivedAuthorization string
+	pushServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/expired" {
+			http.Error(w, "Subscription has expired", 410)
+			return
+		}
+		assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "300", r.Header.Get("TTL"))
+		receivedBody, _ = io.ReadAll(r.Body)
+		receivedAuthorization = r.Header.Get("Authorization")
+		w.WriteHeader(201)
+	}))
+	defer pushServer.Close()
+
+	subscription := model.PushSubscription{
+		Endpoint:   pushServer.URL + "/push/abc",
+		P256dhKey:  base64.RawURLEncoding.EncodeToString(userAgentPrivateKey.PublicKey().Bytes()),
+		AuthSecret: base64.RawURLEncoding.EncodeToString(authSecret),
+	}
+	payload := WebPushPayload{Title: "Team 254", Body: "Q12 is 3 matches away.", Url: "/spectator"}
+	assert.Nil(t, SendWebPush(&subscription, vapidPrivateKey, "fta@example.com", payload))
+
+	// Check that the browser would be able to decrypt the payload.
+	plaintext := decryptWebPushPayload(t, receivedBody, userAgentPrivateKey, authSecret)
+	var receivedPayload WebPushPayload
+	assert.Nil(t, json.Unmarshal(plaintext, &receivedPayload))
+	assert.Equal(t, payload, receivedPayload)
+
+	// Check that the push service would be able to verify the VAPID token.
+	assert.True(t, strings.HasPrefix(receivedAuthorization, "vapid t="))
+	assert.True(t, strings.HasSuffix(receivedAuthorization, ", k="+vapidPublicKey))
+	token := strings.TrimSuffix(strings.TrimPrefix(receivedAuthorization, "vapid t="), ", k="+vapidPublicKey)
+	tokenParts := strings.Split(token, ".")
+	if assert.Equal(t, 3, len(tokenParts)) {
+		claimsJson, _ := base64.RawURLEncoding.DecodeString(tokenParts[1])
+		var claims map[string]any
+		assert.Nil(t, json.Unmarshal(claimsJson, &claims))
+		assert.Equal(t, pushServer.URL, claims["aud"])
+		assert.Equal(t, "mailto:fta@example.com", claims["sub"])
+		signature, _ := base64.RawURLEncoding.DecodeString(tokenParts[2])
+		privateKey, _ := parseVapidPrivateKey(vapidPrivateKey)
+		hash := sha256.Sum256([]byte(tokenParts[0] + "." + tokenParts[1]))
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		assert.True(t, ecdsa.Verify(&privateKey.PublicKey, hash[:], r, s))
+	}
+
+	subscription.Endpoint = pushServer.URL + "/expired"
+	assert.Equal(t, ErrPushSubscriptionGone, SendWebPush(&subscription, vapidPrivateKey, "", payload))
+	subscription.P256dhKey = "invalid"
+	assert.NotNil(t, SendWebPush(&subscription, vapidPrivateKey, "", payload))
+}
+
+func TestHkdf(t *testing.T) {
+	// Known value from RFC 5869 test case 1.
+	inputKeyMaterial := make([]byte, 22)
+	for i := range inputKeyMaterial {
+		inputKeyMaterial[i] = 0x0b
+	}
+	salt := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+	info := []byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9}
+	assert.Equal(
+		t,
+		"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4",
+		hex.EncodeToString(hkdf(salt, inputKeyMaterial, info, 30)),
+	)
+}
+
+// Decrypts the given aes128gcm request body as the browser holding the given subscription keys would.
+func decryptWebPushPayload(t *testing.T, body []byte, privateKey *ecdh.PrivateKey, authSecret []byte) []byte {
+	salt := body[:16]
+	assert.Equal(t, uint32(4096), binary.BigEndian.Uint32(body[16:20]))
+	keyIdLength := int(body[20])
+	serverPublicKeyBytes := body[21 : 21+keyIdLength]
+	serverPublicKey, err := ecdh.P256().NewPublicKey(serverPublicKeyBytes)
+	assert.Nil(t, err)
+	sharedSecret, _ := privateKey.ECDH(serverPublicKey)
+	keyInfo := append([]byte("WebPush: info\x00"), privateKey.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, serverPublicKeyBytes...)
+	inputKeyMaterial := hkdf(authSecret, sharedSecret, keyInfo, 32)
+	contentEncryptionKey := hkdf(salt, inputKeyMaterial, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, inputKeyMaterial, []byte("Content-Encoding: nonce\x00"), 12)
+	block, _ := aes.NewCipher(contentEncryptionKey)
+	gcm, _ := cipher.NewGCM(block)
+	record, err := gcm.Open(nil, nonce, body[21+keyIdLength:], nil)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x02), record[len(record)-1])
+	return record[:len(record)-1]
+}
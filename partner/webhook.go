@@ -0,0 +1,74 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for delivering arena events to outgoing webhooks, such as venue A/V systems and chat bots.
+
+package partner
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	WebhookMatchStarted    = "match_started"
+	WebhookScoreCommitted  = "score_committed"
+	WebhookScheduleChanged = "schedule_changed"
+	webhookSignatureHeader = "X-Cheesy-Arena-Signature"
+	webhookTimeout         = 5 * time.Second
+)
+
+// Ordered list of the events that a webhook can subscribe to.
+var WebhookEvents = []string{WebhookMatchStarted, WebhookScoreCommitted, WebhookScheduleChanged}
+
+// Represents the JSON body that is posted to a webhook.
+type WebhookPayload struct {
+	Event     string `json:"event"`
+	Timestamp int64  `json:"timestamp"`
+	Data      any    `json:"data"`
+}
+
+// Posts the given event and its data to the webhook, signing the body with the webhook's secret if it has one so that
+// the receiver can verify that it came from the arena.
+func SendWebhook(webhook *model.Webhook, event string, data any) error {
+	body, err := json.Marshal(WebhookPayload{Event: event, Timestamp: time.Now().Unix(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		request.Header.Set(webhookSignatureHeader, "sha256="+SignWebhookBody(webhook.Secret, body))
+	}
+	httpClient := &http.Client{Timeout: webhookTimeout}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("Error sending %s webhook to %s: %d, %s", event, webhook.Url, response.StatusCode,
+			string(responseBody))
+	}
+	return nil
+}
+
+// Returns the hex-encoded HMAC-SHA256 of the given body using the given secret.
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
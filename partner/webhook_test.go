@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package partner
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendWebhook(t *testing.T) {
+	// Mock the webhook receiver.
+	var receivedSignature string
+	var receivedPayload map[string]any
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "Receiver is down", 503)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		receivedSignature = r.Header.Get("X-Cheesy-Arena-Signature")
+		json.Unmarshal(body, &receivedPayload)
+		if receivedSignature != "" {
+			assert.Equal(t, "sha256="+SignWebhookBody("abc123", body), receivedSignature)
+		}
+	}))
+	defer webhookServer.Close()
+
+	webhook := model.Webhook{Url: webhookServer.URL + "/hook", Secret: "abc123"}
+	assert.Nil(t, SendWebhook(&webhook, WebhookMatchStarted, map[string]string{"matchName": "Q12"}))
+	assert.NotEqual(t, "", receivedSignature)
+	assert.Equal(t, "match_started", receivedPayload["event"])
+	assert.Equal(t, map[string]any{"matchName": "Q12"}, receivedPayload["data"])
+	assert.NotZero(t, receivedPayload["timestamp"])
+
+	webhook.Secret = ""
+	assert.Nil(t, SendWebhook(&webhook, WebhookScheduleChanged, nil))
+	assert.Equal(t, "", receivedSignature)
+	assert.Equal(t, "schedule_changed", receivedPayload["event"])
+
+	webhook.Url = webhookServer.URL + "/broken"
+	err := SendWebhook(&webhook, WebhookScoreCommitted, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "503, Receiver is down")
+	}
+}
+
+func TestSignWebhookBody(t *testing.T) {
+	// Known value from RFC 4231 test case 2.
+	assert.Equal(
+		t,
+		"5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		SignWebhookBody("Jefe", []byte("what do ya want for nothing?")),
+	)
+}
@@ -88,7 +88,7 @@ func TestDoubleEliminationProgression(t *testing.T) {
 
 	assertMatchupOutcome(t, matchGroups["M1"], "", "")
 
-	playoffMatchResults[1] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[1] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[4:7], []expectedAlliances{{8, 0}, {0, 0}, {1, 0}})
 	for i := 7; i < 19; i++ {
@@ -99,7 +99,7 @@ func TestDoubleEliminationProgression(t *testing.T) {
 	)
 
 	// Reverse a previous outcome.
-	playoffMatchResults[1] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[1] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[4:7], []expectedAlliances{{1, 0}, {0, 0}, {8, 0}})
 	for i := 7; i < 19; i++ {
@@ -109,7 +109,7 @@ func TestDoubleEliminationProgression(t *testing.T) {
 		t, matchGroups["M1"], "Advances to Match 5 &ndash; Round 2 Lower", "Advances to Match 7 &ndash; Round 2 Upper",
 	)
 
-	playoffMatchResults[2] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[2] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[4:7], []expectedAlliances{{1, 5}, {0, 0}, {8, 4}})
 	for i := 7; i < 19; i++ {
@@ -119,7 +119,7 @@ func TestDoubleEliminationProgression(t *testing.T) {
 		t, matchGroups["M2"], "Advances to Match 7 &ndash; Round 2 Upper", "Advances to Match 5 &ndash; Round 2 Lower",
 	)
 
-	playoffMatchResults[3] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[3] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[5:8], []expectedAlliances{{2, 0}, {8, 4}, {7, 0}})
 	for i := 8; i < 19; i++ {
@@ -129,14 +129,14 @@ func TestDoubleEliminationProgression(t *testing.T) {
 		t, matchGroups["M3"], "Advances to Match 6 &ndash; Round 2 Lower", "Advances to Match 8 &ndash; Round 2 Upper",
 	)
 
-	playoffMatchResults[4] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[4] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[5:8], []expectedAlliances{{2, 6}, {8, 4}, {7, 3}})
 	for i := 8; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{0, 0}})
 	}
 
-	playoffMatchResults[5] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[5] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[8:10], []expectedAlliances{{0, 0}, {0, 5}})
 	for i := 10; i < 19; i++ {
@@ -144,7 +144,7 @@ func TestDoubleEliminationProgression(t *testing.T) {
 	}
 	assertMatchupOutcome(t, matchGroups["M5"], "Eliminated", "Advances to Match 10 &ndash; Round 3 Lower")
 
-	playoffMatchResults[6] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[6] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[8:10], []expectedAlliances{{0, 2}, {0, 5}})
 	for i := 10; i < 19; i++ {
@@ -152,21 +152,21 @@ func TestDoubleEliminationProgression(t *testing.T) {
 	}
 
 	// Score a perfect tie; no alliance should advance until the match is replayed.
-	playoffMatchResults[7] = playoffMatchResult{game.TieMatch}
+	playoffMatchResults[7] = playoffMatchResult{status: game.TieMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[8:10], []expectedAlliances{{0, 2}, {0, 5}})
 	for i := 10; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{0, 0}})
 	}
 
-	playoffMatchResults[7] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[7] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[8:11], []expectedAlliances{{8, 2}, {0, 5}, {4, 0}})
 	for i := 11; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{0, 0}})
 	}
 
-	playoffMatchResults[8] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[8] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[8:11], []expectedAlliances{{8, 2}, {7, 5}, {4, 3}})
 	for i := 11; i < 19; i++ {
@@ -174,15 +174,15 @@ func TestDoubleEliminationProgression(t *testing.T) {
 	}
 
 	// Score two matches at the same time.
-	playoffMatchResults[9] = playoffMatchResult{game.RedWonMatch}
-	playoffMatchResults[10] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[9] = playoffMatchResult{status: game.RedWonMatch}
+	playoffMatchResults[10] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[11:12], []expectedAlliances{{7, 8}})
 	for i := 12; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{0, 0}})
 	}
 
-	playoffMatchResults[11] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[11] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[12:13], []expectedAlliances{{3, 0}})
 	finalMatchup.update(playoffMatchResults)
@@ -193,14 +193,14 @@ func TestDoubleEliminationProgression(t *testing.T) {
 		t, matchGroups["M11"], "Advances to Final 1", "Advances to Match 13 &ndash; Round 5 Lower",
 	)
 
-	playoffMatchResults[12] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[12] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assertMatchSpecAlliances(t, matchSpecs[12:13], []expectedAlliances{{3, 7}})
 	for i := 13; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{4, 0}})
 	}
 
-	playoffMatchResults[13] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[13] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	for i := 13; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{4, 3}})
@@ -216,42 +216,42 @@ func TestDoubleEliminationProgression(t *testing.T) {
 	}
 	assertMatchupOutcome(t, matchGroups["M13"], "", "")
 
-	playoffMatchResults[13] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[13] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	for i := 13; i < 19; i++ {
 		assertMatchSpecAlliances(t, matchSpecs[i:i+1], []expectedAlliances{{4, 7}})
 	}
 	assertMatchupOutcome(t, matchGroups["M13"], "Eliminated", "Advances to Final 1")
 
-	playoffMatchResults[14] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[14] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assert.False(t, finalMatchup.IsComplete())
 	assert.Equal(t, 0, finalMatchup.WinningAllianceId())
 	assert.Equal(t, 0, finalMatchup.LosingAllianceId())
 	assertMatchupOutcome(t, matchGroups["F"], "", "")
 
-	playoffMatchResults[15] = playoffMatchResult{game.RedWonMatch}
+	playoffMatchResults[15] = playoffMatchResult{status: game.RedWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assert.False(t, finalMatchup.IsComplete())
 	assert.Equal(t, 0, finalMatchup.WinningAllianceId())
 	assert.Equal(t, 0, finalMatchup.LosingAllianceId())
 	assertMatchupOutcome(t, matchGroups["F"], "", "")
 
-	playoffMatchResults[16] = playoffMatchResult{game.TieMatch}
+	playoffMatchResults[16] = playoffMatchResult{status: game.TieMatch}
 	finalMatchup.update(playoffMatchResults)
 	assert.False(t, finalMatchup.IsComplete())
 	assert.Equal(t, 0, finalMatchup.WinningAllianceId())
 	assert.Equal(t, 0, finalMatchup.LosingAllianceId())
 	assertMatchupOutcome(t, matchGroups["F"], "", "")
 
-	playoffMatchResults[17] = playoffMatchResult{game.TieMatch}
+	playoffMatchResults[17] = playoffMatchResult{status: game.TieMatch}
 	finalMatchup.update(playoffMatchResults)
 	assert.False(t, finalMatchup.IsComplete())
 	assert.Equal(t, 0, finalMatchup.WinningAllianceId())
 	assert.Equal(t, 0, finalMatchup.LosingAllianceId())
 	assertMatchupOutcome(t, matchGroups["F"], "", "")
 
-	playoffMatchResults[18] = playoffMatchResult{game.BlueWonMatch}
+	playoffMatchResults[18] = playoffMatchResult{status: game.BlueWonMatch}
 	finalMatchup.update(playoffMatchResults)
 	assert.True(t, finalMatchup.IsComplete())
 	assert.Equal(t, 7, finalMatchup.WinningAllianceId())
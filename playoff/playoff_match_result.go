@@ -8,5 +8,24 @@ package playoff
 import "github.com/Team254/cheesy-arena/game"
 
 type playoffMatchResult struct {
-	status game.MatchStatus
+	status        game.MatchStatus
+	winReason     game.MatchWinReason
+	tiebreakLevel int
+}
+
+// MatchAdvancement describes the outcome of a completed playoff match and, if the match decided its matchup, where
+// each alliance goes next in the tournament.
+type MatchAdvancement struct {
+	MatchOrder           int
+	MatchGroupId         string
+	Status               game.MatchStatus
+	WinReason            game.MatchWinReason
+	TiebreakLevel        int
+	WinningAllianceId    int
+	LosingAllianceId     int
+	MatchupDecided       bool
+	WinnerDestinationId  string
+	LoserDestinationId   string
+	LoserIsEliminated    bool
+	IsTournamentDecision bool
 }
@@ -13,10 +13,11 @@ import (
 )
 
 type PlayoffTournament struct {
-	matchGroups  map[string]MatchGroup
-	matchSpecs   []*matchSpec
-	breakSpecs   []breakSpec
-	finalMatchup *Matchup
+	matchGroups         map[string]MatchGroup
+	matchSpecs          []*matchSpec
+	breakSpecs          []breakSpec
+	finalMatchup        *Matchup
+	playoffMatchResults map[int]playoffMatchResult
 }
 
 // NewPlayoffTournament creates a new playoff tournament of the given type and number of alliances, or returns an error
@@ -53,10 +54,11 @@ func NewPlayoffTournament(playoffType model.PlayoffType, numPlayoffAlliances int
 	finalMatchup.update(map[int]playoffMatchResult{})
 
 	return &PlayoffTournament{
-		finalMatchup: finalMatchup,
-		matchGroups:  matchGroups,
-		matchSpecs:   matchSpecs,
-		breakSpecs:   breakSpecs,
+		finalMatchup:        finalMatchup,
+		matchGroups:         matchGroups,
+		matchSpecs:          matchSpecs,
+		breakSpecs:          breakSpecs,
+		playoffMatchResults: map[int]playoffMatchResult{},
 	}, nil
 }
 
@@ -92,6 +94,57 @@ func (tournament *PlayoffTournament) Traverse(visitFunction func(MatchGroup) err
 	return tournament.finalMatchup.traverse(visitFunction)
 }
 
+// MatchAdvancements returns the outcome of each completed playoff match in order of play, including which alliance
+// advanced to which match group as a result and why.
+func (tournament *PlayoffTournament) MatchAdvancements() []MatchAdvancement {
+	advancements := []MatchAdvancement{}
+	winsByMatchGroup := make(map[string]map[int]int)
+	for _, spec := range tournament.matchSpecs {
+		matchResult, ok := tournament.playoffMatchResults[spec.order]
+		if !ok {
+			continue
+		}
+		advancement := MatchAdvancement{
+			MatchOrder:    spec.order,
+			MatchGroupId:  spec.matchGroupId,
+			Status:        matchResult.status,
+			WinReason:     matchResult.winReason,
+			TiebreakLevel: matchResult.tiebreakLevel,
+		}
+		switch matchResult.status {
+		case game.RedWonMatch:
+			advancement.WinningAllianceId = spec.redAllianceId
+			advancement.LosingAllianceId = spec.blueAllianceId
+		case game.BlueWonMatch:
+			advancement.WinningAllianceId = spec.blueAllianceId
+			advancement.LosingAllianceId = spec.redAllianceId
+		}
+
+		matchup, ok := tournament.matchGroups[spec.matchGroupId].(*Matchup)
+		if ok && advancement.WinningAllianceId > 0 {
+			// Tally wins in order of play so that only the match that clinched the matchup is marked as deciding it.
+			if _, ok := winsByMatchGroup[spec.matchGroupId]; !ok {
+				winsByMatchGroup[spec.matchGroupId] = make(map[int]int)
+			}
+			winsByMatchGroup[spec.matchGroupId][advancement.WinningAllianceId]++
+			if winsByMatchGroup[spec.matchGroupId][advancement.WinningAllianceId] == matchup.NumWinsToAdvance {
+				advancement.MatchupDecided = true
+				advancement.IsTournamentDecision = matchup.isFinal()
+				if matchup.winningAllianceDestination != nil {
+					advancement.WinnerDestinationId = matchup.winningAllianceDestination.Id()
+				}
+				if matchup.losingAllianceDestination != nil {
+					advancement.LoserDestinationId = matchup.losingAllianceDestination.Id()
+				} else {
+					advancement.LoserIsEliminated = true
+				}
+			}
+		}
+		advancements = append(advancements, advancement)
+	}
+	return advancements
+}
+
 // CreateMatchesAndBreaks creates all the playoff matches and scheduled breaks in the database, as a one-time action at
 // the beginning of the playoff tournament.
 func (tournament *PlayoffTournament) CreateMatchesAndBreaks(database *model.Database, startTime time.Time) error {
@@ -196,11 +249,14 @@ func (tournament *PlayoffTournament) UpdateMatches(database *model.Database) err
 	for _, match := range matches {
 		switch match.Status {
 		case game.RedWonMatch, game.BlueWonMatch, game.TieMatch:
-			playoffMatchResults[match.TypeOrder] = playoffMatchResult{status: match.Status}
+			playoffMatchResults[match.TypeOrder] = playoffMatchResult{
+				status: match.Status, winReason: match.WinReason, tiebreakLevel: match.TiebreakLevel,
+			}
 		}
 	}
 
 	tournament.finalMatchup.update(playoffMatchResults)
+	tournament.playoffMatchResults = playoffMatchResults
 
 	// Update all unplayed matches to assign any alliances that have been newly populated into or removed from matches.
 	matchesByTypeOrder := make(map[int]*model.Match)
@@ -30,7 +30,7 @@ func TestPlayoffTournamentGetters(t *testing.T) {
 	assert.Equal(t, 0, playoffTournament.FinalistAllianceId())
 
 	playoffTournament.FinalMatchup().update(
-		map[int]playoffMatchResult{43: {game.BlueWonMatch}, 44: {game.BlueWonMatch}},
+		map[int]playoffMatchResult{43: {status: game.BlueWonMatch}, 44: {status: game.BlueWonMatch}},
 	)
 	assert.True(t, playoffTournament.IsComplete())
 	assert.Equal(t, 2, playoffTournament.WinningAllianceId())
@@ -240,3 +240,54 @@ func TestPlayoffTournamentUpdateMatches(t *testing.T) {
 	assert.Equal(t, 0, matches[6].Blue2)
 	assert.Equal(t, 0, matches[6].Blue3)
 }
+
+func TestPlayoffTournamentMatchAdvancements(t *testing.T) {
+	database := setupTestDb(t)
+	tournament.CreateTestAlliances(database, 4)
+
+	playoffTournament, err := NewPlayoffTournament(model.SingleEliminationPlayoff, 4)
+	assert.Nil(t, err)
+	assert.Empty(t, playoffTournament.MatchAdvancements())
+	assert.Nil(t, playoffTournament.CreateMatchesAndBreaks(database, time.Unix(0, 0)))
+
+	matches, _ := database.GetMatchesByType(model.Playoff, true)
+	matches[0].Status = game.BlueWonMatch
+	matches[0].WinReason = game.WinReasonScore
+	assert.Nil(t, database.UpdateMatch(&matches[0]))
+	matches[1].Status = game.TieMatch
+	assert.Nil(t, database.UpdateMatch(&matches[1]))
+	assert.Nil(t, playoffTournament.UpdateMatches(database))
+
+	advancements := playoffTournament.MatchAdvancements()
+	if assert.Equal(t, 2, len(advancements)) {
+		assert.Equal(t, "SF1", advancements[0].MatchGroupId)
+		assert.Equal(t, game.WinReasonScore, advancements[0].WinReason)
+		assert.Equal(t, 4, advancements[0].WinningAllianceId)
+		assert.Equal(t, 1, advancements[0].LosingAllianceId)
+		assert.False(t, advancements[0].MatchupDecided)
+		assert.Equal(t, "", advancements[0].WinnerDestinationId)
+		assert.Equal(t, "SF2", advancements[1].MatchGroupId)
+		assert.Equal(t, game.TieMatch, advancements[1].Status)
+		assert.Equal(t, 0, advancements[1].WinningAllianceId)
+		assert.False(t, advancements[1].MatchupDecided)
+	}
+
+	matches[2].Status = game.BlueWonMatch
+	matches[2].WinReason = game.WinReasonTiebreaker
+	matches[2].TiebreakLevel = 2
+	assert.Nil(t, database.UpdateMatch(&matches[2]))
+	assert.Nil(t, playoffTournament.UpdateMatches(database))
+
+	advancements = playoffTournament.MatchAdvancements()
+	if assert.Equal(t, 3, len(advancements)) {
+		assert.Equal(t, "SF1", advancements[2].MatchGroupId)
+		assert.Equal(t, game.WinReasonTiebreaker, advancements[2].WinReason)
+		assert.Equal(t, 2, advancements[2].TiebreakLevel)
+		assert.Equal(t, 4, advancements[2].WinningAllianceId)
+		assert.True(t, advancements[2].MatchupDecided)
+		assert.Equal(t, "F", advancements[2].WinnerDestinationId)
+		assert.Equal(t, "", advancements[2].LoserDestinationId)
+		assert.True(t, advancements[2].LoserIsEliminated)
+		assert.False(t, advancements[2].IsTournamentDecision)
+	}
+}
@@ -7,9 +7,9 @@ package plc
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/goburrow/modbus"
-	"log"
 	"strings"
 	"time"
 )
@@ -189,7 +189,7 @@ func (plc *ModbusPlc) Run() {
 			} else {
 				err := plc.connect()
 				if err != nil {
-					log.Printf("PLC error: %v", err)
+					logging.Plc.Error("PLC error.", "error", err)
 					time.Sleep(time.Second * plcRetryIntevalSec)
 					plc.isHealthy = false
 					continue
@@ -362,7 +362,7 @@ func (plc *ModbusPlc) connect() error {
 	if err != nil {
 		return err
 	}
-	log.Printf("Connected to PLC at %s", address)
+	logging.Plc.Info("Connected to PLC.", "address", address)
 
 	plc.handler = handler
 	plc.client = modbus.NewClient(plc.handler)
@@ -411,11 +411,11 @@ func (plc *ModbusPlc) readInputs() bool {
 
 	inputs, err := plc.client.ReadDiscreteInputs(0, uint16(len(plc.inputs)))
 	if err != nil {
-		log.Printf("PLC error reading inputs: %v", err)
+		logging.Plc.Error("PLC error reading inputs.", "error", err)
 		return false
 	}
 	if len(inputs)*8 < len(plc.inputs) {
-		log.Printf("Insufficient length of PLC inputs: got %d bytes, expected %d bits.", len(inputs), len(plc.inputs))
+		logging.Plc.Error("Insufficient length of PLC inputs.", "gotBytes", len(inputs), "expectedBits", len(plc.inputs))
 		return false
 	}
 
@@ -430,12 +430,13 @@ func (plc *ModbusPlc) readRegisters() bool {
 
 	registers, err := plc.client.ReadHoldingRegisters(0, uint16(len(plc.registers)))
 	if err != nil {
-		log.Printf("PLC error reading registers: %v", err)
+		logging.Plc.Error("PLC error reading registers.", "error", err)
 		return false
 	}
 	if len(registers)/2 < len(plc.registers) {
-		log.Printf("Insufficient length of PLC registers: got %d bytes, expected %d words.", len(registers),
-			len(plc.registers))
+		logging.Plc.Error(
+			"Insufficient length of PLC registers.", "gotBytes", len(registers), "expectedWords", len(plc.registers),
+		)
 		return false
 	}
 
@@ -450,7 +451,7 @@ func (plc *ModbusPlc) writeCoils() bool {
 	coils := boolToByte(plc.coils[:])
 	_, err := plc.client.WriteMultipleCoils(0, uint16(len(plc.coils)), coils)
 	if err != nil {
-		log.Printf("PLC error writing coils: %v", err)
+		logging.Plc.Error("PLC error writing coils.", "error", err)
 		return false
 	}
 
@@ -0,0 +1,56 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Generation of packets in the real-time data format that Daktronics All Sport controllers and the third-party
+// scoreboard drivers built for them accept.
+
+package scoreboard
+
+import (
+	"fmt"
+)
+
+const (
+	daktronicsSyn = 0x16
+	daktronicsSoh = 0x01
+	daktronicsStx = 0x02
+	daktronicsEot = 0x04
+	daktronicsEtb = 0x17
+
+	// Address of the main clock and score block, as used by the basketball-style sport codes that are closest to a
+	// robotics match.
+	daktronicsScoreItemAddress = "0042100000"
+)
+
+// Returns a packet containing the clock, period and scores from the given state. The data block is laid out as the
+// 5-character main clock, the 4-character home and guest scores, and the 2-character period, all right-justified; the
+// packet is followed by a 2-digit hex checksum of everything from the SOH onwards.
+func generateDaktronicsPacket(state State) []byte {
+	data := fmt.Sprintf(
+		"%5s%4d%4d%2d", formatClock(state.ClockSec), clamp(state.RedScore, 9999), clamp(state.BlueScore, 9999),
+		clamp(state.Period, 99),
+	)
+
+	packet := []byte{daktronicsSyn, daktronicsSoh}
+	packet = append(packet, daktronicsScoreItemAddress...)
+	packet = append(packet, daktronicsStx)
+	packet = append(packet, data...)
+	packet = append(packet, daktronicsEot)
+	var checksum byte
+	for _, b := range packet[1:] {
+		checksum += b
+	}
+	packet = append(packet, fmt.Sprintf("%02X", checksum)...)
+	return append(packet, daktronicsEtb)
+}
+
+// Limits the given value to the range that fits in its scoreboard field.
+func clamp(value, limit int) int {
+	if value < 0 {
+		return 0
+	}
+	if value > limit {
+		return limit
+	}
+	return value
+}
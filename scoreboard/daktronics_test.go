@@ -0,0 +1,25 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package scoreboard
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateDaktronicsPacket(t *testing.T) {
+	packet := generateDaktronicsPacket(State{ClockSec: 95, Period: 2, RedScore: 42, BlueScore: 117})
+	assert.Equal(t, "\x16\x010042100000\x02 1:35  42 117 2\x04", string(packet[:29]))
+	var checksum byte
+	for _, b := range packet[1:29] {
+		checksum += b
+	}
+	assert.Equal(t, fmt.Sprintf("%02X", checksum), string(packet[29:31]))
+	assert.Equal(t, []byte{0x17}, packet[31:])
+
+	// Values that don't fit should be clamped rather than overflow their fields.
+	packet = generateDaktronicsPacket(State{ClockSec: -3, Period: 0, RedScore: 12345, BlueScore: -1})
+	assert.Equal(t, " 0:009999   0 0", string(packet[13:28]))
+}
@@ -0,0 +1,202 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Methods for driving an existing venue scoreboard controller with the match clock and scores, over either a serial
+// port or UDP depending on what the controller accepts.
+
+package scoreboard
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/goburrow/serial"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+const (
+	scoreboardKeepAlivePeriod = time.Second
+	defaultSerialBaudRate     = 9600
+	serialWriteTimeout        = 100 * time.Millisecond
+)
+
+// Packet formats that the scoreboard output can produce.
+const (
+	DaktronicsAllSportFormat = "daktronics"
+	TemplateFormat           = "template"
+)
+
+var FormatNames = map[string]string{
+	DaktronicsAllSportFormat: "Daktronics All Sport",
+	TemplateFormat:           "Custom Template",
+}
+
+// Represents the information shown on a venue scoreboard. The red alliance is shown as the home team.
+type State struct {
+	MatchName string
+	ClockSec  int // Time remaining in the current period.
+	Period    int // 1 for autonomous, 2 for teleoperated, and 0 otherwise.
+	RedScore  int
+	BlueScore int
+}
+
+// Represents a connection to a venue scoreboard controller.
+type Controller struct {
+	address      string
+	format       string
+	template     *template.Template
+	conn         io.WriteCloser
+	lastPacket   []byte
+	lastSendTime time.Time
+}
+
+// Returns an error if the given address isn't of the form udp://host:port or serial:///dev/path?baud=rate.
+func ValidateAddress(address string) error {
+	_, err := parseAddress(address)
+	return err
+}
+
+// Returns an error if the given format isn't supported, or if a custom template doesn't parse.
+func ValidateFormat(format, packetTemplate string) error {
+	switch format {
+	case DaktronicsAllSportFormat:
+		return nil
+	case TemplateFormat:
+		_, err := parseTemplate(packetTemplate)
+		return err
+	default:
+		return fmt.Errorf("Invalid scoreboard format '%s'.", format)
+	}
+}
+
+// Sets the address of the scoreboard controller and the format of the packets to send to it. An empty address disables
+// output.
+func (controller *Controller) Configure(address, format, packetTemplate string) error {
+	if controller.conn != nil {
+		_ = controller.conn.Close()
+		controller.conn = nil
+	}
+	controller.address = address
+	controller.format = format
+	controller.lastPacket = nil
+	if address == "" {
+		return nil
+	}
+	if err := ValidateFormat(format, packetTemplate); err != nil {
+		return err
+	}
+	if format == TemplateFormat {
+		controller.template, _ = parseTemplate(packetTemplate)
+	}
+
+	var err error
+	controller.conn, err = openConnection(address)
+	return err
+}
+
+// Returns true if a scoreboard controller has been configured.
+func (controller *Controller) IsEnabled() bool {
+	return controller.conn != nil
+}
+
+// Outputs the given state to the scoreboard controller. A packet is only sent when its contents change or when needed
+// to keep the controller from timing out.
+func (controller *Controller) Update(state State) error {
+	if controller.conn == nil {
+		return nil
+	}
+	packet, err := controller.generatePacket(state)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(packet, controller.lastPacket) && time.Since(controller.lastSendTime) < scoreboardKeepAlivePeriod {
+		return nil
+	}
+
+	if _, err = controller.conn.Write(packet); err != nil {
+		return err
+	}
+	controller.lastPacket = packet
+	controller.lastSendTime = time.Now()
+	return nil
+}
+
+func (controller *Controller) generatePacket(state State) ([]byte, error) {
+	if controller.format == TemplateFormat {
+		var buffer bytes.Buffer
+		if err := controller.template.Execute(&buffer, state); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+	return generateDaktronicsPacket(state), nil
+}
+
+func parseTemplate(packetTemplate string) (*template.Template, error) {
+	if packetTemplate == "" {
+		return nil, fmt.Errorf("Scoreboard packet template cannot be blank.")
+	}
+	parsedTemplate, err := template.New("scoreboard").Funcs(template.FuncMap{"clock": formatClock}).
+		Parse(packetTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid scoreboard packet template: %v", err)
+	}
+	return parsedTemplate, nil
+}
+
+// Returns the serial port configuration or UDP address represented by the given address string.
+func parseAddress(address string) (*serial.Config, error) {
+	parsedUrl, err := url.Parse(address)
+	if err == nil {
+		switch parsedUrl.Scheme {
+		case "udp":
+			if parsedUrl.Hostname() != "" && parsedUrl.Port() != "" {
+				return nil, nil
+			}
+		case "serial":
+			if parsedUrl.Path != "" {
+				config := &serial.Config{
+					Address:  parsedUrl.Path,
+					BaudRate: defaultSerialBaudRate,
+					DataBits: 8,
+					StopBits: 1,
+					Parity:   "N",
+					Timeout:  serialWriteTimeout,
+				}
+				if baudRate := parsedUrl.Query().Get("baud"); baudRate != "" {
+					if config.BaudRate, err = strconv.Atoi(baudRate); err != nil || config.BaudRate <= 0 {
+						return nil, fmt.Errorf("Scoreboard address '%s' has an invalid baud rate.", address)
+					}
+				}
+				return config, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf(
+		"Scoreboard address '%s' must be of the form udp://host:port or serial:///dev/path?baud=rate.", address,
+	)
+}
+
+func openConnection(address string) (io.WriteCloser, error) {
+	serialConfig, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if serialConfig != nil {
+		return serial.Open(serialConfig)
+	}
+	parsedUrl, _ := url.Parse(address)
+	return net.Dial("udp", parsedUrl.Host)
+}
+
+// Returns the given number of seconds formatted as M:SS.
+func formatClock(clockSec int) string {
+	if clockSec < 0 {
+		clockSec = 0
+	}
+	return fmt.Sprintf("%d:%02d", clockSec/60, clockSec%60)
+}
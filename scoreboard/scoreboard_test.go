@@ -0,0 +1,75 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package scoreboard
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestValidateAddress(t *testing.T) {
+	assert.Nil(t, ValidateAddress("udp://10.0.100.30:21000"))
+	assert.Nil(t, ValidateAddress("serial:///dev/ttyUSB0"))
+	assert.Nil(t, ValidateAddress("serial:///dev/ttyUSB0?baud=19200"))
+	assert.NotNil(t, ValidateAddress("10.0.100.30:21000"))
+	assert.NotNil(t, ValidateAddress("udp://10.0.100.30"))
+	assert.NotNil(t, ValidateAddress("serial://"))
+	err := ValidateAddress("serial:///dev/ttyUSB0?baud=fast")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid baud rate")
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert.Nil(t, ValidateFormat(DaktronicsAllSportFormat, ""))
+	assert.Nil(t, ValidateFormat(TemplateFormat, "{{clock .ClockSec}}"))
+	assert.NotNil(t, ValidateFormat(TemplateFormat, ""))
+	assert.NotNil(t, ValidateFormat(TemplateFormat, "{{.ClockSec"))
+	assert.NotNil(t, ValidateFormat("scorebug", ""))
+}
+
+func TestController(t *testing.T) {
+	// Listen where the controller will send its packets.
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer udpConn.Close()
+	readPacket := func() string {
+		packet := make([]byte, 1024)
+		udpConn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		length, err := udpConn.Read(packet)
+		if err != nil {
+			return ""
+		}
+		return string(packet[:length])
+	}
+
+	var controller Controller
+	assert.False(t, controller.IsEnabled())
+	assert.Nil(t, controller.Update(State{}))
+
+	address := fmt.Sprintf("udp://%s", udpConn.LocalAddr().String())
+	packetTemplate := "{{.MatchName}},{{clock .ClockSec}},{{.Period}},{{.RedScore}},{{.BlueScore}}\r\n"
+	assert.Nil(t, controller.Configure(address, TemplateFormat, packetTemplate))
+	assert.True(t, controller.IsEnabled())
+	state := State{MatchName: "Q12", ClockSec: 134, Period: 2, RedScore: 10, BlueScore: 7}
+	assert.Nil(t, controller.Update(state))
+	assert.Equal(t, "Q12,2:14,2,10,7\r\n", readPacket())
+
+	// An unchanged state shouldn't be resent until the keepalive period elapses.
+	assert.Nil(t, controller.Update(state))
+	assert.Equal(t, "", readPacket())
+	state.RedScore = 15
+	assert.Nil(t, controller.Update(state))
+	assert.Equal(t, "Q12,2:14,2,15,7\r\n", readPacket())
+
+	assert.Nil(t, controller.Configure(address, DaktronicsAllSportFormat, ""))
+	assert.Nil(t, controller.Update(state))
+	assert.Equal(t, string(generateDaktronicsPacket(state)), readPacket())
+
+	assert.Nil(t, controller.Configure("", DaktronicsAllSportFormat, ""))
+	assert.False(t, controller.IsEnabled())
+}
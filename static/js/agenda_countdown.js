@@ -0,0 +1,42 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Shared client-side logic for the displays that count down to the next item of the event day's run of show.
+
+let nextAgendaItem = null;
+let agendaClockOffsetMs = 0;  // Difference between the server's clock and this one's.
+
+// Handles a websocket message to update the run of show item that is under way or coming up next.
+const handleAgenda = function(event) {
+  nextAgendaItem = event.data.NextItem;
+  if (event.time) {
+    agendaClockOffsetMs = event.time - Date.now();
+  }
+  updateAgendaCountdown();
+};
+
+// Updates the countdown to the next run of show item, hiding it if there isn't one.
+const updateAgendaCountdown = function() {
+  const agendaCountdown = $("#agendaCountdown");
+  if (!nextAgendaItem) {
+    agendaCountdown.text("");
+    return;
+  }
+
+  const remainingSec = Math.floor(
+    (new Date(nextAgendaItem.StartTime).getTime() - (Date.now() + agendaClockOffsetMs)) / 1000
+  );
+  if (remainingSec <= 0) {
+    agendaCountdown.text(`${nextAgendaItem.Name} is under way`);
+    return;
+  }
+  const hours = Math.floor(remainingSec / 3600);
+  const minutes = String(Math.floor(remainingSec % 3600 / 60));
+  const seconds = String(remainingSec % 60).padStart(2, "0");
+  const countdown = hours > 0 ? `${hours}:${minutes.padStart(2, "0")}:${seconds}` : `${minutes}:${seconds}`;
+  agendaCountdown.text(`${nextAgendaItem.Name} in ${countdown}`);
+};
+
+$(function() {
+  setInterval(updateAgendaCountdown, 1000);
+});
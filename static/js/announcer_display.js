@@ -40,7 +40,7 @@ var handleMatchLoad = function(data) {
 var handleMatchTime = function(data) {
   translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
     $("#matchState").text(matchStateText);
-    $("#matchTime").text(getCountdown(data.MatchState, data.MatchTimeSec));
+    $("#matchTime").text(getCountdown(data.MatchState, getCorrectedMatchTimeSec(data)));
   });
 };
 
@@ -12,11 +12,17 @@ let currentScreen = "blank";
 let redSide;
 let blueSide;
 let currentMatch;
+let currentLowerThirdId;
+let replayBannerTimeout;
 let overlayCenteringHideParams;
 let overlayCenteringShowParams;
+let overlayOnly = false;
+let activeVideoStinger = null;
+let videoStingerStallTimeout;
 const allianceSelectionTemplate = Handlebars.compile($("#allianceSelectionTemplate").html());
 const sponsorImageTemplate = Handlebars.compile($("#sponsorImageTemplate").html());
 const sponsorTextTemplate = Handlebars.compile($("#sponsorTextTemplate").html());
+const sponsorVideoTemplate = Handlebars.compile($("#sponsorVideoTemplate").html());
 
 // Constants for overlay positioning. The CSS is the source of truth for the values that represent initial state.
 const overlayCenteringTopUp = "-130px";
@@ -38,6 +44,9 @@ const bracketLogoScale = 0.75;
 const timeoutDetailsIn = $("#timeoutDetails").css("width");
 const timeoutDetailsOut = "570px";
 
+// Longest that a video stinger may stall while buffering before it is skipped so that the show can go on.
+const videoStingerMaxStallMs = 3000;
+
 // Game-specific constants and variables.
 const amplifyProgressStartOffset = $("#leftAmplified svg circle").css("stroke-dashoffset");
 const amplifyFadeTimeMs = 300;
@@ -45,8 +54,15 @@ const amplifyDwellTimeMs = 500;
 let redAmplified = false;
 let blueAmplified = false;
 
+// Screens that are drawn only within the score bar, and which are therefore still shown in overlay-only mode.
+const overlayOnlyScreens = ["intro", "match", "timeout"];
+
 // Handles a websocket message to change which screen is displayed.
 const handleAudienceDisplayMode = function(targetScreen) {
+  if (overlayOnly && !overlayOnlyScreens.includes(targetScreen)) {
+    // Full-screen graphics would cover the camera video that the overlay is keyed over.
+    targetScreen = "blank";
+  }
   transitionQueue.push(targetScreen);
   executeTransitionQueue();
 };
@@ -91,27 +107,50 @@ const executeTransitionQueue = function() {
 };
 
 // Handles a websocket message to update the teams for the current match.
+// Handles a websocket message to flag the teams whose robots have been emergency- or autonomous-stopped.
+const handleStationStops = function(data) {
+  $.each(data, function(station, stationStop) {
+    const side = station[0] === "R" ? redSide : blueSide;
+    let stop = "";
+    if (stationStop.EStop) {
+      stop = "estop";
+    } else if (stationStop.AStop && stationStop.RobotEnableBlocked) {
+      stop = "astop";
+    }
+    $(`#${side}Team${station[1]}`).attr("data-stop", stop);
+  });
+};
+
 const handleMatchLoad = function(data) {
   currentMatch = data.Match;
   $(`#${redSide}Team1`).text(currentMatch.Red1);
-  $(`#${redSide}Team1`).attr("data-yellow-card", data.Teams["R1"]?.YellowCard);
+  $(`#${redSide}Team1`).attr("data-yellow-card", data.YellowCards["R1"]);
   $(`#${redSide}Team2`).text(currentMatch.Red2);
-  $(`#${redSide}Team2`).attr("data-yellow-card", data.Teams["R2"]?.YellowCard);
+  $(`#${redSide}Team2`).attr("data-yellow-card", data.YellowCards["R2"]);
   $(`#${redSide}Team3`).text(currentMatch.Red3);
-  $(`#${redSide}Team3`).attr("data-yellow-card", data.Teams["R3"]?.YellowCard);
+  $(`#${redSide}Team3`).attr("data-yellow-card", data.YellowCards["R3"]);
   $(`#${redSide}Team1Avatar`).attr("src", getAvatarUrl(currentMatch.Red1));
   $(`#${redSide}Team2Avatar`).attr("src", getAvatarUrl(currentMatch.Red2));
   $(`#${redSide}Team3Avatar`).attr("src", getAvatarUrl(currentMatch.Red3));
   $(`#${blueSide}Team1`).text(currentMatch.Blue1);
-  $(`#${blueSide}Team1`).attr("data-yellow-card", data.Teams["B1"]?.YellowCard);
+  $(`#${blueSide}Team1`).attr("data-yellow-card", data.YellowCards["B1"]);
   $(`#${blueSide}Team2`).text(currentMatch.Blue2);
-  $(`#${blueSide}Team2`).attr("data-yellow-card", data.Teams["B2"]?.YellowCard);
+  $(`#${blueSide}Team2`).attr("data-yellow-card", data.YellowCards["B2"]);
   $(`#${blueSide}Team3`).text(currentMatch.Blue3);
-  $(`#${blueSide}Team3`).attr("data-yellow-card", data.Teams["B3"]?.YellowCard);
+  $(`#${blueSide}Team3`).attr("data-yellow-card", data.YellowCards["B3"]);
   $(`#${blueSide}Team1Avatar`).attr("src", getAvatarUrl(currentMatch.Blue1));
   $(`#${blueSide}Team2Avatar`).attr("src", getAvatarUrl(currentMatch.Blue2));
   $(`#${blueSide}Team3Avatar`).attr("src", getAvatarUrl(currentMatch.Blue3));
 
+  // Hide the team positions that are unused in smaller match formats.
+  for (let position = 2; position <= 3; position++) {
+    const isActive = position <= data.TeamsPerAlliance;
+    for (const side of [redSide, blueSide]) {
+      $(`#${side}Team${position}`).toggle(isActive);
+      $(`#${side}Team${position}Avatar`).toggle(isActive);
+    }
+  }
+
   // Show alliance numbers if this is a playoff match.
   if (currentMatch.Type === matchTypePlayoff) {
     $(`#${redSide}PlayoffAlliance`).text(currentMatch.PlayoffRedAlliance);
@@ -154,19 +193,8 @@ const handleRealtimeScore = function(data) {
   $(`#${redSide}ScoreNumber`).text(data.Red.ScoreSummary.Score - data.Red.ScoreSummary.StagePoints);
   $(`#${blueSide}ScoreNumber`).text(data.Blue.ScoreSummary.Score - data.Blue.ScoreSummary.StagePoints);
 
-  $(`#${redSide}NoteNumerator`).text(data.Red.ScoreSummary.NumNotes);
-  $(`#${redSide}NoteDenominator`).text(data.Red.ScoreSummary.NumNotesGoal);
-  $(`#${blueSide}NoteNumerator`).text(data.Blue.ScoreSummary.NumNotes);
-  $(`#${blueSide}NoteDenominator`).text(data.Blue.ScoreSummary.NumNotesGoal);
-  if (currentMatch.Type === matchTypePlayoff) {
-    $(`#${redSide}NoteDenominator`).hide();
-    $(`#${blueSide}NoteDenominator`).hide();
-    $(".note-splitter").hide();
-  } else {
-    $(`#${redSide}NoteDenominator`).show();
-    $(`#${blueSide}NoteDenominator`).show();
-    $(".note-splitter").show();
-  }
+  updateScoreWidgets(redSide, data.Red);
+  updateScoreWidgets(blueSide, data.Blue);
 
   const redLightsDiv = $(`#${redSide}Lights`);
   const redAmplifiedDiv = $(`#${redSide}Amplified`);
@@ -215,15 +243,23 @@ const handleRealtimeScore = function(data) {
       });
     }, amplifyDwellTimeMs);
   }
+};
 
-  $(`#${redSide}Lights .amp-low`).attr("data-lit", data.Red.Score.AmpSpeaker.BankedAmpNotes >= 1);
-  $(`#${redSide}Lights .amp-high`).attr("data-lit", data.Red.Score.AmpSpeaker.BankedAmpNotes >= 2);
-  $(`#${redSide}Lights .amp-coop`).attr("data-lit", data.Red.Score.AmpSpeaker.CoopActivated);
-  $(`#${redSide}Amplified svg text`).text(data.Red.AmplifiedTimeRemainingSec);
-  $(`#${blueSide}Lights .amp-low`).attr("data-lit", data.Blue.Score.AmpSpeaker.BankedAmpNotes >= 1);
-  $(`#${blueSide}Lights .amp-high`).attr("data-lit", data.Blue.Score.AmpSpeaker.BankedAmpNotes >= 2);
-  $(`#${blueSide}Lights .amp-coop`).attr("data-lit", data.Blue.Score.AmpSpeaker.CoopActivated);
-  $(`#${blueSide}Amplified svg text`).text(data.Blue.AmplifiedTimeRemainingSec);
+// Updates the game-specific score widgets on the given side from the realtime score data for its alliance.
+const updateScoreWidgets = function(side, allianceData) {
+  const getField = function(path) {
+    return path.split(".").reduce((value, key) => value === undefined ? undefined : value[key], allianceData);
+  };
+  const scoreFields = $(`#${side}ScoreFields`);
+  scoreFields.find("[data-score-field]").each(function() {
+    $(this).text(getField($(this).attr("data-score-field")));
+  });
+  scoreFields.find("[data-lit-field]").each(function() {
+    const value = getField($(this).attr("data-lit-field"));
+    const min = $(this).attr("data-lit-min");
+    $(this).attr("data-lit", min === undefined ? Boolean(value) : value >= parseInt(min));
+  });
+  scoreFields.find("[data-hide-in-playoffs]").toggle(currentMatch.Type !== matchTypePlayoff);
 };
 
 // Handles a websocket message to populate the final score data.
@@ -303,7 +339,7 @@ const handleScorePosted = function(data) {
   $("#finalMatchName").html(matchName);
 
   // Reload the bracket to reflect any changes.
-  $("#bracketSvg").attr("src", "/api/bracket/svg?activeMatch=saved&v=" + new Date().getTime());
+  $("#bracketSvg").attr("src", fieldPathPrefix + "/api/bracket/svg?activeMatch=saved&v=" + new Date().getTime());
 
   if (data.Match.Type === matchTypePlayoff) {
     // Hide bonus ranking points and show playoff-only fields.
@@ -316,13 +352,13 @@ const handleScorePosted = function(data) {
 };
 
 // Handles a websocket message to play a sound to signal match start/stop/etc.
-const handlePlaySound = function(sound) {
+const handlePlaySound = function(data) {
   $("audio").each(function(k, v) {
     // Stop and reset any sounds that are still playing.
     v.pause();
     v.currentTime = 0;
   });
-  $("#sound-" + sound)[0].play();
+  $("#sound-" + data.Name)[0].play();
 };
 
 // Handles a websocket message to update the alliance selection screen.
@@ -354,24 +390,114 @@ const handleAllianceSelection = function(data) {
   }
 };
 
+// Handles a websocket message to show the replay banner for as long as the marked clip plays back.
+const handleReplay = function(data) {
+  clearTimeout(replayBannerTimeout);
+  if (data.BannerRemainingSec > 0) {
+    $("#replayBannerMatch").text(data.MatchName);
+    $("#replayBanner").fadeIn(300);
+    replayBannerTimeout = setTimeout(function() {
+      $("#replayBanner").fadeOut(300);
+    }, data.BannerRemainingSec * 1000);
+  } else {
+    $("#replayBanner").hide();
+  }
+};
+
+// Handles a websocket message to preload the video stingers and to update which of them should be playing.
+const handleVideoStinger = function(data) {
+  const container = $("#videoStinger");
+  const videoStingers = data.VideoStingers || [];
+  const videoKeys = videoStingers.map(videoStinger => `${videoStinger.Id}:${videoStinger.VideoFile}`).join(",");
+  if (container.attr("data-videos") !== videoKeys) {
+    // Load every stinger ahead of time so that playback can start right away when it is triggered.
+    container.empty();
+    $.each(videoStingers, function(i, videoStinger) {
+      $("<video preload='auto' playsinline></video>")
+        .attr("id", `videoStinger${videoStinger.Id}`)
+        .attr("src", `/static/video/stingers/${videoStinger.VideoFile}`)
+        .appendTo(container);
+    });
+    container.attr("data-videos", videoKeys);
+  }
+
+  const previousVideoStinger = activeVideoStinger;
+  activeVideoStinger = data.Active;
+  if (currentScreen === "stinger" && activeVideoStinger !== null &&
+      (previousVideoStinger === null || previousVideoStinger.Id !== activeVideoStinger.Id)) {
+    // A different stinger was triggered while one was already showing.
+    playVideoStinger();
+  }
+};
+
+// Starts playing the active video stinger from the beginning, reporting back to the server once it has finished or
+// if it stalls for too long while buffering, so that the show can move on either way.
+const playVideoStinger = function() {
+  stopVideoStinger();
+  if (activeVideoStinger === null) {
+    return;
+  }
+  const videoStingerId = activeVideoStinger.Id;
+  const video = $(`#videoStinger${videoStingerId}`);
+  let completed = false;
+  const complete = function() {
+    if (!completed) {
+      completed = true;
+      clearTimeout(videoStingerStallTimeout);
+      websocket.send("completeVideoStinger", videoStingerId);
+    }
+  };
+  const startStallTimer = function() {
+    clearTimeout(videoStingerStallTimeout);
+    videoStingerStallTimeout = setTimeout(complete, videoStingerMaxStallMs);
+  };
+  if (video.length === 0) {
+    complete();
+    return;
+  }
+
+  const videoElement = video[0];
+  video.on("ended error", complete);
+  video.on("waiting", startStallTimer);
+  video.on("playing", function() { clearTimeout(videoStingerStallTimeout); });
+  video.show();
+  videoElement.currentTime = 0;
+  startStallTimer();
+  const playPromise = videoElement.play();
+  if (playPromise !== undefined) {
+    playPromise.catch(complete);
+  }
+};
+
+// Stops any video stinger that is playing and hides it.
+const stopVideoStinger = function() {
+  clearTimeout(videoStingerStallTimeout);
+  $("#videoStinger video").each(function() {
+    $(this).off("ended error waiting playing");
+    this.pause();
+    $(this).hide();
+  });
+};
+
 // Handles a websocket message to populate and/or show/hide a lower third.
 const handleLowerThird = function(data) {
+  const lowerThirdElement = $("#lowerThird");
+  if (data.ShowLowerThird && lowerThirdElement.is(":visible") && data.LowerThird !== null &&
+      data.LowerThird.Id !== currentLowerThirdId) {
+    // Swap out the visible lower third for the new one, as when stepping through the awards ceremony.
+    currentLowerThirdId = data.LowerThird.Id;
+    lowerThirdElement.transition({queue: false, left: "-1000px"}, 500, "ease", function() {
+      setLowerThirdText(data.LowerThird);
+      lowerThirdElement.transition({queue: false, left: "150px"}, 750, "ease");
+    });
+    return;
+  }
+
   if (data.LowerThird !== null) {
-    if (data.LowerThird.BottomText === "") {
-      $("#lowerThirdTop").hide();
-      $("#lowerThirdBottom").hide();
-      $("#lowerThirdSingle").text(data.LowerThird.TopText);
-      $("#lowerThirdSingle").show();
-    } else {
-      $("#lowerThirdSingle").hide();
-      $("#lowerThirdTop").text(data.LowerThird.TopText);
-      $("#lowerThirdBottom").text(data.LowerThird.BottomText);
-      $("#lowerThirdTop").show();
-      $("#lowerThirdBottom").show();
-    }
+    currentLowerThirdId = data.LowerThird.Id;
+    setLowerThirdText(data.LowerThird);
   }
 
-  const lowerThirdElement = $("#lowerThird");
   if (data.ShowLowerThird && !lowerThirdElement.is(":visible")) {
     lowerThirdElement.show();
     lowerThirdElement.transition({queue: false, left: "150px"}, 750, "ease");
@@ -382,6 +508,22 @@ const handleLowerThird = function(data) {
   }
 };
 
+// Populates the lower third elements with the given text, using the single-line layout if there is no bottom text.
+const setLowerThirdText = function(lowerThird) {
+  if (lowerThird.BottomText === "") {
+    $("#lowerThirdTop").hide();
+    $("#lowerThirdBottom").hide();
+    $("#lowerThirdSingle").text(lowerThird.TopText);
+    $("#lowerThirdSingle").show();
+  } else {
+    $("#lowerThirdSingle").hide();
+    $("#lowerThirdTop").text(lowerThird.TopText);
+    $("#lowerThirdBottom").text(lowerThird.BottomText);
+    $("#lowerThirdTop").show();
+    $("#lowerThirdBottom").show();
+  }
+};
+
 const transitionAllianceSelectionToBlank = function(callback) {
   $('#allianceSelectionCentering').transition({queue: false, right: "-60em"}, 500, "ease", callback);
   $('#allianceRankingsCentering.enabled').transition({queue:false, left: "-60em"}, 500, "ease");
@@ -716,6 +858,20 @@ const transitionSponsorToScore = function(callback) {
   });
 };
 
+const transitionStingerToBlank = function(callback) {
+  $("#videoStinger").transition({queue: false, opacity: 0}, 300, "ease", function() {
+    stopVideoStinger();
+    $("#videoStinger").hide();
+    callback();
+  });
+};
+
+const transitionBlankToStinger = function(callback) {
+  $("#videoStinger").show();
+  playVideoStinger();
+  $("#videoStinger").transition({queue: false, opacity: 1}, 300, "ease", callback);
+};
+
 const transitionTimeoutToBlank = function(callback) {
   $(".timeout-detail").transition({queue: false, opacity: 0}, 300, "linear");
   $("#matchTime").transition({queue: false, opacity: 0}, 300, "linear", function() {
@@ -753,7 +909,9 @@ const initializeSponsorDisplay = function() {
       slide.First = index === 0;
 
       let slideHtml;
-      if (slide.Image) {
+      if (slide.IsVideo) {
+        slideHtml = sponsorVideoTemplate(slide);
+      } else if (slide.Image) {
         slideHtml = sponsorImageTemplate(slide);
       } else {
         slideHtml = sponsorTextTemplate(slide);
@@ -825,6 +983,7 @@ $(function() {
   // Read the configuration for this display from the URL query string.
   const urlParams = new URLSearchParams(window.location.search);
   document.body.style.backgroundColor = urlParams.get("background");
+  overlayOnly = document.body.dataset.overlayOnly === "true";
   const reversed = urlParams.get("reversed");
   if (reversed === "true") {
     redSide = "right";
@@ -854,7 +1013,11 @@ $(function() {
     matchTiming: function(event) { handleMatchTiming(event.data); },
     playSound: function(event) { handlePlaySound(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
+    replay: function(event) { handleReplay(event.data); },
     scorePosted: function(event) { handleScorePosted(event.data); },
+    stationStops: function(event) { handleStationStops(event.data); },
+    streamAudienceDisplayMode: function(event) { handleAudienceDisplayMode(event.data); },
+    videoStinger: function(event) { handleVideoStinger(event.data); },
   });
 
   // Map how to transition from one screen to another. Missing links between screens indicate that first we
@@ -872,6 +1035,7 @@ $(function() {
       match: transitionBlankToMatch,
       score: transitionBlankToScore,
       sponsor: transitionBlankToSponsor,
+      stinger: transitionBlankToStinger,
       timeout: transitionBlankToTimeout,
     },
     bracket: {
@@ -916,6 +1080,9 @@ $(function() {
       logo: transitionSponsorToLogo,
       score: transitionSponsorToScore,
     },
+    stinger: {
+      blank: transitionStingerToBlank,
+    },
     timeout: {
       blank: transitionTimeoutToBlank,
       intro: transitionTimeoutToIntro,
@@ -4,17 +4,56 @@
 // Client-side methods for the bracket display.
 
 var websocket;
+var shownAdvancements;  // Keys of the match advancements already reflected in the bracket that is showing.
 
-// Handles a websocket message to load a new match.
-const handleMatchLoad = function(data) {
-  fetch("/api/bracket/svg?activeMatch=current")
-    .then(response => response.text())
-    .then(svg => $("#bracket").html(svg));
+// Identifies a match advancement, so that it can be told apart from the others when the bracket is refreshed.
+const getAdvancementKey = function(advancement) {
+  return advancement.MatchOrder + "-" + advancement.Status;
+};
+
+// Loads the latest bracket and shows it, animating any advancements that have happened since it was last shown.
+const updateBracket = function() {
+  Promise.all([
+    fetch("/api/bracket/svg?activeMatch=current").then(response => response.text()),
+    fetch("/api/bracket/advancements").then(response => response.json()),
+  ]).then(function([svg, advancements]) {
+    $("#bracket").html(svg);
+
+    // Don't animate anything the first time around, since all the advancements would otherwise appear to be new.
+    if (shownAdvancements !== undefined) {
+      advancements.filter(advancement => !shownAdvancements.has(getAdvancementKey(advancement)))
+        .forEach(animateAdvancement);
+    }
+    shownAdvancements = new Set(advancements.map(getAdvancementKey));
+  });
+};
+
+// Highlights the matchup that the given match was part of and, if the match decided it, the matchups that its
+// alliances have moved on to.
+const animateAdvancement = function(advancement) {
+  addMatchupClass(advancement.MatchGroupId, "updated");
+  if (!advancement.MatchupDecided) {
+    return;
+  }
+  addMatchupClass(advancement.WinnerDestinationId, "advanced");
+  if (!advancement.LoserIsEliminated) {
+    addMatchupClass(advancement.LoserDestinationId, "advanced");
+  }
+};
+
+// Adds the given class to the rendered block for the given matchup, if it is part of the bracket.
+const addMatchupClass = function(matchupId, className) {
+  const matchBlock = document.getElementById("match_" + matchupId);
+  if (matchupId && matchBlock) {
+    // The bracket is SVG, whose elements jQuery can't add classes to.
+    matchBlock.classList.add(className);
+  }
 };
 
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/bracket/websocket", {
-    matchLoad: function(event) { handleMatchLoad(event.data); },
+    matchLoad: function(event) { updateBracket(); },
+    scorePosted: function(event) { updateBracket(); },
   });
 });
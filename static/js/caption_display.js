@@ -0,0 +1,66 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the accessibility caption display.
+
+var websocket;
+
+// Handles a websocket message to show the name of the newly loaded match and clear the previous one's scores.
+const handleMatchLoad = function(data) {
+  $("#matchName").text(data.Match.LongName);
+  $("#redScore").text(0);
+  $("#blueScore").text(0);
+};
+
+// Handles a websocket message to update the match state and time countdown.
+const handleMatchTime = function(data) {
+  translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
+    $("#matchState").text(matchStateText);
+    $("#matchTime").text(matchState === "PRE_MATCH" ? "" : getCountdownString(countdownSec));
+  });
+};
+
+// Handles a websocket message to update the live scores.
+const handleRealtimeScore = function(data) {
+  $("#redScore").text(data.Red.ScoreSummary.Score);
+  $("#blueScore").text(data.Blue.ScoreSummary.Score);
+};
+
+// Handles a websocket message to announce the final score of the last committed match.
+const handleScorePosted = function(data) {
+  if (data.Match.Type === matchTypeTest) {
+    return;
+  }
+  const redScore = data.RedScoreSummary.Score;
+  const blueScore = data.BlueScoreSummary.Score;
+  let result = "Tie";
+  if (redScore > blueScore) {
+    result = "Red wins";
+  } else if (blueScore > redScore) {
+    result = "Blue wins";
+  }
+  $("#scorePosted").remove();
+  $("<div class='caption' id='scorePosted'>")
+    .text(`${data.Match.LongName} final score: ${result}, ${redScore} to ${blueScore}`)
+    .prependTo("#captions");
+};
+
+// Handles a websocket message to show the latest captions pushed by the announcer.
+const handleCaptions = function(data) {
+  $("#captions .caption").not("#scorePosted").remove();
+  $.each(data.Captions, function(i, caption) {
+    $("<div class='caption'>").text(caption.Text).appendTo("#captions");
+  });
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/captions/websocket", {
+    captions: function(event) { handleCaptions(event.data); },
+    matchLoad: function(event) { handleMatchLoad(event.data); },
+    matchTime: function(event) { handleMatchTime(event.data); },
+    matchTiming: function(event) { handleMatchTiming(event.data); },
+    realtimeScore: function(event) { handleRealtimeScore(event.data); },
+    scorePosted: function(event) { handleScorePosted(event.data); },
+  });
+});
@@ -3,6 +3,19 @@
 //
 // Shared code for initiating websocket connections back to the server for full-duplex communication.
 
+// Number of clock synchronization requests to send in each burst, and the interval between bursts.
+var timeSyncSampleCount = 5;
+var timeSyncIntervalMs = 60000;
+
+// Intervals at which to retry connecting to the server after losing the connection, depending on whether the server
+// announced that it was restarting.
+var reconnectIntervalMs = 3000;
+var restartReconnectIntervalMs = 500;
+
+// Path prefix of the pages of a field other than the first, which is empty for the first field. The field's own
+// websockets and resources are requested under the same prefix.
+var fieldPathPrefix = (window.location.pathname.match(/^\/fields\/\d+/) || [""])[0];
+
 var CheesyWebsocket = function(path, events) {
   var that = this;
   var protocol = "ws://";
@@ -13,7 +26,7 @@ var CheesyWebsocket = function(path, events) {
   if (window.location.port !== "") {
     url += ":" + window.location.port;
   }
-  url += path;
+  url += fieldPathPrefix + path;
 
   // Append the page's query string to the websocket URL.
   url += window.location.search;
@@ -49,14 +62,96 @@ var CheesyWebsocket = function(path, events) {
     };
   }
 
+  // Measure the offset between the local clock and the server's if this page shows the match clock, so that the time
+  // it takes for each match time message to be delivered and processed can be made up for. Keeps the samples having
+  // the shortest round trips since those give the tightest bound on the offset.
+  var clockOffsetMs = null;
+  var timeSyncSamples = [];
+  if (events.hasOwnProperty("matchTime")) {
+    events.timeSyncReply = function(event) {
+      var receivedTime = Date.now();
+      var roundTripMs = receivedTime - event.data;
+      timeSyncSamples.push({roundTripMs: roundTripMs, offsetMs: event.time - (event.data + roundTripMs / 2)});
+      timeSyncSamples.sort(function(a, b) { return a.roundTripMs - b.roundTripMs; });
+      timeSyncSamples = timeSyncSamples.slice(0, timeSyncSampleCount);
+      clockOffsetMs = timeSyncSamples[0].offsetMs;
+    };
+
+    var handleMatchTime = events.matchTime;
+    events.matchTime = function(event) {
+      event.data.LatencyMs = 0;
+      if (clockOffsetMs !== null && event.time) {
+        event.data.LatencyMs = Math.max(0, Date.now() + clockOffsetMs - event.time);
+      }
+      handleMatchTime.call(this, event);
+    };
+  }
+
+  // Keep track of which run of the server this page was loaded from, so that it can be reloaded after a restart to pick
+  // up any changes made to the server in the meantime. A restart announced by the server is retried more eagerly than
+  // an unexpected loss of the connection.
+  var serverInstanceId = null;
+  var serverRestarting = false;
+  events.serverInstanceReply = function(event) {
+    if (serverInstanceId !== null && event.data !== serverInstanceId) {
+      console.log("Server has restarted since the page was loaded. Reloading...");
+      location.reload();
+      return;
+    }
+    serverInstanceId = event.data;
+  };
+  events.serverRestarting = function(event) {
+    console.log("Server is restarting.");
+    serverRestarting = true;
+  };
+
+  // Ask the server to send only the fields of each state notification that have changed, and merge them here into the
+  // last full state of that type so that the handlers always see the complete data. The server starts over with full
+  // messages on each new connection.
+  var lastData = {};
+  $.each(events, function(messageType, handler) {
+    events[messageType] = function(event) {
+      if (event.delta) {
+        event.data = $.extend({}, lastData[messageType], event.data);
+        delete event.delta;
+      }
+      if ($.isPlainObject(event.data)) {
+        lastData[messageType] = $.extend({}, event.data);
+      } else {
+        delete lastData[messageType];
+      }
+      handler.call(this, event);
+    };
+  });
+
+  // Sends a burst of clock synchronization requests to the server.
+  var syncTime = function() {
+    timeSyncSamples = [];
+    for (var i = 0; i < timeSyncSampleCount; i++) {
+      setTimeout(function() {
+        if (that.websocket.readyState === WebSocket.OPEN) {
+          that.send("timeSync", Date.now());
+        }
+      }, i * 200);
+    }
+  };
+
   this.connect = function() {
     this.websocket = $.websocket(url, {
       open: function() {
         console.log("Websocket connected to the server at " + url + ".")
+        lastData = {};
+        serverRestarting = false;
+        that.send("serverInstance");
+        that.send("enableDeltas");
+        if (events.hasOwnProperty("timeSyncReply")) {
+          syncTime();
+        }
       },
       close: function() {
-        console.log("Websocket lost connection to the server. Reconnecting in 3 seconds...");
-        setTimeout(that.connect, 3000);
+        var intervalMs = serverRestarting ? restartReconnectIntervalMs : reconnectIntervalMs;
+        console.log("Websocket lost connection to the server. Reconnecting in " + intervalMs + " ms...");
+        setTimeout(that.connect, intervalMs);
       },
       events: events
     });
@@ -67,4 +162,8 @@ var CheesyWebsocket = function(path, events) {
   };
 
   this.connect();
+  if (events.hasOwnProperty("timeSyncReply")) {
+    // Periodically resynchronize in case either clock drifts.
+    setInterval(syncTime, timeSyncIntervalMs);
+  }
 };
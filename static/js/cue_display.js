@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the human player and announcer cue display.
+
+var websocket;
+let alliance;
+let audience;
+
+// Handles a websocket message to update the match time countdown.
+const handleMatchTime = function(data) {
+  translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
+    $("#matchState").text(matchStateText);
+    $("#matchTime").text(getCountdownString(countdownSec));
+  });
+};
+
+// Handles a websocket message to show the cues meant for this display's audience and alliance.
+const handleMatchCues = function(data) {
+  const cuesDiv = $("#cues");
+  cuesDiv.empty();
+  const addCues = function(cues, cueAlliance) {
+    $.each(cues, function(i, cue) {
+      if (cue.Audience === audience) {
+        cuesDiv.append($("<div class='cue'>").attr("data-alliance", cueAlliance).text(cue.Text));
+      }
+    });
+  };
+  addCues(data.Field, "");
+  if (alliance === "red" || alliance === "both") {
+    addCues(data.Red, "red");
+  }
+  if (alliance === "blue" || alliance === "both") {
+    addCues(data.Blue, "blue");
+  }
+};
+
+$(function() {
+  // Read the configuration for this display from the URL query string.
+  const urlParams = new URLSearchParams(window.location.search);
+  alliance = urlParams.get("alliance");
+  audience = urlParams.get("audience");
+  $("#header").attr("data-alliance", alliance);
+
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/cues/websocket", {
+    matchCues: function(event) { handleMatchCues(event.data); },
+    matchTime: function(event) { handleMatchTime(event.data); },
+    matchTiming: function(event) { handleMatchTiming(event.data); },
+  });
+});
@@ -0,0 +1,14 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Shared client-side logic for describing critical displays that have lost their connection.
+
+// Returns a description of the given disconnected display, including where it was last connected from.
+const formatDisplayAlert = function(displayAlert) {
+  let name = displayAlert.Type + " display " + displayAlert.DisplayId;
+  if (displayAlert.Nickname) {
+    name += " (" + displayAlert.Nickname + ")";
+  }
+  const disconnectedTime = new Date(displayAlert.DisconnectedTime).toLocaleTimeString();
+  return `${name} disconnected at ${disconnectedTime}; last seen at ${displayAlert.IpAddress || "unknown address"}`;
+};
@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the emcee display.
+
+var websocket;
+var numMatches;
+
+// Loads the scripts for the current and upcoming matches from the event server and redraws them.
+var loadScripts = function() {
+  $.getJSON("/api/emcee_scripts?numMatches=" + numMatches, function(scripts) {
+    const container = $("#scripts");
+    container.empty();
+    $.each(scripts, function(i, script) {
+      const scriptElement = $("<div class='emcee-script'></div>").attr("data-current", i === 0);
+      let title = script.LongName;
+      if (i === 0) {
+        title = "Now: " + title;
+      }
+      scriptElement.append($("<div class='emcee-script-title'></div>").text(title));
+      $.each(script.Lines, function(j, line) {
+        const lineElement = $("<div class='emcee-script-line'></div>").text(line);
+        if (j === 1) {
+          lineElement.attr("data-alliance", "red");
+        } else if (j === 2) {
+          lineElement.attr("data-alliance", "blue");
+        }
+        scriptElement.append(lineElement);
+      });
+      $.each(script.RedTeams.concat(script.BlueTeams), function(j, team) {
+        if (team.AnnouncerNotes) {
+          scriptElement.append($("<div class='emcee-script-notes'></div>").text(
+            `${team.TeamId}: ${team.AnnouncerNotes}`
+          ));
+        }
+      });
+      container.append(scriptElement);
+    });
+  });
+};
+
+$(function() {
+  // Read the configuration for this display from the URL query string.
+  const urlParams = new URLSearchParams(window.location.search);
+  numMatches = parseInt(urlParams.get("numMatches")) || 3;
+
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/emcee/websocket", {
+    matchLoad: function(event) { loadScripts(); },
+    scorePosted: function(event) { loadScripts(); },
+  });
+});
@@ -79,6 +79,7 @@ var handleArenaStatus = function(data) {
 
     const wifiStatus = stationStatus.WifiStatus;
     teamRadioTextElement.text(wifiStatus.TeamId);
+    teamRadioElement.attr("title", stationStatus.RadioPairingIssue || "Field Access Point Configured Team");
 
     if (stationStatus.DsConn) {
       // Format the driver station status box.
@@ -87,7 +88,8 @@ var handleArenaStatus = function(data) {
       teamDsElement.text(dsConn.MissedPacketCount);
 
       // Format the radio status box according to the connection status of the robot radio.
-      var radioOkay = stationStatus.Team && stationStatus.Team.Id === wifiStatus.TeamId && wifiStatus.RadioLinked;
+      var radioOkay = stationStatus.Team && stationStatus.Team.Id === wifiStatus.TeamId && wifiStatus.RadioLinked &&
+        !stationStatus.RadioPairingIssue;
       teamRadioElement.attr("data-status-ok", radioOkay);
 
       // Format the robot status box.
@@ -116,7 +118,7 @@ var handleArenaStatus = function(data) {
 
       // Format the robot status box according to whether the AP is configured with the correct SSID.
       var expectedTeamId = stationStatus.Team ? stationStatus.Team.Id : 0;
-      if (wifiStatus.TeamId === expectedTeamId) {
+      if (wifiStatus.TeamId === expectedTeamId && !stationStatus.RadioPairingIssue) {
         if (wifiStatus.RadioLinked) {
           teamRadioElement.attr("data-status-ok", true);
         } else {
@@ -199,6 +201,15 @@ var editFtaNotes = function(element) {
   });
 };
 
+// Handles a websocket message to prominently warn about critical displays that have lost their connection.
+var handleDisplayAlerts = function(data) {
+  var displayAlerts = $("#displayAlerts").empty();
+  $.each(data.DisplayAlerts || [], function(i, displayAlert) {
+    displayAlerts.append($("<div>").text(formatDisplayAlert(displayAlert)));
+  });
+  displayAlerts.attr("data-active", displayAlerts.children().length > 0);
+};
+
 $(function() {
   // Read the configuration for this display from the URL query string.
   var urlParams = new URLSearchParams(window.location.search);
@@ -229,6 +240,7 @@ $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/field_monitor/websocket", {
     arenaStatus: function(event) { handleArenaStatus(event.data); },
+    displayAlerts: function(event) { handleDisplayAlerts(event.data); },
     eventStatus: function(event) { handleEventStatus(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTiming: function(event) { handleMatchTiming(event.data); },
@@ -0,0 +1,71 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the league display.
+
+var websocket;
+var rowsPerPage;
+var pageDurationMs;
+var standings = [];
+var currentPageIndex = -1;
+var rotationTimeout;
+
+// Loads the league standings from the event server and redraws the current page.
+var loadStandings = function() {
+  $.getJSON("/api/league/standings", function(data) {
+    standings = data.Standings;
+    $("#leagueName").text(data.LeagueName);
+    showPage(currentPageIndex);
+  });
+};
+
+// Shows the page of standings at the given index, wrapping around at the end.
+var showPage = function(index) {
+  const numPages = Math.max(1, Math.ceil(standings.length / rowsPerPage));
+  currentPageIndex = ((index % numPages) + numPages) % numPages;
+  const rows = $("#standingsRows");
+  rows.empty();
+  const start = currentPageIndex * rowsPerPage;
+  $.each(standings.slice(start, start + rowsPerPage), function(i, standing) {
+    const row = $("<tr></tr>");
+    row.append($("<td></td>").text(standing.Rank));
+    row.append($("<td></td>").text(standing.TeamId));
+    row.append($("<td class='team-nickname'></td>").text(standing.Nickname));
+    row.append($("<td></td>").text(standing.Events.length));
+    row.append($("<td></td>").text(`${standing.Wins}-${standing.Losses}-${standing.Ties}`));
+    row.append($("<td class='league-points'></td>").text(standing.Points));
+    rows.append(row);
+  });
+  if (standings.length === 0) {
+    rows.append($("<tr><td colspan='6'>No league results yet</td></tr>"));
+  }
+  $("#pageNumber").text(numPages > 1 ? `Page ${currentPageIndex + 1} of ${numPages}` : "");
+};
+
+// Advances to the next page and schedules the one after it.
+var rotatePages = function() {
+  showPage(currentPageIndex + 1);
+  clearTimeout(rotationTimeout);
+  rotationTimeout = setTimeout(rotatePages, pageDurationMs);
+};
+
+// Handles a websocket message to update the event status message.
+var handleEventStatus = function(data) {
+  $("#earlyLateMessage").text(data.EarlyLateMessage);
+};
+
+$(function() {
+  // Read the configuration for this display from the URL query string.
+  const urlParams = new URLSearchParams(window.location.search);
+  rowsPerPage = parseInt(urlParams.get("rowsPerPage")) || 12;
+  pageDurationMs = (parseInt(urlParams.get("pageDurationSec")) || 10) * 1000;
+
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/league/websocket", {
+    eventStatus: function(event) { handleEventStatus(event.data); },
+    scorePosted: function(event) { loadStandings(); },
+  });
+
+  loadStandings();
+  rotatePages();
+});
@@ -6,6 +6,10 @@
 var websocket;
 let scoreIsReady;
 let isReplay;
+let matchListStale = false;
+let stationBypasses = {};
+let staffReadiness = {};
+let audienceDisplayModes = {stage: "", stream: ""};
 const lowBatteryThreshold = 8;
 
 // Sends a websocket message to load the specified match.
@@ -13,6 +17,23 @@ const loadMatch = function(matchId) {
   websocket.send("loadMatch", { matchId: matchId });
 }
 
+// Sends a websocket message to load the match that is on deck.
+const loadNextMatch = function() {
+  websocket.send("loadNextMatch");
+}
+
+// Sends a websocket message to choose the specified match to be played next, or to go back to the schedule order if
+// the match ID is zero.
+const setNextMatch = function(matchId) {
+  websocket.send("setNextMatch", { matchId: matchId });
+}
+
+// Sends a websocket message to mark the specified match as a bye to be skipped, or to clear the mark.
+const setMatchBye = function(matchId, bye) {
+  websocket.send("setMatchBye", { matchId: matchId, bye: bye });
+  matchListStale = true;
+}
+
 // Sends a websocket message to load the results for the specified match into the display buffer.
 const showResult = function(matchId) {
   websocket.send("showResult", { matchId: matchId });
@@ -32,9 +53,24 @@ const substituteTeams = function(team, position) {
   websocket.send("substituteTeams", teams);
 };
 
-// Sends a websocket message to toggle the bypass status for an alliance station.
+// Clears the bypass for an alliance station if it is bypassed, or otherwise prompts for the reason to bypass it.
 const toggleBypass = function(station) {
-  websocket.send("toggleBypass", station);
+  if (stationBypasses[station]) {
+    websocket.send("toggleBypass", { station: station });
+  } else {
+    $("#bypassStation").text(station);
+    $("#bypassReason").data("station", station).modal("show");
+  }
+};
+
+// Sends a websocket message to flip whether the given field staff role is ready for the match to start.
+const toggleStaffReady = function(role) {
+  websocket.send("setStaffReady", { role: role, ready: !staffReadiness[role] });
+};
+
+// Sends a websocket message to bypass the alliance station chosen in the bypass dialog for the given reason.
+const bypassStation = function(reason) {
+  websocket.send("toggleBypass", { station: $("#bypassReason").data("station"), reason: reason });
 };
 
 // Sends a websocket message to start the match.
@@ -48,6 +84,17 @@ const abortMatch = function() {
   websocket.send("abortMatch");
 };
 
+// Sends a websocket message to pause the match due to a field fault.
+const declareFieldFault = function() {
+  websocket.send("declareFieldFault", { reason: $("#fieldFaultReason").val() });
+  $("#fieldFaultReason").val("");
+};
+
+// Sends a websocket message to resume the match after a field fault.
+const resumeMatch = function() {
+  websocket.send("resumeMatch");
+};
+
 // Sends a websocket message to signal to the teams that they may enter the field.
 const signalReset = function() {
   websocket.send("signalReset");
@@ -77,9 +124,55 @@ const showFinalScore = function() {
   $("#showFinalScore").prop("disabled", true);
 }
 
-// Sends a websocket message to change what the audience display is showing.
+// Sends a websocket message to change what the selected audience display outputs are showing.
 const setAudienceDisplay = function() {
-  websocket.send("setAudienceDisplay", $("input[name=audienceDisplay]:checked").val());
+  const mode = $("input[name=audienceDisplay]:checked").val();
+  const output = $("#audienceDisplayOutput").val();
+  if (output === "both") {
+    websocket.send("setAudienceDisplay", mode);
+  } else {
+    websocket.send("setAudienceDisplayOutput", {output: output, mode: mode});
+  }
+};
+
+// Sends a websocket message to move the audience display on to the next step of the show flow.
+const advanceShowFlow = function() {
+  websocket.send("advanceShowFlow");
+};
+
+// Sends a websocket message to start the show flow over from its first step.
+const resetShowFlow = function() {
+  websocket.send("resetShowFlow");
+};
+
+// Sends a websocket message to show the given saved lower third on the audience display.
+const showLowerThird = function(lowerThirdId) {
+  websocket.send("showLowerThird", lowerThirdId);
+};
+
+// Sends a websocket message to hide the lower third on the audience display.
+const hideLowerThird = function() {
+  websocket.send("hideLowerThird");
+};
+
+// Sends a websocket message to choose which lower third playlist to step through.
+const selectLowerThirdPlaylist = function() {
+  websocket.send("selectLowerThirdPlaylist", parseInt($("#lowerThirdPlaylist").val()));
+};
+
+// Sends a websocket message to show the next lower third in the selected playlist.
+const advanceLowerThirdPlaylist = function() {
+  websocket.send("advanceLowerThirdPlaylist");
+};
+
+// Sends a websocket message to play the given video stinger full-screen on the audience display.
+const playVideoStinger = function(videoStingerId) {
+  websocket.send("playVideoStinger", videoStingerId);
+};
+
+// Sends a websocket message to have the replay system mark a clip of the last few seconds.
+const markReplay = function() {
+  websocket.send("markReplay");
 };
 
 // Sends a websocket message to change what the alliance station display is showing.
@@ -97,6 +190,11 @@ const startTimeout = function() {
   websocket.send("startTimeout", durationSec);
 };
 
+// Sends a websocket message to start a timeout on behalf of the given playoff alliance.
+const callAllianceTimeout = function(alliance) {
+  websocket.send("callAllianceTimeout", alliance);
+};
+
 const confirmCommit = function() {
   if (isReplay || !scoreIsReady) {
     // Show the appropriate message(s) in the confirmation dialog.
@@ -108,6 +206,21 @@ const confirmCommit = function() {
   }
 };
 
+// Sends a websocket message to turn the scripted robots and scoring inputs on or off.
+const setRobotSimulation = function() {
+  websocket.send("setRobotSimulation", $("#robotSimulation").prop("checked"));
+};
+
+// Sends a websocket message to pick back up the match that was in progress when the server last stopped.
+const resumeInterruptedMatch = function() {
+  websocket.send("resumeInterruptedMatch");
+};
+
+// Sends a websocket message to throw away the match that was in progress when the server last stopped.
+const discardInterruptedMatch = function() {
+  websocket.send("discardInterruptedMatch");
+};
+
 // Sends a websocket message to specify a custom name for the current test match.
 const setTestMatchName = function() {
   websocket.send("setTestMatchName", $("#testMatchName").val());
@@ -154,7 +267,7 @@ const handleArenaStatus = function(data) {
     // status of the robot radio.
     const expectedTeamId = stationStatus.Team ? stationStatus.Team.Id : 0;
     let radioStatus = 0;
-    if (expectedTeamId === wifiStatus.TeamId) {
+    if (expectedTeamId === wifiStatus.TeamId && !stationStatus.RadioPairingIssue) {
       if (wifiStatus.RadioLinked) {
         radioStatus = 2;
       } else {
@@ -162,7 +275,10 @@ const handleArenaStatus = function(data) {
       }
     }
     $(`#status${station} .radio-status`).attr("data-status-ternary", radioStatus);
+    $(`#status${station} .radio-status`).attr("title", stationStatus.RadioPairingIssue);
 
+    stationBypasses[station] = stationStatus.Bypass;
+    $("#status" + station + " .bypass-status").attr("title", stationStatus.BypassReason);
     if (stationStatus.EStop) {
       $("#status" + station + " .bypass-status").attr("data-status-ok", false);
       $("#status" + station + " .bypass-status").text("ES");
@@ -178,11 +294,32 @@ const handleArenaStatus = function(data) {
     }
   });
 
+  $("#robotSimulation").prop("checked", data.RobotSimulationEnabled);
+  $("#robotSimulation").prop("disabled", matchStates[data.MatchState] !== "PRE_MATCH");
+  $("#robotSimulationActive").toggle(data.RobotSimulationEnabled);
+  staffReadiness = {
+    headReferee: data.StaffReadiness.HeadRefereeReady,
+    scorekeeper: data.StaffReadiness.ScorekeeperReady,
+    fta: data.StaffReadiness.FtaReady,
+  };
+  $.each(staffReadiness, function(role, ready) {
+    const prefix = role === "scorekeeper" ? "btn" : "bg";
+    $(`#${role}Ready`).toggleClass(`${prefix}-success`, ready).toggleClass(`${prefix}-secondary`, !ready);
+  });
+  $("#showFlowSteps li").each(function(position) {
+    $(this).toggleClass("fw-bold", position === data.ShowFlowPosition);
+  });
+  $("#interruptedMatch").toggle(data.InterruptedMatchName !== "");
+  $("#interruptedMatchName").text(data.InterruptedMatchName);
+  $("#resumeInterruptedMatch").prop("disabled", matchStates[data.MatchState] !== "PRE_MATCH");
+
   // Enable/disable the buttons based on the current match state.
   switch (matchStates[data.MatchState]) {
     case "PRE_MATCH":
       $("#startMatch").prop("disabled", !data.CanStartMatch);
       $("#abortMatch").prop("disabled", true);
+      $("#fieldFault").prop("disabled", true);
+      $("#resumeMatch").prop("disabled", true);
       $("#signalReset").prop("disabled", false);
       $("#fieldResetRadio").prop("disabled", false);
       $("#commitResults").prop("disabled", true);
@@ -201,6 +338,24 @@ const handleArenaStatus = function(data) {
       $("#scoreRadio").prop("disabled", true);
       $("#startMatch").prop("disabled", true);
       $("#abortMatch").prop("disabled", false);
+      $("#fieldFault").prop("disabled", matchStates[data.MatchState] === "START_MATCH");
+      $("#resumeMatch").prop("disabled", true);
+      $("#signalReset").prop("disabled", true);
+      $("#fieldResetRadio").prop("disabled", true);
+      $("#commitResults").prop("disabled", true);
+      $("#discardResults").prop("disabled", true);
+      $("#editResults").prop("disabled", true);
+      $("#startTimeout").prop("disabled", true);
+      break;
+    case "FIELD_FAULT":
+      $("#showOverlay").prop("disabled", true);
+      $("#introRadio").prop("disabled", true);
+      $("#showFinalScore").prop("disabled", true);
+      $("#scoreRadio").prop("disabled", true);
+      $("#startMatch").prop("disabled", true);
+      $("#abortMatch").prop("disabled", false);
+      $("#fieldFault").prop("disabled", true);
+      $("#resumeMatch").prop("disabled", false);
       $("#signalReset").prop("disabled", true);
       $("#fieldResetRadio").prop("disabled", true);
       $("#commitResults").prop("disabled", true);
@@ -215,6 +370,8 @@ const handleArenaStatus = function(data) {
       $("#scoreRadio").prop("disabled", true);
       $("#startMatch").prop("disabled", true);
       $("#abortMatch").prop("disabled", true);
+      $("#fieldFault").prop("disabled", true);
+      $("#resumeMatch").prop("disabled", true);
       $("#signalReset").prop("disabled", false);
       $("#fieldResetRadio").prop("disabled", false);
       $("#commitResults").prop("disabled", false);
@@ -229,6 +386,8 @@ const handleArenaStatus = function(data) {
       $("#scoreRadio").prop("disabled", false);
       $("#startMatch").prop("disabled", true);
       $("#abortMatch").prop("disabled", false);
+      $("#fieldFault").prop("disabled", true);
+      $("#resumeMatch").prop("disabled", true);
       $("#signalReset").prop("disabled", true);
       $("#fieldResetRadio").prop("disabled", false);
       $("#commitResults").prop("disabled", true);
@@ -243,6 +402,8 @@ const handleArenaStatus = function(data) {
       $("#scoreRadio").prop("disabled", false);
       $("#startMatch").prop("disabled", true);
       $("#abortMatch").prop("disabled", true);
+      $("#fieldFault").prop("disabled", true);
+      $("#resumeMatch").prop("disabled", true);
       $("#signalReset").prop("disabled", true);
       $("#fieldResetRadio").prop("disabled", false);
       $("#commitResults").prop("disabled", true);
@@ -268,11 +429,29 @@ const handleArenaStatus = function(data) {
   });
 };
 
+// Handles a websocket message to highlight the lower third that is showing and the position in the selected playlist.
+const handleLowerThird = function(data) {
+  const showingId = data.ShowLowerThird && data.LowerThird !== null ? data.LowerThird.Id : 0;
+  $("#lowerThirdButtons button").each(function() {
+    const isShowing = parseInt($(this).attr("data-lower-third-id")) === showingId;
+    $(this).toggleClass("btn-success", isShowing).toggleClass("btn-secondary", !isShowing);
+  });
+
+  $("#lowerThirdPlaylist").val(data.PlaylistId);
+  $(".lower-third-playlist").each(function() {
+    const isSelected = parseInt($(this).attr("data-playlist-id")) === data.PlaylistId;
+    $(this).toggle(isSelected);
+    $(this).find("li").each(function(position) {
+      $(this).toggleClass("fw-bold", isSelected && position === data.PlaylistPosition);
+    });
+  });
+};
+
 // Handles a websocket message to update the teams for the current match.
 const handleMatchLoad = function(data) {
   isReplay = data.IsReplay;
 
-  fetch("/match_play/match_load")
+  fetch(fieldPathPrefix + "/match_play/match_load")
     .then(response => response.text())
     .then(html => $("#matchListColumn").html(html));
 
@@ -286,12 +465,47 @@ const handleMatchLoad = function(data) {
   });
   $("#playoffRedAllianceInfo").html(formatPlayoffAllianceInfo(data.Match.PlayoffRedAlliance, data.RedOffFieldTeams));
   $("#playoffBlueAllianceInfo").html(formatPlayoffAllianceInfo(data.Match.PlayoffBlueAlliance, data.BlueOffFieldTeams));
+  $("#allianceTimeouts").toggle(data.Match.Type === matchTypePlayoff);
+  $("#redAllianceTimeout .timeouts-remaining").text(data.RedTimeoutsRemaining);
+  $("#redAllianceTimeout").prop("disabled", data.RedTimeoutsRemaining === 0);
+  $("#blueAllianceTimeout .timeouts-remaining").text(data.BlueTimeoutsRemaining);
+  $("#blueAllianceTimeout").prop("disabled", data.BlueTimeoutsRemaining === 0);
 
   $("#substituteTeams").prop("disabled", true);
   $("#showOverlay").prop("disabled", false);
   $("#introRadio").prop("disabled", false);
 }
 
+// Handles a websocket message to show whether there are event settings changes waiting for the match to end.
+const handleEventSettings = function(data) {
+  $("#settingsPending").toggle(data.ChangesPending);
+};
+
+// Handles a websocket message to update the lineup and any outstanding issues for the match following this one.
+const handleOnDeck = function(data) {
+  if (matchListStale) {
+    // Redraw the match list to reflect a match that was just marked or unmarked as a bye.
+    matchListStale = false;
+    fetch(fieldPathPrefix + "/match_play/match_load")
+      .then(response => response.text())
+      .then(html => $("#matchListColumn").html(html));
+  }
+  $("#onDeck").toggle(data !== null);
+  if (data === null) {
+    return;
+  }
+  $("#onDeckMatchName").text(data.Match.LongName);
+  $("#onDeckOverride").toggle(data.IsOverride);
+  $.each(data.Teams, function(station, team) {
+    $(`#onDeck${station}`).text(team ? team.Id : "");
+  });
+  $("#onDeckIssues").empty();
+  $.each(data.Issues, function(i, issue) {
+    $("#onDeckIssues").append($("<li>").text(issue));
+  });
+  $("#onDeckReady").toggle(data.Issues.length === 0);
+};
+
 // Handles a websocket message to update the match time countdown.
 const handleMatchTime = function(data) {
   translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
@@ -318,20 +532,43 @@ const handleScorePosted = function(data) {
   $("#savedMatchName").html(matchName);
 }
 
-// Handles a websocket message to update the audience display screen selector.
-const handleAudienceDisplayMode = function(data) {
+// Handles a websocket message to update the screen that the given audience display output is showing.
+const handleAudienceDisplayMode = function(output, data) {
+  audienceDisplayModes[output] = data;
+  updateAudienceDisplaySelector();
+};
+
+// Updates the audience display screen selector to reflect the output being controlled, and notes when the stage and
+// stream are showing different screens.
+const updateAudienceDisplaySelector = function() {
+  const output = $("#audienceDisplayOutput").val();
+  const mode = output === "stream" ? audienceDisplayModes.stream : audienceDisplayModes.stage;
   $("input[name=audienceDisplay]:checked").prop("checked", false);
-  $("input[name=audienceDisplay][value=" + data + "]").prop("checked", true);
+  $("input[name=audienceDisplay][value=" + mode + "]").prop("checked", true);
+
+  let status = "";
+  if (audienceDisplayModes.stage !== audienceDisplayModes.stream) {
+    status = `Stage: ${audienceDisplayModes.stage}, Stream: ${audienceDisplayModes.stream}`;
+  }
+  $("#audienceDisplayOutputStatus").text(status);
 };
 
 // Handles a websocket message to signal whether the referee and scorers have committed after the match.
 const handleScoringStatus = function(data) {
   scoreIsReady = data.RefereeScoreReady && data.RedScoreReady && data.BlueScoreReady;
   $("#refereeScoreStatus").attr("data-ready", data.RefereeScoreReady);
+  $("#cardsStatus").attr("data-ready", !data.CardConfirmationPending);
   $("#redScoreStatus").text("Red Scoring " + data.NumRedScoringPanelsReady + "/" + data.NumRedScoringPanels);
   $("#redScoreStatus").attr("data-ready", data.RedScoreReady);
   $("#blueScoreStatus").text("Blue Scoring " + data.NumBlueScoringPanelsReady + "/" + data.NumBlueScoringPanels);
   $("#blueScoreStatus").attr("data-ready", data.BlueScoreReady);
+  $("#scoreReviewStatus").attr("data-ready", !data.ScoreReviewPending);
+  if (data.ScoreReview) {
+    $("#scoreReviewStatus").text("Score Review " + (data.ScoreReview.ScorekeeperApproved ? "SK " : "") +
+      (data.ScoreReview.HeadRefereeApproved ? "HR" : ""));
+  } else {
+    $("#scoreReviewStatus").text("Score Review");
+  }
 };
 
 // Handles a websocket message to update the alliance station display screen selector.
@@ -370,13 +607,40 @@ $(function() {
   websocket = new CheesyWebsocket("/match_play/websocket", {
     allianceStationDisplayMode: function(event) { handleAllianceStationDisplayMode(event.data); },
     arenaStatus: function(event) { handleArenaStatus(event.data); },
-    audienceDisplayMode: function(event) { handleAudienceDisplayMode(event.data); },
+    audienceDisplayMode: function(event) { handleAudienceDisplayMode("stage", event.data); },
+    eventSettings: function(event) { handleEventSettings(event.data); },
     eventStatus: function(event) { handleEventStatus(event.data); },
+    lowerThird: function(event) { handleLowerThird(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
     matchTiming: function(event) { handleMatchTiming(event.data); },
+    onDeck: function(event) { handleOnDeck(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
     scorePosted: function(event) { handleScorePosted(event.data); },
     scoringStatus: function(event) { handleScoringStatus(event.data); },
+    streamAudienceDisplayMode: function(event) { handleAudienceDisplayMode("stream", event.data); },
+  });
+
+  // Let the producer step through the show flow, show lower thirds and mark replays with single keys, as long as they
+  // aren't typing into a field.
+  $(document).keydown(function(event) {
+    if (event.ctrlKey || event.metaKey || event.altKey || $(event.target).is("input, select, textarea")) {
+      return;
+    }
+    const key = event.key.toLowerCase();
+    if (key === "n" && $("#showFlowSteps").length > 0) {
+      advanceShowFlow();
+    } else if (/^[1-9]$/.test(key) && $("#lowerThirdButtons button").length >= parseInt(key)) {
+      $("#lowerThirdButtons button").eq(parseInt(key) - 1).click();
+    } else if (key === "h" && $("#lowerThirdButtons").length > 0) {
+      hideLowerThird();
+    } else if (key === "l" && $("#lowerThirdPlaylist").length > 0) {
+      advanceLowerThirdPlaylist();
+    } else if (key === "r" && $("#markReplayButton").length > 0) {
+      markReplay();
+    } else {
+      return;
+    }
+    event.preventDefault();
   });
 });
@@ -18,7 +18,8 @@ const matchStates = {
   5: "TELEOP_PERIOD",
   6: "POST_MATCH",
   7: "TIMEOUT_ACTIVE",
-  8: "POST_TIMEOUT"
+  8: "POST_TIMEOUT",
+  9: "FIELD_FAULT"
 };
 let matchTiming;
 
@@ -55,8 +56,25 @@ const translateMatchTime = function(data, callback) {
     case "POST_TIMEOUT":
       matchStateText = "TIMEOUT";
       break;
+    case "FIELD_FAULT":
+      matchStateText = "FIELD FAULT";
+      break;
+  }
+  callback(matchStates[data.MatchState], matchStateText, getCountdown(data.MatchState, getCorrectedMatchTimeSec(data)));
+};
+
+// Returns the time into the match at which the given message is being handled, making up for however long the message
+// took to arrive if the match clock is running.
+const getCorrectedMatchTimeSec = function(data) {
+  switch (matchStates[data.MatchState]) {
+    case "AUTO_PERIOD":
+    case "PAUSE_PERIOD":
+    case "TELEOP_PERIOD":
+    case "TIMEOUT_ACTIVE":
+      return data.MatchTimeSec + Math.floor((data.LatencyMs || 0) / 1000);
+    default:
+      return data.MatchTimeSec;
   }
-  callback(matchStates[data.MatchState], matchStateText, getCountdown(data.MatchState, data.MatchTimeSec));
 };
 
 // Returns the per-period countdown for the given match state and overall time into the match.
@@ -67,12 +85,18 @@ const getCountdown = function(matchState, matchTimeSec) {
     case "WARMUP_PERIOD":
       return matchTiming.AutoDurationSec;
     case "AUTO_PERIOD":
-      return matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec - matchTimeSec;
+      return Math.max(0, matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec - matchTimeSec);
     case "TELEOP_PERIOD":
-      return matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec + matchTiming.TeleopDurationSec +
-          matchTiming.PauseDurationSec - matchTimeSec;
+      return Math.max(0, matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec + matchTiming.TeleopDurationSec +
+          matchTiming.PauseDurationSec - matchTimeSec);
     case "TIMEOUT_ACTIVE":
-      return matchTiming.TimeoutDurationSec - matchTimeSec;
+      return Math.max(0, matchTiming.TimeoutDurationSec - matchTimeSec);
+    case "FIELD_FAULT":
+      // Show the stopped countdown of whichever period the fault interrupted.
+      if (matchTimeSec < matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec) {
+        return getCountdown(3, matchTimeSec);
+      }
+      return Math.min(matchTiming.TeleopDurationSec, getCountdown(5, matchTimeSec));
     default:
       return 0;
   }
@@ -0,0 +1,72 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the pit display.
+
+var websocket;
+var pages = [];  // The names of the pages to rotate through, in order.
+var pageDurationMs = 15000;
+var currentPageIndex = -1;
+var rotationTimeout;
+
+// Reloads the server-rendered rankings, schedule and results pages.
+var loadPages = function() {
+  fetch("/displays/pit/pages")
+    .then(response => response.text())
+    .then(html => {
+      $("#pages").html(html);
+      showPage(currentPageIndex);
+    });
+};
+
+// Returns the list of pages that currently have something to show.
+var getVisiblePages = function() {
+  return pages.filter(function(page) {
+    return page !== "announcement" || $("#announcement").text() !== "";
+  });
+};
+
+// Shows the page at the given index among the visible pages.
+var showPage = function(index) {
+  const visiblePages = getVisiblePages();
+  $(".pit-page").hide();
+  if (visiblePages.length === 0) {
+    $("#pageTitle").text("");
+    return;
+  }
+  currentPageIndex = ((index % visiblePages.length) + visiblePages.length) % visiblePages.length;
+  const page = $(`.pit-page[data-page="${visiblePages[currentPageIndex]}"]`);
+  $("#pageTitle").text(page.data("title"));
+  page.show();
+};
+
+// Advances to the next page and schedules the one after it.
+var rotatePages = function() {
+  showPage(currentPageIndex + 1);
+  clearTimeout(rotationTimeout);
+  rotationTimeout = setTimeout(rotatePages, pageDurationMs);
+};
+
+// Handles a websocket message to update the rotation configuration.
+var handlePitDisplay = function(data) {
+  pages = data.Pages;
+  pageDurationMs = data.PageDurationSec * 1000;
+  $("#announcement").text(data.Announcement);
+  rotatePages();
+};
+
+// Handles a websocket message to update the event status message.
+var handleEventStatus = function(data) {
+  $("#earlyLateMessage").text(data.EarlyLateMessage);
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/pit/websocket", {
+    agenda: function(event) { handleAgenda(event); },
+    eventStatus: function(event) { handleEventStatus(event.data); },
+    matchLoad: function(event) { loadPages(); },
+    pitDisplay: function(event) { handlePitDisplay(event.data); },
+    scorePosted: function(event) { loadPages(); },
+  });
+});
@@ -0,0 +1,46 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the practice field timer page.
+
+var websocket;
+
+// Sends a websocket message to start the practice match timer.
+var startMatch = function() {
+  websocket.send("startMatch");
+};
+
+// Sends a websocket message to stop the practice match timer before the end of the match.
+var abortMatch = function() {
+  websocket.send("abortMatch");
+};
+
+// Handles a websocket message to update the practice match time countdown and the state of the controls.
+var handleMatchTime = function(data) {
+  translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
+    $("#matchState").text(matchStateText);
+    $("#matchTime").text(countdownSec);
+    var running = matchState !== "PRE_MATCH" && matchState !== "POST_MATCH";
+    $("#startMatchButton").prop("disabled", running);
+    $("#abortMatchButton").prop("disabled", !running);
+  });
+};
+
+// Handles a websocket message to play a match sound through this browser.
+var handlePlaySound = function(data) {
+  $("audio").each(function(k, v) {
+    // Stop and reset any sounds that are still playing.
+    v.pause();
+    v.currentTime = 0;
+  });
+  $("#sound-" + data.Name)[0].play();
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/practice_field/websocket", {
+    matchTime: function(event) { handleMatchTime(event.data); },
+    matchTiming: function(event) { handleMatchTiming(event.data); },
+    playSound: function(event) { handlePlaySound(event.data); },
+  });
+});
@@ -4,17 +4,67 @@
 // Client-side logic for the queueing display.
 
 var websocket;
+var breakDescription = "";
+var calloutsEnabled;  // Whether to call out the teams that are due in the queue.
+var calloutsRefreshIntervalMs = 5000;
+var teamQueueStatuses = {};
 
 // Handles a websocket message to update the teams for the current match.
 var handleMatchLoad = function(data) {
+  breakDescription = data.BreakDescription;
   fetch("/displays/queueing/match_load")
     .then(response => response.text())
-    .then(html => $("#matches").html(html));
+    .then(html => {
+      $("#matches").html(html);
+      applyTeamQueueStatuses();
+    });
+};
+
+// Loads the projected match times from the server and updates the list of teams that are due in the queue.
+var updateCallouts = function() {
+  $.getJSON("/api/queueing", function(data) {
+    // Tally each team's most urgent status across all of its upcoming matches, keeping them in match order.
+    const calloutTeamIds = [];
+    teamQueueStatuses = {};
+    $.each(data.Matches, function(i, match) {
+      $.each(match.Teams, function(j, team) {
+        if (team.Status === "") {
+          return;
+        }
+        if (!(team.TeamId in teamQueueStatuses)) {
+          calloutTeamIds.push(team.TeamId);
+        }
+        if (teamQueueStatuses[team.TeamId] !== "overdue") {
+          teamQueueStatuses[team.TeamId] = team.Status;
+        }
+      });
+    });
+
+    const calloutTeams = $("#calloutTeams");
+    calloutTeams.empty();
+    $.each(calloutTeamIds, function(i, teamId) {
+      calloutTeams.append($("<span>").addClass(`callout-team queue-${teamQueueStatuses[teamId]}`).text(teamId));
+    });
+    $("#callouts").toggle(calloutTeamIds.length > 0);
+    applyTeamQueueStatuses();
+  });
+};
+
+// Highlights the team numbers in the list of upcoming matches according to whether they are due in the queue.
+var applyTeamQueueStatuses = function() {
+  $(".queue-team").each(function() {
+    const status = teamQueueStatuses[$(this).data("team")];
+    $(this).toggleClass("queue-due", status === "due");
+    $(this).toggleClass("queue-overdue", status === "overdue");
+  });
 };
 
 // Handles a websocket message to update the match time countdown.
 var handleMatchTime = function(data) {
   translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
+    if (matchState === "TIMEOUT_ACTIVE" && breakDescription !== "") {
+      matchStateText = breakDescription.toUpperCase();
+    }
     $("#matchState").text(matchStateText);
     var countdownString = String(countdownSec % 60);
     if (countdownString.length === 1) {
@@ -31,8 +81,18 @@ var handleEventStatus = function(data) {
 };
 
 $(function() {
+  // Read the configuration for this display from the URL query string.
+  const urlParams = new URLSearchParams(window.location.search);
+  calloutsEnabled = urlParams.get("callouts") === "true";
+  $("#callouts").hide();
+  if (calloutsEnabled) {
+    updateCallouts();
+    setInterval(updateCallouts, calloutsRefreshIntervalMs);
+  }
+
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/queueing/websocket", {
+    agenda: function(event) { handleAgenda(event); },
     eventStatus: function(event) { handleEventStatus(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
@@ -9,6 +9,8 @@ var initialDwellMs = 3000;  // How long the display waits upon initial load befo
 var scrollMsPerRow;  // How long in milliseconds it takes to scroll a height of one row.
 var staticUpdateIntervalMs = 10000;  // How long between updates if not scrolling.
 var standingsTemplate = Handlebars.compile($("#standingsTemplate").html());
+var sponsorSlideTemplate = Handlebars.compile($("#sponsorSlideTemplate").html());
+var sponsorSlidesEnabled;  // Whether to show the sponsor slides in between passes through the standings.
 var rankingsData;
 var prevHighestPlayedMatch;
 
@@ -35,7 +37,7 @@ var updateStaticRankings = function() {
       setTimeout(cycleRankings, initialDwellMs);
     } else {
       // Rankings are too short; just update in place.
-      setTimeout(updateStaticRankings, staticUpdateIntervalMs);
+      setTimeout(function() { showSponsorSlides(updateStaticRankings); }, staticUpdateIntervalMs);
     }
   });
 };
@@ -59,7 +61,9 @@ var cycleRankings = function() {
     // Kick off another scrolling animation.
     var scrollDistance = $("#rankings1").height() + parseInt($("#rankings1").css("border-bottom-width"));
     var scrollTime = scrollMsPerRow * $("#rankings1 tr").length;
-    $("#scroller").transition({y: -scrollDistance}, scrollTime, "linear", cycleRankings);
+    $("#scroller").transition({y: -scrollDistance}, scrollTime, "linear", function() {
+      showSponsorSlides(cycleRankings);
+    });
 
     // Set the data to be reloaded two seconds before the scrolling terminates.
     var reloadDataTime = Math.max(0, scrollTime - 2000);
@@ -70,6 +74,30 @@ var cycleRankings = function() {
   }
 };
 
+// Covers the standings with one pass through the sponsor slides currently in rotation, if enabled, and then invokes the
+// given callback.
+var showSponsorSlides = function(callback) {
+  if (!sponsorSlidesEnabled) {
+    callback();
+    return;
+  }
+  $.getJSON("/api/sponsor_slides", function(slides) {
+    showSponsorSlide(slides, 0, callback);
+  }).fail(callback);
+};
+
+// Shows the sponsor slide at the given index for its display time before moving on to the next one.
+var showSponsorSlide = function(slides, index, callback) {
+  if (index >= slides.length) {
+    $("#sponsorSlide").hide().empty();
+    callback();
+    return;
+  }
+  var slide = slides[index];
+  $("#sponsorSlide").html(sponsorSlideTemplate(slide)).show();
+  setTimeout(function() { showSponsorSlide(slides, index + 1, callback); }, slide.DisplayTimeSec * 1000);
+};
+
 // Updates the "Standings as of" message with the given value, or blanks it out if there is no data yet.
 var setHighestPlayedMatch = function(highestPlayedMatch) {
   if (highestPlayedMatch === "") {
@@ -88,6 +116,7 @@ $(function() {
   // Read the configuration for this display from the URL query string.
   var urlParams = new URLSearchParams(window.location.search);
   scrollMsPerRow = urlParams.get("scrollMsPerRow");
+  sponsorSlidesEnabled = urlParams.get("sponsorSlides") === "true";
 
   // Set up the websocket back to the server. Used only for remote forcing of reloads.
   websocket = new CheesyWebsocket("/displays/rankings/websocket", {
@@ -13,23 +13,23 @@ const addFoul = function(alliance, isTechnical) {
 }
 
 // Toggles the foul type between technical and non-technical.
-const toggleFoulType = function(alliance, index) {
-  websocket.send("toggleFoulType", {Alliance: alliance, Index: index});
+const toggleFoulType = function(alliance, foulId, revision) {
+  websocket.send("toggleFoulType", {Alliance: alliance, FoulId: foulId, Revision: revision});
 }
 
 // Updates the team that the foul is attributed to.
-const updateFoulTeam = function(alliance, index, teamId) {
-  websocket.send("updateFoulTeam", {Alliance: alliance, Index: index, TeamId: teamId});
+const updateFoulTeam = function(alliance, foulId, revision, teamId) {
+  websocket.send("updateFoulTeam", {Alliance: alliance, FoulId: foulId, Revision: revision, TeamId: teamId});
 }
 
 // Updates the rule that the foul is for.
-const updateFoulRule = function(alliance, index, ruleId) {
-  websocket.send("updateFoulRule", {Alliance: alliance, Index: index, RuleId: ruleId});
+const updateFoulRule = function(alliance, foulId, revision, ruleId) {
+  websocket.send("updateFoulRule", {Alliance: alliance, FoulId: foulId, Revision: revision, RuleId: ruleId});
 }
 
 // Removes the foul with the given parameters from the list.
-var deleteFoul = function(alliance, index) {
-  websocket.send("deleteFoul", {Alliance: alliance, Index: index});
+var deleteFoul = function(alliance, foulId, revision) {
+  websocket.send("deleteFoul", {Alliance: alliance, FoulId: foulId, Revision: revision});
 };
 
 // Cycles through no card, yellow card, and red card.
@@ -47,6 +47,25 @@ var cycleCard = function(cardButton) {
   $(cardButton).attr("data-card", newCard);
 };
 
+// Gives the team the card chosen in the post-match card assignment, for the rule selected alongside it.
+const assignCard = function(cardButton) {
+  const row = $(cardButton).closest(".card-assignment");
+  websocket.send(
+    "assignCard",
+    {
+      Alliance: row.attr("data-alliance"),
+      TeamId: parseInt(row.attr("data-team")),
+      Card: $(cardButton).attr("data-card"),
+      RuleId: parseInt(row.find(".rule-select").val()),
+    }
+  );
+};
+
+// Signals that the head referee has finished assigning cards for this match.
+const confirmCards = function() {
+  websocket.send("confirmCards");
+};
+
 // Sends a websocket message to signal to the teams that they may enter the field.
 var signalReset = function() {
   websocket.send("signalReset");
@@ -61,17 +80,26 @@ var commitMatch = function() {
 var handleMatchLoad = function(data) {
   $("#matchName").text(data.Match.LongName);
 
-  setTeamCard("red", 1, data.Teams["R1"]);
-  setTeamCard("red", 2, data.Teams["R2"]);
-  setTeamCard("red", 3, data.Teams["R3"]);
-  setTeamCard("blue", 1, data.Teams["B1"]);
-  setTeamCard("blue", 2, data.Teams["B2"]);
-  setTeamCard("blue", 3, data.Teams["B3"]);
+  setTeamCard("red", 1, data.Teams["R1"], data.YellowCards["R1"]);
+  setTeamCard("red", 2, data.Teams["R2"], data.YellowCards["R2"]);
+  setTeamCard("red", 3, data.Teams["R3"], data.YellowCards["R3"]);
+  setTeamCard("blue", 1, data.Teams["B1"], data.YellowCards["B1"]);
+  setTeamCard("blue", 2, data.Teams["B2"], data.YellowCards["B2"]);
+  setTeamCard("blue", 3, data.Teams["B3"], data.YellowCards["B3"]);
+  for (const [station, team] of Object.entries(data.Teams)) {
+    const alliance = station[0] === "R" ? "red" : "blue";
+    const row = $(`#${alliance}Team${station[1]}CardAssignment`);
+    row.attr("data-team", team === null ? 0 : team.Id);
+    row.attr("data-card", "");
+    row.find(".card-assignment-team").text(team === null ? 0 : team.Id);
+    row.find(".rule-select").val(0);
+  }
 };
 
 // Handles a websocket message to update the match status.
 const handleMatchTime = function(data) {
   $(".control-button").attr("data-enabled", matchStates[data.MatchState] === "POST_MATCH");
+  $("#cardAssignment").attr("data-post-match", matchStates[data.MatchState] === "POST_MATCH");
 };
 
 // Handles a websocket message to update the realtime scoring fields.
@@ -79,16 +107,29 @@ const handleRealtimeScore = function(data) {
   for (const [teamId, card] of Object.entries(Object.assign(data.RedCards, data.BlueCards))) {
     $(`[data-team="${teamId}"]`).attr("data-card", card);
   }
+  for (const [teamId, ruleId] of Object.entries(Object.assign(data.RedCardRules, data.BlueCardRules))) {
+    $(`.card-assignment[data-team="${teamId}"] .rule-select`).val(ruleId);
+  }
 
   const newRedFoulsHashCode = hashObject(data.Red.Score.Fouls);
   const newBlueFoulsHashCode = hashObject(data.Blue.Score.Fouls);
   if (newRedFoulsHashCode !== redFoulsHashCode || newBlueFoulsHashCode !== blueFoulsHashCode) {
     redFoulsHashCode = newRedFoulsHashCode;
     blueFoulsHashCode = newBlueFoulsHashCode;
-    fetch("/panels/referee/foul_list")
+    fetch(fieldPathPrefix + "/panels/referee/foul_list" + window.location.search)
       .then(response => response.text())
       .then(svg => $("#foulList").html(svg));
   }
+
+  // Show the edits from the different referee panels that conflicted with each other.
+  const foulConflicts = [];
+  for (const conflict of data.RedFoulConflicts || []) {
+    foulConflicts.push($("<div>").addClass("red-foul").text("Red: " + conflict.Message));
+  }
+  for (const conflict of data.BlueFoulConflicts || []) {
+    foulConflicts.push($("<div>").addClass("blue-foul").text("Blue: " + conflict.Message));
+  }
+  $("#foulConflicts").empty().append(foulConflicts);
 }
 
 // Handles a websocket message to update the scoring commit status.
@@ -96,6 +137,7 @@ const handleScoringStatus = function(data) {
   if (data.RefereeScoreReady) {
     $("#commitButton").attr("data-enabled", false);
   }
+  $("#confirmCardsButton").attr("data-confirmed", data.CardsConfirmed);
   $("#redScoreStatus").text("Red Scoring " + data.NumRedScoringPanelsReady + "/" + data.NumRedScoringPanels);
   $("#redScoreStatus").attr("data-ready", data.RedScoreReady);
   $("#blueScoreStatus").text("Blue Scoring " + data.NumBlueScoringPanelsReady + "/" + data.NumBlueScoringPanels);
@@ -103,7 +145,7 @@ const handleScoringStatus = function(data) {
 }
 
 // Populates the red/yellow card button for a given team.
-const setTeamCard = function(alliance, position, team) {
+const setTeamCard = function(alliance, position, team, yellowCard) {
   const cardButton = $(`#${alliance}Team${position}Card`);
   if (team === null) {
     cardButton.text(0);
@@ -112,7 +154,7 @@ const setTeamCard = function(alliance, position, team) {
   } else {
     cardButton.text(team.Id);
     cardButton.attr("data-team", team.Id)
-    cardButton.attr("data-old-yellow-card", team.YellowCard);
+    cardButton.attr("data-old-yellow-card", yellowCard);
   }
   cardButton.attr("data-card", "");
 }
@@ -130,10 +172,13 @@ const hashObject = function(object) {
 $(function() {
   // Read the configuration for this display from the URL query string.
   var urlParams = new URLSearchParams(window.location.search);
-  $(".headRef-dependent").attr("data-hr", urlParams.get("hr"));
+  // Near and far side referees never have the head referee's controls.
+  const position = urlParams.get("position");
+  const isHeadReferee = urlParams.get("hr") !== "false" && position !== "near" && position !== "far";
+  $(".headRef-dependent").attr("data-hr", isHeadReferee);
 
   // Set up the websocket back to the server.
-  websocket = new CheesyWebsocket("/panels/referee/websocket", {
+  websocket = new CheesyWebsocket("/panels/referee/websocket" + window.location.search, {
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
@@ -5,6 +5,7 @@
 
 var websocket;
 let alliance;
+const timerStartTimes = {};
 
 // Handles a websocket message to update the teams for the current match.
 const handleMatchLoad = function(data) {
@@ -36,6 +37,32 @@ const handleMatchTime = function(data) {
       $("#postMatchMessage").hide();
       $("#commitMatchScore").hide();
   }
+
+  // Disable the elements whose period hasn't started yet while the match is running.
+  const matchIsRunning = ["START_MATCH", "WARMUP_PERIOD", "AUTO_PERIOD", "PAUSE_PERIOD", "TELEOP_PERIOD",
+    "FIELD_FAULT"].includes(matchStates[data.MatchState]);
+  $(".panel-element").each(function() {
+    const periodStartSec = getPeriodStartSec($(this).attr("data-period"));
+    $(this).attr("data-disabled", matchIsRunning && data.MatchTimeSec < periodStartSec);
+  });
+};
+
+// Returns the number of seconds into the match at which the given scoring period starts.
+const getPeriodStartSec = function(period) {
+  if (matchTiming === undefined) {
+    return 0;
+  }
+  const teleopStartSec = matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec + matchTiming.PauseDurationSec;
+  switch (period) {
+    case "auto":
+      return matchTiming.WarmupDurationSec;
+    case "teleop":
+      return teleopStartSec;
+    case "endgame":
+      return teleopStartSec + matchTiming.TeleopDurationSec - matchTiming.WarningRemainingDurationSec;
+    default:
+      return 0;
+  }
 };
 
 // Handles a websocket message to update the realtime scoring fields.
@@ -46,24 +73,62 @@ const handleRealtimeScore = function(data) {
   } else {
     realtimeScore = data.Blue;
   }
-  const score = realtimeScore.Score;
-
-  for (let i = 0; i < 3; i++) {
-    const i1 = i + 1;
-    $(`#leaveStatus${i1}>.value`).text(score.LeaveStatuses[i] ? "Yes" : "No");
-    $(`#leaveStatus${i1}`).attr("data-value", score.LeaveStatuses[i]);
-    $(`#parkTeam${i1}`).attr("data-value", score.EndgameStatuses[i] === 1);
-    $(`#stageSide0Team${i1}`).attr("data-value", score.EndgameStatuses[i] === 2);
-    $(`#stageSide1Team${i1}`).attr("data-value", score.EndgameStatuses[i] === 3);
-    $(`#stageSide2Team${i1}`).attr("data-value", score.EndgameStatuses[i] === 4);
-    $(`#stageSide${i}Microphone`).attr("data-value", score.MicrophoneStatuses[i]);
-    $(`#stageSide${i}Trap`).attr("data-value", score.TrapStatuses[i]);
-  }
+
+  $(".panel-element").each(function() {
+    const key = $(this).attr("data-key");
+    const value = realtimeScore.PanelValues[key];
+    switch ($(this).attr("data-type")) {
+      case "toggle":
+        $(this).attr("data-value", value === parseInt($(this).attr("data-toggle-value")));
+        break;
+      case "timer":
+        $(this).find(".value").text(`${value}s`);
+        break;
+      default:
+        $(this).find(".value").text(value);
+    }
+
+    // Flag the elements whose current value was provided by the vision scoring system so they can be verified.
+    $(this).attr("data-machine-scored", realtimeScore.PanelMachineScored[key]);
+  });
+
+  $("#undoButton").prop("disabled", !realtimeScore.CanUndo);
+  $("#redoButton").prop("disabled", !realtimeScore.CanRedo);
 };
 
 // Handles an element click and sends the appropriate websocket message.
-const handleClick = function(command, teamPosition = 0, stageIndex = 0) {
-  websocket.send(command, {TeamPosition: teamPosition, StageIndex: stageIndex});
+const handleClick = function(command, teamPosition = 0, stageIndex = 0, delta = 0) {
+  websocket.send(command, {TeamPosition: teamPosition, StageIndex: stageIndex, Delta: delta});
+};
+
+// Handles a click on a scoring panel element, or on one of its buttons, and sends the element's command.
+const handleElementClick = function(target, delta = 0) {
+  const element = $(target).closest(".panel-element");
+  handleClick(
+    element.attr("data-command"),
+    parseInt(element.attr("data-team-position")),
+    parseInt(element.attr("data-stage-index")),
+    delta,
+  );
+};
+
+// Starts or stops the stopwatch of a timer element, adding the elapsed time to the element's value when stopped.
+const handleTimerClick = function(target) {
+  const element = $(target).closest(".panel-element");
+  const key = element.attr("data-key");
+  if (timerStartTimes[key] === undefined) {
+    timerStartTimes[key] = Date.now();
+    $(target).text("Stop");
+    element.attr("data-running", true);
+  } else {
+    const elapsedSec = Math.round((Date.now() - timerStartTimes[key]) / 1000);
+    delete timerStartTimes[key];
+    $(target).text("Start");
+    element.attr("data-running", false);
+    if (elapsedSec > 0) {
+      handleElementClick(target, elapsedSec);
+    }
+  }
 };
 
 // Sends a websocket message to indicate that the score for this alliance is ready.
@@ -81,6 +146,21 @@ $(function() {
   websocket = new CheesyWebsocket("/panels/scoring/" + alliance + "/websocket", {
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
+    matchTiming: function(event) { handleMatchTiming(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
   });
+
+  // Allow the usual keyboard shortcuts for undoing and redoing scoring actions.
+  $(document).keydown(function(event) {
+    if (event.ctrlKey || event.metaKey) {
+      const key = event.key.toLowerCase();
+      if (key === "z" && !event.shiftKey) {
+        handleClick("undo");
+        event.preventDefault();
+      } else if (key === "y" || (key === "z" && event.shiftKey)) {
+        handleClick("redo");
+        event.preventDefault();
+      }
+    }
+  });
 });
@@ -65,9 +65,19 @@ var handleDisplayConfiguration = function(data) {
   });
 };
 
+// Handles a websocket message to warn about critical displays that have lost their connection.
+var handleDisplayAlerts = function(data) {
+  var displayAlerts = $("#displayAlerts").empty();
+  $.each(data.DisplayAlerts || [], function(i, displayAlert) {
+    displayAlerts.append($("<div>").text(formatDisplayAlert(displayAlert)));
+  });
+  displayAlerts.toggle(displayAlerts.children().length > 0);
+};
+
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/setup/displays/websocket", {
-    displayConfiguration: function(event) { handleDisplayConfiguration(event.data); }
+    displayAlerts: function(event) { handleDisplayAlerts(event.data); },
+    displayConfiguration: function(event) { handleDisplayConfiguration(event.data); },
   });
 });
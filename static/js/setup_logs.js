@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the server log viewer.
+
+var websocket;
+
+// Maximum number of entries kept on the page, beyond which the oldest are removed as new ones arrive.
+const maxLogEntries = 2000;
+
+// Bootstrap classes used to highlight each log level.
+const logLevelClasses = {
+  DEBUG: "bg-secondary",
+  INFO: "bg-info",
+  WARN: "bg-warning",
+  ERROR: "bg-danger",
+};
+
+// Formats the given attribute value for display, quoting it if it would otherwise be ambiguous.
+const formatAttrValue = function(value) {
+  if (value === "" || /[\s"=]/.test(value)) {
+    return JSON.stringify(value);
+  }
+  return value;
+};
+
+// Adds a row to the log table for the given entry. Text is inserted without interpretation as HTML since log messages
+// can contain arbitrary content.
+const appendLogEntry = function(entry) {
+  const time = new Date(entry.Time);
+  const row = $("<tr>");
+  row.append($("<td class='nowrap'>").text(time.toLocaleString()));
+  row.append($("<td>").append($("<span class='badge'>").addClass(logLevelClasses[entry.Level]).text(entry.Level)));
+  row.append($("<td>").text(entry.Subsystem));
+  const message = $("<td>").append($("<span>").text(entry.Message));
+  $.each(entry.Attrs || [], function(i, attr) {
+    message.append(" ", $("<code>").text(attr.Key + "=" + formatAttrValue(attr.Value)));
+  });
+  row.append(message);
+  $("#logEntries").append(row);
+};
+
+// Trims the table down to the most recent entries and keeps the newest one in view if following the log.
+const updateLogView = function() {
+  const rows = $("#logEntries tr");
+  if (rows.length > maxLogEntries) {
+    rows.slice(0, rows.length - maxLogEntries).remove();
+  }
+  if ($("#followLog").prop("checked")) {
+    window.scrollTo(0, document.body.scrollHeight);
+  }
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/setup/logs/websocket", {
+    logEntries: function(event) {
+      $("#logEntries").empty();
+      $.each(event.data || [], function(i, entry) {
+        appendLogEntry(entry);
+      });
+      updateLogView();
+    },
+    logEntry: function(event) {
+      appendLogEntry(event.data);
+      updateLogView();
+    },
+  });
+});
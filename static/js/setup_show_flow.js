@@ -0,0 +1,35 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the show flow management interface.
+
+var websocket;
+
+// Sends a websocket message to save the given show flow step.
+const saveShowFlowStep = function(button) {
+  websocket.send("saveShowFlowStep", constructShowFlowStep(button));
+};
+
+// Sends a websocket message to delete the given show flow step.
+const deleteShowFlowStep = function(button) {
+  websocket.send("deleteShowFlowStep", constructShowFlowStep(button));
+};
+
+// Sends a websocket message to move the given show flow step up or down in the sequence.
+const reorderShowFlowStep = function(button, moveUp) {
+  websocket.send("reorderShowFlowStep", {Id: parseInt(button.form.id.value), MoveUp: moveUp});
+};
+
+// Gathers the show flow step info and constructs a JSON object.
+const constructShowFlowStep = function(button) {
+  return {
+    Id: parseInt(button.form.id.value),
+    Screen: button.form.screen.value,
+    AutoAdvanceSec: parseInt(button.form.autoAdvanceSec.value) || 0,
+  };
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/setup/show_flow/websocket", {});
+});
@@ -0,0 +1,220 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the spectator site.
+
+const statusRefreshIntervalMs = 5000;
+const followedTeamsStorageKey = "followedTeamIds";
+const notificationTopicsStorageKey = "notificationTopics";
+const allNotificationTopics = ["upcoming_match", "schedule_changed", "delay"];
+
+// Returns the IDs of the teams that are followed on this device.
+const getFollowedTeamIds = function() {
+  return JSON.parse(localStorage.getItem(followedTeamsStorageKey) || "[]");
+};
+
+// Saves the followed teams on this device and tells the server which ones to send notifications about.
+const setFollowedTeamIds = function(teamIds) {
+  localStorage.setItem(followedTeamsStorageKey, JSON.stringify(teamIds));
+  updatePushSubscription(teamIds);
+  loadFollowedTeams();
+  loadStatus();
+};
+
+// Returns the topics that this device should be notified about, which defaults to all of them.
+const getNotificationTopics = function() {
+  return JSON.parse(localStorage.getItem(notificationTopicsStorageKey) || JSON.stringify(allNotificationTopics));
+};
+
+// Saves the topics that are toggled on and tells the server about the change.
+const updateNotificationTopics = function() {
+  const topics = $(".notification-topic:checked").map(function() {
+    return this.value;
+  }).get();
+  localStorage.setItem(notificationTopicsStorageKey, JSON.stringify(topics));
+  updatePushSubscription(getFollowedTeamIds());
+};
+
+// Returns an element listing the given teams, with any that are followed highlighted.
+const formatTeams = function(teamIds) {
+  const followedTeamIds = getFollowedTeamIds();
+  const teams = $("<span>");
+  $.each(teamIds, function(i, teamId) {
+    if (teamId > 0) {
+      const team = $("<span class='spectator-team'>").text(teamId);
+      team.toggleClass("followed", followedTeamIds.includes(teamId));
+      teams.append(team);
+    }
+  });
+  return teams;
+};
+
+// Returns a description of how soon the given match will be played.
+const formatMatchesAway = function(match) {
+  if (match.RedScore !== null) {
+    return match.RedScore + " - " + match.BlueScore;
+  }
+  if (match.MatchesAway === 0) {
+    return "Now";
+  }
+  if (match.MatchesAway > 0) {
+    return match.MatchesAway + (match.MatchesAway === 1 ? " match away" : " matches away");
+  }
+  // Show the time in the event's time zone, in case the spectator's device is still set to that of home.
+  return new Date(match.Time).toLocaleTimeString(
+    [], {hour: "numeric", minute: "2-digit", timeZone: eventTimeZone || undefined}
+  );
+};
+
+// Loads the state of the field and the matches coming up.
+const loadStatus = function() {
+  fetch("/api/spectator/status")
+    .then(response => response.json())
+    .then(function(status) {
+      let matchStatus = status.Status;
+      if (status.Status === "In Progress") {
+        matchStatus += " (" + status.MatchTimeSec + "s)";
+      }
+      $("#matchName").text(status.Match.LongName);
+      $("#matchStatus").text(matchStatus);
+      $("#redTeams").html(formatTeams(status.Match.RedTeams));
+      $("#blueTeams").html(formatTeams(status.Match.BlueTeams));
+      $("#redScore").text(status.Match.RedScore === null ? "" : status.Match.RedScore);
+      $("#blueScore").text(status.Match.BlueScore === null ? "" : status.Match.BlueScore);
+
+      const upcomingMatches = $("#upcomingMatches").empty();
+      $.each(status.UpcomingMatches, function(i, match) {
+        upcomingMatches.append(createMatchListItem(match));
+      });
+    });
+};
+
+// Returns a list item summarizing the given match.
+const createMatchListItem = function(match) {
+  const item = $("<li class='list-group-item d-flex justify-content-between align-items-center'>");
+  item.append($("<span class='spectator-match-name'>").text(match.ShortName));
+  item.append($("<span class='spectator-red'>").html(formatTeams(match.RedTeams)));
+  item.append($("<span class='spectator-blue'>").html(formatTeams(match.BlueTeams)));
+  item.append($("<span class='text-body-secondary small'>").text(formatMatchesAway(match)));
+  return item;
+};
+
+// Loads the current qualification rankings.
+const loadRankings = function() {
+  fetch("/api/rankings")
+    .then(response => response.json())
+    .then(function(data) {
+      const followedTeamIds = getFollowedTeamIds();
+      const rankings = $("#rankings").empty();
+      $.each(data.Rankings, function(i, ranking) {
+        const row = $("<tr>").toggleClass("followed", followedTeamIds.includes(ranking.TeamId));
+        row.append($("<td>").text(ranking.Rank));
+        row.append($("<td>").text(ranking.TeamId));
+        row.append($("<td>").text(ranking.RankingPoints));
+        row.append($("<td>").text(ranking.Wins + "-" + ranking.Losses + "-" + ranking.Ties));
+        rankings.append(row);
+      });
+    });
+};
+
+// Loads the schedule of each followed team.
+const loadFollowedTeams = function() {
+  const followedTeams = $("#followedTeams").empty();
+  $.each(getFollowedTeamIds(), function(i, teamId) {
+    const card = $("<div class='card mb-3'>").appendTo(followedTeams);
+    const header = $("<div class='card-header d-flex justify-content-between align-items-center'>").appendTo(card);
+    const title = $("<span>").text(teamId).appendTo(header);
+    $("<button class='btn btn-sm btn-outline-secondary'>Unfollow</button>").appendTo(header).click(function() {
+      setFollowedTeamIds(getFollowedTeamIds().filter(id => id !== teamId));
+    });
+    const matches = $("<ul class='list-group list-group-flush'>").appendTo(card);
+
+    fetch("/api/spectator/teams/" + teamId + "/schedule")
+      .then(response => response.ok ? response.json() : Promise.reject(response.statusText))
+      .then(function(schedule) {
+        let titleText = schedule.TeamId + " " + schedule.Nickname;
+        if (schedule.Ranking) {
+          titleText += " (Rank " + schedule.Ranking.Rank + ")";
+        }
+        title.text(titleText);
+        $.each(schedule.Matches, function(j, match) {
+          matches.append(createMatchListItem(match));
+        });
+      })
+      .catch(function() {
+        title.text(teamId + " (not found)");
+      });
+  });
+};
+
+// Adds the team entered by the user to the followed teams.
+const followTeam = function() {
+  const teamId = parseInt($("#followTeamId").val());
+  $("#followTeamId").val("");
+  if (teamId > 0 && !getFollowedTeamIds().includes(teamId)) {
+    setFollowedTeamIds(getFollowedTeamIds().concat(teamId));
+  }
+};
+
+// Subscribes this device to push notifications if necessary and tells the server which teams and topics to notify it
+// about.
+const updatePushSubscription = function(teamIds) {
+  if (!("serviceWorker" in navigator) || !("PushManager" in window)) {
+    showPushStatus("This browser doesn't support notifications; check back here for updates instead.");
+    return;
+  }
+  if (teamIds.length === 0 && Notification.permission !== "granted") {
+    return;
+  }
+
+  Promise.all([
+    navigator.serviceWorker.register("/static/js/spectator_service_worker.js"),
+    fetch("/api/spectator/push/key").then(response => response.json()),
+  ]).then(function([registration, key]) {
+    return registration.pushManager.getSubscription().then(function(subscription) {
+      if (subscription || teamIds.length === 0) {
+        return subscription;
+      }
+      return registration.pushManager.subscribe({
+        userVisibleOnly: true,
+        applicationServerKey: decodeBase64Url(key.PublicKey),
+      });
+    });
+  }).then(function(subscription) {
+    if (!subscription) {
+      return;
+    }
+    const request = subscription.toJSON();
+    request.teamIds = teamIds;
+    request.topics = getNotificationTopics();
+    return fetch("/api/spectator/push/subscriptions", {
+      method: "POST",
+      headers: {"Content-Type": "application/json"},
+      body: JSON.stringify(request),
+    });
+  }).then(function() {
+    $("#pushStatus").hide();
+  }).catch(function(error) {
+    showPushStatus("Notifications are unavailable: " + error);
+  });
+};
+
+const showPushStatus = function(message) {
+  $("#pushStatus").text(message).show();
+};
+
+// Converts the given URL-safe base64 string to the byte array that the push manager expects.
+const decodeBase64Url = function(value) {
+  const base64 = (value + "===".slice((value.length + 3) % 4)).replace(/-/g, "+").replace(/_/g, "/");
+  return Uint8Array.from(atob(base64), character => character.charCodeAt(0));
+};
+
+$(function() {
+  const topics = getNotificationTopics();
+  $(".notification-topic").each(function() {
+    this.checked = topics.includes(this.value);
+  });
+
+  loadStatus();
+  setInterval(loadStatus, statusRefreshIntervalMs);
+});
@@ -0,0 +1,19 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Service worker for the spectator site, which shows the push notifications sent when a followed team is up soon.
+
+self.addEventListener("push", function(event) {
+  const payload = event.data ? event.data.json() : {};
+  event.waitUntil(self.registration.showNotification(payload.title || "Cheesy Arena", {
+    body: payload.body,
+    icon: "/static/img/favicon.ico",
+    data: {url: payload.url || "/spectator"},
+  }));
+});
+
+// Brings up the spectator site when the notification is tapped.
+self.addEventListener("notificationclick", function(event) {
+  event.notification.close();
+  event.waitUntil(self.clients.openWindow(event.notification.data.url));
+});
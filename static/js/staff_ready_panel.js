@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the field staff readiness panel.
+
+var websocket;
+let role;
+let isReady = false;
+
+// Sends a websocket message to flip this role's readiness for the next match.
+const toggleReady = function() {
+  websocket.send("setReady", !isReady);
+};
+
+// Handles a websocket message to update the teams for the current match.
+const handleMatchLoad = function(data) {
+  $("#matchName").text(data.Match.LongName);
+};
+
+// Handles a websocket message to update the readiness of each member of the field staff.
+const handleArenaStatus = function(data) {
+  const readiness = data.StaffReadiness;
+  $("#headRefereeStatus").attr("data-ready", readiness.HeadRefereeReady);
+  $("#scorekeeperStatus").attr("data-ready", readiness.ScorekeeperReady);
+  $("#ftaStatus").attr("data-ready", readiness.FtaReady);
+
+  switch (role) {
+    case "headReferee":
+      isReady = readiness.HeadRefereeReady;
+      break;
+    case "scorekeeper":
+      isReady = readiness.ScorekeeperReady;
+      break;
+    case "fta":
+      isReady = readiness.FtaReady;
+      break;
+  }
+  const preMatch = matchStates[data.MatchState] === "PRE_MATCH";
+  $("#readyButton").attr("data-ready", isReady).attr("data-enabled", preMatch);
+  $("#readyButton").text(!preMatch ? "Match in Progress" : isReady ? "Ready" : "Tap When Ready");
+};
+
+$(function() {
+  role = $("#staffReadyPanel").attr("data-role");
+
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/panels/staff_ready/websocket?role=" + role, {
+    arenaStatus: function(event) { handleArenaStatus(event.data); },
+    matchLoad: function(event) { handleMatchLoad(event.data); },
+  });
+});
@@ -0,0 +1,30 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Client-side logic for the web-based team number sign.
+
+var websocket;
+var station;
+
+// Handles a websocket message to update the content of the sign.
+var handleTeamSigns = function(data) {
+  if (data === null || !(station in data)) {
+    return;
+  }
+  const sign = data[station];
+  $("#frontText").text(sign.FrontText).css("color", sign.FrontColor);
+  $("#countdown").text(sign.Countdown);
+  $("#rearText").text(sign.RearText);
+  $("body").attr("data-stopped", sign.EStop || sign.AStop);
+};
+
+$(function() {
+  // Read the configuration for this display from the URL query string.
+  const urlParams = new URLSearchParams(window.location.search);
+  station = urlParams.get("station");
+
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/displays/team_sign/websocket", {
+    teamSigns: function(event) { handleTeamSigns(event.data); },
+  });
+});
@@ -67,6 +67,64 @@ func CreateOrUpdateAward(database *model.Database, award *model.Award, createInt
 	return nil
 }
 
+// Represents a single step of the awards ceremony: showing either the introduction or the recipient of an award.
+type AwardCeremonyStep struct {
+	Award      model.Award
+	LowerThird model.LowerThird
+	IsWinner   bool
+}
+
+// Returns the steps of the awards ceremony in the order that their lower thirds are configured to be shown.
+func GetAwardCeremonySteps(database *model.Database) ([]AwardCeremonyStep, error) {
+	awards, err := database.GetAllAwards()
+	if err != nil {
+		return nil, err
+	}
+	awardsMap := make(map[int]model.Award, len(awards))
+	for _, award := range awards {
+		awardsMap[award.Id] = award
+	}
+	lowerThirds, err := database.GetAllLowerThirds()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []AwardCeremonyStep
+	for _, lowerThird := range lowerThirds {
+		award, ok := awardsMap[lowerThird.AwardId]
+		if !ok {
+			continue
+		}
+		steps = append(
+			steps, AwardCeremonyStep{Award: award, LowerThird: lowerThird, IsWinner: lowerThird.BottomText != ""},
+		)
+	}
+	return steps, nil
+}
+
+// Records the recipient of the given existing award during the ceremony and updates its lower thirds accordingly.
+func RecordAwardWinner(database *model.Database, awardId, teamId int, personName string) (*model.Award, error) {
+	award, err := database.GetAwardById(awardId)
+	if err != nil {
+		return nil, err
+	}
+	if award == nil {
+		return nil, fmt.Errorf("Award %d does not exist.", awardId)
+	}
+	lowerThirds, err := database.GetLowerThirdsByAwardId(awardId)
+	if err != nil {
+		return nil, err
+	}
+
+	award.TeamId = teamId
+	award.PersonName = personName
+	// Awards sharing an introduction with a previous one (e.g. the second winner team) only have one lower third.
+	if err = CreateOrUpdateAward(database, award, len(lowerThirds) != 1); err != nil {
+		return nil, err
+	}
+	return award, nil
+}
+
 // Deletes the given award and any associated lower thirds.
 func DeleteAward(database *model.Database, awardId int) error {
 	if err := database.DeleteAward(awardId); err != nil {
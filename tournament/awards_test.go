@@ -150,3 +150,53 @@ func TestCreateOrUpdateWinnerAndFinalistAwards(t *testing.T) {
 		assert.Equal(t, "Team 101, ", lowerThirds[6].BottomText)
 	}
 }
+
+func TestAwardCeremony(t *testing.T) {
+	database := setupTestDb(t)
+	database.CreateTeam(&model.Team{Id: 254, Nickname: "Teh Chezy Pofs"})
+	database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"})
+	database.CreateLowerThird(&model.LowerThird{TopText: "Unrelated", DisplayOrder: 0})
+
+	award1 := model.Award{Type: model.JudgedAward, AwardName: "Safety Award"}
+	assert.Nil(t, CreateOrUpdateAward(database, &award1, true))
+	award2 := model.Award{Type: model.WinnerAward, AwardName: "Winner", TeamId: 254}
+	assert.Nil(t, CreateOrUpdateAward(database, &award2, true))
+	award3 := model.Award{Type: model.WinnerAward, AwardName: "Winner", TeamId: 1114}
+	assert.Nil(t, CreateOrUpdateAward(database, &award3, false))
+
+	steps, err := GetAwardCeremonySteps(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 5, len(steps)) {
+		assert.Equal(t, award1.Id, steps[0].Award.Id)
+		assert.False(t, steps[0].IsWinner)
+		assert.Equal(t, award1.Id, steps[1].Award.Id)
+		assert.True(t, steps[1].IsWinner)
+		assert.Equal(t, "(No awardee assigned yet)", steps[1].LowerThird.BottomText)
+		assert.False(t, steps[2].IsWinner)
+		assert.Equal(t, "Team 254, Teh Chezy Pofs", steps[3].LowerThird.BottomText)
+		assert.Equal(t, award3.Id, steps[4].Award.Id)
+		assert.True(t, steps[4].IsWinner)
+	}
+
+	// Record the recipients and check that the existing lower thirds are updated in place.
+	award, err := RecordAwardWinner(database, award1.Id, 1114, "Karthik")
+	assert.Nil(t, err)
+	assert.Equal(t, 1114, award.TeamId)
+	award, err = RecordAwardWinner(database, award3.Id, 254, "")
+	assert.Nil(t, err)
+	steps, _ = GetAwardCeremonySteps(database)
+	if assert.Equal(t, 5, len(steps)) {
+		assert.Equal(t, "", steps[0].LowerThird.BottomText)
+		assert.Equal(t, "Karthik &ndash; Team 1114, Simbotics", steps[1].LowerThird.BottomText)
+		assert.Equal(t, "Team 254, Teh Chezy Pofs", steps[4].LowerThird.BottomText)
+	}
+
+	_, err = RecordAwardWinner(database, 12345, 254, "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Award 12345 does not exist.", err.Error())
+	}
+	_, err = RecordAwardWinner(database, award1.Id, 9999, "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 9999 is not present at this event.", err.Error())
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for generating the script from which the emcee introduces the teams playing in each match.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"strings"
+	"time"
+)
+
+// Represents what the emcee needs to know to introduce a single match.
+type EmceeScript struct {
+	MatchId      int
+	ShortName    string
+	LongName     string
+	Time         time.Time
+	RedAlliance  int // Playoff alliance number, or zero outside of the playoffs.
+	BlueAlliance int
+	RedTeams     []EmceeScriptTeam
+	BlueTeams    []EmceeScriptTeam
+	Lines        []string // The introduction to be read out, one line per alliance after the match name.
+}
+
+// Represents what the emcee needs to know to introduce a single team.
+type EmceeScriptTeam struct {
+	TeamId         int
+	Nickname       string
+	Location       string
+	Rank           int    // Zero if the team hasn't been ranked yet.
+	Record         string // Wins-losses-ties in qualification matches, if the team has been ranked.
+	IsSurrogate    bool
+	AnnouncerNotes string
+}
+
+// Returns the script for introducing the given match and the teams playing in it.
+func GenerateEmceeScript(database *model.Database, match *model.Match) (*EmceeScript, error) {
+	script := EmceeScript{
+		MatchId:      match.Id,
+		ShortName:    match.ShortName,
+		LongName:     match.LongName,
+		Time:         match.Time,
+		RedAlliance:  match.PlayoffRedAlliance,
+		BlueAlliance: match.PlayoffBlueAlliance,
+	}
+	var err error
+	if script.RedTeams, err = getEmceeScriptTeams(
+		database,
+		[]int{match.Red1, match.Red2, match.Red3},
+		[]bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate},
+	); err != nil {
+		return nil, err
+	}
+	if script.BlueTeams, err = getEmceeScriptTeams(
+		database,
+		[]int{match.Blue1, match.Blue2, match.Blue3},
+		[]bool{match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate},
+	); err != nil {
+		return nil, err
+	}
+
+	script.Lines = []string{
+		fmt.Sprintf("Coming up next: %s!", match.LongName),
+		introduceEmceeScriptAlliance("red", match.PlayoffRedAlliance, script.RedTeams),
+		introduceEmceeScriptAlliance("blue", match.PlayoffBlueAlliance, script.BlueTeams),
+	}
+	return &script, nil
+}
+
+// Returns the details of the given teams, skipping any empty stations.
+func getEmceeScriptTeams(database *model.Database, teamIds []int, isSurrogates []bool) ([]EmceeScriptTeam, error) {
+	teams := []EmceeScriptTeam{}
+	for i, teamId := range teamIds {
+		if teamId == 0 {
+			continue
+		}
+		scriptTeam := EmceeScriptTeam{TeamId: teamId, IsSurrogate: isSurrogates[i]}
+		team, err := database.GetTeamById(teamId)
+		if err != nil {
+			return nil, err
+		}
+		if team != nil {
+			scriptTeam.Nickname = team.Nickname
+			scriptTeam.AnnouncerNotes = team.AnnouncerNotes
+			var locationParts []string
+			for _, part := range []string{team.City, team.StateProv, team.Country} {
+				if part != "" {
+					locationParts = append(locationParts, part)
+				}
+			}
+			scriptTeam.Location = strings.Join(locationParts, ", ")
+		}
+		ranking, err := database.GetRankingForTeam(teamId)
+		if err != nil {
+			return nil, err
+		}
+		if ranking != nil {
+			scriptTeam.Rank = ranking.Rank
+			scriptTeam.Record = fmt.Sprintf("%d-%d-%d", ranking.Wins, ranking.Losses, ranking.Ties)
+		}
+		teams = append(teams, scriptTeam)
+	}
+	return teams, nil
+}
+
+// Returns the line introducing the given alliance and each of its teams.
+func introduceEmceeScriptAlliance(color string, playoffAlliance int, teams []EmceeScriptTeam) string {
+	var introduction strings.Builder
+	fmt.Fprintf(&introduction, "On the %s alliance", color)
+	if playoffAlliance > 0 {
+		fmt.Fprintf(&introduction, ", alliance %d", playoffAlliance)
+	}
+	if len(teams) == 0 {
+		introduction.WriteString(": no teams.")
+		return introduction.String()
+	}
+	introduction.WriteString(":")
+	for i, team := range teams {
+		if i > 0 {
+			introduction.WriteString(";")
+			if i == len(teams)-1 {
+				introduction.WriteString(" and")
+			}
+		}
+		fmt.Fprintf(&introduction, " team %d", team.TeamId)
+		if team.Nickname != "" {
+			fmt.Fprintf(&introduction, ", %s", team.Nickname)
+		}
+		if team.Location != "" {
+			fmt.Fprintf(&introduction, ", from %s", team.Location)
+		}
+		if team.Rank > 0 {
+			fmt.Fprintf(&introduction, ", ranked %s with a record of %s", ordinal(team.Rank), team.Record)
+		}
+		if team.IsSurrogate {
+			introduction.WriteString(", playing as a surrogate")
+		}
+	}
+	introduction.WriteString(".")
+	return introduction.String()
+}
+
+// Returns the given number as an English ordinal, e.g. "1st" or "12th".
+func ordinal(number int) string {
+	suffix := "th"
+	switch number % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if number%100 >= 11 && number%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", number, suffix)
+}
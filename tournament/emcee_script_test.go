@@ -0,0 +1,76 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateEmceeScript(t *testing.T) {
+	database := setupTestDb(t)
+	database.CreateTeam(
+		&model.Team{Id: 254, Nickname: "The Cheesy Poofs", City: "San Jose", StateProv: "CA", Country: "USA"},
+	)
+	database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics", City: "St. Catharines", Country: "Canada",
+		AnnouncerNotes: "Say hi to the mascot"})
+	database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 2, RankingFields: game.RankingFields{Wins: 3, Losses: 1}})
+	database.CreateRanking(&game.Ranking{TeamId: 1114, Rank: 11, RankingFields: game.RankingFields{Wins: 2, Ties: 1}})
+	match := model.Match{Id: 7, ShortName: "Q7", LongName: "Qualification 7", Red1: 254, Red3: 1678, Blue2: 1114,
+		Blue2IsSurrogate: true}
+
+	script, err := GenerateEmceeScript(database, &match)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, script.MatchId)
+	assert.Equal(t, "Q7", script.ShortName)
+	if assert.Equal(t, 2, len(script.RedTeams)) {
+		assert.Equal(
+			t,
+			EmceeScriptTeam{TeamId: 254, Nickname: "The Cheesy Poofs", Location: "San Jose, CA, USA", Rank: 2,
+				Record: "3-1-0"},
+			script.RedTeams[0],
+		)
+		assert.Equal(t, EmceeScriptTeam{TeamId: 1678}, script.RedTeams[1])
+	}
+	if assert.Equal(t, 1, len(script.BlueTeams)) {
+		assert.Equal(t, "Say hi to the mascot", script.BlueTeams[0].AnnouncerNotes)
+		assert.True(t, script.BlueTeams[0].IsSurrogate)
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Coming up next: Qualification 7!",
+			"On the red alliance: team 254, The Cheesy Poofs, from San Jose, CA, USA, ranked 2nd with a record of " +
+				"3-1-0; and team 1678.",
+			"On the blue alliance: team 1114, Simbotics, from St. Catharines, Canada, ranked 11th with a record of " +
+				"2-0-1, playing as a surrogate.",
+		},
+		script.Lines,
+	)
+
+	// Check that playoff alliances are named and that empty alliances are handled.
+	match = model.Match{LongName: "Final 1", PlayoffRedAlliance: 1, PlayoffBlueAlliance: 2, Red1: 254, Red2: 1114}
+	script, err = GenerateEmceeScript(database, &match)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, script.RedAlliance)
+	assert.Equal(t, 2, script.BlueAlliance)
+	assert.Contains(t, script.Lines[1], "On the red alliance, alliance 1: team 254, The Cheesy Poofs")
+	assert.Contains(t, script.Lines[1], "; and team 1114, Simbotics")
+	assert.Equal(t, "On the blue alliance, alliance 2: no teams.", script.Lines[2])
+	assert.Equal(t, []EmceeScriptTeam{}, script.BlueTeams)
+}
+
+func TestOrdinal(t *testing.T) {
+	assert.Equal(t, "1st", ordinal(1))
+	assert.Equal(t, "2nd", ordinal(2))
+	assert.Equal(t, "3rd", ordinal(3))
+	assert.Equal(t, "4th", ordinal(4))
+	assert.Equal(t, "11th", ordinal(11))
+	assert.Equal(t, "12th", ordinal(12))
+	assert.Equal(t, "13th", ordinal(13))
+	assert.Equal(t, "21st", ordinal(21))
+	assert.Equal(t, "112th", ordinal(112))
+}
@@ -0,0 +1,320 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for projecting the final qualification rankings and remaining match outcomes by Monte Carlo simulation.
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"maps"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Number of times the remaining qualification matches are simulated when projecting the final rankings.
+const NumProjectionSimulations = 1000
+
+// Represents the projected final standing of a single team.
+type RankingProjection struct {
+	TeamId                 int
+	CurrentRank            int
+	CurrentRankingPoints   int
+	ProjectedRankingPoints float64
+	MeanRank               float64
+	BestRank               int
+	WorstRank              int
+	RankProbabilities      []float64
+}
+
+// Represents the projected outcome of a single remaining qualification match.
+type MatchProjection struct {
+	MatchId            int
+	ShortName          string
+	RedWinProbability  float64
+	BlueWinProbability float64
+	TieProbability     float64
+}
+
+// Represents the full set of projections resulting from a round of simulations.
+type Projections struct {
+	NumSimulations      int
+	NumMatchesRemaining int
+	Rankings            []RankingProjection
+	Matches             []MatchProjection
+}
+
+// Per-team averages of the alliance performance in completed matches, used to estimate future match outcomes.
+type teamStrength struct {
+	scoreContribution float64
+	autoContribution  float64
+	stageContribution float64
+	bonusRankingRate  float64
+}
+
+type teamStrengthTotals struct {
+	score        float64
+	autoPoints   float64
+	stagePoints  float64
+	bonusPoints  float64
+	matchesCount int
+}
+
+// Returns the probability of the team finishing within the given number of top ranks.
+func (projection *RankingProjection) TopRankProbability(numRanks int) float64 {
+	probability := 0.0
+	for i := 0; i < numRanks && i < len(projection.RankProbabilities); i++ {
+		probability += projection.RankProbabilities[i]
+	}
+	return probability
+}
+
+// Simulates the remaining qualification matches the given number of times, using each team's average contribution
+// in the matches played so far, and returns the resulting ranking and match projections.
+func CalculateProjections(database *model.Database, numSimulations int) (*Projections, error) {
+	matches, err := database.GetMatchesByType(model.Qualification, false)
+	if err != nil {
+		return nil, err
+	}
+	rankings, err := database.GetAllRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tally up each team's alliance performance in the completed matches.
+	totals := make(map[int]*teamStrengthTotals)
+	var allianceScores []float64
+	var remainingMatches []model.Match
+	for _, match := range matches {
+		if match.IsReplaced() {
+			continue
+		}
+		if !match.IsComplete() {
+			remainingMatches = append(remainingMatches, match)
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if matchResult == nil {
+			continue
+		}
+		redSummary := matchResult.RedScoreSummary()
+		blueSummary := matchResult.BlueScoreSummary()
+		addStrengthTotals(totals, []int{match.Red1, match.Red2, match.Red3}, redSummary)
+		addStrengthTotals(totals, []int{match.Blue1, match.Blue2, match.Blue3}, blueSummary)
+		allianceScores = append(allianceScores, float64(redSummary.Score), float64(blueSummary.Score))
+	}
+
+	// Determine the spread of alliance scores to use as the simulation noise.
+	meanScore, scoreStdDev := 0.0, 0.0
+	if len(allianceScores) > 0 {
+		for _, score := range allianceScores {
+			meanScore += score
+		}
+		meanScore /= float64(len(allianceScores))
+		for _, score := range allianceScores {
+			scoreStdDev += (score - meanScore) * (score - meanScore)
+		}
+		scoreStdDev = math.Sqrt(scoreStdDev / float64(len(allianceScores)))
+	}
+	if scoreStdDev == 0 {
+		scoreStdDev = 1
+	}
+
+	// Build the strength estimate for each team, falling back to the event average for teams without any results.
+	strengths := make(map[int]teamStrength)
+	getStrength := func(teamId int, numTeamsOnAlliance int) teamStrength {
+		if strength, ok := strengths[teamId]; ok {
+			return strength
+		}
+		strength := teamStrength{scoreContribution: meanScore / float64(numTeamsOnAlliance)}
+		if teamTotals, ok := totals[teamId]; ok && teamTotals.matchesCount > 0 {
+			count := float64(teamTotals.matchesCount)
+			strength = teamStrength{
+				scoreContribution: teamTotals.score / count,
+				autoContribution:  teamTotals.autoPoints / count,
+				stageContribution: teamTotals.stagePoints / count,
+				bonusRankingRate:  teamTotals.bonusPoints / count,
+			}
+		}
+		strengths[teamId] = strength
+		return strength
+	}
+
+	// Set up the starting point for each team from the current rankings.
+	baseFields := make(map[int]game.RankingFields)
+	currentRanks := make(map[int]game.Ranking)
+	for _, ranking := range rankings {
+		baseFields[ranking.TeamId] = ranking.RankingFields
+		currentRanks[ranking.TeamId] = ranking
+	}
+	for _, match := range remainingMatches {
+		for _, teamId := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+			if _, ok := baseFields[teamId]; !ok && teamId > 0 {
+				baseFields[teamId] = game.RankingFields{}
+			}
+		}
+	}
+	numTeams := len(baseFields)
+
+	rankCounts := make(map[int][]int)
+	rankingPointTotals := make(map[int]int)
+	for teamId := range baseFields {
+		rankCounts[teamId] = make([]int, numTeams)
+	}
+	matchOutcomeCounts := make([][3]int, len(remainingMatches))
+
+	for simulation := 0; simulation < numSimulations; simulation++ {
+		fields := make(map[int]*game.RankingFields, numTeams)
+		for teamId, base := range baseFields {
+			teamFields := base
+			teamFields.ColumnTotals = maps.Clone(base.ColumnTotals)
+			fields[teamId] = &teamFields
+		}
+
+		for i, match := range remainingMatches {
+			redTeams := []int{match.Red1, match.Red2, match.Red3}
+			blueTeams := []int{match.Blue1, match.Blue2, match.Blue3}
+			redSummary := simulateAllianceScore(redTeams, getStrength, scoreStdDev)
+			blueSummary := simulateAllianceScore(blueTeams, getStrength, scoreStdDev)
+			if redSummary.Score > blueSummary.Score {
+				matchOutcomeCounts[i][0]++
+			} else if redSummary.Score < blueSummary.Score {
+				matchOutcomeCounts[i][1]++
+			} else {
+				matchOutcomeCounts[i][2]++
+			}
+			redSurrogates := []bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate}
+			blueSurrogates := []bool{match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}
+			for j, teamId := range redTeams {
+				if teamId > 0 && !redSurrogates[j] {
+					fields[teamId].AddScoreSummary(redSummary, blueSummary, false)
+				}
+			}
+			for j, teamId := range blueTeams {
+				if teamId > 0 && !blueSurrogates[j] {
+					fields[teamId].AddScoreSummary(blueSummary, redSummary, false)
+				}
+			}
+		}
+
+		simulatedRankings := make(game.Rankings, 0, numTeams)
+		for teamId, teamFields := range fields {
+			simulatedRankings = append(simulatedRankings, game.Ranking{TeamId: teamId, RankingFields: *teamFields})
+		}
+		sort.Sort(simulatedRankings)
+		for rank, ranking := range simulatedRankings {
+			rankCounts[ranking.TeamId][rank]++
+			rankingPointTotals[ranking.TeamId] += ranking.RankingPoints
+		}
+	}
+
+	// Aggregate the simulation results.
+	projections := Projections{NumSimulations: numSimulations, NumMatchesRemaining: len(remainingMatches)}
+	for teamId, counts := range rankCounts {
+		projection := RankingProjection{
+			TeamId:               teamId,
+			CurrentRank:          currentRanks[teamId].Rank,
+			CurrentRankingPoints: currentRanks[teamId].RankingPoints,
+			RankProbabilities:    make([]float64, numTeams),
+		}
+		if numSimulations > 0 {
+			projection.ProjectedRankingPoints = float64(rankingPointTotals[teamId]) / float64(numSimulations)
+			for rank, count := range counts {
+				if count == 0 {
+					continue
+				}
+				if projection.BestRank == 0 {
+					projection.BestRank = rank + 1
+				}
+				projection.WorstRank = rank + 1
+				probability := float64(count) / float64(numSimulations)
+				projection.RankProbabilities[rank] = probability
+				projection.MeanRank += probability * float64(rank+1)
+			}
+		}
+		projections.Rankings = append(projections.Rankings, projection)
+	}
+	sort.Slice(projections.Rankings, func(i, j int) bool {
+		if projections.Rankings[i].MeanRank == projections.Rankings[j].MeanRank {
+			return projections.Rankings[i].TeamId < projections.Rankings[j].TeamId
+		}
+		return projections.Rankings[i].MeanRank < projections.Rankings[j].MeanRank
+	})
+	for i, match := range remainingMatches {
+		matchProjection := MatchProjection{MatchId: match.Id, ShortName: match.ShortName}
+		if numSimulations > 0 {
+			matchProjection.RedWinProbability = float64(matchOutcomeCounts[i][0]) / float64(numSimulations)
+			matchProjection.BlueWinProbability = float64(matchOutcomeCounts[i][1]) / float64(numSimulations)
+			matchProjection.TieProbability = float64(matchOutcomeCounts[i][2]) / float64(numSimulations)
+		}
+		projections.Matches = append(projections.Matches, matchProjection)
+	}
+
+	return &projections, nil
+}
+
+// Accumulates the given alliance result into the totals of each team on the alliance.
+func addStrengthTotals(totals map[int]*teamStrengthTotals, teamIds []int, summary *game.ScoreSummary) {
+	numTeams := 0
+	for _, teamId := range teamIds {
+		if teamId > 0 {
+			numTeams++
+		}
+	}
+	for _, teamId := range teamIds {
+		if teamId == 0 {
+			continue
+		}
+		teamTotals := totals[teamId]
+		if teamTotals == nil {
+			teamTotals = new(teamStrengthTotals)
+			totals[teamId] = teamTotals
+		}
+		teamTotals.score += float64(summary.Score) / float64(numTeams)
+		teamTotals.autoPoints += float64(summary.AutoPoints) / float64(numTeams)
+		teamTotals.stagePoints += float64(summary.StagePoints) / float64(numTeams)
+		teamTotals.bonusPoints += float64(summary.BonusRankingPoints)
+		teamTotals.matchesCount++
+	}
+}
+
+// Generates a random alliance result around the expected performance of the given teams.
+func simulateAllianceScore(
+	teamIds []int, getStrength func(teamId int, numTeamsOnAlliance int) teamStrength, scoreStdDev float64,
+) *game.ScoreSummary {
+	numTeams := 0
+	for _, teamId := range teamIds {
+		if teamId > 0 {
+			numTeams++
+		}
+	}
+	var expectedScore, expectedAuto, expectedStage, bonusRate float64
+	for _, teamId := range teamIds {
+		if teamId == 0 {
+			continue
+		}
+		strength := getStrength(teamId, numTeams)
+		expectedScore += strength.scoreContribution
+		expectedAuto += strength.autoContribution
+		expectedStage += strength.stageContribution
+		bonusRate += strength.bonusRankingRate / float64(numTeams)
+	}
+
+	score := int(math.Max(0, math.Round(expectedScore+rand.NormFloat64()*scoreStdDev)))
+	bonusRankingPoints := int(bonusRate)
+	if rand.Float64() < bonusRate-float64(bonusRankingPoints) {
+		bonusRankingPoints++
+	}
+	return &game.ScoreSummary{
+		Score:              score,
+		MatchPoints:        score,
+		AutoPoints:         int(math.Round(expectedAuto)),
+		StagePoints:        int(math.Round(expectedStage)),
+		BonusRankingPoints: bonusRankingPoints,
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestCalculateProjectionsNoRemainingMatches(t *testing.T) {
+	rand.Seed(1)
+	database := setupTestDb(t)
+
+	projections, err := CalculateProjections(database, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(projections.Rankings))
+	assert.Equal(t, 0, len(projections.Matches))
+
+	// With all matches played, the projection should be identical to the current rankings.
+	setupMatchResultsForRankings(database)
+	unplayedMatch, _ := database.GetMatchByTypeOrder(model.Qualification, 4)
+	database.DeleteMatch(unplayedMatch.Id)
+	rankings, _ := CalculateRankings(database, false)
+	projections, err = CalculateProjections(database, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, projections.NumMatchesRemaining)
+	if assert.Equal(t, len(rankings), len(projections.Rankings)) {
+		for i, ranking := range rankings {
+			projection := projections.Rankings[i]
+			assert.Equal(t, ranking.TeamId, projection.TeamId)
+			assert.Equal(t, ranking.Rank, projection.CurrentRank)
+			assert.Equal(t, float64(ranking.Rank), projection.MeanRank)
+			assert.Equal(t, ranking.Rank, projection.BestRank)
+			assert.Equal(t, ranking.Rank, projection.WorstRank)
+			assert.Equal(t, float64(ranking.RankingPoints), projection.ProjectedRankingPoints)
+			assert.Equal(t, 1.0, projection.RankProbabilities[ranking.Rank-1])
+		}
+	}
+}
+
+func TestCalculateProjections(t *testing.T) {
+	rand.Seed(1)
+	database := setupTestDb(t)
+
+	setupMatchResultsForRankings(database)
+	CalculateRankings(database, false)
+	match := model.Match{Type: model.Qualification, TypeOrder: 5, ShortName: "Q5", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6, Status: game.MatchScheduled}
+	database.CreateMatch(&match)
+
+	projections, err := CalculateProjections(database, 200)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, projections.NumSimulations)
+	assert.Equal(t, 2, projections.NumMatchesRemaining)
+
+	// Teams from the unplayed match 4 should be projected alongside those that have already been ranked.
+	if assert.Equal(t, 12, len(projections.Rankings)) {
+		for i, projection := range projections.Rankings {
+			totalProbability := 0.0
+			for _, probability := range projection.RankProbabilities {
+				totalProbability += probability
+			}
+			assert.InDelta(t, 1.0, totalProbability, 0.0001)
+			assert.InDelta(t, 1.0, projection.TopRankProbability(12), 0.0001)
+			assert.LessOrEqual(t, projection.BestRank, projection.WorstRank)
+			assert.GreaterOrEqual(t, projection.ProjectedRankingPoints, float64(projection.CurrentRankingPoints))
+			if i > 0 {
+				assert.LessOrEqual(t, projections.Rankings[i-1].MeanRank, projection.MeanRank)
+			}
+		}
+	}
+
+	if assert.Equal(t, 2, len(projections.Matches)) {
+		assert.Equal(t, "Q5", projections.Matches[1].ShortName)
+		for _, matchProjection := range projections.Matches {
+			assert.InDelta(
+				t,
+				1.0,
+				matchProjection.RedWinProbability+matchProjection.BlueWinProbability+matchProjection.TieProbability,
+				0.0001,
+			)
+		}
+	}
+}
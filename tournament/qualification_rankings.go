@@ -21,7 +21,8 @@ func CalculateRankings(database *model.Database, preservePreviousRank bool) (gam
 	}
 	rankings := make(map[int]*game.Ranking)
 	for _, match := range matches {
-		if !match.IsComplete() {
+		if !match.IsComplete() || match.IsReplaced() {
+			// Only the result of a replay counts towards the rankings when a match has been replayed.
 			continue
 		}
 		matchResult, err := database.GetMatchResultForMatch(match.Id)
@@ -77,16 +78,15 @@ func CalculateRankings(database *model.Database, preservePreviousRank bool) (gam
 	return sortedRankings, nil
 }
 
-// Checks all the match results for yellow and red cards, and updates the team model accordingly.
+// Checks all the match results for yellow and red cards, and updates the team statuses accordingly.
 func CalculateTeamCards(database *model.Database, matchType model.MatchType) error {
 	teams, err := database.GetAllTeams()
 	if err != nil {
 		return err
 	}
-	teamsMap := make(map[string]model.Team)
+	yellowCards := make(map[string]bool)
 	for _, team := range teams {
-		team.YellowCard = false
-		teamsMap[strconv.Itoa(team.Id)] = team
+		yellowCards[strconv.Itoa(team.Id)] = false
 	}
 
 	matches, err := database.GetMatchesByType(matchType, false)
@@ -94,7 +94,7 @@ func CalculateTeamCards(database *model.Database, matchType model.MatchType) err
 		return err
 	}
 	for _, match := range matches {
-		if !match.IsComplete() {
+		if !match.IsComplete() || match.IsReplaced() {
 			continue
 		}
 		matchResult, err := database.GetMatchResultForMatch(match.Id)
@@ -106,26 +106,28 @@ func CalculateTeamCards(database *model.Database, matchType model.MatchType) err
 		}
 
 		// Mark the team as having a yellow card if they got either a yellow or red in a previous match.
-		for teamId, card := range matchResult.RedCards {
-			if team, ok := teamsMap[teamId]; ok && (card == "red" || card == "yellow") {
-				team.YellowCard = true
-				teamsMap[teamId] = team
-			}
-		}
-		for teamId, card := range matchResult.BlueCards {
-			if team, ok := teamsMap[teamId]; ok && (card == "red" || card == "yellow") {
-				team.YellowCard = true
-				teamsMap[teamId] = team
+		for _, cards := range []map[string]string{matchResult.RedCards, matchResult.BlueCards} {
+			for teamId, card := range cards {
+				if _, ok := yellowCards[teamId]; ok && (card == "red" || card == "yellow") {
+					yellowCards[teamId] = true
+				}
 			}
 		}
 	}
 
-	// Save the teams to the database.
-	for _, team := range teamsMap {
-		err = database.UpdateTeam(&team)
+	// Save the team statuses that have changed to the database.
+	for _, team := range teams {
+		teamStatus, err := database.GetTeamStatus(team.Id)
 		if err != nil {
 			return err
 		}
+		if teamStatus.YellowCard == yellowCards[strconv.Itoa(team.Id)] {
+			continue
+		}
+		teamStatus.YellowCard = yellowCards[strconv.Itoa(team.Id)]
+		if err = database.SaveTeamStatus(teamStatus); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -141,17 +143,28 @@ func addMatchResultToRankings(
 		rankings[teamId] = ranking
 	}
 
-	// Determine whether the team was disqualified.
-	var cards map[string]string
+	// Determine whether the team was disqualified or didn't show up.
+	var cards, bypasses map[string]string
 	if isRed {
 		cards = matchResult.RedCards
+		bypasses = matchResult.RedBypasses
 	} else {
 		cards = matchResult.BlueCards
+		bypasses = matchResult.BlueBypasses
 	}
 	disqualified := false
 	if card, ok := cards[strconv.Itoa(teamId)]; ok && (card == "red" || card == "dq") {
 		disqualified = true
 	}
+	switch bypasses[strconv.Itoa(teamId)] {
+	case model.BypassNoShow:
+		if !disqualified {
+			ranking.AddNoShow()
+			return
+		}
+	case model.BypassRedCard:
+		disqualified = true
+	}
 
 	if isRed {
 		ranking.AddScoreSummary(matchResult.RedScoreSummary(), matchResult.BlueScoreSummary(), disqualified)
@@ -123,6 +123,39 @@ func TestAddMatchResultToRankingsHandleCards(t *testing.T) {
 	assert.Equal(t, 0, rankings[6].Disqualifications)
 }
 
+func TestAddMatchResultToRankingsHandleBypasses(t *testing.T) {
+	rankings := map[int]*game.Ranking{}
+	matchResult := model.BuildTestMatchResult(1, 1)
+	matchResult.RedCards = map[string]string{"3": "red"}
+	matchResult.RedBypasses = map[string]string{
+		"1": model.BypassNoShow, "2": model.BypassRedCard, "3": model.BypassNoShow,
+	}
+	matchResult.BlueBypasses = map[string]string{"4": model.BypassRobotFailure}
+	for teamId := 1; teamId <= 3; teamId++ {
+		addMatchResultToRankings(rankings, teamId, matchResult, true)
+	}
+	addMatchResultToRankings(rankings, 4, matchResult, false)
+	addMatchResultToRankings(rankings, 5, matchResult, false)
+
+	// A no-show counts as a match played but earns nothing, without counting as a disqualification.
+	assert.Equal(t, 1, rankings[1].Played)
+	assert.Equal(t, 1, rankings[1].NoShows)
+	assert.Equal(t, 0, rankings[1].Disqualifications)
+	assert.Equal(t, 0, rankings[1].RankingPoints)
+	assert.Equal(t, 0, rankings[1].Wins+rankings[1].Losses+rankings[1].Ties)
+
+	// Being bypassed for a red card is a disqualification, which also takes precedence over a no-show.
+	assert.Equal(t, 1, rankings[2].Disqualifications)
+	assert.Equal(t, 0, rankings[2].NoShows)
+	assert.Equal(t, 1, rankings[3].Disqualifications)
+	assert.Equal(t, 0, rankings[3].NoShows)
+
+	// A robot failure doesn't affect the team's share of the alliance's result.
+	assert.Equal(t, rankings[5].RankingFields.RankingPoints, rankings[4].RankingFields.RankingPoints)
+	assert.Equal(t, rankings[5].RankingFields.MatchPoints, rankings[4].RankingFields.MatchPoints)
+	assert.Equal(t, 0, rankings[4].NoShows+rankings[4].Disqualifications)
+}
+
 // Sets up a schedule and results that touches on all possible variables.
 func setupMatchResultsForRankings(database *model.Database) {
 	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
@@ -0,0 +1,60 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for re-deriving match outcomes from the raw stored score breakdowns, for recovering after a change to how
+// points are computed or a manual edit to the database.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+)
+
+// Re-evaluates the winner of every completed match of the given type from its latest stored match result, saving any
+// match whose outcome has changed. Matches that have been replaced by a replay are left alone since they no longer
+// count. Returns the list of matches whose outcome changed.
+func RecomputeMatchOutcomes(database *model.Database, matchType model.MatchType) ([]model.Match, error) {
+	matches, err := database.GetMatchesByType(matchType, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var changedMatches []model.Match
+	for _, match := range matches {
+		if !match.IsComplete() {
+			continue
+		}
+		if match.IsReplaced() {
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if matchResult == nil {
+			return nil, fmt.Errorf("found no match result for match %d", match.Id)
+		}
+
+		if match.Type == model.Playoff {
+			// Re-derive the playoff DQ flags from the cards in case they were edited directly.
+			matchResult.CorrectPlayoffScore()
+			if err = database.UpdateMatchResult(matchResult); err != nil {
+				return nil, err
+			}
+		}
+		status, winReason, tiebreakLevel := game.DetermineMatchOutcome(
+			matchResult.RedScoreSummary(), matchResult.BlueScoreSummary(), match.UseTiebreakCriteria,
+		)
+		if status == match.Status && winReason == match.WinReason && tiebreakLevel == match.TiebreakLevel {
+			continue
+		}
+		match.Status, match.WinReason, match.TiebreakLevel = status, winReason, tiebreakLevel
+		if err = database.UpdateMatch(&match); err != nil {
+			return nil, err
+		}
+		changedMatches = append(changedMatches, match)
+	}
+	return changedMatches, nil
+}
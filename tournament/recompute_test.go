@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecomputeMatchOutcomes(t *testing.T) {
+	database := setupTestDb(t)
+
+	changedMatches, err := RecomputeMatchOutcomes(database, model.Qualification)
+	assert.Nil(t, err)
+	assert.Empty(t, changedMatches)
+
+	// Simulate a stored outcome that no longer agrees with the score breakdown.
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, Status: game.RedWonMatch}
+	database.CreateMatch(&match1)
+	matchResult1 := model.NewMatchResult()
+	matchResult1.MatchId = match1.Id
+	matchResult1.BlueScore.LeaveStatuses[0] = true
+	database.CreateMatchResult(matchResult1)
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, Status: game.TieMatch}
+	database.CreateMatch(&match2)
+	matchResult2 := model.NewMatchResult()
+	matchResult2.MatchId = match2.Id
+	database.CreateMatchResult(matchResult2)
+	match3 := model.Match{Type: model.Qualification, TypeOrder: 3}
+	database.CreateMatch(&match3)
+
+	// A match that has been replaced by a replay should keep its outcome even if its result disagrees.
+	match4 := model.Match{
+		Type: model.Qualification, TypeOrder: 4, Status: game.RedWonMatch, ReplacedByMatchId: match3.Id,
+	}
+	database.CreateMatch(&match4)
+	matchResult4 := model.NewMatchResult()
+	matchResult4.MatchId = match4.Id
+	matchResult4.BlueScore.LeaveStatuses[0] = true
+	database.CreateMatchResult(matchResult4)
+
+	changedMatches, err = RecomputeMatchOutcomes(database, model.Qualification)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(changedMatches)) {
+		assert.Equal(t, match1.Id, changedMatches[0].Id)
+	}
+	match, _ := database.GetMatchById(match1.Id)
+	assert.Equal(t, game.BlueWonMatch, match.Status)
+	assert.Equal(t, game.WinReasonScore, match.WinReason)
+	match, _ = database.GetMatchById(match3.Id)
+	assert.Equal(t, game.MatchScheduled, match.Status)
+	match, _ = database.GetMatchById(match4.Id)
+	assert.Equal(t, game.RedWonMatch, match.Status)
+
+	// Running it again should find nothing left to change.
+	changedMatches, err = RecomputeMatchOutcomes(database, model.Qualification)
+	assert.Nil(t, err)
+	assert.Empty(t, changedMatches)
+
+	// A completed match without a stored result should be reported.
+	database.CreateMatch(&model.Match{Type: model.Playoff, Status: game.RedWonMatch})
+	_, err = RecomputeMatchOutcomes(database, model.Playoff)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "found no match result")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for flagging matches for replay and cloning them into the schedule.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"time"
+)
+
+// Flags the given match for replay and inserts a copy of it into the schedule immediately after the match with the
+// given type order, shifting all later matches and breaks back by one place and one match cycle. Returns the newly
+// created replay match.
+func CreateReplayMatch(
+	database *model.Database, matchId int, reason model.ReplayReason, afterTypeOrder int,
+) (*model.Match, error) {
+	match, err := database.GetMatchById(matchId)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("match %d does not exist", matchId)
+	}
+	if !match.ShouldAllowReplay() {
+		return nil, fmt.Errorf("cannot replay %s matches", match.Type.String())
+	}
+	if match.IsReplaced() {
+		return nil, fmt.Errorf("match %s has already been flagged for replay", match.ShortName)
+	}
+	if !match.IsComplete() {
+		return nil, fmt.Errorf("cannot replay match %s because it has not been played", match.ShortName)
+	}
+	if reason == model.NoReplay {
+		return nil, fmt.Errorf("a reason must be given to replay a match")
+	}
+	if afterTypeOrder < match.TypeOrder {
+		return nil, fmt.Errorf("replay of match %s cannot be scheduled before the original", match.ShortName)
+	}
+
+	var replayMatch model.Match
+	err = database.RunInTransaction(func(database *model.Database) error {
+		matches, err := database.GetMatchesByType(match.Type, true)
+		if err != nil {
+			return err
+		}
+
+		// Push every later match and break back by one match cycle to make room for the replay, which takes the time
+		// slot of the match it is inserted before, or the slot after the last match if it is inserted at the end.
+		spacing := getMatchSpacing(matches)
+		replayTime := matches[len(matches)-1].Time.Add(spacing)
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i].TypeOrder <= afterTypeOrder {
+				break
+			}
+			replayTime = matches[i].Time
+			matches[i].TypeOrder++
+			matches[i].Time = matches[i].Time.Add(spacing)
+			if err = database.UpdateMatch(&matches[i]); err != nil {
+				return err
+			}
+		}
+		scheduledBreaks, err := database.GetScheduledBreaksByMatchType(match.Type)
+		if err != nil {
+			return err
+		}
+		for _, scheduledBreak := range scheduledBreaks {
+			if scheduledBreak.TypeOrderBefore > afterTypeOrder {
+				scheduledBreak.TypeOrderBefore++
+				scheduledBreak.Time = scheduledBreak.Time.Add(spacing)
+				if err = database.UpdateScheduledBreak(&scheduledBreak); err != nil {
+					return err
+				}
+			}
+		}
+
+		replayMatch = *match
+		replayMatch.Id = 0
+		replayMatch.TypeOrder = afterTypeOrder + 1
+		replayMatch.Time = replayTime
+		replayMatch.LongName = match.LongName + " Replay"
+		replayMatch.ShortName = match.ShortName + "R"
+		replayMatch.Status = game.MatchScheduled
+		replayMatch.WinReason = game.WinReasonNone
+		replayMatch.TiebreakLevel = 0
+		replayMatch.StartedAt = time.Time{}
+		replayMatch.ScoreCommittedAt = time.Time{}
+		replayMatch.FieldReadyAt = time.Time{}
+		replayMatch.ReplayOfMatchId = match.Id
+		replayMatch.ReplayReason = model.NoReplay
+		if err = database.CreateMatch(&replayMatch); err != nil {
+			return err
+		}
+
+		// Re-fetch the original match since its type order may have been shifted above.
+		originalMatch, err := database.GetMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		originalMatch.ReplacedByMatchId = replayMatch.Id
+		originalMatch.ReplayReason = reason
+		return database.UpdateMatch(originalMatch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &replayMatch, nil
+}
+
+// Returns the time between the starts of consecutive matches in the given list, taken as the shortest gap between any
+// two of them so that breaks aren't counted, or zero if it can't be determined.
+func getMatchSpacing(matches []model.Match) time.Duration {
+	var spacing time.Duration
+	for i := 1; i < len(matches); i++ {
+		gap := matches[i].Time.Sub(matches[i-1].Time)
+		if gap > 0 && (spacing == 0 || gap < spacing) {
+			spacing = gap
+		}
+	}
+	return spacing
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCreateReplayMatch(t *testing.T) {
+	database := setupTestDb(t)
+
+	for i := 1; i <= 3; i++ {
+		match := model.Match{
+			Type:      model.Qualification,
+			TypeOrder: i,
+			Time:      time.Unix(int64(1000*i), 0).UTC(),
+			LongName:  "Qualification " + strconv.Itoa(i),
+			ShortName: "Q" + strconv.Itoa(i),
+			Red1:      i,
+			Blue1:     10 + i,
+			Status:    game.MatchScheduled,
+		}
+		database.CreateMatch(&match)
+	}
+	database.CreateScheduledBreak(
+		&model.ScheduledBreak{MatchType: model.Qualification, TypeOrderBefore: 3, Time: time.Unix(2500, 0).UTC()},
+	)
+
+	_, err := CreateReplayMatch(database, 1, model.ReplayFieldFault, 2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot replay match Q1 because it has not been played", err.Error())
+	}
+
+	match1, _ := database.GetMatchById(1)
+	match1.Status = game.RedWonMatch
+	database.UpdateMatch(match1)
+	_, err = CreateReplayMatch(database, 1, model.NoReplay, 2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "a reason must be given to replay a match", err.Error())
+	}
+	_, err = CreateReplayMatch(database, 1, model.ReplayFieldFault, 0)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "replay of match Q1 cannot be scheduled before the original", err.Error())
+	}
+
+	replayMatch, err := CreateReplayMatch(database, 1, model.ReplayFieldFault, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, replayMatch.TypeOrder)
+	assert.Equal(t, "Q1R", replayMatch.ShortName)
+	assert.Equal(t, "Qualification 1 Replay", replayMatch.LongName)
+	assert.Equal(t, int64(3000), replayMatch.Time.Unix())
+	assert.Equal(t, 1, replayMatch.Red1)
+	assert.Equal(t, 11, replayMatch.Blue1)
+	assert.Equal(t, game.MatchScheduled, replayMatch.Status)
+	assert.Equal(t, 1, replayMatch.ReplayOfMatchId)
+
+	matches, _ := database.GetMatchesByType(model.Qualification, true)
+	if assert.Equal(t, 4, len(matches)) {
+		assert.Equal(t, "Q1", matches[0].ShortName)
+		assert.True(t, matches[0].IsReplaced())
+		assert.Equal(t, replayMatch.Id, matches[0].ReplacedByMatchId)
+		assert.Equal(t, model.ReplayFieldFault, matches[0].ReplayReason)
+		assert.Equal(t, "Q2", matches[1].ShortName)
+		assert.Equal(t, "Q1R", matches[2].ShortName)
+		assert.Equal(t, "Q3", matches[3].ShortName)
+		assert.Equal(t, 4, matches[3].TypeOrder)
+
+		// Check that the later match has been pushed back by a match cycle so that no two matches share a slot.
+		assert.Equal(t, int64(2000), matches[1].Time.Unix())
+		assert.Equal(t, int64(4000), matches[3].Time.Unix())
+	}
+	scheduledBreaks, _ := database.GetScheduledBreaksByMatchType(model.Qualification)
+	if assert.Equal(t, 1, len(scheduledBreaks)) {
+		assert.Equal(t, 4, scheduledBreaks[0].TypeOrderBefore)
+		assert.Equal(t, int64(3500), scheduledBreaks[0].Time.Unix())
+	}
+
+	// Check that a replay scheduled at the end takes the slot after the last match.
+	match2, _ := database.GetMatchById(2)
+	match2.Status = game.BlueWonMatch
+	database.UpdateMatch(match2)
+	replayMatch, err = CreateReplayMatch(database, 2, model.ReplayFieldFault, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, replayMatch.TypeOrder)
+	assert.Equal(t, int64(5000), replayMatch.Time.Unix())
+
+	_, err = CreateReplayMatch(database, 1, model.ReplayFieldFault, 2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "match Q1 has already been flagged for replay", err.Error())
+	}
+
+	playoffMatch := model.Match{Type: model.Playoff, Status: game.RedWonMatch}
+	database.CreateMatch(&playoffMatch)
+	_, err = CreateReplayMatch(database, playoffMatch.Id, model.ReplayFieldFault, 2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot replay Playoff matches", err.Error())
+	}
+}
+
+func TestCalculateRankingsExcludesReplacedMatches(t *testing.T) {
+	database := setupTestDb(t)
+
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch, ReplacedByMatchId: 2}
+	database.CreateMatch(&match1)
+	database.CreateMatchResult(model.BuildTestMatchResult(match1.Id, 1))
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.BlueWonMatch, ReplayOfMatchId: 1}
+	database.CreateMatch(&match2)
+	matchResult2 := model.BuildTestMatchResult(match2.Id, 1)
+	matchResult2.RedScore, matchResult2.BlueScore = matchResult2.BlueScore, matchResult2.RedScore
+	database.CreateMatchResult(matchResult2)
+
+	rankings, err := CalculateRankings(database, false)
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(rankings)) {
+		for _, ranking := range rankings {
+			assert.Equal(t, 1, ranking.Played)
+		}
+	}
+}
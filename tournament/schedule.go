@@ -18,50 +18,49 @@ import (
 )
 
 const (
-	schedulesDir  = "schedules"
-	TeamsPerMatch = 6
+	schedulesDir        = "schedules"
+	MaxTeamsPerAlliance = 3
 )
 
 // Creates a random schedule for the given parameters and returns it as a list of matches.
 func BuildRandomSchedule(
-	teams []model.Team, scheduleBlocks []model.ScheduleBlock, matchType model.MatchType,
+	teams []model.Team, scheduleBlocks []model.ScheduleBlock, matchType model.MatchType, teamsPerAlliance int,
 ) ([]model.Match, error) {
-	// Load the anonymized, pre-randomized match schedule for the given number of teams and matches per team.
+	if teamsPerAlliance < 1 || teamsPerAlliance > MaxTeamsPerAlliance {
+		return nil, fmt.Errorf("Invalid number of teams per alliance: %d", teamsPerAlliance)
+	}
+	teamsPerMatch := 2 * teamsPerAlliance
 	numTeams := len(teams)
 	numMatches := countMatches(scheduleBlocks)
-	matchesPerTeam := int(float32(numMatches*TeamsPerMatch) / float32(numTeams))
+	matchesPerTeam := int(float32(numMatches*teamsPerMatch) / float32(numTeams))
 
 	// Adjust the number of matches to remove any excess from non-perfect block scheduling.
-	numMatches = int(math.Ceil(float64(numTeams) * float64(matchesPerTeam) / TeamsPerMatch))
-
-	file, err := os.Open(fmt.Sprintf("%s/%d_%d.csv", filepath.Join(model.BaseDir, schedulesDir), numTeams,
-		matchesPerTeam))
-	if err != nil {
-		return nil, fmt.Errorf("No schedule template exists for %d teams and %d matches", numTeams, matchesPerTeam)
-	}
-	defer file.Close()
-	reader := csv.NewReader(file)
-	csvLines, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-	if len(csvLines) != numMatches {
-		return nil, fmt.Errorf("Schedule file contains %d matches, expected %d", len(csvLines), numMatches)
-	}
+	numMatches = int(math.Ceil(float64(numTeams) * float64(matchesPerTeam) / float64(teamsPerMatch)))
 
-	// Convert string fields from schedule to integers.
-	anonSchedule := make([][12]int, numMatches)
-	for i := 0; i < numMatches; i++ {
-		for j := 0; j < 12; j++ {
-			anonSchedule[i][j], err = strconv.Atoi(csvLines[i][j])
-			if err != nil {
-				return nil, err
-			}
+	var anonSchedule [][12]int
+	if teamsPerAlliance == MaxTeamsPerAlliance {
+		// Load the anonymized, pre-randomized match schedule for the given number of teams and matches per team.
+		var err error
+		if anonSchedule, err = loadAnonSchedule(numTeams, matchesPerTeam, numMatches); err != nil {
+			return nil, err
 		}
+	} else {
+		// There are no pre-randomized templates for smaller alliances, so generate the schedule on the fly instead.
+		if numTeams < teamsPerMatch {
+			return nil, fmt.Errorf("At least %d teams are required to build a schedule", teamsPerMatch)
+		}
+		anonSchedule = generateAnonSchedule(numTeams, matchesPerTeam, numMatches, teamsPerAlliance)
 	}
 
 	// Generate a random permutation of the team ordering to fill into the pre-randomized schedule.
 	teamShuffle := rand.Perm(numTeams)
+	teamId := func(anonTeam int) int {
+		if anonTeam == 0 {
+			// The position is unused in this match format.
+			return 0
+		}
+		return teams[teamShuffle[anonTeam-1]].Id
+	}
 	matches := make([]model.Match, numMatches)
 	for i, anonMatch := range anonSchedule {
 		matches[i].Type = matchType
@@ -77,17 +76,17 @@ func BuildRandomSchedule(
 		} else {
 			return nil, fmt.Errorf("invalid match type %q", matchType)
 		}
-		matches[i].Red1 = teams[teamShuffle[anonMatch[0]-1]].Id
+		matches[i].Red1 = teamId(anonMatch[0])
 		matches[i].Red1IsSurrogate = anonMatch[1] == 1
-		matches[i].Red2 = teams[teamShuffle[anonMatch[2]-1]].Id
+		matches[i].Red2 = teamId(anonMatch[2])
 		matches[i].Red2IsSurrogate = anonMatch[3] == 1
-		matches[i].Red3 = teams[teamShuffle[anonMatch[4]-1]].Id
+		matches[i].Red3 = teamId(anonMatch[4])
 		matches[i].Red3IsSurrogate = anonMatch[5] == 1
-		matches[i].Blue1 = teams[teamShuffle[anonMatch[6]-1]].Id
+		matches[i].Blue1 = teamId(anonMatch[6])
 		matches[i].Blue1IsSurrogate = anonMatch[7] == 1
-		matches[i].Blue2 = teams[teamShuffle[anonMatch[8]-1]].Id
+		matches[i].Blue2 = teamId(anonMatch[8])
 		matches[i].Blue2IsSurrogate = anonMatch[9] == 1
-		matches[i].Blue3 = teams[teamShuffle[anonMatch[10]-1]].Id
+		matches[i].Blue3 = teamId(anonMatch[10])
 		matches[i].Blue3IsSurrogate = anonMatch[11] == 1
 		matches[i].TbaMatchKey.MatchNumber = i + 1
 	}
@@ -104,6 +103,78 @@ func BuildRandomSchedule(
 	return matches, nil
 }
 
+// Reads the pre-randomized schedule template for full-size alliances from disk.
+func loadAnonSchedule(numTeams, matchesPerTeam, numMatches int) ([][12]int, error) {
+	file, err := os.Open(fmt.Sprintf("%s/%d_%d.csv", filepath.Join(model.BaseDir, schedulesDir), numTeams,
+		matchesPerTeam))
+	if err != nil {
+		return nil, fmt.Errorf("No schedule template exists for %d teams and %d matches", numTeams, matchesPerTeam)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	csvLines, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(csvLines) != numMatches {
+		return nil, fmt.Errorf("Schedule file contains %d matches, expected %d", len(csvLines), numMatches)
+	}
+
+	// Convert string fields from schedule to integers.
+	anonSchedule := make([][12]int, numMatches)
+	for i := 0; i < numMatches; i++ {
+		for j := 0; j < 12; j++ {
+			anonSchedule[i][j], err = strconv.Atoi(csvLines[i][j])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return anonSchedule, nil
+}
+
+// Generates an anonymized schedule in the same format as the pre-randomized templates, for alliances of fewer than
+// three teams. Teams are drawn from successive random orderings so that appearance counts stay balanced, and any
+// appearances needed beyond the per-team match count to fill out the final match are marked as surrogates.
+func generateAnonSchedule(numTeams, matchesPerTeam, numMatches, teamsPerAlliance int) [][12]int {
+	anonSchedule := make([][12]int, numMatches)
+	var pool []int
+	numAppearances := 0
+	for i := 0; i < numMatches; i++ {
+		teamsInMatch := make(map[int]struct{})
+		for _, allianceOffset := range []int{0, MaxTeamsPerAlliance} {
+			for position := 0; position < teamsPerAlliance; position++ {
+				// Take the next team in the pool that isn't already in this match, topping up the pool as needed.
+				poolIndex := -1
+				for poolIndex < 0 {
+					for j, team := range pool {
+						if _, ok := teamsInMatch[team]; !ok {
+							poolIndex = j
+							break
+						}
+					}
+					if poolIndex < 0 {
+						for _, team := range rand.Perm(numTeams) {
+							pool = append(pool, team+1)
+						}
+					}
+				}
+				team := pool[poolIndex]
+				pool = append(pool[:poolIndex], pool[poolIndex+1:]...)
+				teamsInMatch[team] = struct{}{}
+
+				slot := 2 * (allianceOffset + position)
+				anonSchedule[i][slot] = team
+				if numAppearances >= numTeams*matchesPerTeam {
+					anonSchedule[i][slot+1] = 1
+				}
+				numAppearances++
+			}
+		}
+	}
+	return anonSchedule
+}
+
 // Returns the total number of matches that can be run within the given schedule blocks.
 func countMatches(scheduleBlocks []model.ScheduleBlock) int {
 	numMatches := 0
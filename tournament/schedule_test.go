@@ -21,7 +21,7 @@ func TestMain(m *testing.M) {
 func TestNonExistentSchedule(t *testing.T) {
 	teams := make([]model.Team, 5)
 	scheduleBlocks := []model.ScheduleBlock{{0, model.Test, time.Unix(0, 0).UTC(), 2, 60}}
-	_, err := BuildRandomSchedule(teams, scheduleBlocks, model.Test)
+	_, err := BuildRandomSchedule(teams, scheduleBlocks, model.Test, 3)
 	expectedErr := "No schedule template exists for 5 teams and 2 matches"
 	if assert.NotNil(t, err) {
 		assert.Equal(t, expectedErr, err.Error())
@@ -36,7 +36,7 @@ func TestMalformedSchedule(t *testing.T) {
 	scheduleFile.Close()
 	teams := make([]model.Team, 5)
 	scheduleBlocks := []model.ScheduleBlock{{0, model.Test, time.Unix(0, 0).UTC(), 1, 60}}
-	_, err := BuildRandomSchedule(teams, scheduleBlocks, model.Test)
+	_, err := BuildRandomSchedule(teams, scheduleBlocks, model.Test, 3)
 	expectedErr := "Schedule file contains 2 matches, expected 1"
 	if assert.NotNil(t, err) {
 		assert.Equal(t, expectedErr, err.Error())
@@ -46,7 +46,7 @@ func TestMalformedSchedule(t *testing.T) {
 	scheduleFile, _ = os.Create(filename)
 	scheduleFile.WriteString("1,0,asdf,0,3,0,4,0,5,0,6,0\n")
 	scheduleFile.Close()
-	_, err = BuildRandomSchedule(teams, scheduleBlocks, model.Test)
+	_, err = BuildRandomSchedule(teams, scheduleBlocks, model.Test, 3)
 	if assert.NotNil(t, err) {
 		assert.Contains(t, err.Error(), "strconv.Atoi")
 	}
@@ -61,7 +61,7 @@ func TestScheduleTeams(t *testing.T) {
 		teams[i].Id = i + 101
 	}
 	scheduleBlocks := []model.ScheduleBlock{{0, model.Practice, time.Unix(0, 0).UTC(), 6, 60}}
-	matches, err := BuildRandomSchedule(teams, scheduleBlocks, model.Practice)
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, model.Practice, 3)
 	assert.Nil(t, err)
 	assertMatch(t, matches[0], model.Practice, 1, 0, "P1", "Practice 1", "p", 115, 111, 108, 109, 116, 117)
 	assertMatch(t, matches[1], model.Practice, 2, 60, "P2", "Practice 2", "p", 114, 112, 103, 101, 104, 118)
@@ -72,13 +72,13 @@ func TestScheduleTeams(t *testing.T) {
 
 	// Check with excess room for matches in the schedule.
 	scheduleBlocks = []model.ScheduleBlock{{0, model.Practice, time.Unix(0, 0).UTC(), 7, 60}}
-	matches, err = BuildRandomSchedule(teams, scheduleBlocks, model.Practice)
+	matches, err = BuildRandomSchedule(teams, scheduleBlocks, model.Practice, 3)
 	assert.Nil(t, err)
 
 	// Check with qualification matches.
 	rand.Seed(0)
 	scheduleBlocks = []model.ScheduleBlock{{0, model.Qualification, time.Unix(0, 0).UTC(), 6, 60}}
-	matches, err = BuildRandomSchedule(teams, scheduleBlocks, model.Qualification)
+	matches, err = BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 3)
 	assert.Nil(t, err)
 	assertMatch(t, matches[0], model.Qualification, 1, 0, "Q1", "Qualification 1", "qm", 115, 111, 108, 109, 116, 117)
 	assertMatch(t, matches[1], model.Qualification, 2, 60, "Q2", "Qualification 2", "qm", 114, 112, 103, 101, 104, 118)
@@ -95,7 +95,7 @@ func TestScheduleTiming(t *testing.T) {
 		{0, model.Qualification, time.Unix(20000, 0).UTC(), 5, 1000},
 		{0, model.Qualification, time.Unix(100000, 0).UTC(), 15, 29},
 	}
-	matches, err := BuildRandomSchedule(teams, scheduleBlocks, model.Qualification)
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 3)
 	assert.Nil(t, err)
 	assert.Equal(t, time.Unix(100, 0).UTC(), matches[0].Time)
 	assert.Equal(t, time.Unix(775, 0).UTC(), matches[9].Time)
@@ -114,7 +114,7 @@ func TestScheduleSurrogates(t *testing.T) {
 		teams[i].Id = i + 101
 	}
 	scheduleBlocks := []model.ScheduleBlock{{0, model.Qualification, time.Unix(0, 0).UTC(), 64, 60}}
-	matches, _ := BuildRandomSchedule(teams, scheduleBlocks, model.Qualification)
+	matches, _ := BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 3)
 	for i, match := range matches {
 		if i == 13 || i == 14 {
 			if !match.Red1IsSurrogate || match.Red2IsSurrogate || match.Red3IsSurrogate ||
@@ -157,3 +157,66 @@ func assertMatch(
 	assert.Equal(t, 0, match.TbaMatchKey.SetNumber)
 	assert.Equal(t, typeOrder, match.TbaMatchKey.MatchNumber)
 }
+
+func TestScheduleSmallFormat(t *testing.T) {
+	rand.Seed(0)
+
+	numTeams := 9
+	teams := make([]model.Team, numTeams)
+	for i := 0; i < numTeams; i++ {
+		teams[i].Id = i + 101
+	}
+	scheduleBlocks := []model.ScheduleBlock{{0, model.Qualification, time.Unix(0, 0).UTC(), 9, 60}}
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(matches))
+	appearances := make(map[int]int)
+	numSurrogates := 0
+	for _, match := range matches {
+		assert.Equal(t, 0, match.Red3)
+		assert.Equal(t, 0, match.Blue3)
+		matchTeams := []int{match.Red1, match.Red2, match.Blue1, match.Blue2}
+		teamSet := make(map[int]struct{})
+		for _, team := range matchTeams {
+			assert.NotEqual(t, 0, team)
+			teamSet[team] = struct{}{}
+			appearances[team]++
+		}
+		assert.Equal(t, 4, len(teamSet), "duplicate team in match %s", match.ShortName)
+		for _, isSurrogate := range []bool{
+			match.Red1IsSurrogate, match.Red2IsSurrogate, match.Blue1IsSurrogate, match.Blue2IsSurrogate,
+		} {
+			if isSurrogate {
+				numSurrogates++
+			}
+		}
+	}
+	for _, team := range teams {
+		assert.GreaterOrEqual(t, appearances[team.Id], 4)
+	}
+	assert.Equal(t, 0, numSurrogates)
+
+	// Check 1v1 with a surrogate needed to fill out the final match.
+	teams = teams[:5]
+	scheduleBlocks = []model.ScheduleBlock{{0, model.Qualification, time.Unix(0, 0).UTC(), 3, 60}}
+	matches, err = BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(matches))
+	for _, match := range matches {
+		assert.NotEqual(t, match.Red1, match.Blue1)
+		assert.Equal(t, 0, match.Red2)
+		assert.Equal(t, 0, match.Blue2)
+	}
+	assert.False(t, matches[2].Red1IsSurrogate)
+	assert.True(t, matches[2].Blue1IsSurrogate)
+
+	// Check invalid alliance sizes.
+	_, err = BuildRandomSchedule(teams, scheduleBlocks, model.Qualification, 4)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid number of teams per alliance: 4", err.Error())
+	}
+	_, err = BuildRandomSchedule(teams[:3], scheduleBlocks, model.Qualification, 2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "At least 4 teams are required to build a schedule", err.Error())
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for seeding the qualification rankings or playoff alliances from external event data, for running a
+// playoff-only event without qualification matches.
+
+package tournament
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parses a seeding CSV file in which each row contains one or more team numbers. Blank cells are ignored, as is a
+// leading header row if one is present.
+func ParseSeedingCsv(reader io.Reader) ([][]int, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvLines, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]int
+	for i, line := range csvLines {
+		var row []int
+		for _, cell := range line {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			teamId, err := strconv.Atoi(cell)
+			if err != nil {
+				if i == 0 {
+					// Treat a non-numeric first row as a header.
+					row = nil
+					break
+				}
+				return nil, fmt.Errorf("Invalid team number '%s' on line %d", cell, i+1)
+			}
+			row = append(row, teamId)
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// Replaces the qualification rankings with the given teams, in rank order, so that alliance selection can proceed
+// without any qualification matches having been played.
+func SeedRankings(database *model.Database, teamIds []int) (game.Rankings, error) {
+	if len(teamIds) == 0 {
+		return nil, fmt.Errorf("No teams were given to seed the rankings with")
+	}
+	if err := checkSeedingTeams(database, teamIds); err != nil {
+		return nil, err
+	}
+
+	rankings := make(game.Rankings, len(teamIds))
+	for i, teamId := range teamIds {
+		rankings[i] = game.Ranking{TeamId: teamId, Rank: i + 1}
+	}
+	if err := database.ReplaceAllRankings(rankings); err != nil {
+		return nil, err
+	}
+	return rankings, nil
+}
+
+// Builds the set of playoff alliances from the given lists of team numbers, in alliance and pick order, validating
+// them against the event's playoff configuration. Alliances with fewer teams than the maximum are padded with empty
+// spots.
+func BuildSeededAlliances(
+	database *model.Database, allianceTeamIds [][]int, numAlliances, maxTeamsPerAlliance int,
+) ([]model.Alliance, error) {
+	if len(allianceTeamIds) != numAlliances {
+		return nil, fmt.Errorf(
+			"Expected %d alliances to match the event settings but got %d", numAlliances, len(allianceTeamIds),
+		)
+	}
+
+	var allTeamIds []int
+	alliances := make([]model.Alliance, numAlliances)
+	for i, teamIds := range allianceTeamIds {
+		if len(teamIds) == 0 || len(teamIds) > maxTeamsPerAlliance {
+			return nil, fmt.Errorf(
+				"Alliance %d has %d teams; it must have between 1 and %d", i+1, len(teamIds), maxTeamsPerAlliance,
+			)
+		}
+		alliances[i].Id = i + 1
+		alliances[i].TeamIds = make([]int, maxTeamsPerAlliance)
+		copy(alliances[i].TeamIds, teamIds)
+		allTeamIds = append(allTeamIds, teamIds...)
+	}
+	if err := checkSeedingTeams(database, allTeamIds); err != nil {
+		return nil, err
+	}
+	return alliances, nil
+}
+
+// Returns an error if any of the given teams isn't registered for the event or appears more than once.
+func checkSeedingTeams(database *model.Database, teamIds []int) error {
+	seenTeamIds := make(map[int]struct{})
+	for _, teamId := range teamIds {
+		if _, ok := seenTeamIds[teamId]; ok {
+			return fmt.Errorf("Team %d appears more than once", teamId)
+		}
+		seenTeamIds[teamId] = struct{}{}
+
+		team, err := database.GetTeamById(teamId)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return fmt.Errorf("Team %d is not registered for this event", teamId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseSeedingCsv(t *testing.T) {
+	rows, err := ParseSeedingCsv(strings.NewReader("Captain,Pick 1,Pick 2\n254,1114, 2056\n\n1503,148,\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{254, 1114, 2056}, {1503, 148}}, rows)
+
+	_, err = ParseSeedingCsv(strings.NewReader("254,1114\n1503,asdf\n"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid team number 'asdf' on line 2", err.Error())
+	}
+}
+
+func TestSeedRankings(t *testing.T) {
+	database := setupTestDb(t)
+	for _, teamId := range []int{254, 1114, 2056} {
+		database.CreateTeam(&model.Team{Id: teamId})
+	}
+
+	rankings, err := SeedRankings(database, []int{1114, 254, 2056})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rankings))
+	savedRankings, _ := database.GetAllRankings()
+	if assert.Equal(t, 3, len(savedRankings)) {
+		assert.Equal(t, 1114, savedRankings[0].TeamId)
+		assert.Equal(t, 1, savedRankings[0].Rank)
+		assert.Equal(t, 2056, savedRankings[2].TeamId)
+		assert.Equal(t, 3, savedRankings[2].Rank)
+	}
+
+	_, err = SeedRankings(database, []int{254, 1503})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 1503 is not registered for this event", err.Error())
+	}
+	_, err = SeedRankings(database, []int{254, 1114, 254})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 254 appears more than once", err.Error())
+	}
+	_, err = SeedRankings(database, []int{})
+	assert.NotNil(t, err)
+
+	// Check that the rankings are untouched after a failed seeding.
+	savedRankings, _ = database.GetAllRankings()
+	assert.Equal(t, 3, len(savedRankings))
+}
+
+func TestBuildSeededAlliances(t *testing.T) {
+	database := setupTestDb(t)
+	for _, teamId := range []int{254, 1114, 2056, 1503, 148, 971} {
+		database.CreateTeam(&model.Team{Id: teamId})
+	}
+
+	alliances, err := BuildSeededAlliances(database, [][]int{{254, 1114, 2056}, {1503, 148}}, 2, 3)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(alliances)) {
+		assert.Equal(t, model.Alliance{Id: 1, TeamIds: []int{254, 1114, 2056}}, alliances[0])
+		assert.Equal(t, model.Alliance{Id: 2, TeamIds: []int{1503, 148, 0}}, alliances[1])
+	}
+
+	_, err = BuildSeededAlliances(database, [][]int{{254, 1114, 2056}}, 2, 3)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Expected 2 alliances to match the event settings but got 1", err.Error())
+	}
+	_, err = BuildSeededAlliances(database, [][]int{{254, 1114, 2056, 971}, {1503, 148}}, 2, 3)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Alliance 1 has 4 teams; it must have between 1 and 3", err.Error())
+	}
+	_, err = BuildSeededAlliances(database, [][]int{{254, 1114}, {1503, 254}}, 2, 3)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 254 appears more than once", err.Error())
+	}
+	_, err = BuildSeededAlliances(database, [][]int{{254, 1114}, {1503, 9999}}, 2, 3)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 9999 is not registered for this event", err.Error())
+	}
+}
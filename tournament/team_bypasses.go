@@ -0,0 +1,79 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for compiling the history of alliance stations bypassed over the course of the event.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"sort"
+	"strconv"
+)
+
+// Represents a team whose station was bypassed in a completed match.
+type TeamBypass struct {
+	MatchId    int
+	MatchName  string
+	MatchType  model.MatchType
+	TeamId     int
+	Alliance   string
+	Reason     string
+	ReasonName string
+}
+
+// Returns all the bypasses in completed qualification and playoff matches, in the order in which they were played.
+func GetTeamBypasses(database *model.Database) ([]TeamBypass, error) {
+	var teamBypasses []TeamBypass
+	for _, matchType := range []model.MatchType{model.Qualification, model.Playoff} {
+		matches, err := database.GetMatchesByType(matchType, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !match.IsComplete() || match.IsReplaced() {
+				continue
+			}
+			matchResult, err := database.GetMatchResultForMatch(match.Id)
+			if err != nil {
+				return nil, err
+			}
+			if matchResult == nil {
+				return nil, fmt.Errorf("found no match result for match %d", match.Id)
+			}
+			teamBypasses = appendTeamBypasses(teamBypasses, &match, "red", matchResult.RedBypasses)
+			teamBypasses = appendTeamBypasses(teamBypasses, &match, "blue", matchResult.BlueBypasses)
+		}
+	}
+	return teamBypasses, nil
+}
+
+// Appends the bypasses from the given alliance's bypass map to the list, ordered by team number.
+func appendTeamBypasses(
+	teamBypasses []TeamBypass, match *model.Match, alliance string, bypasses map[string]string,
+) []TeamBypass {
+	var allianceBypasses []TeamBypass
+	for teamIdString, reason := range bypasses {
+		teamId, err := strconv.Atoi(teamIdString)
+		if err != nil || reason == "" {
+			continue
+		}
+		allianceBypasses = append(
+			allianceBypasses,
+			TeamBypass{
+				MatchId:    match.Id,
+				MatchName:  match.ShortName,
+				MatchType:  match.Type,
+				TeamId:     teamId,
+				Alliance:   alliance,
+				Reason:     reason,
+				ReasonName: model.BypassReasonNames[reason],
+			},
+		)
+	}
+	sort.Slice(allianceBypasses, func(i, j int) bool {
+		return allianceBypasses[i].TeamId < allianceBypasses[j].TeamId
+	})
+	return append(teamBypasses, allianceBypasses...)
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetTeamBypasses(t *testing.T) {
+	database := setupTestDb(t)
+
+	teamBypasses, err := GetTeamBypasses(database)
+	assert.Nil(t, err)
+	assert.Empty(t, teamBypasses)
+
+	qualificationMatch := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2,
+		Red3: 3, Blue1: 4, Blue2: 5, Blue3: 6, Status: game.RedWonMatch}
+	database.CreateMatch(&qualificationMatch)
+	qualificationMatchResult := model.BuildTestMatchResult(qualificationMatch.Id, 1)
+	qualificationMatchResult.RedBypasses = map[string]string{"3": model.BypassRobotFailure, "1": model.BypassNoShow}
+	qualificationMatchResult.BlueBypasses = map[string]string{"5": ""}
+	database.CreateMatchResult(qualificationMatchResult)
+	incompleteMatch := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	database.CreateMatch(&incompleteMatch)
+	playoffMatch := model.Match{Type: model.Playoff, TypeOrder: 1, ShortName: "M1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4,
+		Blue2: 5, Blue3: 6, Status: game.BlueWonMatch}
+	database.CreateMatch(&playoffMatch)
+	playoffMatchResult := model.BuildTestMatchResult(playoffMatch.Id, 1)
+	playoffMatchResult.BlueBypasses = map[string]string{"6": model.BypassRedCard}
+	database.CreateMatchResult(playoffMatchResult)
+
+	teamBypasses, err = GetTeamBypasses(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(teamBypasses)) {
+		assert.Equal(
+			t,
+			TeamBypass{qualificationMatch.Id, "Q1", model.Qualification, 1, "red", model.BypassNoShow, "No-show"},
+			teamBypasses[0],
+		)
+		assert.Equal(t, 3, teamBypasses[1].TeamId)
+		assert.Equal(t, "Robot failure", teamBypasses[1].ReasonName)
+		assert.Equal(
+			t,
+			TeamBypass{playoffMatch.Id, "M1", model.Playoff, 6, "blue", model.BypassRedCard, "Red card"},
+			teamBypasses[2],
+		)
+	}
+}
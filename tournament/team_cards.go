@@ -0,0 +1,82 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for compiling the history of cards given to teams over the course of the event.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"sort"
+	"strconv"
+)
+
+// Represents a card given to a team in a completed match.
+type TeamCard struct {
+	MatchId    int
+	MatchName  string
+	MatchType  model.MatchType
+	TeamId     int
+	Alliance   string
+	Card       string
+	RuleNumber string // Number of the rule for which the card was given, if known.
+}
+
+// Returns all the cards given in completed qualification and playoff matches, in the order in which they were played.
+func GetTeamCards(database *model.Database) ([]TeamCard, error) {
+	var teamCards []TeamCard
+	for _, matchType := range []model.MatchType{model.Qualification, model.Playoff} {
+		matches, err := database.GetMatchesByType(matchType, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !match.IsComplete() || match.IsReplaced() {
+				continue
+			}
+			matchResult, err := database.GetMatchResultForMatch(match.Id)
+			if err != nil {
+				return nil, err
+			}
+			if matchResult == nil {
+				return nil, fmt.Errorf("found no match result for match %d", match.Id)
+			}
+			teamCards = appendTeamCards(teamCards, &match, "red", matchResult.RedCards, matchResult.RedCardRules)
+			teamCards = appendTeamCards(teamCards, &match, "blue", matchResult.BlueCards, matchResult.BlueCardRules)
+		}
+	}
+	return teamCards, nil
+}
+
+// Appends the non-blank cards from the given alliance's card map to the list, ordered by team number.
+func appendTeamCards(
+	teamCards []TeamCard, match *model.Match, alliance string, cards map[string]string, cardRules map[string]int,
+) []TeamCard {
+	var allianceCards []TeamCard
+	for teamIdString, card := range cards {
+		teamId, err := strconv.Atoi(teamIdString)
+		if err != nil || card == "" {
+			continue
+		}
+		allianceCards = append(
+			allianceCards,
+			TeamCard{
+				MatchId:   match.Id,
+				MatchName: match.ShortName,
+				MatchType: match.Type,
+				TeamId:    teamId,
+				Alliance:  alliance,
+				Card:      card,
+			},
+		)
+		if rule := game.GetRuleById(cardRules[teamIdString]); rule != nil {
+			allianceCards[len(allianceCards)-1].RuleNumber = rule.RuleNumber
+		}
+	}
+	sort.Slice(allianceCards, func(i, j int) bool {
+		return allianceCards[i].TeamId < allianceCards[j].TeamId
+	})
+	return append(teamCards, allianceCards...)
+}
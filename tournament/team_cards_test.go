@@ -0,0 +1,48 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetTeamCards(t *testing.T) {
+	database := setupTestDb(t)
+
+	teamCards, err := GetTeamCards(database)
+	assert.Nil(t, err)
+	assert.Empty(t, teamCards)
+
+	setupMatchResultsForRankings(database)
+	playoffMatch1 := model.Match{Type: model.Playoff, TypeOrder: 1, ShortName: "M1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4,
+		Blue2: 5, Blue3: 6, Status: game.BlueWonMatch}
+	database.CreateMatch(&playoffMatch1)
+	playoffMatchResult1 := model.BuildTestMatchResult(playoffMatch1.Id, 1)
+	playoffMatchResult1.RedCards = map[string]string{"3": "yellow", "1": "yellow", "2": "yellow"}
+	playoffMatchResult1.BlueCards = map[string]string{"5": ""}
+	database.CreateMatchResult(playoffMatchResult1)
+	playoffMatch2 := model.Match{Type: model.Playoff, TypeOrder: 2, ShortName: "M2", Red1: 1, Red2: 2, Red3: 3, Blue1: 4,
+		Blue2: 5, Blue3: 6, Status: game.RedWonMatch}
+	database.CreateMatch(&playoffMatch2)
+	playoffMatchResult2 := model.BuildTestMatchResult(playoffMatch2.Id, 1)
+	playoffMatchResult2.RedCards = map[string]string{}
+	playoffMatchResult2.BlueCards = map[string]string{"6": "red"}
+	playoffMatchResult2.BlueCardRules = map[string]int{"6": 22}
+	database.CreateMatchResult(playoffMatchResult2)
+
+	teamCards, err = GetTeamCards(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 7, len(teamCards)) {
+		assert.Equal(t, TeamCard{1, "", model.Qualification, 2, "red", "red", ""}, teamCards[0])
+		assert.Equal(t, TeamCard{2, "", model.Qualification, 1868, "red", "yellow", ""}, teamCards[1])
+		assert.Equal(t, TeamCard{playoffMatch1.Id, "M1", model.Playoff, 1, "red", "yellow", ""}, teamCards[2])
+		assert.Equal(t, 2, teamCards[3].TeamId)
+		assert.Equal(t, 3, teamCards[4].TeamId)
+		assert.Equal(t, TeamCard{playoffMatch2.Id, "M2", model.Playoff, 6, "blue", "red", "G418"}, teamCards[5])
+		assert.Equal(t, 1868, teamCards[6].TeamId)
+	}
+}
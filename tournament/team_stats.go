@@ -0,0 +1,189 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Functions for calculating each team's offensive and defensive contributions (OPR, DPR and CCWM) by least squares
+// over the completed qualification matches.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"math"
+	"sort"
+)
+
+// Small regularization term added to the diagonal of the normal equations so that they can still be solved early in
+// the event, when there are fewer matches than teams and the system is underdetermined.
+const teamStatsRegularization = 1e-3
+
+// The per-alliance quantities for which a contribution is calculated for each team.
+var teamStatComponents = []func(summary, opponentSummary *game.ScoreSummary) float64{
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.Score) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(opponentSummary.Score) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.AutoPoints) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.AmpPoints) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.SpeakerPoints) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.StagePoints) },
+	func(summary, opponentSummary *game.ScoreSummary) float64 { return float64(summary.FoulPoints) },
+}
+
+// Calculates the OPR, DPR, CCWM and component OPRs of every team that has played a counted qualification match, and
+// saves them to the database.
+func CalculateTeamStats(database *model.Database) ([]model.TeamStat, error) {
+	matches, err := database.GetMatchesByType(model.Qualification, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the teams and score components for each alliance of each counted match.
+	teamIndices := make(map[int]int)
+	var teamIds []int
+	type allianceRow struct {
+		teamIds []int
+		values  []float64
+	}
+	var rows []allianceRow
+	for _, match := range matches {
+		if !match.IsComplete() || match.IsReplaced() {
+			// Only the result of a replay counts when a match has been replayed.
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if matchResult == nil {
+			return nil, fmt.Errorf("found no match result for match %d", match.Id)
+		}
+		redSummary := matchResult.RedScoreSummary()
+		blueSummary := matchResult.BlueScoreSummary()
+		for _, alliance := range []struct {
+			teamIds                  [3]int
+			summary, opponentSummary *game.ScoreSummary
+		}{
+			{[3]int{match.Red1, match.Red2, match.Red3}, redSummary, blueSummary},
+			{[3]int{match.Blue1, match.Blue2, match.Blue3}, blueSummary, redSummary},
+		} {
+			row := allianceRow{}
+			for _, teamId := range alliance.teamIds {
+				if teamId == 0 {
+					continue
+				}
+				if _, ok := teamIndices[teamId]; !ok {
+					teamIndices[teamId] = len(teamIds)
+					teamIds = append(teamIds, teamId)
+				}
+				row.teamIds = append(row.teamIds, teamId)
+			}
+			for _, component := range teamStatComponents {
+				row.values = append(row.values, component(alliance.summary, alliance.opponentSummary))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// Assign each team a row and column in the normal equations, in order of team number.
+	sort.Ints(teamIds)
+	for i, teamId := range teamIds {
+		teamIndices[teamId] = i
+	}
+
+	// Build the normal equations (AᵀA)x = Aᵀb, where A maps teams to the alliances they played on and each column of
+	// b holds one of the components.
+	numTeams := len(teamIds)
+	matrix := make([][]float64, numTeams)
+	for i := range matrix {
+		matrix[i] = make([]float64, numTeams)
+		matrix[i][i] = teamStatsRegularization
+	}
+	vectors := make([][]float64, len(teamStatComponents))
+	for i := range vectors {
+		vectors[i] = make([]float64, numTeams)
+	}
+	matchesPlayed := make([]int, numTeams)
+	for _, row := range rows {
+		for _, teamId := range row.teamIds {
+			i := teamIndices[teamId]
+			matchesPlayed[i]++
+			for _, otherTeamId := range row.teamIds {
+				matrix[i][teamIndices[otherTeamId]]++
+			}
+			for component, value := range row.values {
+				vectors[component][i] += value
+			}
+		}
+	}
+
+	solutions := make([][]float64, len(vectors))
+	for i, vector := range vectors {
+		if solutions[i], err = solveLinearSystem(matrix, vector); err != nil {
+			return nil, err
+		}
+	}
+
+	teamStats := make([]model.TeamStat, numTeams)
+	for i, teamId := range teamIds {
+		teamStats[i] = model.TeamStat{
+			TeamId:        teamId,
+			MatchesPlayed: matchesPlayed[i],
+			Opr:           roundTeamStat(solutions[0][i]),
+			Dpr:           roundTeamStat(solutions[1][i]),
+			Ccwm:          roundTeamStat(solutions[0][i] - solutions[1][i]),
+			AutoOpr:       roundTeamStat(solutions[2][i]),
+			AmpOpr:        roundTeamStat(solutions[3][i]),
+			SpeakerOpr:    roundTeamStat(solutions[4][i]),
+			StageOpr:      roundTeamStat(solutions[5][i]),
+			FoulOpr:       roundTeamStat(solutions[6][i]),
+		}
+	}
+	if err = database.ReplaceAllTeamStats(teamStats); err != nil {
+		return nil, err
+	}
+	return teamStats, nil
+}
+
+// Solves the given square system of linear equations by Gaussian elimination with partial pivoting, without
+// modifying the inputs.
+func solveLinearSystem(matrix [][]float64, vector []float64) ([]float64, error) {
+	n := len(vector)
+	augmented := make([][]float64, n)
+	for i := range matrix {
+		augmented[i] = append(append([]float64{}, matrix[i]...), vector[i])
+	}
+
+	for column := 0; column < n; column++ {
+		pivot := column
+		for row := column + 1; row < n; row++ {
+			if math.Abs(augmented[row][column]) > math.Abs(augmented[pivot][column]) {
+				pivot = row
+			}
+		}
+		if math.Abs(augmented[pivot][column]) < 1e-12 {
+			return nil, fmt.Errorf("unable to solve singular system of equations")
+		}
+		augmented[column], augmented[pivot] = augmented[pivot], augmented[column]
+		for row := column + 1; row < n; row++ {
+			factor := augmented[row][column] / augmented[column][column]
+			for k := column; k <= n; k++ {
+				augmented[row][k] -= factor * augmented[column][k]
+			}
+		}
+	}
+
+	solution := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := augmented[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= augmented[row][k] * solution[k]
+		}
+		solution[row] = sum / augmented[row][row]
+	}
+	return solution, nil
+}
+
+// Rounds the given stat to two decimal places for storage and display.
+func roundTeamStat(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCalculateTeamStats(t *testing.T) {
+	database := setupTestDb(t)
+
+	teamStats, err := CalculateTeamStats(database)
+	assert.Nil(t, err)
+	assert.Empty(t, teamStats)
+
+	// Only team 1 ever scores, by leaving in autonomous from the first station of its alliance.
+	leaveScore := game.Score{LeaveStatuses: [3]bool{true, false, false}}
+	leavePoints := float64(leaveScore.Summarize(&game.Score{}).Score)
+	lineups := [][6]int{
+		{1, 2, 3, 4, 5, 6},
+		{1, 4, 5, 2, 3, 6},
+		{1, 2, 6, 3, 4, 5},
+		{1, 3, 5, 2, 4, 6},
+		{2, 4, 5, 1, 3, 6},
+	}
+	for i, lineup := range lineups {
+		match := model.Match{Type: model.Qualification, TypeOrder: i + 1, Red1: lineup[0], Red2: lineup[1],
+			Red3: lineup[2], Blue1: lineup[3], Blue2: lineup[4], Blue3: lineup[5], Status: game.TieMatch}
+		assert.Nil(t, database.CreateMatch(&match))
+		matchResult := model.NewMatchResult()
+		matchResult.MatchId = match.Id
+		matchResult.PlayNumber = 1
+		if lineup[0] == 1 {
+			matchResult.RedScore = &leaveScore
+		} else {
+			matchResult.BlueScore = &leaveScore
+		}
+		assert.Nil(t, database.CreateMatchResult(matchResult))
+	}
+
+	// Results from incomplete matches and other match types are ignored.
+	database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 6, Red1: 7, Blue1: 8})
+	practiceMatch := model.Match{Type: model.Practice, Red1: 2, Blue1: 9, Status: game.RedWonMatch}
+	database.CreateMatch(&practiceMatch)
+	database.CreateMatchResult(model.BuildTestMatchResult(practiceMatch.Id, 1))
+
+	teamStats, err = CalculateTeamStats(database)
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(teamStats)) {
+		assert.Equal(t, 1, teamStats[0].TeamId)
+		assert.Equal(t, 5, teamStats[0].MatchesPlayed)
+		assert.InDelta(t, leavePoints, teamStats[0].Opr, 0.05)
+		assert.InDelta(t, leavePoints, teamStats[0].AutoOpr, 0.05)
+		// Every alliance facing team 1 concedes its points, so the other teams each take a third of them as DPR and
+		// team 1's is negative to cancel out its partners' when it is the one facing an alliance that doesn't score.
+		assert.InDelta(t, -2*leavePoints/3, teamStats[0].Dpr, 0.05)
+		assert.InDelta(t, 5*leavePoints/3, teamStats[0].Ccwm, 0.05)
+		assert.InDelta(t, leavePoints/3, teamStats[1].Dpr, 0.05)
+		for _, teamStat := range teamStats[1:] {
+			assert.InDelta(t, 0, teamStat.Opr, 0.05)
+			assert.InDelta(t, 0, teamStat.AmpOpr, 0.05)
+		}
+		assert.Equal(t, 6, teamStats[5].TeamId)
+	}
+
+	// The stats should have been saved to the database.
+	savedTeamStats, err := database.GetAllTeamStats()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(savedTeamStats))
+	assert.Equal(t, 1, savedTeamStats[0].TeamId)
+}
+
+func TestSolveLinearSystem(t *testing.T) {
+	solution, err := solveLinearSystem([][]float64{{0, 2}, {3, 1}}, []float64{4, 5})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 2}, solution, 1e-9)
+
+	_, err = solveLinearSystem([][]float64{{1, 2}, {2, 4}}, []float64{1, 2})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "singular")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Helper methods for use in tests in this package and others.
+
+package updater
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Starts a fake release endpoint whose latest release is the given version, with a bundle for the current platform
+// holding the given files in addition to the server binary. The endpoint is at /releases/latest.
+func NewTestReleaseServer(t *testing.T, version string, files map[string]string) *httptest.Server {
+	bundle := BuildTestBundle(t, files)
+	handler := http.NewServeMux()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	handler.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		assetName := "cheesy-arena." + version + platformAssetSuffix()
+		release := map[string]any{
+			"tag_name":     version,
+			"name":         "Cheesy Arena " + version,
+			"body":         "* Fixed <everything>",
+			"published_at": "2024-04-01T12:00:00Z",
+			"html_url":     server.URL + "/releases/" + version,
+			"assets": []map[string]string{
+				{"name": assetName, "browser_download_url": server.URL + "/download/" + assetName},
+			},
+		}
+		json.NewEncoder(w).Encode(release)
+	})
+	handler.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bundle)
+	})
+	return server
+}
+
+// Returns a release bundle holding the server binary and the given files, keyed by their paths within the bundle.
+func BuildTestBundle(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	allFiles := map[string]string{binaryName(): "new binary"}
+	for name, contents := range files {
+		allFiles[name] = contents
+	}
+	for name, contents := range allFiles {
+		fileWriter, err := writer.Create(bundleDir + "/" + name)
+		assert.Nil(t, err)
+		_, err = fileWriter.Write([]byte(contents))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	return buffer.Bytes()
+}
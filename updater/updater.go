@@ -0,0 +1,425 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Checks a release endpoint for newer builds of the server, and downloads and unpacks them over the running
+// installation so that an update can be applied with a quick restart between matches rather than a reinstall.
+
+package updater
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Release endpoint that is checked by default, in the format of the GitHub API's latest release response.
+const DefaultReleaseUrl = "https://api.github.com/repos/frc9611/cyberarena/releases/latest"
+
+// Directory, relative to the base directory, in which downloaded updates are kept until they are applied.
+const StagingDir = "updates"
+
+// Name of the directory at the top level of each release bundle that holds its files.
+const bundleDir = "cheesy-arena"
+
+// Largest release bundle that will be downloaded.
+const maxBundleBytes = 200 << 20
+
+// Version of the running build, which is set when the release bundles are built and left as is for builds from source.
+var Version = "development"
+
+// A published build of the server.
+type Release struct {
+	Version     string
+	Name        string
+	Changelog   string
+	PublishedAt time.Time
+	PageUrl     string
+	AssetName   string // Name of the bundle for this platform, or blank if the release doesn't include one.
+	assetUrl    string
+}
+
+// Snapshot of what the updater knows about the available releases and what it has done with them.
+type Status struct {
+	CurrentVersion  string
+	LastCheckTime   time.Time
+	CheckError      string
+	LatestRelease   *Release
+	UpdateAvailable bool
+	StagedRelease   *Release // Release that has been downloaded and is ready to be applied, if any.
+	ApplyScheduled  bool     // Whether the staged release is to be applied at the next break between matches.
+}
+
+type Updater struct {
+	releaseUrl string
+	stagingDir string
+	httpClient *http.Client
+	mutex      sync.Mutex
+	status     Status
+	stagedPath string
+}
+
+// Subset of the fields of a GitHub release that are used.
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	PublishedAt time.Time `json:"published_at"`
+	HtmlUrl     string    `json:"html_url"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		BrowserDownloadUrl string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Creates an updater that checks the given endpoint for releases newer than the given version and keeps downloaded
+// updates in the given directory. Checking is disabled if the endpoint is blank.
+func NewUpdater(releaseUrl, currentVersion, stagingDir string) *Updater {
+	return &Updater{
+		releaseUrl: releaseUrl,
+		stagingDir: stagingDir,
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		status:     Status{CurrentVersion: currentVersion},
+	}
+}
+
+// Returns true if the updater has a release endpoint to check.
+func (updater *Updater) IsEnabled() bool {
+	return updater.releaseUrl != ""
+}
+
+// Returns a snapshot of the updater's current status.
+func (updater *Updater) Status() Status {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	return updater.status
+}
+
+// Fetches the latest release from the release endpoint and records whether it is newer than the running build.
+func (updater *Updater) Check() error {
+	if !updater.IsEnabled() {
+		return fmt.Errorf("Checking for updates is disabled.")
+	}
+	release, err := updater.getLatestRelease()
+
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	updater.status.LastCheckTime = time.Now()
+	if err != nil {
+		updater.status.CheckError = err.Error()
+		return err
+	}
+	updater.status.CheckError = ""
+	updater.status.LatestRelease = release
+	updater.status.UpdateAvailable = IsNewerVersion(release.Version, updater.status.CurrentVersion)
+	return nil
+}
+
+// Downloads the bundle of the latest release for this platform and checks that it is intact, so that it is ready to be
+// applied.
+func (updater *Updater) Stage() error {
+	status := updater.Status()
+	release := status.LatestRelease
+	if release == nil || !status.UpdateAvailable {
+		return fmt.Errorf("There is no newer release to download.")
+	}
+	if release.assetUrl == "" {
+		return fmt.Errorf(
+			"Release %s doesn't include a bundle for %s/%s.", release.Version, runtime.GOOS, runtime.GOARCH,
+		)
+	}
+	if status.StagedRelease != nil && status.StagedRelease.Version == release.Version {
+		return nil
+	}
+
+	if err := os.MkdirAll(updater.stagingDir, 0755); err != nil {
+		return err
+	}
+	stagedPath := filepath.Join(updater.stagingDir, filepath.Base(release.AssetName))
+	if err := updater.download(release.assetUrl, stagedPath); err != nil {
+		return err
+	}
+	if err := checkBundle(stagedPath); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("Downloaded bundle for release %s is not usable: %v", release.Version, err)
+	}
+
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	if updater.stagedPath != "" && updater.stagedPath != stagedPath {
+		os.Remove(updater.stagedPath)
+	}
+	updater.stagedPath = stagedPath
+	updater.status.StagedRelease = release
+	updater.status.ApplyScheduled = false
+	return nil
+}
+
+// Sets whether the staged release is to be applied at the next break between matches.
+func (updater *Updater) ScheduleApply(scheduled bool) error {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	if scheduled && updater.status.StagedRelease == nil {
+		return fmt.Errorf("No update has been downloaded yet.")
+	}
+	updater.status.ApplyScheduled = scheduled
+	return nil
+}
+
+// Returns true if there is a staged release waiting to be applied at the next break between matches.
+func (updater *Updater) ApplyScheduled() bool {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	return updater.status.ApplyScheduled
+}
+
+// Unpacks the staged release over the installation in the given directory. The server must be restarted afterwards to
+// run the new build. Files that are in use, such as the running binary, are moved aside rather than overwritten.
+func (updater *Updater) Apply(installDir string) error {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+	updater.status.ApplyScheduled = false
+	if updater.status.StagedRelease == nil {
+		return fmt.Errorf("No update has been downloaded yet.")
+	}
+
+	reader, err := zip.OpenReader(updater.stagedPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		relativePath, ok := bundleRelativePath(file.Name)
+		if !ok || file.FileInfo().IsDir() {
+			continue
+		}
+		if err = installFile(file, filepath.Join(installDir, filepath.FromSlash(relativePath))); err != nil {
+			return fmt.Errorf("Failed to install %s: %v", relativePath, err)
+		}
+	}
+
+	os.Remove(updater.stagedPath)
+	updater.stagedPath = ""
+	updater.status.CurrentVersion = updater.status.StagedRelease.Version
+	updater.status.StagedRelease = nil
+	updater.status.UpdateAvailable = false
+	return nil
+}
+
+// Returns true if the first version is a later release than the second. Versions are of the form v1.2.3, and a
+// development build that doesn't have a version of that form is never reported as out of date.
+func IsNewerVersion(version, currentVersion string) bool {
+	parts, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	currentParts, ok := parseVersion(currentVersion)
+	if !ok {
+		return false
+	}
+	for i := range parts {
+		if parts[i] != currentParts[i] {
+			return parts[i] > currentParts[i]
+		}
+	}
+	return false
+}
+
+func (updater *Updater) getLatestRelease() (*Release, error) {
+	req, err := http.NewRequest("GET", updater.releaseUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := updater.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Error getting latest release: %d, %s", resp.StatusCode, string(body))
+	}
+
+	var githubRelease githubRelease
+	if err = json.Unmarshal(body, &githubRelease); err != nil {
+		return nil, err
+	}
+	if githubRelease.TagName == "" {
+		return nil, fmt.Errorf("Latest release doesn't have a version.")
+	}
+	release := Release{
+		Version:     githubRelease.TagName,
+		Name:        githubRelease.Name,
+		Changelog:   githubRelease.Body,
+		PublishedAt: githubRelease.PublishedAt,
+		PageUrl:     githubRelease.HtmlUrl,
+	}
+	assetSuffix := platformAssetSuffix()
+	for _, asset := range githubRelease.Assets {
+		if assetSuffix != "" && strings.HasSuffix(asset.Name, assetSuffix) {
+			release.AssetName = asset.Name
+			release.assetUrl = asset.BrowserDownloadUrl
+			break
+		}
+	}
+	return &release, nil
+}
+
+// Downloads the given URL to the given path, writing to a temporary file first so that an interrupted download
+// doesn't leave a partial bundle behind.
+func (updater *Updater) download(url, destPath string) error {
+	resp, err := updater.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Error downloading %s: %d", url, resp.StatusCode)
+	}
+
+	partPath := destPath + ".part"
+	file, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, io.LimitReader(resp.Body, maxBundleBytes+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > maxBundleBytes {
+		err = fmt.Errorf("Bundle is larger than %d MB.", maxBundleBytes>>20)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return err
+	}
+	return os.Rename(partPath, destPath)
+}
+
+// Checks that the given bundle is a readable zip file containing a server binary.
+func checkBundle(bundlePath string) error {
+	reader, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	hasBinary := false
+	for _, file := range reader.File {
+		relativePath, ok := bundleRelativePath(file.Name)
+		if !ok {
+			return fmt.Errorf("unexpected file %s", file.Name)
+		}
+		if relativePath == binaryName() {
+			hasBinary = true
+		}
+	}
+	if !hasBinary {
+		return fmt.Errorf("missing %s", binaryName())
+	}
+	return nil
+}
+
+// Writes the given file from the bundle to the given path.
+func installFile(file *zip.File, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+	source, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	newPath := destPath + ".new"
+	dest, err := os.OpenFile(newPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, file.Mode().Perm()|0200)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dest, source)
+	if closeErr := dest.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(newPath)
+		return err
+	}
+
+	// Windows doesn't allow a running binary to be overwritten but does allow it to be renamed, so move the existing
+	// file aside first and clean it up on a best-effort basis.
+	oldPath := destPath + ".old"
+	os.Remove(oldPath)
+	if _, err = os.Stat(destPath); err == nil {
+		if err = os.Rename(destPath, oldPath); err != nil {
+			os.Remove(newPath)
+			return err
+		}
+	}
+	if err = os.Rename(newPath, destPath); err != nil {
+		return err
+	}
+	os.Remove(oldPath)
+	return nil
+}
+
+// Returns the path of the given bundle entry relative to the bundle's top-level directory, and false if it lies
+// outside of that directory.
+func bundleRelativePath(name string) (string, bool) {
+	cleanName := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	relativePath, found := strings.CutPrefix(cleanName, bundleDir+"/")
+	if !found || relativePath == "" || strings.HasPrefix(relativePath, "../") {
+		return "", false
+	}
+	return relativePath, true
+}
+
+// Returns the ending of the name of the release bundle for the platform that the server is running on, matching the
+// names given to the bundles by the release workflow, or blank if no bundle is built for it.
+func platformAssetSuffix() string {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return ".linux.x64.zip"
+	case "darwin/amd64":
+		return ".macos.x64.zip"
+	case "darwin/arm64":
+		return ".macos.m1.zip"
+	case "windows/amd64":
+		return ".windows.x64.zip"
+	}
+	return ""
+}
+
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "cheesy-arena.exe"
+	}
+	return "cheesy-arena"
+}
+
+// Parses a version of the form v1.2.3 into its numeric parts.
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	fields := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 {
+			return parts, false
+		}
+		parts[i] = value
+	}
+	return parts, true
+}
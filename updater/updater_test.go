@@ -0,0 +1,138 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package updater
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	assert.True(t, IsNewerVersion("v2024.1.1", "v2024.1.0"))
+	assert.True(t, IsNewerVersion("v2024.2.0", "v2024.1.9"))
+	assert.True(t, IsNewerVersion("v2024.10.0", "v2024.9.0"))
+	assert.False(t, IsNewerVersion("v2024.1.0", "v2024.1.0"))
+	assert.False(t, IsNewerVersion("v2023.9.9", "v2024.1.0"))
+	assert.False(t, IsNewerVersion("v2024.1.1", "development"))
+	assert.False(t, IsNewerVersion("nightly", "v2024.1.0"))
+	assert.False(t, IsNewerVersion("v2024.1", "v2023.1.0"))
+}
+
+func TestCheckAndStage(t *testing.T) {
+	server := NewTestReleaseServer(t, "v2024.2.0", nil)
+	updater := NewUpdater(server.URL+"/releases/latest", "v2024.1.0", t.TempDir())
+	assert.True(t, updater.IsEnabled())
+	assert.NotNil(t, updater.Stage())
+
+	assert.Nil(t, updater.Check())
+	status := updater.Status()
+	assert.False(t, status.LastCheckTime.IsZero())
+	assert.Equal(t, "", status.CheckError)
+	assert.True(t, status.UpdateAvailable)
+	if assert.NotNil(t, status.LatestRelease) {
+		assert.Equal(t, "v2024.2.0", status.LatestRelease.Version)
+		assert.Equal(t, "* Fixed <everything>", status.LatestRelease.Changelog)
+		assert.Equal(t, "cheesy-arena.v2024.2.0"+platformAssetSuffix(), status.LatestRelease.AssetName)
+	}
+	assert.Nil(t, status.StagedRelease)
+	assert.NotNil(t, updater.ScheduleApply(true))
+
+	assert.Nil(t, updater.Stage())
+	status = updater.Status()
+	if assert.NotNil(t, status.StagedRelease) {
+		assert.Equal(t, "v2024.2.0", status.StagedRelease.Version)
+	}
+	assert.FileExists(t, updater.stagedPath)
+	assert.False(t, updater.ApplyScheduled())
+	assert.Nil(t, updater.ScheduleApply(true))
+	assert.True(t, updater.ApplyScheduled())
+	assert.Nil(t, updater.ScheduleApply(false))
+	assert.False(t, updater.ApplyScheduled())
+
+	// Check that an up-to-date installation isn't offered the same release.
+	updater = NewUpdater(server.URL+"/releases/latest", "v2024.2.0", t.TempDir())
+	assert.Nil(t, updater.Check())
+	assert.False(t, updater.Status().UpdateAvailable)
+	err := updater.Stage()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "There is no newer release to download.", err.Error())
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	updater := NewUpdater("", "v2024.1.0", t.TempDir())
+	assert.False(t, updater.IsEnabled())
+	assert.NotNil(t, updater.Check())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", 403)
+	}))
+	defer server.Close()
+	updater = NewUpdater(server.URL, "v2024.1.0", t.TempDir())
+	err := updater.Check()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Error getting latest release: 403")
+	}
+	status := updater.Status()
+	assert.Equal(t, err.Error(), status.CheckError)
+	assert.False(t, status.LastCheckTime.IsZero())
+	assert.Nil(t, status.LatestRelease)
+}
+
+func TestStageInvalidBundle(t *testing.T) {
+	server := NewTestReleaseServer(t, "v2024.2.0", map[string]string{"../../escape.txt": "gotcha"})
+	stagingDir := t.TempDir()
+	updater := NewUpdater(server.URL+"/releases/latest", "v2024.1.0", stagingDir)
+	assert.Nil(t, updater.Check())
+	err := updater.Stage()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Downloaded bundle for release v2024.2.0 is not usable")
+	}
+	assert.Nil(t, updater.Status().StagedRelease)
+	entries, _ := os.ReadDir(stagingDir)
+	assert.Empty(t, entries)
+}
+
+func TestApply(t *testing.T) {
+	server := NewTestReleaseServer(
+		t, "v2024.2.0", map[string]string{"templates/base.html": "new template", "static/js/new.js": "new script"},
+	)
+	installDir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(installDir, "templates"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(installDir, "templates", "base.html"), []byte("old template"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(installDir, binaryName()), []byte("old binary"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(installDir, "event.db"), []byte("event data"), 0644))
+
+	updater := NewUpdater(server.URL+"/releases/latest", "v2024.1.0", t.TempDir())
+	assert.NotNil(t, updater.Apply(installDir))
+	assert.Nil(t, updater.Check())
+	assert.Nil(t, updater.Stage())
+	assert.Nil(t, updater.ScheduleApply(true))
+	stagedPath := updater.stagedPath
+
+	assert.Nil(t, updater.Apply(installDir))
+	assertFileContents(t, "new binary", filepath.Join(installDir, binaryName()))
+	assertFileContents(t, "new template", filepath.Join(installDir, "templates", "base.html"))
+	assertFileContents(t, "new script", filepath.Join(installDir, "static", "js", "new.js"))
+	assertFileContents(t, "event data", filepath.Join(installDir, "event.db"))
+	assert.NoFileExists(t, filepath.Join(installDir, binaryName()+".old"))
+	assert.NoFileExists(t, stagedPath)
+
+	status := updater.Status()
+	assert.Equal(t, "v2024.2.0", status.CurrentVersion)
+	assert.False(t, status.UpdateAvailable)
+	assert.Nil(t, status.StagedRelease)
+	assert.False(t, status.ApplyScheduled)
+}
+
+func assertFileContents(t *testing.T, expectedContents string, path string) {
+	contents, err := os.ReadFile(path)
+	if assert.Nil(t, err) {
+		assert.Equal(t, expectedContents, string(contents))
+	}
+}
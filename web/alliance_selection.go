@@ -7,11 +7,12 @@ package web
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/Team254/cheesy-arena/websocket"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -25,7 +26,7 @@ var allianceSelectionTicker *time.Ticker
 
 // Shows the alliance selection page.
 func (web *Web) allianceSelectionGetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -34,7 +35,7 @@ func (web *Web) allianceSelectionGetHandler(w http.ResponseWriter, r *http.Reque
 
 // Updates the cache with the latest input from the client.
 func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -116,7 +117,7 @@ func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Requ
 
 // Sets up the empty alliances and populates the ranked team list.
 func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -161,7 +162,7 @@ func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Req
 
 // Resets the alliance selection process back to the starting point.
 func (web *Web) allianceSelectionResetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -191,7 +192,7 @@ func (web *Web) allianceSelectionResetHandler(w http.ResponseWriter, r *http.Req
 
 // Saves the selected alliances to the database and generates the first round of playoff matches.
 func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -200,8 +201,9 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	location, _ := time.LoadLocation("Local")
-	startTime, err := time.ParseInLocation("2006-01-02 03:04:05 PM", r.PostFormValue("startTime"), location)
+	startTime, err := time.ParseInLocation(
+		"2006-01-02 03:04:05 PM", r.PostFormValue("startTime"), web.arena.EventSettings.Location(),
+	)
 	if err != nil {
 		web.renderAllianceSelection(w, r, "Must specify a valid start time for the playoff rounds.")
 		return
@@ -237,6 +239,7 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 		handleWebErr(w, err)
 		return
 	}
+	web.arena.NotifyScheduleChanged(model.Playoff)
 
 	// Reset yellow cards.
 	err = tournament.CalculateTeamCards(web.arena.Database, model.Playoff)
@@ -253,17 +256,8 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 	}
 
 	if web.arena.EventSettings.TbaPublishingEnabled {
-		// Publish alliances and schedule to The Blue Alliance.
-		err = web.arena.TbaClient.PublishAlliances(web.arena.Database)
-		if err != nil {
-			web.renderAllianceSelection(w, r, fmt.Sprintf("Failed to publish alliances: %s", err.Error()))
-			return
-		}
-		err = web.arena.TbaClient.PublishMatches(web.arena.Database)
-		if err != nil {
-			web.renderAllianceSelection(w, r, fmt.Sprintf("Failed to publish matches: %s", err.Error()))
-			return
-		}
+		// Publish alliances and schedule to The Blue Alliance in the background.
+		web.arena.TbaPublisher.Enqueue(partner.TbaPublishAlliances, partner.TbaPublishMatches)
 	}
 
 	// Signal displays of the bracket to update themselves.
@@ -280,7 +274,7 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 
 // The websocket endpoint for the alliance selection client to send control commands and receive status updates.
 func (web *Web) allianceSelectionWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -302,7 +296,7 @@ func (web *Web) allianceSelectionWebsocketHandler(w http.ResponseWriter, r *http
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
@@ -6,11 +6,13 @@ package web
 import (
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestAllianceSelection(t *testing.T) {
@@ -77,7 +79,8 @@ func TestAllianceSelection(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), ">110<")
 
 	// Finalize alliance selection.
-	web.arena.Database.CreateTeam(&model.Team{Id: 254, YellowCard: true})
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	web.arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 254, YellowCard: true})
 	recorder = web.postHttpResponse("/alliance_selection/finalize", "startTime=2014-01-01 01:00:00 PM")
 	assert.Equal(t, 303, recorder.Code)
 	alliances, err := web.arena.Database.GetAllAlliances()
@@ -95,8 +98,8 @@ func TestAllianceSelection(t *testing.T) {
 	matches, err := web.arena.Database.GetMatchesByType(model.Playoff, false)
 	assert.Nil(t, err)
 	assert.Equal(t, 16, len(matches))
-	team, _ := web.arena.Database.GetTeamById(254)
-	assert.False(t, team.YellowCard)
+	teamStatus, _ := web.arena.Database.GetTeamStatus(254)
+	assert.False(t, teamStatus.YellowCard)
 }
 
 func TestAllianceSelectionErrors(t *testing.T) {
@@ -142,8 +145,12 @@ func TestAllianceSelectionErrors(t *testing.T) {
 	web.arena.TbaClient.BaseUrl = "fakeurl"
 	web.arena.EventSettings.TbaPublishingEnabled = true
 	recorder = web.postHttpResponse("/alliance_selection/finalize", "startTime=2014-01-01 01:00:00 PM")
-	assert.Equal(t, 200, recorder.Code)
-	assert.Contains(t, recorder.Body.String(), "Failed to publish alliances")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Eventually(t, func() bool {
+		statuses := web.arena.TbaPublisher.Statuses()
+		return statuses[1].FailedAttempts > 0 && statuses[3].FailedAttempts > 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, partner.TbaPublishAlliances, web.arena.TbaPublisher.Statuses()[3].Kind)
 
 	// Do other things after finalization.
 	recorder = web.postHttpResponse("/alliance_selection/finalize", "startTime=2014-01-01 01:00:00 PM")
@@ -79,8 +79,8 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 	}
 	defer ws.Close()
 
-	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
-	ws.HandleNotifiers(
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(
 		display.Notifier,
 		web.arena.MatchTimingNotifier,
 		web.arena.AudienceDisplayModeNotifier,
@@ -91,4 +91,7 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 		web.arena.ScorePostedNotifier,
 		web.arena.ReloadDisplaysNotifier,
 	)
+
+	// Keep reading from the websocket so that the client's clock synchronization requests get answered.
+	ws.HandleReads()
 }
@@ -4,6 +4,7 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
@@ -31,6 +32,21 @@ func TestAnnouncerDisplayMatchLoad(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "2056")
 }
 
+func TestAnnouncerDisplayMatchLoadNotes(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, AnnouncerNotes: "Their robot is named after a dessert"})
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 254, Rank: 3, RankingFields: game.RankingFields{Wins: 4, Losses: 1, Ties: 2}},
+	)
+	match := model.Match{Type: model.Qualification, Red1: 254}
+	web.arena.LoadMatch(&match)
+
+	recorder := web.getHttpResponse("/displays/announcer/match_load")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Their robot is named after a dessert")
+	assert.Contains(t, recorder.Body.String(), "3<br />4-1-2")
+}
+
 func TestAnnouncerDisplayScorePosted(t *testing.T) {
 	web := setupTestWeb(t)
 	match := model.Match{Type: model.Qualification, LongName: "Qual 17"}
@@ -8,15 +8,19 @@ package web
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/partner"
 	"github.com/Team254/cheesy-arena/playoff"
 	"github.com/Team254/cheesy-arena/websocket"
+	"github.com/mitchellh/mapstructure"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
 type MatchResultWithSummary struct {
@@ -92,9 +96,9 @@ func (web *Web) matchesApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Generates a JSON dump of the sponsor slides for use by the audience display.
+// Generates a JSON dump of the sponsor slides currently in rotation, for use by the audience and pit displays.
 func (web *Web) sponsorSlidesApiHandler(w http.ResponseWriter, r *http.Request) {
-	sponsors, err := web.arena.Database.GetAllSponsorSlides()
+	sponsors, err := web.arena.Database.GetActiveSponsorSlides(time.Now())
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -118,9 +122,41 @@ func (web *Web) sponsorSlidesApiHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// Generates a JSON dump of the projected start and queueing times of the upcoming matches, primarily for use by the
+// queueing display.
+func (web *Web) queueingApiHandler(w http.ResponseWriter, r *http.Request) {
+	projectedMatches, err := web.arena.GetProjectedMatches(time.Now())
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	data := struct {
+		LeadTimeMin int
+		Matches     []field.ProjectedMatch
+	}{web.arena.EventSettings.QueueLeadTimeMin, projectedMatches}
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Generates a JSON dump of the qualification rankings, primarily for use by the rankings display.
 func (web *Web) rankingsApiHandler(w http.ResponseWriter, r *http.Request) {
-	rankings, err := web.arena.Database.GetAllRankings()
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rankings, err := event.Database.GetAllRankings()
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -134,7 +170,7 @@ func (web *Web) rankingsApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get team info so that nicknames can be displayed.
-	teams, err := web.arena.Database.GetAllTeams()
+	teams, err := event.Database.GetAllTeams()
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -148,7 +184,7 @@ func (web *Web) rankingsApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get the last match scored so we can report that on the display.
-	matches, err := web.arena.Database.GetMatchesByType(model.Qualification, false)
+	matches, err := event.Database.GetMatchesByType(model.Qualification, false)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -178,6 +214,53 @@ func (web *Web) rankingsApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Generates a JSON dump of the league standings, primarily for use by the league display.
+func (web *Web) leagueStandingsApiHandler(w http.ResponseWriter, r *http.Request) {
+	leagueStandings, err := web.getLeagueStandings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(leagueStandings, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the calculated OPR, DPR, CCWM and component OPRs of each team.
+func (web *Web) teamStatsApiHandler(w http.ResponseWriter, r *http.Request) {
+	teamStats, err := web.arena.Database.GetAllTeamStats()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if teamStats == nil {
+		// Go marshals an empty slice to null, so explicitly create it so that it appears as an empty JSON array.
+		teamStats = make([]model.TeamStat, 0)
+	}
+	jsonData, err := json.MarshalIndent(teamStats, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
 // Generates a JSON dump of the alliances.
 func (web *Web) alliancesApiHandler(w http.ResponseWriter, r *http.Request) {
 	alliances, err := web.arena.Database.GetAllAlliances()
@@ -213,6 +296,123 @@ func (web *Web) arenaWebsocketApiHandler(w http.ResponseWriter, r *http.Request)
 	ws.HandleNotifiers(web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier)
 }
 
+// Websocket API for driving serial or otherwise externally controlled team number signs.
+func (web *Web) teamSignsWebsocketApiHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
+	ws.HandleNotifiers(web.arena.TeamSignsNotifier)
+}
+
+// Generates a JSON dump of the full in-progress score of the current match.
+func (web *Web) liveScoreApiHandler(w http.ResponseWriter, r *http.Request) {
+	jsonData, err := json.MarshalIndent(web.arena.GenerateLiveScoreMessage(), "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Websocket API for receiving updates to the full in-progress score of the current match. Any messages sent by the
+// client are ignored.
+func (web *Web) liveScoreWebsocketApiHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
+	ws.HandleNotifiers(web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier, web.arena.LiveScoreNotifier)
+}
+
+// Returns the driver station, radio and robot connection diagnostics of each alliance station, for use by FTA tools.
+func (web *Web) fieldMonitorApiHandler(w http.ResponseWriter, r *http.Request) {
+	jsonData, err := json.MarshalIndent(web.arena.GenerateFieldMonitorStations(), "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON snapshot of the authoritative match clock.
+func (web *Web) matchClockApiHandler(w http.ResponseWriter, r *http.Request) {
+	jsonData, err := json.MarshalIndent(web.arena.GenerateMatchClockMessage(), "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Websocket API for receiving match clock snapshots at every second boundary and state change. Clients may send
+// "sync" messages carrying their local time to estimate their clock offset from the server.
+func (web *Web) matchClockWebsocketApiHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier, web.arena.MatchClockNotifier)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		receiveTime := time.Now()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Web.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "sync":
+			args := struct {
+				ClientTimeMs int64
+			}{}
+			if err = mapstructure.Decode(data, &args); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			ws.Write("syncResponse", field.GenerateMatchClockSyncMessage(args.ClientTimeMs, receiveTime))
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+		}
+	}
+}
+
 // Serves the avatar for a given team, or a default if none exists.
 func (web *Web) teamAvatarsApiHandler(w http.ResponseWriter, r *http.Request) {
 	teamId, err := strconv.Atoi(r.PathValue("teamId"))
@@ -230,8 +430,15 @@ func (web *Web) teamAvatarsApiHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (web *Web) bracketSvgApiHandler(w http.ResponseWriter, r *http.Request) {
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Only the active event has a match in progress to highlight.
 	var activeMatch *model.Match
-	if activeMatchValue, ok := r.URL.Query()["activeMatch"]; ok {
+	if activeMatchValue, ok := r.URL.Query()["activeMatch"]; ok && r.PathValue("event") == "" {
 		if activeMatchValue[0] == "current" {
 			activeMatch = web.arena.CurrentMatch
 		} else if activeMatchValue[0] == "saved" {
@@ -240,21 +447,43 @@ func (web *Web) bracketSvgApiHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "image/svg+xml")
-	if err := web.generateBracketSvg(w, activeMatch); err != nil {
+	if err = web.generateBracketSvg(w, event, activeMatch); err != nil {
 		handleWebErr(w, err)
 		return
 	}
 }
 
-func (web *Web) generateBracketSvg(w io.Writer, activeMatch *model.Match) error {
-	alliances, err := web.arena.Database.GetAllAlliances()
+// Generates a JSON dump of the completed playoff matches and the resulting alliance advancements, for use by the
+// audience bracket overlay and the bracket display.
+func (web *Web) bracketAdvancementsApiHandler(w http.ResponseWriter, r *http.Request) {
+	advancements := []playoff.MatchAdvancement{}
+	if web.arena.PlayoffTournament != nil {
+		advancements = web.arena.PlayoffTournament.MatchAdvancements()
+	}
+
+	jsonData, err := json.MarshalIndent(advancements, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+func (web *Web) generateBracketSvg(w io.Writer, event *reportEvent, activeMatch *model.Match) error {
+	alliances, err := event.Database.GetAllAlliances()
 	if err != nil {
 		return err
 	}
 
 	matchups := make(map[string]*allianceMatchup)
-	if web.arena.PlayoffTournament != nil {
-		for _, matchGroup := range web.arena.PlayoffTournament.MatchGroups() {
+	if event.PlayoffTournament != nil {
+		for _, matchGroup := range event.PlayoffTournament.MatchGroups() {
 			matchup, ok := matchGroup.(*playoff.Matchup)
 			if !ok {
 				continue
@@ -288,8 +517,8 @@ func (web *Web) generateBracketSvg(w io.Writer, activeMatch *model.Match) error
 	}
 
 	bracketType := "double"
-	numAlliances := web.arena.EventSettings.NumPlayoffAlliances
-	if web.arena.EventSettings.PlayoffType == model.SingleEliminationPlayoff {
+	numAlliances := event.EventSettings.NumPlayoffAlliances
+	if event.EventSettings.PlayoffType == model.SingleEliminationPlayoff {
 		if numAlliances > 8 {
 			bracketType = "16"
 		} else if numAlliances > 4 {
@@ -0,0 +1,44 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Authentication of the web APIs that external tools and integrations call using issued API tokens.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Returns the API token carried by the request, either as a bearer token or as the apiKey query parameter.
+func getRequestApiToken(r *http.Request) string {
+	if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("apiKey")
+}
+
+// Returns true if the request carries an API token having the given scope. Writes an error response and returns false
+// otherwise.
+func (web *Web) apiIsAuthorized(w http.ResponseWriter, r *http.Request, scope string) bool {
+	providedToken := getRequestApiToken(r)
+	if providedToken == "" {
+		http.Error(w, "Missing API token.", 401)
+		return false
+	}
+	apiToken, err := web.arena.Database.GetApiTokenByToken(providedToken)
+	if err != nil {
+		handleWebErr(w, err)
+		return false
+	}
+	if apiToken == nil {
+		http.Error(w, "Invalid API token.", 401)
+		return false
+	}
+	if !apiToken.HasScope(scope) {
+		http.Error(w, fmt.Sprintf("API token does not have the %s scope.", scope), 403)
+		return false
+	}
+	return true
+}
@@ -5,8 +5,10 @@ package web
 
 import (
 	"encoding/json"
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/playoff"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
@@ -44,6 +46,26 @@ func TestMatchesApi(t *testing.T) {
 	}
 }
 
+func TestTeamStatsApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/api/team_stats")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	assert.Equal(t, "[]", recorder.Body.String())
+
+	teamStats := []model.TeamStat{{TeamId: 1114, Opr: 12.5}, {TeamId: 254, Opr: 40.25, Ccwm: 20}}
+	assert.Nil(t, web.arena.Database.ReplaceAllTeamStats(teamStats))
+	recorder = web.getHttpResponse("/api/team_stats")
+	assert.Equal(t, 200, recorder.Code)
+	var teamStatsData []model.TeamStat
+	assert.Nil(t, json.Unmarshal([]byte(recorder.Body.String()), &teamStatsData))
+	if assert.Equal(t, 2, len(teamStatsData)) {
+		assert.Equal(t, teamStats[1], teamStatsData[0])
+		assert.Equal(t, teamStats[0], teamStatsData[1])
+	}
+}
+
 func TestRankingsApi(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -82,13 +104,45 @@ func TestRankingsApi(t *testing.T) {
 	assert.Equal(t, "Q29", rankingsData.HighestPlayedMatch)
 }
 
+func TestQueueingApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Time: time.Now().Add(8 * time.Minute),
+		Red1: 254}
+	web.arena.Database.CreateMatch(&match)
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2",
+		Time: time.Now().Add(time.Hour), Blue2: 1114})
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	recorder := web.getHttpResponse("/api/queueing")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var queueingData struct {
+		LeadTimeMin int
+		Matches     []field.ProjectedMatch
+	}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &queueingData))
+	assert.Equal(t, 10, queueingData.LeadTimeMin)
+	if assert.Equal(t, 2, len(queueingData.Matches)) {
+		assert.Equal(t, []field.QueueingTeam{{254, "R1", field.QueueDue}}, queueingData.Matches[0].Teams)
+		assert.Equal(t, []field.QueueingTeam{{1114, "B2", field.QueueNotYetDue}}, queueingData.Matches[1].Teams)
+	}
+}
+
 func TestSponsorSlidesApi(t *testing.T) {
 	web := setupTestWeb(t)
 
-	slide1 := model.SponsorSlide{0, "subtitle", "line1", "line2", "image", 2, 1}
-	slide2 := model.SponsorSlide{0, "Chezy Sponsaur", "Teh", "Chezy Pofs", "ejface.jpg", 54, 2}
+	slide1 := model.SponsorSlide{0, "subtitle", "line1", "line2", "image", 2, 1, time.Time{}, time.Time{}, false}
+	slide2 := model.SponsorSlide{0, "Chezy Sponsaur", "Teh", "Chezy Pofs", "ejface.jpg", 54, 2, time.Time{}, time.Time{},
+		false}
 	assert.Nil(t, web.arena.Database.CreateSponsorSlide(&slide1))
 	assert.Nil(t, web.arena.Database.CreateSponsorSlide(&slide2))
+	assert.Nil(
+		t,
+		web.arena.Database.CreateSponsorSlide(
+			&model.SponsorSlide{Image: "expired.png", ActiveEndTime: time.Now().Add(-time.Minute)},
+		),
+	)
 
 	recorder := web.getHttpResponse("/api/sponsor_slides")
 	assert.Equal(t, 200, recorder.Code)
@@ -145,6 +199,159 @@ func TestArenaWebsocketApi(t *testing.T) {
 	readWebsocketType(t, ws, "matchTime")
 }
 
+func TestLiveScoreApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.CurrentMatch.Red1 = 254
+	web.arena.CurrentMatch.Blue3 = 1114
+	web.arena.RedRealtimeScore.CurrentScore.LeaveStatuses = [3]bool{true, false, true}
+	web.arena.RedRealtimeScore.CurrentScore.AmpSpeaker.AutoSpeakerNotes = 2
+	web.arena.BlueRealtimeScore.CurrentScore.Fouls = []game.Foul{{TeamId: 254, RuleId: 1}}
+	web.arena.BlueRealtimeScore.Cards["1114"] = "yellow"
+
+	recorder := web.getHttpResponse("/api/live_score")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var liveScore field.LiveScoreMessage
+	err := json.Unmarshal([]byte(recorder.Body.String()), &liveScore)
+	assert.Nil(t, err)
+	assert.Equal(t, field.PreMatch, liveScore.MatchState)
+	assert.Equal(t, [3]int{254, 0, 0}, liveScore.Red.TeamIds)
+	assert.Equal(t, [3]int{0, 0, 1114}, liveScore.Blue.TeamIds)
+	assert.Equal(t, [3]bool{true, false, true}, liveScore.Red.Score.LeaveStatuses)
+	assert.Equal(t, 2, liveScore.Red.Score.AmpSpeaker.AutoSpeakerNotes)
+	assert.Equal(t, 4, liveScore.Red.ScoreSummary.LeavePoints)
+	assert.Equal(t, 10, liveScore.Red.ScoreSummary.SpeakerPoints)
+	assert.Equal(t, 1, len(liveScore.Blue.Score.Fouls))
+	assert.Equal(t, "yellow", liveScore.Blue.Cards["1114"])
+	if assert.Contains(t, liveScore.RulesViolated, 1) {
+		assert.Equal(t, game.GetRuleById(1).RuleNumber, liveScore.RulesViolated[1].RuleNumber)
+	}
+}
+
+func TestLiveScoreWebsocketApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/api/live_score/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "matchTime")
+	readWebsocketType(t, ws, "liveScore")
+
+	// Commands sent by the client should be ignored.
+	ws.Write("leave", map[string]any{"TeamPosition": 1})
+	web.arena.RedRealtimeScore.CurrentScore.TrapStatuses[1] = true
+	web.arena.LiveScoreNotifier.Notify()
+	message := readWebsocketType(t, ws, "liveScore")
+	liveScore, ok := message.(map[string]any)
+	if assert.True(t, ok) {
+		red := liveScore["Red"].(map[string]any)
+		score := red["Score"].(map[string]any)
+		assert.Equal(t, []any{false, false, false}, score["LeaveStatuses"])
+		assert.Equal(t, []any{false, true, false}, score["TrapStatuses"])
+	}
+}
+
+func TestMatchClockApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/api/match_clock")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var matchClock field.MatchClockMessage
+	assert.Nil(t, json.Unmarshal([]byte(recorder.Body.String()), &matchClock))
+	assert.Equal(t, field.PreMatch, matchClock.MatchState)
+	assert.False(t, matchClock.Running)
+	assert.Equal(t, int64(0), matchClock.MatchStartTimeMs)
+	assert.InDelta(t, time.Now().UnixMilli(), matchClock.ServerTimeMs, 1000)
+
+	web.arena.MatchState = field.AutoPeriod
+	web.arena.MatchStartTime = time.Now().Add(-5 * time.Second)
+	recorder = web.getHttpResponse("/api/match_clock")
+	assert.Nil(t, json.Unmarshal([]byte(recorder.Body.String()), &matchClock))
+	assert.True(t, matchClock.Running)
+	assert.Equal(t, web.arena.MatchStartTime.UnixMilli(), matchClock.MatchStartTimeMs)
+	assert.InDelta(t, 5000, matchClock.MatchTimeMs, 1000)
+}
+
+func TestFieldMonitorApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.AllianceStations["R2"].Team = &model.Team{Id: 254}
+	web.arena.AllianceStations["R2"].Ethernet = true
+	web.arena.AllianceStations["R2"].DsConn = &field.DriverStationConnection{
+		TeamId:            254,
+		DsLinked:          true,
+		RadioLinked:       true,
+		RobotLinked:       true,
+		BatteryVoltage:    12.5,
+		DsRobotTripTimeMs: 7,
+		MissedPacketCount: 3,
+	}
+	web.arena.AllianceStations["R2"].WifiStatus.TeamId = 254
+	web.arena.AllianceStations["R2"].WifiStatus.MBits = 2.25
+
+	recorder := web.getHttpResponse("/api/field_monitor")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var stations []field.FieldMonitorStation
+	assert.Nil(t, json.Unmarshal([]byte(recorder.Body.String()), &stations))
+	if assert.Equal(t, 6, len(stations)) {
+		assert.Equal(t, "R1", stations[0].Station)
+		assert.Equal(t, 0, stations[0].TeamId)
+		assert.False(t, stations[0].DsLinked)
+		assert.Equal(t, "B3", stations[5].Station)
+
+		assert.Equal(t, "R2", stations[1].Station)
+		assert.Equal(t, 254, stations[1].TeamId)
+		assert.True(t, stations[1].Ethernet)
+		assert.True(t, stations[1].DsLinked)
+		assert.True(t, stations[1].RadioLinked)
+		assert.True(t, stations[1].RobotLinked)
+		assert.Equal(t, 12.5, stations[1].BatteryVoltage)
+		assert.Equal(t, 7, stations[1].DsRobotTripTimeMs)
+		assert.Equal(t, 3, stations[1].MissedPacketCount)
+		assert.Equal(t, 254, stations[1].WifiTeamId)
+		assert.Equal(t, 2.25, stations[1].BandwidthMbps)
+	}
+}
+
+func TestMatchClockWebsocketApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/api/match_clock/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "matchTiming")
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "matchClock")
+
+	// The server should reply to a sync request with its receive and send timestamps.
+	clientTimeMs := time.Now().UnixMilli()
+	ws.Write("sync", map[string]any{"ClientTimeMs": clientTimeMs})
+	message := readWebsocketType(t, ws, "syncResponse")
+	syncResponse, ok := message.(map[string]any)
+	if assert.True(t, ok) {
+		assert.Equal(t, float64(clientTimeMs), syncResponse["ClientTimeMs"])
+		assert.GreaterOrEqual(t, syncResponse["ServerReceiveTimeMs"], float64(clientTimeMs))
+		assert.GreaterOrEqual(t, syncResponse["ServerSendTimeMs"], syncResponse["ServerReceiveTimeMs"])
+	}
+
+	ws.Write("bogus", nil)
+	readWebsocketType(t, ws, "error")
+}
+
 func TestBracketSvgApiDoubleElimination(t *testing.T) {
 	web := setupTestWeb(t)
 	web.arena.EventSettings.PlayoffType = model.DoubleEliminationPlayoff
@@ -156,3 +363,30 @@ func TestBracketSvgApiDoubleElimination(t *testing.T) {
 	assert.Equal(t, "image/svg+xml", recorder.Header()["Content-Type"][0])
 	assert.Contains(t, recorder.Body.String(), "Best-of-3")
 }
+
+func TestBracketAdvancementsApi(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.PlayoffType = model.SingleEliminationPlayoff
+	web.arena.EventSettings.NumPlayoffAlliances = 2
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	web.arena.CreatePlayoffTournament()
+	web.arena.CreatePlayoffMatches(time.Unix(0, 0))
+
+	match, _ := web.arena.Database.GetMatchByTypeOrder(model.Playoff, 43)
+	match.Status = game.RedWonMatch
+	match.WinReason = game.WinReasonForfeit
+	web.arena.Database.UpdateMatch(match)
+	assert.Nil(t, web.arena.UpdatePlayoffTournament())
+
+	recorder := web.getHttpResponse("/api/bracket/advancements")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var advancements []playoff.MatchAdvancement
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &advancements))
+	if assert.Equal(t, 1, len(advancements)) {
+		assert.Equal(t, "F", advancements[0].MatchGroupId)
+		assert.Equal(t, 1, advancements[0].WinningAllianceId)
+		assert.Equal(t, game.WinReasonForfeit, advancements[0].WinReason)
+		assert.False(t, advancements[0].MatchupDecided)
+	}
+}
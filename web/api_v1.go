@@ -0,0 +1,663 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Versioned REST API through which external tools access the event data. The response types here are deliberately
+// decoupled from the internal models so that they stay stable from one season to the next; any breaking change must go
+// into a new version rather than altering v1.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiV1Version = "v1"
+
+// Describes an endpoint in the API index.
+type apiV1Endpoint struct {
+	Method string
+	Path   string
+	Scope  string
+}
+
+type apiV1Index struct {
+	Version   string
+	Endpoints []apiV1Endpoint
+}
+
+type apiV1Team struct {
+	Id               int
+	Name             string
+	Nickname         string
+	City             string
+	StateProv        string
+	Country          string
+	SchoolName       string
+	RookieYear       int
+	RobotName        string
+	AnnouncerNotes   string
+	InspectionPassed bool
+	HasConnected     bool
+}
+
+// Represents the subset of team fields that may be changed through the API; fields that are omitted are left as is.
+type apiV1TeamUpdate struct {
+	Name             *string
+	Nickname         *string
+	RobotName        *string
+	AnnouncerNotes   *string
+	InspectionPassed *bool
+}
+
+type apiV1TeamStatus struct {
+	TeamId           int
+	InspectionPassed bool
+	Weighed          bool
+	HasConnected     bool
+	YellowCard       bool
+	UpdatedAt        time.Time
+}
+
+// Represents the pit checks that may be recorded through the API; fields that are omitted are left as is. Cards are
+// derived from the match results and so can't be changed directly.
+type apiV1TeamStatusUpdate struct {
+	InspectionPassed *bool
+	Weighed          *bool
+	HasConnected     *bool
+}
+
+type apiV1Match struct {
+	Id        int
+	Type      string
+	ShortName string
+	LongName  string
+	Time      time.Time
+	RedTeams  []int
+	BlueTeams []int
+	Status    string
+	RedScore  *int
+	BlueScore *int
+}
+
+type apiV1AllianceResult struct {
+	Score              int
+	AutoPoints         int
+	FoulPoints         int
+	BonusRankingPoints int
+	// Game-specific breakdown of the score, whose fields change from season to season.
+	Breakdown *game.ScoreSummary
+}
+
+type apiV1MatchResult struct {
+	Match      apiV1Match
+	PlayNumber int
+	Red        apiV1AllianceResult
+	Blue       apiV1AllianceResult
+}
+
+type apiV1Ranking struct {
+	Rank              int
+	TeamId            int
+	Nickname          string
+	RankingPoints     int
+	Wins              int
+	Losses            int
+	Ties              int
+	Disqualifications int
+	Played            int
+	// Game-specific sort criteria, whose fields change from season to season.
+	Details game.RankingFields
+}
+
+type apiV1ArenaStatus struct {
+	EventName       string
+	MatchState      string
+	MatchTimeSec    int
+	Match           apiV1Match
+	CycleTime       string
+	EarlyLateStatus string
+}
+
+var apiV1Endpoints = []apiV1Endpoint{
+	{"GET", "/api/v1/arena/status", model.ApiReadScope},
+	{"GET", "/api/v1/matches", model.ApiReadScope},
+	{"GET", "/api/v1/matches/{type}", model.ApiReadScope},
+	{"GET", "/api/v1/rankings", model.ApiReadScope},
+	{"GET", "/api/v1/results/{matchId}", model.ApiReadScope},
+	{"GET", "/api/v1/teams", model.ApiReadScope},
+	{"POST", "/api/v1/teams", model.ApiWriteScope},
+	{"GET", "/api/v1/teams/{teamId}", model.ApiReadScope},
+	{"POST", "/api/v1/teams/{teamId}", model.ApiWriteScope},
+	{"GET", "/api/v1/teams/{teamId}/status", model.ApiReadScope},
+	{"POST", "/api/v1/teams/{teamId}/status", model.ApiWriteScope},
+	{"GET", "/api/v1/team_statuses", model.ApiReadScope},
+}
+
+var apiV1MatchStates = map[field.MatchState]string{
+	field.PreMatch:      "pre_match",
+	field.StartMatch:    "start_match",
+	field.WarmupPeriod:  "warmup",
+	field.AutoPeriod:    "auto",
+	field.PausePeriod:   "pause",
+	field.TeleopPeriod:  "teleop",
+	field.PostMatch:     "post_match",
+	field.TimeoutActive: "timeout",
+	field.PostTimeout:   "post_timeout",
+	field.FieldFault:    "field_fault",
+}
+
+var apiV1MatchStatuses = map[game.MatchStatus]string{
+	game.MatchScheduled: "scheduled",
+	game.MatchHidden:    "hidden",
+	game.RedWonMatch:    "red_won",
+	game.BlueWonMatch:   "blue_won",
+	game.TieMatch:       "tie",
+}
+
+// Lists the endpoints available in this version of the API and the scope that each requires.
+func (web *Web) apiV1IndexHandler(w http.ResponseWriter, r *http.Request) {
+	writeApiV1Json(w, 200, apiV1Index{Version: apiV1Version, Endpoints: apiV1Endpoints})
+}
+
+// Returns the state of the field and the match currently loaded on it.
+func (web *Web) apiV1ArenaStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	status := apiV1ArenaStatus{
+		EventName:       web.arena.EventSettings.Name,
+		MatchState:      apiV1MatchStates[web.arena.MatchState],
+		MatchTimeSec:    int(web.arena.MatchTimeSec()),
+		Match:           web.newApiV1Match(web.arena.CurrentMatch, nil),
+		CycleTime:       web.arena.EventStatus.CycleTime,
+		EarlyLateStatus: web.arena.EventStatus.EarlyLateMessage,
+	}
+	writeApiV1Json(w, 200, status)
+}
+
+// Returns the schedule of the given match type, along with the scores of those matches that have been played.
+func (web *Web) apiV1MatchesHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	filter, err := parseApiV1MatchFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	matches, total, err := web.arena.Database.GetMatches(filter)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	apiMatches := make([]apiV1Match, len(matches))
+	for i, match := range matches {
+		var matchResult *model.MatchResult
+		if match.IsComplete() {
+			if matchResult, err = web.arena.Database.GetMatchResultForMatch(match.Id); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+		apiMatches[i] = web.newApiV1Match(&match, matchResult)
+	}
+
+	// Report the number of matches across all pages so that clients know when they have fetched them all.
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeApiV1Json(w, 200, apiMatches)
+}
+
+// Returns the match filter given by the request's path and query string. The type may be given in either, and the
+// query string may also give a comma-separated list of types, a team number, whether the matches have been played,
+// and the offset and size of the page to return.
+func parseApiV1MatchFilter(r *http.Request) (model.MatchFilter, error) {
+	var filter model.MatchFilter
+	query := r.URL.Query()
+	matchTypes := r.PathValue("type")
+	if matchTypes == "" {
+		matchTypes = query.Get("type")
+	}
+	if matchTypes != "" {
+		for _, matchTypeString := range strings.Split(matchTypes, ",") {
+			matchType, err := model.MatchTypeFromString(matchTypeString)
+			if err != nil {
+				return filter, err
+			}
+			filter.Types = append(filter.Types, matchType)
+		}
+	}
+
+	var err error
+	if team := query.Get("team"); team != "" {
+		if filter.TeamId, err = strconv.Atoi(team); err != nil || filter.TeamId <= 0 {
+			return filter, fmt.Errorf("invalid team %q", team)
+		}
+	}
+	switch status := query.Get("status"); status {
+	case "":
+	case "played":
+		filter.PlayedOnly = true
+	case "unplayed":
+		filter.UnplayedOnly = true
+	default:
+		return filter, fmt.Errorf("invalid status %q; must be played or unplayed", status)
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil || filter.Offset < 0 {
+			return filter, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+	return filter, nil
+}
+
+// Returns the detailed result of the given match.
+func (web *Web) apiV1ResultHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	matchId, _ := strconv.Atoi(r.PathValue("matchId"))
+	match, err := web.arena.Database.GetMatchById(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		http.Error(w, fmt.Sprintf("Match %s does not exist.", r.PathValue("matchId")), 404)
+		return
+	}
+	matchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if matchResult == nil || !match.IsComplete() {
+		http.Error(w, fmt.Sprintf("Match %s has not been scored yet.", match.ShortName), 404)
+		return
+	}
+
+	redScoreSummary := matchResult.RedScoreSummary()
+	blueScoreSummary := matchResult.BlueScoreSummary()
+	result := apiV1MatchResult{
+		Match:      web.newApiV1Match(match, matchResult),
+		PlayNumber: matchResult.PlayNumber,
+		Red:        newApiV1AllianceResult(redScoreSummary),
+		Blue:       newApiV1AllianceResult(blueScoreSummary),
+	}
+	writeApiV1Json(w, 200, result)
+}
+
+// Returns the current qualification rankings.
+func (web *Web) apiV1RankingsHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	rankings, err := web.arena.Database.GetAllRankings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamNicknames := make(map[int]string)
+	for _, team := range teams {
+		teamNicknames[team.Id] = team.Nickname
+	}
+
+	apiRankings := make([]apiV1Ranking, len(rankings))
+	for i, ranking := range rankings {
+		apiRankings[i] = apiV1Ranking{
+			Rank:              ranking.Rank,
+			TeamId:            ranking.TeamId,
+			Nickname:          teamNicknames[ranking.TeamId],
+			RankingPoints:     ranking.RankingPoints,
+			Wins:              ranking.Wins,
+			Losses:            ranking.Losses,
+			Ties:              ranking.Ties,
+			Disqualifications: ranking.Disqualifications,
+			Played:            ranking.Played,
+			Details:           ranking.RankingFields,
+		}
+	}
+	writeApiV1Json(w, 200, apiRankings)
+}
+
+// Returns the team list.
+func (web *Web) apiV1TeamsHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	apiTeams := make([]apiV1Team, len(teams))
+	for i, team := range teams {
+		teamStatus := teamStatuses[team.Id]
+		apiTeams[i] = newApiV1Team(&team, &teamStatus)
+	}
+	writeApiV1Json(w, 200, apiTeams)
+}
+
+// Adds a team to the team list, as long as the qualification schedule hasn't been generated yet.
+func (web *Web) apiV1TeamsPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiWriteScope) {
+		return
+	}
+
+	var apiTeam apiV1Team
+	if err := json.NewDecoder(r.Body).Decode(&apiTeam); err != nil {
+		http.Error(w, "Invalid team: "+err.Error(), 400)
+		return
+	}
+	if apiTeam.Id <= 0 {
+		http.Error(w, "Team number must be positive.", 400)
+		return
+	}
+	if !web.canModifyTeamList() {
+		http.Error(w, "The team list can't be modified once the qualification schedule has been generated.", 409)
+		return
+	}
+	existingTeam, err := web.arena.Database.GetTeamById(apiTeam.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if existingTeam != nil {
+		http.Error(w, fmt.Sprintf("Team %d is already in the team list.", apiTeam.Id), 409)
+		return
+	}
+
+	team := model.Team{
+		Id:             apiTeam.Id,
+		Name:           apiTeam.Name,
+		Nickname:       apiTeam.Nickname,
+		City:           apiTeam.City,
+		StateProv:      apiTeam.StateProv,
+		Country:        apiTeam.Country,
+		SchoolName:     apiTeam.SchoolName,
+		RookieYear:     apiTeam.RookieYear,
+		RobotName:      apiTeam.RobotName,
+		AnnouncerNotes: apiTeam.AnnouncerNotes,
+	}
+	if err = web.arena.Database.CreateTeam(&team); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if apiTeam.InspectionPassed && !teamStatus.InspectionPassed {
+		teamStatus.InspectionPassed = true
+		if err = web.arena.Database.SaveTeamStatus(teamStatus); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	writeApiV1Json(w, 201, newApiV1Team(&team, teamStatus))
+}
+
+// Returns the given team.
+func (web *Web) apiV1TeamHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	team, ok := web.getApiV1Team(w, r)
+	if !ok {
+		return
+	}
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	writeApiV1Json(w, 200, newApiV1Team(team, teamStatus))
+}
+
+// Updates the given fields of the given team.
+func (web *Web) apiV1TeamPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiWriteScope) {
+		return
+	}
+
+	team, ok := web.getApiV1Team(w, r)
+	if !ok {
+		return
+	}
+	var update apiV1TeamUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid team update: "+err.Error(), 400)
+		return
+	}
+	if update.Name != nil {
+		team.Name = *update.Name
+	}
+	if update.Nickname != nil {
+		team.Nickname = *update.Nickname
+	}
+	if update.RobotName != nil {
+		team.RobotName = *update.RobotName
+	}
+	if update.AnnouncerNotes != nil {
+		team.AnnouncerNotes = *update.AnnouncerNotes
+	}
+	if err := web.arena.Database.UpdateTeam(team); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if update.InspectionPassed != nil {
+		teamStatus.InspectionPassed = *update.InspectionPassed
+		if err = web.arena.Database.SaveTeamStatus(teamStatus); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	// Re-check the on-deck match in case this team is in it.
+	web.arena.UpdateOnDeckMatch()
+	writeApiV1Json(w, 200, newApiV1Team(team, teamStatus))
+}
+
+// Returns the pit check and card status of every team in the team list.
+func (web *Web) apiV1TeamStatusesHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	apiTeamStatuses := make([]apiV1TeamStatus, len(teams))
+	for i, team := range teams {
+		teamStatus := teamStatuses[team.Id]
+		teamStatus.TeamId = team.Id
+		apiTeamStatuses[i] = newApiV1TeamStatus(&teamStatus)
+	}
+	writeApiV1Json(w, 200, apiTeamStatuses)
+}
+
+// Returns the pit check and card status of the given team.
+func (web *Web) apiV1TeamStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiReadScope) {
+		return
+	}
+
+	team, ok := web.getApiV1Team(w, r)
+	if !ok {
+		return
+	}
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	writeApiV1Json(w, 200, newApiV1TeamStatus(teamStatus))
+}
+
+// Records the given pit checks for the given team.
+func (web *Web) apiV1TeamStatusPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiWriteScope) {
+		return
+	}
+
+	team, ok := web.getApiV1Team(w, r)
+	if !ok {
+		return
+	}
+	var update apiV1TeamStatusUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid team status update: "+err.Error(), 400)
+		return
+	}
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if update.InspectionPassed != nil {
+		teamStatus.InspectionPassed = *update.InspectionPassed
+	}
+	if update.Weighed != nil {
+		teamStatus.Weighed = *update.Weighed
+	}
+	if update.HasConnected != nil {
+		teamStatus.HasConnected = *update.HasConnected
+	}
+	if err = web.arena.Database.SaveTeamStatus(teamStatus); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	web.arena.UpdateOnDeckMatch()
+	writeApiV1Json(w, 200, newApiV1TeamStatus(teamStatus))
+}
+
+// Returns the team given in the request path. Writes an error response and returns false if it doesn't exist.
+func (web *Web) getApiV1Team(w http.ResponseWriter, r *http.Request) (*model.Team, bool) {
+	teamId, _ := strconv.Atoi(r.PathValue("teamId"))
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return nil, false
+	}
+	if team == nil {
+		http.Error(w, fmt.Sprintf("Team %s is not at this event.", r.PathValue("teamId")), 404)
+		return nil, false
+	}
+	return team, true
+}
+
+func newApiV1Team(team *model.Team, teamStatus *model.TeamStatus) apiV1Team {
+	return apiV1Team{
+		Id:               team.Id,
+		Name:             team.Name,
+		Nickname:         team.Nickname,
+		City:             team.City,
+		StateProv:        team.StateProv,
+		Country:          team.Country,
+		SchoolName:       team.SchoolName,
+		RookieYear:       team.RookieYear,
+		RobotName:        team.RobotName,
+		AnnouncerNotes:   team.AnnouncerNotes,
+		InspectionPassed: teamStatus.InspectionPassed,
+		HasConnected:     teamStatus.HasConnected,
+	}
+}
+
+func newApiV1TeamStatus(teamStatus *model.TeamStatus) apiV1TeamStatus {
+	return apiV1TeamStatus{
+		TeamId:           teamStatus.TeamId,
+		InspectionPassed: teamStatus.InspectionPassed,
+		Weighed:          teamStatus.Weighed,
+		HasConnected:     teamStatus.HasConnected,
+		YellowCard:       teamStatus.YellowCard,
+		UpdatedAt:        teamStatus.UpdatedAt,
+	}
+}
+
+// Returns the API representation of the given match, including its scores if a result is given. Only the team
+// positions used under the configured number of teams per alliance are included.
+func (web *Web) newApiV1Match(match *model.Match, matchResult *model.MatchResult) apiV1Match {
+	apiMatch := apiV1Match{
+		Id:        match.Id,
+		Type:      strings.ToLower(match.Type.String()),
+		ShortName: match.ShortName,
+		LongName:  match.LongName,
+		Time:      match.Time,
+		RedTeams:  match.RedTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+		BlueTeams: match.BlueTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+		Status:    apiV1MatchStatuses[match.Status],
+	}
+	if matchResult != nil {
+		redScore := matchResult.RedScoreSummary().Score
+		blueScore := matchResult.BlueScoreSummary().Score
+		apiMatch.RedScore = &redScore
+		apiMatch.BlueScore = &blueScore
+	}
+	return apiMatch
+}
+
+func newApiV1AllianceResult(scoreSummary *game.ScoreSummary) apiV1AllianceResult {
+	return apiV1AllianceResult{
+		Score:              scoreSummary.Score,
+		AutoPoints:         scoreSummary.AutoPoints,
+		FoulPoints:         scoreSummary.FoulPoints,
+		BonusRankingPoints: scoreSummary.BonusRankingPoints,
+		Breakdown:          scoreSummary,
+	}
+}
+
+// Writes the given value as the JSON body of the response, with the given status code.
+func writeApiV1Json(w http.ResponseWriter, statusCode int, value any) {
+	jsonData, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(jsonData)
+}
@@ -0,0 +1,273 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func setupApiV1Tokens(web *Web) {
+	web.arena.Database.CreateApiToken(&model.ApiToken{Name: "Reader", Token: "readtoken", Scope: model.ApiReadScope})
+	web.arena.Database.CreateApiToken(&model.ApiToken{Name: "Writer", Token: "writetoken", Scope: model.ApiWriteScope})
+}
+
+func TestApiV1Index(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/api/v1")
+	assert.Equal(t, 200, recorder.Code)
+	var index apiV1Index
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &index))
+	assert.Equal(t, "v1", index.Version)
+	assert.Contains(t, index.Endpoints, apiV1Endpoint{"GET", "/api/v1/rankings", model.ApiReadScope})
+}
+
+func TestApiV1Authorization(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+
+	recorder := web.getHttpResponse("/api/v1/teams")
+	assert.Equal(t, 401, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Missing API token.")
+	recorder = web.getHttpResponse("/api/v1/teams?apiKey=wrong")
+	assert.Equal(t, 401, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid API token.")
+	recorder = web.getHttpResponseWithHeaders("/api/v1/teams", map[string]string{"Authorization": "Bearer readtoken"})
+	assert.Equal(t, 200, recorder.Code)
+	recorder = web.getHttpResponse("/api/v1/teams?apiKey=writetoken")
+	assert.Equal(t, 200, recorder.Code)
+
+	recorder = web.postHttpResponse("/api/v1/teams?apiKey=readtoken", `{"Id": 254}`)
+	assert.Equal(t, 403, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "does not have the write scope")
+}
+
+func TestApiV1Teams(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs", WpaKey: "12345678"})
+
+	recorder := web.getHttpResponse("/api/v1/teams?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	assert.NotContains(t, recorder.Body.String(), "12345678")
+	var teams []apiV1Team
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &teams))
+	if assert.Equal(t, 1, len(teams)) {
+		assert.Equal(t, 254, teams[0].Id)
+		assert.Equal(t, "The Cheesy Poofs", teams[0].Nickname)
+	}
+
+	recorder = web.getHttpResponse("/api/v1/teams/1114?apiKey=readtoken")
+	assert.Equal(t, 404, recorder.Code)
+
+	// Add a team.
+	recorder = web.postHttpResponse("/api/v1/teams?apiKey=writetoken", `{"Id": 1114, "Nickname": "Simbotics"}`)
+	assert.Equal(t, 201, recorder.Code, recorder.Body.String())
+	recorder = web.postHttpResponse("/api/v1/teams?apiKey=writetoken", `{"Id": 1114}`)
+	assert.Equal(t, 409, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "already in the team list")
+	recorder = web.postHttpResponse("/api/v1/teams?apiKey=writetoken", `{"Id": 0}`)
+	assert.Equal(t, 400, recorder.Code)
+	recorder = web.getHttpResponse("/api/v1/teams/1114?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var team apiV1Team
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &team))
+	assert.Equal(t, "Simbotics", team.Nickname)
+
+	// Update only some fields of a team.
+	recorder = web.postHttpResponse(
+		"/api/v1/teams/254?apiKey=writetoken", `{"RobotName": "Barry", "InspectionPassed": true}`,
+	)
+	assert.Equal(t, 200, recorder.Code, recorder.Body.String())
+	dbTeam, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, "The Cheesy Poofs", dbTeam.Nickname)
+	assert.Equal(t, "Barry", dbTeam.RobotName)
+	teamStatus, _ := web.arena.Database.GetTeamStatus(254)
+	assert.True(t, teamStatus.InspectionPassed)
+	assert.Equal(t, "12345678", dbTeam.WpaKey)
+	recorder = web.postHttpResponse("/api/v1/teams/254?apiKey=writetoken", "blorpy")
+	assert.Equal(t, 400, recorder.Code)
+
+	// Check that teams can't be added once the schedule exists.
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification})
+	recorder = web.postHttpResponse("/api/v1/teams?apiKey=writetoken", `{"Id": 33}`)
+	assert.Equal(t, 409, recorder.Code)
+}
+
+func TestApiV1TeamStatuses(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114})
+	web.arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 1114, HasConnected: true, YellowCard: true})
+
+	recorder := web.getHttpResponse("/api/v1/team_statuses?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var teamStatuses []apiV1TeamStatus
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &teamStatuses))
+	if assert.Equal(t, 2, len(teamStatuses)) {
+		assert.Equal(t, apiV1TeamStatus{TeamId: 254}, teamStatuses[0])
+		assert.Equal(t, 1114, teamStatuses[1].TeamId)
+		assert.True(t, teamStatuses[1].HasConnected)
+		assert.True(t, teamStatuses[1].YellowCard)
+	}
+
+	// Check that only the given checks are changed and that the card can't be.
+	recorder = web.postHttpResponse("/api/v1/teams/1114/status?apiKey=readtoken", `{"Weighed": true}`)
+	assert.Equal(t, 403, recorder.Code)
+	recorder = web.postHttpResponse(
+		"/api/v1/teams/1114/status?apiKey=writetoken",
+		`{"Weighed": true, "InspectionPassed": true, "YellowCard": false}`,
+	)
+	assert.Equal(t, 200, recorder.Code, recorder.Body.String())
+	recorder = web.getHttpResponse("/api/v1/teams/1114/status?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var teamStatus apiV1TeamStatus
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &teamStatus))
+	assert.True(t, teamStatus.InspectionPassed)
+	assert.True(t, teamStatus.Weighed)
+	assert.True(t, teamStatus.HasConnected)
+	assert.True(t, teamStatus.YellowCard)
+
+	recorder = web.postHttpResponse("/api/v1/teams/1114/status?apiKey=writetoken", "blorpy")
+	assert.Equal(t, 400, recorder.Code)
+	recorder = web.getHttpResponse("/api/v1/teams/33/status?apiKey=readtoken")
+	assert.Equal(t, 404, recorder.Code)
+}
+
+func TestApiV1MatchesAndResults(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match1)
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2"}
+	web.arena.Database.CreateMatch(&match2)
+	matchResult := model.BuildTestMatchResult(match1.Id, 1)
+	web.arena.Database.CreateMatchResult(matchResult)
+
+	recorder := web.getHttpResponse("/api/v1/matches/qualification?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var matches []apiV1Match
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &matches))
+	if assert.Equal(t, 2, len(matches)) {
+		assert.Equal(t, "qualification", matches[0].Type)
+		assert.Equal(t, "red_won", matches[0].Status)
+		assert.Equal(t, []int{1, 2, 3}, matches[0].RedTeams)
+		if assert.NotNil(t, matches[0].RedScore) {
+			assert.Equal(t, matchResult.RedScoreSummary().Score, *matches[0].RedScore)
+		}
+		assert.Equal(t, "scheduled", matches[1].Status)
+		assert.Nil(t, matches[1].RedScore)
+	}
+
+	// Check that only the team positions in use are listed when alliances are smaller than three teams.
+	web.arena.EventSettings.TeamsPerAlliance = 2
+	recorder = web.getHttpResponse("/api/v1/matches/qualification?apiKey=readtoken")
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &matches))
+	if assert.Equal(t, 2, len(matches)) {
+		assert.Equal(t, []int{1, 2}, matches[0].RedTeams)
+		assert.Equal(t, []int{4, 5}, matches[0].BlueTeams)
+	}
+	web.arena.EventSettings.TeamsPerAlliance = 3
+
+	recorder = web.getHttpResponse("/api/v1/matches/blorpy?apiKey=readtoken")
+	assert.Equal(t, 400, recorder.Code)
+
+	recorder = web.getHttpResponse("/api/v1/results/1?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var result apiV1MatchResult
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.Equal(t, "Q1", result.Match.ShortName)
+	assert.Equal(t, matchResult.BlueScoreSummary().Score, result.Blue.Score)
+	assert.Equal(t, matchResult.RedScoreSummary().AutoPoints, result.Red.AutoPoints)
+	if assert.NotNil(t, result.Red.Breakdown) {
+		assert.Equal(t, matchResult.RedScoreSummary().Score, result.Red.Breakdown.Score)
+	}
+	recorder = web.getHttpResponse("/api/v1/results/2?apiKey=readtoken")
+	assert.Equal(t, 404, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "has not been scored")
+	recorder = web.getHttpResponse("/api/v1/results/3?apiKey=readtoken")
+	assert.Equal(t, 404, recorder.Code)
+}
+
+func TestApiV1MatchFilters(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+	for i := 1; i <= 4; i++ {
+		match := model.Match{Type: model.Qualification, TypeOrder: i, ShortName: fmt.Sprintf("Q%d", i), Red1: 100 + i}
+		if i%2 == 1 {
+			match.Blue1 = 254
+			match.Status = game.BlueWonMatch
+		}
+		web.arena.Database.CreateMatch(&match)
+	}
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Practice, TypeOrder: 1, ShortName: "P1", Red2: 254})
+
+	getShortNames := func(query string) ([]string, string) {
+		recorder := web.getHttpResponse("/api/v1/matches?apiKey=readtoken&" + query)
+		assert.Equal(t, 200, recorder.Code, recorder.Body.String())
+		var matches []apiV1Match
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &matches))
+		shortNames := []string{}
+		for _, match := range matches {
+			shortNames = append(shortNames, match.ShortName)
+		}
+		return shortNames, recorder.Header().Get("X-Total-Count")
+	}
+
+	shortNames, total := getShortNames("")
+	assert.Equal(t, []string{"P1", "Q1", "Q2", "Q3", "Q4"}, shortNames)
+	assert.Equal(t, "5", total)
+	shortNames, _ = getShortNames("type=qualification&team=254&status=played")
+	assert.Equal(t, []string{"Q1", "Q3"}, shortNames)
+	shortNames, _ = getShortNames("type=practice,qualification&status=unplayed")
+	assert.Equal(t, []string{"P1", "Q2", "Q4"}, shortNames)
+	shortNames, total = getShortNames("type=qualification&offset=1&limit=2")
+	assert.Equal(t, []string{"Q2", "Q3"}, shortNames)
+	assert.Equal(t, "4", total)
+
+	// Check that the type can also be given in the path.
+	recorder := web.getHttpResponse("/api/v1/matches/practice?apiKey=readtoken&team=254")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("X-Total-Count"))
+
+	for _, query := range []string{"type=blorpy", "team=blorpy", "team=-1", "status=blorpy", "offset=-1", "limit=0"} {
+		recorder = web.getHttpResponse("/api/v1/matches?apiKey=readtoken&" + query)
+		assert.Equal(t, 400, recorder.Code, query)
+	}
+}
+
+func TestApiV1RankingsAndStatus(t *testing.T) {
+	web := setupTestWeb(t)
+	setupApiV1Tokens(web)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	ranking := game.TestRanking1()
+	ranking.TeamId = 254
+	web.arena.Database.CreateRanking(ranking)
+
+	recorder := web.getHttpResponse("/api/v1/rankings?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var rankings []apiV1Ranking
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &rankings))
+	if assert.Equal(t, 1, len(rankings)) {
+		assert.Equal(t, 254, rankings[0].TeamId)
+		assert.Equal(t, "The Cheesy Poofs", rankings[0].Nickname)
+		assert.Equal(t, ranking.RankingPoints, rankings[0].RankingPoints)
+		assert.Equal(t, ranking.RankingFields, rankings[0].Details)
+	}
+
+	recorder = web.getHttpResponse("/api/v1/arena/status?apiKey=readtoken")
+	assert.Equal(t, 200, recorder.Code)
+	var status apiV1ArenaStatus
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, "pre_match", status.MatchState)
+	assert.Equal(t, "test", status.Match.Type)
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for browsing the reports and brackets of past events stored alongside the active one, and for the
+// standings across all of them.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/playoff"
+	"net/http"
+)
+
+// The data of an event from which reports are generated, which is either the active event or a past one.
+type reportEvent struct {
+	Database          *model.Database
+	EventSettings     *model.EventSettings
+	PlayoffTournament *playoff.PlayoffTournament
+}
+
+// A past event that has been opened for browsing, which stays open until its stored database changes.
+type archivedEvent struct {
+	reportEvent
+	archive *model.EventArchive
+}
+
+// Represents a team's combined record across all the events of the season.
+type seasonStanding struct {
+	Rank          int
+	TeamId        int
+	Nickname      string
+	NumEvents     int
+	RankingPoints int
+	Wins          int
+	Losses        int
+	Ties          int
+	Played        int
+}
+
+// Shows the list of past events whose reports can be browsed, along with the season standings.
+func (web *Web) archiveHandler(w http.ResponseWriter, r *http.Request) {
+	template, err := web.parseFiles("templates/archive.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	eventDatabases, err := model.ListEventDatabases(false)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	archivedEventDatabases, err := model.ListEventDatabases(true)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	seasonStandings, err := web.getSeasonStandings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		EventDatabases  []model.EventDatabaseFile
+		SeasonStandings []seasonStanding
+	}{web.arena.EventSettings, append(eventDatabases, archivedEventDatabases...), seasonStandings}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the season standings.
+func (web *Web) seasonStandingsApiHandler(w http.ResponseWriter, r *http.Request) {
+	seasonStandings, err := web.getSeasonStandings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(seasonStandings, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the event that the given report request pertains to: the past event named in the path for the /archive
+// routes, or the active event otherwise.
+func (web *Web) getReportEvent(r *http.Request) (*reportEvent, error) {
+	name := r.PathValue("event")
+	if name == "" {
+		return &reportEvent{web.arena.Database, web.arena.EventSettings, web.arena.PlayoffTournament}, nil
+	}
+	event, err := web.getArchivedEvent(name)
+	if err != nil {
+		return nil, err
+	}
+	return &event.reportEvent, nil
+}
+
+// Returns the past event with the given name, opening it if it isn't already open or has changed since it was opened.
+func (web *Web) getArchivedEvent(name string) (*archivedEvent, error) {
+	eventDatabase, err := model.FindEventDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+	if eventDatabase == nil {
+		return nil, fmt.Errorf("Event '%s' does not exist.", name)
+	}
+
+	web.archivedEventsMutex.Lock()
+	defer web.archivedEventsMutex.Unlock()
+	if event, ok := web.archivedEvents[name]; ok {
+		if event.archive.ModifiedTime.Equal(eventDatabase.ModifiedTime) {
+			return event, nil
+		}
+		web.closeArchivedEvent(event)
+		delete(web.archivedEvents, name)
+	}
+
+	archive, err := model.OpenEventArchive(name)
+	if err != nil {
+		return nil, err
+	}
+	event := &archivedEvent{reportEvent: reportEvent{Database: archive.Database}, archive: archive}
+	if err = event.load(); err != nil {
+		web.closeArchivedEvent(event)
+		return nil, err
+	}
+	if web.archivedEvents == nil {
+		web.archivedEvents = make(map[string]*archivedEvent)
+	}
+	web.archivedEvents[name] = event
+	return event, nil
+}
+
+// Closes all the past events that have been opened for browsing.
+func (web *Web) closeArchivedEvents() {
+	web.archivedEventsMutex.Lock()
+	defer web.archivedEventsMutex.Unlock()
+	for name, event := range web.archivedEvents {
+		web.closeArchivedEvent(event)
+		delete(web.archivedEvents, name)
+	}
+}
+
+func (web *Web) closeArchivedEvent(event *archivedEvent) {
+	if err := event.archive.Close(); err != nil {
+		logging.Database.Warn("Failed to close archived event.", "event", event.archive.Name, "error", err)
+	}
+}
+
+// Reads the settings of the past event and rebuilds the state of its playoff tournament from the results of its
+// playoff matches.
+func (event *archivedEvent) load() error {
+	var err error
+	if event.EventSettings, err = event.Database.GetEventSettings(); err != nil {
+		return err
+	}
+	event.PlayoffTournament, err = playoff.NewPlayoffTournament(
+		event.EventSettings.PlayoffType, event.EventSettings.NumPlayoffAlliances,
+	)
+	if err != nil {
+		return err
+	}
+	alliances, err := event.Database.GetAllAlliances()
+	if err != nil || len(alliances) == 0 {
+		return err
+	}
+
+	// This only writes to the scratch copy of the event's database, so the stored file is left untouched.
+	return event.PlayoffTournament.UpdateMatches(event.Database)
+}
+
+// Returns the combined qualification records of the teams across all the stored past events and the active event,
+// ordered by total ranking points. They are calculated as league standings in which every event counts and a team
+// earns one point per ranking point.
+func (web *Web) getSeasonStandings() ([]seasonStanding, error) {
+	eventDatabases, err := model.ListEventDatabases(false)
+	if err != nil {
+		return nil, err
+	}
+	archivedEventDatabases, err := model.ListEventDatabases(true)
+	if err != nil {
+		return nil, err
+	}
+	var events []league.EventResults
+	for _, eventDatabase := range append(eventDatabases, archivedEventDatabases...) {
+		event, err := web.getArchivedEvent(eventDatabase.Name)
+		if err != nil {
+			return nil, err
+		}
+		eventResults, err := getEventResults(event.Database, eventDatabase.Name)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, eventResults)
+	}
+
+	// The active event goes last so that its team nicknames take precedence over older ones.
+	eventResults, err := getEventResults(web.arena.Database, model.EventDatabaseName(web.arena.EventSettings.Name))
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, eventResults)
+
+	standings := league.CalculateStandings(league.SeasonSettings(), events)
+	seasonStandings := make([]seasonStanding, len(standings))
+	for i, standing := range standings {
+		seasonStandings[i] = seasonStanding{
+			Rank:          standing.Rank,
+			TeamId:        standing.TeamId,
+			Nickname:      standing.Nickname,
+			NumEvents:     len(standing.Events),
+			RankingPoints: standing.Points,
+			Wins:          standing.Wins,
+			Losses:        standing.Losses,
+			Ties:          standing.Ties,
+			Played:        standing.Played,
+		}
+	}
+	return seasonStandings, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Chezy_Champs_2023", "Chezy Champs 2023")
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 254, Rank: 2, RankingFields: game.RankingFields{RankingPoints: 10, Wins: 3, Played: 4}},
+	)
+
+	recorder := web.getHttpResponse("/archive")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, "/archive/Chezy_Champs_2023/reports/pdf/rankings")
+	assert.Contains(t, body, "<td>1114</td>")
+
+	// Check that the reports are generated from the past event rather than the active one.
+	recorder = web.getHttpResponse("/archive/Chezy_Champs_2023/reports/csv/rankings")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "1114")
+	assert.NotContains(t, recorder.Body.String(), "254")
+	recorder = web.getHttpResponse("/archive/Chezy_Champs_2023/reports/csv/schedule/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "1114")
+	recorder = web.getHttpResponse("/archive/Chezy_Champs_2023/reports/pdf/rankings")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
+	recorder = web.getHttpResponse("/archive/Chezy_Champs_2023/reports/pdf/bracket")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "<svg")
+	recorder = web.getHttpResponse("/archive/Chezy_Champs_2023/api/rankings")
+	assert.Equal(t, 200, recorder.Code)
+	var rankingsData struct {
+		Rankings           []RankingWithNickname
+		HighestPlayedMatch string
+	}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &rankingsData))
+	if assert.Equal(t, 1, len(rankingsData.Rankings)) {
+		assert.Equal(t, 1114, rankingsData.Rankings[0].TeamId)
+		assert.Equal(t, "Simbotics", rankingsData.Rankings[0].Nickname)
+	}
+	assert.Equal(t, "Q1", rankingsData.HighestPlayedMatch)
+
+	recorder = web.getHttpResponse("/archive/Blorpy/reports/pdf/rankings")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Event 'Blorpy' does not exist.")
+}
+
+func TestArchiveReopensChangedEvent(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Chezy_Champs_2023", "Chezy Champs 2023")
+
+	event, err := web.getArchivedEvent("Chezy_Champs_2023")
+	assert.Nil(t, err)
+	assert.Equal(t, "Chezy Champs 2023", event.EventSettings.Name)
+	sameEvent, _ := web.getArchivedEvent("Chezy_Champs_2023")
+	assert.Same(t, event, sameEvent)
+
+	// Modify the stored event and check that the new version is picked up.
+	dbPath := filepath.Join(model.BaseDir, "event.db")
+	assert.Nil(t, model.ActivateEventDatabase(dbPath, "Chezy_Champs_2023"))
+	time.Sleep(10 * time.Millisecond)
+	database, err := model.OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.Name = "Chezy Champs 2023 (Revised)"
+	assert.Nil(t, database.UpdateEventSettings(eventSettings))
+	assert.Nil(t, database.Close())
+	assert.Nil(t, model.StoreEventDatabase(dbPath, "Chezy_Champs_2023"))
+	newEvent, err := web.getArchivedEvent("Chezy_Champs_2023")
+	assert.Nil(t, err)
+	assert.NotSame(t, event, newEvent)
+	assert.Equal(t, "Chezy Champs 2023 (Revised)", newEvent.EventSettings.Name)
+}
+
+func TestSeasonStandings(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Chezy_Champs_2023", "Chezy Champs 2023")
+	assert.Nil(t, model.SetEventDatabaseArchived("Chezy_Champs_2023", true))
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics 2024"})
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 1114, Rank: 1, RankingFields: game.RankingFields{RankingPoints: 8, Wins: 2, Played: 3}},
+	)
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 254, Rank: 2, RankingFields: game.RankingFields{RankingPoints: 12, Wins: 4, Played: 4}},
+	)
+
+	recorder := web.getHttpResponse("/api/archive/season_standings")
+	assert.Equal(t, 200, recorder.Code)
+	var seasonStandings []seasonStanding
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &seasonStandings))
+	if assert.Equal(t, 2, len(seasonStandings)) {
+		assert.Equal(
+			t,
+			seasonStanding{
+				Rank:          1,
+				TeamId:        1114,
+				Nickname:      "Simbotics 2024",
+				NumEvents:     2,
+				RankingPoints: 18,
+				Wins:          5,
+				Losses:        1,
+				Played:        7,
+			},
+			seasonStandings[0],
+		)
+		assert.Equal(t, 254, seasonStandings[1].TeamId)
+		assert.Equal(t, 1, seasonStandings[1].NumEvents)
+	}
+
+	// Check that the season standings are only shown on the pit display if that page is enabled.
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	recorder = web.getHttpResponse("/displays/pit/pages")
+	assert.Contains(t, recorder.Body.String(), "No season standings yet")
+	web.arena.EventSettings.PitDisplayPages = []string{"rankings", "season"}
+	recorder = web.getHttpResponse("/displays/pit/pages")
+	assert.NotContains(t, recorder.Body.String(), "No season standings yet")
+	assert.Contains(t, recorder.Body.String(), "Simbotics 2024")
+}
+
+// Keeps the stored event databases in a scratch directory so that they don't end up in the source tree, while still
+// loading the templates from it.
+func useScratchEventDatabasesDir(t *testing.T) {
+	templatesDir, _ := filepath.Abs(filepath.Join(model.BaseDir, "templates"))
+	model.BaseDir = t.TempDir()
+	t.Cleanup(func() { model.BaseDir = ".." })
+	assert.Nil(t, os.Symlink(templatesDir, filepath.Join(model.BaseDir, "templates")))
+}
+
+// Stores an event database under the given name containing a single team with a completed qualification match.
+func createTestEventArchive(t *testing.T, name, eventName string) {
+	dbPath := filepath.Join(model.BaseDir, name+".db")
+	database, err := model.OpenDatabase(dbPath)
+	assert.Nil(t, err)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.Name = eventName
+	assert.Nil(t, database.UpdateEventSettings(eventSettings))
+	assert.Nil(t, database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"}))
+	assert.Nil(t, database.CreateMatch(
+		&model.Match{Type: model.Qualification, ShortName: "Q1", Red1: 1114, Status: game.RedWonMatch},
+	))
+	assert.Nil(t, database.CreateRanking(
+		&game.Ranking{TeamId: 1114, Rank: 1, RankingFields: game.RankingFields{RankingPoints: 10, Wins: 3, Losses: 1,
+			Played: 4}},
+	))
+	assert.Nil(t, database.Close())
+	assert.Nil(t, model.StoreEventDatabase(dbPath, name))
+}
@@ -6,21 +6,43 @@
 package web
 
 import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
 	"net/http"
+	"path/filepath"
+	"slices"
 
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 )
 
-// Renders the audience display to be chroma keyed over the video feed.
+// Renders the audience display to be chroma keyed over the video feed. In overlay-only mode, the full-screen graphics
+// are suppressed so that only the score bar and lower thirds are ever drawn over the background. The output determines
+// whether the display follows the screen selected for the stage or for the stream, so that each can be connected at
+// the same time and controlled independently.
 func (web *Web) audienceDisplayHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.enforceDisplayConfiguration(w, r, map[string]string{"background": "#0f0", "reversed": "false",
-		"overlayLocation": "top"}) {
+		"overlayLocation": "top", "overlayOnly": "false", "output": field.StageAudienceDisplay}) {
 		return
 	}
 
-	template, err := web.parseFiles("templates/audience_display.html")
+	displayTemplate, err := web.parseFiles("templates/audience_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	leftScoreWidgets, err := web.renderAudienceScoreWidgets("left")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rightScoreWidgets, err := web.renderAudienceScoreWidgets("right")
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -28,9 +50,18 @@ func (web *Web) audienceDisplayHandler(w http.ResponseWriter, r *http.Request) {
 
 	data := struct {
 		*model.EventSettings
-		MatchSounds []*game.MatchSound
-	}{web.arena.EventSettings, game.MatchSounds}
-	err = template.ExecuteTemplate(w, "audience_display.html", data)
+		MatchSounds       []*game.MatchSound
+		LeftScoreWidgets  []template.HTML
+		RightScoreWidgets []template.HTML
+		OverlayOnly       bool
+	}{
+		web.arena.EventSettings,
+		game.MatchSounds,
+		leftScoreWidgets,
+		rightScoreWidgets,
+		r.URL.Query().Get("overlayOnly") == "true",
+	}
+	err = displayTemplate.ExecuteTemplate(w, "audience_display.html", data)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -53,9 +84,63 @@ func (web *Web) audienceDisplayWebsocketHandler(w http.ResponseWriter, r *http.R
 	}
 	defer ws.Close()
 
-	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
-	ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.AudienceDisplayModeNotifier,
+	audienceDisplayModeNotifier := web.arena.AudienceDisplayModeNotifier
+	if display.DisplayConfiguration.Configuration["output"] == field.StreamAudienceDisplay {
+		audienceDisplayModeNotifier = web.arena.StreamAudienceDisplayModeNotifier
+	}
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, audienceDisplayModeNotifier,
 		web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier, web.arena.RealtimeScoreNotifier,
 		web.arena.PlaySoundNotifier, web.arena.ScorePostedNotifier, web.arena.AllianceSelectionNotifier,
-		web.arena.LowerThirdNotifier, web.arena.ReloadDisplaysNotifier)
+		web.arena.LowerThirdNotifier, web.arena.StationStopsNotifier, web.arena.VideoStingerNotifier,
+		web.arena.ReplayNotifier, web.arena.ReloadDisplaysNotifier)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Web.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "completeVideoStinger":
+			videoStingerId, ok := data.(float64)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			web.arena.CompleteVideoStinger(int(videoStingerId))
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+		}
+	}
+}
+
+// Renders the current game's realtime score widgets for the given side of the audience display, ordered so that they
+// read from the outside of the screen towards the center.
+func (web *Web) renderAudienceScoreWidgets(side string) ([]template.HTML, error) {
+	widgetNames := game.CurrentGame().AudienceScoreWidgets()
+	widgets := make([]template.HTML, len(widgetNames))
+	for i, widgetName := range widgetNames {
+		widgetTemplate, err := web.parseFiles(filepath.Join("templates/audience_score_widgets", widgetName))
+		if err != nil {
+			return nil, err
+		}
+		var widget bytes.Buffer
+		if err = widgetTemplate.ExecuteTemplate(&widget, widgetName, struct{ Side string }{side}); err != nil {
+			return nil, err
+		}
+		widgets[i] = template.HTML(widget.String())
+	}
+	if side == "right" {
+		// The right side is a mirror image of the left.
+		slices.Reverse(widgets)
+	}
+	return widgets, nil
 }
@@ -4,6 +4,8 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -18,12 +20,51 @@ func TestAudienceDisplay(t *testing.T) {
 	assert.Contains(t, recorder.Header().Get("Location"), "displayId=100")
 	assert.Contains(t, recorder.Header().Get("Location"), "background=%230f0")
 	assert.Contains(t, recorder.Header().Get("Location"), "reversed=false")
-	assert.Contains(t, recorder.Header().Get("Location"), "overlayLocation=bottom")
+	assert.Contains(t, recorder.Header().Get("Location"), "overlayLocation=top")
+	assert.Contains(t, recorder.Header().Get("Location"), "overlayOnly=false")
+	assert.Contains(t, recorder.Header().Get("Location"), "output=stage")
 
-	recorder = web.getHttpResponse("/displays/audience?displayId=1&background=%23000&reversed=false&overlayLocation=" +
-		"top")
+	recorder = web.getHttpResponse(
+		"/displays/audience?displayId=1&background=%23000&reversed=false&overlayLocation=top&overlayOnly=false" +
+			"&output=stage",
+	)
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Audience Display - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), `<body data-overlay-only="false">`)
+}
+
+func TestAudienceDisplayOverlayOnly(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Check that the overlay-only setting is carried through the redirect that fills in the other defaults.
+	recorder := web.getHttpResponse("/displays/audience?overlayOnly=true")
+	assert.Equal(t, 302, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Location"), "overlayOnly=true")
+
+	// Check that the page flags the overlay-only mode for the JavaScript to suppress the full-screen graphics.
+	recorder = web.getHttpResponse(
+		"/displays/audience?displayId=1&background=%23000&reversed=false&overlayLocation=top&overlayOnly=true" +
+			"&output=stream",
+	)
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `<body data-overlay-only="true">`)
+	assert.Contains(t, body, `id="leftLights"`)
+}
+
+func TestAudienceDisplayScoreWidgets(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse(
+		"/displays/audience?displayId=1&background=%23000&reversed=false&overlayLocation=top&overlayOnly=false" +
+			"&output=stage",
+	)
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `data-score-field="ScoreSummary.NumNotes"`)
+	assert.Contains(t, body, `id="leftLights"`)
+	assert.Contains(t, body, `id="rightAmplified"`)
+	assert.NotContains(t, body, "&lt;div")
 }
 
 func TestAudienceDisplayWebsocket(t *testing.T) {
@@ -46,6 +87,9 @@ func TestAudienceDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "scorePosted")
 	readWebsocketType(t, ws, "allianceSelection")
 	readWebsocketType(t, ws, "lowerThird")
+	readWebsocketType(t, ws, "stationStops")
+	readWebsocketType(t, ws, "videoStinger")
+	readWebsocketType(t, ws, "replay")
 
 	// Run through a match cycle.
 	web.arena.MatchLoadNotifier.Notify()
@@ -59,17 +103,19 @@ func TestAudienceDisplayWebsocket(t *testing.T) {
 	web.arena.StartMatch()
 	web.arena.Update()
 	web.arena.Update()
-	messages := readWebsocketMultiple(t, ws, 3)
+	messages := readWebsocketMultiple(t, ws, 4)
 	screen, ok := messages["audienceDisplayMode"]
 	if assert.True(t, ok) {
 		assert.Equal(t, "match", screen)
 	}
 	sound, ok := messages["playSound"]
 	if assert.True(t, ok) {
-		assert.Equal(t, "start", sound)
+		assert.Equal(t, "start", sound.(map[string]any)["Name"])
 	}
 	_, ok = messages["matchTime"]
 	assert.True(t, ok)
+	_, ok = messages["stationStops"]
+	assert.True(t, ok)
 	web.arena.RealtimeScoreNotifier.Notify()
 	readWebsocketType(t, ws, "realtimeScore")
 	web.arena.ScorePostedNotifier.Notify()
@@ -80,4 +126,39 @@ func TestAudienceDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "allianceSelection")
 	web.arena.LowerThirdNotifier.Notify()
 	readWebsocketType(t, ws, "lowerThird")
+
+	// Test playing a video stinger through to completion.
+	web.arena.Database.CreateVideoStinger(
+		&model.VideoStinger{Name: "Award Intro", VideoFile: "award.webm", NextScreen: "logo"},
+	)
+	assert.Nil(t, web.arena.PlayVideoStinger(1))
+	messages = readWebsocketMultiple(t, ws, 2)
+	assert.Equal(t, "stinger", messages["audienceDisplayMode"])
+	assert.Equal(t, "Award Intro", messages["videoStinger"].(map[string]any)["Active"].(map[string]any)["Name"])
+	ws.Write("completeVideoStinger", 1)
+	messages = readWebsocketMultiple(t, ws, 2)
+	assert.Equal(t, "logo", messages["audienceDisplayMode"])
+	assert.Nil(t, messages["videoStinger"].(map[string]any)["Active"])
+}
+
+func TestAudienceDisplayWebsocketStreamOutput(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/displays/audience/websocket?displayId=2&overlayOnly=true&output=stream", nil,
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// The stream output should follow its own screen instead of the stage's.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "matchTiming")
+	assert.Equal(t, "blank", readWebsocketType(t, ws, "streamAudienceDisplayMode"))
+	readWebsocketMultiple(t, ws, 9)
+	assert.Nil(t, web.arena.SetAudienceDisplayOutputMode(field.StageAudienceDisplay, "logo"))
+	assert.Nil(t, web.arena.SetAudienceDisplayOutputMode(field.StreamAudienceDisplay, "sponsor"))
+	assert.Equal(t, "sponsor", readWebsocketType(t, ws, "streamAudienceDisplayMode"))
 }
@@ -0,0 +1,240 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Recording of state-changing web requests in the audit log, and web routes for browsing and exporting it.
+
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Maximum number of entries shown on the audit log page; the CSV export always includes every matching entry.
+const auditLogPageSize = 500
+
+// Maximum length of each form value recorded in an audit log entry's details.
+const auditMaxValueLength = 100
+
+// Format of the date filters on the audit log page.
+const auditDateFormat = "2006-01-02T15:04"
+
+// Paths of the GET routes that change state, which are audited along with all requests using other methods.
+var auditedGetPaths = []string{
+	"/setup/settings/publish_alliances",
+	"/setup/settings/publish_awards",
+	"/setup/settings/publish_matches",
+	"/setup/settings/publish_rankings",
+	"/setup/settings/publish_teams",
+	"/setup/teams/generate_wpa_keys",
+	"/setup/teams/refresh",
+}
+
+type auditEntryContextKey struct{}
+
+// Wraps a response writer to capture the status code of the response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (writer *auditResponseWriter) WriteHeader(status int) {
+	writer.status = status
+	writer.ResponseWriter.WriteHeader(status)
+}
+
+// Wraps the given handler to record each state-changing request in the audit log once it has been handled.
+func (web *Web) auditRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == "GET" || r.Method == "HEAD") && !slices.Contains(auditedGetPaths, r.URL.Path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		// Determine the user before handling the request, since logging in or out changes it.
+		auditEntry := &model.AuditEntry{
+			Time: time.Now(), Username: web.getRequestUsername(r), Action: r.Method + " " + r.URL.Path,
+		}
+		r = r.WithContext(context.WithValue(r.Context(), auditEntryContextKey{}, auditEntry))
+		auditWriter := &auditResponseWriter{ResponseWriter: w, status: 200}
+		handler.ServeHTTP(auditWriter, r)
+
+		auditEntry.Status = auditWriter.status
+		if len(auditEntry.Changes) == 0 {
+			// The changes describe the action more precisely than the form does, so only fall back to the form.
+			auditEntry.Details = formatAuditDetails(r)
+		}
+		if err := web.arena.Database.CreateAuditEntry(auditEntry); err != nil {
+			logging.Web.Error("Failed to write audit log entry.", "action", auditEntry.Action, "error", err)
+		}
+	})
+}
+
+// Records the fields that differ between the old and new values of whatever the given request changed, in the request's
+// audit log entry.
+func recordAuditChanges(r *http.Request, oldValue, newValue any) {
+	if auditEntry, ok := r.Context().Value(auditEntryContextKey{}).(*model.AuditEntry); ok {
+		auditEntry.Changes = append(auditEntry.Changes, model.DiffAuditValues(oldValue, newValue)...)
+	}
+}
+
+// Records a command received over the websocket opened by the given request as its own audit log entry.
+func (web *Web) recordAuditCommand(r *http.Request, command string, data any) {
+	auditEntry := model.AuditEntry{
+		Time:     time.Now(),
+		Username: web.getRequestUsername(r),
+		Action:   fmt.Sprintf("%s %s", r.URL.Path, command),
+		Status:   200,
+	}
+	if data != nil {
+		auditEntry.Details = truncateAuditValue(fmt.Sprint(data))
+	}
+	if err := web.arena.Database.CreateAuditEntry(&auditEntry); err != nil {
+		logging.Web.Error("Failed to write audit log entry.", "action", auditEntry.Action, "error", err)
+	}
+}
+
+// Shows the audit log, filtered by the criteria given in the query string.
+func (web *Web) auditLogGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	filter := web.parseAuditFilter(r)
+	auditEntries, err := web.arena.Database.GetAuditEntries(filter)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	usernames, err := web.arena.Database.GetAuditUsernames()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	totalCount := len(auditEntries)
+	if totalCount > auditLogPageSize {
+		auditEntries = auditEntries[:auditLogPageSize]
+	}
+
+	template, err := web.parseFiles("templates/setup_audit_log.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		AuditEntries []model.AuditEntry
+		TotalCount   int
+		Usernames    []string
+		Filter       model.AuditFilter
+		SinceText    string
+		UntilText    string
+		QueryString  string
+	}{
+		web.arena.EventSettings,
+		auditEntries,
+		totalCount,
+		usernames,
+		filter,
+		r.URL.Query().Get("since"),
+		r.URL.Query().Get("until"),
+		r.URL.RawQuery,
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV file of the audit log entries matching the criteria given in the query string, with one row per
+// changed field.
+func (web *Web) auditLogCsvHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	auditEntries, err := web.arena.Database.GetAuditEntries(web.parseAuditFilter(r))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"Time", "Username", "Action", "Status", "Details", "Field", "OldValue", "NewValue"})
+	for _, auditEntry := range auditEntries {
+		row := []string{
+			auditEntry.Time.In(web.arena.EventSettings.Location()).Format("2006-01-02 15:04:05"),
+			auditEntry.Username,
+			auditEntry.Action,
+			strconv.Itoa(auditEntry.Status),
+			auditEntry.Details,
+		}
+		if len(auditEntry.Changes) == 0 {
+			_ = writer.Write(append(row, "", "", ""))
+		}
+		for _, change := range auditEntry.Changes {
+			_ = writer.Write(append(row, change.Field, change.OldValue, change.NewValue))
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the audit log filter given by the query string of the request, ignoring any dates that can't be parsed. The
+// dates are taken to be in the event's time zone.
+func (web *Web) parseAuditFilter(r *http.Request) model.AuditFilter {
+	query := r.URL.Query()
+	filter := model.AuditFilter{Username: query.Get("username"), Search: strings.TrimSpace(query.Get("search"))}
+	location := web.arena.EventSettings.Location()
+	if since, err := time.ParseInLocation(auditDateFormat, query.Get("since"), location); err == nil {
+		filter.Since = since
+	}
+	if until, err := time.ParseInLocation(auditDateFormat, query.Get("until"), location); err == nil {
+		filter.Until = until
+	}
+	return filter
+}
+
+// Returns a summary of the form values and uploaded files of the given request, with sensitive values hidden.
+func formatAuditDetails(r *http.Request) string {
+	var details []string
+	for key, values := range r.PostForm {
+		value := strings.Join(values, ", ")
+		if model.IsAuditSensitiveName(key) {
+			value = model.AuditRedactedValue
+		}
+		details = append(details, fmt.Sprintf("%s=%s", key, truncateAuditValue(value)))
+	}
+	if r.MultipartForm != nil {
+		for key, fileHeaders := range r.MultipartForm.File {
+			for _, fileHeader := range fileHeaders {
+				details = append(details, fmt.Sprintf("%s=%s (%d bytes)", key, fileHeader.Filename, fileHeader.Size))
+			}
+		}
+	}
+	sort.Strings(details)
+	return strings.Join(details, "; ")
+}
+
+// Shortens the given value to the maximum length recorded in the audit log.
+func truncateAuditValue(value string) string {
+	if len(value) > auditMaxValueLength {
+		return value[:auditMaxValueLength] + "..."
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "Poofs"})
+
+	// Check that viewing pages isn't logged.
+	web.getHttpResponse("/setup/teams")
+	auditEntries, _ := web.arena.Database.GetAuditEntries(model.AuditFilter{})
+	assert.Empty(t, auditEntries)
+
+	recorder := web.postHttpResponse("/setup/teams/254/edit", "nickname=The Cheesy Poofs&wpaKey=12345678")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Bertha&password=whistle&role=referee")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/teams/generate_wpa_keys?all=true")
+	assert.Equal(t, 303, recorder.Code)
+	auditEntries, _ = web.arena.Database.GetAuditEntries(model.AuditFilter{})
+	if assert.Equal(t, 3, len(auditEntries)) {
+		assert.Equal(t, "GET /setup/teams/generate_wpa_keys", auditEntries[0].Action)
+		assert.Equal(t, "POST /setup/users", auditEntries[1].Action)
+		assert.Equal(t, 303, auditEntries[1].Status)
+		assert.Equal(t, "action=create; password=(hidden); role=referee; username=Bertha", auditEntries[1].Details)
+		assert.Equal(t, "POST /setup/teams/254/edit", auditEntries[2].Action)
+		assert.Equal(t, "", auditEntries[2].Details)
+		assert.Equal(
+			t, []model.AuditChange{{"Nickname", "Poofs", "The Cheesy Poofs"}}, auditEntries[2].Changes,
+		)
+	}
+
+	recorder = web.getHttpResponse("/setup/audit_log")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "POST /setup/users")
+	assert.Contains(t, recorder.Body.String(), "Nickname: Poofs &rarr; The Cheesy Poofs")
+	assert.NotContains(t, recorder.Body.String(), "whistle")
+	recorder = web.getHttpResponse("/setup/audit_log?search=nickname")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "POST /setup/teams/254/edit")
+	assert.NotContains(t, recorder.Body.String(), "POST /setup/users")
+
+	recorder = web.getHttpResponse("/setup/audit_log/csv?search=users")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Time,Username,Action,Status,Details,Field,OldValue,NewValue\n")
+	assert.Contains(t, recorder.Body.String(), ",POST /setup/users,303,action=create; password=(hidden);")
+	assert.NotContains(t, recorder.Body.String(), "edit")
+}
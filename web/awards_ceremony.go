@@ -0,0 +1,161 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for running the awards ceremony.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/Team254/cheesy-arena/tournament"
+	"net/http"
+	"strconv"
+)
+
+// Shows the awards ceremony control page.
+func (web *Web) awardsCeremonyGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.AvRole) {
+		return
+	}
+
+	web.renderAwardsCeremony(w, r, "")
+}
+
+// Records the recipient of an award and publishes the updated awards if TBA publishing is enabled.
+func (web *Web) awardsCeremonyWinnerPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.AvRole) {
+		return
+	}
+
+	awardId, err := strconv.Atoi(r.PathValue("awardId"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamId, _ := strconv.Atoi(r.PostFormValue("teamId"))
+	award, err := tournament.RecordAwardWinner(web.arena.Database, awardId, teamId, r.PostFormValue("personName"))
+	if err != nil {
+		web.renderAwardsCeremony(w, r, err.Error())
+		return
+	}
+
+	// Refresh the lower third on screen in case it is the one that was just changed.
+	if web.arena.LowerThird != nil && web.arena.LowerThird.AwardId == award.Id {
+		if web.arena.LowerThird, err = web.arena.Database.GetLowerThirdById(web.arena.LowerThird.Id); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		web.arena.LowerThirdNotifier.Notify()
+	}
+
+	if web.arena.EventSettings.TbaPublishingEnabled {
+		web.arena.TbaPublisher.Enqueue(partner.TbaPublishAwards)
+	}
+
+	http.Redirect(w, r, "/awards_ceremony", 303)
+}
+
+// Shows the lower third for the given step of the ceremony on the audience display.
+func (web *Web) awardsCeremonyShowPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.AvRole) {
+		return
+	}
+
+	steps, err := tournament.GetAwardCeremonySteps(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	stepIndex, err := strconv.Atoi(r.PostFormValue("step"))
+	if err != nil || stepIndex < 0 || stepIndex >= len(steps) {
+		web.renderAwardsCeremony(w, r, "Invalid awards ceremony step.")
+		return
+	}
+	web.showAwardCeremonyStep(&steps[stepIndex])
+
+	http.Redirect(w, r, "/awards_ceremony", 303)
+}
+
+// Advances the ceremony to the step following the one currently being shown.
+func (web *Web) awardsCeremonyNextPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.AvRole) {
+		return
+	}
+
+	steps, err := tournament.GetAwardCeremonySteps(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	nextIndex := web.currentAwardCeremonyStepIndex(steps) + 1
+	if nextIndex >= len(steps) {
+		web.renderAwardsCeremony(w, r, "The awards ceremony has no more steps.")
+		return
+	}
+	web.showAwardCeremonyStep(&steps[nextIndex])
+
+	http.Redirect(w, r, "/awards_ceremony", 303)
+}
+
+// Hides the lower third on the audience display.
+func (web *Web) awardsCeremonyHidePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.AvRole) {
+		return
+	}
+
+	web.arena.ShowLowerThird = false
+	web.arena.LowerThirdNotifier.Notify()
+
+	http.Redirect(w, r, "/awards_ceremony", 303)
+}
+
+func (web *Web) showAwardCeremonyStep(step *tournament.AwardCeremonyStep) {
+	lowerThird := step.LowerThird
+	web.arena.LowerThird = &lowerThird
+	web.arena.ShowLowerThird = true
+	web.arena.LowerThirdNotifier.Notify()
+}
+
+// Returns the index of the ceremony step whose lower third is currently showing, or -1 if there isn't one.
+func (web *Web) currentAwardCeremonyStepIndex(steps []tournament.AwardCeremonyStep) int {
+	if web.arena.LowerThird == nil || !web.arena.ShowLowerThird {
+		return -1
+	}
+	for i, step := range steps {
+		if step.LowerThird.Id == web.arena.LowerThird.Id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (web *Web) renderAwardsCeremony(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/awards_ceremony.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	steps, err := tournament.GetAwardCeremonySteps(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Steps            []tournament.AwardCeremonyStep
+		CurrentStepIndex int
+		Teams            []model.Team
+		ErrorMessage     string
+	}{web.arena.EventSettings, steps, web.currentAwardCeremonyStepIndex(steps), teams, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
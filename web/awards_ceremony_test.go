@@ -0,0 +1,60 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestAwardsCeremony(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "Teh Chezy Pofs"})
+	award := model.Award{Type: model.JudgedAward, AwardName: "Safety Award"}
+	assert.Nil(t, tournament.CreateOrUpdateAward(web.arena.Database, &award, true))
+
+	recorder := web.getHttpResponse("/awards_ceremony")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Safety Award")
+	assert.Contains(t, recorder.Body.String(), "(No awardee assigned yet)")
+
+	// Step through the ceremony.
+	recorder = web.postHttpResponse("/awards_ceremony/next", "")
+	assert.Equal(t, 303, recorder.Code)
+	assert.True(t, web.arena.ShowLowerThird)
+	assert.Equal(t, "Safety Award", web.arena.LowerThird.TopText)
+	assert.Equal(t, "", web.arena.LowerThird.BottomText)
+	recorder = web.postHttpResponse("/awards_ceremony/next", "")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "(No awardee assigned yet)", web.arena.LowerThird.BottomText)
+	recorder = web.postHttpResponse("/awards_ceremony/next", "")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The awards ceremony has no more steps.")
+
+	// Recording the recipient should update the lower third being shown.
+	recorder = web.postHttpResponse("/awards_ceremony/"+strconv.Itoa(award.Id)+"/winner", "teamId=254&personName=")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "Team 254, Teh Chezy Pofs", web.arena.LowerThird.BottomText)
+	awards, _ := web.arena.Database.GetAllAwards()
+	if assert.Equal(t, 1, len(awards)) {
+		assert.Equal(t, 254, awards[0].TeamId)
+	}
+	recorder = web.postHttpResponse("/awards_ceremony/"+strconv.Itoa(award.Id)+"/winner", "teamId=9999")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 9999 is not present at this event.")
+
+	recorder = web.postHttpResponse("/awards_ceremony/show", "step=0")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "", web.arena.LowerThird.BottomText)
+	recorder = web.postHttpResponse("/awards_ceremony/show", "step=5")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid awards ceremony step.")
+
+	recorder = web.postHttpResponse("/awards_ceremony/hide", "")
+	assert.Equal(t, 303, recorder.Code)
+	assert.False(t, web.arena.ShowLowerThird)
+}
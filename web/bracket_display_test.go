@@ -31,4 +31,9 @@ func TestBracketDisplayWebsocket(t *testing.T) {
 	// Should get a few status updates right after connection.
 	readWebsocketType(t, ws, "displayConfiguration")
 	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "scorePosted")
+
+	// Check that the bracket is told to update when a playoff match result is committed.
+	web.arena.ScorePostedNotifier.Notify()
+	readWebsocketType(t, ws, "scorePosted")
 }
@@ -0,0 +1,66 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the accessibility caption display, which shows the match state, scores and announcements in large
+// text for audience members who can't hear the announcer.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+)
+
+// Renders the caption display.
+func (web *Web) captionDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.enforceDisplayConfiguration(w, r, nil) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/caption_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+	}{web.arena.EventSettings}
+	err = template.ExecuteTemplate(w, "caption_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the caption display client to receive status updates.
+func (web *Web) captionDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	display, err := web.registerDisplay(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer web.arena.MarkDisplayDisconnected(display.DisplayConfiguration.Id)
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(
+		display.Notifier,
+		web.arena.MatchTimingNotifier,
+		web.arena.MatchLoadNotifier,
+		web.arena.MatchTimeNotifier,
+		web.arena.RealtimeScoreNotifier,
+		web.arena.ScorePostedNotifier,
+		web.arena.CaptionsNotifier,
+		web.arena.ReloadDisplaysNotifier,
+	)
+
+	// Keep reading from the websocket so that the client's clock synchronization requests get answered.
+	ws.HandleReads()
+}
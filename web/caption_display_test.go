@@ -0,0 +1,50 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCaptionDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/captions?displayId=1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Caption Display - Untitled Event - Cheesy Arena")
+}
+
+func TestCaptionDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/captions/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "matchTiming")
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "matchTime")
+	readWebsocketType(t, ws, "realtimeScore")
+	readWebsocketType(t, ws, "scorePosted")
+	readWebsocketType(t, ws, "captions")
+
+	// Check that captions are passed along as the announcer pushes them.
+	assert.Nil(t, web.arena.AddCaption("Welcome to the finals!"))
+	message := readWebsocketType(t, ws, "captions").(map[string]any)
+	captions := message["Captions"].([]any)
+	if assert.Equal(t, 1, len(captions)) {
+		assert.Equal(t, "Welcome to the finals!", captions[0].(map[string]any)["Text"])
+	}
+	web.arena.ClearCaptions()
+	message = readWebsocketType(t, ws, "captions").(map[string]any)
+	assert.Empty(t, message["Captions"])
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web API through which the announcer or a captioner pushes text to the accessibility caption display.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+)
+
+// Accepts a new caption to show on the caption display.
+func (web *Web) captionsApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiCaptionsScope) {
+		return
+	}
+
+	var caption struct {
+		Text string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&caption); err != nil {
+		http.Error(w, "Invalid caption: "+err.Error(), 400)
+		return
+	}
+	if err := web.arena.AddCaption(caption.Text); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// Removes all captions from the caption display.
+func (web *Web) captionsClearApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiCaptionsScope) {
+		return
+	}
+
+	web.arena.ClearCaptions()
+	w.WriteHeader(204)
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCaptionsApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	setupApiV1Tokens(web)
+	web.arena.Database.CreateApiToken(&model.ApiToken{Name: "Captioner", Token: "secret", Scope: model.ApiCaptionsScope})
+
+	recorder := web.postHttpResponse("/api/captions", `{"Text": "Hello"}`)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/captions?apiKey=wrong", `{"Text": "Hello"}`)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/captions?apiKey=writetoken", `{"Text": "Hello"}`)
+	assert.Equal(t, 403, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "does not have the captions scope")
+	recorder = web.postHttpResponse("/api/captions/clear", "")
+	assert.Equal(t, 401, recorder.Code)
+
+	recorder = web.postHttpResponse("/api/captions?apiKey=secret", `{"Text": "Hello"}`)
+	assert.Equal(t, 204, recorder.Code)
+	recorder = web.postHttpResponse("/api/captions?apiKey=secret", `{"Text": ""}`)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "caption text cannot be blank")
+	recorder = web.postHttpResponse("/api/captions?apiKey=secret", "{")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid caption")
+
+	captions := web.arena.GetCaptions()
+	if assert.Equal(t, 1, len(captions)) {
+		assert.Equal(t, "Hello", captions[0].Text)
+	}
+	recorder = web.postHttpResponse("/api/captions/clear?apiKey=secret", "")
+	assert.Equal(t, 204, recorder.Code)
+	assert.Empty(t, web.arena.GetCaptions())
+}
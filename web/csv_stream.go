@@ -0,0 +1,52 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Helper for writing CSV reports out to the client as they are generated, rather than building them up in memory.
+
+package web
+
+import (
+	"encoding/csv"
+	"net/http"
+)
+
+// Number of rows to write between pushing the output out to the client.
+const csvStreamFlushRows = 100
+
+type csvStreamWriter struct {
+	writer         *csv.Writer
+	flusher        http.Flusher
+	rowsSinceFlush int
+}
+
+func newCsvStreamWriter(w http.ResponseWriter) *csvStreamWriter {
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	flusher, _ := w.(http.Flusher)
+	return &csvStreamWriter{writer: csv.NewWriter(w), flusher: flusher}
+}
+
+// Writes the given row, periodically pushing what has been written so far out to the client.
+func (streamWriter *csvStreamWriter) Write(row []string) error {
+	if err := streamWriter.writer.Write(row); err != nil {
+		return err
+	}
+	streamWriter.rowsSinceFlush++
+	if streamWriter.rowsSinceFlush >= csvStreamFlushRows {
+		return streamWriter.Flush()
+	}
+	return nil
+}
+
+// Pushes any buffered rows out to the client.
+func (streamWriter *csvStreamWriter) Flush() error {
+	streamWriter.writer.Flush()
+	streamWriter.rowsSinceFlush = 0
+	if err := streamWriter.writer.Error(); err != nil {
+		return err
+	}
+	if streamWriter.flusher != nil {
+		streamWriter.flusher.Flush()
+	}
+	return nil
+}
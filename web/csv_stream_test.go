@@ -0,0 +1,36 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCsvStreamWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := newCsvStreamWriter(recorder)
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+
+	for i := 1; i < csvStreamFlushRows; i++ {
+		assert.Nil(t, writer.Write([]string{strconv.Itoa(i), "row"}))
+	}
+	assert.False(t, recorder.Flushed)
+
+	// Check that the rows are pushed out to the client once enough have accumulated.
+	assert.Nil(t, writer.Write([]string{strconv.Itoa(csvStreamFlushRows), "row, with a comma"}))
+	assert.True(t, recorder.Flushed)
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	if assert.Equal(t, csvStreamFlushRows, len(lines)) {
+		assert.Equal(t, "1,row", lines[0])
+		assert.Equal(t, "100,\"row, with a comma\"", lines[csvStreamFlushRows-1])
+	}
+
+	assert.Nil(t, writer.Write([]string{"last"}))
+	assert.Nil(t, writer.Flush())
+	assert.True(t, strings.HasSuffix(recorder.Body.String(), "\nlast\n"))
+}
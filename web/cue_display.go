@@ -0,0 +1,58 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for a display that shows the human players or announcer cues about what is happening in the match, for
+// positions that don't have a view of the main screen.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+)
+
+// Renders the cue display.
+func (web *Web) cueDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.enforceDisplayConfiguration(w, r, map[string]string{"alliance": "red", "audience": "humanPlayer"}) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/cue_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+	}{web.arena.EventSettings}
+	err = template.ExecuteTemplate(w, "cue_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the cue display client to receive status updates.
+func (web *Web) cueDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	display, err := web.registerDisplay(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer web.arena.MarkDisplayDisconnected(display.DisplayConfiguration.Id)
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.MatchTimeNotifier,
+		web.arena.MatchCuesNotifier, web.arena.ReloadDisplaysNotifier)
+
+	// Keep reading from the websocket so that the client's clock synchronization requests get answered.
+	ws.HandleReads()
+}
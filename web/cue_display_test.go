@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCueDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/cues?displayId=1&alliance=blue&audience=announcer")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Cue Display - Untitled Event - Cheesy Arena")
+}
+
+func TestCueDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/displays/cues/websocket?displayId=1&alliance=red&audience=humanPlayer", nil,
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "matchTiming")
+	readWebsocketType(t, ws, "matchTime")
+	readWebsocketType(t, ws, "matchCues")
+
+	// Check that the cues are passed along as they change.
+	web.arena.MatchState = field.TeleopPeriod
+	web.arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd() + 5*time.Second)
+	web.arena.RedRealtimeScore.CurrentScore.AmpSpeaker.BankedAmpNotes = 2
+	web.arena.Update()
+	messages := readWebsocketMultiple(t, ws, 2)
+	if assert.Contains(t, messages, "matchCues") {
+		matchCues := messages["matchCues"].(map[string]any)
+		assert.Equal(t, "Ready to AMPLIFY", matchCues["Red"].([]any)[0].(map[string]any)["Text"])
+		assert.Nil(t, matchCues["Blue"])
+		assert.Equal(t, "ENDGAME: 5s left", matchCues["Field"].([]any)[0].(map[string]any)["Text"])
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for checking the health of the server and the field hardware it depends on, both as a page for the FTA's
+// pre-event checklist and as an endpoint for external monitoring.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	diagnosticOk      = "ok"
+	diagnosticWarning = "warning"
+	diagnosticFailed  = "failed"
+
+	// Free disk space below which the disk space check warns or fails, respectively.
+	diskSpaceWarningBytes = 1 << 30
+	diskSpaceFailureBytes = 100 << 20
+)
+
+// Time at which the server was started, for reporting its uptime.
+var serverStartTime = time.Now()
+
+// Outcome of a single self-diagnostic check.
+type diagnosticCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Reports the overall health of the server as JSON, with a status code of 503 if any check has failed. Doesn't require
+// authentication so that it can be polled by external monitoring.
+func (web *Web) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	checks := web.runDiagnosticChecks()
+	status := overallDiagnosticStatus(checks)
+	data := struct {
+		Status string            `json:"status"`
+		Checks []diagnosticCheck `json:"checks"`
+	}{status, checks}
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if status == diagnosticFailed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Shows the diagnostics page.
+func (web *Web) diagnosticsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.FtaRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_diagnostics.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	checks := web.runDiagnosticChecks()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	data := struct {
+		*model.EventSettings
+		Status      string
+		Checks      []diagnosticCheck
+		Uptime      time.Duration
+		GoVersion   string
+		HeapAllocMb float64
+		CheckedAt   time.Time
+	}{
+		web.arena.EventSettings,
+		overallDiagnosticStatus(checks),
+		checks,
+		time.Since(serverStartTime).Round(time.Second),
+		runtime.Version(),
+		float64(memStats.HeapAlloc) / (1 << 20),
+		time.Now(),
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Runs all of the self-diagnostic checks and returns their outcomes.
+func (web *Web) runDiagnosticChecks() []diagnosticCheck {
+	return []diagnosticCheck{
+		web.checkDatabase(),
+		checkDiskSpace(),
+		web.checkAccessPoint(),
+		web.checkPlc(),
+		web.checkTemplates(),
+		{
+			Name:   "Runtime",
+			Status: diagnosticOk,
+			Detail: fmt.Sprintf(
+				"%d goroutines, %d open websockets", runtime.NumGoroutine(), websocket.OpenConnectionCount(),
+			),
+		},
+	}
+}
+
+// Verifies that the database can be read from, and reports how long it took.
+func (web *Web) checkDatabase() diagnosticCheck {
+	check := diagnosticCheck{Name: "Database"}
+	startTime := time.Now()
+	if _, err := web.arena.Database.GetEventSettings(); err != nil {
+		check.Status = diagnosticFailed
+		check.Detail = fmt.Sprintf("Failed to read event settings: %v", err)
+		return check
+	}
+	check.Status = diagnosticOk
+	check.Detail = fmt.Sprintf("Read event settings in %v", time.Since(startTime).Round(time.Microsecond))
+	return check
+}
+
+// Verifies that the disk holding the database, backups and logs has enough free space left.
+func checkDiskSpace() diagnosticCheck {
+	check := diagnosticCheck{Name: "Disk space"}
+	freeBytes, totalBytes, err := getDiskSpace(model.BaseDir)
+	if err != nil {
+		check.Status = diagnosticFailed
+		check.Detail = fmt.Sprintf("Failed to determine free disk space: %v", err)
+		return check
+	}
+	switch {
+	case freeBytes < diskSpaceFailureBytes:
+		check.Status = diagnosticFailed
+	case freeBytes < diskSpaceWarningBytes:
+		check.Status = diagnosticWarning
+	default:
+		check.Status = diagnosticOk
+	}
+	check.Detail = fmt.Sprintf("%.1f GB free of %.1f GB", float64(freeBytes)/(1<<30), float64(totalBytes)/(1<<30))
+	return check
+}
+
+// Reports whether the field access point is reachable and configured.
+func (web *Web) checkAccessPoint() diagnosticCheck {
+	check := diagnosticCheck{Name: "Access point"}
+	if !web.arena.EventSettings.NetworkSecurityEnabled {
+		check.Status = diagnosticOk
+		check.Detail = "Not managed; network security is disabled"
+		return check
+	}
+	apStatus := web.arena.AccessPointStatus()
+	switch apStatus {
+	case "ACTIVE":
+		check.Status = diagnosticOk
+	case "CONFIGURING":
+		check.Status = diagnosticWarning
+	default:
+		check.Status = diagnosticFailed
+	}
+	check.Detail = fmt.Sprintf("Status is %s", apStatus)
+	return check
+}
+
+// Reports whether the field PLC is connected and responding.
+func (web *Web) checkPlc() diagnosticCheck {
+	check := diagnosticCheck{Name: "PLC"}
+	switch {
+	case !web.arena.Plc.IsEnabled():
+		check.Status = diagnosticOk
+		check.Detail = "Not configured"
+	case web.arena.Plc.IsHealthy():
+		check.Status = diagnosticOk
+		check.Detail = "Connected"
+	default:
+		check.Status = diagnosticFailed
+		check.Detail = "Not responding"
+	}
+	return check
+}
+
+// Verifies that every page template parses, to catch a broken template before someone navigates to it.
+func (web *Web) checkTemplates() diagnosticCheck {
+	check := diagnosticCheck{Name: "Templates"}
+	templatePaths, err := filepath.Glob(filepath.Join(model.BaseDir, "templates", "*.html"))
+	if err != nil || len(templatePaths) == 0 {
+		check.Status = diagnosticFailed
+		check.Detail = "No templates found"
+		return check
+	}
+	var brokenTemplates []string
+	for _, templatePath := range templatePaths {
+		relativePath := filepath.ToSlash(filepath.Join("templates", filepath.Base(templatePath)))
+		if _, err = web.parseFiles(relativePath); err != nil {
+			brokenTemplates = append(brokenTemplates, filepath.Base(templatePath))
+		}
+	}
+	if len(brokenTemplates) > 0 {
+		check.Status = diagnosticFailed
+		check.Detail = fmt.Sprintf("Failed to parse %s", strings.Join(brokenTemplates, ", "))
+		return check
+	}
+	check.Status = diagnosticOk
+	check.Detail = fmt.Sprintf("Parsed %d templates", len(templatePaths))
+	return check
+}
+
+// Returns the worst status among the given checks.
+func overallDiagnosticStatus(checks []diagnosticCheck) string {
+	status := diagnosticOk
+	for _, check := range checks {
+		if check.Status == diagnosticFailed {
+			return diagnosticFailed
+		}
+		if check.Status == diagnosticWarning {
+			status = diagnosticWarning
+		}
+	}
+	return status
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHealthz(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Check that the endpoint doesn't require authentication.
+	web.arena.EventSettings.AdminPassword = "admin"
+	recorder := web.getHttpResponse("/healthz")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var health struct {
+		Status string
+		Checks []diagnosticCheck
+	}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+	assert.NotEqual(t, diagnosticFailed, health.Status)
+	checksByName := make(map[string]diagnosticCheck)
+	for _, check := range health.Checks {
+		checksByName[check.Name] = check
+	}
+	assert.Equal(t, diagnosticOk, checksByName["Database"].Status)
+	assert.Equal(t, diagnosticOk, checksByName["Templates"].Status)
+	assert.Equal(t, "Not configured", checksByName["PLC"].Detail)
+	assert.Equal(t, "Not managed; network security is disabled", checksByName["Access point"].Detail)
+
+	// Check that a failed check is reported with an error status code.
+	web.arena.Plc.SetAddress("10.0.100.40")
+	recorder = web.getHttpResponse("/healthz")
+	assert.Equal(t, 503, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "\"status\": \"failed\"")
+	assert.Contains(t, recorder.Body.String(), "Not responding")
+}
+
+func TestSetupDiagnostics(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/diagnostics")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Diagnostics - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "Disk space")
+	assert.Contains(t, recorder.Body.String(), "open websockets")
+}
+
+func TestOverallDiagnosticStatus(t *testing.T) {
+	assert.Equal(t, diagnosticOk, overallDiagnosticStatus(nil))
+	assert.Equal(
+		t,
+		diagnosticWarning,
+		overallDiagnosticStatus([]diagnosticCheck{{Status: diagnosticOk}, {Status: diagnosticWarning}}),
+	)
+	assert.Equal(
+		t,
+		diagnosticFailed,
+		overallDiagnosticStatus([]diagnosticCheck{{Status: diagnosticFailed}, {Status: diagnosticWarning}}),
+	)
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Disk space lookup for Unix-like platforms.
+
+//go:build !windows
+
+package web
+
+import "syscall"
+
+// Returns the number of bytes available to the server and the total size of the filesystem holding the given path.
+func getDiskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), stat.Blocks * uint64(stat.Bsize), nil
+}
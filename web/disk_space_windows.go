@@ -0,0 +1,34 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Disk space lookup for Windows.
+
+//go:build windows
+
+package web
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// Returns the number of bytes available to the server and the total size of the volume holding the given path.
+func getDiskSpace(path string) (uint64, uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var freeBytes, totalBytes, totalFreeBytes uint64
+	result, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&freeBytes)),
+		uintptr(unsafe.Pointer(&totalBytes)),
+		uintptr(unsafe.Pointer(&totalFreeBytes)),
+	)
+	if result == 0 {
+		return 0, 0, err
+	}
+	return freeBytes, totalBytes, nil
+}
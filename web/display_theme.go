@@ -0,0 +1,144 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for applying the event's theme to the displays and for uploading the assets that it uses.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Directory under which uploaded theme assets are stored and served from.
+const themeAssetDir = "static/img/theme"
+
+// File extensions accepted for each kind of theme asset.
+var themeAssetExtensions = map[string][]string{
+	"background": {".gif", ".jpeg", ".jpg", ".png", ".svg", ".webp"},
+	"font":       {".otf", ".ttf", ".woff", ".woff2"},
+	"logo":       {".gif", ".jpeg", ".jpg", ".png", ".svg", ".webp"},
+}
+
+var themeColorRe = regexp.MustCompile("^#[0-9a-fA-F]{6}$")
+
+// Generates the stylesheet that overrides the built-in colors, fonts and images of the displays with those of the
+// event's theme.
+func (web *Web) themeCssHandler(w http.ResponseWriter, r *http.Request) {
+	eventSettings := web.arena.EventSettings
+	var css strings.Builder
+	if eventSettings.ThemeFontFile != "" {
+		fmt.Fprintf(
+			&css,
+			"@font-face {\n  font-family: \"ThemeFont\";\n  src: url(\"%s\");\n}\n",
+			themeAssetUrl(eventSettings.ThemeFontFile),
+		)
+	}
+	css.WriteString(":root {\n")
+	fmt.Fprintf(&css, "  --theme-accent-color: %s;\n", eventSettings.ThemeAccentColor)
+	if eventSettings.ThemeFontFile != "" {
+		css.WriteString("  --theme-font: \"ThemeFont\";\n  --theme-font-bold: \"ThemeFont\";\n")
+	}
+	if eventSettings.ThemeBackgroundFile != "" {
+		fmt.Fprintf(
+			&css, "  --theme-background-image: url(\"%s\");\n", themeAssetUrl(eventSettings.ThemeBackgroundFile),
+		)
+	}
+	css.WriteString("}\n")
+
+	w.Header().Set("Content-Type", "text/css")
+	w.Header().Set("Cache-Control", "no-cache")
+	if _, err := io.WriteString(w, css.String()); err != nil {
+		handleWebErr(w, err)
+	}
+}
+
+// Saves an uploaded logo, background image or font and makes it part of the display theme.
+func (web *Web) settingsThemeAssetPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	asset := r.PostFormValue("asset")
+	fileName, err := saveUploadedThemeAsset(r, asset)
+	if err != nil {
+		web.renderSettings(w, r, err.Error())
+		return
+	}
+
+	eventSettings := web.arena.SavedEventSettings()
+	switch asset {
+	case "background":
+		eventSettings.ThemeBackgroundFile = fileName
+	case "font":
+		eventSettings.ThemeFontFile = fileName
+	case "logo":
+		eventSettings.ThemeLogoFile = fileName
+	}
+	if err = web.arena.Database.UpdateEventSettings(eventSettings); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// The arena has the displays reload to pick up the new asset once the change takes effect.
+	if err = web.arena.LoadSettings(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, "/setup/settings", 303)
+}
+
+// Writes the uploaded file to the theme asset directory after checking that it suits the given kind of asset, and
+// returns its file name.
+func saveUploadedThemeAsset(r *http.Request, asset string) (string, error) {
+	if _, ok := themeAssetExtensions[asset]; !ok {
+		return "", fmt.Errorf("Invalid theme asset type '%s'.", asset)
+	}
+	file, fileHeader, err := r.FormFile("themeFile")
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return "", fmt.Errorf("No theme asset file was specified.")
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fileName := filepath.Base(fileHeader.Filename)
+	if !isValidThemeAssetFile(asset, fileName) {
+		return "", fmt.Errorf("File '%s' is not a supported type of theme %s.", fileName, asset)
+	}
+	assetDir := filepath.Join(model.BaseDir, themeAssetDir)
+	if err = os.MkdirAll(assetDir, 0755); err != nil {
+		return "", err
+	}
+	assetFile, err := os.Create(filepath.Join(assetDir, fileName))
+	if err != nil {
+		return "", err
+	}
+	defer assetFile.Close()
+	if _, err = io.Copy(assetFile, file); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// Returns true if the given file name refers directly to a file in the theme asset directory and has an extension
+// suited to the given kind of asset.
+func isValidThemeAssetFile(asset, fileName string) bool {
+	return fileName == filepath.Base(fileName) &&
+		slices.Contains(themeAssetExtensions[asset], strings.ToLower(filepath.Ext(fileName)))
+}
+
+// Returns the URL from which the given uploaded theme asset is served.
+func themeAssetUrl(fileName string) string {
+	return "/" + themeAssetDir + "/" + url.PathEscape(fileName)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"bytes"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestThemeCss(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/theme.css")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/css", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "--theme-accent-color: #ffcc00;")
+	assert.NotContains(t, recorder.Body.String(), "@font-face")
+	assert.NotContains(t, recorder.Body.String(), "--theme-background-image")
+
+	recorder = web.postHttpResponse("/setup/settings", "themeAccentColor=#3366CC&themeFontFile=brand.woff2&"+
+		"themeBackgroundFile=my backdrop.png")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/displays/theme.css")
+	assert.Contains(t, recorder.Body.String(), "--theme-accent-color: #3366cc;")
+	assert.Contains(t, recorder.Body.String(), "src: url(\"/static/img/theme/brand.woff2\");")
+	assert.Contains(t, recorder.Body.String(), "--theme-font: \"ThemeFont\";")
+	assert.Contains(t, recorder.Body.String(), "url(\"/static/img/theme/my%20backdrop.png\")")
+
+	recorder = web.postHttpResponse("/setup/settings", "themeAccentColor=red")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Theme accent color must be of the form #rrggbb.")
+	recorder = web.postHttpResponse("/setup/settings", "themeLogoFile=../../secret.png")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "is not a valid theme logo file")
+	recorder = web.postHttpResponse("/setup/settings", "themeFontFile=brand.png")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "is not a valid theme font file")
+}
+
+func TestSetupSettingsThemeAsset(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/logo?displayId=1&message=")
+	assert.Contains(t, recorder.Body.String(), "/static/img/alliance-station-logo.png")
+
+	// Point the asset directory elsewhere for the upload, so as not to leave the file behind in the source tree.
+	baseDir := model.BaseDir
+	model.BaseDir = t.TempDir()
+	recorder = web.postThemeAsset("logo", "brand.svg", "logo data")
+	assert.Equal(t, 303, recorder.Code)
+	logo, err := os.ReadFile(filepath.Join(model.BaseDir, "static/img/theme/brand.svg"))
+	assert.Nil(t, err)
+	assert.Equal(t, "logo data", string(logo))
+	model.BaseDir = baseDir
+	assert.Equal(t, "brand.svg", web.arena.EventSettings.ThemeLogoFile)
+
+	recorder = web.getHttpResponse("/displays/logo?displayId=1&message=")
+	assert.Contains(t, recorder.Body.String(), "/static/img/theme/brand.svg")
+	assert.NotContains(t, recorder.Body.String(), "/static/img/alliance-station-logo.png")
+
+	recorder = web.postThemeAsset("font", "brand.svg", "font data")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "not a supported type of theme font")
+	recorder = web.postThemeAsset("favicon", "brand.ico", "icon data")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid theme asset type")
+	recorder = web.postHttpResponse("/setup/settings/theme_asset", "asset=logo")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No theme asset file was specified.")
+	assert.Equal(t, "", web.arena.EventSettings.ThemeFontFile)
+}
+
+// Posts a request to upload the given file as the given kind of theme asset.
+func (web *Web) postThemeAsset(asset, fileName, contents string) *httptest.ResponseRecorder {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("asset", asset)
+	part, _ := writer.CreateFormFile("themeFile", fileName)
+	part.Write([]byte(contents))
+	writer.Close()
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/setup/settings/theme_asset", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	web.newHandler().ServeHTTP(recorder, req)
+	return recorder
+}
@@ -56,7 +56,7 @@ func (web *Web) enforceDisplayConfiguration(w http.ResponseWriter, r *http.Reque
 
 // Constructs, registers, and returns the display object for the given incoming websocket request.
 func (web *Web) registerDisplay(r *http.Request) (*field.Display, error) {
-	displayConfig, err := field.DisplayFromUrl(r.URL.Path, r.URL.Query())
+	displayConfig, err := field.DisplayFromUrl(strings.TrimPrefix(r.URL.Path, web.arena.PathPrefix()), r.URL.Query())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,134 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the emcee display, which shows the script for introducing the current and upcoming matches.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+	"strconv"
+)
+
+// Renders the emcee display.
+func (web *Web) emceeDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.enforceDisplayConfiguration(w, r, map[string]string{"numMatches": "3"}) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/emcee_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+	}{web.arena.EventSettings}
+	err = template.ExecuteTemplate(w, "emcee_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the emcee display to be told when to refresh the scripts.
+func (web *Web) emceeDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	display, err := web.registerDisplay(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer web.arena.MarkDisplayDisconnected(display.DisplayConfiguration.Id)
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
+	ws.HandleNotifiers(display.Notifier, web.arena.MatchLoadNotifier, web.arena.ScorePostedNotifier,
+		web.arena.ReloadDisplaysNotifier)
+}
+
+// Generates a JSON dump of the emcee scripts for the current match and the given number of matches in total, which
+// defaults to just the current one.
+func (web *Web) emceeScriptsApiHandler(w http.ResponseWriter, r *http.Request) {
+	numMatches := 1
+	if numMatchesParam := r.URL.Query().Get("numMatches"); numMatchesParam != "" {
+		var err error
+		if numMatches, err = strconv.Atoi(numMatchesParam); err != nil || numMatches < 1 {
+			handleWebErr(w, fmt.Errorf("Invalid number of matches '%s'.", numMatchesParam))
+			return
+		}
+	}
+
+	matches := []model.Match{*web.arena.CurrentMatch}
+	if web.arena.CurrentMatch.Type != model.Test {
+		allMatches, err := web.arena.Database.GetMatchesByType(web.arena.CurrentMatch.Type, false)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, match := range allMatches {
+			if len(matches) >= numMatches {
+				break
+			}
+			if match.IsComplete() || match.TypeOrder <= web.arena.CurrentMatch.TypeOrder {
+				continue
+			}
+			matches = append(matches, match)
+		}
+	}
+
+	scripts := make([]*tournament.EmceeScript, len(matches))
+	for i := range matches {
+		var err error
+		if scripts[i], err = tournament.GenerateEmceeScript(web.arena.Database, &matches[i]); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	writeEmceeScriptsJson(w, scripts)
+}
+
+// Generates a JSON dump of the emcee script for the given match.
+func (web *Web) emceeScriptApiHandler(w http.ResponseWriter, r *http.Request) {
+	matchId, _ := strconv.Atoi(r.PathValue("matchId"))
+	match, err := web.arena.Database.GetMatchById(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		http.Error(w, fmt.Sprintf("Error: No such match: %d", matchId), 404)
+		return
+	}
+	script, err := tournament.GenerateEmceeScript(web.arena.Database, match)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	writeEmceeScriptsJson(w, script)
+}
+
+func writeEmceeScriptsJson(w http.ResponseWriter, data any) {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestEmceeDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/emcee?displayId=1&numMatches=2")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Emcee Display - Untitled Event - Cheesy Arena")
+}
+
+func TestEmceeDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/emcee/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "scorePosted")
+}
+
+func TestEmceeScriptsApi(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs", City: "San Jose"})
+	statuses := []game.MatchStatus{game.RedWonMatch, game.MatchScheduled, game.TieMatch, game.MatchScheduled}
+	for i, status := range statuses {
+		web.arena.Database.CreateMatch(&model.Match{
+			Type:      model.Qualification,
+			TypeOrder: i + 1,
+			ShortName: "Q" + strconv.Itoa(i+1),
+			LongName:  "Qualification " + strconv.Itoa(i+1),
+			Red1:      254,
+			Blue1:     1114 + i,
+			Status:    status,
+		})
+	}
+
+	// Check that only the current match is given while a test match is loaded.
+	recorder := web.getHttpResponse("/api/emcee_scripts?numMatches=3")
+	assert.Equal(t, 200, recorder.Code)
+	var scripts []tournament.EmceeScript
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &scripts))
+	if assert.Equal(t, 1, len(scripts)) {
+		assert.Equal(t, "Test Match", scripts[0].LongName)
+	}
+
+	// Check that completed matches are skipped when looking ahead.
+	match, _ := web.arena.Database.GetMatchById(2)
+	assert.Nil(t, web.arena.LoadMatch(match))
+	recorder = web.getHttpResponse("/api/emcee_scripts?numMatches=3")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &scripts))
+	if assert.Equal(t, 2, len(scripts)) {
+		assert.Equal(t, "Q2", scripts[0].ShortName)
+		assert.Equal(t, "Q4", scripts[1].ShortName)
+		assert.Contains(t, scripts[0].Lines[1], "team 254, The Cheesy Poofs, from San Jose")
+		assert.Equal(t, "On the blue alliance: team 1117.", scripts[1].Lines[2])
+	}
+	recorder = web.getHttpResponse("/api/emcee_scripts")
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &scripts))
+	assert.Equal(t, 1, len(scripts))
+	recorder = web.getHttpResponse("/api/emcee_scripts?numMatches=0")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid number of matches '0'.")
+
+	recorder = web.getHttpResponse("/api/emcee_scripts/1")
+	assert.Equal(t, 200, recorder.Code)
+	var script tournament.EmceeScript
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &script))
+	assert.Equal(t, "Coming up next: Qualification 1!", script.Lines[0])
+	recorder = web.getHttpResponse("/api/emcee_scripts/12345")
+	assert.Equal(t, 404, recorder.Code)
+}
@@ -6,17 +6,17 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
 	"io"
-	"log"
 	"net/http"
 )
 
 // Renders the field monitor display.
 func (web *Web) fieldMonitorDisplayHandler(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Query().Get("fta") == "true" && !web.userIsAdmin(w, r) {
+	if r.URL.Query().Get("fta") == "true" && !web.userHasRole(w, r, model.FtaRole) {
 		return
 	}
 
@@ -42,7 +42,7 @@ func (web *Web) fieldMonitorDisplayHandler(w http.ResponseWriter, r *http.Reques
 // The websocket endpoint for the field monitor display client to receive status updates.
 func (web *Web) fieldMonitorDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
 	isFta := r.URL.Query().Get("fta") == "true"
-	if isFta && !web.userIsAdmin(w, r) {
+	if isFta && !web.userHasRole(w, r, model.FtaRole) {
 		return
 	}
 
@@ -63,7 +63,7 @@ func (web *Web) fieldMonitorDisplayWebsocketHandler(w http.ResponseWriter, r *ht
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
 	go ws.HandleNotifiers(web.arena.MatchTimingNotifier, display.Notifier, web.arena.ArenaStatusNotifier,
 		web.arena.EventStatusNotifier, web.arena.RealtimeScoreNotifier, web.arena.MatchTimeNotifier,
-		web.arena.MatchLoadNotifier, web.arena.ReloadDisplaysNotifier)
+		web.arena.MatchLoadNotifier, web.arena.DisplayAlertsNotifier, web.arena.ReloadDisplaysNotifier)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
 	for {
@@ -73,7 +73,7 @@ func (web *Web) fieldMonitorDisplayWebsocketHandler(w http.ResponseWriter, r *ht
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
@@ -41,6 +41,7 @@ func TestFieldMonitorDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "realtimeScore")
 	readWebsocketType(t, ws, "matchTime")
 	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "displayAlerts")
 
 	// Should not be able to update team notes.
 	ws.Write("updateTeamNotes", map[string]any{"station": "B1", "notes": "Bypassed in M1"})
@@ -69,6 +70,7 @@ func TestFieldMonitorFtaDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "realtimeScore")
 	readWebsocketType(t, ws, "matchTime")
 	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "displayAlerts")
 
 	// Should not be able to update team notes.
 	ws.Write("updateTeamNotes", map[string]any{"station": "B1", "notes": "Bypassed in M1"})
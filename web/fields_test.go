@@ -0,0 +1,69 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func setupTestWebWithFields(t *testing.T) (*Web, *field.Arena) {
+	game.MatchTiming.WarmupDurationSec = 3
+	game.MatchTiming.PauseDurationSec = 2
+	arena := field.SetupTestArena(t, "web")
+	field2, err := arena.AddField()
+	assert.Nil(t, err)
+	return NewWeb(arena), field2
+}
+
+func TestFieldPages(t *testing.T) {
+	web, _ := setupTestWebWithFields(t)
+
+	recorder := web.getHttpResponse("/match_play")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "href=\"/fields/2/match_play\"")
+	assert.Contains(t, recorder.Body.String(), "'/match_review/current/edit'")
+
+	recorder = web.getHttpResponse("/fields/2/match_play")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "'/fields/2/match_review/current/edit'")
+
+	recorder = web.getHttpResponse("/fields/2/match_play/score_review")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "href=\"/fields/2/match_play\"")
+
+	// Check that the first field's pages are sent back to their usual URLs and that unknown fields aren't served.
+	recorder = web.getHttpResponse("/fields/1/match_play?foo=bar")
+	assert.Equal(t, 307, recorder.Code)
+	assert.Equal(t, "/match_play?foo=bar", recorder.Header().Get("Location"))
+	assert.Equal(t, 404, web.getHttpResponse("/fields/3/match_play").Code)
+	assert.Equal(t, 404, web.getHttpResponse("/fields/2/setup/settings").Code)
+}
+
+func TestFieldDisplayWebsocket(t *testing.T) {
+	web, field2 := setupTestWebWithFields(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/fields/2/displays/audience/websocket?displayId=5", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketType(t, ws, "displayConfiguration")
+
+	// Check that the display is attached to the second field and is sent back to that field's page.
+	if assert.Contains(t, field2.Displays, "5") {
+		assert.Equal(t, "/fields/2/displays/audience?displayId=5", field2.Displays["5"].ToUrl())
+	}
+	assert.NotContains(t, web.arena.Displays, "5")
+
+	// Check that the display follows the second field's audience display mode.
+	readWebsocketMultiple(t, ws, 11)
+	field2.SetAudienceDisplayMode("score")
+	assert.Equal(t, "score", readWebsocketType(t, ws, "audienceDisplayMode"))
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for the pit kiosk where teams sign up to fill open practice match slots.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type fillerLineEntry struct {
+	model.FillerTeam
+	Nickname string
+}
+
+// Shows the filler line kiosk page.
+func (web *Web) fillerLineGetHandler(w http.ResponseWriter, r *http.Request) {
+	web.renderFillerLine(w, r, "")
+}
+
+// Adds a team to the back of the filler line.
+func (web *Web) fillerLinePostHandler(w http.ResponseWriter, r *http.Request) {
+	teamId, err := strconv.Atoi(r.PostFormValue("teamId"))
+	if err != nil {
+		web.renderFillerLine(w, r, "Invalid team number.")
+		return
+	}
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if team == nil {
+		web.renderFillerLine(w, r, fmt.Sprintf("Team %d is not registered for this event.", teamId))
+		return
+	}
+
+	fillerTeams, err := web.arena.Database.GetAllFillerTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	for _, fillerTeam := range fillerTeams {
+		if fillerTeam.TeamId == teamId {
+			web.renderFillerLine(w, r, fmt.Sprintf("Team %d is already in the filler line.", teamId))
+			return
+		}
+	}
+
+	if err = web.arena.Database.CreateFillerTeam(
+		&model.FillerTeam{TeamId: teamId, SignedUpAt: time.Now()},
+	); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	http.Redirect(w, r, "/panels/filler_line", 303)
+}
+
+// Removes a team from the filler line.
+func (web *Web) fillerLineDeletePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
+		return
+	}
+
+	fillerTeamId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if err = web.arena.Database.DeleteFillerTeam(fillerTeamId); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	http.Redirect(w, r, "/panels/filler_line", 303)
+}
+
+func (web *Web) renderFillerLine(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	fillerTeams, err := web.arena.Database.GetAllFillerTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	entries := make([]fillerLineEntry, len(fillerTeams))
+	for i, fillerTeam := range fillerTeams {
+		entries[i].FillerTeam = fillerTeam
+		team, err := web.arena.Database.GetTeamById(fillerTeam.TeamId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if team != nil {
+			entries[i].Nickname = team.Nickname
+		}
+	}
+
+	template, err := web.parseFiles("templates/filler_line.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		FillerTeams  []fillerLineEntry
+		ErrorMessage string
+	}{web.arena.EventSettings, entries, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFillerLine(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"})
+
+	recorder := web.getHttpResponse("/panels/filler_line")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Practice Filler Line")
+
+	recorder = web.postHttpResponse("/panels/filler_line", "teamId=1114")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/panels/filler_line", "teamId=254")
+	assert.Equal(t, 303, recorder.Code)
+	fillerTeams, _ := web.arena.Database.GetAllFillerTeams()
+	if assert.Equal(t, 2, len(fillerTeams)) {
+		assert.Equal(t, 1114, fillerTeams[0].TeamId)
+		assert.Equal(t, 254, fillerTeams[1].TeamId)
+	}
+	recorder = web.getHttpResponse("/panels/filler_line")
+	assert.Contains(t, recorder.Body.String(), "Simbotics")
+
+	// Check the validation errors.
+	recorder = web.postHttpResponse("/panels/filler_line", "teamId=1114")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "already in the filler line")
+	recorder = web.postHttpResponse("/panels/filler_line", "teamId=9999")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "not registered")
+	recorder = web.postHttpResponse("/panels/filler_line", "teamId=abc")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid team number")
+
+	recorder = web.postHttpResponse("/panels/filler_line/1/delete", "")
+	assert.Equal(t, 303, recorder.Code)
+	fillerTeams, _ = web.arena.Database.GetAllFillerTeams()
+	if assert.Equal(t, 1, len(fillerTeams)) {
+		assert.Equal(t, 254, fillerTeams[0].TeamId)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Serving of the web interface over HTTPS, and handling of the headers added by a reverse proxy in front of it.
+
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type secureRequestContextKey struct{}
+
+// Configures the web interface to also be served over HTTPS on the given port using the given certificate and key
+// files. Once enabled, logging in is only possible over HTTPS and the session cookie is never sent over plain HTTP, while
+// unauthenticated clients such as the displays and driver stations can carry on using plain HTTP.
+func (web *Web) EnableHttps(port int, certFile, keyFile string) error {
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	web.httpsPort = port
+	web.tlsCertFile = certFile
+	web.tlsKeyFile = keyFile
+	return nil
+}
+
+// Configures the web interface to honor the X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto headers on requests
+// coming from the given comma-separated list of reverse proxy IP addresses or CIDR ranges.
+func (web *Web) SetTrustedProxies(proxies string) error {
+	var trustedProxies []*net.IPNet
+	for _, proxy := range strings.Split(proxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy address '%s'", proxy)
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+	web.trustedProxies = trustedProxies
+	return nil
+}
+
+// Wraps the given handler to replace the client address and host of requests coming through a trusted reverse proxy
+// with the ones the proxy received, so that logging, display registration and the websocket origin check all see the
+// original request.
+func (web *Web) applyForwardedHeaders(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secure := r.TLS != nil
+		if web.isTrustedProxy(r.RemoteAddr) {
+			if clientIp := web.getForwardedClientIp(r.Header.Values("X-Forwarded-For")); clientIp != "" {
+				r.RemoteAddr = net.JoinHostPort(clientIp, "0")
+			}
+			if host := lastHeaderValue(r.Header.Get("X-Forwarded-Host")); host != "" {
+				r.Host = host
+			}
+			if proto := lastHeaderValue(r.Header.Get("X-Forwarded-Proto")); proto != "" {
+				secure = strings.EqualFold(proto, "https")
+			}
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), secureRequestContextKey{}, secure)))
+	})
+}
+
+// Returns true if the request reached either the web server or the reverse proxy in front of it over HTTPS.
+func isSecureRequest(r *http.Request) bool {
+	secure, ok := r.Context().Value(secureRequestContextKey{}).(bool)
+	return ok && secure || r.TLS != nil
+}
+
+// Redirects the request to the HTTPS version of the same page if HTTPS is enabled and the request arrived over plain
+// HTTP. Returns true if the request was redirected.
+func (web *Web) redirectToHttps(w http.ResponseWriter, r *http.Request) bool {
+	if web.httpsPort == 0 || isSecureRequest(r) {
+		return false
+	}
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	if web.httpsPort != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(web.httpsPort))
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), 307)
+	return true
+}
+
+// Returns true if the given remote address belongs to one of the trusted reverse proxies.
+func (web *Web) isTrustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, trustedProxy := range web.trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the address of the original client from the given X-Forwarded-For headers, which is the last address that
+// wasn't added by one of the trusted proxies, or an empty string if there are no valid addresses.
+func (web *Web) getForwardedClientIp(forwardedForHeaders []string) string {
+	var addresses []string
+	for _, header := range forwardedForHeaders {
+		for _, address := range strings.Split(header, ",") {
+			addresses = append(addresses, strings.TrimSpace(address))
+		}
+	}
+	clientIp := ""
+	for i := len(addresses) - 1; i >= 0; i-- {
+		if net.ParseIP(addresses[i]) == nil {
+			break
+		}
+		clientIp = addresses[i]
+		if !web.isTrustedProxy(clientIp) {
+			break
+		}
+	}
+	return clientIp
+}
+
+// Returns the value added by the nearest proxy to a header that may contain a comma-separated list of values.
+func lastHeaderValue(header string) string {
+	values := strings.Split(header, ",")
+	return strings.TrimSpace(values[len(values)-1])
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnableHttps(t *testing.T) {
+	web := setupTestWeb(t)
+
+	certFile, keyFile := writeTestCertificate(t)
+	assert.Nil(t, web.EnableHttps(8443, certFile, keyFile))
+	assert.Equal(t, 8443, web.httpsPort)
+
+	err := web.EnableHttps(8443, keyFile, certFile)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed to load TLS certificate")
+	}
+}
+
+func TestSetTrustedProxies(t *testing.T) {
+	web := setupTestWeb(t)
+
+	assert.Nil(t, web.SetTrustedProxies(""))
+	assert.Empty(t, web.trustedProxies)
+
+	assert.Nil(t, web.SetTrustedProxies("10.0.100.5, 192.168.0.0/24,::1"))
+	assert.Equal(t, 3, len(web.trustedProxies))
+	assert.True(t, web.isTrustedProxy("10.0.100.5:1234"))
+	assert.False(t, web.isTrustedProxy("10.0.100.6:1234"))
+	assert.True(t, web.isTrustedProxy("192.168.0.77:1234"))
+	assert.True(t, web.isTrustedProxy("[::1]:1234"))
+	assert.False(t, web.isTrustedProxy("bogus"))
+
+	err := web.SetTrustedProxies("10.0.100.5,blorpy")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid trusted proxy address 'blorpy/128'", err.Error())
+	}
+}
+
+func TestForwardedHeaders(t *testing.T) {
+	web := setupTestWeb(t)
+	var handledRequest *http.Request
+	handler := web.applyForwardedHeaders(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { handledRequest = r }),
+	)
+	serve := func(remoteAddr string, headers map[string]string) {
+		req := httptest.NewRequest("GET", "http://10.0.100.5:8080/setup/settings", nil)
+		req.RemoteAddr = remoteAddr
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	proxyHeaders := map[string]string{
+		"X-Forwarded-For":   "1.2.3.4, 10.0.0.50, 192.168.0.10",
+		"X-Forwarded-Host":  "arena.example.com",
+		"X-Forwarded-Proto": "https",
+	}
+
+	// Check that the headers are ignored when they don't come from a trusted proxy.
+	serve("10.0.0.50:5555", proxyHeaders)
+	assert.Equal(t, "10.0.0.50:5555", handledRequest.RemoteAddr)
+	assert.Equal(t, "10.0.100.5:8080", handledRequest.Host)
+	assert.False(t, isSecureRequest(handledRequest))
+
+	// Check that the headers are applied when they do, skipping over any other trusted proxies in the chain.
+	assert.Nil(t, web.SetTrustedProxies("10.0.0.50,192.168.0.0/24"))
+	serve("10.0.0.50:5555", proxyHeaders)
+	assert.Equal(t, "1.2.3.4:0", handledRequest.RemoteAddr)
+	assert.Equal(t, "arena.example.com", handledRequest.Host)
+	assert.True(t, isSecureRequest(handledRequest))
+
+	// Check that the nearest untrusted address is used rather than any spoofed by the client.
+	serve("10.0.0.50:5555", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4"})
+	assert.Equal(t, "1.2.3.4:0", handledRequest.RemoteAddr)
+	assert.Equal(t, "10.0.100.5:8080", handledRequest.Host)
+	assert.False(t, isSecureRequest(handledRequest))
+}
+
+func TestLoginOverHttps(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.AdminPassword = "admin"
+	certFile, keyFile := writeTestCertificate(t)
+	assert.Nil(t, web.EnableHttps(8443, certFile, keyFile))
+	assert.Nil(t, web.SetTrustedProxies("192.0.2.1"))
+
+	// Check that logging in over plain HTTP redirects to HTTPS.
+	recorder := web.getHttpResponse("http://10.0.100.5:8080/login?redirect=%2Fmatch_play")
+	assert.Equal(t, 307, recorder.Code)
+	assert.Equal(t, "https://10.0.100.5:8443/login?redirect=%2Fmatch_play", recorder.Header().Get("Location"))
+	recorder = web.postHttpResponse("http://10.0.100.5:8080/login", "username=admin&password=admin")
+	assert.Equal(t, 307, recorder.Code)
+	assert.Empty(t, recorder.Result().Cookies())
+
+	// Check that displays remain available over plain HTTP.
+	recorder = web.getHttpResponse("http://10.0.100.5:8080/display?displayId=1")
+	assert.Equal(t, 200, recorder.Code)
+
+	// Check that logging in through a proxy that terminates TLS sets a secure cookie.
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(
+		"POST", "http://10.0.100.5:8080/login", strings.NewReader("username=admin&password=admin"),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	web.newHandler().ServeHTTP(recorder, req)
+	assert.Equal(t, 303, recorder.Code)
+	cookies := recorder.Result().Cookies()
+	if assert.Equal(t, 1, len(cookies)) {
+		assert.Equal(t, sessionTokenCookie, cookies[0].Name)
+		assert.True(t, cookies[0].Secure)
+		assert.True(t, cookies[0].HttpOnly)
+		session, _ := web.arena.Database.GetUserSessionByToken(cookies[0].Value)
+		if assert.NotNil(t, session) {
+			assert.Equal(t, "admin", session.Username)
+		}
+	}
+}
+
+func TestWebsocketOriginBehindProxy(t *testing.T) {
+	web := setupTestWeb(t)
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	headers := http.Header{}
+	headers.Set("Origin", "https://arena.example.com")
+	headers.Set("X-Forwarded-Host", "arena.example.com")
+	headers.Set("X-Forwarded-Proto", "https")
+
+	// Check that the connection is refused when the proxy isn't trusted, since the origin doesn't match the host.
+	_, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/display/websocket?displayId=1", headers)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, web.SetTrustedProxies("127.0.0.1"))
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/display/websocket?displayId=1", headers)
+	if assert.Nil(t, err) {
+		conn.Close()
+	}
+}
+
+// Writes a self-signed certificate and its private key to temporary files and returns their paths.
+func writeTestCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "arena.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(privateKey)
+	assert.Nil(t, err)
+
+	directory := t.TempDir()
+	certFile := filepath.Join(directory, "cert.pem")
+	keyFile := filepath.Join(directory, "key.pem")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer}), 0644))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
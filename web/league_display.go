@@ -0,0 +1,124 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the league display, which pages through the cumulative standings of the league.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+)
+
+// Renders the league display.
+func (web *Web) leagueDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.enforceDisplayConfiguration(w, r, map[string]string{"rowsPerPage": "12", "pageDurationSec": "10"}) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/league_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	leagueSettings, err := league.LoadSettings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		LeagueSettings *league.Settings
+	}{web.arena.EventSettings, leagueSettings}
+	err = template.ExecuteTemplate(w, "league_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the league display to receive updates.
+func (web *Web) leagueDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	display, err := web.registerDisplay(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer web.arena.MarkDisplayDisconnected(display.DisplayConfiguration.Id)
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
+	ws.HandleNotifiers(display.Notifier, web.arena.ScorePostedNotifier, web.arena.EventStatusNotifier,
+		web.arena.ReloadDisplaysNotifier)
+}
+
+// The cumulative standings of the league, along with the names of the events they were calculated from.
+type leagueStandings struct {
+	LeagueName string
+	EventNames []string
+	Standings  []league.Standing
+}
+
+// Calculates the league standings from the results of the events in the league, taken in the order in which they are
+// listed followed by the active event if it is included.
+func (web *Web) getLeagueStandings() (*leagueStandings, error) {
+	leagueSettings, err := league.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	var databases []*model.Database
+	eventNames := make([]string, 0)
+	for _, eventName := range leagueSettings.EventNames {
+		event, err := web.getArchivedEvent(eventName)
+		if err != nil {
+			// Leave out any event that has since been deleted rather than breaking the standings for everyone else.
+			logging.Web.Warn("Failed to open league event.", "event", eventName, "error", err)
+			continue
+		}
+		databases = append(databases, event.Database)
+		eventNames = append(eventNames, eventName)
+	}
+	if leagueSettings.IncludeActiveEvent {
+		databases = append(databases, web.arena.Database)
+		eventNames = append(eventNames, model.EventDatabaseName(web.arena.EventSettings.Name))
+	}
+
+	events := make([]league.EventResults, len(databases))
+	for i, database := range databases {
+		if events[i], err = getEventResults(database, eventNames[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &leagueStandings{
+		LeagueName: leagueSettings.Name,
+		EventNames: eventNames,
+		Standings:  league.CalculateStandings(leagueSettings, events),
+	}, nil
+}
+
+// Returns the qualification results stored in the given event database, for combining into league or season standings.
+func getEventResults(database *model.Database, eventName string) (league.EventResults, error) {
+	rankings, err := database.GetAllRankings()
+	if err != nil {
+		return league.EventResults{}, err
+	}
+	teams, err := database.GetAllTeams()
+	if err != nil {
+		return league.EventResults{}, err
+	}
+	teamNicknames := make(map[int]string, len(teams))
+	for _, team := range teams {
+		teamNicknames[team.Id] = team.Nickname
+	}
+	return league.EventResults{EventName: eventName, Rankings: rankings, TeamNicknames: teamNicknames}, nil
+}
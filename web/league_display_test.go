@@ -0,0 +1,81 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLeagueDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/league?displayId=1&rowsPerPage=10&pageDurationSec=5")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "League Display - Untitled Event - Cheesy Arena")
+}
+
+func TestLeagueDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/league/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "scorePosted")
+	readWebsocketType(t, ws, "eventStatus")
+}
+
+func TestLeagueStandingsApi(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Week_1", "Week 1")
+	leagueSettings := league.DefaultSettings()
+	leagueSettings.Name = "Bay Area League"
+	leagueSettings.EventNames = []string{"Week_1", "Deleted_Event"}
+	leagueSettings.Rules.PlacementPoints = []int{5}
+	assert.Nil(t, league.SaveSettings(leagueSettings))
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 254, Rank: 1, RankingFields: game.RankingFields{Wins: 4, Played: 4}},
+	)
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 1114, Rank: 2, RankingFields: game.RankingFields{Wins: 2, Losses: 2, Played: 4}},
+	)
+
+	recorder := web.getHttpResponse("/api/league/standings")
+	assert.Equal(t, 200, recorder.Code)
+	var leagueStandings leagueStandings
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &leagueStandings))
+	assert.Equal(t, "Bay Area League", leagueStandings.LeagueName)
+	assert.Equal(t, []string{"Week_1", "Untitled_Event"}, leagueStandings.EventNames)
+	if assert.Equal(t, 2, len(leagueStandings.Standings)) {
+		// Team 1114 earns 3 wins and first place at the past event plus 2 wins at the current one.
+		assert.Equal(t, 1114, leagueStandings.Standings[0].TeamId)
+		assert.Equal(t, "Simbotics", leagueStandings.Standings[0].Nickname)
+		assert.Equal(t, 2*3+5+2*2, leagueStandings.Standings[0].Points)
+		assert.Equal(t, 254, leagueStandings.Standings[1].TeamId)
+		assert.Equal(t, 2*4+5, leagueStandings.Standings[1].Points)
+	}
+
+	// Check that the active event is left out if so configured.
+	leagueSettings.IncludeActiveEvent = false
+	assert.Nil(t, league.SaveSettings(leagueSettings))
+	recorder = web.getHttpResponse("/api/league/standings")
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &leagueStandings))
+	assert.Equal(t, []string{"Week_1"}, leagueStandings.EventNames)
+	assert.Equal(t, 1, len(leagueStandings.Standings))
+}
@@ -9,18 +9,30 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/google/uuid"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
 )
 
+// Length of time for which an enrolled panel tablet stays enrolled, which comfortably covers an event.
+const panelDeviceCookieMaxAgeSec = 14 * 24 * 60 * 60
+
 // Shows the login form.
 func (web *Web) loginHandler(w http.ResponseWriter, r *http.Request) {
+	if web.redirectToHttps(w, r) {
+		return
+	}
+
 	web.renderLogin(w, r, "")
 }
 
 // Processes the login request.
 func (web *Web) loginPostHandler(w http.ResponseWriter, r *http.Request) {
+	if web.redirectToHttps(w, r) {
+		return
+	}
+
 	username := r.PostFormValue("username")
 	if err := web.checkAuthPassword(username, r.PostFormValue("password")); err != nil {
 		web.renderLogin(w, r, err.Error())
@@ -33,7 +45,17 @@ func (web *Web) loginPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{Name: sessionTokenCookie, Value: session.Token})
+	// Keep the session token away from scripts, and from plain HTTP requests whenever HTTPS is available.
+	http.SetCookie(
+		w,
+		&http.Cookie{
+			Name:     sessionTokenCookie,
+			Value:    session.Token,
+			Secure:   isSecureRequest(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	)
 	redirectUrl := r.URL.Query().Get("redirect")
 	if redirectUrl == "" {
 		redirectUrl = "/"
@@ -60,14 +82,19 @@ func (web *Web) renderLogin(w http.ResponseWriter, r *http.Request, errorMessage
 
 // Returns true if the given user is authorized for admin operations. Used for HTTP cookie authentication.
 func (web *Web) userIsAdmin(w http.ResponseWriter, r *http.Request) bool {
+	return web.userHasRole(w, r)
+}
+
+// Returns true if the given user's role grants access to pages that are open to any of the given roles, which admins
+// always have. Otherwise sends the user to log in, or rejects the request if they are logged in to an account without
+// access. Used for HTTP cookie authentication.
+func (web *Web) userHasRole(w http.ResponseWriter, r *http.Request, roles ...string) bool {
 	if web.arena.EventSettings.AdminPassword == "" {
 		// Disable auth if there is no password configured.
 		return true
 	}
-	session := web.getUserSessionFromCookie(r)
-	if session != nil && session.Username == adminUser {
-		return true
-	} else {
+	userAccount := web.getUserAccountFromCookie(r)
+	if userAccount == nil {
 		redirect := r.URL.Path
 		if r.URL.RawQuery != "" {
 			redirect += "?" + r.URL.RawQuery
@@ -75,6 +102,101 @@ func (web *Web) userIsAdmin(w http.ResponseWriter, r *http.Request) bool {
 		http.Redirect(w, r, "/login?redirect="+url.QueryEscape(redirect), 307)
 		return false
 	}
+	if !userAccount.HasRole(roles...) {
+		roleName := model.UserRoleNames[userAccount.Role]
+		http.Error(w, fmt.Sprintf("The %s role doesn't have access to this page.", roleName), 403)
+		return false
+	}
+	return true
+}
+
+// Returns the tablet that the given scoring or referee panel request comes from, and true if it is authorized for any
+// of the given roles. A device that was already enrolled keeps the role it was given even if its user's login has
+// since expired, so that a panel can reconnect mid-event without anyone having to log back in. Otherwise the user must
+// log in as usual, and the tablet is then enrolled under their role. Tablets are neither enrolled nor recognized while
+// auth is disabled, since nobody has vouched for them and they would otherwise keep access once a password is set.
+func (web *Web) panelDeviceHasRole(w http.ResponseWriter, r *http.Request, roles ...string) (*model.PanelDevice, bool) {
+	if web.arena.EventSettings.AdminPassword == "" {
+		return nil, true
+	}
+	panelDevice := web.getPanelDeviceFromCookie(r)
+	// A device without a username was enrolled by an older version while auth was disabled, and must be re-enrolled.
+	if panelDevice != nil && panelDevice.Username != "" && panelDevice.HasRole(roles...) {
+		panelDevice.LastSeenAt = time.Now()
+		if err := web.arena.Database.UpdatePanelDevice(panelDevice); err != nil {
+			handleWebErr(w, err)
+			return nil, false
+		}
+		return panelDevice, true
+	}
+	if !web.userHasRole(w, r, roles...) {
+		return nil, false
+	}
+
+	// Enroll the tablet under the role of the user who logged in on it, or re-enroll it if it had a lesser role.
+	if panelDevice == nil {
+		panelDevice = &model.PanelDevice{
+			Token: uuid.New().String(), Name: web.getClientAddress(r), CreatedAt: time.Now(),
+		}
+	}
+	panelDevice.Role = roles[0]
+	panelDevice.Username = ""
+	panelDevice.SessionToken = ""
+	if session := web.getUserSessionFromCookie(r); session != nil {
+		panelDevice.Username = session.Username
+		panelDevice.SessionToken = session.Token
+		if userAccount := web.getUserAccountFromCookie(r); userAccount != nil {
+			panelDevice.Role = userAccount.Role
+		}
+	}
+	panelDevice.LastSeenAt = time.Now()
+	var err error
+	if panelDevice.Id == 0 {
+		err = web.arena.Database.CreatePanelDevice(panelDevice)
+	} else {
+		err = web.arena.Database.UpdatePanelDevice(panelDevice)
+	}
+	if err != nil {
+		handleWebErr(w, err)
+		return nil, false
+	}
+	http.SetCookie(
+		w,
+		&http.Cookie{
+			Name:     panelDeviceCookie,
+			Value:    panelDevice.Token,
+			Path:     "/panels",
+			MaxAge:   panelDeviceCookieMaxAgeSec,
+			Secure:   isSecureRequest(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	)
+	return panelDevice, true
+}
+
+// Returns the enrolled tablet identified by the device cookie in the given request, or nil if there isn't one.
+func (web *Web) getPanelDeviceFromCookie(r *http.Request) *model.PanelDevice {
+	token, err := r.Cookie(panelDeviceCookie)
+	if err != nil {
+		return nil
+	}
+	panelDevice, _ := web.arena.Database.GetPanelDeviceByToken(token.Value)
+	return panelDevice
+}
+
+// Returns the account of the user logged in to the session in the given request, or nil if there isn't one.
+func (web *Web) getUserAccountFromCookie(r *http.Request) *model.UserAccount {
+	session := web.getUserSessionFromCookie(r)
+	if session == nil {
+		return nil
+	}
+	if session.Username == adminUser {
+		// The built-in admin user logs in with the admin password from the settings rather than having an account.
+		return &model.UserAccount{Username: adminUser, Role: model.AdminRole}
+	}
+	userAccount, _ := web.arena.Database.GetUserAccountByUsername(session.Username)
+	return userAccount
 }
 
 func (web *Web) getUserSessionFromCookie(r *http.Request) *model.UserSession {
@@ -87,9 +209,35 @@ func (web *Web) getUserSessionFromCookie(r *http.Request) *model.UserSession {
 }
 
 func (web *Web) checkAuthPassword(user, password string) error {
-	if user == adminUser && password == web.arena.EventSettings.AdminPassword {
-		return nil
-	} else {
-		return fmt.Errorf("Invalid login credentials.")
+	if user == adminUser {
+		if password == web.arena.EventSettings.AdminPassword {
+			return nil
+		}
+	} else if userAccount, _ := web.arena.Database.GetUserAccountByUsername(user); userAccount != nil {
+		if userAccount.CheckPassword(password) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid login credentials.")
+}
+
+// Returns the name to record as having made the given request: the logged-in username if there is one, or else the
+// address of the client.
+func (web *Web) getRequestUsername(r *http.Request) string {
+	if session := web.getUserSessionFromCookie(r); session != nil {
+		return session.Username
+	}
+	return web.getClientAddress(r)
+}
+
+// Returns the IP address of the client that made the given request.
+func (web *Web) getClientAddress(r *http.Request) string {
+	if r.RemoteAddr == "" {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+	return host
 }
@@ -4,6 +4,7 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -45,3 +46,50 @@ func TestLoginDisplay(t *testing.T) {
 	recorder = web.getHttpResponseWithHeaders("/match_play?p1=v1&p2=v2", map[string]string{"Cookie": cookie})
 	assert.Equal(t, 200, recorder.Code)
 }
+
+func TestLoginRoles(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.AdminPassword = "admin"
+	referee := model.UserAccount{Username: "Bertha", Role: model.RefereeRole}
+	referee.SetPassword("whistle")
+	assert.Nil(t, web.arena.Database.CreateUserAccount(&referee))
+
+	recorder := web.postHttpResponse("/login", "username=bertha&password=admin")
+	assert.Contains(t, recorder.Body.String(), "Invalid login credentials.")
+	recorder = web.postHttpResponse("/login", "username=bertha&password=whistle")
+	assert.Equal(t, 303, recorder.Code)
+	headers := map[string]string{"Cookie": recorder.Header().Get("Set-Cookie")}
+
+	// Check that the referee can only access the pages for their role.
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", headers)
+	assert.Equal(t, 200, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/match_play", headers)
+	assert.Equal(t, 403, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The Referee role doesn't have access to this page.")
+	recorder = web.getHttpResponseWithHeaders("/setup/settings", headers)
+	assert.Equal(t, 403, recorder.Code)
+
+	// Check that a change of role takes effect immediately.
+	referee.Role = model.ScorekeeperRole
+	assert.Nil(t, web.arena.Database.UpdateUserAccount(&referee))
+	recorder = web.getHttpResponseWithHeaders("/match_play", headers)
+	assert.Equal(t, 200, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", headers)
+	assert.Equal(t, 403, recorder.Code)
+
+	// Check that deleting the account logs the user out.
+	assert.Nil(t, web.arena.Database.DeleteUserAccount(referee.Id))
+	recorder = web.getHttpResponseWithHeaders("/match_play", headers)
+	assert.Equal(t, 307, recorder.Code)
+
+	// Check that an admin account can access everything.
+	admin := model.UserAccount{Username: "Alfred", Role: model.AdminRole}
+	admin.SetPassword("sudo")
+	assert.Nil(t, web.arena.Database.CreateUserAccount(&admin))
+	recorder = web.postHttpResponse("/login", "username=Alfred&password=sudo")
+	headers = map[string]string{"Cookie": recorder.Header().Get("Set-Cookie")}
+	recorder = web.getHttpResponseWithHeaders("/setup/settings", headers)
+	assert.Equal(t, 200, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", headers)
+	assert.Equal(t, 200, recorder.Code)
+}
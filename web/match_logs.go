@@ -243,7 +243,7 @@ func (web *Web) buildMatchLogsList(matchType model.MatchType) ([]MatchLogsListIt
 	for i, match := range matches {
 		matchLogsList[i].Id = match.Id
 		matchLogsList[i].ShortName = match.ShortName
-		matchLogsList[i].Time = match.Time.Local().Format("Mon 1/02 03:04 PM")
+		matchLogsList[i].Time = match.Time.In(web.arena.EventSettings.Location()).Format("Mon 1/02 03:04 PM")
 		matchLogsList[i].RedTeams = []int{match.Red1, match.Red2, match.Red3}
 		matchLogsList[i].BlueTeams = []int{match.Blue1, match.Blue2, match.Blue3}
 		if err != nil {
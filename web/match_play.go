@@ -8,14 +8,16 @@ package web
 import (
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
@@ -27,13 +29,14 @@ type MatchPlayListItem struct {
 	Time       string
 	Status     game.MatchStatus
 	ColorClass string
+	Bye        bool
 }
 
 type MatchPlayList []MatchPlayListItem
 
 // Shows the match play control interface.
 func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -42,14 +45,48 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	showFlowSteps, err := web.arena.Database.GetAllShowFlowSteps()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	videoStingers, err := web.arena.Database.GetAllVideoStingers()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	lowerThirds, err := web.arena.Database.GetAllLowerThirds()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	// Award lower thirds are left to the awards ceremony screen or to playlists.
+	lowerThirds = slices.DeleteFunc(lowerThirds, func(lowerThird model.LowerThird) bool {
+		return lowerThird.AwardId != 0
+	})
+	lowerThirdPlaylists, err := web.getLowerThirdPlaylistViews()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	data := struct {
 		*model.EventSettings
 		PlcIsEnabled          bool
 		PlcArmorBlockStatuses map[string]bool
+		BypassReasonNames     map[string]string
+		ShowFlowSteps         []model.ShowFlowStep
+		VideoStingers         []model.VideoStinger
+		LowerThirds           []model.LowerThird
+		LowerThirdPlaylists   []lowerThirdPlaylistView
 	}{
 		web.arena.EventSettings,
 		web.arena.Plc.IsEnabled(),
 		web.arena.Plc.GetArmorBlockStatuses(),
+		model.BypassReasonNames,
+		showFlowSteps,
+		videoStingers,
+		lowerThirds,
+		lowerThirdPlaylists,
 	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
@@ -60,7 +97,7 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 
 // Renders a partial template containing the list of matches.
 func (web *Web) matchPlayMatchLoadHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -111,7 +148,7 @@ func (web *Web) matchPlayMatchLoadHandler(w http.ResponseWriter, r *http.Request
 
 // The websocket endpoint for the match play client to send control commands and receive status updates.
 func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
@@ -128,12 +165,16 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 		web.arena.AllianceStationDisplayModeNotifier,
 		web.arena.ArenaStatusNotifier,
 		web.arena.AudienceDisplayModeNotifier,
+		web.arena.EventSettingsNotifier,
 		web.arena.EventStatusNotifier,
+		web.arena.LowerThirdNotifier,
 		web.arena.MatchLoadNotifier,
 		web.arena.MatchTimeNotifier,
+		web.arena.OnDeckNotifier,
 		web.arena.RealtimeScoreNotifier,
 		web.arena.ScorePostedNotifier,
 		web.arena.ScoringStatusNotifier,
+		web.arena.StreamAudienceDisplayModeNotifier,
 	)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
@@ -144,10 +185,11 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
+		web.recordAuditCommand(r, messageType, data)
 		switch messageType {
 		case "loadMatch":
 			args := struct {
@@ -181,6 +223,46 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError(err.Error())
 				continue
 			}
+		case "loadNextMatch":
+			err = web.arena.ResetMatch()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.LoadNextMatch(false)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "setNextMatch":
+			args := struct {
+				MatchId int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.SetNextMatchOverride(args.MatchId)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "setMatchBye":
+			args := struct {
+				MatchId int
+				Bye     bool
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.SetMatchBye(args.MatchId, args.Bye)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "showResult":
 			args := struct {
 				MatchId int
@@ -247,19 +329,19 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				continue
 			}
 		case "toggleBypass":
-			station, ok := data.(string)
-			if !ok {
-				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+			args := struct {
+				Station string
+				Reason  string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
 				continue
 			}
-			if _, ok := web.arena.AllianceStations[station]; !ok {
-				ws.WriteError(fmt.Sprintf("Invalid alliance station '%s'.", station))
+			if err = web.arena.ToggleBypass(args.Station, args.Reason); err != nil {
+				ws.WriteError(err.Error())
 				continue
 			}
-			web.arena.AllianceStations[station].Bypass = !web.arena.AllianceStations[station].Bypass
-			if err = ws.WriteNotifier(web.arena.ArenaStatusNotifier); err != nil {
-				log.Println(err)
-			}
 		case "startMatch":
 			args := struct {
 				MuteMatchSounds bool
@@ -281,6 +363,38 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError(err.Error())
 				continue
 			}
+		case "declareFieldFault":
+			args := struct {
+				Reason string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.DeclareFieldFault(args.Reason)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "resumeMatch":
+			err = web.arena.ResumeMatch()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "resumeInterruptedMatch":
+			err = web.arena.ResumeInterruptedMatch()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "discardInterruptedMatch":
+			err = web.arena.DiscardInterruptedMatch()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "signalReset":
 			if web.arena.MatchState != field.PostMatch && web.arena.MatchState != field.PreMatch {
 				// Don't allow clearing the field until the match is over.
@@ -294,6 +408,14 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError("cannot commit match while it is in progress")
 				continue
 			}
+			if web.arena.ScoreReviewPending() {
+				ws.WriteError("cannot commit match until the scorekeeper and head referee have approved the scores")
+				continue
+			}
+			if web.arena.CardConfirmationPending() {
+				ws.WriteError("cannot commit match until the head referee has confirmed the cards")
+				continue
+			}
 			err = web.commitCurrentMatchScore()
 			if err != nil {
 				ws.WriteError(err.Error())
@@ -327,6 +449,70 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				continue
 			}
 			web.arena.SetAudienceDisplayMode(mode)
+		case "setAudienceDisplayOutput":
+			args := struct {
+				Output string
+				Mode   string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if err = web.arena.SetAudienceDisplayOutputMode(args.Output, args.Mode); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "advanceShowFlow":
+			err = web.arena.AdvanceShowFlow()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "resetShowFlow":
+			web.arena.ResetShowFlow()
+		case "playVideoStinger":
+			videoStingerId, ok := data.(float64)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if err = web.arena.PlayVideoStinger(int(videoStingerId)); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "markReplay":
+			if err = web.arena.MarkReplay(); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "showLowerThird":
+			lowerThirdId, ok := data.(float64)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if err = web.arena.ShowSavedLowerThird(int(lowerThirdId)); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "hideLowerThird":
+			web.arena.HideLowerThird()
+		case "selectLowerThirdPlaylist":
+			playlistId, ok := data.(float64)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if err = web.arena.SelectLowerThirdPlaylist(int(playlistId)); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "advanceLowerThirdPlaylist":
+			if err = web.arena.AdvanceLowerThirdPlaylist(); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "setAllianceStationDisplay":
 			mode, ok := data.(string)
 			if !ok {
@@ -345,6 +531,41 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError(err.Error())
 				continue
 			}
+		case "callAllianceTimeout":
+			alliance, ok := data.(string)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			err = web.arena.CallAllianceTimeout(alliance)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "setRobotSimulation":
+			enabled, ok := data.(bool)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if err = web.arena.SetRobotSimulationEnabled(enabled); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "setStaffReady":
+			args := struct {
+				Role  string
+				Ready bool
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if err = web.arena.SetStaffReady(field.StaffRole(args.Role), args.Ready); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "setTestMatchName":
 			if web.arena.CurrentMatch.Type != model.Test {
 				// Don't allow changing the name of a non-test match.
@@ -365,6 +586,10 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 
 // Saves the given match and result to the database, supplanting any previous result for the match.
 func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchResult, isMatchReviewEdit bool) error {
+	if match.Type == model.Practice && web.arena.EventSettings.PracticeSandboxEnabled && !isMatchReviewEdit {
+		return web.commitSandboxMatchScore(match, matchResult)
+	}
+
 	var updatedRankings game.Rankings
 
 	if match.Type == model.Playoff {
@@ -372,125 +597,213 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 		matchResult.CorrectPlayoffScore()
 	}
 
-	// Update the match record.
-	match.ScoreCommittedAt = time.Now()
+	// Update a copy of the match record, which only replaces the shared one once it has been saved.
+	updatedMatch := *match
+	updatedMatch.ScoreCommittedAt = time.Now()
 	redScoreSummary := matchResult.RedScoreSummary()
 	blueScoreSummary := matchResult.BlueScoreSummary()
-	match.Status = game.DetermineMatchStatus(redScoreSummary, blueScoreSummary, match.UseTiebreakCriteria)
+	updatedMatch.Status, updatedMatch.WinReason, updatedMatch.TiebreakLevel = game.DetermineMatchOutcome(
+		redScoreSummary, blueScoreSummary, match.UseTiebreakCriteria,
+	)
 
-	if match.Type != model.Test {
-		if matchResult.PlayNumber == 0 {
-			// Determine the play number for this new match result.
-			prevMatchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
-			if err != nil {
-				return err
-			}
-			if prevMatchResult != nil {
-				matchResult.PlayNumber = prevMatchResult.PlayNumber + 1
-			} else {
-				matchResult.PlayNumber = 1
+	if match.Type == model.Test {
+		*match = updatedMatch
+	} else {
+		// Save the result along with everything derived from it in a single transaction, so that a failure or crash
+		// partway through can't leave the rankings or playoff bracket out of step with the stored results.
+		originalMatchResultId, originalPlayNumber := matchResult.Id, matchResult.PlayNumber
+		err := web.arena.Database.RunInTransaction(func(database *model.Database) error {
+			rankings, err := web.saveMatchScore(database, &updatedMatch, matchResult, isMatchReviewEdit)
+			updatedRankings = rankings
+			return err
+		})
+		if err != nil {
+			matchResult.Id, matchResult.PlayNumber = originalMatchResultId, originalPlayNumber
+			if match.ShouldUpdatePlayoffMatches() {
+				// Bring the in-memory bracket back in line with the matches as they were before the rollback.
+				if playoffErr := web.arena.UpdatePlayoffTournament(); playoffErr != nil {
+					logging.Arena.Error("Failed to restore the playoff tournament.", "error", playoffErr)
+				}
 			}
+			return err
+		}
+		*match = updatedMatch
 
-			// Save the match result record to the database.
-			err = web.arena.Database.CreateMatchResult(matchResult)
-			if err != nil {
+		if match.ShouldUpdateRankings() {
+			// Re-run the simulations of the remaining matches to reflect the new result.
+			if err = web.updateProjections(); err != nil {
 				return err
 			}
-		} else {
-			// We are updating a match result record that already exists.
-			err := web.arena.Database.UpdateMatchResult(matchResult)
-			if err != nil {
-				return err
+		}
+
+		if web.arena.EventSettings.TbaPublishingEnabled && match.Type != model.Practice {
+			// Now that the result is safely stored, publish asynchronously to The Blue Alliance, retrying in the
+			// background if the internet is flaky.
+			web.arena.TbaPublisher.Enqueue(partner.TbaPublishMatches)
+			if match.ShouldUpdateRankings() {
+				web.arena.TbaPublisher.Enqueue(partner.TbaPublishRankings)
 			}
 		}
 
-		err := web.arena.Database.UpdateMatch(match)
+		webhookData := field.NewWebhookMatchData(match)
+		webhookData.RedScore = &redScoreSummary.Score
+		webhookData.BlueScore = &blueScoreSummary.Score
+		web.arena.NotifyWebhooks(partner.WebhookScoreCommitted, webhookData)
+
+		// Back up the database, but don't error out if it fails.
+		err = web.arena.AutoBackupDatabase(model.PostMatchBackupReason(match))
 		if err != nil {
-			return err
+			logging.Database.Error("Failed to back up the event database.", "match", match.ShortName, "error", err)
 		}
+	}
 
-		if match.ShouldUpdateCards() {
-			// Regenerate the residual yellow cards that teams may carry.
-			if err = tournament.CalculateTeamCards(web.arena.Database, match.Type); err != nil {
-				return err
-			}
+	if !isMatchReviewEdit {
+		// Store the result in the buffer to be shown in the audience display.
+		web.arena.SavedMatch = match
+		web.arena.SavedMatchResult = matchResult
+		web.arena.SavedRankings = updatedRankings
+		web.arena.ScorePostedNotifier.Notify()
+	}
+
+	return nil
+}
+
+// Writes the given match result and everything derived from it to the given database, which is expected to be bound to
+// a transaction. Returns the recalculated rankings if the match affects them.
+func (web *Web) saveMatchScore(
+	database *model.Database, match *model.Match, matchResult *model.MatchResult, isMatchReviewEdit bool,
+) (game.Rankings, error) {
+	if matchResult.PlayNumber == 0 {
+		// Determine the play number for this new match result.
+		prevMatchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if prevMatchResult != nil {
+			matchResult.PlayNumber = prevMatchResult.PlayNumber + 1
+		} else {
+			matchResult.PlayNumber = 1
 		}
 
-		if match.ShouldUpdateRankings() {
-			// Recalculate all the rankings.
-			rankings, err := tournament.CalculateRankings(web.arena.Database, isMatchReviewEdit)
-			if err != nil {
-				return err
-			}
-			updatedRankings = rankings
+		// Save the match result record to the database.
+		err = database.CreateMatchResult(matchResult)
+		if err != nil {
+			return nil, err
 		}
 
-		if match.ShouldUpdatePlayoffMatches() {
-			if err = web.arena.Database.UpdateAllianceFromMatch(
-				match.PlayoffRedAlliance, [3]int{match.Red1, match.Red2, match.Red3},
-			); err != nil {
-				return err
+		if !isMatchReviewEdit {
+			// Save the timeline of what happened during this play of the match for later dispute resolution.
+			matchTimeline := model.MatchTimeline{
+				MatchId: match.Id, PlayNumber: matchResult.PlayNumber, Events: web.arena.GetMatchTimeline(),
 			}
-			if err = web.arena.Database.UpdateAllianceFromMatch(
-				match.PlayoffBlueAlliance, [3]int{match.Blue1, match.Blue2, match.Blue3},
-			); err != nil {
-				return err
+			if err = database.CreateMatchTimeline(&matchTimeline); err != nil {
+				return nil, err
 			}
+		}
+	} else {
+		// We are updating a match result record that already exists.
+		err := database.UpdateMatchResult(matchResult)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-			// Populate any subsequent playoff matches.
-			if err = web.arena.UpdatePlayoffTournament(); err != nil {
-				return err
-			}
+	err := database.UpdateMatch(match)
+	if err != nil {
+		return nil, err
+	}
 
-			// Generate awards if the tournament is over.
-			if web.arena.PlayoffTournament.IsComplete() {
-				winnerAllianceId := web.arena.PlayoffTournament.WinningAllianceId()
-				finalistAllianceId := web.arena.PlayoffTournament.FinalistAllianceId()
-				if err = tournament.CreateOrUpdateWinnerAndFinalistAwards(
-					web.arena.Database, winnerAllianceId, finalistAllianceId,
-				); err != nil {
-					return err
-				}
-			}
+	if match.ShouldUpdateCards() {
+		// Regenerate the residual yellow cards that teams may carry.
+		if err = tournament.CalculateTeamCards(database, match.Type); err != nil {
+			return nil, err
 		}
+	}
 
-		if web.arena.EventSettings.TbaPublishingEnabled && match.Type != model.Practice {
-			// Publish asynchronously to The Blue Alliance.
-			go func() {
-				if err = web.arena.TbaClient.PublishMatches(web.arena.Database); err != nil {
-					log.Printf("Failed to publish matches: %s", err.Error())
-				}
-				if match.ShouldUpdateRankings() {
-					if err = web.arena.TbaClient.PublishRankings(web.arena.Database); err != nil {
-						log.Printf("Failed to publish rankings: %s", err.Error())
-					}
-				}
-			}()
+	var updatedRankings game.Rankings
+	if match.ShouldUpdateRankings() {
+		// Recalculate all the rankings.
+		updatedRankings, err = tournament.CalculateRankings(database, isMatchReviewEdit)
+		if err != nil {
+			return nil, err
 		}
 
-		// Back up the database, but don't error out if it fails.
-		err = web.arena.Database.Backup(web.arena.EventSettings.Name,
-			fmt.Sprintf("post_%s_match_%s", match.Type, match.ShortName))
-		if err != nil {
-			log.Println(err)
+		// Recalculate the per-team contributions used for alliance selection preparation.
+		if _, err = tournament.CalculateTeamStats(database); err != nil {
+			return nil, err
 		}
 	}
 
-	if !isMatchReviewEdit {
-		// Store the result in the buffer to be shown in the audience display.
-		web.arena.SavedMatch = match
-		web.arena.SavedMatchResult = matchResult
-		web.arena.SavedRankings = updatedRankings
-		web.arena.ScorePostedNotifier.Notify()
+	if match.ShouldUpdatePlayoffMatches() {
+		if err = database.UpdateAllianceFromMatch(
+			match.PlayoffRedAlliance, [3]int{match.Red1, match.Red2, match.Red3},
+		); err != nil {
+			return nil, err
+		}
+		if err = database.UpdateAllianceFromMatch(
+			match.PlayoffBlueAlliance, [3]int{match.Blue1, match.Blue2, match.Blue3},
+		); err != nil {
+			return nil, err
+		}
+
+		// Populate any subsequent playoff matches.
+		if err = web.arena.UpdatePlayoffTournamentFrom(database); err != nil {
+			return nil, err
+		}
+
+		// Generate awards if the tournament is over.
+		if web.arena.PlayoffTournament.IsComplete() {
+			winnerAllianceId := web.arena.PlayoffTournament.WinningAllianceId()
+			finalistAllianceId := web.arena.PlayoffTournament.FinalistAllianceId()
+			if err = tournament.CreateOrUpdateWinnerAndFinalistAwards(
+				database, winnerAllianceId, finalistAllianceId,
+			); err != nil {
+				return nil, err
+			}
+		}
 	}
 
+	return updatedRankings, nil
+}
+
+// Saves the result of a practice match to the sandbox table, leaving the match itself unplayed so that it never affects
+// the real match record or rankings.
+func (web *Web) commitSandboxMatchScore(match *model.Match, matchResult *model.MatchResult) error {
+	sandboxMatchResult := model.SandboxMatchResult{
+		MatchId:     match.Id,
+		MatchName:   match.ShortName,
+		CommittedAt: time.Now(),
+		RedScore:    matchResult.RedScore,
+		BlueScore:   matchResult.BlueScore,
+		RedCards:    matchResult.RedCards,
+		BlueCards:   matchResult.BlueCards,
+	}
+	if err := web.arena.Database.CreateSandboxMatchResult(&sandboxMatchResult); err != nil {
+		return err
+	}
+
+	// Work on a copy of the match so that the outcome is only reflected on the displays.
+	sandboxMatch := *match
+	sandboxMatch.ScoreCommittedAt = sandboxMatchResult.CommittedAt
+	sandboxMatch.Status, sandboxMatch.WinReason, sandboxMatch.TiebreakLevel = game.DetermineMatchOutcome(
+		matchResult.RedScoreSummary(), matchResult.BlueScoreSummary(), match.UseTiebreakCriteria,
+	)
+	web.arena.SavedMatch = &sandboxMatch
+	web.arena.SavedMatchResult = matchResult
+	web.arena.SavedRankings = nil
+	web.arena.ScorePostedNotifier.Notify()
+
 	return nil
 }
 
 func (web *Web) getCurrentMatchResult() *model.MatchResult {
 	return &model.MatchResult{MatchId: web.arena.CurrentMatch.Id, MatchType: web.arena.CurrentMatch.Type,
 		RedScore: &web.arena.RedRealtimeScore.CurrentScore, BlueScore: &web.arena.BlueRealtimeScore.CurrentScore,
-		RedCards: web.arena.RedRealtimeScore.Cards, BlueCards: web.arena.BlueRealtimeScore.Cards}
+		RedCards: web.arena.RedRealtimeScore.Cards, BlueCards: web.arena.BlueRealtimeScore.Cards,
+		RedCardRules: web.arena.RedRealtimeScore.CardRules, BlueCardRules: web.arena.BlueRealtimeScore.CardRules,
+		RedScoringLog:  web.arena.RedRealtimeScore.ScoringHistory.Log,
+		BlueScoringLog: web.arena.BlueRealtimeScore.ScoringHistory.Log,
+		RedBypasses:    web.arena.BypassReasons("R"), BlueBypasses: web.arena.BypassReasons("B")}
 }
 
 // Saves the realtime result as the final score for the match currently loaded into the arena.
@@ -524,8 +837,9 @@ func (web *Web) buildMatchPlayList(matchType model.MatchType) (MatchPlayList, er
 	for i, match := range matches {
 		matchPlayList[i].Id = match.Id
 		matchPlayList[i].ShortName = match.ShortName
-		matchPlayList[i].Time = match.Time.Local().Format("3:04 PM")
+		matchPlayList[i].Time = match.Time.In(web.arena.EventSettings.Location()).Format("3:04 PM")
 		matchPlayList[i].Status = match.Status
+		matchPlayList[i].Bye = match.Bye
 		switch match.Status {
 		case game.RedWonMatch:
 			matchPlayList[i].ColorClass = "red"
@@ -4,16 +4,16 @@
 package web
 
 import (
-	"bytes"
+	"fmt"
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"testing"
 	"time"
 )
@@ -25,6 +25,15 @@ func TestMatchPlay(t *testing.T) {
 	recorder := web.getHttpResponse("/match_play")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Are you sure you want to discard the results for this match?")
+
+	// Check that saved lower thirds are offered for display, but not those belonging to awards.
+	web.arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Welcome", DisplayOrder: 1})
+	web.arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Chairman's Award", DisplayOrder: 2, AwardId: 1})
+	web.arena.Database.CreateLowerThirdPlaylist(&model.LowerThirdPlaylist{Name: "Awards", LowerThirdIds: []int{2}})
+	recorder = web.getHttpResponse("/match_play")
+	assert.Contains(t, recorder.Body.String(), "1. Welcome")
+	assert.NotContains(t, recorder.Body.String(), "2. Chairman")
+	assert.Contains(t, recorder.Body.String(), "Awards")
 }
 
 func TestMatchPlayMatchList(t *testing.T) {
@@ -98,13 +107,134 @@ func TestCommitMatch(t *testing.T) {
 	// Verify TBA publishing by checking the log for the expected failure messages.
 	web.arena.TbaClient.BaseUrl = "fakeUrl"
 	web.arena.EventSettings.TbaPublishingEnabled = true
-	var writer bytes.Buffer
-	log.SetOutput(&writer)
+	logEntries, unsubscribe := logging.Subscribe()
+	defer unsubscribe()
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
 	time.Sleep(time.Millisecond * 100) // Allow some time for the asynchronous publishing to happen.
-	assert.Contains(t, writer.String(), "Failed to publish matches")
-	assert.Contains(t, writer.String(), "Failed to publish rankings")
+	failedKinds := map[string]bool{}
+	for len(logEntries) > 0 {
+		entry := <-logEntries
+		if entry.Message == "Failed to publish to TBA." {
+			failedKinds[entry.Attrs[0].Value] = true
+		}
+	}
+	assert.True(t, failedKinds["matches"])
+	assert.True(t, failedKinds["rankings"])
+}
+
+func TestCommitMatchRollback(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Commit a match that was never saved, so that the commit fails after the match result has been written.
+	match := &model.Match{Id: 254, Type: model.Qualification, Red1: 101, Blue1: 104}
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	err := web.commitMatchScore(match, matchResult, false)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't update non-existent Match")
+	}
+
+	// Check that nothing from the failed commit was kept.
+	assert.Equal(t, 0, matchResult.Id)
+	assert.Equal(t, 0, matchResult.PlayNumber)
+	storedMatchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, storedMatchResult)
+	matchTimelines, err := web.arena.Database.GetMatchTimelinesForMatch(match.Id)
+	assert.Nil(t, err)
+	assert.Empty(t, matchTimelines)
+	assert.NotSame(t, match, web.arena.SavedMatch)
+}
+
+func TestCommitMatchRollbackKeepsMatch(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Commit a red win over an earlier blue win of a match that was never saved, so that the save fails.
+	committedAt := time.Unix(1000, 0)
+	match := &model.Match{
+		Id:               254,
+		Type:             model.Qualification,
+		Red1:             101,
+		Blue1:            104,
+		Status:           game.BlueWonMatch,
+		WinReason:        game.WinReasonScore,
+		ScoreCommittedAt: committedAt,
+	}
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.RedScore.LeaveStatuses[0] = true
+	assert.NotNil(t, web.commitMatchScore(match, matchResult, true))
+
+	// Check that the shared match record still reflects the last successful commit.
+	assert.Equal(t, game.BlueWonMatch, match.Status)
+	assert.Equal(t, game.WinReasonScore, match.WinReason)
+	assert.Equal(t, 0, match.TiebreakLevel)
+	assert.Equal(t, committedAt, match.ScoreCommittedAt)
+}
+
+func TestCommitSandboxMatch(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.PracticeSandboxEnabled = true
+
+	match := &model.Match{Type: model.Practice, ShortName: "P1", Red1: 101, Blue1: 104}
+	assert.Nil(t, web.arena.Database.CreateMatch(match))
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.BlueScore.LeaveStatuses[2] = true
+	assert.Nil(t, web.commitMatchScore(match, matchResult, false))
+
+	// The result should go only to the sandbox and leave the match itself unplayed.
+	realMatchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, realMatchResult)
+	match, _ = web.arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, game.MatchScheduled, match.Status)
+	assert.Equal(t, game.BlueWonMatch, web.arena.SavedMatch.Status)
+	sandboxMatchResults, err := web.arena.Database.GetAllSandboxMatchResults()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(sandboxMatchResults)) {
+		assert.Equal(t, "P1", sandboxMatchResults[0].MatchName)
+		assert.True(t, sandboxMatchResults[0].BlueScore.LeaveStatuses[2])
+	}
+
+	recorder := web.getHttpResponse("/reports/csv/sandbox_results")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Match,CommittedAt,RedScore,BlueScore")
+	assert.Contains(t, recorder.Body.String(), "\nP1,")
+
+	// Edits made through match review still go to the real match record.
+	assert.Nil(t, web.commitMatchScore(match, matchResult, true))
+	match, _ = web.arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, game.BlueWonMatch, match.Status)
+}
+
+func TestCommitSandboxMatchLoadsNext(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.PracticeSandboxEnabled = true
+	for i := 1; i <= 2; i++ {
+		match := model.Match{Type: model.Practice, TypeOrder: i, ShortName: fmt.Sprintf("P%d", i)}
+		web.arena.Database.CreateMatch(&match)
+	}
+	matches, _ := web.arena.Database.GetMatchesByType(model.Practice, false)
+	assert.Nil(t, web.arena.LoadMatch(&matches[0]))
+	assert.Equal(t, "P2", web.arena.OnDeck.Match.ShortName)
+
+	// Check that committing a sandbox result moves on to the next practice match even though the match stays unplayed.
+	assert.Nil(t, web.commitCurrentMatchScore())
+	assert.Nil(t, web.arena.ResetMatch())
+	assert.Nil(t, web.arena.LoadNextMatch(true))
+	assert.Equal(t, "P2", web.arena.CurrentMatch.ShortName)
+	assert.Nil(t, web.arena.OnDeck)
+	assert.Nil(t, web.commitCurrentMatchScore())
+	assert.Nil(t, web.arena.ResetMatch())
+	assert.Nil(t, web.arena.LoadNextMatch(true))
+	assert.Equal(t, model.Test, web.arena.CurrentMatch.Type)
+
+	// Check that clearing the sandbox results makes the practice matches available again.
+	assert.Nil(t, web.arena.Database.TruncateSandboxMatchResults())
+	assert.Nil(t, web.arena.LoadMatch(&matches[0]))
+	assert.Equal(t, "P2", web.arena.OnDeck.Match.ShortName)
 }
 
 func TestCommitTiebreak(t *testing.T) {
@@ -202,20 +332,20 @@ func TestCommitCards(t *testing.T) {
 	matchResult.BlueCards = map[string]string{"5": "yellow"}
 	err := web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
-	team1, _ = web.arena.Database.GetTeamById(3)
-	assert.True(t, team1.YellowCard)
-	team2, _ = web.arena.Database.GetTeamById(5)
-	assert.True(t, team2.YellowCard)
+	teamStatus, _ := web.arena.Database.GetTeamStatus(3)
+	assert.True(t, teamStatus.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(5)
+	assert.True(t, teamStatus.YellowCard)
 
 	// Check that editing a match result removes a yellow card from a team.
 	matchResult = model.NewMatchResult()
 	matchResult.MatchId = match.Id
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
-	team1, _ = web.arena.Database.GetTeamById(3)
-	assert.False(t, team1.YellowCard)
-	team2, _ = web.arena.Database.GetTeamById(5)
-	assert.False(t, team2.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(3)
+	assert.False(t, teamStatus.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(5)
+	assert.False(t, teamStatus.YellowCard)
 
 	// Check that a red card causes a yellow card to stick with a team.
 	matchResult = model.NewMatchResult()
@@ -224,10 +354,10 @@ func TestCommitCards(t *testing.T) {
 	matchResult.BlueCards = map[string]string{"5": "red"}
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
-	team1, _ = web.arena.Database.GetTeamById(3)
-	assert.True(t, team1.YellowCard)
-	team2, _ = web.arena.Database.GetTeamById(5)
-	assert.True(t, team2.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(3)
+	assert.True(t, teamStatus.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(5)
+	assert.True(t, teamStatus.YellowCard)
 
 	// Check that a DQ does not cause a yellow card to stick with a team.
 	matchResult = model.NewMatchResult()
@@ -236,10 +366,10 @@ func TestCommitCards(t *testing.T) {
 	matchResult.BlueCards = map[string]string{"5": "dq"}
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
-	team1, _ = web.arena.Database.GetTeamById(3)
-	assert.False(t, team1.YellowCard)
-	team2, _ = web.arena.Database.GetTeamById(5)
-	assert.False(t, team2.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(3)
+	assert.False(t, teamStatus.YellowCard)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(5)
+	assert.False(t, teamStatus.YellowCard)
 
 	// Check that a red card in playoffs zeroes out the score.
 	tournament.CreateTestAlliances(web.arena.Database, 2)
@@ -282,12 +412,16 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	readWebsocketType(t, ws, "allianceStationDisplayMode")
 	readWebsocketType(t, ws, "arenaStatus")
 	readWebsocketType(t, ws, "audienceDisplayMode")
+	readWebsocketType(t, ws, "eventSettings")
 	readWebsocketType(t, ws, "eventStatus")
+	readWebsocketType(t, ws, "lowerThird")
 	readWebsocketType(t, ws, "matchLoad")
 	readWebsocketType(t, ws, "matchTime")
+	readWebsocketType(t, ws, "onDeck")
 	readWebsocketType(t, ws, "realtimeScore")
 	readWebsocketType(t, ws, "scorePosted")
 	readWebsocketType(t, ws, "scoringStatus")
+	readWebsocketType(t, ws, "streamAudienceDisplayMode")
 
 	// Test that a server-side error is communicated to the client.
 	ws.Write("nonexistenttype", nil)
@@ -302,22 +436,35 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	ws.Write("substituteTeams", map[string]int{"Red1": 0, "Red2": 0, "Red3": 0, "Blue1": 0, "Blue2": 0, "Blue3": 0})
 	readWebsocketType(t, ws, "matchLoad")
 	assert.Equal(t, 0, web.arena.CurrentMatch.Blue1)
-	ws.Write("toggleBypass", nil)
-	assert.Contains(t, readWebsocketError(t, ws), "Failed to parse")
-	ws.Write("toggleBypass", "R4")
-	assert.Contains(t, readWebsocketError(t, ws), "Invalid alliance station")
+	auditEntries, _ := web.arena.Database.GetAuditEntries(model.AuditFilter{Search: "substituteTeams"})
+	if assert.Equal(t, 3, len(auditEntries)) {
+		assert.Equal(t, "/match_play/websocket substituteTeams", auditEntries[0].Action)
+		assert.Contains(t, auditEntries[0].Details, "Blue1:0")
+	}
 	ws.Write("toggleBypass", "R3")
+	assert.Contains(t, readWebsocketError(t, ws), "expected a map")
+	ws.Write("toggleBypass", map[string]string{"station": "R4", "reason": model.BypassNoShow})
+	assert.Contains(t, readWebsocketError(t, ws), "invalid alliance station")
+	ws.Write("toggleBypass", map[string]string{"station": "R3"})
+	assert.Contains(t, readWebsocketError(t, ws), "invalid bypass reason")
+	ws.Write("toggleBypass", map[string]string{"station": "R3", "reason": model.BypassRobotFailure})
 	readWebsocketType(t, ws, "arenaStatus")
 	assert.Equal(t, true, web.arena.AllianceStations["R3"].Bypass)
-	ws.Write("toggleBypass", "R3")
+	assert.Equal(t, model.BypassRobotFailure, web.arena.AllianceStations["R3"].BypassReason)
+	ws.Write("toggleBypass", map[string]string{"station": "R3"})
 	readWebsocketType(t, ws, "arenaStatus")
 	assert.Equal(t, false, web.arena.AllianceStations["R3"].Bypass)
+	assert.Equal(t, "", web.arena.AllianceStations["R3"].BypassReason)
 
 	// Go through match flow.
 	ws.Write("abortMatch", nil)
 	assert.Contains(t, readWebsocketError(t, ws), "cannot abort match")
 	ws.Write("startMatch", nil)
 	assert.Contains(t, readWebsocketError(t, ws), "cannot start match")
+	ws.Write("declareFieldFault", map[string]any{"reason": "Broken field element"})
+	assert.Contains(t, readWebsocketError(t, ws), "cannot declare a field fault")
+	ws.Write("resumeMatch", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "cannot resume match")
 	web.arena.AllianceStations["R1"].Bypass = true
 	web.arena.AllianceStations["R2"].Bypass = true
 	web.arena.AllianceStations["R3"].Bypass = true
@@ -333,26 +480,114 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	assert.Contains(t, readWebsocketError(t, ws), "cannot reset match while it is in progress")
 	ws.Write("abortMatch", nil)
 	readWebsocketType(t, ws, "audienceDisplayMode")
+	readWebsocketType(t, ws, "streamAudienceDisplayMode")
 	readWebsocketType(t, ws, "allianceStationDisplayMode")
 	assert.Equal(t, field.PostMatch, web.arena.MatchState)
 	web.arena.RedRealtimeScore.CurrentScore.AmpSpeaker.TeleopAmplifiedSpeakerNotes = 6
 	web.arena.BlueRealtimeScore.CurrentScore.LeaveStatuses = [3]bool{true, false, true}
 	ws.Write("commitResults", nil)
-	readWebsocketMultiple(t, ws, 5) // scorePosted, matchLoad, realtimeScore, allianceStationDisplayMode, scoringStatus
+	readWebsocketMultiple(t, ws, 6) // scorePosted, matchLoad, realtimeScore, allianceStationDisplayMode, scoringStatus,
+	// onDeck
 	assert.Equal(t, 6, web.arena.SavedMatchResult.RedScore.AmpSpeaker.TeleopAmplifiedSpeakerNotes)
 	assert.Equal(t, [3]bool{true, false, true}, web.arena.SavedMatchResult.BlueScore.LeaveStatuses)
 	assert.Equal(t, field.PreMatch, web.arena.MatchState)
 	ws.Write("discardResults", nil)
-	readWebsocketMultiple(t, ws, 4) // matchLoad, realtimeScore, allianceStationDisplayMode, scoringStatus
+	readWebsocketMultiple(t, ws, 5) // matchLoad, realtimeScore, allianceStationDisplayMode, scoringStatus, onDeck
 	assert.Equal(t, field.PreMatch, web.arena.MatchState)
 
 	// Test changing the displays.
 	ws.Write("setAudienceDisplay", "logo")
 	readWebsocketType(t, ws, "audienceDisplayMode")
+	readWebsocketType(t, ws, "streamAudienceDisplayMode")
 	assert.Equal(t, "logo", web.arena.AudienceDisplayMode)
+	assert.Equal(t, "logo", web.arena.StreamAudienceDisplayMode)
+	ws.Write("setAudienceDisplayOutput", map[string]string{"output": "stream", "mode": "sponsor"})
+	readWebsocketType(t, ws, "streamAudienceDisplayMode")
+	assert.Equal(t, "logo", web.arena.AudienceDisplayMode)
+	assert.Equal(t, "sponsor", web.arena.StreamAudienceDisplayMode)
+	ws.Write("setAudienceDisplayOutput", map[string]string{"output": "stage", "mode": "bracket"})
+	readWebsocketType(t, ws, "audienceDisplayMode")
+	assert.Equal(t, "bracket", web.arena.AudienceDisplayMode)
+	assert.Equal(t, "sponsor", web.arena.StreamAudienceDisplayMode)
+	ws.Write("setAudienceDisplayOutput", map[string]string{"output": "lobby", "mode": "logo"})
+	assert.Contains(t, readWebsocketError(t, ws), "Invalid audience display output 'lobby'.")
 	ws.Write("setAllianceStationDisplay", "logo")
 	readWebsocketType(t, ws, "allianceStationDisplayMode")
 	assert.Equal(t, "logo", web.arena.AllianceStationDisplayMode)
+
+	// Test stepping through the show flow.
+	ws.Write("advanceShowFlow", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "no show flow steps")
+	web.arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "sponsor", DisplayOrder: 1})
+	ws.Write("advanceShowFlow", nil)
+	readWebsocketMultiple(t, ws, 2) // audienceDisplayMode, arenaStatus; the stream is already showing the sponsor reel
+	assert.Equal(t, "sponsor", web.arena.AudienceDisplayMode)
+	assert.Equal(t, 0, web.arena.ShowFlowPosition)
+	ws.Write("resetShowFlow", nil)
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.Equal(t, -1, web.arena.ShowFlowPosition)
+
+	// Test playing a video stinger.
+	ws.Write("playVideoStinger", 1)
+	assert.Contains(t, readWebsocketError(t, ws), "video stinger 1 does not exist")
+	web.arena.Database.CreateVideoStinger(
+		&model.VideoStinger{Name: "Match Start", VideoFile: "start.mp4", NextScreen: "match"},
+	)
+	ws.Write("playVideoStinger", 1)
+	readWebsocketType(t, ws, "audienceDisplayMode")
+	readWebsocketType(t, ws, "streamAudienceDisplayMode")
+	assert.Equal(t, "stinger", web.arena.AudienceDisplayMode)
+
+	// Test marking a replay.
+	ws.Write("markReplay", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "the replay system integration is not enabled")
+
+	// Test showing lower thirds.
+	web.arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Welcome", DisplayOrder: 1})
+	web.arena.Database.CreateLowerThird(&model.LowerThird{TopText: "Emcee", DisplayOrder: 2})
+	web.arena.Database.CreateLowerThirdPlaylist(&model.LowerThirdPlaylist{Name: "Opening", LowerThirdIds: []int{2}})
+	ws.Write("showLowerThird", 1)
+	readWebsocketType(t, ws, "lowerThird")
+	assert.True(t, web.arena.ShowLowerThird)
+	assert.Equal(t, "Welcome", web.arena.LowerThird.TopText)
+	ws.Write("hideLowerThird", nil)
+	readWebsocketType(t, ws, "lowerThird")
+	assert.False(t, web.arena.ShowLowerThird)
+	ws.Write("advanceLowerThirdPlaylist", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "no lower third playlist has been selected")
+	ws.Write("selectLowerThirdPlaylist", 1)
+	readWebsocketType(t, ws, "lowerThird")
+	ws.Write("advanceLowerThirdPlaylist", nil)
+	lowerThird := readWebsocketType(t, ws, "lowerThird").(map[string]any)
+	assert.Equal(t, "Emcee", lowerThird["LowerThird"].(map[string]any)["TopText"])
+	assert.Equal(t, 0.0, lowerThird["PlaylistPosition"])
+
+	// Test toggling the robot simulation.
+	ws.Write("setRobotSimulation", "on")
+	assert.Contains(t, readWebsocketError(t, ws), "Failed to parse")
+	ws.Write("setRobotSimulation", true)
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.True(t, web.arena.RobotSimulationEnabled)
+	ws.Write("setRobotSimulation", false)
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.False(t, web.arena.RobotSimulationEnabled)
+
+	// Test confirming field staff readiness.
+	ws.Write("setStaffReady", map[string]any{"role": "scorekeeper", "ready": true})
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.True(t, web.arena.StaffReadiness.ScorekeeperReady)
+	ws.Write("setStaffReady", map[string]any{"role": "announcer", "ready": true})
+	assert.Contains(t, readWebsocketError(t, ws), "invalid staff role")
+
+	// Test resuming or discarding a match interrupted by a server restart.
+	ws.Write("resumeInterruptedMatch", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "no interrupted match")
+	ws.Write("discardInterruptedMatch", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "no interrupted match")
+	web.arena.InterruptedMatch = &model.ArenaSnapshot{MatchId: 1114}
+	ws.Write("discardInterruptedMatch", nil)
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.Nil(t, web.arena.InterruptedMatch)
 }
 
 func TestMatchPlayWebsocketLoadMatch(t *testing.T) {
@@ -368,7 +603,7 @@ func TestMatchPlayWebsocketLoadMatch(t *testing.T) {
 	ws := websocket.NewTestWebsocket(conn)
 
 	// Should get a few status updates right after connection.
-	readWebsocketMultiple(t, ws, 10)
+	readWebsocketMultiple(t, ws, 14)
 
 	web.arena.Database.CreateTeam(&model.Team{Id: 101})
 	web.arena.Database.CreateTeam(&model.Team{Id: 102})
@@ -383,7 +618,7 @@ func TestMatchPlayWebsocketLoadMatch(t *testing.T) {
 	matchIdMessage := struct{ MatchId int }{match.Id}
 	ws.Write("loadMatch", matchIdMessage)
 	readWebsocketType(t, ws, "matchLoad")
-	readWebsocketMultiple(t, ws, 3)
+	readWebsocketMultiple(t, ws, 4)
 	assert.Equal(t, 101, web.arena.CurrentMatch.Red1)
 	assert.Equal(t, 102, web.arena.CurrentMatch.Red2)
 	assert.Equal(t, 103, web.arena.CurrentMatch.Red3)
@@ -395,7 +630,7 @@ func TestMatchPlayWebsocketLoadMatch(t *testing.T) {
 	matchIdMessage.MatchId = 0
 	ws.Write("loadMatch", matchIdMessage)
 	readWebsocketType(t, ws, "matchLoad")
-	readWebsocketMultiple(t, ws, 3)
+	readWebsocketMultiple(t, ws, 4)
 	assert.Equal(t, 0, web.arena.CurrentMatch.Red1)
 	assert.Equal(t, 0, web.arena.CurrentMatch.Red2)
 	assert.Equal(t, 0, web.arena.CurrentMatch.Red3)
@@ -409,6 +644,74 @@ func TestMatchPlayWebsocketLoadMatch(t *testing.T) {
 	assert.Contains(t, readWebsocketError(t, ws), "invalid match ID 254")
 }
 
+func TestMatchPlayWebsocketNextMatch(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 101})
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 102})
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketMultiple(t, ws, 14)
+
+	ws.Write("setNextMatch", map[string]int{"matchId": 254})
+	assert.Equal(t, "match does not exist", readWebsocketError(t, ws))
+	ws.Write("setNextMatch", map[string]int{"matchId": 2})
+	readWebsocketType(t, ws, "onDeck")
+	if assert.NotNil(t, web.arena.OnDeck) {
+		assert.Equal(t, "Q2", web.arena.OnDeck.Match.ShortName)
+		assert.True(t, web.arena.OnDeck.IsOverride)
+	}
+
+	ws.Write("loadNextMatch", nil)
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketMultiple(t, ws, 4)
+	assert.Equal(t, "Q2", web.arena.CurrentMatch.ShortName)
+	if assert.NotNil(t, web.arena.OnDeck) {
+		assert.Equal(t, "Q1", web.arena.OnDeck.Match.ShortName)
+		assert.False(t, web.arena.OnDeck.IsOverride)
+	}
+
+	ws.Write("setMatchBye", map[string]any{"matchId": 1, "bye": true})
+	readWebsocketType(t, ws, "onDeck")
+	assert.Nil(t, web.arena.OnDeck)
+	match, _ := web.arena.Database.GetMatchById(1)
+	assert.True(t, match.IsBye())
+	ws.Write("setMatchBye", map[string]any{"matchId": 2, "bye": true})
+	assert.Equal(t, "match Q2 is already loaded", readWebsocketError(t, ws))
+}
+
+func TestMatchPlayWebsocketCommitRequiresCardConfirmation(t *testing.T) {
+	web := setupTestWeb(t)
+	match := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 101, Red2: 102, Red3: 103,
+		Blue1: 104, Blue2: 105, Blue3: 106}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 14)
+
+	web.arena.MatchState = field.PostMatch
+	ws.Write("commitResults", nil)
+	assert.Equal(t, "cannot commit match until the head referee has confirmed the cards", readWebsocketError(t, ws))
+	assert.Nil(t, web.arena.ConfirmCards())
+	readWebsocketType(t, ws, "scoringStatus")
+	ws.Write("commitResults", nil)
+	readWebsocketType(t, ws, "scorePosted")
+	savedMatch, _ := web.arena.Database.GetMatchById(match.Id)
+	assert.True(t, savedMatch.IsComplete())
+}
+
 func TestMatchPlayWebsocketShowAndClearResult(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -420,7 +723,7 @@ func TestMatchPlayWebsocketShowAndClearResult(t *testing.T) {
 	ws := websocket.NewTestWebsocket(conn)
 
 	// Should get a few status updates right after connection.
-	readWebsocketMultiple(t, ws, 10)
+	readWebsocketMultiple(t, ws, 14)
 
 	matchIdMessage := struct{ MatchId int }{1}
 	ws.Write("showResult", matchIdMessage)
@@ -457,7 +760,7 @@ func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	ws := websocket.NewTestWebsocket(conn)
 
 	// Should get a few status updates right after connection.
-	readWebsocketMultiple(t, ws, 10)
+	readWebsocketMultiple(t, ws, 14)
 
 	web.arena.AllianceStations["R1"].Bypass = true
 	web.arena.AllianceStations["R2"].Bypass = true
@@ -467,11 +770,13 @@ func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	web.arena.AllianceStations["B3"].Bypass = true
 	assert.Nil(t, web.arena.StartMatch())
 	web.arena.Update()
-	messages := readWebsocketMultiple(t, ws, 5)
+	messages := readWebsocketMultiple(t, ws, 6)
 	_, ok := messages["matchTime"]
 	assert.True(t, ok)
 	_, ok = messages["audienceDisplayMode"]
 	assert.True(t, ok)
+	_, ok = messages["streamAudienceDisplayMode"]
+	assert.True(t, ok)
 	_, ok = messages["allianceStationDisplayMode"]
 	assert.True(t, ok)
 	_, ok = messages["eventStatus"]
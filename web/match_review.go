@@ -10,20 +10,26 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type MatchReviewListItem struct {
-	Id         int
-	ShortName  string
-	Time       string
-	RedTeams   []int
-	BlueTeams  []int
-	RedScore   int
-	BlueScore  int
-	ColorClass string
-	IsComplete bool
+	Id            int
+	TypeOrder     int
+	ShortName     string
+	Time          string
+	RedTeams      []int
+	BlueTeams     []int
+	RedScore      int
+	BlueScore     int
+	ColorClass    string
+	IsComplete    bool
+	CanReplay     bool
+	ReplayReason  string
+	NumScoreEdits int
 }
 
 // Shows the match review interface.
@@ -72,7 +78,7 @@ func (web *Web) matchReviewHandler(w http.ResponseWriter, r *http.Request) {
 
 // Shows the page to edit the results for a match.
 func (web *Web) matchReviewEditGetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.RefereeRole, model.ReadOnlyRole) {
 		return
 	}
 
@@ -108,11 +114,11 @@ func (web *Web) matchReviewEditGetHandler(w http.ResponseWriter, r *http.Request
 
 // Updates the results for a match.
 func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
 		return
 	}
 
-	match, _, isCurrent, err := web.getMatchResultFromRequest(r)
+	match, oldMatchResult, isCurrent, err := web.getMatchResultFromRequest(r)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -134,19 +140,167 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 		web.arena.BlueRealtimeScore.CurrentScore = *matchResult.BlueScore
 		web.arena.RedRealtimeScore.Cards = matchResult.RedCards
 		web.arena.BlueRealtimeScore.Cards = matchResult.BlueCards
+		if matchResult.RedCardRules != nil && matchResult.BlueCardRules != nil {
+			web.arena.RedRealtimeScore.CardRules = matchResult.RedCardRules
+			web.arena.BlueRealtimeScore.CardRules = matchResult.BlueCardRules
+		}
 
-		http.Redirect(w, r, "/match_play", 303)
+		http.Redirect(w, r, web.arena.PathPrefix()+"/match_play", 303)
 	} else {
+		changes := model.DiffMatchResults(oldMatchResult, &matchResult)
 		err = web.commitMatchScore(match, &matchResult, true)
 		if err != nil {
 			handleWebErr(w, err)
 			return
 		}
 
+		// Keep a record of the edit so that it can be reviewed in case of a protest.
+		if len(changes) > 0 {
+			scoreEdit := model.ScoreEdit{
+				MatchId:    match.Id,
+				PlayNumber: matchResult.PlayNumber,
+				EditedBy:   web.getRequestUsername(r),
+				EditedAt:   time.Now(),
+				Changes:    changes,
+			}
+			if err = web.arena.Database.CreateScoreEdit(&scoreEdit); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+
 		http.Redirect(w, r, "/match_review", 303)
 	}
 }
 
+// Shows the audit trail of the edits made to a match's score.
+func (web *Web) matchReviewAuditGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.RefereeRole, model.ReadOnlyRole) {
+		return
+	}
+
+	matchId, _ := strconv.Atoi(r.PathValue("matchId"))
+	match, err := web.arena.Database.GetMatchById(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		handleWebErr(w, fmt.Errorf("Error: No such match: %d", matchId))
+		return
+	}
+	scoreEdits, err := web.arena.Database.GetScoreEditsForMatch(match.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	template, err := web.parseFiles("templates/match_review_audit.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match      *model.Match
+		ScoreEdits []model.ScoreEdit
+	}{web.arena.EventSettings, match, scoreEdits}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Exports the timelines of events recorded during each play of a match as JSON, for use in resolving disputes.
+func (web *Web) matchReviewTimelineGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.RefereeRole, model.ReadOnlyRole) {
+		return
+	}
+
+	matchId, _ := strconv.Atoi(r.PathValue("matchId"))
+	match, err := web.arena.Database.GetMatchById(matchId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		handleWebErr(w, fmt.Errorf("Error: No such match: %d", matchId))
+		return
+	}
+	matchTimelines, err := web.arena.Database.GetMatchTimelinesForMatch(match.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if matchTimelines == nil {
+		// Export an empty list rather than null for matches that haven't been played.
+		matchTimelines = []model.MatchTimeline{}
+	}
+
+	jsonData, err := json.MarshalIndent(matchTimelines, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"timeline_%s.json\"", match.ShortName))
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Flags a match for replay and clones it into the schedule at the requested position.
+func (web *Web) matchReviewReplayPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole) {
+		return
+	}
+
+	matchId, _ := strconv.Atoi(r.PathValue("matchId"))
+	reason, err := model.ReplayReasonFromString(r.PostFormValue("reason"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	afterTypeOrder, err := strconv.Atoi(r.PostFormValue("afterTypeOrder"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	replayMatch, err := tournament.CreateReplayMatch(web.arena.Database, matchId, reason, afterTypeOrder)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if err = web.arena.RefreshSchedule(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Remove the superseded result from the cards and rankings.
+	if replayMatch.ShouldUpdateCards() {
+		if err = tournament.CalculateTeamCards(web.arena.Database, replayMatch.Type); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	if replayMatch.ShouldUpdateRankings() {
+		if _, err = tournament.CalculateRankings(web.arena.Database, true); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if err = web.updateProjections(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/match_review", 303)
+}
+
 // Load the match result for the match referenced in the HTTP query string.
 func (web *Web) getMatchResultFromRequest(r *http.Request) (*model.Match, *model.MatchResult, bool, error) {
 	// If editing the current match, get it from memory instead of the DB.
@@ -183,11 +337,21 @@ func (web *Web) buildMatchReviewList(matchType model.MatchType) ([]MatchReviewLi
 		return []MatchReviewListItem{}, err
 	}
 
+	scoreEdits, err := web.arena.Database.GetAllScoreEdits()
+	if err != nil {
+		return []MatchReviewListItem{}, err
+	}
+	numScoreEdits := make(map[int]int)
+	for _, scoreEdit := range scoreEdits {
+		numScoreEdits[scoreEdit.MatchId]++
+	}
+
 	matchReviewList := make([]MatchReviewListItem, len(matches))
 	for i, match := range matches {
 		matchReviewList[i].Id = match.Id
+		matchReviewList[i].TypeOrder = match.TypeOrder
 		matchReviewList[i].ShortName = match.ShortName
-		matchReviewList[i].Time = match.Time.Local().Format("Mon 1/02 03:04 PM")
+		matchReviewList[i].Time = match.Time.In(web.arena.EventSettings.Location()).Format("Mon 1/02 03:04 PM")
 		matchReviewList[i].RedTeams = []int{match.Red1, match.Red2, match.Red3}
 		matchReviewList[i].BlueTeams = []int{match.Blue1, match.Blue2, match.Blue3}
 		matchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
@@ -212,6 +376,12 @@ func (web *Web) buildMatchReviewList(matchType model.MatchType) ([]MatchReviewLi
 			matchReviewList[i].ColorClass = ""
 			matchReviewList[i].IsComplete = false
 		}
+		matchReviewList[i].NumScoreEdits = numScoreEdits[match.Id]
+		if match.IsReplaced() {
+			matchReviewList[i].ReplayReason = match.ReplayReason.String()
+		} else {
+			matchReviewList[i].CanReplay = match.ShouldAllowReplay() && matchReviewList[i].IsComplete
+		}
 	}
 
 	return matchReviewList, nil
@@ -84,6 +84,35 @@ func TestMatchReviewEditExistingResult(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), ">QF4-3<")
 	assert.Contains(t, recorder.Body.String(), ">6<")  // The red score
 	assert.Contains(t, recorder.Body.String(), ">25<") // The blue score
+	assert.Contains(t, recorder.Body.String(), "Audit (1)")
+
+	// Check that the edit was recorded in the audit trail.
+	scoreEdits, err := web.arena.Database.GetScoreEditsForMatch(match.Id)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(scoreEdits)) {
+		assert.Equal(t, 2, scoreEdits[0].PlayNumber)
+		assert.Equal(t, "unknown", scoreEdits[0].EditedBy)
+		assert.Contains(t, scoreEdits[0].Changes, model.ScoreEditChange{Alliance: "red", Field: "Summary.Score", OldValue: "81", NewValue: "6"})
+		assert.Contains(t, scoreEdits[0].Changes, model.ScoreEditChange{Alliance: "red", Field: "Cards[105]", NewValue: "yellow"})
+	}
+	recorder = web.getHttpResponse(fmt.Sprintf("/match_review/%d/audit", match.Id))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Score Edits for Quarterfinal 4-3")
+	assert.Contains(t, recorder.Body.String(), "Summary.Score")
+	recorder = web.getHttpResponse("/reports/csv/score_edits")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "QF4-3,")
+	assert.Contains(t, recorder.Body.String(), ",red,Summary.Score,81,6\n")
+	recorder = web.getHttpResponse("/reports/csv/results/playoff")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "QF4-3,Playoff,")
+	assert.Contains(t, recorder.Body.String(), ",6,25,2,1,unknown,")
+
+	// Submitting the same score again should not be recorded as an edit.
+	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	scoreEdits, _ = web.arena.Database.GetScoreEditsForMatch(match.Id)
+	assert.Equal(t, 1, len(scoreEdits))
 }
 
 func TestMatchReviewCreateNewResult(t *testing.T) {
@@ -173,3 +202,108 @@ func TestMatchReviewEditCurrentMatch(t *testing.T) {
 	assert.Equal(t, 1, len(web.arena.RedRealtimeScore.Cards))
 	assert.Equal(t, 0, len(web.arena.BlueRealtimeScore.Cards))
 }
+
+func TestMatchReviewReplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match1)
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match1.Id, 1))
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2"}
+	web.arena.Database.CreateMatch(&match2)
+	tournament.CalculateRankings(web.arena.Database, false)
+	rankings, _ := web.arena.Database.GetAllRankings()
+	assert.Equal(t, 6, len(rankings))
+
+	recorder := web.getHttpResponse("/match_review")
+	assert.Contains(t, recorder.Body.String(), "/match_review/1/replay")
+
+	recorder = web.postHttpResponse("/match_review/1/replay", "reason=blorpy&afterTypeOrder=2")
+	assert.Equal(t, 500, recorder.Code)
+	recorder = web.postHttpResponse("/match_review/1/replay", "reason=fieldFault&afterTypeOrder=2")
+	assert.Equal(t, 303, recorder.Code)
+
+	matches, _ := web.arena.Database.GetMatchesByType(model.Qualification, false)
+	if assert.Equal(t, 3, len(matches)) {
+		assert.Equal(t, "Q1R", matches[2].ShortName)
+		assert.Equal(t, match1.Id, matches[2].ReplayOfMatchId)
+	}
+	rankings, _ = web.arena.Database.GetAllRankings()
+	assert.Equal(t, 0, len(rankings))
+
+	recorder = web.getHttpResponse("/match_review")
+	assert.NotContains(t, recorder.Body.String(), "/match_review/1/replay")
+	assert.Contains(t, recorder.Body.String(), "Replayed: Field Fault")
+}
+
+func TestMatchReviewReplayBeforeLoadedMatch(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6, Status: game.RedWonMatch, Time: time.Unix(500, 0)}
+	web.arena.Database.CreateMatch(&match1)
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match1.Id, 1))
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6, Time: time.Unix(1000, 0)}
+	web.arena.Database.CreateMatch(&match2)
+	match3 := model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Time: time.Unix(1500, 0)}
+	web.arena.Database.CreateMatch(&match3)
+	assert.Nil(t, web.arena.LoadMatch(&match2))
+
+	// Insert the replay ahead of the loaded match and check that the loaded match picks up its new position.
+	recorder := web.postHttpResponse("/match_review/1/replay", "reason=fieldFault&afterTypeOrder=1")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 3, web.arena.CurrentMatch.TypeOrder)
+	assert.Equal(t, int64(1500), web.arena.CurrentMatch.Time.Unix())
+	assert.Equal(t, "Q1R", web.arena.OnDeck.Match.ShortName)
+
+	// Check that committing the loaded match doesn't write its old position back over the shifted schedule.
+	assert.Nil(t, web.commitMatchScore(web.arena.CurrentMatch, model.BuildTestMatchResult(match2.Id, 0), true))
+	matches, _ := web.arena.Database.GetMatchesByType(model.Qualification, true)
+	if assert.Equal(t, 4, len(matches)) {
+		assert.Equal(t, "Q1", matches[0].ShortName)
+		assert.Equal(t, "Q1R", matches[1].ShortName)
+		assert.Equal(t, 2, matches[1].TypeOrder)
+		assert.Equal(t, "Q2", matches[2].ShortName)
+		assert.Equal(t, 3, matches[2].TypeOrder)
+		assert.Equal(t, int64(1500), matches[2].Time.Unix())
+		assert.Equal(t, "Q3", matches[3].ShortName)
+		assert.Equal(t, 4, matches[3].TypeOrder)
+	}
+}
+
+func TestMatchReviewTimeline(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	web.arena.Database.CreateMatch(&match)
+	recorder := web.getHttpResponse("/match_review/1/timeline")
+	assert.Equal(t, 200, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "[]", recorder.Body.String())
+
+	// Committing the match from the arena should save its timeline, but edits from match review should not.
+	web.arena.RecordTimelineEvent(model.TimelineStop, "B2", "E-stop activated")
+	assert.Nil(t, web.commitMatchScore(&match, model.BuildTestMatchResult(match.Id, 0), false))
+	assert.Nil(t, web.commitMatchScore(&match, model.BuildTestMatchResult(match.Id, 0), true))
+	matchTimelines, err := web.arena.Database.GetMatchTimelinesForMatch(match.Id)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(matchTimelines)) {
+		assert.Equal(t, 1, matchTimelines[0].PlayNumber)
+		assert.Equal(t, 1, len(matchTimelines[0].Events))
+	}
+
+	recorder = web.getHttpResponse("/match_review")
+	assert.Contains(t, recorder.Body.String(), "/match_review/1/timeline")
+	recorder = web.getHttpResponse("/match_review/1/timeline")
+	assert.Equal(t, 200, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	assert.Contains(t, recorder.Body.String(), "\"PlayNumber\": 1")
+	assert.Contains(t, recorder.Body.String(), "\"Source\": \"B2\"")
+	assert.Contains(t, recorder.Body.String(), "\"Description\": \"E-stop activated\"")
+
+	recorder = web.getHttpResponse("/match_review/2/timeline")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No such match")
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the pit display, which rotates among the rankings, upcoming matches, recent results, season
+// standings and an announcement.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+	"slices"
+)
+
+const (
+	numPitDisplayMatchesToShow         = 8
+	numPitDisplaySeasonStandingsToShow = 24
+)
+
+type pitDisplayResult struct {
+	model.Match
+	RedScore  int
+	BlueScore int
+	RedWon    bool
+	BlueWon   bool
+}
+
+// Renders the pit display.
+func (web *Web) pitDisplayHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.enforceDisplayConfiguration(w, r, nil) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/pit_display.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+	}{web.arena.EventSettings}
+	err = template.ExecuteTemplate(w, "pit_display.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Renders a partial template containing the rankings, upcoming matches, recent results and season standings pages of
+// the pit display.
+func (web *Web) pitDisplayPagesHandler(w http.ResponseWriter, r *http.Request) {
+	rankings, err := web.arena.Database.GetAllRankings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamNicknames := make(map[int]string)
+	for _, team := range teams {
+		teamNicknames[team.Id] = team.Nickname
+	}
+	rankingsWithNicknames := make([]RankingWithNickname, len(rankings))
+	for i, ranking := range rankings {
+		rankingsWithNicknames[i] = RankingWithNickname{ranking, teamNicknames[ranking.TeamId]}
+	}
+
+	matches, err := web.getPitDisplayMatches()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var upcomingMatches []model.Match
+	var recentResults []pitDisplayResult
+	for _, match := range matches {
+		if !match.IsComplete() {
+			if len(upcomingMatches) < numPitDisplayMatchesToShow {
+				upcomingMatches = append(upcomingMatches, match)
+			}
+			continue
+		}
+		matchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		result := pitDisplayResult{
+			Match: match, RedWon: match.Status == game.RedWonMatch, BlueWon: match.Status == game.BlueWonMatch,
+		}
+		if matchResult != nil {
+			result.RedScore = matchResult.RedScoreSummary().Score
+			result.BlueScore = matchResult.BlueScoreSummary().Score
+		}
+		recentResults = append(recentResults, result)
+	}
+
+	// Show the most recent results first.
+	slices.Reverse(recentResults)
+	if len(recentResults) > numPitDisplayMatchesToShow {
+		recentResults = recentResults[:numPitDisplayMatchesToShow]
+	}
+
+	// Split the rankings into two columns so that more of them fit on the screen at once.
+	splitIndex := (len(rankingsWithNicknames) + 1) / 2
+
+	// Only gather the season standings if they are shown, since doing so requires opening all the past events.
+	var seasonStandings []seasonStanding
+	if slices.Contains(web.arena.EventSettings.PitDisplayPages, "season") {
+		if seasonStandings, err = web.getSeasonStandings(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if len(seasonStandings) > numPitDisplaySeasonStandingsToShow {
+			seasonStandings = seasonStandings[:numPitDisplaySeasonStandingsToShow]
+		}
+	}
+
+	template, err := web.parseFiles("templates/pit_display_pages.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		RankingColumns  [][]RankingWithNickname
+		UpcomingMatches []model.Match
+		RecentResults   []pitDisplayResult
+		SeasonStandings []seasonStanding
+	}{
+		[][]RankingWithNickname{rankingsWithNicknames[:splitIndex], rankingsWithNicknames[splitIndex:]},
+		upcomingMatches,
+		recentResults,
+		seasonStandings,
+	}
+	err = template.ExecuteTemplate(w, "pit_display_pages.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the pit display to receive updates.
+func (web *Web) pitDisplayWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	display, err := web.registerDisplay(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer web.arena.MarkDisplayDisconnected(display.DisplayConfiguration.Id)
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
+	ws.HandleNotifiers(display.Notifier, web.arena.PitDisplayNotifier, web.arena.MatchLoadNotifier,
+		web.arena.ScorePostedNotifier, web.arena.EventStatusNotifier, web.arena.AgendaNotifier,
+		web.arena.ReloadDisplaysNotifier)
+}
+
+// Returns the matches of the type currently being played, or of the latest phase of the event that has a schedule if a
+// test match is loaded.
+func (web *Web) getPitDisplayMatches() ([]model.Match, error) {
+	if web.arena.CurrentMatch.Type != model.Test {
+		return web.arena.Database.GetMatchesByType(web.arena.CurrentMatch.Type, false)
+	}
+	for _, matchType := range []model.MatchType{model.Playoff, model.Qualification, model.Practice} {
+		matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+		if err != nil || len(matches) > 0 {
+			return matches, err
+		}
+	}
+	return nil, nil
+}
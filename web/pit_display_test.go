@@ -0,0 +1,81 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPitDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/pit?displayId=1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Pit Display - Untitled Event - Cheesy Arena")
+}
+
+func TestPitDisplayPages(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 1})
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "ChezyPof"})
+	match := model.Match{Type: model.Qualification, ShortName: "Q1", Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match.Id, 1))
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q2", Red1: 1503})
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Practice, ShortName: "P1"})
+
+	// The qualification matches should be shown when a test match is loaded.
+	recorder := web.getHttpResponse("/displays/pit/pages")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, "ChezyPof")
+	assert.Contains(t, body, "<td>Q1</td>")
+	assert.Contains(t, body, "red-teams winner")
+	assert.Contains(t, body, "<td>Q2</td>")
+	assert.Contains(t, body, "1503")
+	assert.NotContains(t, body, "<td>P1</td>")
+
+	// Check that only the team positions in use are shown when alliances are smaller than three teams.
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q3", Red2: 1678, Red3: 5940})
+	web.arena.EventSettings.TeamsPerAlliance = 2
+	body = web.getHttpResponse("/displays/pit/pages").Body.String()
+	assert.Contains(t, body, "1678")
+	assert.NotContains(t, body, "5940")
+	assert.Contains(t, body, `<th colspan="2" class="red-teams">Red Alliance</th>`)
+}
+
+func TestPitDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/pit/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	pitDisplay := readWebsocketType(t, ws, "pitDisplay").(map[string]any)
+	assert.Equal(t, []any{"rankings", "schedule", "results"}, pitDisplay["Pages"])
+	assert.Equal(t, 15.0, pitDisplay["PageDurationSec"])
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "scorePosted")
+	readWebsocketType(t, ws, "eventStatus")
+	readWebsocketType(t, ws, "agenda")
+
+	// Check that a change to the rotation configuration is pushed out.
+	web.arena.EventSettings.PitDisplayPages = []string{"announcement", "rankings"}
+	web.arena.EventSettings.PitDisplayAnnouncement = "Lunch is served"
+	web.arena.PitDisplayNotifier.Notify()
+	pitDisplay = readWebsocketType(t, ws, "pitDisplay").(map[string]any)
+	assert.Equal(t, []any{"rankings", "announcement"}, pitDisplay["Pages"])
+	assert.Equal(t, "Lunch is served", pitDisplay["Announcement"])
+}
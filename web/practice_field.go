@@ -0,0 +1,84 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for running the match timer and sounds on the venue's standalone practice field.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"io"
+	"net/http"
+)
+
+// Shows the practice field timer page.
+func (web *Web) practiceFieldHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.FtaRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/practice_field.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		MatchSounds []*game.MatchSound
+	}{web.arena.EventSettings, game.MatchSounds}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the practice field timer page to receive updates and send commands.
+func (web *Web) practiceFieldWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.FtaRole) {
+		return
+	}
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	practiceField := web.arena.PracticeField
+	go ws.HandleNotifiers(
+		web.arena.MatchTimingNotifier, practiceField.MatchTimeNotifier, practiceField.PlaySoundNotifier,
+	)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, _, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Web.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "startMatch":
+			err = practiceField.StartMatch()
+		case "abortMatch":
+			err = practiceField.AbortMatch()
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+			continue
+		}
+		if err != nil {
+			ws.WriteError(err.Error())
+		}
+	}
+}
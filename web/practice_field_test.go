@@ -0,0 +1,51 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPracticeField(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/practice_field")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Practice Field - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "id=\"sound-start\"")
+}
+
+func TestPracticeFieldWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/practice_field/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "matchTiming")
+	readWebsocketType(t, ws, "matchTime")
+
+	// Check that the practice field timer runs without starting a match on the main field.
+	ws.Write("startMatch", nil)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, field.WarmupPeriod, web.arena.PracticeField.MatchState)
+	assert.Equal(t, field.PreMatch, web.arena.MatchState)
+	ws.Write("startMatch", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "cannot start practice match")
+
+	ws.Write("abortMatch", nil)
+	readWebsocketType(t, ws, "playSound")
+	assert.Equal(t, field.PostMatch, web.arena.PracticeField.MatchState)
+	ws.Write("blorpy", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "Invalid message type 'blorpy'.")
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for the projected final qualification rankings and remaining match outcomes.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"net/http"
+)
+
+// Global var to hold the most recently calculated projections; nil if they need to be recalculated.
+var cachedProjections *tournament.Projections
+
+// Shows the page displaying the projected final rankings and remaining match win probabilities.
+func (web *Web) projectionsGetHandler(w http.ResponseWriter, r *http.Request) {
+	projections, err := web.getProjections()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamNicknames := make(map[int]string)
+	for _, team := range teams {
+		teamNicknames[team.Id] = team.Nickname
+	}
+
+	template, err := web.parseFiles("templates/projections.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Projections   *tournament.Projections
+		TeamNicknames map[int]string
+	}{web.arena.EventSettings, projections, teamNicknames}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the projected final rankings and remaining match win probabilities.
+func (web *Web) projectionsApiHandler(w http.ResponseWriter, r *http.Request) {
+	projections, err := web.getProjections()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	jsonData, err := json.MarshalIndent(projections, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Recalculates the projections from the current rankings and schedule and caches the result.
+func (web *Web) updateProjections() error {
+	projections, err := tournament.CalculateProjections(web.arena.Database, tournament.NumProjectionSimulations)
+	if err != nil {
+		return err
+	}
+	cachedProjections = projections
+	return nil
+}
+
+// Returns the cached projections, calculating them first if necessary.
+func (web *Web) getProjections() (*tournament.Projections, error) {
+	if cachedProjections == nil {
+		if err := web.updateProjections(); err != nil {
+			return nil, err
+		}
+	}
+	return cachedProjections, nil
+}
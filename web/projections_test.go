@@ -0,0 +1,58 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProjections(t *testing.T) {
+	web := setupTestWeb(t)
+	cachedProjections = nil
+
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Red1: 6, Red2: 5, Red3: 4,
+		Blue1: 3, Blue2: 2, Blue3: 1}
+	web.arena.Database.CreateMatch(&match1)
+	web.arena.Database.CreateMatch(&match2)
+	web.arena.Database.CreateTeam(&model.Team{Id: 1, Nickname: "Team One"})
+
+	recorder := web.getHttpResponse("/api/rankings/projections")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var projections tournament.Projections
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &projections))
+	assert.Equal(t, 2, projections.NumMatchesRemaining)
+	assert.Equal(t, 6, len(projections.Rankings))
+	assert.Equal(t, 2, len(projections.Matches))
+
+	// Committing a qualification match should update the projections.
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.RedScore = game.TestScore1()
+	matchResult.BlueScore = game.TestScore2()
+	assert.Nil(t, web.commitMatchScore(&match1, matchResult, true))
+	recorder = web.getHttpResponse("/api/rankings/projections")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &projections))
+	assert.Equal(t, 1, projections.NumMatchesRemaining)
+	if assert.Equal(t, 1, len(projections.Matches)) {
+		assert.Equal(t, "Q2", projections.Matches[0].ShortName)
+	}
+	for _, projection := range projections.Rankings {
+		assert.NotEqual(t, 0, projection.CurrentRank)
+	}
+
+	recorder = web.getHttpResponse("/projections")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Projected Final Rankings")
+	assert.Contains(t, recorder.Body.String(), "Team One")
+	assert.Contains(t, recorder.Body.String(), "Q2")
+}
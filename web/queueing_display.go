@@ -20,7 +20,7 @@ const (
 
 // Renders the queueing display that shows upcoming matches and timing information.
 func (web *Web) queueingDisplayHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.enforceDisplayConfiguration(w, r, nil) {
+	if !web.enforceDisplayConfiguration(w, r, map[string]string{"callouts": "false"}) {
 		return
 	}
 
@@ -122,7 +122,11 @@ func (web *Web) queueingDisplayWebsocketHandler(w http.ResponseWriter, r *http.R
 	}
 	defer ws.Close()
 
-	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
-	ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier,
-		web.arena.MatchTimeNotifier, web.arena.EventStatusNotifier, web.arena.ReloadDisplaysNotifier)
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier,
+		web.arena.MatchTimeNotifier, web.arena.EventStatusNotifier, web.arena.AgendaNotifier,
+		web.arena.ReloadDisplaysNotifier)
+
+	// Keep reading from the websocket so that the client's clock synchronization requests get answered.
+	ws.HandleReads()
 }
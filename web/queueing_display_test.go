@@ -4,6 +4,7 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -14,10 +15,32 @@ func TestQueueingDisplay(t *testing.T) {
 	web := setupTestWeb(t)
 
 	recorder := web.getHttpResponse("/displays/queueing?displayId=1")
+	assert.Equal(t, 302, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Location"), "callouts=false")
+
+	recorder = web.getHttpResponse("/displays/queueing?displayId=1&callouts=true")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Queueing Display - Untitled Event - Cheesy Arena")
 }
 
+func TestQueueingDisplayMatchLoadSmallAlliances(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.TeamsPerAlliance = 2
+	match := model.Match{Type: model.Practice, TypeOrder: 1, ShortName: "P1", Red1: 254, Red2: 1114, Red3: 2056,
+		Blue1: 148, Blue2: 604, Blue3: 846}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	// Only the teams in the alliance positions in use should be listed.
+	recorder := web.getHttpResponse("/displays/queueing/match_load")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `data-team="1114"`)
+	assert.Contains(t, body, `data-team="604"`)
+	assert.NotContains(t, body, "2056")
+	assert.NotContains(t, body, "846")
+}
+
 func TestQueueingDisplayWebsocket(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -34,4 +57,5 @@ func TestQueueingDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "matchLoad")
 	readWebsocketType(t, ws, "matchTime")
 	readWebsocketType(t, ws, "eventStatus")
+	readWebsocketType(t, ws, "agenda")
 }
@@ -6,14 +6,16 @@
 package web
 
 import (
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"net/http"
 )
 
-// Renders the display which shows scrolling rankings.
+// Renders the display which shows scrolling rankings, optionally interspersed with the sponsor slides for use in the
+// pits.
 func (web *Web) rankingsDisplayHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.enforceDisplayConfiguration(w, r, map[string]string{"scrollMsPerRow": "1000"}) {
+	if !web.enforceDisplayConfiguration(w, r, map[string]string{"scrollMsPerRow": "1000", "sponsorSlides": "false"}) {
 		return
 	}
 
@@ -24,7 +26,8 @@ func (web *Web) rankingsDisplayHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	data := struct {
 		*model.EventSettings
-	}{web.arena.EventSettings}
+		RankingColumns []game.RankingColumn
+	}{web.arena.EventSettings, game.CurrentRankingPointConfig().Columns}
 	err = template.ExecuteTemplate(w, "rankings_display.html", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -13,9 +13,12 @@ import (
 func TestRankingsDisplay(t *testing.T) {
 	web := setupTestWeb(t)
 
-	recorder := web.getHttpResponse("/displays/rankings?displayId=1&scrollMsPerRow=700")
+	recorder := web.getHttpResponse("/displays/rankings?displayId=1&scrollMsPerRow=700&sponsorSlides=true")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Standings Display - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "<td class=\"team-field\">Coop</td>")
+	assert.Contains(t, recorder.Body.String(), "<td class=\"team-field\">{{this.ColumnTotals.CoopertitionPoints}}</td>")
+	assert.Contains(t, recorder.Body.String(), `id="sponsorSlideTemplate"`)
 }
 
 func TestRankingsDisplayWebsocket(t *testing.T) {
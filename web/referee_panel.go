@@ -9,18 +9,22 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
 	"io"
-	"log"
 	"net/http"
-	"strconv"
 )
 
 // Renders the referee interface for assigning fouls.
 func (web *Web) refereePanelHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	panelDevice, ok := web.panelDeviceHasRole(w, r, model.RefereeRole)
+	if !ok {
+		return
+	}
+	if err := web.setPanelDevicePath(panelDevice, r); err != nil {
+		handleWebErr(w, err)
 		return
 	}
 
@@ -32,7 +36,8 @@ func (web *Web) refereePanelHandler(w http.ResponseWriter, r *http.Request) {
 
 	data := struct {
 		*model.EventSettings
-	}{web.arena.EventSettings}
+		Rules map[int]*game.Rule
+	}{web.arena.EventSettings, game.GetAllRules()}
 	err = template.ExecuteTemplate(w, "base_no_navbar", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -42,12 +47,25 @@ func (web *Web) refereePanelHandler(w http.ResponseWriter, r *http.Request) {
 
 // Renders a partial template for when the foul list is updated.
 func (web *Web) refereePanelFoulListHandler(w http.ResponseWriter, r *http.Request) {
+	panel, err := field.NewRefereePanel(r.URL.Query().Get("position"), r.URL.Query().Get("rules"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	template, err := web.parseFiles("templates/referee_panel_foul_list.html")
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 
+	// Only offer the rules that this panel is responsible for.
+	rules := make(map[int]*game.Rule)
+	for id, rule := range game.GetAllRules() {
+		if panel.CanAssignRule(rule) {
+			rules[id] = rule
+		}
+	}
+
 	data := struct {
 		Match     *model.Match
 		RedFouls  []game.Foul
@@ -57,7 +75,7 @@ func (web *Web) refereePanelFoulListHandler(w http.ResponseWriter, r *http.Reque
 		web.arena.CurrentMatch,
 		web.arena.RedRealtimeScore.CurrentScore.Fouls,
 		web.arena.BlueRealtimeScore.CurrentScore.Fouls,
-		game.GetAllRules(),
+		rules,
 	}
 	err = template.ExecuteTemplate(w, "referee_panel_foul_list", data)
 	if err != nil {
@@ -68,7 +86,14 @@ func (web *Web) refereePanelFoulListHandler(w http.ResponseWriter, r *http.Reque
 
 // The websocket endpoint for the refereee interface client to send control commands and receive status updates.
 func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	panelDevice, ok := web.panelDeviceHasRole(w, r, model.RefereeRole)
+	if !ok {
+		return
+	}
+
+	panel, err := field.NewRefereePanel(r.URL.Query().Get("position"), r.URL.Query().Get("rules"))
+	if err != nil {
+		handleWebErr(w, err)
 		return
 	}
 
@@ -96,7 +121,10 @@ func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Scoring.Warn("Websocket read error.", "error", err)
+			return
+		}
+		if !web.panelDeviceIsActive(panelDevice, ws) {
 			return
 		}
 
@@ -112,20 +140,14 @@ func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 
-			// Add the foul to the correct alliance's list.
-			foul := game.Foul{IsTechnical: args.IsTechnical}
-			if args.Alliance == "red" {
-				web.arena.RedRealtimeScore.CurrentScore.Fouls =
-					append(web.arena.RedRealtimeScore.CurrentScore.Fouls, foul)
-			} else {
-				web.arena.BlueRealtimeScore.CurrentScore.Fouls =
-					append(web.arena.BlueRealtimeScore.CurrentScore.Fouls, foul)
-			}
+			web.arena.AddFoul(panel, args.Alliance, args.IsTechnical)
 			web.arena.RealtimeScoreNotifier.Notify()
+			web.arena.LiveScoreNotifier.Notify()
 		case "toggleFoulType", "updateFoulTeam", "updateFoulRule", "deleteFoul":
 			args := struct {
 				Alliance string
-				Index    int
+				FoulId   int
+				Revision int
 				TeamId   int
 				RuleId   int
 			}{}
@@ -135,31 +157,34 @@ func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 
-			// Find the foul in the correct alliance's list.
-			var fouls *[]game.Foul
-			if args.Alliance == "red" {
-				fouls = &web.arena.RedRealtimeScore.CurrentScore.Fouls
+			if messageType == "deleteFoul" {
+				err = web.arena.DeleteFoul(panel, args.Alliance, args.FoulId, args.Revision)
 			} else {
-				fouls = &web.arena.BlueRealtimeScore.CurrentScore.Fouls
-			}
-			if args.Index >= 0 && args.Index < len(*fouls) {
-				switch messageType {
-				case "toggleFoulType":
-					(*fouls)[args.Index].IsTechnical = !(*fouls)[args.Index].IsTechnical
-					(*fouls)[args.Index].RuleId = 0
-				case "deleteFoul":
-					*fouls = append((*fouls)[:args.Index], (*fouls)[args.Index+1:]...)
-				case "updateFoulTeam":
-					if (*fouls)[args.Index].TeamId == args.TeamId {
-						(*fouls)[args.Index].TeamId = 0
-					} else {
-						(*fouls)[args.Index].TeamId = args.TeamId
-					}
-				case "updateFoulRule":
-					(*fouls)[args.Index].RuleId = args.RuleId
-				}
-				web.arena.RealtimeScoreNotifier.Notify()
+				err = web.arena.EditFoul(
+					panel, args.Alliance, args.FoulId, args.Revision, func(foul *game.Foul) error {
+						switch messageType {
+						case "toggleFoulType":
+							foul.IsTechnical = !foul.IsTechnical
+							foul.RuleId = 0
+						case "updateFoulTeam":
+							if foul.TeamId == args.TeamId {
+								foul.TeamId = 0
+							} else {
+								foul.TeamId = args.TeamId
+							}
+						case "updateFoulRule":
+							return foul.AssignRule(args.RuleId)
+						}
+						return nil
+					},
+				)
 			}
+			if err != nil {
+				ws.WriteError(err.Error())
+			}
+			// Notify even on failure so that a recorded conflict reaches the other panels.
+			web.arena.RealtimeScoreNotifier.Notify()
+			web.arena.LiveScoreNotifier.Notify()
 		case "card":
 			args := struct {
 				Alliance string
@@ -172,28 +197,40 @@ func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 
-			// Set the card in the correct alliance's score.
-			var cards map[string]string
-			if args.Alliance == "red" {
-				cards = web.arena.RedRealtimeScore.Cards
-			} else {
-				cards = web.arena.BlueRealtimeScore.Cards
-			}
-			if web.arena.CurrentMatch.Type == model.Playoff {
-				// Cards apply to the whole alliance in playoffs.
-				if args.Alliance == "red" {
-					cards[strconv.Itoa(web.arena.CurrentMatch.Red1)] = args.Card
-					cards[strconv.Itoa(web.arena.CurrentMatch.Red2)] = args.Card
-					cards[strconv.Itoa(web.arena.CurrentMatch.Red3)] = args.Card
-				} else {
-					cards[strconv.Itoa(web.arena.CurrentMatch.Blue1)] = args.Card
-					cards[strconv.Itoa(web.arena.CurrentMatch.Blue2)] = args.Card
-					cards[strconv.Itoa(web.arena.CurrentMatch.Blue3)] = args.Card
-				}
-			} else {
-				cards[strconv.Itoa(args.TeamId)] = args.Card
+			web.arena.SetCard(args.Alliance, args.TeamId, args.Card)
+			web.arena.RealtimeScoreNotifier.Notify()
+			web.arena.LiveScoreNotifier.Notify()
+		case "assignCard":
+			if !panel.IsHeadReferee() {
+				ws.WriteError("Only the head referee may assign cards.")
+				continue
+			}
+			args := struct {
+				Alliance string
+				TeamId   int
+				Card     string
+				RuleId   int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+
+			if err = web.arena.AssignCard(args.Alliance, args.TeamId, args.Card, args.RuleId); err != nil {
+				ws.WriteError(err.Error())
+				continue
 			}
 			web.arena.RealtimeScoreNotifier.Notify()
+			web.arena.LiveScoreNotifier.Notify()
+		case "confirmCards":
+			if !panel.IsHeadReferee() {
+				ws.WriteError("Only the head referee may confirm cards.")
+				continue
+			}
+			if err = web.arena.ConfirmCards(); err != nil {
+				ws.WriteError(err.Error())
+			}
 		case "signalReset":
 			if web.arena.MatchState != field.PostMatch {
 				// Don't allow clearing the field until the match is over.
@@ -203,12 +240,25 @@ func (web *Web) refereePanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 			web.arena.AllianceStationDisplayMode = "fieldReset"
 			web.arena.AllianceStationDisplayModeNotifier.Notify()
 		case "commitMatch":
+			if !panel.IsHeadReferee() {
+				// Committing serves as the head referee's approval of the scores, so no other referee may do it.
+				ws.WriteError("Only the head referee may commit the match.")
+				continue
+			}
 			if web.arena.MatchState != field.PostMatch {
 				// Don't allow committing the fouls until the match is over.
 				continue
 			}
+			if web.arena.CardConfirmationPending() {
+				ws.WriteError("Cards must be confirmed before committing the match.")
+				continue
+			}
 			web.arena.RedRealtimeScore.FoulsCommitted = true
 			web.arena.BlueRealtimeScore.FoulsCommitted = true
+			if web.arena.ScoreReview != nil {
+				// Committing from the head referee panel also serves as the head referee's approval of the scores.
+				_ = web.arena.ApproveScores(field.HeadRefereeRole)
+			}
 			web.arena.FieldReset = true
 			web.arena.AllianceStationDisplayMode = "fieldReset"
 			web.arena.AllianceStationDisplayModeNotifier.Notify()
@@ -5,6 +5,7 @@ package web
 
 import (
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
@@ -21,6 +22,21 @@ func TestRefereePanel(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Referee Panel - Untitled Event - Cheesy Arena")
 }
 
+func TestRefereePanelFoulListSmallAlliances(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.CurrentMatch = &model.Match{Type: model.Qualification, Red1: 254, Red2: 1114, Red3: 2056, Blue1: 148}
+	web.arena.RedRealtimeScore.CurrentScore.Fouls = []game.Foul{{FoulId: 1}}
+
+	// Only the teams in the alliance positions in use should be offered for the foul.
+	recorder := web.getHttpResponse("/panels/referee/foul_list?position=head")
+	assert.Contains(t, recorder.Body.String(), "2056")
+	web.arena.EventSettings.TeamsPerAlliance = 2
+	recorder = web.getHttpResponse("/panels/referee/foul_list?position=head")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "1114")
+	assert.NotContains(t, recorder.Body.String(), "2056")
+}
+
 func TestRefereePanelWebsocket(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -43,13 +59,13 @@ func TestRefereePanelWebsocket(t *testing.T) {
 		IsTechnical bool
 	}{"red", true}
 	ws.Write("addFoul", addFoulData)
+	readWebsocketType(t, ws, "realtimeScore")
 	addFoulData.IsTechnical = false
 	ws.Write("addFoul", addFoulData)
+	readWebsocketType(t, ws, "realtimeScore")
 	addFoulData.Alliance = "blue"
 	ws.Write("addFoul", addFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
-	readWebsocketType(t, ws, "realtimeScore")
-	readWebsocketType(t, ws, "realtimeScore")
 	if assert.Equal(t, 2, len(web.arena.RedRealtimeScore.CurrentScore.Fouls)) {
 		assert.Equal(t, true, web.arena.RedRealtimeScore.CurrentScore.Fouls[0].IsTechnical)
 		assert.Equal(t, 0, web.arena.RedRealtimeScore.CurrentScore.Fouls[0].TeamId)
@@ -69,16 +85,18 @@ func TestRefereePanelWebsocket(t *testing.T) {
 	// Test foul mutation.
 	modifyFoulData := struct {
 		Alliance string
-		Index    int
+		FoulId   int
+		Revision int
 		TeamId   int
 		RuleId   int
 	}{}
 	modifyFoulData.Alliance = "red"
-	modifyFoulData.Index = 1
+	modifyFoulData.FoulId = 2
 	ws.Write("toggleFoulType", modifyFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, true, web.arena.RedRealtimeScore.CurrentScore.Fouls[1].IsTechnical)
-	modifyFoulData.Index = 0
+	assert.Equal(t, 1, web.arena.RedRealtimeScore.CurrentScore.Fouls[1].Revision)
+	modifyFoulData.FoulId = 1
 	modifyFoulData.TeamId = 256
 	ws.Write("updateFoulTeam", modifyFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
@@ -88,22 +106,29 @@ func TestRefereePanelWebsocket(t *testing.T) {
 	ws.Write("updateFoulRule", modifyFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, 3, web.arena.BlueRealtimeScore.CurrentScore.Fouls[0].RuleId)
+	assert.True(t, web.arena.BlueRealtimeScore.CurrentScore.Fouls[0].IsTechnical) // Taken from the rule.
 
 	// Test foul deletion.
 	modifyFoulData.Alliance = "blue"
-	modifyFoulData.Index = 0
+	modifyFoulData.FoulId = 1
+	modifyFoulData.Revision = 1
 	ws.Write("deleteFoul", modifyFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, 0, len(web.arena.BlueRealtimeScore.CurrentScore.Fouls))
 	modifyFoulData.Alliance = "red"
-	modifyFoulData.Index = -1 // Invalid index.
+	modifyFoulData.FoulId = 3 // Nonexistent foul.
 	ws.Write("deleteFoul", modifyFoulData)
+	readWebsocketType(t, ws, "error")
+	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, 2, len(web.arena.RedRealtimeScore.CurrentScore.Fouls))
-	modifyFoulData.Alliance = "red"
-	modifyFoulData.Index = 2 // Invalid index.
+	modifyFoulData.FoulId = 2
+	modifyFoulData.Revision = 0 // Stale revision.
 	ws.Write("deleteFoul", modifyFoulData)
+	readWebsocketType(t, ws, "error")
+	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, 2, len(web.arena.RedRealtimeScore.CurrentScore.Fouls))
-	modifyFoulData.Index = 1
+	assert.Equal(t, 2, len(web.arena.RedRealtimeScore.FoulConflicts))
+	modifyFoulData.Revision = 1
 	ws.Write("deleteFoul", modifyFoulData)
 	readWebsocketType(t, ws, "realtimeScore")
 	assert.Equal(t, 1, len(web.arena.RedRealtimeScore.CurrentScore.Fouls))
@@ -165,6 +190,8 @@ func TestRefereePanelWebsocket(t *testing.T) {
 	assert.False(t, web.arena.RedRealtimeScore.FoulsCommitted)
 	assert.False(t, web.arena.BlueRealtimeScore.FoulsCommitted)
 	web.arena.AllianceStationDisplayMode = "logo"
+	ws.Write("confirmCards", nil)
+	readWebsocketType(t, ws, "scoringStatus")
 	ws.Write("commitMatch", nil)
 	readWebsocketType(t, ws, "scoringStatus")
 	assert.Equal(t, "fieldReset", web.arena.AllianceStationDisplayMode)
@@ -175,3 +202,165 @@ func TestRefereePanelWebsocket(t *testing.T) {
 	web.arena.MatchLoadNotifier.Notify()
 	readWebsocketType(t, ws, "matchLoad")
 }
+
+func TestRefereePanelCardAssignment(t *testing.T) {
+	web := setupTestWeb(t)
+	match := model.Match{Type: model.Qualification, ShortName: "Q1", Red1: 254, Red2: 1114, Red3: 2056, Blue1: 148}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/referee/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 4)
+
+	assignCardData := struct {
+		Alliance string
+		TeamId   int
+		Card     string
+		RuleId   int
+	}{"red", 1114, "yellow", 22}
+	ws.Write("assignCard", assignCardData)
+	assert.Contains(t, readWebsocketError(t, ws), "cannot assign cards until the match has ended")
+	ws.Write("confirmCards", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "cannot confirm cards until the match has ended")
+
+	web.arena.MatchState = field.PostMatch
+	ws.Write("assignCard", assignCardData)
+	readWebsocketMultiple(t, ws, 2) // scoringStatus, realtimeScore
+	assert.Equal(t, "yellow", web.arena.RedRealtimeScore.Cards["1114"])
+	assert.Equal(t, 22, web.arena.RedRealtimeScore.CardRules["1114"])
+	assignCardData.RuleId = 0
+	ws.Write("assignCard", assignCardData)
+	assert.Contains(t, readWebsocketError(t, ws), "a valid rule must be given for the yellow card")
+
+	// The match can't be committed until the head referee has confirmed the cards.
+	ws.Write("commitMatch", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "Cards must be confirmed before committing the match.")
+	assert.False(t, web.arena.RedRealtimeScore.FoulsCommitted)
+	ws.Write("confirmCards", nil)
+	readWebsocketType(t, ws, "scoringStatus")
+	assert.True(t, web.arena.CardsConfirmed)
+	ws.Write("commitMatch", nil)
+	readWebsocketType(t, ws, "scoringStatus")
+	assert.True(t, web.arena.RedRealtimeScore.FoulsCommitted)
+
+	// Check that the other referees can't assign or confirm cards.
+	nearConn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/referee/websocket?position=near", nil)
+	assert.Nil(t, err)
+	defer nearConn.Close()
+	nearWs := websocket.NewTestWebsocket(nearConn)
+	readWebsocketMultiple(t, nearWs, 4)
+	assignCardData.Card = "red"
+	assignCardData.RuleId = 22
+	nearWs.Write("assignCard", assignCardData)
+	assert.Equal(t, "Only the head referee may assign cards.", readWebsocketError(t, nearWs))
+	assert.Equal(t, "yellow", web.arena.RedRealtimeScore.Cards["1114"])
+	web.arena.CardsConfirmed = false
+	nearWs.Write("confirmCards", nil)
+	assert.Equal(t, "Only the head referee may confirm cards.", readWebsocketError(t, nearWs))
+	assert.False(t, web.arena.CardsConfirmed)
+}
+
+func TestRefereePanelNearAndFar(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	_, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/referee/websocket?position=blorpy", nil)
+	assert.NotNil(t, err)
+	nearConn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/panels/referee/websocket?position=near&rules=G40", nil,
+	)
+	assert.Nil(t, err)
+	defer nearConn.Close()
+	nearWs := websocket.NewTestWebsocket(nearConn)
+	farConn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/panels/referee/websocket?position=far&rules=G41,G42", nil,
+	)
+	assert.Nil(t, err)
+	defer farConn.Close()
+	farWs := websocket.NewTestWebsocket(farConn)
+	for _, ws := range []*websocket.Websocket{nearWs, farWs} {
+		readWebsocketType(t, ws, "matchLoad")
+		readWebsocketType(t, ws, "matchTime")
+		readWebsocketType(t, ws, "realtimeScore")
+		readWebsocketType(t, ws, "scoringStatus")
+	}
+
+	// Fouls added from both panels should be merged into the same list.
+	addFoulData := struct {
+		Alliance    string
+		IsTechnical bool
+	}{"red", false}
+	nearWs.Write("addFoul", addFoulData)
+	readWebsocketType(t, nearWs, "realtimeScore")
+	readWebsocketType(t, farWs, "realtimeScore")
+	farWs.Write("addFoul", addFoulData)
+	readWebsocketType(t, nearWs, "realtimeScore")
+	readWebsocketType(t, farWs, "realtimeScore")
+	fouls := web.arena.RedRealtimeScore.CurrentScore.Fouls
+	if assert.Equal(t, 2, len(fouls)) {
+		assert.Equal(t, 1, fouls[0].FoulId)
+		assert.Equal(t, 2, fouls[1].FoulId)
+	}
+
+	// Each panel should only be able to assign the rules it is responsible for.
+	modifyFoulData := struct {
+		Alliance string
+		FoulId   int
+		Revision int
+		RuleId   int
+	}{"red", 1, 0, 12} // G409
+	nearWs.Write("updateFoulRule", modifyFoulData)
+	readWebsocketType(t, nearWs, "realtimeScore")
+	readWebsocketType(t, farWs, "realtimeScore")
+	assert.Equal(t, 12, web.arena.RedRealtimeScore.CurrentScore.Fouls[0].RuleId)
+	modifyFoulData.FoulId = 2
+	modifyFoulData.RuleId = 4 // G401
+	farWs.Write("updateFoulRule", modifyFoulData)
+	readWebsocketType(t, farWs, "error")
+	readWebsocketType(t, farWs, "realtimeScore")
+	readWebsocketType(t, nearWs, "realtimeScore")
+	assert.Equal(t, 0, web.arena.RedRealtimeScore.CurrentScore.Fouls[1].RuleId)
+	farWs.Write("deleteFoul", struct {
+		Alliance string
+		FoulId   int
+		Revision int
+	}{"red", 1, 1})
+	readWebsocketType(t, farWs, "error")
+	readWebsocketType(t, farWs, "realtimeScore")
+	readWebsocketType(t, nearWs, "realtimeScore")
+	assert.Equal(t, 2, len(web.arena.RedRealtimeScore.CurrentScore.Fouls))
+
+	// An edit based on a stale view of the foul should be flagged rather than overwriting the other panel's change.
+	modifyFoulData.RuleId = 0
+	nearWs.Write("toggleFoulType", modifyFoulData)
+	readWebsocketType(t, nearWs, "realtimeScore")
+	readWebsocketType(t, farWs, "realtimeScore")
+	farWs.Write("toggleFoulType", modifyFoulData)
+	readWebsocketType(t, farWs, "error")
+	readWebsocketType(t, farWs, "realtimeScore")
+	readWebsocketType(t, nearWs, "realtimeScore")
+	assert.True(t, web.arena.RedRealtimeScore.CurrentScore.Fouls[1].IsTechnical)
+	if assert.Equal(t, 1, len(web.arena.RedRealtimeScore.FoulConflicts)) {
+		assert.Equal(t, "far", web.arena.RedRealtimeScore.FoulConflicts[0].Position)
+		assert.Equal(t, 2, web.arena.RedRealtimeScore.FoulConflicts[0].FoulId)
+	}
+
+	// Only the head referee should be able to commit the match.
+	web.arena.MatchState = field.PostMatch
+	nearWs.Write("commitMatch", nil)
+	assert.Equal(t, "Only the head referee may commit the match.", readWebsocketError(t, nearWs))
+	assert.False(t, web.arena.RedRealtimeScore.FoulsCommitted)
+	assert.False(t, web.arena.BlueRealtimeScore.FoulsCommitted)
+
+	// The foul list should only offer the panel's rules.
+	recorder := web.getHttpResponse("/panels/referee/foul_list?position=near&rules=G40")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "G409")
+	assert.NotContains(t, recorder.Body.String(), "G418")
+}
@@ -7,14 +7,19 @@ package web
 
 import (
 	"bytes"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/playoff"
 	"github.com/Team254/cheesy-arena/tournament"
@@ -23,12 +28,32 @@ import (
 
 // Generates a CSV-formatted report of the qualification rankings.
 func (web *Web) rankingsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
-	rankings, err := web.arena.Database.GetAllRankings()
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rankings, err := event.Database.GetAllRankings()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 
+	// Include whichever ranking columns are defined by the current game.
+	rankingPointConfig := game.CurrentRankingPointConfig()
+	type rankingRow struct {
+		game.Ranking
+		ColumnValues []int
+	}
+	rows := make([]rankingRow, len(rankings))
+	for i, ranking := range rankings {
+		rows[i] = rankingRow{ranking, rankingPointConfig.ColumnValues(&ranking.RankingFields)}
+	}
+	data := struct {
+		Columns []game.RankingColumn
+		Rows    []rankingRow
+	}{rankingPointConfig.Columns, rows}
+
 	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
 	w.Header().Set("Content-Type", "text/plain")
 	template, err := web.parseFiles("templates/rankings.csv")
@@ -36,7 +61,358 @@ func (web *Web) rankingsCsvReportHandler(w http.ResponseWriter, r *http.Request)
 		handleWebErr(w, err)
 		return
 	}
-	err = template.ExecuteTemplate(w, "rankings.csv", rankings)
+	err = template.ExecuteTemplate(w, "rankings.csv", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of the results of the given type of match, including a summary of any edits made
+// to each score after it was committed.
+func (web *Web) resultsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	matchType, err := model.MatchTypeFromString(r.PathValue("type"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Write out each row as soon as it is generated, since there can be thousands of matches over a season-long league.
+	location := web.arena.EventSettings.Location()
+	writer := newCsvStreamWriter(w)
+	_ = writer.Write(
+		[]string{
+			"Match", "Type", "Red1", "Red2", "Red3", "Blue1", "Blue2", "Blue3", "RedScore", "BlueScore", "PlayNumber",
+			"NumScoreEdits", "LastEditedBy", "LastEditedAt", "StartedAt", "StreamStartSec", "EndedAt", "StreamEndSec",
+		},
+	)
+	for _, match := range matches {
+		if !match.IsComplete() {
+			continue
+		}
+		matchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			logging.Web.Error("Failed to generate results report.", "match", match.ShortName, "error", err)
+			return
+		}
+		if matchResult == nil {
+			continue
+		}
+		scoreEdits, err := web.arena.Database.GetScoreEditsForMatch(match.Id)
+		if err != nil {
+			logging.Web.Error("Failed to generate results report.", "match", match.ShortName, "error", err)
+			return
+		}
+		startMarker, endMarker, err := web.arena.Database.GetVideoMarkersForMatch(match.Id)
+		if err != nil {
+			logging.Web.Error("Failed to generate results report.", "match", match.ShortName, "error", err)
+			return
+		}
+		row := []string{
+			match.ShortName,
+			match.Type.String(),
+			strconv.Itoa(match.Red1),
+			strconv.Itoa(match.Red2),
+			strconv.Itoa(match.Red3),
+			strconv.Itoa(match.Blue1),
+			strconv.Itoa(match.Blue2),
+			strconv.Itoa(match.Blue3),
+			strconv.Itoa(matchResult.RedScoreSummary().Score),
+			strconv.Itoa(matchResult.BlueScoreSummary().Score),
+			strconv.Itoa(matchResult.PlayNumber),
+			strconv.Itoa(len(scoreEdits)),
+		}
+		if len(scoreEdits) > 0 {
+			lastScoreEdit := scoreEdits[len(scoreEdits)-1]
+			row = append(row, lastScoreEdit.EditedBy, lastScoreEdit.EditedAt.In(location).String())
+		} else {
+			row = append(row, "", "")
+		}
+		row = append(row, formatVideoMarker(startMarker, location)...)
+		row = append(row, formatVideoMarker(endMarker, location)...)
+		if err = writer.Write(row); err != nil {
+			logging.Web.Error("Failed to write results report.", "error", err)
+			return
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		logging.Web.Error("Failed to write results report.", "error", err)
+	}
+}
+
+// Returns the CSV columns giving the time of the given video marker in the given time zone and its offset into the
+// stream, if it is synced.
+func formatVideoMarker(videoMarker *model.VideoMarker, location *time.Location) []string {
+	if videoMarker == nil {
+		return []string{"", ""}
+	}
+	var streamOffset string
+	if videoMarker.StreamSynced {
+		streamOffset = fmt.Sprintf("%.1f", videoMarker.StreamOffsetSec)
+	}
+	return []string{videoMarker.Time.In(location).String(), streamOffset}
+}
+
+// Generates a CSV-formatted report of the events recorded during every play of the given type of match, for
+// reviewing the season's matches in bulk.
+func (web *Web) timelinesCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	matchType, err := model.MatchTypeFromString(r.PathValue("type"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	matches, err := web.arena.Database.GetMatchesByType(matchType, true)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	matchTimelineIds, err := web.arena.Database.GetMatchTimelineIdsByMatch()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Load and write out one timeline at a time, since each can hold hundreds of events.
+	location := web.arena.EventSettings.Location()
+	writer := newCsvStreamWriter(w)
+	_ = writer.Write([]string{"Match", "PlayNumber", "Time", "MatchTimeSec", "Type", "Source", "Description"})
+	for _, match := range matches {
+		for _, matchTimelineId := range matchTimelineIds[match.Id] {
+			matchTimeline, err := web.arena.Database.GetMatchTimelineById(matchTimelineId)
+			if err != nil {
+				logging.Web.Error("Failed to generate timelines report.", "match", match.ShortName, "error", err)
+				return
+			}
+			if matchTimeline == nil {
+				// The timeline was deleted after the report was started.
+				continue
+			}
+			for _, event := range matchTimeline.Events {
+				row := []string{
+					match.ShortName,
+					strconv.Itoa(matchTimeline.PlayNumber),
+					event.Time.In(location).Format("2006-01-02 15:04:05.000"),
+					fmt.Sprintf("%.3f", float64(event.MatchTimeMs)/1000),
+					string(event.Type),
+					event.Source,
+					event.Description,
+				}
+				if err = writer.Write(row); err != nil {
+					logging.Web.Error("Failed to write timelines report.", "error", err)
+					return
+				}
+			}
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		logging.Web.Error("Failed to write timelines report.", "error", err)
+	}
+}
+
+// Generates a CSV-formatted report of the practice match results scored in sandbox mode.
+func (web *Web) sandboxResultsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	sandboxMatchResults, err := web.arena.Database.GetAllSandboxMatchResults()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	type sandboxResultRow struct {
+		model.SandboxMatchResult
+		RedSummary  *game.ScoreSummary
+		BlueSummary *game.ScoreSummary
+	}
+	var rows []sandboxResultRow
+	for _, sandboxMatchResult := range sandboxMatchResults {
+		matchResult := sandboxMatchResult.MatchResult()
+		rows = append(
+			rows,
+			sandboxResultRow{sandboxMatchResult, matchResult.RedScoreSummary(), matchResult.BlueScoreSummary()},
+		)
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	template, err := web.parseFiles("templates/sandbox_results.csv")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	err = template.ExecuteTemplate(w, "sandbox_results.csv", rows)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of every change made by post-match score edits, for use in handling protests.
+func (web *Web) scoreEditsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	scoreEdits, err := web.arena.Database.GetAllScoreEdits()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	type scoreEditRow struct {
+		MatchName string
+		model.ScoreEdit
+	}
+	var rows []scoreEditRow
+	for _, scoreEdit := range scoreEdits {
+		match, err := web.arena.Database.GetMatchById(scoreEdit.MatchId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		row := scoreEditRow{ScoreEdit: scoreEdit}
+		if match != nil {
+			row.MatchName = match.ShortName
+		}
+		rows = append(rows, row)
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	template, err := web.parseFiles("templates/score_edits.csv")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	err = template.ExecuteTemplate(w, "score_edits.csv", rows)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of all scouting observations, joined with the match and each team's current
+// ranking so that it can be analyzed alongside the official statistics.
+func (web *Web) scoutingCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	observations, err := web.arena.Database.GetAllScoutingObservations()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	scoutingApps, err := web.arena.Database.GetAllScoutingApps()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	appNames := make(map[int]string)
+	for _, scoutingApp := range scoutingApps {
+		appNames[scoutingApp.Id] = scoutingApp.Name
+	}
+	rankings, err := web.arena.Database.GetAllRankings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rankingsByTeam := make(map[int]game.Ranking)
+	for _, ranking := range rankings {
+		rankingsByTeam[ranking.TeamId] = ranking
+	}
+
+	// Each metric reported by any of the apps gets its own column.
+	metricSet := make(map[string]struct{})
+	for _, observation := range observations {
+		for metric := range observation.Values {
+			metricSet[metric] = struct{}{}
+		}
+	}
+	var metrics []string
+	for metric := range metricSet {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	writer := csv.NewWriter(w)
+	header := []string{"Match", "Team", "Rank", "RankingPoints", "App", "Scout", "SubmittedAt"}
+	_ = writer.Write(append(append(header, metrics...), "Notes"))
+	for _, observation := range observations {
+		match, err := web.arena.Database.GetMatchById(observation.MatchId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		var matchName string
+		if match != nil {
+			matchName = match.ShortName
+		}
+		var rank, rankingPoints string
+		if ranking, ok := rankingsByTeam[observation.TeamId]; ok {
+			rank = strconv.Itoa(ranking.Rank)
+			rankingPoints = strconv.Itoa(ranking.RankingPoints)
+		}
+		row := []string{
+			matchName,
+			strconv.Itoa(observation.TeamId),
+			rank,
+			rankingPoints,
+			appNames[observation.ScoutingAppId],
+			observation.ScoutName,
+			observation.SubmittedAt.In(web.arena.EventSettings.Location()).Format(time.RFC3339),
+		}
+		for _, metric := range metrics {
+			if value, ok := observation.Values[metric]; ok {
+				row = append(row, strconv.Itoa(value))
+			} else {
+				row = append(row, "")
+			}
+		}
+		_ = writer.Write(append(row, observation.Notes))
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of all the cards given to teams over the course of the event.
+func (web *Web) teamCardsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	teamCards, err := tournament.GetTeamCards(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	template, err := web.parseFiles("templates/team_cards.csv")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	err = template.ExecuteTemplate(w, "team_cards.csv", teamCards)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of all the alliance stations bypassed over the course of the event and why.
+func (web *Web) teamBypassesCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	teamBypasses, err := tournament.GetTeamBypasses(web.arena.Database)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	template, err := web.parseFiles("templates/team_bypasses.csv")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	err = template.ExecuteTemplate(w, "team_bypasses.csv", teamBypasses)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -45,15 +421,25 @@ func (web *Web) rankingsCsvReportHandler(w http.ResponseWriter, r *http.Request)
 
 // Generates a PDF-formatted report of the qualification rankings.
 func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
-	rankings, err := web.arena.Database.GetAllRankings()
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rankings, err := event.Database.GetAllRankings()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 
-	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
-	colWidths := map[string]float64{"Rank": 13, "Team": 20, "RP": 20, "Coop": 20, "Match": 20, "Auto": 20, "Stage": 20,
-		"W-L-T": 22, "DQ": 20, "Played": 20}
+	// The widths of the table columns in mm, stored here so that they can be referenced for each row. The columns
+	// defined by the current game share whatever width is left over from the fixed columns.
+	rankingPointConfig := game.CurrentRankingPointConfig()
+	colWidths := map[string]float64{"Rank": 13, "Team": 20, "W-L-T": 22, "DQ": 20, "Played": 20}
+	gameColWidth := 100.0
+	if len(rankingPointConfig.Columns) > 0 {
+		gameColWidth /= float64(len(rankingPointConfig.Columns))
+	}
 	rowHeight := 6.5
 
 	pdf := gofpdf.New("P", "mm", "Letter", "font")
@@ -62,14 +448,12 @@ func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 	// Render table header row.
 	pdf.SetFont("Arial", "B", 10)
 	pdf.SetFillColor(220, 220, 220)
-	pdf.CellFormat(195, rowHeight, "Team Standings - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
+	pdf.CellFormat(195, rowHeight, "Team Standings - "+event.EventSettings.Name, "", 1, "C", false, 0, "")
 	pdf.CellFormat(colWidths["Rank"], rowHeight, "Rank", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Team"], rowHeight, "Team", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["RP"], rowHeight, "RP", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Coop"], rowHeight, "Coop", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Match"], rowHeight, "Match", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Auto"], rowHeight, "Auto", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Stage"], rowHeight, "Stage", "1", 0, "C", true, 0, "")
+	for _, column := range rankingPointConfig.Columns {
+		pdf.CellFormat(gameColWidth, rowHeight, column.Header, "1", 0, "C", true, 0, "")
+	}
 	pdf.CellFormat(colWidths["W-L-T"], rowHeight, "W-L-T", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["DQ"], rowHeight, "DQ", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Played"], rowHeight, "Played", "1", 1, "C", true, 0, "")
@@ -79,18 +463,202 @@ func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 		pdf.CellFormat(colWidths["Rank"], rowHeight, strconv.Itoa(ranking.Rank), "1", 0, "C", false, 0, "")
 		pdf.SetFont("Arial", "", 10)
 		pdf.CellFormat(colWidths["Team"], rowHeight, strconv.Itoa(ranking.TeamId), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["RP"], rowHeight, strconv.Itoa(ranking.RankingPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Coop"], rowHeight, strconv.Itoa(ranking.CoopertitionPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Match"], rowHeight, strconv.Itoa(ranking.MatchPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Auto"], rowHeight, strconv.Itoa(ranking.AutoPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Stage"], rowHeight, strconv.Itoa(ranking.StagePoints), "1", 0, "C", false, 0, "")
+		for _, value := range rankingPointConfig.ColumnValues(&ranking.RankingFields) {
+			pdf.CellFormat(gameColWidth, rowHeight, strconv.Itoa(value), "1", 0, "C", false, 0, "")
+		}
 		record := fmt.Sprintf("%d-%d-%d", ranking.Wins, ranking.Losses, ranking.Ties)
 		pdf.CellFormat(colWidths["W-L-T"], rowHeight, record, "1", 0, "C", false, 0, "")
 		pdf.CellFormat(colWidths["DQ"], rowHeight, strconv.Itoa(ranking.Disqualifications), "1", 0, "C", false, 0, "")
 		pdf.CellFormat(colWidths["Played"], rowHeight, strconv.Itoa(ranking.Played), "1", 1, "C", false, 0, "")
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
+
+	// Write out the PDF file as the HTTP response.
+	w.Header().Set("Content-Type", "application/pdf")
+	err = pdf.Output(w)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a CSV-formatted report of the cumulative league standings, with the points earned at each event.
+func (web *Web) leagueCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	leagueStandings, err := web.getLeagueStandings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
+	w.Header().Set("Content-Type", "text/plain")
+	writer := csv.NewWriter(w)
+	header := []string{"Rank", "TeamId", "Nickname"}
+	header = append(header, leagueStandings.EventNames...)
+	_ = writer.Write(append(header, "Wins", "Losses", "Ties", "Played", "Points"))
+	for _, standing := range leagueStandings.Standings {
+		row := []string{strconv.Itoa(standing.Rank), strconv.Itoa(standing.TeamId), standing.Nickname}
+		for _, eventName := range leagueStandings.EventNames {
+			row = append(row, formatLeagueEventPoints(&standing, eventName))
+		}
+		row = append(
+			row,
+			strconv.Itoa(standing.Wins),
+			strconv.Itoa(standing.Losses),
+			strconv.Itoa(standing.Ties),
+			strconv.Itoa(standing.Played),
+			strconv.Itoa(standing.Points),
+		)
+		_ = writer.Write(row)
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a PDF-formatted report of the cumulative league standings, with the points earned at each event.
+func (web *Web) leaguePdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	leagueStandings, err := web.getLeagueStandings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// The widths of the table columns in mm, stored here so that they can be referenced for each row. The events share
+	// whatever width is left over from the fixed columns.
+	colWidths := map[string]float64{"Rank": 13, "Team": 18, "Name": 60, "W-L-T": 22, "Points": 20}
+	eventColWidth := 126.0
+	if len(leagueStandings.EventNames) > 0 {
+		eventColWidth /= float64(len(leagueStandings.EventNames))
+	}
+	rowHeight := 6.5
+	lineHeight := 4.0
+
+	pdf := gofpdf.New("L", "mm", "Letter", "font")
+	pdf.AddPage()
+
+	// Render table header row, wrapping the event names since their columns may be narrow.
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(220, 220, 220)
+	pdf.CellFormat(259, rowHeight, "League Standings - "+leagueStandings.LeagueName, "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "B", 8)
+	numHeaderLines := 1
+	for _, eventName := range leagueStandings.EventNames {
+		numHeaderLines = max(numHeaderLines, len(pdf.SplitLines([]byte(eventName), eventColWidth)))
+	}
+	headerHeight := max(rowHeight, lineHeight*float64(numHeaderLines))
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(colWidths["Rank"], headerHeight, "Rank", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Team"], headerHeight, "Team", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Name"], headerHeight, "Name", "1", 0, "C", true, 0, "")
+	pdf.SetFont("Arial", "B", 8)
+	for _, eventName := range leagueStandings.EventNames {
+		numLines := len(pdf.SplitLines([]byte(eventName), eventColWidth))
+		x, y := pdf.GetXY()
+		pdf.Rect(x, y, eventColWidth, headerHeight, "F")
+		drawMultiLineCell(pdf, eventColWidth, headerHeight, lineHeight, eventName, "C", numLines)
+	}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(colWidths["W-L-T"], headerHeight, "W-L-T", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Points"], headerHeight, "Points", "1", 1, "C", true, 0, "")
+	for _, standing := range leagueStandings.Standings {
+		// Render standing info row.
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(colWidths["Rank"], rowHeight, strconv.Itoa(standing.Rank), "1", 0, "C", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(colWidths["Team"], rowHeight, strconv.Itoa(standing.TeamId), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths["Name"], rowHeight, standing.Nickname, "1", 0, "L", false, 0, "")
+		for _, eventName := range leagueStandings.EventNames {
+			eventPoints := formatLeagueEventPoints(&standing, eventName)
+			pdf.CellFormat(eventColWidth, rowHeight, eventPoints, "1", 0, "C", false, 0, "")
+		}
+		record := fmt.Sprintf("%d-%d-%d", standing.Wins, standing.Losses, standing.Ties)
+		pdf.CellFormat(colWidths["W-L-T"], rowHeight, record, "1", 0, "C", false, 0, "")
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(colWidths["Points"], rowHeight, strconv.Itoa(standing.Points), "1", 1, "C", false, 0, "")
+	}
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(259, 10, "Points in parentheses don't count towards the team's total.", "", 1, "L", false, 0, "")
+
+	web.addTimeGeneratedFooter(pdf)
+
+	// Write out the PDF file as the HTTP response.
+	w.Header().Set("Content-Type", "application/pdf")
+	err = pdf.Output(w)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the league points the team earned at the given event for display, in parentheses if they aren't among the
+// ones that count towards its total, or blank if it didn't attend the event.
+func formatLeagueEventPoints(standing *league.Standing, eventName string) string {
+	eventPoints, ok := standing.EventPoints(eventName)
+	if !ok {
+		return ""
+	}
+	if !eventPoints.Counted {
+		return fmt.Sprintf("(%d)", eventPoints.Points)
+	}
+	return strconv.Itoa(eventPoints.Points)
+}
+
+// Generates a PDF-formatted report of the calculated OPR, DPR, CCWM and component OPRs of each team.
+func (web *Web) teamStatsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	teamStats, err := web.arena.Database.GetAllTeamStats()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	headers := []string{"Team", "Played", "OPR", "DPR", "CCWM", "Auto", "Amp", "Speaker", "Stage", "Foul"}
+	colWidth := 19.5
+	rowHeight := 6.5
+
+	pdf := gofpdf.New("P", "mm", "Letter", "font")
+	pdf.AddPage()
+
+	// Render table header row.
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(220, 220, 220)
+	pdf.CellFormat(195, rowHeight, "Team Statistics - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
+	for i, header := range headers {
+		lineBreak := 0
+		if i == len(headers)-1 {
+			lineBreak = 1
+		}
+		pdf.CellFormat(colWidth, rowHeight, header, "1", lineBreak, "C", true, 0, "")
+	}
+	for _, teamStat := range teamStats {
+		// Render team stat row.
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(colWidth, rowHeight, strconv.Itoa(teamStat.TeamId), "1", 0, "C", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(colWidth, rowHeight, strconv.Itoa(teamStat.MatchesPlayed), "1", 0, "C", false, 0, "")
+		values := []float64{
+			teamStat.Opr,
+			teamStat.Dpr,
+			teamStat.Ccwm,
+			teamStat.AutoOpr,
+			teamStat.AmpOpr,
+			teamStat.SpeakerOpr,
+			teamStat.StageOpr,
+			teamStat.FoulOpr,
+		}
+		for i, value := range values {
+			lineBreak := 0
+			if i == len(values)-1 {
+				lineBreak = 1
+			}
+			pdf.CellFormat(colWidth, rowHeight, fmt.Sprintf("%.2f", value), "1", lineBreak, "C", false, 0, "")
+		}
+	}
+
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -236,7 +804,7 @@ func (web *Web) backupsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 		pdf.CellFormat(colWidths["RP"], rowHeight, strconv.Itoa(ranking.RankingPoints), "1", 1, "C", false, 0, "")
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -360,13 +928,18 @@ func drawPdfLogo(pdf gofpdf.Pdf, x float64, y float64, width float64) {
 
 // Generates a CSV-formatted report of the match schedule.
 func (web *Web) scheduleCsvReportHandler(w http.ResponseWriter, r *http.Request) {
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	matchType, err := model.MatchTypeFromString(r.PathValue("type"))
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 
-	matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+	matches, err := event.Database.GetMatchesByType(matchType, false)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -388,31 +961,36 @@ func (web *Web) scheduleCsvReportHandler(w http.ResponseWriter, r *http.Request)
 
 // Generates a PDF-formatted report of the match schedule.
 func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	matchType, err := model.MatchTypeFromString(r.PathValue("type"))
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 
-	matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+	matches, err := event.Database.GetMatchesByType(matchType, false)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
-	scheduledBreaks, err := web.arena.Database.GetScheduledBreaksByMatchType(matchType)
+	scheduledBreaks, err := event.Database.GetScheduledBreaksByMatchType(matchType)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 	breakIndex := 0
-	teams, err := web.arena.Database.GetAllTeams()
+	teams, err := event.Database.GetAllTeams()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 	matchesPerTeam := 0
 	if len(teams) > 0 {
-		matchesPerTeam = len(matches) * tournament.TeamsPerMatch / len(teams)
+		matchesPerTeam = len(matches) * 2 * event.EventSettings.TeamsPerAlliance / len(teams)
 	}
 
 	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
@@ -425,7 +1003,7 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 	// Render table header row.
 	pdf.SetFont("Arial", "B", 10)
 	pdf.SetFillColor(220, 220, 220)
-	pdf.CellFormat(195, rowHeight, "Match Schedule - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
+	pdf.CellFormat(195, rowHeight, "Match Schedule - "+event.EventSettings.Name, "", 1, "C", false, 0, "")
 	pdf.CellFormat(colWidths["Time"], rowHeight, "Time", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Match"], rowHeight, "Match", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Team"], rowHeight, "Red 1", "1", 0, "C", true, 0, "")
@@ -435,11 +1013,12 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 	pdf.CellFormat(colWidths["Team"], rowHeight, "Blue 2", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Team"], rowHeight, "Blue 3", "1", 1, "C", true, 0, "")
 	pdf.SetFont("Arial", "", 10)
+	location := event.EventSettings.Location()
 	for _, match := range matches {
 		// Render break if there is one before this match.
 		if breakIndex < len(scheduledBreaks) && scheduledBreaks[breakIndex].TypeOrderBefore == match.TypeOrder {
 			scheduledBreak := scheduledBreaks[breakIndex]
-			formattedTime := scheduledBreak.Time.Local().Format("Mon 1/02 03:04 PM")
+			formattedTime := scheduledBreak.Time.In(location).Format("Mon 1/02 03:04 PM")
 			description := fmt.Sprintf("%s (%d minutes)", scheduledBreak.Description, scheduledBreak.DurationSec/60)
 			pdf.CellFormat(colWidths["Time"], rowHeight, formattedTime, "1", 0, "C", false, 0, "")
 			pdf.CellFormat(colWidths["Match"]+6*colWidths["Team"], rowHeight, description, "1", 1, "C", false, 0, "")
@@ -468,8 +1047,10 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 		}
 
 		// Render match info row.
-		pdf.CellFormat(colWidths["Time"], height, match.Time.Local().Format("Mon 1/02 03:04 PM"), borderStr, 0,
-			alignStr, false, 0, "")
+		pdf.CellFormat(
+			colWidths["Time"], height, match.Time.In(location).Format("Mon 1/02 03:04 PM"), borderStr, 0, alignStr,
+			false, 0, "",
+		)
 		pdf.CellFormat(colWidths["Match"], height, match.LongName, borderStr, 0, alignStr, false, 0, "")
 		pdf.CellFormat(colWidths["Team"], height, formatTeam(match.Red1), borderStr, 0, alignStr, false, 0, "")
 		pdf.CellFormat(colWidths["Team"], height, formatTeam(match.Red2), borderStr, 0, alignStr, false, 0, "")
@@ -504,7 +1085,104 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 		pdf.CellFormat(195, 10, fmt.Sprintf("Matches Per Team: %d", matchesPerTeam), "", 1, "L", false, 0, "")
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
+
+	// Write out the PDF file as the HTTP response.
+	w.Header().Set("Content-Type", "application/pdf")
+	err = pdf.Output(w)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Represents a line of the day agenda report, which may be a run of show item, a scheduled break or a match.
+type agendaReportRow struct {
+	Time         time.Time
+	Description  string
+	Details      string
+	IsAgendaItem bool
+}
+
+// Generates a PDF-formatted report of the full run of show for the event, merged with the schedule of matches and
+// breaks.
+func (web *Web) agendaPdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	agendaItems, err := web.arena.Database.GetAllAgendaItems()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var rows []agendaReportRow
+	for _, agendaItem := range agendaItems {
+		var details string
+		if agendaItem.DurationMin > 0 {
+			details = fmt.Sprintf("%d minutes", agendaItem.DurationMin)
+		}
+		rows = append(rows, agendaReportRow{agendaItem.StartTime, agendaItem.Name, details, true})
+	}
+	for _, matchType := range []model.MatchType{model.Practice, model.Qualification, model.Playoff} {
+		matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, match := range matches {
+			rows = append(rows, agendaReportRow{Time: match.Time, Description: match.LongName,
+				Details: formatAgendaReportAlliances(match)})
+		}
+		scheduledBreaks, err := web.arena.Database.GetScheduledBreaksByMatchType(matchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, scheduledBreak := range scheduledBreaks {
+			rows = append(rows, agendaReportRow{Time: scheduledBreak.Time, Description: scheduledBreak.Description,
+				Details: fmt.Sprintf("%d minute break", scheduledBreak.DurationSec/60)})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Time.Before(rows[j].Time)
+	})
+
+	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
+	colWidths := map[string]float64{"Time": 30, "Description": 75, "Details": 90}
+	rowHeight := 6.5
+
+	pdf := gofpdf.New("P", "mm", "Letter", "font")
+	pdf.AddPage()
+
+	// Render table header row.
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(220, 220, 220)
+	pdf.CellFormat(195, rowHeight, "Day Agenda - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
+	pdf.CellFormat(colWidths["Time"], rowHeight, "Time", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Description"], rowHeight, "Item", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Details"], rowHeight, "Details", "1", 1, "C", true, 0, "")
+	location := web.arena.EventSettings.Location()
+	var lastDay string
+	for _, row := range rows {
+		// Start each day of the event with a line giving its date.
+		day := row.Time.In(location).Format("Monday, January 2")
+		if day != lastDay {
+			pdf.SetFont("Arial", "B", 10)
+			pdf.CellFormat(195, rowHeight, day, "1", 1, "L", true, 0, "")
+			lastDay = day
+		}
+
+		// Make the run of show items stand out from the matches around them.
+		if row.IsAgendaItem {
+			pdf.SetFont("Arial", "B", 10)
+		} else {
+			pdf.SetFont("Arial", "", 10)
+		}
+		pdf.CellFormat(
+			colWidths["Time"], rowHeight, row.Time.In(location).Format("03:04 PM"), "1", 0, "C", false, 0, "",
+		)
+		pdf.CellFormat(colWidths["Description"], rowHeight, row.Description, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths["Details"], rowHeight, row.Details, "1", 1, "L", false, 0, "")
+	}
+
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -515,6 +1193,26 @@ func (web *Web) schedulePdfReportHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// Returns the teams of both alliances in the given match for the day agenda report, or an empty string if they haven't
+// been determined yet.
+func formatAgendaReportAlliances(match model.Match) string {
+	formatAlliance := func(teamIds ...int) string {
+		var teams []string
+		for _, teamId := range teamIds {
+			if teamId > 0 {
+				teams = append(teams, strconv.Itoa(teamId))
+			}
+		}
+		return strings.Join(teams, " ")
+	}
+	red := formatAlliance(match.Red1, match.Red2, match.Red3)
+	blue := formatAlliance(match.Blue1, match.Blue2, match.Blue3)
+	if red == "" && blue == "" {
+		return ""
+	}
+	return fmt.Sprintf("Red %s vs. Blue %s", red, blue)
+}
+
 // Generates a CSV-formatted report of the team list.
 func (web *Web) teamsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 	teams, err := web.arena.Database.GetAllTeams()
@@ -522,6 +1220,11 @@ func (web *Web) teamsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 
 	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
 	w.Header().Set("Content-Type", "text/plain")
@@ -530,7 +1233,11 @@ func (web *Web) teamsCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
-	err = template.ExecuteTemplate(w, "teams.csv", teams)
+	data := struct {
+		Teams        []model.Team
+		TeamStatuses map[int]model.TeamStatus
+	}{teams, teamStatuses}
+	err = template.ExecuteTemplate(w, "teams.csv", data)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -545,6 +1252,12 @@ func (web *Web) teamsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
 	showHasConnected := r.URL.Query().Get("showHasConnected") == "true"
 
 	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
@@ -592,7 +1305,7 @@ func (web *Web) teamsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
 				colWidths["RookieYear"], teamRowHeight, strconv.Itoa(team.RookieYear), "1", 0, "L", false, 0, "",
 			)
 			var hasConnected string
-			if team.HasConnected {
+			if teamStatuses[team.Id].HasConnected {
 				hasConnected = "Yes"
 			}
 			pdf.CellFormat(colWidths["HasConnected"], teamRowHeight, hasConnected, "1", 1, "L", false, 0, "")
@@ -603,7 +1316,7 @@ func (web *Web) teamsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -616,7 +1329,7 @@ func (web *Web) teamsPdfReportHandler(w http.ResponseWriter, r *http.Request) {
 
 // Generates a CSV-formatted report of the WPA keys, for import into the radio kiosk.
 func (web *Web) wpaKeysCsvReportHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.FtaRole) {
 		return
 	}
 
@@ -639,7 +1352,12 @@ func (web *Web) wpaKeysCsvReportHandler(w http.ResponseWriter, r *http.Request)
 
 // Generates a PDF-formatted report of the playoff alliances and the teams contained within.
 func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request) {
-	alliances, err := web.arena.Database.GetAllAlliances()
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	alliances, err := event.Database.GetAllAlliances()
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -647,11 +1365,11 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 
 	// Traverse the playoff tournament to register the furthest level that the alliance has achieved.
 	allianceStatuses := make(map[int]string)
-	if web.arena.PlayoffTournament.IsComplete() {
-		allianceStatuses[web.arena.PlayoffTournament.WinningAllianceId()] = "Winner"
-		allianceStatuses[web.arena.PlayoffTournament.FinalistAllianceId()] = "Finalist"
+	if event.PlayoffTournament.IsComplete() {
+		allianceStatuses[event.PlayoffTournament.WinningAllianceId()] = "Winner"
+		allianceStatuses[event.PlayoffTournament.FinalistAllianceId()] = "Finalist"
 	}
-	err = web.arena.PlayoffTournament.Traverse(func(matchGroup playoff.MatchGroup) error {
+	err = event.PlayoffTournament.Traverse(func(matchGroup playoff.MatchGroup) error {
 		matchup, ok := matchGroup.(*playoff.Matchup)
 		if !ok {
 			return nil
@@ -675,7 +1393,7 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	teams, err := web.arena.Database.GetAllTeams()
+	teams, err := event.Database.GetAllTeams()
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -696,7 +1414,7 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 	pdf.SetFillColor(220, 220, 220)
 
 	// Render table header row.
-	pdf.CellFormat(195, rowHeight, "Playoff Alliances - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
+	pdf.CellFormat(195, rowHeight, "Playoff Alliances - "+event.EventSettings.Name, "", 1, "C", false, 0, "")
 	pdf.CellFormat(colWidths["Alliance"], rowHeight, "Alliance", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Id"], rowHeight, "Team", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Name"], rowHeight, "Name", "1", 0, "C", true, 0, "")
@@ -745,7 +1463,7 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 		pdf.SetX(startX)
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -759,8 +1477,13 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 // Generates a PDF-formatted report of the playoff bracket, relying on the browser to convert SVG to PDF (since no
 // suitable Go library for doing so appears to exist).
 func (web *Web) bracketPdfReportHandler(w http.ResponseWriter, r *http.Request) {
+	event, err := web.getReportEvent(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	buffer := new(bytes.Buffer)
-	err := web.generateBracketSvg(buffer, nil)
+	err = web.generateBracketSvg(buffer, event, nil)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -825,6 +1548,7 @@ func (web *Web) cyclePdfReportHandler(w http.ResponseWriter, r *http.Request) {
 	pdf.CellFormat(colWidths["Diff"], rowHeight, "Ref Time", "1", 1, "C", true, 0, "")
 	pdf.SetFont("Arial", "", 10)
 	var lastMatchStart time.Time
+	location := web.arena.EventSettings.Location()
 	for _, match := range matches {
 		height := rowHeight
 		borderStr := "1"
@@ -838,13 +1562,13 @@ func (web *Web) cyclePdfReportHandler(w http.ResponseWriter, r *http.Request) {
 		cycleTime := ""
 
 		if !match.FieldReadyAt.IsZero() {
-			fieldReady = match.FieldReadyAt.Local().Format("03:04 PM")
+			fieldReady = match.FieldReadyAt.In(location).Format("03:04 PM")
 		}
 		if !match.StartedAt.IsZero() {
-			startedAt = match.StartedAt.Local().Format("03:04 PM")
+			startedAt = match.StartedAt.In(location).Format("03:04 PM")
 		}
 		if !match.ScoreCommittedAt.IsZero() {
-			scoreCommitted = match.ScoreCommittedAt.Local().Format("03:04 PM")
+			scoreCommitted = match.ScoreCommittedAt.In(location).Format("03:04 PM")
 		}
 
 		if !match.StartedAt.IsZero() && !match.ScoreCommittedAt.IsZero() {
@@ -868,8 +1592,10 @@ func (web *Web) cyclePdfReportHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Render match info row.
 		pdf.CellFormat(colWidths["Match"], height, match.ShortName, borderStr, 0, alignStr, false, 0, "")
-		pdf.CellFormat(colWidths["Time"], height, match.Time.Local().Format("1/02 03:04 PM"), borderStr, 0,
-			alignStr, false, 0, "")
+		pdf.CellFormat(
+			colWidths["Time"], height, match.Time.In(location).Format("1/02 03:04 PM"), borderStr, 0, alignStr, false,
+			0, "",
+		)
 		pdf.CellFormat(colWidths["Time2"], height, fieldReady, borderStr, 0, alignStr, false, 0, "")
 		pdf.CellFormat(colWidths["Time2"], height, startedAt, borderStr, 0, alignStr, false, 0, "")
 		pdf.CellFormat(colWidths["Time2"], height, scoreCommitted, borderStr, 0, alignStr, false, 0, "")
@@ -879,7 +1605,7 @@ func (web *Web) cyclePdfReportHandler(w http.ResponseWriter, r *http.Request) {
 		pdf.CellFormat(colWidths["Diff"], height, refTime, borderStr, 1, alignStr, false, 0, "")
 	}
 
-	addTimeGeneratedFooter(pdf)
+	web.addTimeGeneratedFooter(pdf)
 
 	// Write out the PDF file as the HTTP response.
 	w.Header().Set("Content-Type", "application/pdf")
@@ -897,6 +1623,11 @@ func (web *Web) ftaCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 
 	// Don't set the content type as "text/csv", as that will trigger an automatic download in the browser.
 	w.Header().Set("Content-Type", "text/plain")
@@ -905,17 +1636,20 @@ func (web *Web) ftaCsvReportHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
-	err = template.ExecuteTemplate(w, "fta.csv", teams)
+	data := struct {
+		Teams        []model.Team
+		TeamStatuses map[int]model.TeamStatus
+	}{teams, teamStatuses}
+	err = template.ExecuteTemplate(w, "fta.csv", data)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 }
 
-func addTimeGeneratedFooter(pdf *gofpdf.Fpdf) {
-	footerText := fmt.Sprintf(
-		"Report generated at %s on %s", time.Now().Format("3:04:05 PM"), time.Now().Format("Mon Jan 2 2006"),
-	)
+func (web *Web) addTimeGeneratedFooter(pdf *gofpdf.Fpdf) {
+	now := time.Now().In(web.arena.EventSettings.Location())
+	footerText := fmt.Sprintf("Report generated at %s on %s", now.Format("3:04:05 PM"), now.Format("Mon Jan 2 2006"))
 	pdf.SetFont("Arial", "", 10)
 	pdf.CellFormat(0, 10, footerText, "", 1, "L", false, 0, "")
 }
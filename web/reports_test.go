@@ -4,10 +4,13 @@
 package web
 
 import (
+	"fmt"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/league"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -24,7 +27,8 @@ func TestRankingsCsvReport(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
 	expectedBody := "Rank,TeamId,RankingPoints,CoopertitionPoints,MatchPoints,AutoPoints,StagePoints,Wins,Losses," +
-		"Ties,Disqualifications,Played\n1,254,20,625,90,554,12,3,2,1,0,10\n2,1114,18,700,625,90,23,1,3,2,0,10\n\n"
+		"Ties,Disqualifications,NoShows,FoulRankingPoints,Played\n1,254,20,625,90,554,12,3,2,1,0,0,0,10\n" +
+		"2,1114,18,700,625,90,23,1,3,2,0,0,0,10\n\n"
 	assert.Equal(t, expectedBody, recorder.Body.String())
 }
 
@@ -42,6 +46,44 @@ func TestRankingsPdfReport(t *testing.T) {
 	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
 }
 
+func TestLeagueReports(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Week_1", "Week 1")
+	leagueSettings := league.DefaultSettings()
+	leagueSettings.EventNames = []string{"Week_1"}
+	leagueSettings.CountedEvents = 1
+	assert.Nil(t, league.SaveSettings(leagueSettings))
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics, Inc."})
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 1114, Rank: 1, RankingFields: game.RankingFields{Wins: 1, Ties: 1, Played: 2}},
+	)
+
+	recorder := web.getHttpResponse("/reports/csv/league")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	expectedBody := "Rank,TeamId,Nickname,Week_1,Untitled_Event,Wins,Losses,Ties,Played,Points\n" +
+		"1,1114,\"Simbotics, Inc.\",6,(3),4,1,1,6,6\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+
+	// Can't really parse the PDF content and check it, so just check that what's sent back is a PDF.
+	recorder = web.getHttpResponse("/reports/pdf/league")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
+}
+
+func TestTeamStatsPdfReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.ReplaceAllTeamStats([]model.TeamStat{{TeamId: 254, MatchesPlayed: 3, Opr: 40.25}})
+
+	// Can't really parse the PDF content and check it, so just check that what's sent back is a PDF.
+	recorder := web.getHttpResponse("/reports/pdf/team_stats")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
+}
+
 func TestScheduleCsvReport(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -83,6 +125,29 @@ func TestSchedulePdfReport(t *testing.T) {
 	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
 }
 
+func TestAgendaPdfReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateAgendaItem(
+		&model.AgendaItem{Name: "Opening Ceremony", StartTime: time.Unix(0, 0), DurationMin: 15},
+	)
+	web.arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, LongName: "Qualification 1", Time: time.Unix(1200, 0), Red1: 254},
+	)
+	web.arena.Database.CreateScheduledBreak(
+		&model.ScheduledBreak{MatchType: model.Qualification, Time: time.Unix(600, 0), DurationSec: 600},
+	)
+
+	// Can't really parse the PDF content and check it, so just check that what's sent back is a PDF.
+	recorder := web.getHttpResponse("/reports/pdf/agenda")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
+	assert.Equal(
+		t, "Red 254 1114 vs. Blue 846", formatAgendaReportAlliances(model.Match{Red1: 254, Red2: 1114, Blue3: 846}),
+	)
+	assert.Equal(t, "", formatAgendaReportAlliances(model.Match{}))
+}
+
 func TestTeamsCsvReport(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -102,6 +167,165 @@ func TestTeamsCsvReport(t *testing.T) {
 	assert.Equal(t, expectedBody, recorder.Body.String())
 }
 
+func TestScoutingCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	scoutingApp := model.ScoutingApp{Name: "Tablets", ApiKey: "secret"}
+	web.arena.Database.CreateScoutingApp(&scoutingApp)
+	match := model.Match{Type: model.Qualification, ShortName: "Q3", Red1: 254, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6}
+	web.arena.Database.CreateMatch(&match)
+	web.arena.Database.CreateRanking(
+		&game.Ranking{TeamId: 254, Rank: 2, RankingFields: game.RankingFields{RankingPoints: 9}},
+	)
+	submittedAt := time.Unix(1700000000, 0)
+	web.arena.Database.CreateScoutingObservation(&model.ScoutingObservation{
+		ScoutingAppId: scoutingApp.Id,
+		MatchId:       match.Id,
+		TeamId:        254,
+		ScoutName:     "Alex",
+		SubmittedAt:   submittedAt,
+		Values:        map[string]int{"speaker": 5, "amp": 2},
+		Notes:         "Fast, reliable",
+	})
+	web.arena.Database.CreateScoutingObservation(&model.ScoutingObservation{
+		ScoutingAppId: scoutingApp.Id,
+		MatchId:       match.Id,
+		TeamId:        5,
+		ScoutName:     "Sam",
+		SubmittedAt:   submittedAt.Add(time.Second),
+		Values:        map[string]int{"climb": 1},
+	})
+
+	recorder := web.getHttpResponse("/reports/csv/scouting")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	expectedBody := "Match,Team,Rank,RankingPoints,App,Scout,SubmittedAt,amp,climb,speaker,Notes\n" +
+		"Q3,254,2,9,Tablets,Alex," + submittedAt.Format(time.RFC3339) + ",2,,5,\"Fast, reliable\"\n" +
+		"Q3,5,,,Tablets,Sam," + submittedAt.Add(time.Second).Format(time.RFC3339) + ",,1,,\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
+
+func TestTeamCardsCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, ShortName: "Q7", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedCards = map[string]string{"2": "yellow"}
+	matchResult.BlueCards = map[string]string{"6": "red"}
+	matchResult.RedCardRules = map[string]int{"2": 19}
+	web.arena.Database.CreateMatchResult(matchResult)
+
+	recorder := web.getHttpResponse("/reports/csv/team_cards")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	expectedBody := "Match,Type,Team,Alliance,Card,Rule\nQ7,Qualification,2,red,yellow,G415\n" +
+		"Q7,Qualification,6,blue,red,\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
+
+func TestTeamBypassesCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, ShortName: "Q7", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedBypasses = map[string]string{"2": model.BypassNoShow}
+	matchResult.BlueBypasses = map[string]string{"6": model.BypassRobotFailure}
+	web.arena.Database.CreateMatchResult(matchResult)
+
+	recorder := web.getHttpResponse("/reports/csv/team_bypasses")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	expectedBody := "Match,Type,Team,Alliance,Reason\nQ7,Qualification,2,red,No-show\n" +
+		"Q7,Qualification,6,blue,Robot failure\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
+
+func TestResultsCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, ShortName: "Q7", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	web.arena.Database.CreateMatchResult(matchResult)
+	startTime := time.Unix(1700000000, 0)
+	web.arena.Database.CreateVideoMarker(
+		&model.VideoMarker{MatchId: match.Id, Type: model.VideoMarkerMatchStart, Time: startTime},
+	)
+	web.arena.Database.CreateVideoMarker(
+		&model.VideoMarker{
+			MatchId:         match.Id,
+			Type:            model.VideoMarkerMatchEnd,
+			Time:            startTime.Add(150 * time.Second),
+			StreamSynced:    true,
+			StreamOffsetSec: 3725.25,
+		},
+	)
+
+	recorder := web.getHttpResponse("/reports/csv/results/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	lines := strings.Split(recorder.Body.String(), "\n")
+	assert.True(t, strings.HasSuffix(lines[0], ",StartedAt,StreamStartSec,EndedAt,StreamEndSec"))
+	assert.True(
+		t,
+		strings.HasSuffix(
+			lines[1],
+			fmt.Sprintf(",%s,,%s,3725.2", startTime.Local(), startTime.Add(150*time.Second).Local()),
+		),
+	)
+}
+
+func TestTimelinesCsvReport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := model.Match{Type: model.Qualification, ShortName: "Q7", Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	otherMatch := model.Match{Type: model.Practice, ShortName: "P1"}
+	web.arena.Database.CreateMatch(&otherMatch)
+	startTime := time.Date(2024, 10, 5, 13, 0, 0, 0, time.Local)
+	web.arena.Database.CreateMatchTimeline(
+		&model.MatchTimeline{
+			MatchId:    match.Id,
+			PlayNumber: 2,
+			Events: []model.MatchTimelineEvent{
+				{startTime, 0, model.TimelineMatchState, "", "Match state changed to Autonomous"},
+				{startTime.Add(1500 * time.Millisecond), 1500, model.TimelineStop, "R1", "E-stop activated, oh no"},
+			},
+		},
+	)
+	web.arena.Database.CreateMatchTimeline(
+		&model.MatchTimeline{
+			MatchId:    match.Id,
+			PlayNumber: 1,
+			Events:     []model.MatchTimelineEvent{{startTime, 0, model.TimelineFieldFault, "", "Field fault"}},
+		},
+	)
+	web.arena.Database.CreateMatchTimeline(
+		&model.MatchTimeline{
+			MatchId: otherMatch.Id,
+			Events:  []model.MatchTimelineEvent{{startTime, 0, model.TimelineMatchState, "", "Practice"}},
+		},
+	)
+
+	recorder := web.getHttpResponse("/reports/csv/timelines/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header()["Content-Type"][0])
+	expectedBody := "Match,PlayNumber,Time,MatchTimeSec,Type,Source,Description\n" +
+		"Q7,1,2024-10-05 13:00:00.000,0.000,fieldFault,,Field fault\n" +
+		"Q7,2,2024-10-05 13:00:00.000,0.000,matchState,,Match state changed to Autonomous\n" +
+		"Q7,2,2024-10-05 13:00:01.500,1.500,stop,R1,\"E-stop activated, oh no\"\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+
+	recorder = web.getHttpResponse("/reports/csv/timelines/blorpy")
+	assert.Equal(t, 500, recorder.Code)
+}
+
 func TestTeamsPdfReport(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -0,0 +1,49 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for reviewing and approving the scores of the current match before they are committed.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+)
+
+// Shows the differences between the provisional and edited scores of the current match.
+func (web *Web) scoreReviewGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.RefereeRole, model.ScorekeeperRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/score_review.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Match       *model.Match
+		ScoreReview *field.ScoreReview
+		Differences []model.ScoreEditChange
+	}{web.arena.EventSettings, web.arena.CurrentMatch, web.arena.ScoreReview, web.arena.ScoreReviewDifferences()}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Records the scorekeeper's approval of the scores of the current match.
+func (web *Web) scoreReviewPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.RefereeRole, model.ScorekeeperRole) {
+		return
+	}
+
+	if err := web.arena.ApproveScores(field.ScorekeeperRole); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, web.arena.PathPrefix()+"/match_play", 303)
+}
@@ -9,17 +9,18 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
 	"io"
-	"log"
 	"net/http"
 )
 
 // Renders the scoring interface which enables input of scores in real-time.
 func (web *Web) scoringPanelHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	panelDevice, ok := web.panelDeviceHasRole(w, r, model.RefereeRole)
+	if !ok {
 		return
 	}
 
@@ -28,6 +29,10 @@ func (web *Web) scoringPanelHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, fmt.Errorf("Invalid alliance '%s'.", alliance))
 		return
 	}
+	if err := web.setPanelDevicePath(panelDevice, r); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 
 	template, err := web.parseFiles("templates/scoring_panel.html", "templates/base.html")
 	if err != nil {
@@ -38,7 +43,8 @@ func (web *Web) scoringPanelHandler(w http.ResponseWriter, r *http.Request) {
 		*model.EventSettings
 		PlcIsEnabled bool
 		Alliance     string
-	}{web.arena.EventSettings, web.arena.Plc.IsEnabled(), alliance}
+		Sections     []game.ScoringPanelSection
+	}{web.arena.EventSettings, web.arena.Plc.IsEnabled(), alliance, game.CurrentGame().ScoringPanelLayout()}
 	err = template.ExecuteTemplate(w, "base_no_navbar", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -48,7 +54,8 @@ func (web *Web) scoringPanelHandler(w http.ResponseWriter, r *http.Request) {
 
 // The websocket endpoint for the scoring interface client to send control commands and receive status updates.
 func (web *Web) scoringPanelWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	panelDevice, ok := web.panelDeviceHasRole(w, r, model.RefereeRole)
+	if !ok {
 		return
 	}
 
@@ -72,13 +79,17 @@ func (web *Web) scoringPanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 	}
 	defer ws.Close()
 	web.arena.ScoringPanelRegistry.RegisterPanel(alliance, ws)
+	if panelDevice != nil && panelDevice.IsScoreCommitted(web.arena.CurrentMatch.Id) {
+		// Pick up where the panel left off if it had already committed its score before reconnecting.
+		web.arena.ScoringPanelRegistry.SetScoreCommitted(alliance, ws)
+	}
 	web.arena.ScoringStatusNotifier.Notify()
 	defer web.arena.ScoringStatusNotifier.Notify()
 	defer web.arena.ScoringPanelRegistry.UnregisterPanel(alliance, ws)
 
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
-	go ws.HandleNotifiers(web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier, web.arena.RealtimeScoreNotifier,
-		web.arena.ReloadDisplaysNotifier)
+	go ws.HandleNotifiers(web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier, web.arena.MatchTimingNotifier,
+		web.arena.RealtimeScoreNotifier, web.arena.ReloadDisplaysNotifier)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
 	for {
@@ -88,7 +99,10 @@ func (web *Web) scoringPanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Scoring.Warn("Websocket read error.", "error", err)
+			return
+		}
+		if !web.panelDeviceIsActive(panelDevice, ws) {
 			return
 		}
 		score := &(*realtimeScore).CurrentScore
@@ -102,10 +116,18 @@ func (web *Web) scoringPanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 			}
 			web.arena.ScoringPanelRegistry.SetScoreCommitted(alliance, ws)
 			web.arena.ScoringStatusNotifier.Notify()
+			if panelDevice != nil {
+				panelDevice.MatchId = web.arena.CurrentMatch.Id
+				panelDevice.ScoreCommitted = true
+				if err = web.arena.Database.UpdatePanelDevice(panelDevice); err != nil {
+					logging.Scoring.Error("Failed to save panel device.", "device", panelDevice.Name, "error", err)
+				}
+			}
 		} else {
 			args := struct {
 				TeamPosition int
 				StageIndex   int
+				Delta        int
 			}{}
 			err = mapstructure.Decode(data, &args)
 			if err != nil {
@@ -113,45 +135,54 @@ func (web *Web) scoringPanelWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 
+			history := &(*realtimeScore).ScoringHistory
 			switch command {
-			case "leave":
-				if args.TeamPosition >= 1 && args.TeamPosition <= 3 {
-					score.LeaveStatuses[args.TeamPosition-1] = !score.LeaveStatuses[args.TeamPosition-1]
-					scoreChanged = true
-				}
-			case "onStage":
-				if args.TeamPosition >= 1 && args.TeamPosition <= 3 && args.StageIndex >= 0 && args.StageIndex <= 2 {
-					endgameStatus := game.EndgameStatus(args.StageIndex + 2)
-					if score.EndgameStatuses[args.TeamPosition-1] == endgameStatus {
-						score.EndgameStatuses[args.TeamPosition-1] = game.EndgameNone
-					} else {
-						score.EndgameStatuses[args.TeamPosition-1] = endgameStatus
-					}
-					scoreChanged = true
-				}
-			case "park":
-				if args.TeamPosition >= 1 && args.TeamPosition <= 3 {
-					if score.EndgameStatuses[args.TeamPosition-1] == game.EndgameParked {
-						score.EndgameStatuses[args.TeamPosition-1] = game.EndgameNone
-					} else {
-						score.EndgameStatuses[args.TeamPosition-1] = game.EndgameParked
-					}
-					scoreChanged = true
-				}
-			case "microphone":
-				if args.StageIndex >= 0 && args.StageIndex <= 2 {
-					score.MicrophoneStatuses[args.StageIndex] = !score.MicrophoneStatuses[args.StageIndex]
-					scoreChanged = true
+			case "undo":
+				scoreChanged = history.Undo(score, web.arena.MatchTimeSec())
+			case "redo":
+				scoreChanged = history.Redo(score, web.arena.MatchTimeSec())
+			default:
+				// Don't allow elements to be scored before their period of a match in progress has started.
+				matchState := web.arena.MatchState
+				matchIsRunning := matchState >= field.StartMatch && matchState <= field.TeleopPeriod ||
+					matchState == field.FieldFault
+				if element := game.GetScoringPanelElement(command, args.StageIndex); element != nil &&
+					matchIsRunning && web.arena.MatchTimeSec() < element.Period.StartTimeSec() {
+					ws.WriteError(fmt.Sprintf("Cannot score %s before the %s period.", element.Name, element.Period))
+					continue
 				}
-			case "trap":
-				if args.StageIndex >= 0 && args.StageIndex <= 2 {
-					score.TrapStatuses[args.StageIndex] = !score.TrapStatuses[args.StageIndex]
+				if scoringCommand, ok := game.NewScoringCommand(
+					command, args.TeamPosition, args.StageIndex, args.Delta, score,
+				); ok {
+					history.Execute(score, scoringCommand, web.arena.MatchTimeSec())
+					// A human scorer's input takes precedence over any provisional value from the vision system.
+					(*realtimeScore).HumanScored.Set(scoringCommand, true)
+					(*realtimeScore).MachineScored.Set(scoringCommand, false)
 					scoreChanged = true
 				}
 			}
 
 			if scoreChanged {
+				entry := history.Log[len(history.Log)-1]
+				oldValue, newValue := entry.Command.OldValue, entry.Command.NewValue
+				if entry.Action == game.ScoringActionUndo {
+					oldValue, newValue = newValue, oldValue
+				}
+				web.arena.RecordTimelineEvent(
+					model.TimelineScore,
+					alliance,
+					fmt.Sprintf(
+						"Scorer %s: %s (position %d, stage %d) changed from %d to %d",
+						entry.Action,
+						entry.Command.Command,
+						entry.Command.TeamPosition,
+						entry.Command.StageIndex,
+						oldValue,
+						newValue,
+					),
+				)
 				web.arena.RealtimeScoreNotifier.Notify()
+				web.arena.LiveScoreNotifier.Notify()
 			}
 		}
 	}
@@ -24,6 +24,8 @@ func TestScoringPanel(t *testing.T) {
 	recorder = web.getHttpResponse("/panels/scoring/blue")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Scoring Panel - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "Center Stage")
+	assert.Contains(t, recorder.Body.String(), "data-key=\"onStage:3:1\"")
 }
 
 func TestScoringPanelWebsocket(t *testing.T) {
@@ -49,9 +51,11 @@ func TestScoringPanelWebsocket(t *testing.T) {
 	// Should get a few status updates right after connection.
 	readWebsocketType(t, redWs, "matchLoad")
 	readWebsocketType(t, redWs, "matchTime")
+	readWebsocketType(t, redWs, "matchTiming")
 	readWebsocketType(t, redWs, "realtimeScore")
 	readWebsocketType(t, blueWs, "matchLoad")
 	readWebsocketType(t, blueWs, "matchTime")
+	readWebsocketType(t, blueWs, "matchTiming")
 	readWebsocketType(t, blueWs, "realtimeScore")
 
 	// Send some autonomous period scoring commands.
@@ -62,37 +66,27 @@ func TestScoringPanelWebsocket(t *testing.T) {
 	}{}
 	web.arena.MatchState = field.AutoPeriod
 	scoringData.TeamPosition = 1
-	redWs.Write("leave", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "leave", scoringData)
 	scoringData.TeamPosition = 3
-	redWs.Write("leave", scoringData)
-	for i := 0; i < 2; i++ {
-		readWebsocketType(t, redWs, "realtimeScore")
-		readWebsocketType(t, blueWs, "realtimeScore")
-	}
+	writeScoringCommand(t, redWs, blueWs, redWs, "leave", scoringData)
 	assert.Equal(t, [3]bool{true, false, true}, web.arena.RedRealtimeScore.CurrentScore.LeaveStatuses)
-	redWs.Write("leave", scoringData)
-	readWebsocketType(t, redWs, "realtimeScore")
-	readWebsocketType(t, blueWs, "realtimeScore")
+	writeScoringCommand(t, redWs, blueWs, redWs, "leave", scoringData)
 	assert.Equal(t, [3]bool{true, false, false}, web.arena.RedRealtimeScore.CurrentScore.LeaveStatuses)
 
 	// Send some teleoperated period scoring commands.
 	web.arena.MatchState = field.TeleopPeriod
 	scoringData.TeamPosition = 1
 	scoringData.StageIndex = 0
-	blueWs.Write("onStage", scoringData)
+	writeScoringCommand(t, redWs, blueWs, blueWs, "onStage", scoringData)
 	scoringData.TeamPosition = 2
 	scoringData.StageIndex = 1
-	blueWs.Write("onStage", scoringData)
+	writeScoringCommand(t, redWs, blueWs, blueWs, "onStage", scoringData)
 	scoringData.TeamPosition = 3
 	scoringData.StageIndex = 2
-	redWs.Write("onStage", scoringData)
-	redWs.Write("microphone", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "onStage", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "microphone", scoringData)
 	scoringData.StageIndex = 0
-	redWs.Write("trap", scoringData)
-	for i := 0; i < 5; i++ {
-		readWebsocketType(t, redWs, "realtimeScore")
-		readWebsocketType(t, blueWs, "realtimeScore")
-	}
+	writeScoringCommand(t, redWs, blueWs, redWs, "trap", scoringData)
 	assert.Equal(
 		t,
 		[3]game.EndgameStatus{game.EndgameStageLeft, game.EndgameCenterStage, game.EndgameNone},
@@ -108,20 +102,16 @@ func TestScoringPanelWebsocket(t *testing.T) {
 	assert.Equal(t, [3]bool{false, false, true}, web.arena.RedRealtimeScore.CurrentScore.MicrophoneStatuses)
 	assert.Equal(t, [3]bool{true, false, false}, web.arena.RedRealtimeScore.CurrentScore.TrapStatuses)
 	scoringData.StageIndex = 1
-	redWs.Write("trap", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "trap", scoringData)
 	scoringData.StageIndex = 0
-	redWs.Write("trap", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "trap", scoringData)
 	scoringData.StageIndex = 2
-	redWs.Write("microphone", scoringData)
+	writeScoringCommand(t, redWs, blueWs, redWs, "microphone", scoringData)
 	scoringData.TeamPosition = 1
-	blueWs.Write("park", scoringData)
+	writeScoringCommand(t, redWs, blueWs, blueWs, "park", scoringData)
 	scoringData.TeamPosition = 2
 	scoringData.StageIndex = 1
-	blueWs.Write("onStage", scoringData)
-	for i := 0; i < 5; i++ {
-		readWebsocketType(t, redWs, "realtimeScore")
-		readWebsocketType(t, blueWs, "realtimeScore")
-	}
+	writeScoringCommand(t, redWs, blueWs, blueWs, "onStage", scoringData)
 	assert.Equal(
 		t,
 		[3]game.EndgameStatus{game.EndgameParked, game.EndgameNone, game.EndgameNone},
@@ -130,8 +120,25 @@ func TestScoringPanelWebsocket(t *testing.T) {
 	assert.Equal(t, [3]bool{false, false, false}, web.arena.RedRealtimeScore.CurrentScore.MicrophoneStatuses)
 	assert.Equal(t, [3]bool{false, true, false}, web.arena.RedRealtimeScore.CurrentScore.TrapStatuses)
 
+	// Step back through the most recent scoring actions and then reapply one.
+	writeScoringCommand(t, redWs, blueWs, redWs, "undo", nil)
+	writeScoringCommand(t, redWs, blueWs, redWs, "undo", nil)
+	writeScoringCommand(t, redWs, blueWs, redWs, "redo", nil)
+	assert.Equal(t, [3]bool{false, false, true}, web.arena.RedRealtimeScore.CurrentScore.MicrophoneStatuses)
+	assert.Equal(t, [3]bool{false, true, false}, web.arena.RedRealtimeScore.CurrentScore.TrapStatuses)
+	assert.True(t, web.arena.RedRealtimeScore.ScoringHistory.CanRedo())
+	redLog := web.arena.RedRealtimeScore.ScoringHistory.Log
+	if assert.Equal(t, 12, len(redLog)) {
+		assert.Equal(t, game.ScoringActionUndo, redLog[9].Action)
+		assert.Equal(t, "microphone", redLog[9].Command.Command)
+		assert.Equal(t, game.ScoringActionRedo, redLog[11].Action)
+		assert.Equal(t, "trap", redLog[11].Command.Command)
+	}
+	assert.Equal(t, redLog, web.getCurrentMatchResult().RedScoringLog)
+
 	// Test that some invalid commands do nothing and don't result in score change notifications.
 	redWs.Write("invalid", nil)
+	blueWs.Write("redo", nil)
 	scoringData.TeamPosition = 0
 	redWs.Write("leave", scoringData)
 	scoringData.TeamPosition = 4
@@ -166,3 +173,40 @@ func TestScoringPanelWebsocket(t *testing.T) {
 	assert.Equal(t, 0, web.arena.ScoringPanelRegistry.GetNumScoreCommitted("red"))
 	assert.Equal(t, 0, web.arena.ScoringPanelRegistry.GetNumScoreCommitted("blue"))
 }
+
+func TestScoringPanelWebsocketPeriods(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/scoring/red/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 4)
+
+	// Check that an element can't be scored before its period has started.
+	web.arena.MatchState = field.AutoPeriod
+	web.arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+1) * time.Second)
+	ws.Write("park", map[string]int{"TeamPosition": 2})
+	assert.Equal(t, "Cannot score Park before the teleop period.", readWebsocketError(t, ws))
+	ws.Write("leave", map[string]int{"TeamPosition": 2})
+	readWebsocketType(t, ws, "realtimeScore")
+	assert.Equal(t, [3]bool{false, true, false}, web.arena.RedRealtimeScore.CurrentScore.LeaveStatuses)
+
+	// Check that any element can be scored once the match is over.
+	web.arena.MatchState = field.PostMatch
+	ws.Write("park", map[string]int{"TeamPosition": 2})
+	readWebsocketType(t, ws, "realtimeScore")
+	assert.Equal(t, game.EndgameParked, web.arena.RedRealtimeScore.CurrentScore.EndgameStatuses[1])
+}
+
+// Sends the given command from one of the scoring panels and waits for the resulting score update to reach both of
+// them, so that the next update isn't coalesced with it.
+func writeScoringCommand(
+	t *testing.T, redWs, blueWs, senderWs *websocket.Websocket, messageType string, data any,
+) {
+	senderWs.Write(messageType, data)
+	readWebsocketType(t, redWs, "realtimeScore")
+	readWebsocketType(t, blueWs, "realtimeScore")
+}
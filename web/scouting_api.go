@@ -0,0 +1,172 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web API through which registered scouting apps fetch the schedule and submit their observations.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"time"
+)
+
+// Represents a match in the schedule as presented to scouting apps.
+type scoutingMatch struct {
+	Id        int
+	ShortName string
+	LongName  string
+	Time      time.Time
+	Status    game.MatchStatus
+	RedTeams  []int
+	BlueTeams []int
+}
+
+// Represents a single observation as submitted by a scouting app.
+type scoutingObservationSubmission struct {
+	MatchId   int
+	TeamId    int
+	ScoutName string
+	Values    map[string]int
+	Notes     string
+}
+
+// Generates a JSON dump of the schedule of the given match type for use by scouting apps.
+func (web *Web) scoutingScheduleApiHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := web.getScoutingApp(w, r); !ok {
+		return
+	}
+
+	matchType, err := model.MatchTypeFromString(r.PathValue("type"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	scoutingMatches := make([]scoutingMatch, len(matches))
+	for i, match := range matches {
+		scoutingMatches[i] = scoutingMatch{
+			Id:        match.Id,
+			ShortName: match.ShortName,
+			LongName:  match.LongName,
+			Time:      match.Time,
+			Status:    match.Status,
+			RedTeams:  match.RedTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+			BlueTeams: match.BlueTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(scoutingMatches, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Accepts a batch of observations from a scouting app. Resubmitting an observation for the same scout, team, and match
+// replaces the previous one, so that apps can safely retry after losing connectivity.
+func (web *Web) scoutingObservationsApiHandler(w http.ResponseWriter, r *http.Request) {
+	scoutingApp, ok := web.getScoutingApp(w, r)
+	if !ok {
+		return
+	}
+
+	var submissions []scoutingObservationSubmission
+	if err := json.NewDecoder(r.Body).Decode(&submissions); err != nil {
+		http.Error(w, "Invalid observations: "+err.Error(), 400)
+		return
+	}
+
+	// Validate the whole batch before saving any of it.
+	for _, submission := range submissions {
+		match, err := web.arena.Database.GetMatchById(submission.MatchId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if match == nil {
+			http.Error(w, fmt.Sprintf("Invalid match ID %d.", submission.MatchId), 400)
+			return
+		}
+		if !matchHasTeam(match, submission.TeamId) {
+			http.Error(w, fmt.Sprintf("Team %d is not in match %s.", submission.TeamId, match.ShortName), 400)
+			return
+		}
+	}
+
+	now := time.Now()
+	for _, submission := range submissions {
+		observation := model.ScoutingObservation{
+			ScoutingAppId: scoutingApp.Id,
+			MatchId:       submission.MatchId,
+			TeamId:        submission.TeamId,
+			ScoutName:     submission.ScoutName,
+			SubmittedAt:   now,
+			Values:        submission.Values,
+			Notes:         submission.Notes,
+		}
+		existingObservation, err := web.arena.Database.GetScoutingObservation(
+			scoutingApp.Id, submission.MatchId, submission.TeamId, submission.ScoutName,
+		)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if existingObservation != nil {
+			observation.Id = existingObservation.Id
+			err = web.arena.Database.UpdateScoutingObservation(&observation)
+		} else {
+			err = web.arena.Database.CreateScoutingObservation(&observation)
+		}
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	w.WriteHeader(204)
+}
+
+// Returns the registered scouting app whose API key is carried by the request, either as a bearer token or as the
+// apiKey query parameter. Writes an error response and returns false if there is none.
+func (web *Web) getScoutingApp(w http.ResponseWriter, r *http.Request) (*model.ScoutingApp, bool) {
+	providedKey := getRequestApiToken(r)
+	if providedKey == "" {
+		http.Error(w, "Missing scouting API key.", 401)
+		return nil, false
+	}
+	scoutingApp, err := web.arena.Database.GetScoutingAppByApiKey(providedKey)
+	if err != nil {
+		handleWebErr(w, err)
+		return nil, false
+	}
+	if scoutingApp == nil {
+		http.Error(w, "Invalid scouting API key.", 401)
+		return nil, false
+	}
+	return scoutingApp, true
+}
+
+// Returns true if the given team is one of those playing in the given match.
+func matchHasTeam(match *model.Match, teamId int) bool {
+	for _, id := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+		if teamId != 0 && id == teamId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoutingScheduleApi(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateScoutingApp(&model.ScoutingApp{Name: "Tablets", ApiKey: "secret"})
+	match := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	web.arena.Database.CreateMatch(&match)
+
+	recorder := web.getHttpResponse("/api/scouting/schedule/qualification")
+	assert.Equal(t, 401, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Missing scouting API key.")
+	recorder = web.getHttpResponse("/api/scouting/schedule/qualification?apiKey=wrong")
+	assert.Equal(t, 401, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid scouting API key.")
+
+	recorder = web.getHttpResponseWithHeaders(
+		"/api/scouting/schedule/qualification", map[string]string{"Authorization": "Bearer secret"},
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var matches []scoutingMatch
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &matches))
+	if assert.Equal(t, 1, len(matches)) {
+		assert.Equal(t, match.Id, matches[0].Id)
+		assert.Equal(t, "Q1", matches[0].ShortName)
+		assert.Equal(t, []int{1, 2, 3}, matches[0].RedTeams)
+		assert.Equal(t, []int{4, 5, 6}, matches[0].BlueTeams)
+	}
+
+	recorder = web.getHttpResponse("/api/scouting/schedule/practice?apiKey=secret")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &matches))
+	assert.Empty(t, matches)
+
+	recorder = web.getHttpResponse("/api/scouting/schedule/blorpy?apiKey=secret")
+	assert.Equal(t, 400, recorder.Code)
+}
+
+func TestScoutingObservationsApi(t *testing.T) {
+	web := setupTestWeb(t)
+	scoutingApp := model.ScoutingApp{Name: "Tablets", ApiKey: "secret"}
+	web.arena.Database.CreateScoutingApp(&scoutingApp)
+	match := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	web.arena.Database.CreateMatch(&match)
+
+	body := `[{"MatchId": 1, "TeamId": 5, "ScoutName": "Alex", "Values": {"notesScored": 4}, "Notes": "Tipped"}]`
+	recorder := web.postHttpResponse("/api/scouting/observations", body)
+	assert.Equal(t, 401, recorder.Code)
+
+	recorder = web.postHttpResponse("/api/scouting/observations?apiKey=secret", body)
+	assert.Equal(t, 204, recorder.Code)
+	observations, _ := web.arena.Database.GetAllScoutingObservations()
+	if assert.Equal(t, 1, len(observations)) {
+		assert.Equal(t, scoutingApp.Id, observations[0].ScoutingAppId)
+		assert.Equal(t, 5, observations[0].TeamId)
+		assert.Equal(t, "Alex", observations[0].ScoutName)
+		assert.Equal(t, map[string]int{"notesScored": 4}, observations[0].Values)
+		assert.Equal(t, "Tipped", observations[0].Notes)
+	}
+
+	// Resubmitting the same observation should replace the original.
+	body = `[{"MatchId": 1, "TeamId": 5, "ScoutName": "Alex", "Values": {"notesScored": 6}},
+		{"MatchId": 1, "TeamId": 2, "ScoutName": "Sam", "Values": {"notesScored": 1}}]`
+	recorder = web.postHttpResponse("/api/scouting/observations?apiKey=secret", body)
+	assert.Equal(t, 204, recorder.Code)
+	observations, _ = web.arena.Database.GetAllScoutingObservations()
+	if assert.Equal(t, 2, len(observations)) {
+		observation, _ := web.arena.Database.GetScoutingObservation(scoutingApp.Id, 1, 5, "Alex")
+		assert.Equal(t, map[string]int{"notesScored": 6}, observation.Values)
+		assert.Equal(t, "", observation.Notes)
+	}
+
+	// Invalid batches should be rejected without saving anything.
+	body = `[{"MatchId": 1, "TeamId": 3, "ScoutName": "Alex"}, {"MatchId": 2, "TeamId": 5, "ScoutName": "Alex"}]`
+	recorder = web.postHttpResponse("/api/scouting/observations?apiKey=secret", body)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid match ID 2.")
+	recorder = web.postHttpResponse(
+		"/api/scouting/observations?apiKey=secret", `[{"MatchId": 1, "TeamId": 254, "ScoutName": "Alex"}]`,
+	)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 254 is not in match Q1.")
+	recorder = web.postHttpResponse("/api/scouting/observations?apiKey=secret", "{")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid observations")
+	observations, _ = web.arena.Database.GetAllScoutingObservations()
+	assert.Equal(t, 2, len(observations))
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for managing the timed items of the event day's run of show.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Format of the start time field, as submitted by a datetime-local input.
+const agendaItemTimeFormat = "2006-01-02T15:04"
+
+// Shows the run of show configuration page.
+func (web *Web) agendaGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_agenda.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	agendaItems, err := web.arena.Database.GetAllAgendaItems()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Append a blank item to the end that can be used to add a new one.
+	agendaItems = append(agendaItems, model.AgendaItem{})
+
+	data := struct {
+		*model.EventSettings
+		AgendaItems []model.AgendaItem
+	}{web.arena.EventSettings, agendaItems}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Saves the new or modified run of show items to the database.
+func (web *Web) agendaPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	agendaItemId, _ := strconv.Atoi(r.PostFormValue("id"))
+	if r.PostFormValue("action") == "delete" {
+		if err := web.arena.Database.DeleteAgendaItem(agendaItemId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	} else {
+		// A missing or malformed start time is left as zero so that it gets rejected when the item is validated.
+		startTime, _ := time.ParseInLocation(
+			agendaItemTimeFormat, r.PostFormValue("startTime"), web.arena.EventSettings.Location(),
+		)
+		durationMin, _ := strconv.Atoi(r.PostFormValue("durationMin"))
+		agendaItem := model.AgendaItem{
+			Id: agendaItemId, Name: r.PostFormValue("name"), StartTime: startTime, DurationMin: durationMin,
+		}
+		var err error
+		if agendaItemId == 0 {
+			err = web.arena.Database.CreateAgendaItem(&agendaItem)
+		} else {
+			err = web.arena.Database.UpdateAgendaItem(&agendaItem)
+		}
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	if err := web.arena.UpdateAgenda(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	http.Redirect(w, r, "/setup/agenda", 303)
+}
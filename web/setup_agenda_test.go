@@ -0,0 +1,53 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetupAgenda(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.TimeZone = "America/Chicago"
+	location := web.arena.EventSettings.Location()
+
+	recorder := web.getHttpResponse("/setup/agenda")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Run of Show")
+
+	recorder = web.postHttpResponse(
+		"/setup/agenda", "action=save&name=Opening Ceremony&startTime=2099-03-09T08:30&durationMin=15",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	agendaItems, _ := web.arena.Database.GetAllAgendaItems()
+	if assert.Equal(t, 1, len(agendaItems)) {
+		assert.Equal(t, "Opening Ceremony", agendaItems[0].Name)
+		assert.True(t, time.Date(2099, 3, 9, 8, 30, 0, 0, location).Equal(agendaItems[0].StartTime))
+		assert.Equal(t, 15, agendaItems[0].DurationMin)
+	}
+	if assert.NotNil(t, web.arena.NextAgendaItem) {
+		assert.Equal(t, "Opening Ceremony", web.arena.NextAgendaItem.Name)
+	}
+	recorder = web.getHttpResponse("/setup/agenda")
+	assert.Contains(t, recorder.Body.String(), "2099-03-09T08:30")
+
+	recorder = web.postHttpResponse(
+		"/setup/agenda", "action=save&id=1&name=Opening Ceremony&startTime=2099-03-09T09:00&durationMin=20",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	agendaItem, _ := web.arena.Database.GetAgendaItemById(1)
+	assert.Equal(t, 20, agendaItem.DurationMin)
+
+	recorder = web.postHttpResponse("/setup/agenda", "action=save&name=Awards&startTime=&durationMin=0")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "agenda item start time must be set")
+
+	recorder = web.postHttpResponse("/setup/agenda", "action=delete&id=1")
+	assert.Equal(t, 303, recorder.Code)
+	agendaItems, _ = web.arena.Database.GetAllAgendaItems()
+	assert.Empty(t, agendaItems)
+	assert.Nil(t, web.arena.NextAgendaItem)
+}
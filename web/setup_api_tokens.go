@@ -0,0 +1,70 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for issuing the tokens that external tools use to access the versioned REST API.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// Shows the API token management page.
+func (web *Web) apiTokensGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_api_tokens.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	apiTokens, err := web.arena.Database.GetAllApiTokens()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		ApiTokens []model.ApiToken
+		ApiScopes []string
+		Endpoints []apiV1Endpoint
+	}{web.arena.EventSettings, apiTokens, model.ApiScopes, apiV1Endpoints}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Issues a new API token or revokes an existing one.
+func (web *Web) apiTokensPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if r.PostFormValue("action") == "delete" {
+		apiTokenId, _ := strconv.Atoi(r.PostFormValue("id"))
+		if err := web.arena.Database.DeleteApiToken(apiTokenId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	} else if name := r.PostFormValue("name"); name != "" {
+		scope := r.PostFormValue("scope")
+		if !slices.Contains(model.ApiScopes, scope) {
+			scope = model.ApiReadScope
+		}
+		apiToken := model.ApiToken{Name: name, Token: uuid.New().String(), Scope: scope}
+		if err := web.arena.Database.CreateApiToken(&apiToken); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/setup/api_tokens", 303)
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupApiTokens(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/api_tokens")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "API Tokens")
+	assert.Contains(t, recorder.Body.String(), "/api/v1/rankings")
+
+	recorder = web.postHttpResponse("/setup/api_tokens", "action=create&name=Stream Graphics&scope=read")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/api_tokens", "action=create&name=Inspection&scope=blorpy")
+	assert.Equal(t, 303, recorder.Code)
+	apiTokens, _ := web.arena.Database.GetAllApiTokens()
+	if assert.Equal(t, 2, len(apiTokens)) {
+		assert.Equal(t, "Stream Graphics", apiTokens[0].Name)
+		assert.Equal(t, model.ApiReadScope, apiTokens[0].Scope)
+		assert.NotEmpty(t, apiTokens[0].Token)
+		assert.Equal(t, model.ApiReadScope, apiTokens[1].Scope)
+		recorder = web.getHttpResponse("/setup/api_tokens")
+		assert.Contains(t, recorder.Body.String(), "Stream Graphics")
+		assert.Contains(t, recorder.Body.String(), apiTokens[0].Token)
+	}
+
+	recorder = web.postHttpResponse("/setup/api_tokens", fmt.Sprintf("action=delete&id=%d", apiTokens[0].Id))
+	assert.Equal(t, 303, recorder.Code)
+	apiTokens, _ = web.arena.Database.GetAllApiTokens()
+	assert.Equal(t, 1, len(apiTokens))
+}
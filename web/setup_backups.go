@@ -0,0 +1,110 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for browsing the saved database backups and restoring the database to one of them.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"os"
+)
+
+// Shows the list of database backups.
+func (web *Web) backupsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderBackups(w, r, "")
+}
+
+// Sends the given backup file to the client for safekeeping.
+func (web *Web) backupDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	backupPath, err := model.GetBackupPath(r.PathValue("filename"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", r.PathValue("filename")))
+	http.ServeFile(w, r, backupPath)
+}
+
+// Replaces the event database with the given backup, after backing up the current state in case of a mistake.
+func (web *Web) backupRestorePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	filename := r.PathValue("filename")
+	if web.arena.MatchState != field.PreMatch {
+		web.renderBackups(w, r, "The database can't be restored while a match is in progress.")
+		return
+	}
+
+	// Copy the backup to a temporary location and verify that it can be opened before replacing the database with it.
+	tempFile, err := os.CreateTemp(".", "restored-db-")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	tempFile.Close()
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+	if err = model.RestoreBackup(filename, tempFilePath); err != nil {
+		web.renderBackups(w, r, fmt.Sprintf("Failed to restore backup: %v", err))
+		return
+	}
+	tempDb, err := model.OpenDatabase(tempFilePath)
+	if err != nil {
+		web.renderBackups(w, r, fmt.Sprintf("Backup '%s' is not a valid database file.", filename))
+		return
+	}
+	tempDb.Close()
+
+	if err = web.arena.Database.Backup(web.arena.EventSettings.Name, "pre_restore"); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	err = web.arena.ReplaceDatabase(func(dbPath string) error {
+		return os.Rename(tempFilePath, dbPath)
+	})
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	cachedProjections = nil
+
+	http.Redirect(w, r, "/setup/backups", 303)
+}
+
+func (web *Web) renderBackups(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_backups.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	backups, err := model.ListBackups()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Backups      []model.BackupFile
+		ErrorMessage string
+	}{web.arena.EventSettings, backups, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupBackups(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Keep the backups in a scratch directory so that they don't end up in the source tree.
+	model.BaseDir = t.TempDir()
+	defer func() { model.BaseDir = ".." }()
+	templatesDir, _ := filepath.Abs("../templates")
+	assert.Nil(t, os.Symlink(templatesDir, filepath.Join(model.BaseDir, "templates")))
+
+	assert.Nil(t, web.arena.Database.CreateTeam(&model.Team{Id: 254}))
+	assert.Nil(t, web.arena.AutoBackupDatabase(model.PeriodicBackupReason))
+	assert.Nil(t, web.arena.Database.CreateTeam(&model.Team{Id: 1114}))
+	backups, _ := model.ListBackups()
+	if !assert.Equal(t, 1, len(backups)) {
+		return
+	}
+	filename := backups[0].Filename
+
+	recorder := web.getHttpResponse("/setup/backups")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), filename)
+	recorder = web.getHttpResponse("/setup/backups/" + filename)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/octet-stream", recorder.Header()["Content-Type"][0])
+	recorder = web.getHttpResponse("/setup/backups/blorpy.db")
+	assert.Equal(t, 500, recorder.Code)
+
+	// Restore the database to the point before the second team was added.
+	web.arena.MatchState = field.AutoPeriod
+	recorder = web.postHttpResponse("/setup/backups/"+filename+"/restore", "")
+	assert.Contains(t, recorder.Body.String(), "can't be restored while a match is in progress")
+	web.arena.MatchState = field.PreMatch
+	recorder = web.postHttpResponse("/setup/backups/"+filename+"/restore", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	teams, _ := web.arena.Database.GetAllTeams()
+	if assert.Equal(t, 1, len(teams)) {
+		assert.Equal(t, 254, teams[0].Id)
+	}
+	backups, _ = model.ListBackups()
+	if assert.Equal(t, 2, len(backups)) {
+		assert.Contains(t, []string{backups[0].Reason, backups[1].Reason}, "pre_restore")
+	}
+
+	recorder = web.postHttpResponse("/setup/backups/blorpy.db/restore", "")
+	assert.Contains(t, recorder.Body.String(), "invalid backup filename 'blorpy.db'")
+}
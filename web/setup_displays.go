@@ -8,17 +8,17 @@ package web
 import (
 	"fmt"
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
 	"io"
-	"log"
 	"net/http"
 )
 
 // Shows the displays configuration page.
 func (web *Web) displaysGetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole, model.FtaRole) {
 		return
 	}
 
@@ -40,7 +40,7 @@ func (web *Web) displaysGetHandler(w http.ResponseWriter, r *http.Request) {
 
 // The websocket endpoint for the display configuration page to send control commands and receive status updates.
 func (web *Web) displaysWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole, model.FtaRole) {
 		return
 	}
 
@@ -52,7 +52,7 @@ func (web *Web) displaysWebsocketHandler(w http.ResponseWriter, r *http.Request)
 	defer ws.Close()
 
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
-	go ws.HandleNotifiers(web.arena.DisplayConfigurationNotifier)
+	go ws.HandleNotifiers(web.arena.DisplayConfigurationNotifier, web.arena.DisplayAlertsNotifier)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
 	for {
@@ -62,7 +62,7 @@ func (web *Web) displaysWebsocketHandler(w http.ResponseWriter, r *http.Request)
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
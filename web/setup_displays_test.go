@@ -10,6 +10,7 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestSetupDisplays(t *testing.T) {
@@ -33,6 +34,7 @@ func TestSetupDisplaysWebsocket(t *testing.T) {
 	// Should get a few status updates right after connection.
 	message := readDisplayConfiguration(t, ws)
 	assert.Empty(t, message)
+	readWebsocketType(t, ws, "displayAlerts")
 
 	// Connect a couple of displays and verify the resulting configuration messages.
 	displayConn1, _, _ := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/display/websocket?displayId=1", nil)
@@ -77,6 +79,7 @@ func TestSetupDisplaysWebsocketReloadDisplays(t *testing.T) {
 
 	// Should get a few status updates right after connection.
 	readDisplayConfiguration(t, ws)
+	readWebsocketType(t, ws, "displayAlerts")
 
 	// Connect a display and verify the resulting configuration messages.
 	displayConn, _, _ := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/display/websocket?displayId=1", nil)
@@ -92,6 +95,39 @@ func TestSetupDisplaysWebsocketReloadDisplays(t *testing.T) {
 	assert.Equal(t, nil, readWebsocketType(t, displayWs, "reload"))
 }
 
+func TestSetupDisplaysWebsocketDisplayAlerts(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.DisplayAlertThresholdSec = 1
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/setup/displays/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readDisplayConfiguration(t, ws)
+	readWebsocketType(t, ws, "displayAlerts")
+
+	// Connect and then disconnect a critical display and verify that an alert is raised after the threshold.
+	displayConn, _, _ := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/displays/alliance_station/websocket?displayId=2&station=R2", nil,
+	)
+	displayWs := websocket.NewTestWebsocket(displayConn)
+	readWebsocketType(t, displayWs, "displayConfiguration")
+	readDisplayConfiguration(t, ws)
+	displayConn.Close()
+	readDisplayConfiguration(t, ws)
+	time.Sleep(500 * time.Millisecond)
+	message := readWebsocketType(t, ws, "displayAlerts").(map[string]any)
+	displayAlerts := message["DisplayAlerts"].([]any)
+	if assert.Equal(t, 1, len(displayAlerts)) {
+		displayAlert := displayAlerts[0].(map[string]any)
+		assert.Equal(t, "2", displayAlert["DisplayId"])
+		assert.Equal(t, "Alliance Station", displayAlert["Type"])
+		assert.Equal(t, "127.0.0.1", displayAlert["IpAddress"])
+	}
+}
+
 func readDisplayConfiguration(t *testing.T, ws *websocket.Websocket) map[string]field.Display {
 	message := readWebsocketType(t, ws, "displayConfiguration")
 	var displayConfigurationMessage map[string]field.Display
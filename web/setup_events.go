@@ -0,0 +1,162 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for creating, switching between, and archiving the databases of multiple events.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strings"
+)
+
+// Shows the event database management page.
+func (web *Web) eventsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderEvents(w, r, "")
+}
+
+// Creates a new event, switches to a stored one, or moves a stored one into or out of the archive.
+func (web *Web) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	action := r.PostFormValue("action")
+	name := r.PostFormValue("name")
+	switch action {
+	case "archive", "unarchive":
+		if err := model.SetEventDatabaseArchived(name, action == "archive"); err != nil {
+			web.renderEvents(w, r, fmt.Sprintf("Failed to %s event database: %v", action, err))
+			return
+		}
+	case "create", "switch":
+		if web.arena.MatchState != field.PreMatch {
+			web.renderEvents(w, r, "The event database can't be changed while a match is in progress.")
+			return
+		}
+		currentName := model.EventDatabaseName(web.arena.EventSettings.Name)
+		if model.EventDatabaseExists(currentName) {
+			web.renderEvents(
+				w,
+				r,
+				fmt.Sprintf(
+					"A stored event database named '%s' already exists. Rename the current event on the settings page "+
+						"before switching away from it.",
+					currentName,
+				),
+			)
+			return
+		}
+
+		if action == "create" {
+			eventName := strings.TrimSpace(r.PostFormValue("eventName"))
+			if eventName == "" {
+				web.renderEvents(w, r, "A name is required for the new event.")
+				return
+			}
+			copyTeamsFrom := r.PostFormValue("copyTeamsFrom")
+			if copyTeamsFrom == "current" {
+				copyTeamsFrom = currentName
+			}
+			err := web.arena.ReplaceDatabase(func(dbPath string) error {
+				return model.StoreEventDatabase(dbPath, currentName)
+			})
+			if err != nil {
+				handleWebErr(w, err)
+				return
+			}
+			if err = web.initializeEvent(eventName, copyTeamsFrom); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		} else {
+			if !model.EventDatabaseExists(name) {
+				web.renderEvents(w, r, fmt.Sprintf("Event database '%s' does not exist.", name))
+				return
+			}
+			err := web.arena.ReplaceDatabase(func(dbPath string) error {
+				if err := model.StoreEventDatabase(dbPath, currentName); err != nil {
+					return err
+				}
+				if err := model.ActivateEventDatabase(dbPath, name); err != nil {
+					// Put the previous event back so that the arena doesn't end up with an empty database.
+					if restoreErr := model.ActivateEventDatabase(dbPath, currentName); restoreErr != nil {
+						logging.Database.Error(
+							"Failed to reactivate event database.", "event", currentName, "error", restoreErr,
+						)
+					}
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+		cachedProjections = nil
+	}
+
+	http.Redirect(w, r, "/setup/events", 303)
+}
+
+// Names the newly created event database and populates its team list from the given stored event, if any.
+func (web *Web) initializeEvent(eventName, copyTeamsFrom string) error {
+	eventSettings := web.arena.SavedEventSettings()
+	eventSettings.Name = eventName
+	if err := web.arena.Database.UpdateEventSettings(eventSettings); err != nil {
+		return err
+	}
+	if err := web.arena.LoadSettings(); err != nil {
+		return err
+	}
+	if copyTeamsFrom != "" {
+		if _, err := web.arena.Database.CopyTeamsFromEventDatabase(copyTeamsFrom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (web *Web) renderEvents(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_events.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	eventDatabases, err := model.ListEventDatabases(false)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	archivedEventDatabases, err := model.ListEventDatabases(true)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		NumTeams               int
+		EventDatabases         []model.EventDatabaseFile
+		ArchivedEventDatabases []model.EventDatabaseFile
+		ErrorMessage           string
+	}{web.arena.EventSettings, len(teams), eventDatabases, archivedEventDatabases, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
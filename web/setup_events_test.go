@@ -0,0 +1,81 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupEvents(t *testing.T) {
+	// Keep the event databases in a scratch directory so that they don't end up in the source tree.
+	model.BaseDir = t.TempDir()
+	defer func() { model.BaseDir = ".." }()
+	templatesDir, _ := filepath.Abs("../templates")
+	assert.Nil(t, os.Symlink(templatesDir, filepath.Join(model.BaseDir, "templates")))
+	arena, err := field.NewArena(filepath.Join(model.BaseDir, "event.db"))
+	assert.Nil(t, err)
+	web := NewWeb(arena)
+	settings := web.arena.SavedEventSettings()
+	settings.Name = "Chezy Champs 2023"
+	assert.Nil(t, web.arena.Database.UpdateEventSettings(settings))
+	assert.Nil(t, web.arena.LoadSettings())
+	assert.Nil(t, web.arena.Database.CreateTeam(&model.Team{Id: 254}))
+	assert.Nil(t, web.arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 254, YellowCard: true}))
+	assert.Nil(t, web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q1"}))
+
+	recorder := web.getHttpResponse("/setup/events")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The current event is <b>Chezy Champs 2023</b>, with 1 teams.")
+
+	// Create a new event carrying the teams forward.
+	recorder = web.postHttpResponse("/setup/events", "action=create&eventName=&copyTeamsFrom=current")
+	assert.Contains(t, recorder.Body.String(), "A name is required for the new event.")
+	recorder = web.postHttpResponse(
+		"/setup/events", "action=create&eventName=Chezy+Champs+2024&copyTeamsFrom=current",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "Chezy Champs 2024", web.arena.EventSettings.Name)
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.NotNil(t, team)
+	teamStatus, _ := web.arena.Database.GetTeamStatus(254)
+	assert.False(t, teamStatus.YellowCard)
+	matches, _ := web.arena.Database.GetMatchesByType(model.Qualification, true)
+	assert.Empty(t, matches)
+	recorder = web.getHttpResponse("/setup/events")
+	assert.Contains(t, recorder.Body.String(), "Chezy_Champs_2023")
+
+	// Switch back to the previous event.
+	recorder = web.postHttpResponse("/setup/events", "action=switch&name=Blorpy")
+	assert.Contains(t, recorder.Body.String(), "Event database 'Blorpy' does not exist.")
+	recorder = web.postHttpResponse("/setup/events", "action=switch&name=Chezy_Champs_2023")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "Chezy Champs 2023", web.arena.EventSettings.Name)
+	matches, _ = web.arena.Database.GetMatchesByType(model.Qualification, true)
+	assert.Equal(t, 1, len(matches))
+	assert.True(t, model.EventDatabaseExists("Chezy_Champs_2024"))
+	assert.False(t, model.EventDatabaseExists("Chezy_Champs_2023"))
+
+	// Archive and unarchive the other event.
+	recorder = web.postHttpResponse("/setup/events", "action=archive&name=Chezy_Champs_2024")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	recorder = web.getHttpResponse("/setup/events")
+	assert.Contains(t, recorder.Body.String(), "Archived Events")
+	recorder = web.postHttpResponse("/setup/events", "action=switch&name=Chezy_Champs_2024")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Equal(t, "Chezy Champs 2023", web.arena.EventSettings.Name)
+	recorder = web.postHttpResponse("/setup/events", "action=unarchive&name=Chezy_Champs_2024")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+
+	// The database can't be switched during a match.
+	web.arena.MatchState = field.AutoPeriod
+	recorder = web.postHttpResponse("/setup/events", "action=switch&name=Chezy_Champs_2024")
+	assert.Contains(t, recorder.Body.String(), "can't be changed while a match is in progress")
+	web.arena.MatchState = field.PreMatch
+	assert.Nil(t, web.arena.Database.Close())
+}
@@ -7,12 +7,14 @@ package web
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"io"
-	"log"
 	"net/http"
+	"time"
 )
 
 // Shows the Field Testing page.
@@ -65,7 +67,7 @@ func (web *Web) fieldTestingWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
@@ -76,7 +78,9 @@ func (web *Web) fieldTestingWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
 				continue
 			}
-			web.arena.PlaySoundNotifier.NotifyWithMessage(sound)
+			web.arena.PlaySoundNotifier.NotifyWithMessage(
+				field.PlaySoundMessage{Name: sound, ScheduledTimeMs: time.Now().UnixMilli()},
+			)
 		default:
 			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
 			continue
@@ -39,8 +39,9 @@ func TestSetupFieldTestingWebsocket(t *testing.T) {
 	assert.Nil(t, err)
 	defer audienceConn.Close()
 	audienceWs := websocket.NewTestWebsocket(audienceConn)
-	readWebsocketMultiple(t, audienceWs, 9)
+	readWebsocketMultiple(t, audienceWs, 12)
 
 	ws.Write("playSound", "resume")
-	assert.Equal(t, "resume", readWebsocketType(t, audienceWs, "playSound"))
+	sound := readWebsocketType(t, audienceWs, "playSound")
+	assert.Equal(t, "resume", sound.(map[string]any)["Name"])
 }
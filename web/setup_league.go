@@ -0,0 +1,110 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for configuring the league whose standings are accumulated across several events.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Shows the league configuration page.
+func (web *Web) leagueGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_league.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	leagueSettings, err := league.LoadSettings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	eventDatabases, err := model.ListEventDatabases(false)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	archivedEventDatabases, err := model.ListEventDatabases(true)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	includedEventNames := make(map[string]bool)
+	for _, eventName := range leagueSettings.EventNames {
+		includedEventNames[eventName] = true
+	}
+	placementPoints := make([]string, len(leagueSettings.Rules.PlacementPoints))
+	for i, points := range leagueSettings.Rules.PlacementPoints {
+		placementPoints[i] = strconv.Itoa(points)
+	}
+	data := struct {
+		*model.EventSettings
+		LeagueSettings     *league.Settings
+		EventDatabases     []model.EventDatabaseFile
+		IncludedEventNames map[string]bool
+		PlacementPoints    string
+	}{
+		web.arena.EventSettings,
+		leagueSettings,
+		append(eventDatabases, archivedEventDatabases...),
+		includedEventNames,
+		strings.Join(placementPoints, ", "),
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Saves the league configuration.
+func (web *Web) leaguePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	leagueSettings := &league.Settings{
+		Name:               strings.TrimSpace(r.PostFormValue("name")),
+		EventNames:         r.PostForm["eventNames"],
+		IncludeActiveEvent: r.PostFormValue("includeActiveEvent") == "on",
+	}
+	leagueSettings.CountedEvents, _ = strconv.Atoi(r.PostFormValue("countedEvents"))
+	leagueSettings.Rules.ParticipationPoints, _ = strconv.Atoi(r.PostFormValue("participationPoints"))
+	leagueSettings.Rules.WinPoints, _ = strconv.Atoi(r.PostFormValue("winPoints"))
+	leagueSettings.Rules.TiePoints, _ = strconv.Atoi(r.PostFormValue("tiePoints"))
+	leagueSettings.Rules.LossPoints, _ = strconv.Atoi(r.PostFormValue("lossPoints"))
+	leagueSettings.Rules.RankingPointMultiplier, _ = strconv.Atoi(r.PostFormValue("rankingPointMultiplier"))
+	for _, pointsText := range strings.Split(r.PostFormValue("placementPoints"), ",") {
+		pointsText = strings.TrimSpace(pointsText)
+		if pointsText == "" {
+			continue
+		}
+		points, err := strconv.Atoi(pointsText)
+		if err != nil {
+			handleWebErr(w, fmt.Errorf("Invalid placement points '%s'.", pointsText))
+			return
+		}
+		leagueSettings.Rules.PlacementPoints = append(leagueSettings.Rules.PlacementPoints, points)
+	}
+	if err := league.SaveSettings(leagueSettings); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	http.Redirect(w, r, "/setup/league", 303)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/league"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupLeague(t *testing.T) {
+	web := setupTestWeb(t)
+	t.Cleanup(web.closeArchivedEvents)
+	useScratchEventDatabasesDir(t)
+	createTestEventArchive(t, "Week_1", "Week 1")
+
+	recorder := web.getHttpResponse("/setup/league")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `value="Week_1"`)
+	assert.Contains(t, recorder.Body.String(), "Untitled Event (current event)")
+
+	recorder = web.postHttpResponse(
+		"/setup/league",
+		"name=Bay+Area+League&eventNames=Week_1&includeActiveEvent=on&countedEvents=3&participationPoints=5&"+
+			"winPoints=3&tiePoints=1&lossPoints=0&rankingPointMultiplier=1&placementPoints=10,+8,+6",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	leagueSettings, _ := league.LoadSettings()
+	assert.Equal(
+		t,
+		&league.Settings{
+			Name:               "Bay Area League",
+			EventNames:         []string{"Week_1"},
+			IncludeActiveEvent: true,
+			CountedEvents:      3,
+			Rules: league.PointRules{
+				ParticipationPoints:    5,
+				WinPoints:              3,
+				TiePoints:              1,
+				RankingPointMultiplier: 1,
+				PlacementPoints:        []int{10, 8, 6},
+			},
+		},
+		leagueSettings,
+	)
+	recorder = web.getHttpResponse("/setup/league")
+	assert.Contains(t, recorder.Body.String(), `value="10, 8, 6"`)
+
+	// Check that invalid settings are rejected.
+	recorder = web.postHttpResponse("/setup/league", "name=&winPoints=2")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The league name cannot be blank.")
+	recorder = web.postHttpResponse("/setup/league", "name=League&placementPoints=10,first")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid placement points 'first'.")
+	leagueSettings, _ = league.LoadSettings()
+	assert.Equal(t, "Bay Area League", leagueSettings.Name)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for viewing the server log and following it live.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// Maximum number of past entries sent to the log viewer when it first connects.
+const logViewerBacklogSize = 500
+
+// Shows the log viewer page.
+func (web *Web) logsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.FtaRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_logs.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Subsystems []string
+		Levels     []string
+		Filter     logging.Filter
+	}{
+		web.arena.EventSettings,
+		logging.Subsystems,
+		[]string{slog.LevelDebug.String(), slog.LevelInfo.String(), slog.LevelWarn.String(), slog.LevelError.String()},
+		parseLogFilter(r),
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the log viewer, which sends the recent log entries matching the filter given in the query
+// string and then each new matching entry as it is logged.
+func (web *Web) logsWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.FtaRole) {
+		return
+	}
+
+	filter := parseLogFilter(r)
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe before taking the backlog so that nothing is missed in between, and skip anything already sent.
+	newEntries, unsubscribe := logging.Subscribe()
+	defer unsubscribe()
+	entries := logging.RecentEntries(filter)
+	if len(entries) > logViewerBacklogSize {
+		entries = slices.Clone(entries[len(entries)-logViewerBacklogSize:])
+	}
+	if err = ws.Write("logEntries", entries); err != nil {
+		return
+	}
+	go func() {
+		for entry := range newEntries {
+			if len(entries) > 0 && !entry.Time.After(entries[len(entries)-1].Time) || !filter.Matches(entry) {
+				continue
+			}
+			if err := ws.Write("logEntry", entry); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Wait until the client closes the connection.
+	for {
+		if _, _, err = ws.Read(); err != nil {
+			return
+		}
+	}
+}
+
+// Returns the log filter given by the query string of the request.
+func parseLogFilter(r *http.Request) logging.Filter {
+	query := r.URL.Query()
+	return logging.Filter{Subsystem: query.Get("subsystem"), MinLevel: query.Get("level"), Search: query.Get("search")}
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupLogs(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/logs?subsystem=network&level=WARN")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Server Log - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "<option value=\"network\" selected>")
+	assert.Contains(t, recorder.Body.String(), "<option value=\"WARN\" selected>")
+}
+
+func TestSetupLogsWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+	logging.Network.Warn("Radio pairing issue.", "station", "R1", "issue", "blorpy")
+	logging.Arena.Warn("Deferring the event settings changes.")
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/setup/logs/websocket?subsystem=network&level=WARN&search=blorpy", nil,
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Check that the backlog only includes the entries matching the filter.
+	entries, ok := readWebsocketType(t, ws, "logEntries").([]any)
+	if assert.True(t, ok) && assert.NotEmpty(t, entries) {
+		for _, entry := range entries {
+			assert.Equal(t, logging.NetworkSubsystem, entry.(map[string]any)["Subsystem"])
+		}
+		assert.Equal(t, "Radio pairing issue.", entries[len(entries)-1].(map[string]any)["Message"])
+	}
+
+	// Check that new matching entries are sent as they are logged.
+	logging.Network.Info("Driver station connected.", "team", "blorpy")
+	logging.Arena.Error("Failed to save arena snapshot.", "error", "blorpy")
+	logging.Network.Error("Failed to configure team WiFi.", "error", "blorpy")
+	entry, ok := readWebsocketType(t, ws, "logEntry").(map[string]any)
+	if assert.True(t, ok) {
+		assert.Equal(t, "Failed to configure team WiFi.", entry["Message"])
+		assert.Equal(t, "ERROR", entry["Level"])
+	}
+}
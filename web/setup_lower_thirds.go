@@ -7,17 +7,20 @@ package web
 
 import (
 	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/Team254/cheesy-arena/websocket"
 	"github.com/mitchellh/mapstructure"
 	"io"
-	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // Shows the lower third configuration page.
 func (web *Web) lowerThirdsGetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole) {
 		return
 	}
 
@@ -31,10 +34,16 @@ func (web *Web) lowerThirdsGetHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	playlists, err := web.getLowerThirdPlaylistViews()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	data := struct {
 		*model.EventSettings
 		LowerThirds []model.LowerThird
-	}{web.arena.EventSettings, lowerThirds}
+		Playlists   []lowerThirdPlaylistView
+	}{web.arena.EventSettings, lowerThirds, playlists}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -42,9 +51,95 @@ func (web *Web) lowerThirdsGetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Creates, edits or deletes a playlist of lower thirds.
+func (web *Web) lowerThirdPlaylistsPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	if r.PostFormValue("action") == "create" {
+		playlist := model.LowerThirdPlaylist{Name: strings.TrimSpace(r.PostFormValue("name")), LowerThirdIds: []int{}}
+		if err := web.arena.Database.CreateLowerThirdPlaylist(&playlist); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		http.Redirect(w, r, "/setup/lower_thirds", 303)
+		return
+	}
+
+	playlistId, _ := strconv.Atoi(r.PostFormValue("id"))
+	playlist, err := web.arena.Database.GetLowerThirdPlaylistById(playlistId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if playlist == nil {
+		handleWebErr(w, fmt.Errorf("Lower third playlist %d does not exist.", playlistId))
+		return
+	}
+
+	// Drop any lower thirds that have since been deleted, so that the positions line up with what the page shows.
+	entries, err := web.arena.Database.GetLowerThirdPlaylistEntries(playlist)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	playlist.LowerThirdIds = []int{}
+	for _, entry := range entries {
+		playlist.LowerThirdIds = append(playlist.LowerThirdIds, entry.Id)
+	}
+
+	position, _ := strconv.Atoi(r.PostFormValue("position"))
+	validPosition := position >= 0 && position < len(playlist.LowerThirdIds)
+	switch r.PostFormValue("action") {
+	case "delete":
+		if err = web.arena.Database.DeleteLowerThirdPlaylist(playlist.Id); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if web.arena.LowerThirdPlaylistId == playlist.Id {
+			_ = web.arena.SelectLowerThirdPlaylist(0)
+		}
+		http.Redirect(w, r, "/setup/lower_thirds", 303)
+		return
+	case "add":
+		lowerThirdId, _ := strconv.Atoi(r.PostFormValue("lowerThirdId"))
+		lowerThird, err := web.arena.Database.GetLowerThirdById(lowerThirdId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if lowerThird == nil {
+			handleWebErr(w, fmt.Errorf("Lower third %d does not exist.", lowerThirdId))
+			return
+		}
+		playlist.LowerThirdIds = append(playlist.LowerThirdIds, lowerThird.Id)
+	case "remove":
+		if validPosition {
+			playlist.LowerThirdIds = slices.Delete(playlist.LowerThirdIds, position, position+1)
+		}
+	case "moveUp":
+		if validPosition && position > 0 {
+			playlist.LowerThirdIds[position-1], playlist.LowerThirdIds[position] =
+				playlist.LowerThirdIds[position], playlist.LowerThirdIds[position-1]
+		}
+	case "moveDown":
+		if validPosition && position < len(playlist.LowerThirdIds)-1 {
+			playlist.LowerThirdIds[position+1], playlist.LowerThirdIds[position] =
+				playlist.LowerThirdIds[position], playlist.LowerThirdIds[position+1]
+		}
+	}
+	if err = web.arena.Database.UpdateLowerThirdPlaylist(playlist); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	http.Redirect(w, r, "/setup/lower_thirds", 303)
+}
+
 // The websocket endpoint for the lower thirds client to send control commands.
 func (web *Web) lowerThirdsWebsocketHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole) {
 		return
 	}
 
@@ -63,7 +158,7 @@ func (web *Web) lowerThirdsWebsocketHandler(w http.ResponseWriter, r *http.Reque
 				// Client has closed the connection; nothing to do here.
 				return
 			}
-			log.Println(err)
+			logging.Web.Warn("Websocket read error.", "error", err)
 			return
 		}
 
@@ -134,7 +229,7 @@ func (web *Web) lowerThirdsWebsocketHandler(w http.ResponseWriter, r *http.Reque
 		// Force a reload of the client to render the updated lower thirds list.
 		err = ws.WriteNotifier(web.arena.ReloadDisplaysNotifier)
 		if err != nil {
-			log.Println(err)
+			logging.Web.Warn("Websocket write error.", "error", err)
 			return
 		}
 	}
@@ -208,3 +303,25 @@ func (web *Web) reorderLowerThird(id int, moveUp bool) error {
 
 	return nil
 }
+
+// A lower third playlist along with the lower thirds that it contains, for rendering.
+type lowerThirdPlaylistView struct {
+	model.LowerThirdPlaylist
+	Entries []model.LowerThird
+}
+
+// Returns all of the lower third playlists along with their entries.
+func (web *Web) getLowerThirdPlaylistViews() ([]lowerThirdPlaylistView, error) {
+	playlists, err := web.arena.Database.GetAllLowerThirdPlaylists()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]lowerThirdPlaylistView, len(playlists))
+	for i, playlist := range playlists {
+		views[i].LowerThirdPlaylist = playlist
+		if views[i].Entries, err = web.arena.Database.GetLowerThirdPlaylistEntries(&playlist); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -60,3 +60,53 @@ func TestSetupLowerThirds(t *testing.T) {
 	assert.Equal(t, 3, lowerThirds[0].Id)
 	assert.Equal(t, 2, lowerThirds[1].Id)
 }
+
+func TestSetupLowerThirdPlaylists(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateLowerThird(&model.LowerThird{0, "Welcome", "", 0, 0})
+	web.arena.Database.CreateLowerThird(&model.LowerThird{0, "Emcee", "Jane Doe", 1, 0})
+	web.arena.Database.CreateLowerThird(&model.LowerThird{0, "Game Announcer", "John Doe", 2, 0})
+
+	recorder := web.postHttpResponse("/setup/lower_thirds/playlists", "action=create&name=")
+	assert.Equal(t, 500, recorder.Code)
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=create&name=Opening Ceremony")
+	assert.Equal(t, 303, recorder.Code)
+	for _, lowerThirdId := range []string{"2", "3", "1"} {
+		recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=add&id=1&lowerThirdId="+lowerThirdId)
+		assert.Equal(t, 303, recorder.Code)
+	}
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=add&id=1&lowerThirdId=254")
+	assert.Equal(t, 500, recorder.Code)
+	playlist, _ := web.arena.Database.GetLowerThirdPlaylistById(1)
+	assert.Equal(t, []int{2, 3, 1}, playlist.LowerThirdIds)
+
+	recorder = web.getHttpResponse("/setup/lower_thirds")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Opening Ceremony")
+	assert.Contains(t, recorder.Body.String(), "Emcee &ndash; Jane Doe")
+
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=moveUp&id=1&position=2")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=moveDown&id=1&position=2")
+	assert.Equal(t, 303, recorder.Code)
+	playlist, _ = web.arena.Database.GetLowerThirdPlaylistById(1)
+	assert.Equal(t, []int{2, 1, 3}, playlist.LowerThirdIds)
+
+	// Check that deleted lower thirds are dropped from the playlist.
+	web.arena.Database.DeleteLowerThird(2)
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=remove&id=1&position=1")
+	assert.Equal(t, 303, recorder.Code)
+	playlist, _ = web.arena.Database.GetLowerThirdPlaylistById(1)
+	assert.Equal(t, []int{1}, playlist.LowerThirdIds)
+
+	web.arena.SelectLowerThirdPlaylist(1)
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=delete&id=1")
+	assert.Equal(t, 303, recorder.Code)
+	playlist, _ = web.arena.Database.GetLowerThirdPlaylistById(1)
+	assert.Nil(t, playlist)
+	assert.Equal(t, 0, web.arena.LowerThirdPlaylistId)
+	recorder = web.postHttpResponse("/setup/lower_thirds/playlists", "action=add&id=1&lowerThirdId=1")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Lower third playlist 1 does not exist.")
+}
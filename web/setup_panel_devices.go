@@ -0,0 +1,119 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for reviewing and revoking the tablets enrolled to run the scoring and referee panels, and for sending an
+// enrolled tablet back to the panel it was last running.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"net/http"
+	"strconv"
+)
+
+// Shows the list of enrolled panel devices.
+func (web *Web) panelDevicesGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderPanelDevices(w, r, "")
+}
+
+// Revokes the given device's enrollment along with the login session it was enrolled with, so that it can't be used
+// again until someone logs back in on it.
+func (web *Web) panelDeviceRevokePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	panelDeviceId, _ := strconv.Atoi(r.PathValue("id"))
+	panelDevice, err := web.arena.Database.GetPanelDeviceById(panelDeviceId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if panelDevice == nil {
+		web.renderPanelDevices(w, r, fmt.Sprintf("No such panel device: %d", panelDeviceId))
+		return
+	}
+	if err = web.arena.Database.DeletePanelDevice(panelDevice.Id); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if panelDevice.SessionToken != "" {
+		// The session may have already been ended by a change to the admin password.
+		session, err := web.arena.Database.GetUserSessionByToken(panelDevice.SessionToken)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if session != nil {
+			if err = web.arena.Database.DeleteUserSession(session.Id); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+	}
+
+	http.Redirect(w, r, "/setup/panel_devices", 303)
+}
+
+// Redirects an enrolled tablet to the panel that it was last running, with the same alliance, position and rules.
+func (web *Web) panelResumeHandler(w http.ResponseWriter, r *http.Request) {
+	panelDevice := web.getPanelDeviceFromCookie(r)
+	if panelDevice == nil || panelDevice.PanelPath == "" {
+		http.Redirect(w, r, "/", 307)
+		return
+	}
+	http.Redirect(w, r, panelDevice.PanelPath, 307)
+}
+
+// Records the given panel page as the one that the device is running, so that it can be resumed later.
+func (web *Web) setPanelDevicePath(panelDevice *model.PanelDevice, r *http.Request) error {
+	if panelDevice == nil || panelDevice.PanelPath == r.URL.RequestURI() {
+		return nil
+	}
+	panelDevice.PanelPath = r.URL.RequestURI()
+	return web.arena.Database.UpdatePanelDevice(panelDevice)
+}
+
+// Returns false if the device behind the given panel websocket has been revoked since it connected, in which case the
+// panel is told to reload so that it ends up back at the login page.
+func (web *Web) panelDeviceIsActive(panelDevice *model.PanelDevice, ws *websocket.Websocket) bool {
+	if panelDevice == nil {
+		return true
+	}
+	if currentPanelDevice, _ := web.arena.Database.GetPanelDeviceById(panelDevice.Id); currentPanelDevice != nil {
+		return true
+	}
+	ws.Write("reload", nil)
+	return false
+}
+
+func (web *Web) renderPanelDevices(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_panel_devices.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	panelDevices, err := web.arena.Database.GetAllPanelDevices()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		PanelDevices  []model.PanelDevice
+		UserRoleNames map[string]string
+		ErrorMessage  string
+	}{web.arena.EventSettings, panelDevices, model.UserRoleNames, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
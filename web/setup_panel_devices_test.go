@@ -0,0 +1,171 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPanelDeviceEnrollment(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.AdminPassword = "admin"
+	referee := model.UserAccount{Username: "Bertha", Role: model.RefereeRole}
+	referee.SetPassword("whistle")
+	assert.Nil(t, web.arena.Database.CreateUserAccount(&referee))
+
+	// Check that opening a panel after logging in enrolls the tablet under the user's role.
+	recorder := web.postHttpResponse("/login", "username=Bertha&password=whistle")
+	sessionCookie := getCookieHeader(recorder, sessionTokenCookie)
+	recorder = web.getHttpResponseWithHeaders("/panels/scoring/red", map[string]string{"Cookie": sessionCookie})
+	assert.Equal(t, 200, recorder.Code)
+	deviceCookie := getCookieHeader(recorder, panelDeviceCookie)
+	assert.NotEmpty(t, deviceCookie)
+	panelDevices, _ := web.arena.Database.GetAllPanelDevices()
+	if assert.Equal(t, 1, len(panelDevices)) {
+		assert.Equal(t, "Bertha", panelDevices[0].Username)
+		assert.Equal(t, model.RefereeRole, panelDevices[0].Role)
+		assert.Equal(t, "/panels/scoring/red", panelDevices[0].PanelPath)
+		assert.Equal(t, sessionCookie, sessionTokenCookie+"="+panelDevices[0].SessionToken)
+	}
+
+	// Check that the tablet keeps working after its login session has gone, and remembers which panel it last ran.
+	assert.Nil(t, web.arena.Database.TruncateUserSessions())
+	deviceHeaders := map[string]string{"Cookie": deviceCookie}
+	recorder = web.getHttpResponseWithHeaders("/panels/referee?position=head&rules=all", deviceHeaders)
+	assert.Equal(t, 200, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/match_play", deviceHeaders)
+	assert.Equal(t, 307, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/panels/resume", deviceHeaders)
+	assert.Equal(t, 307, recorder.Code)
+	assert.Equal(t, "/panels/referee?position=head&rules=all", recorder.Header().Get("Location"))
+	recorder = web.getHttpResponse("/panels/resume")
+	assert.Equal(t, 307, recorder.Code)
+	assert.Equal(t, "/", recorder.Header().Get("Location"))
+
+	// Check that the admin can see and revoke the tablet.
+	recorder = web.postHttpResponse("/login", "username=admin&password=admin")
+	adminHeaders := map[string]string{"Cookie": getCookieHeader(recorder, sessionTokenCookie)}
+	recorder = web.getHttpResponseWithHeaders("/setup/panel_devices", adminHeaders)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "/panels/referee?position=head&rules=all")
+	assert.Contains(t, recorder.Body.String(), "Bertha")
+	recorder = web.postHttpResponseWithHeaders(
+		fmt.Sprintf("/setup/panel_devices/%d/revoke", panelDevices[0].Id), "", adminHeaders,
+	)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponseWithHeaders("/panels/scoring/red", deviceHeaders)
+	assert.Equal(t, 307, recorder.Code)
+	recorder = web.postHttpResponseWithHeaders("/setup/panel_devices/1114/revoke", "", adminHeaders)
+	assert.Contains(t, recorder.Body.String(), "No such panel device: 1114")
+
+	// Check that revoking a tablet also ends the login session it was enrolled with.
+	recorder = web.postHttpResponse("/login", "username=Bertha&password=whistle")
+	sessionCookie = getCookieHeader(recorder, sessionTokenCookie)
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", map[string]string{"Cookie": sessionCookie})
+	assert.Equal(t, 200, recorder.Code)
+	panelDevices, _ = web.arena.Database.GetAllPanelDevices()
+	if assert.Equal(t, 1, len(panelDevices)) {
+		web.postHttpResponseWithHeaders(
+			fmt.Sprintf("/setup/panel_devices/%d/revoke", panelDevices[0].Id), "", adminHeaders,
+		)
+	}
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", map[string]string{"Cookie": sessionCookie})
+	assert.Equal(t, 307, recorder.Code)
+
+	// Check that deleting a user revokes the tablets they enrolled.
+	recorder = web.postHttpResponse("/login", "username=Bertha&password=whistle")
+	sessionCookie = getCookieHeader(recorder, sessionTokenCookie)
+	web.getHttpResponseWithHeaders("/panels/referee", map[string]string{"Cookie": sessionCookie})
+	panelDevices, _ = web.arena.Database.GetAllPanelDevices()
+	assert.Equal(t, 1, len(panelDevices))
+	recorder = web.postHttpResponseWithHeaders(
+		"/setup/users", fmt.Sprintf("action=delete&id=%d", referee.Id), adminHeaders,
+	)
+	assert.Equal(t, 303, recorder.Code)
+	panelDevices, _ = web.arena.Database.GetAllPanelDevices()
+	assert.Empty(t, panelDevices)
+}
+
+func TestPanelDeviceEnrollmentWithoutAuth(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Check that a tablet isn't enrolled while auth is disabled.
+	recorder := web.getHttpResponse("/panels/scoring/red")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Empty(t, getCookieHeader(recorder, panelDeviceCookie))
+	panelDevices, _ := web.arena.Database.GetAllPanelDevices()
+	assert.Empty(t, panelDevices)
+
+	// Check that a tablet enrolled without a login doesn't keep access once a password is set.
+	legacyDevice := model.PanelDevice{Token: "abcd", Role: model.RefereeRole}
+	assert.Nil(t, web.arena.Database.CreatePanelDevice(&legacyDevice))
+	deviceHeaders := map[string]string{"Cookie": panelDeviceCookie + "=abcd"}
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", deviceHeaders)
+	assert.Equal(t, 200, recorder.Code)
+	web.arena.EventSettings.AdminPassword = "admin"
+	recorder = web.getHttpResponseWithHeaders("/panels/referee", deviceHeaders)
+	assert.Equal(t, 307, recorder.Code)
+}
+
+func TestPanelDeviceWebsocketResume(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.AdminPassword = "admin"
+	referee := model.UserAccount{Username: "Bertha", Role: model.RefereeRole}
+	referee.SetPassword("whistle")
+	assert.Nil(t, web.arena.Database.CreateUserAccount(&referee))
+
+	recorder := web.postHttpResponse("/login", "username=Bertha&password=whistle")
+	sessionCookie := getCookieHeader(recorder, sessionTokenCookie)
+	recorder = web.getHttpResponseWithHeaders("/panels/scoring/red", map[string]string{"Cookie": sessionCookie})
+	assert.Equal(t, 200, recorder.Code)
+	headers := http.Header{}
+	headers.Set("Cookie", getCookieHeader(recorder, panelDeviceCookie))
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/scoring/red/websocket", headers)
+	assert.Nil(t, err)
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 4)
+	web.arena.MatchState = field.PostMatch
+	ws.Write("commitMatch", nil)
+	time.Sleep(time.Millisecond * 10) // Allow some time for the command to be processed.
+	assert.Equal(t, 1, web.arena.ScoringPanelRegistry.GetNumScoreCommitted("red"))
+	conn.Close()
+	time.Sleep(time.Millisecond * 10)
+	assert.Equal(t, 0, web.arena.ScoringPanelRegistry.GetNumPanels("red"))
+
+	// Check that the panel's committed score is restored when it reconnects during the same match.
+	conn, _, err = gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/scoring/red/websocket", headers)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws = websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 4)
+	assert.Equal(t, 1, web.arena.ScoringPanelRegistry.GetNumScoreCommitted("red"))
+
+	// Check that a revoked panel is told to reload instead of having its commands processed.
+	panelDevices, _ := web.arena.Database.GetAllPanelDevices()
+	assert.Nil(t, web.arena.Database.DeletePanelDevice(panelDevices[0].Id))
+	ws.Write("commitMatch", nil)
+	readWebsocketType(t, ws, "reload")
+}
+
+// Returns the given cookie from the response in the form of a request Cookie header.
+func getCookieHeader(recorder *httptest.ResponseRecorder, name string) string {
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == name {
+			return fmt.Sprintf("%s=%s", cookie.Name, cookie.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for recording each team's progress through the pit checks that must be done before it can play.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"strconv"
+)
+
+// Shows the pit checks and card status of every team.
+func (web *Web) pitGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.FtaRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_pit.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamStatuses, err := web.arena.Database.GetTeamStatusesByTeamId()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Teams        []model.Team
+		TeamStatuses map[int]model.TeamStatus
+	}{web.arena.EventSettings, teams, teamStatuses}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Records the pit checks and robot radio MAC address for the given team.
+func (web *Web) pitTeamPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.FtaRole) {
+		return
+	}
+
+	teamId, _ := strconv.Atoi(r.PathValue("id"))
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if team == nil {
+		http.Error(w, fmt.Sprintf("Error: No such team: %d", teamId), 400)
+		return
+	}
+
+	teamStatus, err := web.arena.Database.GetTeamStatus(team.Id)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	oldTeamStatus := *teamStatus
+	teamStatus.InspectionPassed = r.PostFormValue("inspectionPassed") == "on"
+	teamStatus.Weighed = r.PostFormValue("weighed") == "on"
+	teamStatus.HasConnected = r.PostFormValue("hasConnected") == "on"
+	if teamStatus.RadioMacAddress, err = model.NormalizeRadioMacAddress(r.PostFormValue("radioMacAddress")); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	recordAuditChanges(r, oldTeamStatus, *teamStatus)
+	if err = web.arena.Database.SaveTeamStatus(teamStatus); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Re-check the on-deck and current matches in case this team is in them.
+	web.arena.UpdateOnDeckMatch()
+	web.arena.UpdateExpectedRadioMacAddress(team.Id, teamStatus.RadioMacAddress)
+	http.Redirect(w, r, "/setup/pit", 303)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupPit(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"})
+	web.arena.Database.SaveTeamStatus(&model.TeamStatus{TeamId: 1114, YellowCard: true})
+
+	recorder := web.getHttpResponse("/setup/pit")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The Cheesy Poofs")
+	assert.Contains(t, recorder.Body.String(), "action=\"/setup/pit/1114\"")
+	assert.Contains(t, recorder.Body.String(), ">Yellow<")
+
+	recorder = web.postHttpResponse("/setup/pit/254", "inspectionPassed=on&weighed=on")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "/setup/pit", recorder.Header().Get("Location"))
+	teamStatus, _ := web.arena.Database.GetTeamStatus(254)
+	assert.True(t, teamStatus.InspectionPassed)
+	assert.True(t, teamStatus.Weighed)
+	assert.False(t, teamStatus.HasConnected)
+
+	// Check that unticking a check clears it without touching the card.
+	recorder = web.postHttpResponse("/setup/pit/1114", "hasConnected=on")
+	assert.Equal(t, 303, recorder.Code)
+	teamStatus, _ = web.arena.Database.GetTeamStatus(1114)
+	assert.True(t, teamStatus.HasConnected)
+	assert.True(t, teamStatus.YellowCard)
+	recorder = web.postHttpResponse("/setup/pit/254", "weighed=on")
+	teamStatus, _ = web.arena.Database.GetTeamStatus(254)
+	assert.False(t, teamStatus.InspectionPassed)
+	assert.True(t, teamStatus.Weighed)
+
+	recorder = web.postHttpResponse("/setup/pit/33", "weighed=on")
+	assert.Equal(t, 400, recorder.Code)
+}
+
+func TestSetupPitRadioMacAddress(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"})
+	assert.Nil(t, web.arena.SubstituteTeams(254, 0, 0, 0, 0, 0))
+
+	recorder := web.postHttpResponse("/setup/pit/254", "weighed=on&radioMacAddress=00-80-2F-AB-CD-01")
+	assert.Equal(t, 303, recorder.Code)
+	teamStatus, _ := web.arena.Database.GetTeamStatus(254)
+	assert.Equal(t, "00:80:2f:ab:cd:01", teamStatus.RadioMacAddress)
+	assert.True(t, teamStatus.Weighed)
+	assert.Equal(t, "00:80:2f:ab:cd:01", web.arena.AllianceStations["R1"].ExpectedRadioMacAddress)
+	recorder = web.getHttpResponse("/setup/pit")
+	assert.Contains(t, recorder.Body.String(), "value=\"00:80:2f:ab:cd:01\"")
+
+	recorder = web.postHttpResponse("/setup/pit/1114", "radioMacAddress=blorpy")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid radio MAC address 'blorpy'.")
+	teamStatus, _ = web.arena.Database.GetTeamStatus(1114)
+	assert.Equal(t, "", teamStatus.RadioMacAddress)
+
+	// Check that clearing the address stops the check for the team.
+	recorder = web.postHttpResponse("/setup/pit/254", "weighed=on")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "", web.arena.AllianceStations["R1"].ExpectedRadioMacAddress)
+}
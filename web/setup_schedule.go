@@ -47,7 +47,7 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	scheduleBlocks, err := getScheduleBlocks(r)
+	scheduleBlocks, err := getScheduleBlocks(r, web.arena.EventSettings.Location())
 	// Save blocks even if there is an error, so that any good ones are not discarded.
 	deleteBlocksErr := web.arena.Database.DeleteScheduleBlocksByMatchType(matchType)
 	if deleteBlocksErr != nil {
@@ -78,13 +78,14 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 			"generating the schedule.")
 		return
 	}
-	if len(teams) < 6 {
-		web.renderSchedule(w, r, fmt.Sprintf("There are only %d teams. There must be at least 6 teams to generate "+
-			"a schedule.", len(teams)))
+	teamsPerAlliance := web.arena.EventSettings.TeamsPerAlliance
+	if len(teams) < 2*teamsPerAlliance {
+		web.renderSchedule(w, r, fmt.Sprintf("There are only %d teams. There must be at least %d teams to generate "+
+			"a schedule.", len(teams), 2*teamsPerAlliance))
 		return
 	}
 
-	matches, err := tournament.BuildRandomSchedule(teams, scheduleBlocks, matchType)
+	matches, err := tournament.BuildRandomSchedule(teams, scheduleBlocks, matchType, teamsPerAlliance)
 	if err != nil {
 		web.renderSchedule(w, r, fmt.Sprintf("Error generating schedule: %s.", err.Error()))
 		return
@@ -144,6 +145,9 @@ func (web *Web) scheduleSavePostHandler(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	cachedProjections = nil
+	web.arena.NotifyScheduleChanged(matchType)
+
 	// Back up the database.
 	err = web.arena.Database.Backup(web.arena.EventSettings.Name, "post_scheduling")
 	if err != nil {
@@ -195,15 +199,15 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 	}
 }
 
-// Converts the post form variables into a slice of schedule blocks.
-func getScheduleBlocks(r *http.Request) ([]model.ScheduleBlock, error) {
+// Converts the post form variables into a slice of schedule blocks, taking the start times to be in the given time
+// zone.
+func getScheduleBlocks(r *http.Request, location *time.Location) ([]model.ScheduleBlock, error) {
 	numScheduleBlocks, err := strconv.Atoi(r.PostFormValue("numScheduleBlocks"))
 	if err != nil {
 		return []model.ScheduleBlock{}, err
 	}
 	var returnErr error
 	scheduleBlocks := make([]model.ScheduleBlock, numScheduleBlocks)
-	location, _ := time.LoadLocation("Local")
 	for i := 0; i < numScheduleBlocks; i++ {
 		scheduleBlocks[i].StartTime, err = time.ParseInLocation("2006-01-02 03:04:05 PM",
 			r.PostFormValue(fmt.Sprintf("startTime%d", i)), location)
@@ -0,0 +1,74 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for registering the scouting apps allowed to use the scouting API.
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+// Shows the scouting app registration page.
+func (web *Web) scoutingAppsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_scouting.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	scoutingApps, err := web.arena.Database.GetAllScoutingApps()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	observations, err := web.arena.Database.GetAllScoutingObservations()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	numObservations := make(map[int]int)
+	for _, observation := range observations {
+		numObservations[observation.ScoutingAppId]++
+	}
+
+	data := struct {
+		*model.EventSettings
+		ScoutingApps    []model.ScoutingApp
+		NumObservations map[int]int
+	}{web.arena.EventSettings, scoutingApps, numObservations}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Registers a new scouting app or revokes an existing one.
+func (web *Web) scoutingAppsPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if r.PostFormValue("action") == "delete" {
+		scoutingAppId, _ := strconv.Atoi(r.PostFormValue("id"))
+		if err := web.arena.Database.DeleteScoutingApp(scoutingAppId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	} else if name := r.PostFormValue("name"); name != "" {
+		scoutingApp := model.ScoutingApp{Name: name, ApiKey: uuid.New().String()}
+		if err := web.arena.Database.CreateScoutingApp(&scoutingApp); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/setup/scouting", 303)
+}
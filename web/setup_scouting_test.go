@@ -0,0 +1,34 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupScoutingApps(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/scouting")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Scouting Apps")
+
+	recorder = web.postHttpResponse("/setup/scouting", "action=create&name=Pit Tablets")
+	assert.Equal(t, 303, recorder.Code)
+	scoutingApps, _ := web.arena.Database.GetAllScoutingApps()
+	if assert.Equal(t, 1, len(scoutingApps)) {
+		assert.Equal(t, "Pit Tablets", scoutingApps[0].Name)
+		assert.NotEmpty(t, scoutingApps[0].ApiKey)
+		recorder = web.getHttpResponse("/setup/scouting")
+		assert.Contains(t, recorder.Body.String(), "Pit Tablets")
+		assert.Contains(t, recorder.Body.String(), scoutingApps[0].ApiKey)
+	}
+
+	recorder = web.postHttpResponse("/setup/scouting", fmt.Sprintf("action=delete&id=%d", scoutingApps[0].Id))
+	assert.Equal(t, 303, recorder.Code)
+	scoutingApps, _ = web.arena.Database.GetAllScoutingApps()
+	assert.Empty(t, scoutingApps)
+}
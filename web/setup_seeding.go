@@ -0,0 +1,168 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for seeding the rankings or playoff alliances from external event data.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/tournament"
+	"net/http"
+	"strings"
+)
+
+// Shows the external seeding page.
+func (web *Web) seedingGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderSeeding(w, r, "")
+}
+
+// Replaces the qualification rankings with those from a TBA event or an uploaded CSV file.
+func (web *Web) seedingRankingsPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if len(web.arena.AllianceSelectionAlliances) > 0 || !web.canModifyAllianceSelection() {
+		web.renderSeeding(w, r, "Cannot seed rankings once alliance selection has started.")
+		return
+	}
+
+	rows, err := web.getSeedingRows(r, false)
+	if err != nil {
+		web.renderSeeding(w, r, err.Error())
+		return
+	}
+	var teamIds []int
+	for _, row := range rows {
+		// Use the last column so that files with a leading rank column are also accepted.
+		teamIds = append(teamIds, row[len(row)-1])
+	}
+	if _, err = tournament.SeedRankings(web.arena.Database, teamIds); err != nil {
+		web.renderSeeding(w, r, fmt.Sprintf("Failed to seed rankings: %s.", err.Error()))
+		return
+	}
+
+	cachedProjections = nil
+	web.arena.ScorePostedNotifier.Notify()
+	http.Redirect(w, r, "/alliance_selection", 303)
+}
+
+// Fills in the alliance selection with the alliances from a TBA event or an uploaded CSV file, leaving them to be
+// reviewed and finalized through the usual alliance selection process.
+func (web *Web) seedingAlliancesPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if !web.canModifyAllianceSelection() {
+		web.renderSeeding(w, r, "Alliance selection has already been finalized.")
+		return
+	}
+
+	rows, err := web.getSeedingRows(r, true)
+	if err != nil {
+		web.renderSeeding(w, r, err.Error())
+		return
+	}
+	teamsPerAlliance := 3
+	if web.arena.EventSettings.SelectionRound3Order != "" {
+		teamsPerAlliance = 4
+	}
+	alliances, err := tournament.BuildSeededAlliances(
+		web.arena.Database, rows, web.arena.EventSettings.NumPlayoffAlliances, teamsPerAlliance,
+	)
+	if err != nil {
+		web.renderSeeding(w, r, fmt.Sprintf("Failed to seed alliances: %s.", err.Error()))
+		return
+	}
+
+	// Make every seeded team eligible for selection, keeping any existing rankings ahead of the rest.
+	rankings, err := web.arena.Database.GetAllRankings()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var rankedTeams []model.AllianceSelectionRankedTeam
+	rankedTeamIndices := make(map[int]int)
+	addRankedTeam := func(teamId int) {
+		if _, ok := rankedTeamIndices[teamId]; !ok {
+			rankedTeamIndices[teamId] = len(rankedTeams)
+			rankedTeams = append(
+				rankedTeams, model.AllianceSelectionRankedTeam{Rank: len(rankedTeams) + 1, TeamId: teamId},
+			)
+		}
+	}
+	for _, ranking := range rankings {
+		addRankedTeam(ranking.TeamId)
+	}
+	for _, alliance := range alliances {
+		for _, teamId := range alliance.TeamIds {
+			if teamId > 0 {
+				addRankedTeam(teamId)
+				rankedTeams[rankedTeamIndices[teamId]].Picked = true
+			}
+		}
+	}
+
+	web.arena.AllianceSelectionAlliances = alliances
+	web.arena.AllianceSelectionRankedTeams = rankedTeams
+	web.arena.AllianceSelectionNotifier.Notify()
+	http.Redirect(w, r, "/alliance_selection", 303)
+}
+
+// Returns the rows of team numbers from whichever seeding source was specified in the request.
+func (web *Web) getSeedingRows(r *http.Request, isAlliances bool) ([][]int, error) {
+	var rows [][]int
+	if eventKey := strings.TrimSpace(r.PostFormValue("tbaEventKey")); eventKey != "" {
+		var err error
+		if isAlliances {
+			if rows, err = web.arena.TbaClient.GetEventAlliances(eventKey); err != nil {
+				return nil, fmt.Errorf("Failed to get alliances from TBA: %s.", err.Error())
+			}
+		} else {
+			var teamIds []int
+			if teamIds, err = web.arena.TbaClient.GetEventRankings(eventKey); err != nil {
+				return nil, fmt.Errorf("Failed to get rankings from TBA: %s.", err.Error())
+			}
+			for _, teamId := range teamIds {
+				rows = append(rows, []int{teamId})
+			}
+		}
+	} else {
+		file, _, err := r.FormFile("csvFile")
+		if err != nil {
+			return nil, fmt.Errorf("Either a TBA event key or a CSV file must be specified.")
+		}
+		defer file.Close()
+		if rows, err = tournament.ParseSeedingCsv(file); err != nil {
+			return nil, fmt.Errorf("Failed to parse CSV file: %s.", err.Error())
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("No teams were found in the seeding data.")
+	}
+	return rows, nil
+}
+
+func (web *Web) renderSeeding(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_seeding.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		ErrorMessage string
+	}{web.arena.EventSettings, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
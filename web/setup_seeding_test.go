@@ -0,0 +1,80 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"bytes"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupSeeding(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/seeding")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Seed Rankings")
+	assert.Contains(t, recorder.Body.String(), "Seed Playoff Alliances")
+
+	recorder = web.postHttpResponse("/setup/seeding/rankings", "")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Either a TBA event key or a CSV file must be specified.")
+}
+
+func TestSetupSeedingRankings(t *testing.T) {
+	web := setupTestWeb(t)
+	for _, teamId := range []int{254, 1114, 2056} {
+		web.arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+
+	recorder := web.postFileHttpResponse(
+		"/setup/seeding/rankings", "csvFile", bytes.NewBufferString("Rank,Team\n1,2056\n2,254\n3,1114\n"),
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	rankings, _ := web.arena.Database.GetAllRankings()
+	if assert.Equal(t, 3, len(rankings)) {
+		assert.Equal(t, 2056, rankings[0].TeamId)
+		assert.Equal(t, 254, rankings[1].TeamId)
+		assert.Equal(t, 1114, rankings[2].TeamId)
+	}
+
+	recorder = web.postFileHttpResponse("/setup/seeding/rankings", "csvFile", bytes.NewBufferString("254\n1503\n"))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Failed to seed rankings: Team 1503 is not registered for this event.")
+}
+
+func TestSetupSeedingAlliances(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.PlayoffType = model.SingleEliminationPlayoff
+	web.arena.EventSettings.NumPlayoffAlliances = 2
+	for _, teamId := range []int{254, 1114, 2056, 1503, 148, 971} {
+		web.arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+
+	recorder := web.postFileHttpResponse(
+		"/setup/seeding/alliances", "csvFile", bytes.NewBufferString("254,1114,2056\n1503,148\n"),
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	if assert.Equal(t, 2, len(web.arena.AllianceSelectionAlliances)) {
+		assert.Equal(t, []int{254, 1114, 2056}, web.arena.AllianceSelectionAlliances[0].TeamIds)
+		assert.Equal(t, []int{1503, 148, 0}, web.arena.AllianceSelectionAlliances[1].TeamIds)
+	}
+	assert.Equal(t, 5, len(web.arena.AllianceSelectionRankedTeams))
+	for _, rankedTeam := range web.arena.AllianceSelectionRankedTeams {
+		assert.True(t, rankedTeam.Picked)
+	}
+
+	// Check that teams absent from both the rankings and the seeded alliances remain ineligible for selection.
+	recorder = web.postHttpResponse(
+		"/alliance_selection",
+		"selection0_0=254&selection0_1=1114&selection0_2=2056&selection1_0=1503&selection1_1=148&selection1_2=971",
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 971 has not played any matches")
+
+	recorder = web.postFileHttpResponse("/setup/seeding/alliances", "csvFile", bytes.NewBufferString("254,1114\n"))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Expected 2 alliances to match the event settings but got 1")
+}
@@ -15,7 +15,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/Team254/cheesy-arena/scoreboard"
+	"github.com/Team254/cheesy-arena/tournament"
 )
 
 // Shows the event settings editing page.
@@ -33,15 +39,40 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventSettings := web.arena.EventSettings
+	eventSettings := web.arena.SavedEventSettings()
 
 	previousEventName := eventSettings.Name
 	eventSettings.Name = r.PostFormValue("name")
 	if len(eventSettings.Name) < 1 && eventSettings.Name != previousEventName {
 		eventSettings.Name = previousEventName
 	}
+	if _, err := model.LoadTimeZone(r.PostFormValue("timeZone")); err != nil {
+		web.renderSettings(w, r, err.Error())
+		return
+	}
+	eventSettings.TimeZone = r.PostFormValue("timeZone")
 	previousAdminPassword := eventSettings.AdminPassword
 
+	if teamsPerAllianceValue := r.PostFormValue("teamsPerAlliance"); teamsPerAllianceValue != "" {
+		teamsPerAlliance, _ := strconv.Atoi(teamsPerAllianceValue)
+		if teamsPerAlliance < 1 || teamsPerAlliance > tournament.MaxTeamsPerAlliance {
+			web.renderSettings(
+				w, r, fmt.Sprintf("Teams per alliance must be between 1 and %d.", tournament.MaxTeamsPerAlliance),
+			)
+			return
+		}
+		eventSettings.TeamsPerAlliance = teamsPerAlliance
+	}
+
+	previousGameKey := eventSettings.GameKey
+	if gameKey := r.PostFormValue("gameKey"); gameKey != "" {
+		if _, err := game.GetGameDefinition(gameKey); err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+		eventSettings.GameKey = gameKey
+	}
+
 	var playoffType model.PlayoffType
 	numAlliances := 0
 	if r.PostFormValue("playoffType") == "SingleEliminationPlayoff" {
@@ -74,12 +105,118 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.SelectionRound2Order = r.PostFormValue("selectionRound2Order")
 	eventSettings.SelectionRound3Order = r.PostFormValue("selectionRound3Order")
 	eventSettings.SelectionShowUnpickedTeams = r.PostFormValue("selectionShowUnpickedTeams") == "on"
+	eventSettings.PlayoffTimeoutsPerAlliance, _ = strconv.Atoi(r.PostFormValue("playoffTimeoutsPerAlliance"))
+	if playoffTimeoutDuration := r.PostFormValue("playoffTimeoutDurationSec"); playoffTimeoutDuration != "" {
+		durationSec, _ := strconv.Atoi(playoffTimeoutDuration)
+		if durationSec <= 0 {
+			web.renderSettings(w, r, "Playoff timeout duration must be positive.")
+			return
+		}
+		eventSettings.PlayoffTimeoutDurationSec = durationSec
+	}
+	if pitDisplayPageDuration := r.PostFormValue("pitDisplayPageDurationSec"); pitDisplayPageDuration != "" {
+		durationSec, _ := strconv.Atoi(pitDisplayPageDuration)
+		if durationSec <= 0 {
+			web.renderSettings(w, r, "Pit display page duration must be positive.")
+			return
+		}
+		var pages []string
+		for _, page := range r.PostForm["pitDisplayPages"] {
+			if _, ok := model.PitDisplayPageNames[page]; ok {
+				pages = append(pages, page)
+			}
+		}
+		if len(pages) == 0 {
+			web.renderSettings(w, r, "At least one pit display page must be enabled.")
+			return
+		}
+		eventSettings.PitDisplayPages = pages
+		eventSettings.PitDisplayPageDurationSec = durationSec
+	}
+	eventSettings.PitDisplayAnnouncement = r.PostFormValue("pitDisplayAnnouncement")
+	if queueLeadTime := r.PostFormValue("queueLeadTimeMin"); queueLeadTime != "" {
+		leadTimeMin, _ := strconv.Atoi(queueLeadTime)
+		if leadTimeMin <= 0 {
+			web.renderSettings(w, r, "Queueing lead time must be positive.")
+			return
+		}
+		eventSettings.QueueLeadTimeMin = leadTimeMin
+	}
+	if displayAlertThreshold := r.PostFormValue("displayAlertThresholdSec"); displayAlertThreshold != "" {
+		displayAlertThresholdSec, _ := strconv.Atoi(displayAlertThreshold)
+		if displayAlertThresholdSec <= 0 {
+			web.renderSettings(w, r, "Display alert threshold must be positive.")
+			return
+		}
+		eventSettings.DisplayAlertThresholdSec = displayAlertThresholdSec
+	}
+	if autoBackupInterval := r.PostFormValue("autoBackupIntervalMin"); autoBackupInterval != "" {
+		autoBackupIntervalMin, _ := strconv.Atoi(autoBackupInterval)
+		if autoBackupIntervalMin < 0 {
+			web.renderSettings(w, r, "Automatic backup interval must not be negative.")
+			return
+		}
+		eventSettings.AutoBackupIntervalMin = autoBackupIntervalMin
+	}
+	if autoBackupRetention := r.PostFormValue("autoBackupRetentionCount"); autoBackupRetention != "" {
+		autoBackupRetentionCount, _ := strconv.Atoi(autoBackupRetention)
+		if autoBackupRetentionCount <= 0 {
+			web.renderSettings(w, r, "Number of automatic backups to keep must be positive.")
+			return
+		}
+		eventSettings.AutoBackupRetentionCount = autoBackupRetentionCount
+	}
+	if themeAccentColor := r.PostFormValue("themeAccentColor"); themeAccentColor != "" {
+		if !themeColorRe.MatchString(themeAccentColor) {
+			web.renderSettings(w, r, "Theme accent color must be of the form #rrggbb.")
+			return
+		}
+		eventSettings.ThemeAccentColor = strings.ToLower(themeAccentColor)
+	}
+	themeAssetFiles := []struct {
+		asset    string
+		field    *string
+		formName string
+	}{
+		{"background", &eventSettings.ThemeBackgroundFile, "themeBackgroundFile"},
+		{"font", &eventSettings.ThemeFontFile, "themeFontFile"},
+		{"logo", &eventSettings.ThemeLogoFile, "themeLogoFile"},
+	}
+	for _, themeAssetFile := range themeAssetFiles {
+		fileName := strings.TrimSpace(r.PostFormValue(themeAssetFile.formName))
+		if fileName != "" && !isValidThemeAssetFile(themeAssetFile.asset, fileName) {
+			web.renderSettings(w, r, fmt.Sprintf("'%s' is not a valid theme %s file.", fileName, themeAssetFile.asset))
+			return
+		}
+		*themeAssetFile.field = fileName
+	}
 	eventSettings.TbaDownloadEnabled = r.PostFormValue("tbaDownloadEnabled") == "on"
 	eventSettings.TbaPublishingEnabled = r.PostFormValue("tbaPublishingEnabled") == "on"
 	eventSettings.TbaEventCode = r.PostFormValue("tbaEventCode")
 	eventSettings.TbaSecretId = r.PostFormValue("tbaSecretId")
 	eventSettings.TbaSecret = r.PostFormValue("tbaSecret")
 	eventSettings.NexusEnabled = r.PostFormValue("nexusEnabled") == "on"
+	eventSettings.FirstApiUsername = strings.TrimSpace(r.PostFormValue("firstApiUsername"))
+	eventSettings.FirstApiKey = strings.TrimSpace(r.PostFormValue("firstApiKey"))
+	eventSettings.FirstEventCode = strings.ToUpper(strings.TrimSpace(r.PostFormValue("firstEventCode")))
+	eventSettings.WebPushContactEmail = strings.TrimSpace(r.PostFormValue("webPushContactEmail"))
+	eventSettings.ReplayEnabled = r.PostFormValue("replayEnabled") == "on"
+	eventSettings.ReplayAddress = strings.TrimSpace(r.PostFormValue("replayAddress"))
+	eventSettings.ReplayBannerEnabled = r.PostFormValue("replayBannerEnabled") == "on"
+	if eventSettings.ReplayEnabled {
+		if err := partner.ValidateReplayAddress(eventSettings.ReplayAddress); err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+	}
+	if replayClipDuration := r.PostFormValue("replayClipDurationSec"); replayClipDuration != "" {
+		replayClipDurationSec, _ := strconv.Atoi(replayClipDuration)
+		if replayClipDurationSec <= 0 {
+			web.renderSettings(w, r, "Replay clip duration must be positive.")
+			return
+		}
+		eventSettings.ReplayClipDurationSec = replayClipDurationSec
+	}
 	eventSettings.NetworkSecurityEnabled = r.PostFormValue("networkSecurityEnabled") == "on"
 	eventSettings.ApAddress = r.PostFormValue("apAddress")
 	eventSettings.ApPassword = r.PostFormValue("apPassword")
@@ -87,6 +224,9 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.SwitchAddress = r.PostFormValue("switchAddress")
 	eventSettings.SwitchPassword = r.PostFormValue("switchPassword")
 	eventSettings.PlcAddress = r.PostFormValue("plcAddress")
+	eventSettings.ScoreReviewEnabled = r.PostFormValue("scoreReviewEnabled") == "on"
+	eventSettings.StaffReadyCheckEnabled = r.PostFormValue("staffReadyCheckEnabled") == "on"
+	eventSettings.PracticeSandboxEnabled = r.PostFormValue("practiceSandboxEnabled") == "on"
 	eventSettings.AdminPassword = r.PostFormValue("adminPassword")
 	eventSettings.TeamSignRed1Address = r.PostFormValue("teamSignRed1Address")
 	eventSettings.TeamSignRed2Address = r.PostFormValue("teamSignRed2Address")
@@ -96,16 +236,78 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.TeamSignBlue2Address = r.PostFormValue("teamSignBlue2Address")
 	eventSettings.TeamSignBlue3Address = r.PostFormValue("teamSignBlue3Address")
 	eventSettings.TeamSignBlueTimerAddress = r.PostFormValue("teamSignBlueTimerAddress")
+	eventSettings.LightingAddress = r.PostFormValue("lightingAddress")
+	if lightingUniverse := r.PostFormValue("lightingUniverse"); lightingUniverse != "" {
+		eventSettings.LightingUniverse, _ = strconv.Atoi(lightingUniverse)
+		if eventSettings.LightingUniverse < 1 || eventSettings.LightingUniverse > 63999 {
+			web.renderSettings(w, r, "Lighting universe must be between 1 and 63999.")
+			return
+		}
+	}
+	eventSettings.ScoreboardAddress = strings.TrimSpace(r.PostFormValue("scoreboardAddress"))
+	if scoreboardFormat := r.PostFormValue("scoreboardFormat"); scoreboardFormat != "" {
+		eventSettings.ScoreboardFormat = scoreboardFormat
+	}
+	eventSettings.ScoreboardTemplate = r.PostFormValue("scoreboardTemplate")
+	if eventSettings.ScoreboardAddress != "" {
+		if err := scoreboard.ValidateAddress(eventSettings.ScoreboardAddress); err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+		err := scoreboard.ValidateFormat(eventSettings.ScoreboardFormat, eventSettings.ScoreboardTemplate)
+		if err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+	}
+	if lightingScenes := r.PostFormValue("lightingScenes"); lightingScenes != "" {
+		scenes, err := lighting.ParseScenes(lightingScenes)
+		if err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+		eventSettings.LightingScenes = scenes
+	} else {
+		eventSettings.LightingScenes = lighting.DefaultScenes
+	}
 	eventSettings.WarmupDurationSec, _ = strconv.Atoi(r.PostFormValue("warmupDurationSec"))
 	eventSettings.AutoDurationSec, _ = strconv.Atoi(r.PostFormValue("autoDurationSec"))
 	eventSettings.PauseDurationSec, _ = strconv.Atoi(r.PostFormValue("pauseDurationSec"))
 	eventSettings.TeleopDurationSec, _ = strconv.Atoi(r.PostFormValue("teleopDurationSec"))
 	eventSettings.WarningRemainingDurationSec, _ = strconv.Atoi(r.PostFormValue("warningRemainingDurationSec"))
+	eventSettings.DemoMatchTimingEnabled = r.PostFormValue("demoMatchTimingEnabled") == "on"
+	eventSettings.DemoAutoDurationSec, _ = strconv.Atoi(r.PostFormValue("demoAutoDurationSec"))
+	eventSettings.DemoPauseDurationSec, _ = strconv.Atoi(r.PostFormValue("demoPauseDurationSec"))
+	eventSettings.DemoTeleopDurationSec, _ = strconv.Atoi(r.PostFormValue("demoTeleopDurationSec"))
+	eventSettings.DemoWarningRemainingDurationSec, _ = strconv.Atoi(
+		r.PostFormValue("demoWarningRemainingDurationSec"),
+	)
+	if matchSoundCues := r.PostFormValue("matchSoundCues"); matchSoundCues != "" {
+		cues, err := game.ParseMatchSoundCues(matchSoundCues)
+		if err != nil {
+			web.renderSettings(w, r, err.Error())
+			return
+		}
+		eventSettings.MatchSoundCues = cues
+	} else {
+		eventSettings.MatchSoundCues = game.DefaultMatchSoundCues
+	}
 	eventSettings.MelodyBonusThresholdWithoutCoop, _ = strconv.Atoi(r.PostFormValue("melodyBonusThresholdWithoutCoop"))
 	eventSettings.MelodyBonusThresholdWithCoop, _ = strconv.Atoi(r.PostFormValue("melodyBonusThresholdWithCoop"))
 	eventSettings.AmplificationNoteLimit, _ = strconv.Atoi(r.PostFormValue("amplificationNoteLimit"))
 	eventSettings.AmplificationDurationSec, _ = strconv.Atoi(r.PostFormValue("amplificationDurationSec"))
-
+	if eventSettings.GameKey != previousGameKey {
+		// Start from the new game's standard match timing rather than carrying over that of the previous game.
+		gameDefinition, _ := game.GetGameDefinition(eventSettings.GameKey)
+		matchTiming := gameDefinition.DefaultMatchTiming()
+		eventSettings.WarmupDurationSec = matchTiming.WarmupDurationSec
+		eventSettings.AutoDurationSec = matchTiming.AutoDurationSec
+		eventSettings.PauseDurationSec = matchTiming.PauseDurationSec
+		eventSettings.TeleopDurationSec = matchTiming.TeleopDurationSec
+		eventSettings.WarningRemainingDurationSec = matchTiming.WarningRemainingDurationSec
+	}
+
+	recordAuditChanges(r, *web.arena.SavedEventSettings(), *eventSettings)
 	err := web.arena.Database.UpdateEventSettings(eventSettings)
 	if err != nil {
 		handleWebErr(w, err)
@@ -189,31 +391,160 @@ func (web *Web) restoreDbHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Replace the current database with the new one.
-	web.arena.Database.Close()
-	err = os.Remove(web.arena.Database.Path)
+	err = web.arena.ReplaceDatabase(func(dbPath string) error {
+		if err := os.Remove(dbPath); err != nil {
+			return err
+		}
+		return os.Rename(tempFilePath, dbPath)
+	})
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
-	err = os.Rename(tempFilePath, web.arena.Database.Path)
+	cachedProjections = nil
+
+	http.Redirect(w, r, "/setup/settings", 303)
+}
+
+// Sends the event settings to the client as a JSON document for loading into another event's database.
+func (web *Web) settingsExportHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	settingsJson, err := web.arena.SavedEventSettings().ExportJson()
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
-	web.arena.Database, err = model.OpenDatabase(web.arena.Database.Path)
+	filename := fmt.Sprintf("%s-settings.json", strings.Replace(web.arena.EventSettings.Name, " ", "_", -1))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	_, _ = w.Write(settingsJson)
+}
+
+// Accepts an event settings JSON document as an upload and saves its contents over the current event settings.
+func (web *Web) settingsImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("settingsFile")
+	if err != nil {
+		web.renderSettings(w, r, "No event settings file was specified.")
+		return
+	}
+	defer file.Close()
+	settingsJson, err := io.ReadAll(file)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
-	err = web.arena.LoadSettings()
+	previousSettings := web.arena.SavedEventSettings()
+	eventSettings, err := model.ImportEventSettingsJson(previousSettings, settingsJson)
 	if err != nil {
+		web.renderSettings(w, r, err.Error())
+		return
+	}
+	if err = validateImportedEventSettings(eventSettings); err != nil {
+		web.renderSettings(w, r, err.Error())
+		return
+	}
+	if eventSettings.PlayoffType != previousSettings.PlayoffType ||
+		eventSettings.NumPlayoffAlliances != previousSettings.NumPlayoffAlliances {
+		alliances, err := web.arena.Database.GetAllAlliances()
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if len(alliances) > 0 {
+			web.renderSettings(
+				w, r, "Cannot change playoff type or size after alliance selection has been finalized.",
+			)
+			return
+		}
+	}
+
+	recordAuditChanges(r, *previousSettings, *eventSettings)
+	if err = web.arena.Database.UpdateEventSettings(eventSettings); err != nil {
 		handleWebErr(w, err)
 		return
 	}
+	if err = web.arena.LoadSettings(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if eventSettings.AdminPassword != previousSettings.AdminPassword {
+		// Delete any existing user sessions to force a logout.
+		if err = web.arena.Database.TruncateUserSessions(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
 
 	http.Redirect(w, r, "/setup/settings", 303)
 }
 
+// Checks the settings loaded from an imported file against the same constraints that the settings form enforces.
+func validateImportedEventSettings(eventSettings *model.EventSettings) error {
+	if _, err := game.GetGameDefinition(eventSettings.GameKey); err != nil {
+		return err
+	}
+	if eventSettings.TeamsPerAlliance < 1 || eventSettings.TeamsPerAlliance > tournament.MaxTeamsPerAlliance {
+		return fmt.Errorf("Teams per alliance must be between 1 and %d.", tournament.MaxTeamsPerAlliance)
+	}
+	if eventSettings.NumPlayoffAlliances < 2 || eventSettings.NumPlayoffAlliances > 16 {
+		return fmt.Errorf("Number of alliances must be between 2 and 16.")
+	}
+	if eventSettings.PlayoffTimeoutDurationSec <= 0 {
+		return fmt.Errorf("Playoff timeout duration must be positive.")
+	}
+	if eventSettings.PitDisplayPageDurationSec <= 0 {
+		return fmt.Errorf("Pit display page duration must be positive.")
+	}
+	if len(eventSettings.PitDisplayPages) == 0 {
+		return fmt.Errorf("At least one pit display page must be enabled.")
+	}
+	for _, page := range eventSettings.PitDisplayPages {
+		if _, ok := model.PitDisplayPageNames[page]; !ok {
+			return fmt.Errorf("Invalid pit display page '%s'.", page)
+		}
+	}
+	if eventSettings.QueueLeadTimeMin <= 0 {
+		return fmt.Errorf("Queueing lead time must be positive.")
+	}
+	if eventSettings.DisplayAlertThresholdSec <= 0 {
+		return fmt.Errorf("Display alert threshold must be positive.")
+	}
+	if eventSettings.AutoBackupIntervalMin < 0 {
+		return fmt.Errorf("Automatic backup interval must not be negative.")
+	}
+	if eventSettings.AutoBackupRetentionCount <= 0 {
+		return fmt.Errorf("Number of automatic backups to keep must be positive.")
+	}
+	if !themeColorRe.MatchString(eventSettings.ThemeAccentColor) {
+		return fmt.Errorf("Theme accent color must be of the form #rrggbb.")
+	}
+	if eventSettings.ReplayClipDurationSec <= 0 {
+		return fmt.Errorf("Replay clip duration must be positive.")
+	}
+	if eventSettings.LightingUniverse < 1 || eventSettings.LightingUniverse > 63999 {
+		return fmt.Errorf("Lighting universe must be between 1 and 63999.")
+	}
+	if eventSettings.ReplayEnabled {
+		if err := partner.ValidateReplayAddress(eventSettings.ReplayAddress); err != nil {
+			return err
+		}
+	}
+	if eventSettings.ScoreboardAddress != "" {
+		if err := scoreboard.ValidateAddress(eventSettings.ScoreboardAddress); err != nil {
+			return err
+		}
+		return scoreboard.ValidateFormat(eventSettings.ScoreboardFormat, eventSettings.ScoreboardTemplate)
+	}
+	return nil
+}
+
 // Deletes all match data including and beyond the given tournament stage.
 func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -240,6 +571,14 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 			handleWebErr(w, err)
 			return
 		}
+		if err = web.arena.Database.TruncateFillerTeams(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if err = web.arena.Database.TruncateSandboxMatchResults(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
 	case model.Qualification:
 		if err = web.deleteMatchDataForType(model.Qualification); err != nil {
 			handleWebErr(w, err)
@@ -249,6 +588,11 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 			handleWebErr(w, err)
 			return
 		}
+		if err = web.arena.Database.TruncateTeamStats(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		cachedProjections = nil
 	case model.Playoff:
 		if err = web.deleteMatchDataForType(model.Playoff); err != nil {
 			handleWebErr(w, err)
@@ -261,6 +605,66 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 		web.arena.AllianceSelectionAlliances = []model.Alliance{}
 		web.arena.AllianceSelectionRankedTeams = []model.AllianceSelectionRankedTeam{}
 	}
+	web.arena.NotifyScheduleChanged(matchType)
+
+	http.Redirect(w, r, "/setup/settings", 303)
+}
+
+// Re-derives the match outcomes, rankings and playoff advancement from the raw stored score breakdowns.
+func (web *Web) recomputeDbHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	// Back up the database.
+	err := web.arena.Database.Backup(web.arena.EventSettings.Name, "pre_recompute")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	for _, matchType := range []model.MatchType{model.Qualification, model.Playoff} {
+		changedMatches, err := tournament.RecomputeMatchOutcomes(web.arena.Database, matchType)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, match := range changedMatches {
+			logging.Scoring.Info("Recomputed outcome of match.", "match", match.ShortName, "status", match.Status)
+		}
+		if err = tournament.CalculateTeamCards(web.arena.Database, matchType); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	if _, err = tournament.CalculateRankings(web.arena.Database, true); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if _, err = tournament.CalculateTeamStats(web.arena.Database); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if err = web.updateProjections(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	if err = web.arena.UpdatePlayoffTournament(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if web.arena.PlayoffTournament.IsComplete() {
+		if err = tournament.CreateOrUpdateWinnerAndFinalistAwards(
+			web.arena.Database,
+			web.arena.PlayoffTournament.WinningAllianceId(),
+			web.arena.PlayoffTournament.FinalistAllianceId(),
+		); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
 
 	http.Redirect(w, r, "/setup/settings", 303)
 }
@@ -272,7 +676,7 @@ func (web *Web) settingsPublishAlliancesHandler(w http.ResponseWriter, r *http.R
 	}
 
 	if web.arena.EventSettings.TbaPublishingEnabled {
-		err := web.arena.TbaClient.PublishAlliances(web.arena.Database)
+		err := web.arena.TbaPublisher.Publish(partner.TbaPublishAlliances)
 		if err != nil {
 			http.Error(w, "Failed to publish alliances: "+err.Error(), 500)
 			return
@@ -292,7 +696,7 @@ func (web *Web) settingsPublishAwardsHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	if web.arena.EventSettings.TbaPublishingEnabled {
-		err := web.arena.TbaClient.PublishAwards(web.arena.Database)
+		err := web.arena.TbaPublisher.Publish(partner.TbaPublishAwards)
 		if err != nil {
 			http.Error(w, "Failed to publish awards: "+err.Error(), 500)
 			return
@@ -317,7 +721,7 @@ func (web *Web) settingsPublishMatchesHandler(w http.ResponseWriter, r *http.Req
 			http.Error(w, "Failed to delete published matches: "+err.Error(), 500)
 			return
 		}
-		err = web.arena.TbaClient.PublishMatches(web.arena.Database)
+		err = web.arena.TbaPublisher.Publish(partner.TbaPublishMatches)
 		if err != nil {
 			http.Error(w, "Failed to publish matches: "+err.Error(), 500)
 			return
@@ -337,7 +741,7 @@ func (web *Web) settingsPublishRankingsHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	if web.arena.EventSettings.TbaPublishingEnabled {
-		err := web.arena.TbaClient.PublishRankings(web.arena.Database)
+		err := web.arena.TbaPublisher.Publish(partner.TbaPublishRankings)
 		if err != nil {
 			http.Error(w, "Failed to publish rankings: "+err.Error(), 500)
 			return
@@ -357,7 +761,7 @@ func (web *Web) settingsPublishTeamsHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	if web.arena.EventSettings.TbaPublishingEnabled {
-		err := web.arena.TbaClient.PublishTeams(web.arena.Database)
+		err := web.arena.TbaPublisher.Publish(partner.TbaPublishTeams)
 		if err != nil {
 			http.Error(w, "Failed to publish teams: "+err.Error(), 500)
 			return
@@ -376,10 +780,40 @@ func (web *Web) renderSettings(w http.ResponseWriter, r *http.Request, errorMess
 		handleWebErr(w, err)
 		return
 	}
+	eventSettings := web.arena.SavedEventSettings()
+	enabledPitDisplayPages := make(map[string]bool)
+	for _, page := range eventSettings.PitDisplayPages {
+		enabledPitDisplayPages[page] = true
+	}
 	data := struct {
 		*model.EventSettings
-		ErrorMessage string
-	}{web.arena.EventSettings, errorMessage}
+		GameDefinitions        []game.GameDefinition
+		MatchSoundCuesText     string
+		MatchSoundNames        []string
+		LightingScenesText     string
+		LightingSceneNames     []lighting.SceneName
+		AllPitDisplayPages     []string
+		PitDisplayPageNames    map[string]string
+		EnabledPitDisplayPages map[string]bool
+		ScoreboardFormatNames  map[string]string
+		ServerTimeZone         string
+		ChangesPending         bool
+		ErrorMessage           string
+	}{
+		eventSettings,
+		game.GetAllGameDefinitions(),
+		game.FormatMatchSoundCues(eventSettings.MatchSoundCues),
+		game.MatchSoundNames,
+		lighting.FormatScenes(eventSettings.LightingScenes),
+		lighting.SceneNames,
+		model.PitDisplayPages,
+		model.PitDisplayPageNames,
+		enabledPitDisplayPages,
+		scoreboard.FormatNames,
+		time.Local.String(),
+		web.arena.SettingsChangesPending(),
+		errorMessage,
+	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -408,6 +842,33 @@ func (web *Web) deleteMatchDataForType(matchType model.MatchType) error {
 				return err
 			}
 		}
+		scoreEdits, err := web.arena.Database.GetScoreEditsForMatch(match.Id)
+		if err != nil {
+			return err
+		}
+		for _, scoreEdit := range scoreEdits {
+			if err = web.arena.Database.DeleteScoreEdit(scoreEdit.Id); err != nil {
+				return err
+			}
+		}
+		matchTimelines, err := web.arena.Database.GetMatchTimelinesForMatch(match.Id)
+		if err != nil {
+			return err
+		}
+		for _, matchTimeline := range matchTimelines {
+			if err = web.arena.Database.DeleteMatchTimeline(matchTimeline.Id); err != nil {
+				return err
+			}
+		}
+		observations, err := web.arena.Database.GetScoutingObservationsForMatch(match.Id)
+		if err != nil {
+			return err
+		}
+		for _, observation := range observations {
+			if err = web.arena.Database.DeleteScoutingObservation(observation.Id); err != nil {
+				return err
+			}
+		}
 
 		if err = web.arena.Database.DeleteMatch(match.Id); err != nil {
 			return err
@@ -5,8 +5,11 @@ package web
 
 import (
 	"bytes"
+	"github.com/Team254/cheesy-arena/field"
 	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/lighting"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/scoreboard"
 	"github.com/Team254/cheesy-arena/tournament"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -14,6 +17,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestSetupSettings(t *testing.T) {
@@ -39,6 +43,272 @@ func TestSetupSettings(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "tbasec")
 }
 
+func TestSetupSettingsPlayoffTimeouts(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "playoffTimeoutsPerAlliance=2&playoffTimeoutDurationSec=0")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Playoff timeout duration must be positive.")
+
+	recorder = web.postHttpResponse("/setup/settings", "playoffTimeoutsPerAlliance=2&playoffTimeoutDurationSec=480")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 2, web.arena.EventSettings.PlayoffTimeoutsPerAlliance)
+	assert.Equal(t, 480, web.arena.EventSettings.PlayoffTimeoutDurationSec)
+}
+
+func TestSetupSettingsReplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "replayEnabled=on&replayAddress=10.0.100.20:9000")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "must be of the form udp://host:port")
+	recorder = web.postHttpResponse(
+		"/setup/settings", "replayEnabled=on&replayAddress=udp://10.0.100.20:9000&replayClipDurationSec=0",
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Replay clip duration must be positive.")
+
+	recorder = web.postHttpResponse(
+		"/setup/settings",
+		"replayEnabled=on&replayAddress=udp://10.0.100.20:9000&replayClipDurationSec=15&replayBannerEnabled=on",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.True(t, web.arena.EventSettings.ReplayEnabled)
+	assert.Equal(t, "udp://10.0.100.20:9000", web.arena.EventSettings.ReplayAddress)
+	assert.Equal(t, 15, web.arena.EventSettings.ReplayClipDurationSec)
+	assert.True(t, web.arena.EventSettings.ReplayBannerEnabled)
+}
+
+func TestSetupSettingsFirstApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse(
+		"/setup/settings", "firstEventCode= casj &firstApiUsername=my_username&firstApiKey=my_api_key",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "CASJ", web.arena.EventSettings.FirstEventCode)
+	assert.Equal(t, "my_username", web.arena.EventSettings.FirstApiUsername)
+	assert.Equal(t, "my_api_key", web.arena.EventSettings.FirstApiKey)
+	assert.True(t, web.arena.FirstClient.IsConfigured())
+}
+
+func TestSetupSettingsPitDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "pitDisplayPageDurationSec=0&pitDisplayPages=rankings")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Pit display page duration must be positive.")
+
+	recorder = web.postHttpResponse("/setup/settings", "pitDisplayPageDurationSec=20")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "At least one pit display page must be enabled.")
+
+	recorder = web.postHttpResponse("/setup/settings", "pitDisplayPageDurationSec=20&pitDisplayPages=announcement&"+
+		"pitDisplayPages=rankings&pitDisplayAnnouncement=Lunch+at+noon")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []string{"announcement", "rankings"}, web.arena.EventSettings.PitDisplayPages)
+	assert.Equal(t, 20, web.arena.EventSettings.PitDisplayPageDurationSec)
+	assert.Equal(t, "Lunch at noon", web.arena.EventSettings.PitDisplayAnnouncement)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Lunch at noon")
+}
+
+func TestSetupSettingsQueueLeadTime(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "queueLeadTimeMin=0")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Queueing lead time must be positive.")
+
+	recorder = web.postHttpResponse("/setup/settings", "queueLeadTimeMin=12")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 12, web.arena.EventSettings.QueueLeadTimeMin)
+}
+
+func TestSetupSettingsTimeZone(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "timeZone=America/Nowhere")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Unknown time zone 'America/Nowhere'.")
+
+	recorder = web.postHttpResponse("/setup/settings", "timeZone=America/Chicago")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "America/Chicago", web.arena.EventSettings.TimeZone)
+	assert.Equal(t, "America/Chicago", web.arena.TbaClient.Location.String())
+
+	// Check that the schedule is shown in the event's time zone rather than the server's.
+	web.arena.Database.CreateMatch(
+		&model.Match{Type: model.Qualification, ShortName: "Q1", Time: time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)},
+	)
+	recorder = web.getHttpResponse("/reports/csv/schedule/qualification")
+	assert.Contains(t, recorder.Body.String(), "Q1,Qualification,2024-03-09 09:00:00 -0600 CST,")
+}
+
+func TestSetupSettingsScoreboard(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "scoreboardAddress=10.0.100.30")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "must be of the form udp://host:port")
+	recorder = web.postHttpResponse(
+		"/setup/settings", "scoreboardAddress=udp://127.0.0.1:21000&scoreboardFormat=template&scoreboardTemplate=",
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Scoreboard packet template cannot be blank.")
+
+	recorder = web.postHttpResponse(
+		"/setup/settings", "scoreboardAddress=udp://127.0.0.1:21000&scoreboardFormat=daktronics",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "udp://127.0.0.1:21000", web.arena.EventSettings.ScoreboardAddress)
+	assert.Equal(t, scoreboard.DaktronicsAllSportFormat, web.arena.EventSettings.ScoreboardFormat)
+	assert.True(t, web.arena.Scoreboard.IsEnabled())
+}
+
+func TestSetupSettingsDisplayAlertThreshold(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "displayAlertThresholdSec=-5")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Display alert threshold must be positive.")
+
+	recorder = web.postHttpResponse("/setup/settings", "displayAlertThresholdSec=30")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 30, web.arena.EventSettings.DisplayAlertThresholdSec)
+}
+
+func TestSetupSettingsAutoBackups(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "autoBackupIntervalMin=-1")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Automatic backup interval must not be negative.")
+	recorder = web.postHttpResponse("/setup/settings", "autoBackupRetentionCount=0")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Number of automatic backups to keep must be positive.")
+
+	recorder = web.postHttpResponse("/setup/settings", "autoBackupIntervalMin=0&autoBackupRetentionCount=20")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 0, web.arena.EventSettings.AutoBackupIntervalMin)
+	assert.Equal(t, 20, web.arena.EventSettings.AutoBackupRetentionCount)
+}
+
+func TestSetupSettingsGame(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "2024 CRESCENDO")
+
+	recorder = web.postHttpResponse("/setup/settings", "gameKey=blorpy")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid game: blorpy")
+	assert.Equal(t, "crescendo2024", web.arena.EventSettings.GameKey)
+
+	recorder = web.postHttpResponse("/setup/settings", "gameKey=crescendo2024&autoDurationSec=15&teleopDurationSec=135")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "crescendo2024", web.arena.EventSettings.GameKey)
+	assert.Equal(t, "crescendo2024", game.CurrentGame().Key())
+}
+
+func TestSetupSettingsMatchTiming(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "warning_guitar,warning,0")
+	assert.NotContains(t, recorder.Body.String(), "demoMatchTimingEnabled\" checked")
+
+	recorder = web.postHttpResponse(
+		"/setup/settings",
+		"autoDurationSec=15&teleopDurationSec=135&demoMatchTimingEnabled=on&demoAutoDurationSec=10&"+
+			"demoTeleopDurationSec=60&demoWarningRemainingDurationSec=15&matchSoundCues=start,autoStart,0%0A"+
+			"warning,teleopEnd,-30",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.True(t, web.arena.EventSettings.DemoMatchTimingEnabled)
+	assert.Equal(t, 10, web.arena.EventSettings.DemoAutoDurationSec)
+	assert.Equal(t, 60, web.arena.EventSettings.DemoTeleopDurationSec)
+	assert.Equal(t, 15, web.arena.EventSettings.DemoWarningRemainingDurationSec)
+	assert.Equal(
+		t,
+		[]game.MatchSoundCue{{"start", game.SoundAnchorAutoStart, 0}, {"warning", game.SoundAnchorTeleopEnd, -30}},
+		web.arena.EventSettings.MatchSoundCues,
+	)
+
+	// The test match loaded at startup should pick up the demo timing.
+	assert.Equal(t, 10, game.MatchTiming.AutoDurationSec)
+	assert.Equal(t, 60, game.MatchTiming.TeleopDurationSec)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "demoMatchTimingEnabled\" checked")
+	assert.Contains(t, recorder.Body.String(), "warning,teleopEnd,-30")
+
+	// An invalid sound cue schedule should be rejected.
+	recorder = web.postHttpResponse("/setup/settings", "matchSoundCues=blorpy,autoStart,0")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid sound name 'blorpy'")
+	assert.Equal(t, 2, len(web.arena.EventSettings.MatchSoundCues))
+
+	// Clearing the schedule should restore the default.
+	recorder = web.postHttpResponse("/setup/settings", "autoDurationSec=15&teleopDurationSec=135&matchSoundCues=")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, game.DefaultMatchSoundCues, web.arena.EventSettings.MatchSoundCues)
+	assert.Equal(t, 15, game.MatchTiming.AutoDurationSec)
+}
+
+func TestSetupSettingsLighting(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse(
+		"/setup/settings", "lightingAddress=127.0.0.1&lightingUniverse=4&lightingScenes=redWin:255,0,0%0Atie:0,0,0,255",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "127.0.0.1", web.arena.EventSettings.LightingAddress)
+	assert.Equal(t, 4, web.arena.EventSettings.LightingUniverse)
+	assert.Equal(
+		t,
+		[]lighting.Scene{{lighting.SceneRedWin, []byte{255, 0, 0}}, {lighting.SceneTie, []byte{0, 0, 0, 255}}},
+		web.arena.EventSettings.LightingScenes,
+	)
+	assert.True(t, web.arena.Lighting.IsEnabled())
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "tie:0,0,0,255")
+
+	// Invalid values should be rejected.
+	recorder = web.postHttpResponse("/setup/settings", "lightingUniverse=0")
+	assert.Contains(t, recorder.Body.String(), "Lighting universe must be between 1 and 63999.")
+	recorder = web.postHttpResponse("/setup/settings", "lightingScenes=blorpy:255")
+	assert.Contains(t, recorder.Body.String(), "Invalid scene name 'blorpy'")
+	assert.Equal(t, 2, len(web.arena.EventSettings.LightingScenes))
+
+	// Clearing the scenes should restore the defaults.
+	recorder = web.postHttpResponse("/setup/settings", "lightingScenes=")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, lighting.DefaultScenes, web.arena.EventSettings.LightingScenes)
+	assert.False(t, web.arena.Lighting.IsEnabled())
+}
+
+func TestSetupSettingsDuringMatch(t *testing.T) {
+	web := setupTestWeb(t)
+	originalMatchTiming := game.MatchTiming
+	defer func() { game.MatchTiming = originalMatchTiming }()
+	web.arena.MatchState = field.AutoPeriod
+
+	// The saved settings should be shown while the ones in effect stay the same until the match is over.
+	recorder := web.postHttpResponse("/setup/settings", "name=Chezy Champs&teleopDurationSec=100")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "Untitled Event", web.arena.EventSettings.Name)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Chezy Champs")
+	assert.Contains(t, recorder.Body.String(), "will take effect once it is over")
+
+	web.arena.MatchState = field.PreMatch
+	web.arena.Update()
+	assert.Equal(t, "Chezy Champs", web.arena.EventSettings.Name)
+	assert.Equal(t, 100, game.MatchTiming.TeleopDurationSec)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.NotContains(t, recorder.Body.String(), "will take effect once it is over")
+}
+
 func TestSetupSettingsDoubleElimination(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -172,6 +442,35 @@ func TestSetupSettingsClearDb(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Invalid tournament stage to clear")
 }
 
+func TestSetupSettingsRecomputeDb(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Store a qualification result whose recorded outcome disagrees with its score breakdown.
+	match := model.Match{Type: model.Qualification, Red1: 254, Red2: 1, Red3: 2, Blue1: 1114, Blue2: 3, Blue3: 4,
+		Status: game.RedWonMatch}
+	assert.Nil(t, web.arena.Database.CreateMatch(&match))
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.PlayNumber = 1
+	matchResult.BlueScore.LeaveStatuses[0] = true
+	assert.Nil(t, web.arena.Database.CreateMatchResult(matchResult))
+
+	recorder := web.postHttpResponse("/setup/db/recompute", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	updatedMatch, _ := web.arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, game.BlueWonMatch, updatedMatch.Status)
+	ranking, _ := web.arena.Database.GetRankingForTeam(1114)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 1, ranking.Wins)
+	}
+	ranking, _ = web.arena.Database.GetRankingForTeam(254)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 1, ranking.Losses)
+	}
+	teamStat, _ := web.arena.Database.GetTeamStatForTeam(1114)
+	assert.NotNil(t, teamStat)
+}
+
 func TestSetupSettingsBackupRestoreDb(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -205,6 +504,48 @@ func TestSetupSettingsBackupRestoreDb(t *testing.T) {
 	assert.Equal(t, "Chezy Champs", web.arena.EventSettings.Name)
 }
 
+func TestSetupSettingsExportImport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	settings := web.arena.SavedEventSettings()
+	settings.Name = "Chezy Champs"
+	settings.QueueLeadTimeMin = 15
+	settings.ApPassword = "vetted"
+	assert.Nil(t, web.arena.Database.UpdateEventSettings(settings))
+	assert.Nil(t, web.arena.LoadSettings())
+	recorder := web.getHttpResponse("/setup/settings/export")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "Chezy_Champs-settings.json")
+	settingsBody := recorder.Body
+
+	// Start over with a fresh database for a different event.
+	web = setupTestWeb(t)
+	web.arena.EventSettings.Name = "Madtown Throwdown"
+	assert.Nil(t, web.arena.Database.UpdateEventSettings(web.arena.EventSettings))
+
+	recorder = web.postHttpResponse("/setup/settings/import", "")
+	assert.Contains(t, recorder.Body.String(), "No event settings file was specified.")
+	recorder = web.postFileHttpResponse(
+		"/setup/settings/import", "settingsFile", bytes.NewBufferString("{\"QueueLeadTimeMin\": 0}"),
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Queueing lead time must be positive.")
+	recorder = web.postFileHttpResponse(
+		"/setup/settings/import", "settingsFile", bytes.NewBufferString("{\"GameKey\": \"blorpy\"}"),
+	)
+	assert.Contains(t, recorder.Body.String(), "Invalid game: blorpy")
+	recorder = web.postFileHttpResponse("/setup/settings/import", "settingsFile", bytes.NewBufferString("blorpy"))
+	assert.Contains(t, recorder.Body.String(), "Invalid event settings file")
+	assert.Equal(t, 10, web.arena.EventSettings.QueueLeadTimeMin)
+
+	recorder = web.postFileHttpResponse("/setup/settings/import", "settingsFile", settingsBody)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "Madtown Throwdown", web.arena.EventSettings.Name)
+	assert.Equal(t, 15, web.arena.EventSettings.QueueLeadTimeMin)
+	assert.Equal(t, "vetted", web.arena.EventSettings.ApPassword)
+}
+
 func TestSetupSettingsPublishToTba(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -0,0 +1,177 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for managing the sequence of audience display screens that the producer steps through during the show.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"github.com/mitchellh/mapstructure"
+	"io"
+	"net/http"
+)
+
+// Shows the show flow configuration page.
+func (web *Web) showFlowGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_show_flow.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	showFlowSteps, err := web.arena.Database.GetAllShowFlowSteps()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		ShowFlowSteps []model.ShowFlowStep
+		ScreenNames   map[string]string
+	}{web.arena.EventSettings, showFlowSteps, model.ShowFlowScreenNames}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the show flow client to send control commands.
+func (web *Web) showFlowWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Web.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "saveShowFlowStep":
+			var showFlowStep model.ShowFlowStep
+			err = mapstructure.Decode(data, &showFlowStep)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.saveShowFlowStep(&showFlowStep)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "deleteShowFlowStep":
+			var showFlowStep model.ShowFlowStep
+			err = mapstructure.Decode(data, &showFlowStep)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.Database.DeleteShowFlowStep(showFlowStep.Id)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "reorderShowFlowStep":
+			args := struct {
+				Id     int
+				MoveUp bool
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.reorderShowFlowStep(args.Id, args.MoveUp)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+			continue
+		}
+
+		// The positions of the steps may have shifted, so start the flow over from the top.
+		web.arena.ResetShowFlow()
+
+		// Force a reload of the client to render the updated show flow.
+		err = ws.WriteNotifier(web.arena.ReloadDisplaysNotifier)
+		if err != nil {
+			logging.Web.Warn("Websocket write error.", "error", err)
+			return
+		}
+	}
+}
+
+func (web *Web) saveShowFlowStep(showFlowStep *model.ShowFlowStep) error {
+	oldShowFlowStep, err := web.arena.Database.GetShowFlowStepById(showFlowStep.Id)
+	if err != nil {
+		return err
+	}
+
+	// Create or update the step.
+	if oldShowFlowStep == nil {
+		showFlowStep.DisplayOrder = web.arena.Database.GetNextShowFlowStepDisplayOrder()
+		return web.arena.Database.CreateShowFlowStep(showFlowStep)
+	}
+	oldShowFlowStep.Screen = showFlowStep.Screen
+	oldShowFlowStep.AutoAdvanceSec = showFlowStep.AutoAdvanceSec
+	return web.arena.Database.UpdateShowFlowStep(oldShowFlowStep)
+}
+
+// Swaps the show flow step having the given ID with the one immediately above or below it.
+func (web *Web) reorderShowFlowStep(id int, moveUp bool) error {
+	showFlowSteps, err := web.arena.Database.GetAllShowFlowSteps()
+	if err != nil {
+		return err
+	}
+	stepIndex := -1
+	for i, showFlowStep := range showFlowSteps {
+		if showFlowStep.Id == id {
+			stepIndex = i
+			break
+		}
+	}
+	if stepIndex == -1 {
+		return fmt.Errorf("show flow step %d does not exist", id)
+	}
+	adjacentIndex := stepIndex + 1
+	if moveUp {
+		adjacentIndex = stepIndex - 1
+	}
+	if adjacentIndex < 0 || adjacentIndex == len(showFlowSteps) {
+		// The one to move is already at the limit; return an error to prevent a page reload.
+		return fmt.Errorf("already at the limit")
+	}
+
+	// Swap their display orders and save.
+	showFlowStep, adjacentShowFlowStep := &showFlowSteps[stepIndex], &showFlowSteps[adjacentIndex]
+	showFlowStep.DisplayOrder, adjacentShowFlowStep.DisplayOrder =
+		adjacentShowFlowStep.DisplayOrder, showFlowStep.DisplayOrder
+	if err = web.arena.Database.UpdateShowFlowStep(showFlowStep); err != nil {
+		return err
+	}
+	return web.arena.Database.UpdateShowFlowStep(adjacentShowFlowStep)
+}
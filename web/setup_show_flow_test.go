@@ -0,0 +1,61 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetupShowFlow(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "intro", DisplayOrder: 1})
+	web.arena.Database.CreateShowFlowStep(&model.ShowFlowStep{Screen: "sponsor", AutoAdvanceSec: 30, DisplayOrder: 2})
+
+	recorder := web.getHttpResponse("/setup/show_flow")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Show Flow")
+	assert.Contains(t, recorder.Body.String(), `value="30"`)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/setup/show_flow/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	ws.Write("saveShowFlowStep", map[string]any{"Id": 0, "Screen": "score", "AutoAdvanceSec": 0})
+	readWebsocketType(t, ws, "reload")
+	ws.Write("saveShowFlowStep", map[string]any{"Id": 1, "Screen": "match", "AutoAdvanceSec": 0})
+	readWebsocketType(t, ws, "reload")
+	showFlowSteps, _ := web.arena.Database.GetAllShowFlowSteps()
+	if assert.Equal(t, 3, len(showFlowSteps)) {
+		assert.Equal(t, "match", showFlowSteps[0].Screen)
+		assert.Equal(t, "score", showFlowSteps[2].Screen)
+		assert.Equal(t, 3, showFlowSteps[2].DisplayOrder)
+	}
+	ws.Write("saveShowFlowStep", map[string]any{"Id": 0, "Screen": "rankings", "AutoAdvanceSec": 0})
+	assert.Contains(t, readWebsocketError(t, ws), "invalid audience display screen")
+
+	ws.Write("reorderShowFlowStep", map[string]any{"Id": 3, "MoveUp": true})
+	readWebsocketType(t, ws, "reload")
+	showFlowSteps, _ = web.arena.Database.GetAllShowFlowSteps()
+	assert.Equal(t, []int{1, 3, 2}, []int{showFlowSteps[0].Id, showFlowSteps[1].Id, showFlowSteps[2].Id})
+	ws.Write("reorderShowFlowStep", map[string]any{"Id": 1, "MoveUp": true})
+	assert.Contains(t, readWebsocketError(t, ws), "already at the limit")
+
+	// Editing the flow should start it over from the top.
+	assert.Nil(t, web.arena.AdvanceShowFlow())
+	ws.Write("deleteShowFlowStep", map[string]any{"Id": 3})
+	readWebsocketType(t, ws, "reload")
+	time.Sleep(time.Millisecond * 10)
+	showFlowSteps, _ = web.arena.Database.GetAllShowFlowSteps()
+	assert.Equal(t, 2, len(showFlowSteps))
+	assert.Equal(t, -1, web.arena.ShowFlowPosition)
+}
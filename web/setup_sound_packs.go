@@ -0,0 +1,181 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for uploading and choosing packs of alternate match sounds.
+
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Shows the sound pack configuration page.
+func (web *Web) soundPacksGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	web.renderSoundPacks(w, r, "")
+}
+
+// Uploads a new sound pack, or activates or deletes an existing one.
+func (web *Web) soundPacksPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	soundPackId, _ := strconv.Atoi(r.PostFormValue("id"))
+	switch r.PostFormValue("action") {
+	case "upload":
+		name := strings.TrimSpace(r.PostFormValue("name"))
+		if name == "" {
+			web.renderSoundPacks(w, r, "A name is required for the sound pack.")
+			return
+		}
+		sounds, err := readUploadedSounds(r)
+		if err != nil {
+			web.renderSoundPacks(w, r, err.Error())
+			return
+		}
+		if err = web.arena.Database.CreateSoundPack(&model.SoundPack{Name: name, Sounds: sounds}); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "activate":
+		if soundPackId != 0 {
+			soundPack, err := web.arena.Database.GetSoundPackById(soundPackId)
+			if err != nil {
+				handleWebErr(w, err)
+				return
+			}
+			if soundPack == nil {
+				web.renderSoundPacks(w, r, fmt.Sprintf("Sound pack %d does not exist.", soundPackId))
+				return
+			}
+		}
+		if err := web.setActiveSoundPack(soundPackId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "delete":
+		if err := web.arena.Database.DeleteSoundPack(soundPackId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if web.arena.EventSettings.SoundPackId == soundPackId {
+			if err := web.setActiveSoundPack(0); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+	}
+
+	http.Redirect(w, r, "/setup/sound_packs", 303)
+}
+
+// Serves the audio file for the given match sound, taken from the active sound pack if it replaces that sound or from
+// the built-in set otherwise.
+func (web *Web) soundsApiHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !slices.Contains(game.MatchSoundNames, name) {
+		handleWebErr(w, fmt.Errorf("invalid sound '%s'", name))
+		return
+	}
+
+	if web.arena.EventSettings.SoundPackId != 0 {
+		soundPack, err := web.arena.Database.GetSoundPackById(web.arena.EventSettings.SoundPackId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if soundPack != nil {
+			if sound, ok := soundPack.Sounds[name]; ok {
+				w.Header().Set("Content-Type", "audio/wav")
+				if _, err = w.Write(sound); err != nil {
+					handleWebErr(w, err)
+				}
+				return
+			}
+		}
+	}
+
+	http.ServeFile(w, r, filepath.Join(model.BaseDir, "static/audio", name+".wav"))
+}
+
+func (web *Web) renderSoundPacks(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_sound_packs.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	soundPacks, err := web.arena.Database.GetAllSoundPacks()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		SoundPacks      []model.SoundPack
+		MatchSoundNames []string
+		ErrorMessage    string
+	}{web.arena.EventSettings, soundPacks, game.MatchSoundNames, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Switches the displays over to the sound pack having the given ID, or to the built-in sounds if it is zero.
+func (web *Web) setActiveSoundPack(soundPackId int) error {
+	eventSettings := web.arena.SavedEventSettings()
+	eventSettings.SoundPackId = soundPackId
+	if err := web.arena.Database.UpdateEventSettings(eventSettings); err != nil {
+		return err
+	}
+
+	// The arena has the displays reload to pick up the new sounds once the change takes effect.
+	return web.arena.LoadSettings()
+}
+
+// Returns the contents of the uploaded WAV files, keyed by the name of the match sound that each replaces as given by
+// its file name.
+func readUploadedSounds(r *http.Request) (map[string][]byte, error) {
+	if r.MultipartForm == nil || len(r.MultipartForm.File["soundFiles"]) == 0 {
+		return nil, fmt.Errorf("No sound files were uploaded.")
+	}
+
+	sounds := make(map[string][]byte)
+	for _, fileHeader := range r.MultipartForm.File["soundFiles"] {
+		fileName := filepath.Base(fileHeader.Filename)
+		name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		if !slices.Contains(game.MatchSoundNames, name) {
+			return nil, fmt.Errorf(
+				"File '%s' does not match any of the sound names: %s.", fileName, strings.Join(game.MatchSoundNames, ", "),
+			)
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		sound, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(sound) < 12 || !bytes.Equal(sound[0:4], []byte("RIFF")) || !bytes.Equal(sound[8:12], []byte("WAVE")) {
+			return nil, fmt.Errorf("File '%s' is not a WAV file.", fileName)
+		}
+		sounds[name] = sound
+	}
+	return sounds, nil
+}
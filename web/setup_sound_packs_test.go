@@ -0,0 +1,85 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSetupSoundPacks(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/sound_packs")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Built-in")
+
+	// Check the validation of uploaded files.
+	recorder = web.postSoundPack("Retro", map[string]string{"start.wav": "RIFF0000WAVEfmt "})
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	recorder = web.postSoundPack("", map[string]string{"start.wav": "RIFF0000WAVEfmt "})
+	assert.Contains(t, recorder.Body.String(), "A name is required")
+	recorder = web.postSoundPack("Bad", map[string]string{"blorpy.wav": "RIFF0000WAVEfmt "})
+	assert.Contains(t, recorder.Body.String(), "File 'blorpy.wav' does not match any of the sound names")
+	recorder = web.postSoundPack("Bad", map[string]string{"end.wav": "ID3 not a wav file"})
+	assert.Contains(t, recorder.Body.String(), "File 'end.wav' is not a WAV file.")
+	recorder = web.postSoundPack("Bad", map[string]string{})
+	assert.Contains(t, recorder.Body.String(), "No sound files were uploaded.")
+	soundPacks, _ := web.arena.Database.GetAllSoundPacks()
+	if assert.Equal(t, 1, len(soundPacks)) {
+		assert.Equal(t, "Retro", soundPacks[0].Name)
+		assert.Equal(t, []string{"start"}, soundPacks[0].SoundNames())
+	}
+	recorder = web.getHttpResponse("/setup/sound_packs")
+	assert.Contains(t, recorder.Body.String(), "Retro")
+
+	// The built-in sounds should be served until the pack is activated.
+	recorder = web.getHttpResponse("/api/sounds/start")
+	assert.Equal(t, 200, recorder.Code)
+	assert.NotEqual(t, "RIFF0000WAVEfmt ", recorder.Body.String())
+	recorder = web.postHttpResponse("/setup/sound_packs", "action=activate&id=99")
+	assert.Contains(t, recorder.Body.String(), "Sound pack 99 does not exist.")
+	recorder = web.postHttpResponse("/setup/sound_packs", "action=activate&id="+strconv.Itoa(soundPacks[0].Id))
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, soundPacks[0].Id, web.arena.EventSettings.SoundPackId)
+	recorder = web.getHttpResponse("/api/sounds/start")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "RIFF0000WAVEfmt ", recorder.Body.String())
+	recorder = web.getHttpResponse("/api/sounds/end")
+	assert.Equal(t, 200, recorder.Code)
+	assert.NotEqual(t, "RIFF0000WAVEfmt ", recorder.Body.String())
+	recorder = web.getHttpResponse("/api/sounds/blorpy")
+	assert.Equal(t, 500, recorder.Code)
+
+	// Deleting the active pack should revert to the built-in sounds.
+	recorder = web.postHttpResponse("/setup/sound_packs", "action=delete&id="+strconv.Itoa(soundPacks[0].Id))
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 0, web.arena.EventSettings.SoundPackId)
+	soundPacks, _ = web.arena.Database.GetAllSoundPacks()
+	assert.Empty(t, soundPacks)
+}
+
+// Posts a request to upload a sound pack with the given name and files, keyed by file name.
+func (web *Web) postSoundPack(name string, files map[string]string) *httptest.ResponseRecorder {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("action", "upload")
+	writer.WriteField("name", name)
+	for fileName, contents := range files {
+		part, _ := writer.CreateFormFile("soundFiles", fileName)
+		part.Write([]byte(contents))
+	}
+	writer.Close()
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/setup/sound_packs", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	web.newHandler().ServeHTTP(recorder, req)
+	return recorder
+}
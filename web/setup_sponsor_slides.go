@@ -6,14 +6,31 @@
 package web
 
 import (
+	"errors"
+	"fmt"
 	"github.com/Team254/cheesy-arena/model"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Directory under which uploaded sponsor images and videos are stored and served from.
+const sponsorMediaDir = "static/img/sponsors"
+
+// Format of the active time window fields, as submitted by a datetime-local input.
+const sponsorSlideTimeFormat = "2006-01-02T15:04"
+
+// File extensions of the sponsor media that may be uploaded as still images.
+var sponsorImageExtensions = []string{".gif", ".jpeg", ".jpg", ".png", ".svg", ".webp"}
+
 // Shows the sponsor slides configuration page.
 func (web *Web) sponsorSlidesGetHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole) {
 		return
 	}
 
@@ -44,7 +61,7 @@ func (web *Web) sponsorSlidesGetHandler(w http.ResponseWriter, r *http.Request)
 
 // Saves the new or modified sponsor slides to the database.
 func (web *Web) sponsorSlidesPostHandler(w http.ResponseWriter, r *http.Request) {
-	if !web.userIsAdmin(w, r) {
+	if !web.userHasRole(w, r, model.AvRole) {
 		return
 	}
 
@@ -63,19 +80,42 @@ func (web *Web) sponsorSlidesPostHandler(w http.ResponseWriter, r *http.Request)
 		}
 	case "save":
 		displayTimeSec, _ := strconv.Atoi(r.PostFormValue("displayTimeSec"))
+		location := web.arena.EventSettings.Location()
+		activeStartTime, err := parseSponsorSlideTime(r.PostFormValue("activeStartTime"), location)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		activeEndTime, err := parseSponsorSlideTime(r.PostFormValue("activeEndTime"), location)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		image := r.PostFormValue("image")
+		uploadedImage, err := saveUploadedSponsorMedia(r)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if uploadedImage != "" {
+			image = uploadedImage
+		}
 		if sponsorSlide == nil {
 			sponsorSlide = &model.SponsorSlide{Subtitle: r.PostFormValue("subtitle"),
 				Line1: r.PostFormValue("line1"), Line2: r.PostFormValue("line2"),
-				Image: r.PostFormValue("image"), DisplayTimeSec: displayTimeSec,
-				DisplayOrder: web.arena.Database.GetNextSponsorSlideDisplayOrder(),
+				Image: image, DisplayTimeSec: displayTimeSec,
+				DisplayOrder:    web.arena.Database.GetNextSponsorSlideDisplayOrder(),
+				ActiveStartTime: activeStartTime, ActiveEndTime: activeEndTime,
 			}
 			err = web.arena.Database.CreateSponsorSlide(sponsorSlide)
 		} else {
 			sponsorSlide.Subtitle = r.PostFormValue("subtitle")
 			sponsorSlide.Line1 = r.PostFormValue("line1")
 			sponsorSlide.Line2 = r.PostFormValue("line2")
-			sponsorSlide.Image = r.PostFormValue("image")
+			sponsorSlide.Image = image
 			sponsorSlide.DisplayTimeSec = displayTimeSec
+			sponsorSlide.ActiveStartTime = activeStartTime
+			sponsorSlide.ActiveEndTime = activeEndTime
 			err = web.arena.Database.UpdateSponsorSlide(sponsorSlide)
 		}
 		if err != nil {
@@ -144,3 +184,43 @@ func (web *Web) reorderSponsorSlide(id int, moveUp bool) error {
 
 	return nil
 }
+
+// Parses the given time in the given time zone from the sponsor slide form, returning the zero time if it is blank.
+func parseSponsorSlideTime(value string, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(sponsorSlideTimeFormat, value, location)
+}
+
+// Writes the sponsor image or video uploaded along with the slide to the sponsor media directory, and returns its file
+// name, or an empty string if no file was uploaded.
+func saveUploadedSponsorMedia(r *http.Request) (string, error) {
+	file, fileHeader, err := r.FormFile("mediaFile")
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fileName := filepath.Base(fileHeader.Filename)
+	extension := strings.ToLower(filepath.Ext(fileName))
+	if !slices.Contains(sponsorImageExtensions, extension) && !model.IsVideoFile(fileName) {
+		return "", fmt.Errorf("File '%s' is not a supported image or video type.", fileName)
+	}
+	mediaDir := filepath.Join(model.BaseDir, sponsorMediaDir)
+	if err = os.MkdirAll(mediaDir, 0755); err != nil {
+		return "", err
+	}
+	mediaFile, err := os.Create(filepath.Join(mediaDir, fileName))
+	if err != nil {
+		return "", err
+	}
+	defer mediaFile.Close()
+	if _, err = io.Copy(mediaFile, file); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
@@ -4,18 +4,25 @@
 package web
 
 import (
+	"bytes"
 	"github.com/Team254/cheesy-arena/model"
 	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestSetupSponsorSlides(t *testing.T) {
 	web := setupTestWeb(t)
 
 	web.arena.Database.CreateSponsorSlide(&model.SponsorSlide{0, "Subtitle", "Sponsor Line 1", "Sponsor Line 2", "", 10,
-		0})
+		0, time.Time{}, time.Time{}, false})
 	web.arena.Database.CreateSponsorSlide(&model.SponsorSlide{0, "Subtitle", "", "", "Image.gif", 10,
-		1})
+		1, time.Time{}, time.Time{}, false})
 
 	recorder := web.getHttpResponse("/setup/sponsor_slides")
 	assert.Equal(t, 200, recorder.Code)
@@ -62,3 +69,64 @@ func TestSetupSponsorSlides(t *testing.T) {
 		assert.Equal(t, sponsorSlides1[1].Id, sponsorSlides3[1].Id)
 	}
 }
+
+func TestSetupSponsorSlidesMediaAndActiveWindow(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Point the media directory elsewhere for the upload, so as not to leave the file behind in the source tree.
+	baseDir := model.BaseDir
+	model.BaseDir = t.TempDir()
+	recorder := web.postSponsorSlide(
+		map[string]string{"action": "save", "displayTimeSec": "15", "activeStartTime": "2024-04-06T09:00",
+			"activeEndTime": "2024-04-06T12:30"},
+		"promo.mp4",
+		"video data",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	sponsorSlide, _ := web.arena.Database.GetSponsorSlideById(1)
+	if assert.NotNil(t, sponsorSlide) {
+		assert.Equal(t, "promo.mp4", sponsorSlide.Image)
+		assert.True(t, sponsorSlide.IsVideo)
+		assert.Equal(t, time.Date(2024, 4, 6, 9, 0, 0, 0, time.Local), sponsorSlide.ActiveStartTime.Local())
+		assert.Equal(t, time.Date(2024, 4, 6, 12, 30, 0, 0, time.Local), sponsorSlide.ActiveEndTime.Local())
+	}
+	media, err := os.ReadFile(filepath.Join(model.BaseDir, "static/img/sponsors/promo.mp4"))
+	assert.Nil(t, err)
+	assert.Equal(t, "video data", string(media))
+	model.BaseDir = baseDir
+
+	recorder = web.getHttpResponse("/setup/sponsor_slides")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `value="2024-04-06T09:00"`)
+	assert.Contains(t, recorder.Body.String(), `value="2024-04-06T12:30"`)
+
+	// Clearing the window should put the slide in the rotation at all times.
+	recorder = web.postHttpResponse("/setup/sponsor_slides", "action=save&id=1&image=promo.mp4&activeStartTime=")
+	assert.Equal(t, 303, recorder.Code)
+	sponsorSlide, _ = web.arena.Database.GetSponsorSlideById(1)
+	assert.True(t, sponsorSlide.ActiveStartTime.IsZero())
+	assert.True(t, sponsorSlide.ActiveEndTime.IsZero())
+
+	recorder = web.postSponsorSlide(map[string]string{"action": "save"}, "notes.txt", "text")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "not a supported image or video type")
+	recorder = web.postHttpResponse("/setup/sponsor_slides", "action=save&activeStartTime=tomorrow")
+	assert.Equal(t, 500, recorder.Code)
+}
+
+// Posts a request to save a sponsor slide with the given form fields and uploaded media file.
+func (web *Web) postSponsorSlide(fields map[string]string, fileName, contents string) *httptest.ResponseRecorder {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	part, _ := writer.CreateFormFile("mediaFile", fileName)
+	part.Write([]byte(contents))
+	writer.Close()
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/setup/sponsor_slides", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	web.newHandler().ServeHTTP(recorder, req)
+	return recorder
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for monitoring the queue of data being published to The Blue Alliance.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"net/http"
+)
+
+// Shows the publishing status of each kind of data.
+func (web *Web) tbaPublishingGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	template, err := web.parseFiles("templates/setup_tba_publishing.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Statuses []partner.TbaPublishStatus
+	}{web.arena.EventSettings, web.arena.TbaPublisher.Statuses()}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Queues the given kind of data to be published in the background.
+func (web *Web) tbaPublishingPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	kind := r.PathValue("kind")
+	if !partner.IsValidTbaPublishKind(kind) {
+		http.Error(w, fmt.Sprintf("Invalid publish kind '%s'.", kind), 400)
+		return
+	}
+	if !web.arena.EventSettings.TbaPublishingEnabled {
+		http.Error(w, "TBA publishing is not enabled", 500)
+		return
+	}
+	web.arena.TbaPublisher.Enqueue(kind)
+
+	http.Redirect(w, r, "/setup/tba_publishing", 303)
+}
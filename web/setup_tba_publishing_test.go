@@ -0,0 +1,40 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetupTbaPublishing(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/tba_publishing")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "publishing is not enabled")
+	assert.Contains(t, recorder.Body.String(), "Not Published")
+
+	recorder = web.postHttpResponse("/setup/tba_publishing/rankings", "")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "TBA publishing is not enabled")
+
+	web.arena.TbaClient.BaseUrl = "fakeurl"
+	web.arena.EventSettings.TbaPublishingEnabled = true
+	recorder = web.postHttpResponse("/setup/tba_publishing/blorpy", "")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid publish kind")
+	recorder = web.postHttpResponse("/setup/tba_publishing/rankings", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Eventually(t, func() bool {
+		return web.arena.TbaPublisher.Statuses()[2].FailedAttempts > 0
+	}, time.Second, time.Millisecond)
+
+	recorder = web.getHttpResponse("/setup/tba_publishing")
+	assert.Equal(t, 200, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "publishing is not enabled")
+	assert.Contains(t, recorder.Body.String(), "Failed")
+	assert.Contains(t, recorder.Body.String(), "fakeurl")
+}
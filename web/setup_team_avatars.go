@@ -0,0 +1,90 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for uploading and importing the avatar images shown alongside the teams on the displays.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/partner"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Stores an uploaded PNG image as the avatar of the given team.
+func (web *Web) teamAvatarPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	teamId, _ := strconv.Atoi(r.PathValue("id"))
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if team == nil {
+		http.Error(w, fmt.Sprintf("Error: No such team: %d", teamId), 400)
+		return
+	}
+
+	file, _, err := r.FormFile("avatarFile")
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("No avatar file was specified."))
+		return
+	}
+	defer file.Close()
+	avatarBytes, err := io.ReadAll(io.LimitReader(file, partner.MaxAvatarBytes+1))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if err = partner.SaveTeamAvatar(teamId, avatarBytes); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Refresh the displays in case the team is in the current match.
+	web.arena.MatchLoadNotifier.Notify()
+	http.Redirect(w, r, fmt.Sprintf("/setup/teams/%d/edit", teamId), 303)
+}
+
+// Removes the avatar of the given team so that the displays fall back to the default image.
+func (web *Web) teamAvatarDeletePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	teamId, _ := strconv.Atoi(r.PathValue("id"))
+	if err := partner.DeleteTeamAvatar(teamId); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	web.arena.MatchLoadNotifier.Notify()
+	http.Redirect(w, r, fmt.Sprintf("/setup/teams/%d/edit", teamId), 303)
+}
+
+// Stores the avatars contained in an uploaded file saved from the FIRST Events API, for when the server can't reach
+// the API itself.
+func (web *Web) teamsAvatarImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("avatarsFile")
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("No avatars file was specified."))
+		return
+	}
+	defer file.Close()
+	if _, err = partner.ImportFirstAvatars(file); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	web.arena.MatchLoadNotifier.Notify()
+	http.Redirect(w, r, "/setup/teams", 303)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupTeamAvatarUpload(t *testing.T) {
+	web := setupTestWeb(t)
+	useScratchWorkingDir(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	avatar := createTestAvatar(t)
+
+	recorder := web.getHttpResponse("/setup/teams/254/edit")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "<i>None</i>")
+
+	recorder = web.postFileHttpResponse("/setup/teams/254/avatar", "avatarFile", bytes.NewBuffer(avatar))
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "/setup/teams/254/edit", recorder.Header().Get("Location"))
+	assert.True(t, partner.HasTeamAvatar(254))
+	recorder = web.getHttpResponse("/setup/teams/254/edit")
+	assert.Contains(t, recorder.Body.String(), "src=\"/api/teams/254/avatar\"")
+	recorder = web.getHttpResponse("/api/teams/254/avatar")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, avatar, recorder.Body.Bytes())
+
+	// Check that only PNG images are accepted and only for teams in the list.
+	recorder = web.postFileHttpResponse("/setup/teams/254/avatar", "avatarFile", bytes.NewBufferString("GIF89a"))
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Avatar for team 254 is not a valid PNG image.")
+	recorder = web.postFileHttpResponse("/setup/teams/1114/avatar", "avatarFile", bytes.NewBuffer(avatar))
+	assert.Equal(t, 400, recorder.Code)
+	assert.False(t, partner.HasTeamAvatar(1114))
+
+	recorder = web.postHttpResponse("/setup/teams/254/avatar/delete", "")
+	assert.Equal(t, 303, recorder.Code)
+	assert.False(t, partner.HasTeamAvatar(254))
+}
+
+func TestSetupTeamsAvatarImport(t *testing.T) {
+	web := setupTestWeb(t)
+	useScratchWorkingDir(t)
+
+	avatarsJson := fmt.Sprintf(
+		`{"teams": [{"teamNumber": 254, "encodedAvatar": "%s"}], "pageCurrent": 1, "pageTotal": 1}`,
+		base64.StdEncoding.EncodeToString(createTestAvatar(t)),
+	)
+	recorder := web.postFileHttpResponse(
+		"/setup/teams/avatar_import", "avatarsFile", bytes.NewBufferString(avatarsJson),
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.True(t, partner.HasTeamAvatar(254))
+
+	recorder = web.postFileHttpResponse("/setup/teams/avatar_import", "avatarsFile", bytes.NewBufferString("[]"))
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The avatars file doesn't contain any avatars.")
+}
+
+// Runs the test from a scratch directory so that any stored avatars don't end up in the source tree, while still
+// loading the templates from it.
+func useScratchWorkingDir(t *testing.T) {
+	workingDir, _ := os.Getwd()
+	originalBaseDir := model.BaseDir
+	baseDir, err := filepath.Abs(model.BaseDir)
+	assert.Nil(t, err)
+	model.BaseDir = baseDir
+	assert.Nil(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		os.Chdir(workingDir)
+		model.BaseDir = originalBaseDir
+	})
+}
+
+func createTestAvatar(t *testing.T) []byte {
+	var buffer bytes.Buffer
+	assert.Nil(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 40, 40))))
+	return buffer.Bytes()
+}
@@ -8,7 +8,9 @@ package web
 import (
 	"bytes"
 	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
 	"github.com/dchest/uniuri"
 	"net/http"
 	"regexp"
@@ -22,13 +24,19 @@ const wpaKeyLength = 8
 // Global var to hold the team download progress percentage.
 var progressPercentage float64 = 5
 
+// Outcome of importing a team list file, for showing to the user when some of the rows couldn't be fully imported.
+type teamImportResult struct {
+	ImportedCount int
+	Errors        []string
+}
+
 // Shows the team list.
 func (web *Web) teamsGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
 		return
 	}
 
-	web.renderTeams(w, r, false)
+	web.renderTeams(w, r, false, nil)
 }
 
 // Adds teams to the team list.
@@ -38,7 +46,7 @@ func (web *Web) teamsPostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, true, nil)
 		return
 	}
 
@@ -72,6 +80,76 @@ func (web *Web) teamsPostHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
+// Adds the teams listed in an uploaded CSV or TSV file to the team list, skipping any invalid rows or teams that are
+// already in the list and filling in any missing details from TBA if enabled.
+func (web *Web) teamsCsvImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if !web.canModifyTeamList() {
+		web.renderTeams(w, r, true, nil)
+		return
+	}
+
+	file, _, err := r.FormFile("csvFile")
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("No team list file was specified."))
+		return
+	}
+	defer file.Close()
+	rows, err := model.ParseTeamImportCsv(file)
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("Failed to parse team list file: %s.", err.Error()))
+		return
+	}
+
+	importResult := new(teamImportResult)
+	progressPercentage = 5
+	progressIncrement := 95.0 / float64(len(rows))
+	for _, row := range rows {
+		progressPercentage += progressIncrement
+		if row.Error != "" {
+			importResult.addError(row.LineNumber, "%s.", row.Error)
+			continue
+		}
+		existingTeam, err := web.arena.Database.GetTeamById(row.Team.Id)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if existingTeam != nil {
+			importResult.addError(row.LineNumber, "Team %d is already in the team list.", row.Team.Id)
+			continue
+		}
+
+		team := row.Team
+		if web.arena.EventSettings.TbaDownloadEnabled && (team.Nickname == "" || team.RookieYear == 0) {
+			// TBA might not be reachable from the event network, so import the team with whatever details were given.
+			if err = web.populateMissingTeamInfo(&team); err != nil {
+				importResult.addError(
+					row.LineNumber,
+					"Team %d was added, but its details couldn't be downloaded from TBA: %v.",
+					team.Id,
+					err,
+				)
+			}
+		}
+		if err = web.arena.Database.CreateTeam(&team); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		importResult.ImportedCount++
+	}
+	progressPercentage = 100
+
+	if len(importResult.Errors) > 0 {
+		web.renderTeams(w, r, false, importResult)
+		return
+	}
+	http.Redirect(w, r, "/setup/teams", 303)
+}
+
 // Re-downloads the data for all teams from TBA and overwrites any local edits.
 func (web *Web) teamsRefreshHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -104,6 +182,101 @@ func (web *Web) teamsRefreshHandler(w http.ResponseWriter, r *http.Request) {
 	progressPercentage = 5
 }
 
+// Imports the official team list for the event from the FIRST Events API, adding any teams that aren't already in the
+// list and overwriting the registration data of those that are.
+func (web *Web) teamsFirstImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if !web.canModifyTeamList() {
+		web.renderTeams(w, r, true, nil)
+		return
+	}
+	if !web.arena.FirstClient.IsConfigured() {
+		handleWebErr(w, fmt.Errorf("The FIRST Events API must be configured on the settings page first."))
+		return
+	}
+
+	season := time.Now().Year()
+	firstTeams, err := web.arena.FirstClient.GetEventTeams(season)
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("Failed to get teams from the FIRST Events API: %v", err))
+		return
+	}
+	for _, firstTeam := range firstTeams {
+		team, err := web.arena.Database.GetTeamById(firstTeam.TeamNumber)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if team == nil {
+			team = new(model.Team)
+			firstTeam.PopulateTeam(team)
+			err = web.arena.Database.CreateTeam(team)
+		} else {
+			firstTeam.PopulateTeam(team)
+			err = web.arena.Database.UpdateTeam(team)
+		}
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	// Avatars are nice to have, so don't fail the import if they can't be downloaded.
+	if _, err = web.arena.FirstClient.DownloadEventAvatars(season); err != nil {
+		logging.Partner.Error("Failed to download avatars from the FIRST Events API.", "error", err)
+	}
+
+	http.Redirect(w, r, "/setup/teams", 303)
+}
+
+// Imports announcer notes for existing teams from an uploaded CSV file.
+func (web *Web) teamsAnnouncerNotesPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("csvFile")
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("No announcer notes file was specified."))
+		return
+	}
+	defer file.Close()
+	notes, err := model.ParseAnnouncerNotesCsv(file)
+	if err != nil {
+		handleWebErr(w, fmt.Errorf("Failed to parse announcer notes file: %s.", err.Error()))
+		return
+	}
+
+	// Check that all the teams exist before updating any of them.
+	var teams []*model.Team
+	for teamId, teamNotes := range notes {
+		team, err := web.arena.Database.GetTeamById(teamId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if team == nil {
+			handleWebErr(w, fmt.Errorf("Team %d is not in the team list.", teamId))
+			return
+		}
+		team.AnnouncerNotes = teamNotes
+		teams = append(teams, team)
+	}
+	for _, team := range teams {
+		if err = web.arena.Database.UpdateTeam(team); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	// Refresh the announcer display in case any of the teams in the current match were updated.
+	web.arena.MatchLoadNotifier.Notify()
+	http.Redirect(w, r, "/setup/teams", 303)
+}
+
 // Clears the team list.
 func (web *Web) teamsClearHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -111,7 +284,7 @@ func (web *Web) teamsClearHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, true, nil)
 		return
 	}
 
@@ -120,6 +293,10 @@ func (web *Web) teamsClearHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	if err = web.arena.Database.TruncateTeamStatuses(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
@@ -148,7 +325,8 @@ func (web *Web) teamEditGetHandler(w http.ResponseWriter, r *http.Request) {
 	data := struct {
 		*model.EventSettings
 		*model.Team
-	}{web.arena.EventSettings, team}
+		HasAvatar bool
+	}{web.arena.EventSettings, team, partner.HasTeamAvatar(team.Id)}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -173,6 +351,7 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	oldTeam := *team
 	team.Name = r.PostFormValue("name")
 	team.Nickname = r.PostFormValue("nickname")
 	team.City = r.PostFormValue("city")
@@ -181,6 +360,7 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 	team.RookieYear, _ = strconv.Atoi(r.PostFormValue("rookieYear"))
 	team.RobotName = r.PostFormValue("robotName")
 	team.Accomplishments = r.PostFormValue("accomplishments")
+	team.AnnouncerNotes = r.PostFormValue("announcerNotes")
 	if web.arena.EventSettings.NetworkSecurityEnabled {
 		team.WpaKey = r.PostFormValue("wpaKey")
 		if len(team.WpaKey) < 8 || len(team.WpaKey) > 63 {
@@ -188,12 +368,15 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	team.HasConnected = r.PostFormValue("hasConnected") == "on"
+	recordAuditChanges(r, oldTeam, *team)
 	err = web.arena.Database.UpdateTeam(team)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
+
+	// Re-check the on-deck match in case this team is in it.
+	web.arena.UpdateOnDeckMatch()
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
@@ -204,7 +387,7 @@ func (web *Web) teamDeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !web.canModifyTeamList() {
-		web.renderTeams(w, r, true)
+		web.renderTeams(w, r, true, nil)
 		return
 	}
 
@@ -223,6 +406,10 @@ func (web *Web) teamDeletePostHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
+	if err = web.arena.Database.DeleteTeamStatus(team.Id); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
@@ -258,7 +445,9 @@ func (web *Web) teamsUpdateProgressBarHandler(w http.ResponseWriter, r *http.Req
 	_, _ = w.Write([]byte(fmt.Sprintf("%.0f", progressPercentage)))
 }
 
-func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMessage bool) {
+func (web *Web) renderTeams(
+	w http.ResponseWriter, r *http.Request, showErrorMessage bool, importResult *teamImportResult,
+) {
 	teams, err := web.arena.Database.GetAllTeams()
 	if err != nil {
 		handleWebErr(w, err)
@@ -274,7 +463,8 @@ func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMes
 		*model.EventSettings
 		Teams            []model.Team
 		ShowErrorMessage bool
-	}{web.arena.EventSettings, teams, showErrorMessage}
+		ImportResult     *teamImportResult
+	}{web.arena.EventSettings, teams, showErrorMessage, importResult}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -339,3 +529,33 @@ func (web *Web) populateOfficialTeamInfo(team *model.Team) error {
 
 	return nil
 }
+
+// Fills in any details of the given team that weren't given when it was imported using the data from TBA.
+func (web *Web) populateMissingTeamInfo(team *model.Team) error {
+	tbaTeam := model.Team{Id: team.Id}
+	if err := web.populateOfficialTeamInfo(&tbaTeam); err != nil {
+		return err
+	}
+
+	fillBlank := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fillBlank(&team.Name, tbaTeam.Name)
+	fillBlank(&team.Nickname, tbaTeam.Nickname)
+	fillBlank(&team.City, tbaTeam.City)
+	fillBlank(&team.StateProv, tbaTeam.StateProv)
+	fillBlank(&team.Country, tbaTeam.Country)
+	fillBlank(&team.SchoolName, tbaTeam.SchoolName)
+	fillBlank(&team.RobotName, tbaTeam.RobotName)
+	fillBlank(&team.Accomplishments, tbaTeam.Accomplishments)
+	if team.RookieYear == 0 {
+		team.RookieYear = tbaTeam.RookieYear
+	}
+	return nil
+}
+
+func (result *teamImportResult) addError(lineNumber int, format string, args ...any) {
+	result.Errors = append(result.Errors, fmt.Sprintf("Line %d: ", lineNumber)+fmt.Sprintf(format, args...))
+}
@@ -4,8 +4,10 @@
 package web
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -104,10 +106,12 @@ func TestSetupTeams(t *testing.T) {
 	recorder = web.getHttpResponse("/setup/teams/254/edit")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "The Cheesy Poofs")
-	recorder = web.postHttpResponse("/setup/teams/254/edit", "nickname=Teh Chezy Pofs")
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "nickname=Teh Chezy Pofs&announcerNotes=Likes+cheese")
 	assert.Equal(t, 303, recorder.Code)
 	recorder = web.getHttpResponse("/setup/teams")
 	assert.Contains(t, recorder.Body.String(), "Teh Chezy Pofs")
+	recorder = web.getHttpResponse("/setup/teams/254/edit")
+	assert.Contains(t, recorder.Body.String(), "Likes cheese")
 
 	// Re-download team info from TBA.
 	recorder = web.getHttpResponse("/setup/teams/refresh")
@@ -176,6 +180,93 @@ func TestSetupTeamsBadReqest(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "No such team")
 }
 
+func TestSetupTeamsAnnouncerNotes(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114, AnnouncerNotes: "Old notes"})
+
+	// Check that nothing is updated if any of the teams are unknown.
+	recorder := web.postFileHttpResponse(
+		"/setup/teams/announcer_notes", "csvFile", bytes.NewBufferString("254,Poofs\n1503,Spartonics\n"),
+	)
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 1503 is not in the team list.")
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, "", team.AnnouncerNotes)
+
+	recorder = web.postFileHttpResponse(
+		"/setup/teams/announcer_notes", "csvFile", bytes.NewBufferString("Team,Notes\n254,\"Won Einstein, twice\"\n"),
+	)
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.Equal(t, "Won Einstein, twice", team.AnnouncerNotes)
+	team, _ = web.arena.Database.GetTeamById(1114)
+	assert.Equal(t, "Old notes", team.AnnouncerNotes)
+
+	recorder = web.postFileHttpResponse("/setup/teams/announcer_notes", "csvFile", bytes.NewBufferString("254\n"))
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Missing notes for team 254 on line 1")
+}
+
+func TestSetupTeamsCsvImport(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114})
+
+	// Mock TBA so that it only knows about one of the teams.
+	tbaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.RequestURI, "frc254") {
+			if strings.Contains(r.RequestURI, "robots") || strings.Contains(r.RequestURI, "awards") {
+				fmt.Fprintln(w, "[]")
+			} else {
+				fmt.Fprintln(w, `{"team_number": 254, "nickname": "The Cheesy Poofs", "rookie_year": 1999}`)
+			}
+		} else {
+			http.Error(w, "Unexpected request during test", 500)
+		}
+	}))
+	defer tbaServer.Close()
+	web.arena.TbaClient.BaseUrl = tbaServer.URL
+
+	recorder := web.postFileHttpResponse(
+		"/setup/teams/csv_import",
+		"csvFile",
+		bytes.NewBufferString("Team,Nickname,City\n254,,San Jose\n1114,Simbotics\nabc\n973,Greybots\n254,Poofs\n"),
+	)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Added 2 teams from the file.")
+	assert.Contains(t, recorder.Body.String(), "Line 3: Team 1114 is already in the team list.")
+	assert.Contains(t, recorder.Body.String(), "Line 4: Invalid team number 'abc'.")
+	assert.Contains(t, recorder.Body.String(), "Line 5: Team 973 was added, but its details couldn't be downloaded")
+	assert.Contains(t, recorder.Body.String(), "Line 6: Team 254 is already listed on line 2.")
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, model.Team{Id: 254, Nickname: "The Cheesy Poofs", City: "San Jose", RookieYear: 1999}, *team)
+	team, _ = web.arena.Database.GetTeamById(973)
+	assert.Equal(t, model.Team{Id: 973, Nickname: "Greybots"}, *team)
+	team, _ = web.arena.Database.GetTeamById(1114)
+	assert.Equal(t, "", team.Nickname)
+
+	// Check that a clean import redirects back to the team list.
+	web.arena.EventSettings.TbaDownloadEnabled = false
+	recorder = web.postFileHttpResponse(
+		"/setup/teams/csv_import", "csvFile", bytes.NewBufferString("33\t\tKiller Bees\n"),
+	)
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(33)
+	assert.Equal(t, model.Team{Id: 33, Name: "Killer Bees"}, *team)
+
+	recorder = web.postFileHttpResponse("/setup/teams/csv_import", "csvFile", bytes.NewBufferString("Name\nPoofs\n"))
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "The header row doesn't have a team number column")
+
+	// Check that the team list can't be changed once the schedule exists.
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, ShortName: "Q1"})
+	recorder = web.postFileHttpResponse("/setup/teams/csv_import", "csvFile", bytes.NewBufferString("4414\n"))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "can't modify")
+	team, _ = web.arena.Database.GetTeamById(4414)
+	assert.Nil(t, team)
+}
+
 func TestSetupTeamsWpaKeys(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -216,3 +307,50 @@ func TestSetupTeamsProgress(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Equal(t, "25", recorder.Body.String())
 }
+
+func TestSetupTeamsFirstImport(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "Old nickname", WpaKey: "12345678"})
+
+	recorder := web.postHttpResponse("/setup/teams/first_import", "")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "must be configured on the settings page")
+
+	// Mock the FIRST Events API.
+	teamsBody := `{"teams": [
+		{"teamNumber": 254, "nameFull": "NASA Ames & Bellarmine", "nameShort": "The Cheesy Poofs", "city": "San Jose",
+			"stateProv": "California", "country": "USA", "rookieYear": 1999, "robotName": "Barry",
+			"schoolName": "Bellarmine College Preparatory"},
+		{"teamNumber": 1114, "nameShort": "Simbotics", "rookieYear": 2003}
+	], "pageCurrent": 1, "pageTotal": 1}`
+	firstServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.RequestURI, "/teams") {
+			fmt.Fprintln(w, teamsBody)
+		} else if strings.Contains(r.RequestURI, "/avatars") {
+			fmt.Fprintln(w, `{"teams": [], "pageCurrent": 1, "pageTotal": 1}`)
+		} else {
+			http.Error(w, "Unexpected request during test", 500)
+		}
+	}))
+	defer firstServer.Close()
+	web.arena.FirstClient = partner.NewFirstClient("user", "key", "CASJ")
+	web.arena.FirstClient.BaseUrl = firstServer.URL
+
+	recorder = web.postHttpResponse("/setup/teams/first_import", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, "The Cheesy Poofs", team.Nickname)
+	assert.Equal(t, "Bellarmine College Preparatory", team.SchoolName)
+	assert.Equal(t, "Barry", team.RobotName)
+	assert.Equal(t, "12345678", team.WpaKey)
+	team, _ = web.arena.Database.GetTeamById(1114)
+	if assert.NotNil(t, team) {
+		assert.Equal(t, "Simbotics", team.Nickname)
+		assert.Equal(t, 2003, team.RookieYear)
+	}
+
+	// Check that the import is disallowed once the schedule exists.
+	web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification})
+	recorder = web.postHttpResponse("/setup/teams/first_import", "")
+	assert.Contains(t, recorder.Body.String(), "can't modify the team list")
+}
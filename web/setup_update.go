@@ -0,0 +1,88 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for checking for a newer build of the server and staging it to be applied between matches.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/updater"
+	"net/http"
+)
+
+// Shows the running version, the latest release and its changelog, and the progress of any update.
+func (web *Web) updateGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderUpdate(w, r, "")
+}
+
+// Checks the release endpoint for a newer build right away rather than waiting for the next periodic check.
+func (web *Web) updateCheckPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if err := web.arena.Updater.Check(); err != nil {
+		web.renderUpdate(w, r, fmt.Sprintf("Failed to check for an update: %v", err))
+		return
+	}
+	http.Redirect(w, r, "/setup/update", 303)
+}
+
+// Downloads the latest release so that it is ready to be applied.
+func (web *Web) updateDownloadPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if err := web.arena.Updater.Stage(); err != nil {
+		web.renderUpdate(w, r, fmt.Sprintf("Failed to download the update: %v", err))
+		return
+	}
+	http.Redirect(w, r, "/setup/update", 303)
+}
+
+// Sets whether the downloaded release is to be applied, followed by a restart, at the next break between matches.
+func (web *Web) updateSchedulePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if err := web.arena.Updater.ScheduleApply(r.PostFormValue("scheduled") == "true"); err != nil {
+		web.renderUpdate(w, r, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/setup/update", 303)
+}
+
+func (web *Web) renderUpdate(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_update.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		updater.Status
+		CheckingEnabled bool
+		MatchInProgress bool
+		ErrorMessage    string
+	}{
+		web.arena.EventSettings,
+		web.arena.Updater.Status(),
+		web.arena.Updater.IsEnabled(),
+		web.arena.MatchState != field.PreMatch,
+		errorMessage,
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/updater"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupUpdate(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/update")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "running version <b>development</b>")
+	assert.Contains(t, recorder.Body.String(), "Checking for updates has been turned off")
+
+	server := updater.NewTestReleaseServer(t, "v2024.2.0", nil)
+	web.arena.Updater = updater.NewUpdater(server.URL+"/releases/latest", "v2024.1.0", t.TempDir())
+	recorder = web.getHttpResponse("/setup/update")
+	assert.Contains(t, recorder.Body.String(), "hasn't checked for a newer release yet")
+	assert.NotContains(t, recorder.Body.String(), "Latest release")
+
+	recorder = web.postHttpResponse("/setup/update/check", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	recorder = web.getHttpResponse("/setup/update")
+	assert.Contains(t, recorder.Body.String(), "Latest release: v2024.2.0")
+	assert.Contains(t, recorder.Body.String(), "* Fixed &lt;everything&gt;")
+	assert.Contains(t, recorder.Body.String(), "/setup/update/download")
+
+	recorder = web.postHttpResponse("/setup/update/download", "")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.NotNil(t, web.arena.Updater.Status().StagedRelease)
+	recorder = web.getHttpResponse("/setup/update")
+	assert.Contains(t, recorder.Body.String(), "Apply at Next Break")
+
+	web.arena.MatchState = field.TeleopPeriod
+	recorder = web.postHttpResponse("/setup/update/schedule", "scheduled=true")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.True(t, web.arena.Updater.ApplyScheduled())
+	recorder = web.getHttpResponse("/setup/update")
+	assert.Contains(t, recorder.Body.String(), "as soon as the current match is over")
+
+	recorder = web.postHttpResponse("/setup/update/schedule", "scheduled=false")
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.False(t, web.arena.Updater.ApplyScheduled())
+}
+
+func TestSetupUpdateErrors(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/update/check", "")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Failed to check for an update: Checking for updates is disabled.")
+	recorder = web.postHttpResponse("/setup/update/download", "")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "There is no newer release to download.")
+	recorder = web.postHttpResponse("/setup/update/schedule", "scheduled=true")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "No update has been downloaded yet.")
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for managing the volunteers' login accounts and the roles that determine which pages they can access.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Shows the user account management page.
+func (web *Web) usersGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderUsers(w, r, "")
+}
+
+// Creates, updates or deletes a user account.
+func (web *Web) usersPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	role := r.PostFormValue("role")
+	if r.PostFormValue("action") != "delete" && !slices.Contains(model.UserRoles, role) {
+		web.renderUsers(w, r, fmt.Sprintf("Invalid role '%s'.", role))
+		return
+	}
+	password := r.PostFormValue("password")
+
+	switch r.PostFormValue("action") {
+	case "create":
+		username := strings.TrimSpace(r.PostFormValue("username"))
+		if username == "" || strings.EqualFold(username, adminUser) {
+			web.renderUsers(w, r, fmt.Sprintf("Invalid username '%s'.", username))
+			return
+		}
+		existingAccount, err := web.arena.Database.GetUserAccountByUsername(username)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if existingAccount != nil {
+			web.renderUsers(w, r, fmt.Sprintf("User '%s' already exists.", username))
+			return
+		}
+		if password == "" {
+			web.renderUsers(w, r, "A password is required for new users.")
+			return
+		}
+		userAccount := model.UserAccount{Username: username, Role: role}
+		if err = userAccount.SetPassword(password); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if err = web.arena.Database.CreateUserAccount(&userAccount); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "update":
+		userAccount, ok := web.getUserAccountForPost(w, r)
+		if !ok {
+			return
+		}
+		oldUserAccount := *userAccount
+		userAccount.Role = role
+		if password != "" {
+			if err := userAccount.SetPassword(password); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+		recordAuditChanges(r, oldUserAccount, *userAccount)
+		if err := web.arena.Database.UpdateUserAccount(userAccount); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "delete":
+		userAccount, ok := web.getUserAccountForPost(w, r)
+		if !ok {
+			return
+		}
+		if err := web.arena.Database.DeleteUserAccount(userAccount.Id); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+
+		// Revoke any panel tablets that the user enrolled, since they would otherwise carry on with the user's role.
+		panelDevices, err := web.arena.Database.GetAllPanelDevices()
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, panelDevice := range panelDevices {
+			if strings.EqualFold(panelDevice.Username, userAccount.Username) {
+				if err = web.arena.Database.DeletePanelDevice(panelDevice.Id); err != nil {
+					handleWebErr(w, err)
+					return
+				}
+			}
+		}
+	default:
+		web.renderUsers(w, r, fmt.Sprintf("Invalid action '%s'.", r.PostFormValue("action")))
+		return
+	}
+
+	http.Redirect(w, r, "/setup/users", 303)
+}
+
+// Returns the user account given by the ID in the posted form, or renders an error if it doesn't exist.
+func (web *Web) getUserAccountForPost(w http.ResponseWriter, r *http.Request) (*model.UserAccount, bool) {
+	userAccountId, _ := strconv.Atoi(r.PostFormValue("id"))
+	userAccount, err := web.arena.Database.GetUserAccountById(userAccountId)
+	if err != nil {
+		handleWebErr(w, err)
+		return nil, false
+	}
+	if userAccount == nil {
+		web.renderUsers(w, r, fmt.Sprintf("No such user: %d", userAccountId))
+		return nil, false
+	}
+	return userAccount, true
+}
+
+func (web *Web) renderUsers(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_users.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	userAccounts, err := web.arena.Database.GetAllUserAccounts()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		UserAccounts  []model.UserAccount
+		UserRoles     []string
+		UserRoleNames map[string]string
+		ErrorMessage  string
+	}{web.arena.EventSettings, userAccounts, model.UserRoles, model.UserRoleNames, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetupUsers(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/users")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Logins aren't required until an admin password is set")
+
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Bertha&password=whistle&role=referee")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Alfred&password=lights&role=av")
+	assert.Equal(t, 303, recorder.Code)
+	userAccounts, _ := web.arena.Database.GetAllUserAccounts()
+	if assert.Equal(t, 2, len(userAccounts)) {
+		assert.Equal(t, "Bertha", userAccounts[0].Username)
+		assert.Equal(t, model.RefereeRole, userAccounts[0].Role)
+		assert.True(t, userAccounts[0].CheckPassword("whistle"))
+		assert.Equal(t, model.AvRole, userAccounts[1].Role)
+	}
+	recorder = web.getHttpResponse("/setup/users")
+	assert.Contains(t, recorder.Body.String(), "Bertha")
+	assert.NotContains(t, recorder.Body.String(), "whistle")
+
+	// Check the validation of new users.
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=bertha&password=a&role=referee")
+	assert.Contains(t, recorder.Body.String(), "User 'bertha' already exists.")
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Admin&password=a&role=referee")
+	assert.Contains(t, recorder.Body.String(), "Invalid username 'Admin'.")
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Carol&password=&role=referee")
+	assert.Contains(t, recorder.Body.String(), "A password is required for new users.")
+	recorder = web.postHttpResponse("/setup/users", "action=create&username=Carol&password=a&role=blorpy")
+	assert.Contains(t, recorder.Body.String(), "Invalid role 'blorpy'.")
+
+	// Change a user's role while keeping their password, and then their password.
+	recorder = web.postHttpResponse(
+		"/setup/users", fmt.Sprintf("action=update&id=%d&role=fta&password=", userAccounts[0].Id),
+	)
+	assert.Equal(t, 303, recorder.Code)
+	userAccount, _ := web.arena.Database.GetUserAccountById(userAccounts[0].Id)
+	assert.Equal(t, model.FtaRole, userAccount.Role)
+	assert.True(t, userAccount.CheckPassword("whistle"))
+	recorder = web.postHttpResponse(
+		"/setup/users", fmt.Sprintf("action=update&id=%d&role=fta&password=radio", userAccounts[0].Id),
+	)
+	assert.Equal(t, 303, recorder.Code)
+	userAccount, _ = web.arena.Database.GetUserAccountById(userAccounts[0].Id)
+	assert.True(t, userAccount.CheckPassword("radio"))
+
+	recorder = web.postHttpResponse("/setup/users", fmt.Sprintf("action=delete&id=%d", userAccounts[1].Id))
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/users", fmt.Sprintf("action=delete&id=%d", userAccounts[1].Id))
+	assert.Contains(t, recorder.Body.String(), "No such user")
+	userAccounts, _ = web.arena.Database.GetAllUserAccounts()
+	assert.Equal(t, 1, len(userAccounts))
+}
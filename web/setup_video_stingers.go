@@ -0,0 +1,141 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for managing the video stingers played on the audience display.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Directory under which uploaded video stingers are stored and served from.
+const videoStingerDir = "static/video/stingers"
+
+// Shows the video stingers configuration page.
+func (web *Web) videoStingersGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	web.renderVideoStingers(w, r, "")
+}
+
+// Saves a new or modified video stinger to the database, or deletes an existing one.
+func (web *Web) videoStingersPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	videoStingerId, _ := strconv.Atoi(r.PostFormValue("id"))
+	videoStinger, err := web.arena.Database.GetVideoStingerById(videoStingerId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	switch r.PostFormValue("action") {
+	case "delete":
+		if err = web.arena.Database.DeleteVideoStinger(videoStingerId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "save":
+		if videoStinger == nil {
+			videoStinger = &model.VideoStinger{}
+		}
+		videoStinger.Name = strings.TrimSpace(r.PostFormValue("name"))
+		videoStinger.NextScreen = r.PostFormValue("nextScreen")
+		uploadedVideo, err := saveUploadedVideoStinger(r)
+		if err != nil {
+			web.renderVideoStingers(w, r, err.Error())
+			return
+		}
+		if uploadedVideo != "" {
+			videoStinger.VideoFile = uploadedVideo
+		}
+		if videoStinger.VideoFile == "" {
+			web.renderVideoStingers(w, r, "A video file must be uploaded for the stinger.")
+			return
+		}
+		if videoStinger.Id == 0 {
+			err = web.arena.Database.CreateVideoStinger(videoStinger)
+		} else {
+			err = web.arena.Database.UpdateVideoStinger(videoStinger)
+		}
+		if err != nil {
+			web.renderVideoStingers(w, r, err.Error())
+			return
+		}
+	}
+
+	// Have the audience displays preload any new or changed videos.
+	web.arena.VideoStingerNotifier.Notify()
+	http.Redirect(w, r, "/setup/video_stingers", 303)
+}
+
+func (web *Web) renderVideoStingers(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_video_stingers.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	videoStingers, err := web.arena.Database.GetAllVideoStingers()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Append a blank stinger to the end that can be used to add a new one.
+	videoStingers = append(videoStingers, model.VideoStinger{NextScreen: "blank"})
+
+	data := struct {
+		*model.EventSettings
+		VideoStingers []model.VideoStinger
+		ScreenNames   map[string]string
+		ErrorMessage  string
+	}{web.arena.EventSettings, videoStingers, model.ShowFlowScreenNames, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Writes the video uploaded along with the stinger to the video stinger directory, and returns its file name, or an
+// empty string if no file was uploaded.
+func saveUploadedVideoStinger(r *http.Request) (string, error) {
+	file, fileHeader, err := r.FormFile("videoFile")
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fileName := filepath.Base(fileHeader.Filename)
+	if !model.IsVideoFile(fileName) {
+		return "", fmt.Errorf("File '%s' is not a supported video type.", fileName)
+	}
+	videoDir := filepath.Join(model.BaseDir, videoStingerDir)
+	if err = os.MkdirAll(videoDir, 0755); err != nil {
+		return "", err
+	}
+	videoFile, err := os.Create(filepath.Join(videoDir, fileName))
+	if err != nil {
+		return "", err
+	}
+	defer videoFile.Close()
+	if _, err = io.Copy(videoFile, file); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
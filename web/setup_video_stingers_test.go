@@ -0,0 +1,83 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"bytes"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupVideoStingers(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/video_stingers")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Video Stingers")
+
+	// Point the video directory elsewhere for the upload, so as not to leave the file behind in the source tree.
+	baseDir := model.BaseDir
+	model.BaseDir = t.TempDir()
+	recorder = web.postVideoStinger(
+		map[string]string{"action": "save", "name": "Match Start", "nextScreen": "match"}, "start.mp4", "video data",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	video, err := os.ReadFile(filepath.Join(model.BaseDir, "static/video/stingers/start.mp4"))
+	assert.Nil(t, err)
+	assert.Equal(t, "video data", string(video))
+	model.BaseDir = baseDir
+
+	recorder = web.postVideoStinger(map[string]string{"action": "save", "name": "Notes"}, "notes.txt", "text")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "not a supported video type")
+
+	videoStinger, _ := web.arena.Database.GetVideoStingerById(1)
+	if assert.NotNil(t, videoStinger) {
+		assert.Equal(t, model.VideoStinger{1, "Match Start", "start.mp4", "match"}, *videoStinger)
+	}
+	recorder = web.getHttpResponse("/setup/video_stingers")
+	assert.Contains(t, recorder.Body.String(), "Match Start")
+	assert.Contains(t, recorder.Body.String(), "start.mp4")
+
+	// Check that the existing video is kept if a new one isn't uploaded.
+	recorder = web.postHttpResponse("/setup/video_stingers", "action=save&id=1&name=Award Intro&nextScreen=logo")
+	assert.Equal(t, 303, recorder.Code)
+	videoStinger, _ = web.arena.Database.GetVideoStingerById(1)
+	assert.Equal(t, model.VideoStinger{1, "Award Intro", "start.mp4", "logo"}, *videoStinger)
+
+	recorder = web.postHttpResponse("/setup/video_stingers", "action=save&name=No Video&nextScreen=logo")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "A video file must be uploaded")
+	recorder = web.postHttpResponse("/setup/video_stingers", "action=save&id=1&name=&nextScreen=logo")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "name must not be blank")
+
+	recorder = web.postHttpResponse("/setup/video_stingers", "action=delete&id=1")
+	assert.Equal(t, 303, recorder.Code)
+	videoStingers, _ := web.arena.Database.GetAllVideoStingers()
+	assert.Empty(t, videoStingers)
+}
+
+// Posts a request to save a video stinger with the given form fields and uploaded video file.
+func (web *Web) postVideoStinger(fields map[string]string, fileName, contents string) *httptest.ResponseRecorder {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	part, _ := writer.CreateFormFile("videoFile", fileName)
+	part.Write([]byte(contents))
+	writer.Close()
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/setup/video_stingers", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	web.newHandler().ServeHTTP(recorder, req)
+	return recorder
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web routes for configuring the outgoing webhooks that are fired on arena events.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/partner"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Shows the webhook configuration page.
+func (web *Web) webhooksGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	web.renderWebhooks(w, r, "")
+}
+
+// Adds a new webhook, or deletes or sends a test event to an existing one.
+func (web *Web) webhooksPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	webhookId, _ := strconv.Atoi(r.PostFormValue("id"))
+	switch r.PostFormValue("action") {
+	case "create":
+		webhookUrl := strings.TrimSpace(r.PostFormValue("url"))
+		if parsedUrl, err := url.Parse(webhookUrl); err != nil || parsedUrl.Host == "" ||
+			(parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") {
+			web.renderWebhooks(w, r, fmt.Sprintf("'%s' is not a valid HTTP or HTTPS URL.", webhookUrl))
+			return
+		}
+		r.ParseForm()
+		var events []string
+		for _, event := range r.Form["events"] {
+			if slices.Contains(partner.WebhookEvents, event) {
+				events = append(events, event)
+			}
+		}
+		if len(events) == 0 {
+			web.renderWebhooks(w, r, "At least one event must be selected for the webhook.")
+			return
+		}
+		secret := strings.TrimSpace(r.PostFormValue("secret"))
+		if secret == "" {
+			secret = uuid.New().String()
+		}
+		webhook := model.Webhook{Url: webhookUrl, Secret: secret, Events: events}
+		if err := web.arena.Database.CreateWebhook(&webhook); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "delete":
+		if err := web.arena.Database.DeleteWebhook(webhookId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	case "test":
+		webhook, err := web.arena.Database.GetWebhookById(webhookId)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if webhook == nil {
+			web.renderWebhooks(w, r, fmt.Sprintf("Webhook %d does not exist.", webhookId))
+			return
+		}
+		if err = partner.SendWebhook(webhook, "test", nil); err != nil {
+			web.renderWebhooks(w, r, err.Error())
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/setup/webhooks", 303)
+}
+
+func (web *Web) renderWebhooks(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	template, err := web.parseFiles("templates/setup_webhooks.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	webhooks, err := web.arena.Database.GetAllWebhooks()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Webhooks      []model.Webhook
+		WebhookEvents []string
+		ErrorMessage  string
+	}{web.arena.EventSettings, webhooks, partner.WebhookEvents, errorMessage}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSetupWebhooks(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// Mock the webhook receiver.
+	var receivedEvents []string
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEvents = append(receivedEvents, r.URL.Path)
+	}))
+	defer webhookServer.Close()
+
+	recorder := web.getHttpResponse("/setup/webhooks")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "score_committed")
+
+	recorder = web.postHttpResponse("/setup/webhooks", "action=create&url=ftp://example.com&events=match_started")
+	assert.Contains(t, recorder.Body.String(), "is not a valid HTTP or HTTPS URL.")
+	recorder = web.postHttpResponse("/setup/webhooks", "action=create&url="+webhookServer.URL+"&events=blorpy")
+	assert.Contains(t, recorder.Body.String(), "At least one event must be selected")
+	recorder = web.postHttpResponse(
+		"/setup/webhooks",
+		"action=create&url="+webhookServer.URL+"/hook&secret=abc123&events=match_started&events=schedule_changed",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	recorder = web.postHttpResponse(
+		"/setup/webhooks", "action=create&url="+webhookServer.URL+"/other&events=score_committed",
+	)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+
+	webhooks, _ := web.arena.Database.GetAllWebhooks()
+	if assert.Equal(t, 2, len(webhooks)) {
+		assert.Equal(t, "abc123", webhooks[0].Secret)
+		assert.Equal(t, []string{"match_started", "schedule_changed"}, webhooks[0].Events)
+		assert.NotEqual(t, "", webhooks[1].Secret)
+		assert.Equal(t, []string{"score_committed"}, webhooks[1].Events)
+	}
+	recorder = web.getHttpResponse("/setup/webhooks")
+	assert.Contains(t, recorder.Body.String(), webhookServer.URL+"/hook")
+
+	recorder = web.postHttpResponse("/setup/webhooks", "action=test&id="+strconv.Itoa(webhooks[0].Id))
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	assert.Equal(t, []string{"/hook"}, receivedEvents)
+	recorder = web.postHttpResponse("/setup/webhooks", "action=test&id=99")
+	assert.Contains(t, recorder.Body.String(), "Webhook 99 does not exist.")
+
+	recorder = web.postHttpResponse("/setup/webhooks", "action=delete&id="+strconv.Itoa(webhooks[0].Id))
+	assert.Equal(t, 303, recorder.Code)
+	webhooks, _ = web.arena.Database.GetAllWebhooks()
+	if assert.Equal(t, 1, len(webhooks)) {
+		assert.Equal(t, []string{"score_committed"}, webhooks[0].Events)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the mobile-friendly public site through which spectators can follow the event.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/notification"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Number of matches following the current one to show on the spectator site.
+const numSpectatorUpcomingMatches = 3
+
+// Represents a match as shown on the spectator site.
+type spectatorMatch struct {
+	Id          int
+	ShortName   string
+	LongName    string
+	Time        time.Time
+	RedTeams    []int
+	BlueTeams   []int
+	RedScore    *int
+	BlueScore   *int
+	MatchesAway int // Or -1 if the match isn't among the upcoming matches of the type currently being played.
+}
+
+// Represents the state of the field as shown on the spectator site.
+type spectatorStatus struct {
+	EventName       string
+	Match           spectatorMatch
+	Status          string
+	MatchTimeSec    int
+	UpcomingMatches []spectatorMatch
+}
+
+// Represents a team's schedule as shown on the spectator site.
+type spectatorTeamSchedule struct {
+	TeamId   int
+	Nickname string
+	Ranking  *game.Ranking
+	Matches  []spectatorMatch
+}
+
+// Represents a browser's push subscription, along with the teams and topics that the subscriber wants to be notified
+// about.
+type spectatorPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+	TeamIds []int    `json:"teamIds"`
+	Topics  []string `json:"topics"`
+}
+
+// Renders the spectator site.
+func (web *Web) spectatorHandler(w http.ResponseWriter, r *http.Request) {
+	template, err := web.parseFiles("templates/spectator.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		MatchesAhead int
+	}{web.arena.EventSettings, field.SpectatorPushMatchesAhead}
+	err = template.ExecuteTemplate(w, "spectator.html", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON summary of the current match and those following it.
+func (web *Web) spectatorStatusApiHandler(w http.ResponseWriter, r *http.Request) {
+	upcomingMatches, err := web.arena.GetUpcomingMatches()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	status := spectatorStatus{
+		EventName:       web.arena.EventSettings.Name,
+		Match:           web.newSpectatorMatch(web.arena.CurrentMatch, nil, 0),
+		Status:          getSpectatorMatchStatus(web.arena.MatchState),
+		MatchTimeSec:    int(web.arena.MatchTimeSec()),
+		UpcomingMatches: []spectatorMatch{},
+	}
+	if web.arena.MatchState != field.PreMatch {
+		redScore := web.arena.RedScoreSummary().Score
+		blueScore := web.arena.BlueScoreSummary().Score
+		status.Match.RedScore = &redScore
+		status.Match.BlueScore = &blueScore
+	}
+	for i, match := range upcomingMatches {
+		if match.Id == web.arena.CurrentMatch.Id {
+			continue
+		}
+		status.UpcomingMatches = append(status.UpcomingMatches, web.newSpectatorMatch(&match, nil, i))
+		if len(status.UpcomingMatches) == numSpectatorUpcomingMatches {
+			break
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the given team's matches and ranking.
+func (web *Web) spectatorTeamScheduleApiHandler(w http.ResponseWriter, r *http.Request) {
+	teamId, err := strconv.Atoi(r.PathValue("teamId"))
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	team, err := web.arena.Database.GetTeamById(teamId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if team == nil {
+		http.Error(w, fmt.Sprintf("Team %d is not at this event.", teamId), 404)
+		return
+	}
+	upcomingMatches, err := web.arena.GetUpcomingMatches()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	matchesAway := make(map[int]int)
+	for i, match := range upcomingMatches {
+		matchesAway[match.Id] = i
+	}
+
+	schedule := spectatorTeamSchedule{TeamId: team.Id, Nickname: team.Nickname, Matches: []spectatorMatch{}}
+	if schedule.Ranking, err = web.arena.Database.GetRankingForTeam(team.Id); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	for _, matchType := range []model.MatchType{model.Practice, model.Qualification, model.Playoff} {
+		matches, err := web.arena.Database.GetMatchesByType(matchType, false)
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, match := range matches {
+			if !matchHasTeam(&match, team.Id) {
+				continue
+			}
+			var matchResult *model.MatchResult
+			if match.IsComplete() {
+				if matchResult, err = web.arena.Database.GetMatchResultForMatch(match.Id); err != nil {
+					handleWebErr(w, err)
+					return
+				}
+			}
+			away, ok := matchesAway[match.Id]
+			if !ok {
+				away = -1
+			}
+			schedule.Matches = append(schedule.Matches, web.newSpectatorMatch(&match, matchResult, away))
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the public key that browsers need in order to subscribe to the arena's push notifications.
+func (web *Web) spectatorPushKeyApiHandler(w http.ResponseWriter, r *http.Request) {
+	publicKey, err := web.arena.GetVapidPublicKey()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	jsonData, err := json.Marshal(struct{ PublicKey string }{publicKey})
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Saves the teams and topics that the browser having the given push subscription should be notified about, or deletes
+// the subscription if there are no longer any.
+func (web *Web) spectatorPushSubscriptionsApiHandler(w http.ResponseWriter, r *http.Request) {
+	var request spectatorPushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid push subscription: "+err.Error(), 400)
+		return
+	}
+	endpointUrl, err := url.Parse(request.Endpoint)
+	if err != nil || endpointUrl.Scheme != "https" || endpointUrl.Host == "" {
+		http.Error(w, "Invalid push subscription endpoint.", 400)
+		return
+	}
+	if request.Keys.P256dh == "" || request.Keys.Auth == "" {
+		http.Error(w, "Push subscription is missing its keys.", 400)
+		return
+	}
+	if request.Topics == nil {
+		// Subscribe to everything by default.
+		request.Topics = notification.Topics
+	}
+	for _, topic := range request.Topics {
+		if !notification.IsValidTopic(topic) {
+			http.Error(w, fmt.Sprintf("Invalid notification topic '%s'.", topic), 400)
+			return
+		}
+	}
+
+	pushSubscription, err := web.arena.Database.GetPushSubscriptionByEndpoint(request.Endpoint)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if len(request.TeamIds) == 0 || len(request.Topics) == 0 {
+		if pushSubscription != nil {
+			if err = web.arena.Database.DeletePushSubscription(pushSubscription.Id); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+		w.WriteHeader(204)
+		return
+	}
+
+	if pushSubscription == nil {
+		pushSubscription = &model.PushSubscription{Endpoint: request.Endpoint}
+	}
+	pushSubscription.P256dhKey = request.Keys.P256dh
+	pushSubscription.AuthSecret = request.Keys.Auth
+	pushSubscription.TeamIds = request.TeamIds
+	pushSubscription.Topics = request.Topics
+	if pushSubscription.Id == 0 {
+		err = web.arena.Database.CreatePushSubscription(pushSubscription)
+	} else {
+		err = web.arena.Database.UpdatePushSubscription(pushSubscription)
+	}
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// Returns the spectator site representation of the given match, including its scores if a result is given.
+func (web *Web) newSpectatorMatch(match *model.Match, matchResult *model.MatchResult, matchesAway int) spectatorMatch {
+	spectatorMatch := spectatorMatch{
+		Id:          match.Id,
+		ShortName:   match.ShortName,
+		LongName:    match.LongName,
+		Time:        match.Time,
+		RedTeams:    match.RedTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+		BlueTeams:   match.BlueTeamIds(web.arena.EventSettings.TeamsPerAlliance),
+		MatchesAway: matchesAway,
+	}
+	if matchResult != nil {
+		redScore := matchResult.RedScoreSummary().Score
+		blueScore := matchResult.BlueScoreSummary().Score
+		spectatorMatch.RedScore = &redScore
+		spectatorMatch.BlueScore = &blueScore
+	}
+	return spectatorMatch
+}
+
+// Returns a description of the given match state that is meaningful to spectators.
+func getSpectatorMatchStatus(matchState field.MatchState) string {
+	switch matchState {
+	case field.PreMatch:
+		return "Up Next"
+	case field.PostMatch:
+		return "Awaiting Score"
+	case field.TimeoutActive, field.PostTimeout:
+		return "Timeout"
+	case field.FieldFault:
+		return "Paused"
+	default:
+		return "In Progress"
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/notification"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSpectator(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/spectator")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "3 matches away")
+}
+
+func TestSpectatorStatusApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 1; i <= 5; i++ {
+		web.arena.Database.CreateMatch(&model.Match{Type: model.Qualification, TypeOrder: i, Red1: 254 + i})
+	}
+	match, _ := web.arena.Database.GetMatchByTypeOrder(model.Qualification, 1)
+	match.LongName = "Qualification 1"
+	assert.Nil(t, web.arena.LoadMatch(match))
+
+	recorder := web.getHttpResponse("/api/spectator/status")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var status spectatorStatus
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, "Untitled Event", status.EventName)
+	assert.Equal(t, "Qualification 1", status.Match.LongName)
+	assert.Equal(t, "Up Next", status.Status)
+	assert.Nil(t, status.Match.RedScore)
+	if assert.Equal(t, 3, len(status.UpcomingMatches)) {
+		assert.Equal(t, 257, status.UpcomingMatches[1].RedTeams[0])
+		assert.Equal(t, 2, status.UpcomingMatches[1].MatchesAway)
+	}
+}
+
+func TestSpectatorTeamScheduleApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	match1 := model.Match{Type: model.Qualification, TypeOrder: 1, ShortName: "Q1", Red1: 254}
+	match2 := model.Match{Type: model.Qualification, TypeOrder: 2, ShortName: "Q2", Blue3: 1114}
+	match3 := model.Match{Type: model.Qualification, TypeOrder: 3, ShortName: "Q3", Blue2: 254}
+	web.arena.Database.CreateMatch(&match1)
+	web.arena.Database.CreateMatch(&match2)
+	web.arena.Database.CreateMatch(&match3)
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match1.Id, 1))
+	match1.Status = game.RedWonMatch
+	web.arena.Database.UpdateMatch(&match1)
+	assert.Nil(t, web.arena.LoadMatch(&match2))
+
+	recorder := web.getHttpResponse("/api/spectator/teams/254/schedule")
+	assert.Equal(t, 200, recorder.Code)
+	var schedule spectatorTeamSchedule
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &schedule))
+	assert.Equal(t, "The Cheesy Poofs", schedule.Nickname)
+	if assert.Equal(t, 2, len(schedule.Matches)) {
+		assert.Equal(t, "Q1", schedule.Matches[0].ShortName)
+		assert.NotNil(t, schedule.Matches[0].RedScore)
+		assert.Equal(t, -1, schedule.Matches[0].MatchesAway)
+		assert.Equal(t, "Q3", schedule.Matches[1].ShortName)
+		assert.Nil(t, schedule.Matches[1].RedScore)
+		assert.Equal(t, 1, schedule.Matches[1].MatchesAway)
+	}
+
+	recorder = web.getHttpResponse("/api/spectator/teams/1114/schedule")
+	assert.Equal(t, 404, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 1114 is not at this event.")
+}
+
+func TestSpectatorPushApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/api/spectator/push/key")
+	assert.Equal(t, 200, recorder.Code)
+	var key struct{ PublicKey string }
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &key))
+	assert.Equal(t, 87, len(key.PublicKey))
+
+	// Check that invalid subscriptions are rejected.
+	recorder = web.postHttpResponse("/api/spectator/push/subscriptions", "{")
+	assert.Equal(t, 400, recorder.Code)
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions",
+		`{"endpoint":"http://push.example.com/1","keys":{"p256dh":"abc","auth":"def"},"teamIds":[254]}`,
+	)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid push subscription endpoint.")
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions", `{"endpoint":"https://push.example.com/1","teamIds":[254]}`,
+	)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Push subscription is missing its keys.")
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions",
+		`{"endpoint":"https://push.example.com/1","keys":{"p256dh":"abc","auth":"def"},"teamIds":[254],"topics":["x"]}`,
+	)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid notification topic 'x'.")
+
+	// Check creating, updating and deleting a subscription.
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions",
+		`{"endpoint":"https://push.example.com/1","keys":{"p256dh":"abc","auth":"def"},"teamIds":[254]}`,
+	)
+	assert.Equal(t, 204, recorder.Code)
+	pushSubscriptions, _ := web.arena.Database.GetAllPushSubscriptions()
+	if assert.Equal(t, 1, len(pushSubscriptions)) {
+		assert.Equal(t, notification.Topics, pushSubscriptions[0].Topics)
+	}
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions",
+		`{"endpoint":"https://push.example.com/1","keys":{"p256dh":"abc","auth":"ghi"},"teamIds":[254,1114],`+
+			`"topics":["delay"]}`,
+	)
+	assert.Equal(t, 204, recorder.Code)
+	pushSubscriptions, _ = web.arena.Database.GetAllPushSubscriptions()
+	if assert.Equal(t, 1, len(pushSubscriptions)) {
+		assert.Equal(t, "ghi", pushSubscriptions[0].AuthSecret)
+		assert.Equal(t, []int{254, 1114}, pushSubscriptions[0].TeamIds)
+		assert.Equal(t, []string{"delay"}, pushSubscriptions[0].Topics)
+	}
+	recorder = web.postHttpResponse(
+		"/api/spectator/push/subscriptions",
+		`{"endpoint":"https://push.example.com/1","keys":{"p256dh":"abc","auth":"ghi"},"teamIds":[]}`,
+	)
+	assert.Equal(t, 204, recorder.Code)
+	pushSubscriptions, _ = web.arena.Database.GetAllPushSubscriptions()
+	assert.Empty(t, pushSubscriptions)
+}
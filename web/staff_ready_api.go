@@ -0,0 +1,33 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web API through which a venue intercom or paging system reports that a member of the field staff is ready.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+)
+
+// Accepts an update to whether the given field staff role is ready for the match to start.
+func (web *Web) staffReadyApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiStaffReadyScope) {
+		return
+	}
+
+	var update struct {
+		Ready bool
+	}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid readiness update: "+err.Error(), 400)
+		return
+	}
+	if err := web.arena.SetStaffReady(field.StaffRole(r.PathValue("role")), update.Ready); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
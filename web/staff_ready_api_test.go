@@ -0,0 +1,41 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStaffReadyApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	setupApiV1Tokens(web)
+	apiToken := model.ApiToken{Name: "Intercom", Token: "secret", Scope: model.ApiStaffReadyScope}
+	web.arena.Database.CreateApiToken(&apiToken)
+
+	recorder := web.postHttpResponse("/api/staff_ready/fta", `{"Ready": true}`)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/staff_ready/fta?apiKey=wrong", `{"Ready": true}`)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/staff_ready/fta?apiKey=writetoken", `{"Ready": true}`)
+	assert.Equal(t, 403, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "does not have the staff_ready scope")
+	assert.False(t, web.arena.StaffReadiness.FtaReady)
+
+	recorder = web.postHttpResponse("/api/staff_ready/fta?apiKey=secret", `{"Ready": true}`)
+	assert.Equal(t, 204, recorder.Code)
+	assert.True(t, web.arena.StaffReadiness.FtaReady)
+	recorder = web.postHttpResponse("/api/staff_ready/fta?apiKey=secret", `{"Ready": false}`)
+	assert.Equal(t, 204, recorder.Code)
+	assert.False(t, web.arena.StaffReadiness.FtaReady)
+
+	recorder = web.postHttpResponse("/api/staff_ready/announcer?apiKey=secret", `{"Ready": true}`)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "invalid staff role")
+	recorder = web.postHttpResponse("/api/staff_ready/fta?apiKey=secret", "{")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid readiness update")
+}
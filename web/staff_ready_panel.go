@@ -0,0 +1,104 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web handlers for the panel through which a member of the field staff confirms that they are ready for the next match.
+
+package web
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"io"
+	"net/http"
+)
+
+// Renders the staff readiness panel for the role given in the query string.
+func (web *Web) staffReadyPanelHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.RefereeRole, model.FtaRole, model.AvRole) {
+		return
+	}
+
+	role, err := getStaffRole(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	template, err := web.parseFiles("templates/staff_ready_panel.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Role     field.StaffRole
+		RoleName string
+	}{web.arena.EventSettings, role, field.StaffRoleNames[role]}
+	err = template.ExecuteTemplate(w, "base_no_navbar", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// The websocket endpoint for the staff readiness panel client to send control commands and receive status updates.
+func (web *Web) staffReadyPanelWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.ScorekeeperRole, model.RefereeRole, model.FtaRole, model.AvRole) {
+		return
+	}
+
+	role, err := getStaffRole(r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(web.arena.MatchLoadNotifier, web.arena.ArenaStatusNotifier, web.arena.ReloadDisplaysNotifier)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Web.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "setReady":
+			ready, ok := data.(bool)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			if err = web.arena.SetStaffReady(role, ready); err != nil {
+				ws.WriteError(err.Error())
+			}
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+		}
+	}
+}
+
+// Returns the field staff role given in the request's query string.
+func getStaffRole(r *http.Request) (field.StaffRole, error) {
+	role := field.StaffRole(r.URL.Query().Get("role"))
+	if _, ok := field.StaffRoleNames[role]; !ok {
+		return "", fmt.Errorf("invalid staff role '%s'", role)
+	}
+	return role, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStaffReadyPanel(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/panels/staff_ready?role=headReferee")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Head Referee Ready - Untitled Event - Cheesy Arena")
+	assert.Contains(t, recorder.Body.String(), "not currently required")
+
+	recorder = web.getHttpResponse("/panels/staff_ready?role=announcer")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "invalid staff role")
+}
+
+func TestStaffReadyPanelWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/staff_ready/websocket?role=fta", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "arenaStatus")
+
+	ws.Write("setReady", "yes")
+	assert.Contains(t, readWebsocketError(t, ws), "Failed to parse")
+	ws.Write("setReady", true)
+	readWebsocketType(t, ws, "arenaStatus")
+	assert.True(t, web.arena.StaffReadiness.FtaReady)
+	assert.False(t, web.arena.StaffReadiness.HeadRefereeReady)
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTeamSignDisplay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/displays/team_sign?displayId=1")
+	assert.Equal(t, 302, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Location"), "station=R1")
+
+	recorder = web.getHttpResponse("/displays/team_sign?displayId=1&station=B2")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team Sign Display - Untitled Event - Cheesy Arena")
+}
+
+func TestTeamSignDisplayWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/team_sign/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "displayConfiguration")
+	readWebsocketType(t, ws, "teamSigns")
+
+	// Check that a change to the lineup is pushed out.
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, web.arena.SubstituteTeams(0, 0, 0, 0, 254, 0))
+	web.arena.Update()
+	signs := readWebsocketType(t, ws, "teamSigns").(map[string]any)
+	assert.Equal(t, 254.0, signs["B2"].(map[string]any)["TeamId"])
+}
+
+func TestTeamSignsWebsocketApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/api/team_signs/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	readWebsocketType(t, ws, "teamSigns")
+	web.arena.Update()
+	signs := readWebsocketType(t, ws, "teamSigns").(map[string]any)
+	assert.Equal(t, 6, len(signs))
+}
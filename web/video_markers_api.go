@@ -0,0 +1,54 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web API through which the A/V operator syncs the match timestamps to the video stream, and through which the
+// timestamps are retrieved for cutting the stream into match videos.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"net/http"
+)
+
+// Returns all recorded video markers as JSON, in chronological order.
+func (web *Web) videoMarkersApiHandler(w http.ResponseWriter, r *http.Request) {
+	videoMarkers, err := web.arena.Database.GetAllVideoMarkers()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	jsonData, err := json.MarshalIndent(videoMarkers, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Accepts the current position of the video stream, against which subsequent match markers are timed.
+func (web *Web) videoStreamOffsetApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userHasRole(w, r, model.AvRole) {
+		return
+	}
+
+	var update struct {
+		OffsetSec float64
+	}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid stream offset: "+err.Error(), 400)
+		return
+	}
+	if err := web.arena.SetVideoStreamOffset(update.OffsetSec); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVideoMarkersApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/api/video_markers/stream_offset", `{"OffsetSec": 125.5}`)
+	assert.Equal(t, 204, recorder.Code)
+	recorder = web.postHttpResponse("/api/video_markers/stream_offset", `{"OffsetSec": -5}`)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "must not be negative")
+	recorder = web.postHttpResponse("/api/video_markers/stream_offset", "{")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid stream offset")
+
+	recorder = web.getHttpResponse("/api/video_markers")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var videoMarkers []model.VideoMarker
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &videoMarkers))
+	if assert.Equal(t, 1, len(videoMarkers)) {
+		assert.Equal(t, model.VideoMarkerStreamSync, videoMarkers[0].Type)
+		assert.Equal(t, 125.5, videoMarkers[0].StreamOffsetSec)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Web API through which an automated vision scoring system submits provisional scores.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	"github.com/mitchellh/mapstructure"
+	"io"
+	"net/http"
+)
+
+// Accepts a provisional score update for the given alliance.
+func (web *Web) visionScoreApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiVisionScoringScope) {
+		return
+	}
+
+	var update field.VisionScoreUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid score update: "+err.Error(), 400)
+		return
+	}
+	if err := web.arena.ApplyVisionScoreUpdate(r.PathValue("alliance"), &update); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// Websocket API for submitting a stream of provisional score updates and receiving the match status.
+func (web *Web) visionScoreWebsocketApiHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.apiIsAuthorized(w, r, model.ApiVisionScoringScope) {
+		return
+	}
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			logging.Scoring.Warn("Websocket read error.", "error", err)
+			return
+		}
+
+		switch messageType {
+		case "update":
+			args := struct {
+				Alliance                string
+				field.VisionScoreUpdate `mapstructure:",squash"`
+			}{}
+			if err = mapstructure.Decode(data, &args); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if err = web.arena.ApplyVisionScoreUpdate(args.Alliance, &args.VisionScoreUpdate); err != nil {
+				ws.WriteError(err.Error())
+			}
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+		}
+	}
+}
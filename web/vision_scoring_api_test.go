@@ -0,0 +1,102 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+
+package web
+
+import (
+	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/game"
+	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func setupVisionScoringToken(web *Web) {
+	apiToken := model.ApiToken{Name: "Vision", Token: "secret", Scope: model.ApiVisionScoringScope}
+	web.arena.Database.CreateApiToken(&apiToken)
+}
+
+func TestVisionScoreApi(t *testing.T) {
+	web := setupTestWeb(t)
+	body := `{"LeaveStatuses": [true, false, true], "TrapStatuses": [false, false, true]}`
+
+	setupApiV1Tokens(web)
+	setupVisionScoringToken(web)
+
+	recorder := web.postHttpResponse("/api/vision_score/red", body)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/vision_score/red?apiKey=wrong", body)
+	assert.Equal(t, 401, recorder.Code)
+	recorder = web.postHttpResponse("/api/vision_score/red?apiKey=writetoken", body)
+	assert.Equal(t, 403, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "does not have the vision_scoring scope")
+
+	recorder = web.postHttpResponse("/api/vision_score/red?apiKey=secret", body)
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "no match is in progress")
+
+	web.arena.MatchState = field.AutoPeriod
+	recorder = web.postHttpResponse("/api/vision_score/red?apiKey=secret", body)
+	assert.Equal(t, 204, recorder.Code)
+	assert.Equal(t, [3]bool{true, false, true}, web.arena.RedRealtimeScore.CurrentScore.LeaveStatuses)
+	assert.Equal(t, [3]bool{false, false, true}, web.arena.RedRealtimeScore.CurrentScore.TrapStatuses)
+	assert.Equal(t, [3]bool{true, true, true}, web.arena.RedRealtimeScore.MachineScored.LeaveStatuses)
+
+	recorder = web.postHttpResponse("/api/vision_score/blue?apiKey=secret", "{")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid score update")
+}
+
+func TestVisionScoreWebsocketApi(t *testing.T) {
+	web := setupTestWeb(t)
+	setupVisionScoringToken(web)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	_, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/api/vision_score/websocket", nil)
+	assert.NotNil(t, err)
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(
+		wsUrl+"/api/vision_score/websocket", http.Header{"Authorization": []string{"Bearer secret"}},
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+
+	// Should get a few status updates right after connection.
+	readWebsocketType(t, ws, "matchLoad")
+	readWebsocketType(t, ws, "matchTime")
+
+	web.arena.MatchState = field.TeleopPeriod
+	ws.Write("update", map[string]any{"Alliance": "blue", "EndgameStatuses": []int{0, 3, 1}})
+	ws.Write("blorpy", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "Invalid message type 'blorpy'.")
+	assert.Equal(
+		t,
+		[3]game.EndgameStatus{game.EndgameNone, game.EndgameCenterStage, game.EndgameParked},
+		web.arena.BlueRealtimeScore.CurrentScore.EndgameStatuses,
+	)
+	ws.Write("update", map[string]any{"Alliance": "green"})
+	assert.Equal(t, "Invalid alliance 'green'.", readWebsocketError(t, ws))
+
+	// A human scorer's input should take precedence over the vision system.
+	scoringConn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/panels/scoring/blue/websocket", nil)
+	assert.Nil(t, err)
+	defer scoringConn.Close()
+	scoringWs := websocket.NewTestWebsocket(scoringConn)
+	readWebsocketMultiple(t, scoringWs, 4)
+	scoringWs.Write("onStage", map[string]any{"TeamPosition": 2, "StageIndex": 0})
+	readWebsocketType(t, scoringWs, "realtimeScore")
+	assert.False(t, web.arena.BlueRealtimeScore.MachineScored.EndgameStatuses[1])
+	ws.Write("update", map[string]any{"Alliance": "blue", "EndgameStatuses": []int{2, 0, 0}})
+	time.Sleep(time.Millisecond * 10) // Allow some time for the command to be processed.
+	assert.Equal(
+		t,
+		[3]game.EndgameStatus{game.EndgameStageLeft, game.EndgameStageLeft, game.EndgameNone},
+		web.arena.BlueRealtimeScore.CurrentScore.EndgameStatuses,
+	)
+	assert.Equal(t, [3]bool{true, false, true}, web.arena.BlueRealtimeScore.MachineScored.EndgameStatuses)
+}
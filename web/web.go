@@ -6,31 +6,59 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"github.com/Team254/cheesy-arena/game"
-	"log"
+	"net"
 	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/Team254/cheesy-arena/field"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/Team254/cheesy-arena/model"
+	"github.com/Team254/cheesy-arena/websocket"
 )
 
 const (
 	sessionTokenCookie = "session_token"
+	panelDeviceCookie  = "panel_device_token"
 	adminUser          = "admin"
 )
 
 type Web struct {
 	arena           *field.Arena
 	templateHelpers template.FuncMap
+	httpsPort       int
+	tlsCertFile     string
+	tlsKeyFile      string
+	trustedProxies  []*net.IPNet
+	servers         []*http.Server
+	serversMutex    sync.Mutex
+
+	archivedEvents      map[string]*archivedEvent
+	archivedEventsMutex sync.Mutex
+
+	// Web interfaces of the fields other than the first, keyed by field number, which only the first field's web
+	// interface has. Their pages are served under the field's path prefix.
+	fieldWebs    map[int]*Web
+	fieldHandler http.Handler
 }
 
 func NewWeb(arena *field.Arena) *Web {
 	web := &Web{arena: arena}
+	if arena.IsFirstField() {
+		web.fieldWebs = make(map[int]*Web)
+		for _, fieldArena := range arena.Fields()[1:] {
+			web.fieldWebs[fieldArena.FieldId] = NewWeb(fieldArena)
+		}
+	} else {
+		web.fieldHandler = web.newFieldHandler()
+	}
 
 	// Helper functions that can be used inside templates.
 	web.templateHelpers = template.FuncMap{
@@ -52,12 +80,38 @@ func NewWeb(arena *field.Arena) *Web {
 		"add": func(a, b int) int {
 			return a + b
 		},
+		"datetimeLocal": func(t time.Time) string {
+			// Formats the time for a datetime-local input, leaving the input blank if the time is unset.
+			if t.IsZero() {
+				return ""
+			}
+			return t.In(web.arena.EventSettings.Location()).Format("2006-01-02T15:04")
+		},
+		"additionalFieldIds": func() []int {
+			// Lists the fields other than the first, whose pages are linked to separately.
+			var fieldIds []int
+			for _, fieldArena := range web.arena.Fields()[1:] {
+				fieldIds = append(fieldIds, fieldArena.FieldId)
+			}
+			return fieldIds
+		},
+		"eventTime": func(t time.Time) time.Time {
+			// Converts the time to the event's time zone for display.
+			return t.In(web.arena.EventSettings.Location())
+		},
+		"fieldPath": func(path string) string {
+			// Prefixes the path of a page that is specific to a field so that it stays on the same field.
+			return web.arena.PathPrefix() + path
+		},
 		"itoa": func(a int) string {
 			return strconv.Itoa(a)
 		},
 		"multiply": func(a, b int) int {
 			return a * b
 		},
+		"percent": func(fraction float64) string {
+			return fmt.Sprintf("%.0f%%", fraction*100)
+		},
 		"seq": func(count int) []int {
 			seq := make([]int, count)
 			for i := 0; i < count; i++ {
@@ -65,6 +119,16 @@ func NewWeb(arena *field.Arena) *Web {
 			}
 			return seq
 		},
+		"teamsPerAlliance": func() int {
+			return web.arena.EventSettings.TeamsPerAlliance
+		},
+		"themeLogo": func(defaultPath string) string {
+			// Substitutes the event's own logo for the built-in one if one has been uploaded.
+			if web.arena.EventSettings.ThemeLogoFile == "" {
+				return defaultPath
+			}
+			return themeAssetUrl(web.arena.EventSettings.ThemeLogoFile)
+		},
 		"toUpper": func(str string) string {
 			return strings.ToUpper(str)
 		},
@@ -88,12 +152,54 @@ func NewWeb(arena *field.Arena) *Web {
 
 // Starts the webserver and blocks, waiting on requests. Does not return until the application exits.
 func (web *Web) ServeWebInterface(port int) {
-	http.Handle("/static/", http.StripPrefix("/static/", addNoCacheHeader(http.FileServer(http.Dir("static/")))))
-	http.Handle("/", web.newHandler())
-	log.Printf("Serving HTTP requests on port %d", port)
+	handler := http.NewServeMux()
+	handler.Handle("/static/", http.StripPrefix("/static/", addNoCacheHeader(http.FileServer(http.Dir("static/")))))
+	handler.Handle("/", web.newHandler())
+	httpServer := web.addServer(port, handler)
+	if web.httpsPort != 0 {
+		httpsServer := web.addServer(web.httpsPort, handler)
+		go func() {
+			logging.Web.Info("Serving HTTPS requests.", "port", web.httpsPort)
+			err := httpsServer.ListenAndServeTLS(web.tlsCertFile, web.tlsKeyFile)
+			logging.Web.Error("HTTPS server stopped.", "error", err)
+		}()
+	}
+	logging.Web.Info("Serving HTTP requests.", "port", port)
 
 	// Start Server
-	http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		logging.Web.Error("HTTP server stopped.", "error", err)
+	}
+}
+
+// Stops accepting new requests and waits up to the given timeout for those in progress to finish, after telling all
+// the websocket clients that the server is restarting so that they reconnect promptly once it is back up. The
+// clients will reload on reconnecting to pick up any changes made to the pages in the meantime.
+func (web *Web) Shutdown(timeout time.Duration) {
+	web.serversMutex.Lock()
+	servers := web.servers
+	web.serversMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			logging.Web.Warn("Error shutting down web server.", "address", server.Addr, "error", err)
+		}
+	}
+
+	// The websocket connections have been hijacked from the servers and so have to be closed separately.
+	websocket.CloseAllForRestart()
+
+	web.closeArchivedEvents()
+}
+
+func (web *Web) addServer(port int, handler http.Handler) *http.Server {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: handler}
+	web.serversMutex.Lock()
+	web.servers = append(web.servers, server)
+	web.serversMutex.Unlock()
+	return server
 }
 
 // Serves the root page of Cheesy Arena.
@@ -132,12 +238,67 @@ func (web *Web) newHandler() http.Handler {
 	mux.HandleFunc("POST /alliance_selection/reset", web.allianceSelectionResetHandler)
 	mux.HandleFunc("POST /alliance_selection/start", web.allianceSelectionStartHandler)
 	mux.HandleFunc("GET /api/alliances", web.alliancesApiHandler)
+	mux.HandleFunc("GET /api/archive/season_standings", web.seasonStandingsApiHandler)
 	mux.HandleFunc("GET /api/arena/websocket", web.arenaWebsocketApiHandler)
+	mux.HandleFunc("GET /api/bracket/advancements", web.bracketAdvancementsApiHandler)
 	mux.HandleFunc("GET /api/bracket/svg", web.bracketSvgApiHandler)
+	mux.HandleFunc("POST /api/captions", web.captionsApiHandler)
+	mux.HandleFunc("POST /api/captions/clear", web.captionsClearApiHandler)
+	mux.HandleFunc("GET /api/emcee_scripts", web.emceeScriptsApiHandler)
+	mux.HandleFunc("GET /api/emcee_scripts/{matchId}", web.emceeScriptApiHandler)
+	mux.HandleFunc("GET /api/field_monitor", web.fieldMonitorApiHandler)
+	mux.HandleFunc("GET /api/league/standings", web.leagueStandingsApiHandler)
+	mux.HandleFunc("GET /api/live_score", web.liveScoreApiHandler)
+	mux.HandleFunc("GET /api/live_score/websocket", web.liveScoreWebsocketApiHandler)
+	mux.HandleFunc("GET /api/match_clock", web.matchClockApiHandler)
+	mux.HandleFunc("GET /api/match_clock/websocket", web.matchClockWebsocketApiHandler)
 	mux.HandleFunc("GET /api/matches/{type}", web.matchesApiHandler)
+	mux.HandleFunc("GET /api/queueing", web.queueingApiHandler)
 	mux.HandleFunc("GET /api/rankings", web.rankingsApiHandler)
+	mux.HandleFunc("GET /api/rankings/projections", web.projectionsApiHandler)
+	mux.HandleFunc("POST /api/scouting/observations", web.scoutingObservationsApiHandler)
+	mux.HandleFunc("GET /api/scouting/schedule/{type}", web.scoutingScheduleApiHandler)
+	mux.HandleFunc("GET /api/sounds/{name}", web.soundsApiHandler)
+	mux.HandleFunc("GET /api/spectator/push/key", web.spectatorPushKeyApiHandler)
+	mux.HandleFunc("POST /api/spectator/push/subscriptions", web.spectatorPushSubscriptionsApiHandler)
+	mux.HandleFunc("GET /api/spectator/status", web.spectatorStatusApiHandler)
+	mux.HandleFunc("GET /api/spectator/teams/{teamId}/schedule", web.spectatorTeamScheduleApiHandler)
 	mux.HandleFunc("GET /api/sponsor_slides", web.sponsorSlidesApiHandler)
+	mux.HandleFunc("POST /api/staff_ready/{role}", web.staffReadyApiHandler)
+	mux.HandleFunc("GET /api/team_signs/websocket", web.teamSignsWebsocketApiHandler)
+	mux.HandleFunc("GET /api/team_stats", web.teamStatsApiHandler)
 	mux.HandleFunc("GET /api/teams/{teamId}/avatar", web.teamAvatarsApiHandler)
+	mux.HandleFunc("GET /api/v1", web.apiV1IndexHandler)
+	mux.HandleFunc("GET /api/v1/arena/status", web.apiV1ArenaStatusHandler)
+	mux.HandleFunc("GET /api/v1/matches", web.apiV1MatchesHandler)
+	mux.HandleFunc("GET /api/v1/matches/{type}", web.apiV1MatchesHandler)
+	mux.HandleFunc("GET /api/v1/rankings", web.apiV1RankingsHandler)
+	mux.HandleFunc("GET /api/v1/results/{matchId}", web.apiV1ResultHandler)
+	mux.HandleFunc("GET /api/v1/team_statuses", web.apiV1TeamStatusesHandler)
+	mux.HandleFunc("GET /api/v1/teams", web.apiV1TeamsHandler)
+	mux.HandleFunc("POST /api/v1/teams", web.apiV1TeamsPostHandler)
+	mux.HandleFunc("GET /api/v1/teams/{teamId}", web.apiV1TeamHandler)
+	mux.HandleFunc("POST /api/v1/teams/{teamId}", web.apiV1TeamPostHandler)
+	mux.HandleFunc("GET /api/v1/teams/{teamId}/status", web.apiV1TeamStatusHandler)
+	mux.HandleFunc("POST /api/v1/teams/{teamId}/status", web.apiV1TeamStatusPostHandler)
+	mux.HandleFunc("GET /api/video_markers", web.videoMarkersApiHandler)
+	mux.HandleFunc("POST /api/video_markers/stream_offset", web.videoStreamOffsetApiHandler)
+	mux.HandleFunc("POST /api/vision_score/{alliance}", web.visionScoreApiHandler)
+	mux.HandleFunc("GET /api/vision_score/websocket", web.visionScoreWebsocketApiHandler)
+	mux.HandleFunc("GET /archive", web.archiveHandler)
+	mux.HandleFunc("GET /archive/{event}/api/bracket/svg", web.bracketSvgApiHandler)
+	mux.HandleFunc("GET /archive/{event}/api/rankings", web.rankingsApiHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/csv/rankings", web.rankingsCsvReportHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/csv/schedule/{type}", web.scheduleCsvReportHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/pdf/alliances", web.alliancesPdfReportHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/pdf/bracket", web.bracketPdfReportHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/pdf/rankings", web.rankingsPdfReportHandler)
+	mux.HandleFunc("GET /archive/{event}/reports/pdf/schedule/{type}", web.schedulePdfReportHandler)
+	mux.HandleFunc("GET /awards_ceremony", web.awardsCeremonyGetHandler)
+	mux.HandleFunc("POST /awards_ceremony/{awardId}/winner", web.awardsCeremonyWinnerPostHandler)
+	mux.HandleFunc("POST /awards_ceremony/hide", web.awardsCeremonyHidePostHandler)
+	mux.HandleFunc("POST /awards_ceremony/next", web.awardsCeremonyNextPostHandler)
+	mux.HandleFunc("POST /awards_ceremony/show", web.awardsCeremonyShowPostHandler)
 	mux.HandleFunc("GET /display", web.placeholderDisplayHandler)
 	mux.HandleFunc("GET /display/websocket", web.placeholderDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/alliance_station", web.allianceStationDisplayHandler)
@@ -150,90 +311,229 @@ func (web *Web) newHandler() http.Handler {
 	mux.HandleFunc("GET /displays/audience/websocket", web.audienceDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/bracket", web.bracketDisplayHandler)
 	mux.HandleFunc("GET /displays/bracket/websocket", web.bracketDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/captions", web.captionDisplayHandler)
+	mux.HandleFunc("GET /displays/captions/websocket", web.captionDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/cues", web.cueDisplayHandler)
+	mux.HandleFunc("GET /displays/cues/websocket", web.cueDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/emcee", web.emceeDisplayHandler)
+	mux.HandleFunc("GET /displays/emcee/websocket", web.emceeDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/field_monitor", web.fieldMonitorDisplayHandler)
 	mux.HandleFunc("GET /displays/field_monitor/websocket", web.fieldMonitorDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/league", web.leagueDisplayHandler)
+	mux.HandleFunc("GET /displays/league/websocket", web.leagueDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/logo", web.logoDisplayHandler)
 	mux.HandleFunc("GET /displays/logo/websocket", web.logoDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/pit", web.pitDisplayHandler)
+	mux.HandleFunc("GET /displays/pit/pages", web.pitDisplayPagesHandler)
+	mux.HandleFunc("GET /displays/pit/websocket", web.pitDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/queueing", web.queueingDisplayHandler)
 	mux.HandleFunc("GET /displays/queueing/match_load", web.queueingDisplayMatchLoadHandler)
 	mux.HandleFunc("GET /displays/queueing/websocket", web.queueingDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/rankings", web.rankingsDisplayHandler)
 	mux.HandleFunc("GET /displays/rankings/websocket", web.rankingsDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/team_sign", web.teamSignDisplayHandler)
+	mux.HandleFunc("GET /displays/team_sign/websocket", web.teamSignDisplayWebsocketHandler)
+	mux.HandleFunc("GET /displays/theme.css", web.themeCssHandler)
 	mux.HandleFunc("GET /displays/twitch", web.twitchDisplayHandler)
 	mux.HandleFunc("GET /displays/twitch/websocket", web.twitchDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/wall", web.wallDisplayHandler)
 	mux.HandleFunc("GET /displays/wall/websocket", web.wallDisplayWebsocketHandler)
 	mux.HandleFunc("GET /displays/webpage", web.webpageDisplayHandler)
 	mux.HandleFunc("GET /displays/webpage/websocket", web.webpageDisplayWebsocketHandler)
+	mux.HandleFunc("GET /fields/{fieldId}/", web.fieldPageHandler)
+	mux.HandleFunc("POST /fields/{fieldId}/", web.fieldPageHandler)
+	mux.HandleFunc("GET /healthz", web.healthzHandler)
 	mux.HandleFunc("GET /login", web.loginHandler)
 	mux.HandleFunc("POST /login", web.loginPostHandler)
 	mux.HandleFunc("GET /match_play", web.matchPlayHandler)
 	mux.HandleFunc("GET /match_play/match_load", web.matchPlayMatchLoadHandler)
+	mux.HandleFunc("GET /match_play/score_review", web.scoreReviewGetHandler)
+	mux.HandleFunc("POST /match_play/score_review", web.scoreReviewPostHandler)
 	mux.HandleFunc("GET /match_play/websocket", web.matchPlayWebsocketHandler)
 	mux.HandleFunc("GET /match_logs", web.matchLogsHandler)
 	mux.HandleFunc("GET /match_logs/{matchId}/{stationId}/log", web.matchLogsViewGetHandler)
 	mux.HandleFunc("GET /match_review", web.matchReviewHandler)
+	mux.HandleFunc("GET /match_review/{matchId}/audit", web.matchReviewAuditGetHandler)
 	mux.HandleFunc("GET /match_review/{matchId}/edit", web.matchReviewEditGetHandler)
 	mux.HandleFunc("POST /match_review/{matchId}/edit", web.matchReviewEditPostHandler)
+	mux.HandleFunc("POST /match_review/{matchId}/replay", web.matchReviewReplayPostHandler)
+	mux.HandleFunc("GET /match_review/{matchId}/timeline", web.matchReviewTimelineGetHandler)
 	mux.HandleFunc("GET /panels/scoring/{alliance}", web.scoringPanelHandler)
 	mux.HandleFunc("GET /panels/scoring/{alliance}/websocket", web.scoringPanelWebsocketHandler)
+	mux.HandleFunc("GET /panels/filler_line", web.fillerLineGetHandler)
+	mux.HandleFunc("POST /panels/filler_line", web.fillerLinePostHandler)
+	mux.HandleFunc("POST /panels/filler_line/{id}/delete", web.fillerLineDeletePostHandler)
 	mux.HandleFunc("GET /panels/referee", web.refereePanelHandler)
 	mux.HandleFunc("GET /panels/referee/foul_list", web.refereePanelFoulListHandler)
 	mux.HandleFunc("GET /panels/referee/websocket", web.refereePanelWebsocketHandler)
+	mux.HandleFunc("GET /panels/resume", web.panelResumeHandler)
+	mux.HandleFunc("GET /panels/staff_ready", web.staffReadyPanelHandler)
+	mux.HandleFunc("GET /panels/staff_ready/websocket", web.staffReadyPanelWebsocketHandler)
+	mux.HandleFunc("GET /practice_field", web.practiceFieldHandler)
+	mux.HandleFunc("GET /practice_field/websocket", web.practiceFieldWebsocketHandler)
+	mux.HandleFunc("GET /projections", web.projectionsGetHandler)
 	mux.HandleFunc("GET /reports/csv/backups", web.backupTeamsCsvReportHandler)
 	mux.HandleFunc("GET /reports/csv/fta", web.ftaCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/league", web.leagueCsvReportHandler)
 	mux.HandleFunc("GET /reports/csv/rankings", web.rankingsCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/results/{type}", web.resultsCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/sandbox_results", web.sandboxResultsCsvReportHandler)
 	mux.HandleFunc("GET /reports/csv/schedule/{type}", web.scheduleCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/score_edits", web.scoreEditsCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/scouting", web.scoutingCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/team_bypasses", web.teamBypassesCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/team_cards", web.teamCardsCsvReportHandler)
 	mux.HandleFunc("GET /reports/csv/teams", web.teamsCsvReportHandler)
+	mux.HandleFunc("GET /reports/csv/timelines/{type}", web.timelinesCsvReportHandler)
 	mux.HandleFunc("GET /reports/csv/wpa_keys", web.wpaKeysCsvReportHandler)
+	mux.HandleFunc("GET /reports/pdf/agenda", web.agendaPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/alliances", web.alliancesPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/backups", web.backupsPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/bracket", web.bracketPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/coupons", web.couponsPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/cycle/{type}", web.cyclePdfReportHandler)
+	mux.HandleFunc("GET /reports/pdf/league", web.leaguePdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/rankings", web.rankingsPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/schedule/{type}", web.schedulePdfReportHandler)
+	mux.HandleFunc("GET /reports/pdf/team_stats", web.teamStatsPdfReportHandler)
 	mux.HandleFunc("GET /reports/pdf/teams", web.teamsPdfReportHandler)
+	mux.HandleFunc("GET /setup/api_tokens", web.apiTokensGetHandler)
+	mux.HandleFunc("POST /setup/api_tokens", web.apiTokensPostHandler)
+	mux.HandleFunc("GET /setup/agenda", web.agendaGetHandler)
+	mux.HandleFunc("POST /setup/agenda", web.agendaPostHandler)
+	mux.HandleFunc("GET /setup/audit_log", web.auditLogGetHandler)
+	mux.HandleFunc("GET /setup/audit_log/csv", web.auditLogCsvHandler)
 	mux.HandleFunc("GET /setup/awards", web.awardsGetHandler)
 	mux.HandleFunc("POST /setup/awards", web.awardsPostHandler)
+	mux.HandleFunc("GET /setup/backups", web.backupsGetHandler)
+	mux.HandleFunc("GET /setup/backups/{filename}", web.backupDownloadHandler)
+	mux.HandleFunc("POST /setup/backups/{filename}/restore", web.backupRestorePostHandler)
 	mux.HandleFunc("GET /setup/breaks", web.breaksGetHandler)
 	mux.HandleFunc("POST /setup/breaks", web.breaksPostHandler)
 	mux.HandleFunc("POST /setup/db/clear/{type}", web.clearDbHandler)
+	mux.HandleFunc("POST /setup/db/recompute", web.recomputeDbHandler)
 	mux.HandleFunc("POST /setup/db/restore", web.restoreDbHandler)
 	mux.HandleFunc("GET /setup/db/save", web.saveDbHandler)
+	mux.HandleFunc("GET /setup/diagnostics", web.diagnosticsGetHandler)
 	mux.HandleFunc("GET /setup/displays", web.displaysGetHandler)
 	mux.HandleFunc("GET /setup/displays/websocket", web.displaysWebsocketHandler)
+	mux.HandleFunc("GET /setup/events", web.eventsGetHandler)
+	mux.HandleFunc("POST /setup/events", web.eventsPostHandler)
 	mux.HandleFunc("GET /setup/field_testing", web.fieldTestingGetHandler)
 	mux.HandleFunc("GET /setup/field_testing/websocket", web.fieldTestingWebsocketHandler)
+	mux.HandleFunc("GET /setup/league", web.leagueGetHandler)
+	mux.HandleFunc("POST /setup/league", web.leaguePostHandler)
+	mux.HandleFunc("GET /setup/logs", web.logsGetHandler)
+	mux.HandleFunc("GET /setup/logs/websocket", web.logsWebsocketHandler)
 	mux.HandleFunc("GET /setup/lower_thirds", web.lowerThirdsGetHandler)
+	mux.HandleFunc("POST /setup/lower_thirds/playlists", web.lowerThirdPlaylistsPostHandler)
 	mux.HandleFunc("GET /setup/lower_thirds/websocket", web.lowerThirdsWebsocketHandler)
+	mux.HandleFunc("GET /setup/panel_devices", web.panelDevicesGetHandler)
+	mux.HandleFunc("POST /setup/panel_devices/{id}/revoke", web.panelDeviceRevokePostHandler)
+	mux.HandleFunc("GET /setup/pit", web.pitGetHandler)
+	mux.HandleFunc("POST /setup/pit/{id}", web.pitTeamPostHandler)
 	mux.HandleFunc("GET /setup/schedule", web.scheduleGetHandler)
 	mux.HandleFunc("POST /setup/schedule/generate", web.scheduleGeneratePostHandler)
 	mux.HandleFunc("POST /setup/schedule/save", web.scheduleSavePostHandler)
+	mux.HandleFunc("GET /setup/scouting", web.scoutingAppsGetHandler)
+	mux.HandleFunc("POST /setup/scouting", web.scoutingAppsPostHandler)
+	mux.HandleFunc("GET /setup/seeding", web.seedingGetHandler)
+	mux.HandleFunc("POST /setup/seeding/alliances", web.seedingAlliancesPostHandler)
+	mux.HandleFunc("POST /setup/seeding/rankings", web.seedingRankingsPostHandler)
 	mux.HandleFunc("GET /setup/settings", web.settingsGetHandler)
 	mux.HandleFunc("POST /setup/settings", web.settingsPostHandler)
+	mux.HandleFunc("GET /setup/settings/export", web.settingsExportHandler)
+	mux.HandleFunc("POST /setup/settings/import", web.settingsImportPostHandler)
 	mux.HandleFunc("GET /setup/settings/publish_alliances", web.settingsPublishAlliancesHandler)
 	mux.HandleFunc("GET /setup/settings/publish_awards", web.settingsPublishAwardsHandler)
 	mux.HandleFunc("GET /setup/settings/publish_matches", web.settingsPublishMatchesHandler)
 	mux.HandleFunc("GET /setup/settings/publish_rankings", web.settingsPublishRankingsHandler)
 	mux.HandleFunc("GET /setup/settings/publish_teams", web.settingsPublishTeamsHandler)
+	mux.HandleFunc("POST /setup/settings/theme_asset", web.settingsThemeAssetPostHandler)
+	mux.HandleFunc("GET /setup/show_flow", web.showFlowGetHandler)
+	mux.HandleFunc("GET /setup/show_flow/websocket", web.showFlowWebsocketHandler)
+	mux.HandleFunc("GET /setup/sound_packs", web.soundPacksGetHandler)
+	mux.HandleFunc("POST /setup/sound_packs", web.soundPacksPostHandler)
 	mux.HandleFunc("GET /setup/sponsor_slides", web.sponsorSlidesGetHandler)
 	mux.HandleFunc("POST /setup/sponsor_slides", web.sponsorSlidesPostHandler)
+	mux.HandleFunc("GET /setup/tba_publishing", web.tbaPublishingGetHandler)
+	mux.HandleFunc("POST /setup/tba_publishing/{kind}", web.tbaPublishingPostHandler)
 	mux.HandleFunc("GET /setup/teams", web.teamsGetHandler)
 	mux.HandleFunc("POST /setup/teams", web.teamsPostHandler)
+	mux.HandleFunc("POST /setup/teams/announcer_notes", web.teamsAnnouncerNotesPostHandler)
+	mux.HandleFunc("POST /setup/teams/avatar_import", web.teamsAvatarImportPostHandler)
+	mux.HandleFunc("POST /setup/teams/{id}/avatar", web.teamAvatarPostHandler)
+	mux.HandleFunc("POST /setup/teams/{id}/avatar/delete", web.teamAvatarDeletePostHandler)
 	mux.HandleFunc("POST /setup/teams/{id}/delete", web.teamDeletePostHandler)
 	mux.HandleFunc("GET /setup/teams/{id}/edit", web.teamEditGetHandler)
 	mux.HandleFunc("POST /setup/teams/{id}/edit", web.teamEditPostHandler)
 	mux.HandleFunc("POST /setup/teams/clear", web.teamsClearHandler)
+	mux.HandleFunc("POST /setup/teams/csv_import", web.teamsCsvImportPostHandler)
+	mux.HandleFunc("POST /setup/teams/first_import", web.teamsFirstImportPostHandler)
 	mux.HandleFunc("GET /setup/teams/generate_wpa_keys", web.teamsGenerateWpaKeysHandler)
 	mux.HandleFunc("GET /setup/teams/progress", web.teamsUpdateProgressBarHandler)
 	mux.HandleFunc("GET /setup/teams/refresh", web.teamsRefreshHandler)
+	mux.HandleFunc("GET /setup/update", web.updateGetHandler)
+	mux.HandleFunc("POST /setup/update/check", web.updateCheckPostHandler)
+	mux.HandleFunc("POST /setup/update/download", web.updateDownloadPostHandler)
+	mux.HandleFunc("POST /setup/update/schedule", web.updateSchedulePostHandler)
+	mux.HandleFunc("GET /setup/users", web.usersGetHandler)
+	mux.HandleFunc("POST /setup/users", web.usersPostHandler)
+	mux.HandleFunc("GET /setup/video_stingers", web.videoStingersGetHandler)
+	mux.HandleFunc("POST /setup/video_stingers", web.videoStingersPostHandler)
+	mux.HandleFunc("GET /setup/webhooks", web.webhooksGetHandler)
+	mux.HandleFunc("POST /setup/webhooks", web.webhooksPostHandler)
+	mux.HandleFunc("GET /spectator", web.spectatorHandler)
+	return web.applyForwardedHeaders(web.auditRequests(mux))
+}
+
+// Sets up the mapping between URLs and handlers for the pages of a field other than the first, which are those that
+// control or show the field's own match play. The rest of the pages are shared by all fields and so are only served
+// without a prefix.
+func (web *Web) newFieldHandler() http.Handler {
+	prefix := web.arena.PathPrefix()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+prefix+"/api/bracket/svg", web.bracketSvgApiHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/alliance_station", web.allianceStationDisplayHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/alliance_station/websocket", web.allianceStationDisplayWebsocketHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/audience", web.audienceDisplayHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/audience/websocket", web.audienceDisplayWebsocketHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/field_monitor", web.fieldMonitorDisplayHandler)
+	mux.HandleFunc("GET "+prefix+"/displays/field_monitor/websocket", web.fieldMonitorDisplayWebsocketHandler)
+	mux.HandleFunc("GET "+prefix+"/match_play", web.matchPlayHandler)
+	mux.HandleFunc("GET "+prefix+"/match_play/match_load", web.matchPlayMatchLoadHandler)
+	mux.HandleFunc("GET "+prefix+"/match_play/score_review", web.scoreReviewGetHandler)
+	mux.HandleFunc("POST "+prefix+"/match_play/score_review", web.scoreReviewPostHandler)
+	mux.HandleFunc("GET "+prefix+"/match_play/websocket", web.matchPlayWebsocketHandler)
+	mux.HandleFunc("GET "+prefix+"/match_review/{matchId}/edit", web.matchReviewEditGetHandler)
+	mux.HandleFunc("POST "+prefix+"/match_review/{matchId}/edit", web.matchReviewEditPostHandler)
+	mux.HandleFunc("GET "+prefix+"/panels/referee", web.refereePanelHandler)
+	mux.HandleFunc("GET "+prefix+"/panels/referee/foul_list", web.refereePanelFoulListHandler)
+	mux.HandleFunc("GET "+prefix+"/panels/referee/websocket", web.refereePanelWebsocketHandler)
+	mux.HandleFunc("GET "+prefix+"/panels/scoring/{alliance}", web.scoringPanelHandler)
+	mux.HandleFunc("GET "+prefix+"/panels/scoring/{alliance}/websocket", web.scoringPanelWebsocketHandler)
 	return mux
 }
 
+// Hands a request for one of the pages of a field other than the first to that field's web interface. The first
+// field's pages are redirected to their usual URLs.
+func (web *Web) fieldPageHandler(w http.ResponseWriter, r *http.Request) {
+	fieldId, _ := strconv.Atoi(r.PathValue("fieldId"))
+	if fieldId == 1 {
+		http.Redirect(w, r, strings.TrimPrefix(r.URL.RequestURI(), "/fields/1"), 307)
+		return
+	}
+	fieldWeb, ok := web.fieldWebs[fieldId]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fieldWeb.fieldHandler.ServeHTTP(w, r)
+}
+
 // Writes the given error out as plain text with a status code of 500.
 func handleWebErr(w http.ResponseWriter, err error) {
-	log.Printf("HTTP request error: %v", err)
+	logging.Web.Error("HTTP request error.", "error", err)
 	http.Error(w, "Internal server error: "+err.Error(), 500)
 }
 
@@ -6,76 +6,92 @@
 package websocket
 
 import (
-	"log"
+	"encoding/json"
+	"github.com/Team254/cheesy-arena/logging"
 	"sync"
+	"time"
 )
 
-// Allow the listeners to buffer a small number of notifications to streamline delivery.
-const notifyBufferSize = 5
-
+// A topic that websocket clients can subscribe to. Each notification is marshaled to JSON only once and the same
+// payload is then handed off to every subscriber, so that the cost of a notification on the caller's side doesn't grow
+// with the number of connected clients.
 type Notifier struct {
 	messageType     string
 	messageProducer func() any
-	listeners       map[chan messageEnvelope]struct{} // The map is essentially a set; the value is ignored.
+	subscribers     map[*subscriber]struct{} // The map is essentially a set; the value is ignored.
 	mutex           sync.Mutex
 }
 
-type messageEnvelope struct {
-	messageType string
-	messageBody any
-}
-
 func NewNotifier(messageType string, messageProducer func() any) *Notifier {
 	notifier := &Notifier{messageType: messageType, messageProducer: messageProducer}
-	notifier.listeners = make(map[chan messageEnvelope]struct{})
+	notifier.subscribers = make(map[*subscriber]struct{})
 	return notifier
 }
 
-// Calls the messageProducer function and sends a message containing the results to all registered listeners, and cleans
-// up any listeners that have closed.
+// Calls the messageProducer function and sends a message containing the results to all subscribers. Since the message
+// reflects the latest state, it replaces any earlier message from this notifier that a subscriber hasn't yet sent.
 func (notifier *Notifier) Notify() {
-	notifier.NotifyWithMessage(notifier.getMessageBody())
+	if !notifier.hasSubscribers() {
+		// Avoid the cost of producing a message that nobody will receive.
+		return
+	}
+	notifier.broadcast(notifier.getMessageBody(), true)
 }
 
-// Sends the given message to all registered listeners, and cleans up any listeners that have closed. If there is a
-// messageProducer function defined it is ignored.
+// Sends the given message to all subscribers. If there is a messageProducer function defined it is ignored. Such
+// messages represent discrete events rather than state, so they are always delivered individually.
 func (notifier *Notifier) NotifyWithMessage(messageBody any) {
-	notifier.mutex.Lock()
-	defer notifier.mutex.Unlock()
-
-	message := messageEnvelope{messageType: notifier.messageType, messageBody: messageBody}
-	for listener := range notifier.listeners {
-		notifier.notifyListener(listener, message)
+	if !notifier.hasSubscribers() {
+		return
 	}
+	notifier.broadcast(messageBody, false)
 }
 
-func (notifier *Notifier) notifyListener(listener chan messageEnvelope, message messageEnvelope) {
-	defer func() {
-		// If channel is closed sending to it will cause a panic; recover and remove it from the list.
-		if r := recover(); r != nil {
-			delete(notifier.listeners, listener)
-		}
-	}()
+// Marshals the given message once and queues it for sending to every subscriber, dropping any subscribers that have
+// fallen too far behind.
+func (notifier *Notifier) broadcast(messageBody any, coalesce bool) {
+	bodyJson, err := json.Marshal(messageBody)
+	if err != nil {
+		logging.Web.Error("Failed to marshal notification.", "messageType", notifier.messageType, "error", err)
+		return
+	}
+	now := time.Now().UnixMilli()
+	payload, err := json.Marshal(Message{Type: notifier.messageType, Data: json.RawMessage(bodyJson), Time: now})
+	if err != nil {
+		logging.Web.Error("Failed to marshal notification.", "messageType", notifier.messageType, "error", err)
+		return
+	}
+	message := &outgoingMessage{
+		messageType: notifier.messageType, payload: payload, coalesce: coalesce, data: bodyJson, time: now,
+	}
 
-	// Do a non-blocking send. This guarantees that sending notifications won't interrupt the main event loop,
-	// at the risk of clients missing some messages if they don't read them all promptly.
-	select {
-	case listener <- message:
-		// The notification was sent and received successfully.
-	default:
-		log.Printf("Failed to send a '%s' notification due to blocked listener.", notifier.messageType)
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	for subscriber := range notifier.subscribers {
+		if !subscriber.enqueue(message) {
+			delete(notifier.subscribers, subscriber)
+		}
 	}
 }
 
-// Registers and returns a channel that can be read from to receive notification messages. The caller is
-// responsible for closing the channel, which will cause it to be reaped from the list of listeners.
-func (notifier *Notifier) listen() chan messageEnvelope {
+// Registers the given subscriber to receive this notifier's messages.
+func (notifier *Notifier) subscribe(subscriber *subscriber) {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	notifier.subscribers[subscriber] = struct{}{}
+}
+
+// Stops sending this notifier's messages to the given subscriber.
+func (notifier *Notifier) unsubscribe(subscriber *subscriber) {
 	notifier.mutex.Lock()
 	defer notifier.mutex.Unlock()
+	delete(notifier.subscribers, subscriber)
+}
 
-	listener := make(chan messageEnvelope, notifyBufferSize)
-	notifier.listeners[listener] = struct{}{}
-	return listener
+func (notifier *Notifier) hasSubscribers() bool {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	return len(notifier.subscribers) > 0
 }
 
 // Invokes the message producer to get the message, or returns nil if no producer is defined.
@@ -4,87 +4,125 @@
 package websocket
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
-	"io/ioutil"
-	"log"
 	"testing"
 )
 
 func TestNotifier(t *testing.T) {
-	notifier := NewNotifier("testMessageType", generateTestMessage)
+	producerCalls := 0
+	notifier := NewNotifier("testMessageType", func() any {
+		producerCalls++
+		return "test message"
+	})
 
-	// Should do nothing when there are no listeners.
+	// Should do nothing when there are no subscribers.
 	notifier.Notify()
 	notifier.NotifyWithMessage(12345)
 	notifier.NotifyWithMessage(struct{}{})
+	assert.Equal(t, 0, producerCalls)
 
-	listener := notifier.listen()
+	subscriber := newSubscriber()
+	notifier.subscribe(subscriber)
 	notifier.Notify()
-	message := <-listener
-	assert.Equal(t, "testMessageType", message.messageType)
-	assert.Equal(t, "test message", message.messageBody)
+	<-subscriber.ready
+	messages := subscriber.takePending()
+	if assert.Equal(t, 1, len(messages)) {
+		assertPayload(t, messages[0], "testMessageType", "test message")
+	}
 	notifier.NotifyWithMessage(12345)
-	assert.Equal(t, 12345, (<-listener).messageBody)
+	assertPayload(t, subscriber.takePending()[0], "testMessageType", 12345.0)
 
-	// Should allow multiple messages without blocking.
+	// Should deliver every event message, but only the latest state message.
 	notifier.NotifyWithMessage("message1")
+	notifier.Notify()
 	notifier.NotifyWithMessage("message2")
 	notifier.Notify()
-	assert.Equal(t, "message1", (<-listener).messageBody)
-	assert.Equal(t, "message2", (<-listener).messageBody)
-	assert.Equal(t, "test message", (<-listener).messageBody)
+	messages = subscriber.takePending()
+	if assert.Equal(t, 3, len(messages)) {
+		assertPayload(t, messages[0], "testMessageType", "message1")
+		assertPayload(t, messages[1], "testMessageType", "test message")
+		assertPayload(t, messages[2], "testMessageType", "message2")
+	}
+	assert.Equal(t, 3, producerCalls)
 
-	// Should stop sending messages and not block once the buffer is full.
-	log.SetOutput(ioutil.Discard) // Silence noisy log output.
-	for i := 0; i < 20; i++ {
+	// Should stop sending messages and not block once the subscriber has fallen too far behind.
+	for i := 0; i < maxPendingMessages+1; i++ {
 		notifier.NotifyWithMessage(i)
 	}
-	var value messageEnvelope
-	var lastValue any
-	for lastValue == nil {
-		select {
-		case value = <-listener:
-		default:
-			lastValue = value.messageBody
-			return
-		}
-	}
+	<-subscriber.evicted
+	assert.Empty(t, subscriber.takePending())
+	assert.Equal(t, 0, len(notifier.subscribers))
 	notifier.NotifyWithMessage("next message")
-	assert.True(t, lastValue.(int) < 10)
-	assert.Equal(t, "next message", (<-listener).messageBody)
+	assert.Empty(t, subscriber.takePending())
 }
 
-func TestNotifyMultipleListeners(t *testing.T) {
+func TestNotifyMultipleSubscribers(t *testing.T) {
 	notifier := NewNotifier("testMessageType2", nil)
-	listeners := [50]chan messageEnvelope{}
-	for i := 0; i < len(listeners); i++ {
-		listeners[i] = notifier.listen()
+	subscribers := [50]*subscriber{}
+	for i := 0; i < len(subscribers); i++ {
+		subscribers[i] = newSubscriber()
+		notifier.subscribe(subscribers[i])
 	}
 
 	notifier.Notify()
 	notifier.NotifyWithMessage(12345)
-	for listener := range notifier.listeners {
-		assert.Equal(t, nil, (<-listener).messageBody)
-		assert.Equal(t, 12345, (<-listener).messageBody)
+	var firstMessages []*outgoingMessage
+	for _, subscriber := range subscribers {
+		messages := subscriber.takePending()
+		if assert.Equal(t, 2, len(messages)) {
+			assertPayload(t, messages[0], "testMessageType2", nil)
+			assertPayload(t, messages[1], "testMessageType2", 12345.0)
+		}
+		if firstMessages == nil {
+			firstMessages = messages
+		} else {
+			// The message should only have been marshaled once for all the subscribers.
+			assert.Same(t, firstMessages[1], messages[1])
+		}
 	}
 
-	// Should reap closed channels automatically.
-	close(listeners[4])
+	// Should drop closed subscribers automatically.
+	subscribers[4].close()
 	notifier.NotifyWithMessage("message1")
-	assert.Equal(t, 49, len(notifier.listeners))
-	for listener := range notifier.listeners {
-		assert.Equal(t, "message1", (<-listener).messageBody)
-	}
-	close(listeners[16])
-	close(listeners[21])
-	close(listeners[49])
+	assert.Equal(t, 49, len(notifier.subscribers))
+	notifier.unsubscribe(subscribers[16])
+	subscribers[21].close()
 	notifier.NotifyWithMessage("message2")
-	assert.Equal(t, 46, len(notifier.listeners))
-	for listener := range notifier.listeners {
-		assert.Equal(t, "message2", (<-listener).messageBody)
+	assert.Equal(t, 47, len(notifier.subscribers))
+	for subscriber := range notifier.subscribers {
+		messages := subscriber.takePending()
+		if assert.Equal(t, 2, len(messages)) {
+			assertPayload(t, messages[1], "testMessageType2", "message2")
+		}
+	}
+}
+
+func assertPayload(t *testing.T, message *outgoingMessage, expectedType string, expectedData any) {
+	var decodedMessage Message
+	if assert.Nil(t, json.Unmarshal(message.payload, &decodedMessage)) {
+		assert.Equal(t, expectedType, decodedMessage.Type)
+		assert.Equal(t, expectedData, decodedMessage.Data)
+		assert.NotZero(t, decodedMessage.Time)
 	}
 }
 
-func generateTestMessage() any {
-	return "test message"
+func TestNotifierSplitsFieldsOnlyForDeltas(t *testing.T) {
+	notifier := NewNotifier("testMessageType3", func() any {
+		return map[string]int{"A": 1, "B": 2}
+	})
+	subscriber := newSubscriber()
+	notifier.subscribe(subscriber)
+
+	// The fields should only be split out once a client that has asked for deltas needs them.
+	notifier.Notify()
+	message := subscriber.takePending()[0]
+	assert.Nil(t, message.fields)
+	assert.Equal(
+		t, map[string]json.RawMessage{"A": json.RawMessage("1"), "B": json.RawMessage("2")}, message.getFields(),
+	)
+
+	// Event messages never carry fields.
+	notifier.NotifyWithMessage(map[string]int{"C": 3})
+	assert.Nil(t, subscriber.takePending()[0].getFields())
 }
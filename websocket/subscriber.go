@@ -0,0 +1,133 @@
+// Copyright 2024 Team 254. All Rights Reserved.
+// Author: pat@patfairbank.com (Patrick Fairbank)
+//
+// Per-client queue of notifications waiting to be written out to a websocket.
+
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// Maximum number of notifications that may be waiting to be sent to a single client before it is considered too slow
+// to keep up and is disconnected, so that it can't hold up or bloat the server.
+const maxPendingMessages = 50
+
+// A notification that has already been marshaled to JSON, ready to be written to any number of websockets.
+type outgoingMessage struct {
+	messageType string
+	payload     []byte
+	coalesce    bool // Whether the message may be replaced by a later one of the same type that supersedes it.
+
+	// The data, and its top-level fields for state messages whose data is a JSON object, from which deltas are built.
+	// The fields are only split out once a client that has asked for deltas needs them, since most clients don't.
+	data       []byte
+	fields     map[string]json.RawMessage
+	fieldsOnce sync.Once
+	time       int64
+}
+
+type subscriber struct {
+	pending []*outgoingMessage
+	ready   chan struct{} // Signaled whenever there are pending messages to send.
+	evicted chan struct{} // Closed if the client falls too far behind.
+	closed  bool
+	mutex   sync.Mutex
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{ready: make(chan struct{}, 1), evicted: make(chan struct{})}
+}
+
+// Queues the given message for sending without blocking, in place of any unsent message that it supersedes. Returns
+// false if the subscriber should no longer receive messages, either because it has been closed or because it has now
+// been evicted for having too many messages pending.
+func (subscriber *subscriber) enqueue(message *outgoingMessage) bool {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	if subscriber.closed {
+		return false
+	}
+
+	if message.coalesce {
+		for i, pendingMessage := range subscriber.pending {
+			if pendingMessage.coalesce && pendingMessage.messageType == message.messageType {
+				subscriber.pending[i] = message
+				return true
+			}
+		}
+	}
+	if len(subscriber.pending) >= maxPendingMessages {
+		subscriber.closed = true
+		subscriber.pending = nil
+		close(subscriber.evicted)
+		return false
+	}
+	subscriber.pending = append(subscriber.pending, message)
+
+	select {
+	case subscriber.ready <- struct{}{}:
+	default:
+		// The writer has already been signaled and will pick this message up along with the others.
+	}
+	return true
+}
+
+// Returns all the messages that are waiting to be sent, in order, and clears the queue.
+func (subscriber *subscriber) takePending() []*outgoingMessage {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	messages := subscriber.pending
+	subscriber.pending = nil
+	return messages
+}
+
+// Marks the subscriber as no longer accepting messages.
+func (subscriber *subscriber) close() {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	subscriber.closed = true
+	subscriber.pending = nil
+}
+
+// Returns the top-level fields of the message's data, splitting them out on first use, or nil if the message isn't a
+// state message whose data is a JSON object.
+func (message *outgoingMessage) getFields() map[string]json.RawMessage {
+	message.fieldsOnce.Do(func() {
+		if message.coalesce && len(message.data) > 0 && message.data[0] == '{' {
+			if err := json.Unmarshal(message.data, &message.fields); err != nil {
+				message.fields = nil
+			}
+		}
+	})
+	return message.fields
+}
+
+// Returns the message re-marshaled to hold only the fields that differ from the given ones previously sent to the
+// client, or nil if the full message must be sent instead because the client has nothing to merge the delta into.
+func (message *outgoingMessage) deltaPayload(baselineFields map[string]json.RawMessage) []byte {
+	fields := message.getFields()
+	if baselineFields == nil || len(baselineFields) != len(fields) {
+		return nil
+	}
+	changedFields := make(map[string]json.RawMessage)
+	for name, value := range fields {
+		baselineValue, ok := baselineFields[name]
+		if !ok {
+			// The set of fields has changed, as can happen for a map, so a delta couldn't express the removal.
+			return nil
+		}
+		if !bytes.Equal(value, baselineValue) {
+			changedFields[name] = value
+		}
+	}
+	payload, err := json.Marshal(
+		Message{Type: message.messageType, Data: changedFields, Time: message.time, Delta: true},
+	)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
@@ -6,15 +6,17 @@
 package websocket
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/Team254/cheesy-arena/logging"
 	"github.com/gorilla/websocket"
 	"io"
-	"log"
 	"net/http"
-	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,14 +26,59 @@ const pingInterval = time.Second * 10
 type Websocket struct {
 	conn       *websocket.Conn
 	writeMutex *sync.Mutex
+	closeOnce  *sync.Once
+
+	// Whether the client has asked to receive only the changed fields of state notifications, and the fields last sent
+	// to it for each message type, against which the next notification of that type is compared. The latter is guarded
+	// by writeMutex.
+	deltasEnabled  atomic.Bool
+	deltaBaselines map[string]map[string]json.RawMessage
+
+	// Whether the server has closed the connection itself, in which case the error from any pending read is expected.
+	closing atomic.Bool
 }
 
 type Message struct {
 	Type string `json:"type"`
 	Data any    `json:"data"`
+	Time int64  `json:"time,omitempty"` // Server time in milliseconds since the epoch at which the data was generated.
+
+	// Whether the data holds only the top-level fields that have changed since the previous message of the same type,
+	// to be merged by the client into the data it already has.
+	Delta bool `json:"delta,omitempty"`
 }
 
-var websocketUpgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 2014}
+// Message types used by clients to measure the offset between their clock and the server's, so that they can tell how
+// stale a timestamped message is by the time they process it.
+const (
+	timeSyncMessageType      = "timeSync"
+	timeSyncReplyMessageType = "timeSyncReply"
+)
+
+// Message type used by clients that can merge delta-encoded notifications to ask for them.
+const enableDeltasMessageType = "enableDeltas"
+
+// Message types used by clients to learn which run of the server they are connected to, so that they can tell on
+// reconnecting whether it has been restarted in the meantime and reload to pick up any changes, and the message sent to
+// all clients just before the server goes down for a restart.
+const (
+	serverInstanceMessageType      = "serverInstance"
+	serverInstanceReplyMessageType = "serverInstanceReply"
+	serverRestartingMessageType    = "serverRestarting"
+)
+
+// Identifies this run of the server to clients.
+var serverInstanceId = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// Compression is negotiated with each client that supports it, which cuts the size of the largely repetitive JSON
+// notifications considerably on congested venue networks.
+var websocketUpgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 2014, EnableCompression: true}
+
+// Client connections that have been upgraded to websockets and not yet closed.
+var (
+	openWebsockets      = make(map[*Websocket]struct{})
+	openWebsocketsMutex sync.Mutex
+)
 
 // Upgrades the given HTTP request to a websocket connection.
 func NewWebsocket(w http.ResponseWriter, r *http.Request) (*Websocket, error) {
@@ -39,33 +86,116 @@ func NewWebsocket(w http.ResponseWriter, r *http.Request) (*Websocket, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Websocket{conn, new(sync.Mutex)}, nil
+	ws := newWebsocket(conn, new(sync.Once))
+	openWebsocketsMutex.Lock()
+	openWebsockets[ws] = struct{}{}
+	openWebsocketsMutex.Unlock()
+	return ws, nil
 }
 
 func NewTestWebsocket(conn *websocket.Conn) *Websocket {
-	return &Websocket{conn, new(sync.Mutex)}
+	// Mark the connection as already closed so that closing it doesn't affect the set of server-side connections.
+	closeOnce := new(sync.Once)
+	closeOnce.Do(func() {})
+	return newWebsocket(conn, closeOnce)
+}
+
+func newWebsocket(conn *websocket.Conn, closeOnce *sync.Once) *Websocket {
+	return &Websocket{
+		conn:           conn,
+		writeMutex:     new(sync.Mutex),
+		closeOnce:      closeOnce,
+		deltaBaselines: make(map[string]map[string]json.RawMessage),
+	}
 }
 
 func (ws *Websocket) Close() error {
+	ws.closing.Store(true)
+	ws.closeOnce.Do(func() {
+		openWebsocketsMutex.Lock()
+		delete(openWebsockets, ws)
+		openWebsocketsMutex.Unlock()
+	})
 	return ws.conn.Close()
 }
 
+// Returns the number of server-side websocket connections that are currently open.
+func OpenConnectionCount() int {
+	openWebsocketsMutex.Lock()
+	defer openWebsocketsMutex.Unlock()
+	return len(openWebsockets)
+}
+
+// Tells every connected client that the server is about to restart and closes its connection, so that the client can
+// show that the outage is planned and reconnect as soon as the server is back rather than waiting out its usual retry
+// interval. To be called just before the server exits.
+func CloseAllForRestart() {
+	openWebsocketsMutex.Lock()
+	websockets := make([]*Websocket, 0, len(openWebsockets))
+	for ws := range openWebsockets {
+		websockets = append(websockets, ws)
+	}
+	openWebsocketsMutex.Unlock()
+
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "Server restarting")
+	for _, ws := range websockets {
+		// Ignore errors since the client may well have gone away already.
+		_ = ws.Write(serverRestartingMessageType, nil)
+		_ = ws.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+		ws.Close()
+	}
+}
+
 func (ws *Websocket) Read() (string, any, error) {
-	var message Message
-	err := ws.conn.ReadJSON(&message)
-	if websocket.IsCloseError(err, websocket.CloseAbnormalClosure, websocket.CloseGoingAway,
-		websocket.CloseNoStatusReceived) {
-		// This error indicates that the browser terminated the connection normally; rewrite it so that clients don't
-		// log it.
-		return "", nil, io.EOF
+	for {
+		var message Message
+		err := ws.conn.ReadJSON(&message)
+		if websocket.IsCloseError(err, websocket.CloseAbnormalClosure, websocket.CloseGoingAway,
+			websocket.CloseNoStatusReceived) || err != nil && ws.closing.Load() {
+			// This error indicates that the browser terminated the connection normally, or that the server closed it
+			// itself; rewrite it so that clients don't log it.
+			return "", nil, io.EOF
+		}
+		if err != nil {
+			// Include the caller of this method in the error message.
+			_, file, line, _ := runtime.Caller(1)
+			filePathParts := strings.Split(file, "/")
+			return "", nil, fmt.Errorf("[%s:%d] Websocket read error: %v", filePathParts[len(filePathParts)-1], line, err)
+		}
+
+		if message.Type == timeSyncMessageType {
+			// Echo the client's own timestamp back so that it can combine the round trip time with the server time
+			// stamped on the reply to work out its clock offset. These requests are never passed on to the caller.
+			if err = ws.Write(timeSyncReplyMessageType, message.Data); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+		if message.Type == enableDeltasMessageType {
+			ws.deltasEnabled.Store(true)
+			continue
+		}
+		if message.Type == serverInstanceMessageType {
+			if err = ws.Write(serverInstanceReplyMessageType, serverInstanceId); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+		return message.Type, message.Data, nil
 	}
-	if err != nil {
-		// Include the caller of this method in the error message.
-		_, file, line, _ := runtime.Caller(1)
-		filePathParts := strings.Split(file, "/")
-		return "", nil, fmt.Errorf("[%s:%d] Websocket read error: %v", filePathParts[len(filePathParts)-1], line, err)
+}
+
+// Loops reading from the websocket until the client closes the connection, answering any clock synchronization
+// requests along the way. For use by handlers that only push notifications and don't otherwise expect any input.
+func (ws *Websocket) HandleReads() {
+	for {
+		if _, _, err := ws.Read(); err != nil {
+			if err != io.EOF {
+				logging.Web.Warn("Websocket read error.", "error", err)
+			}
+			return
+		}
 	}
-	return message.Type, message.Data, nil
 }
 
 func (ws *Websocket) ReadWithTimeout(timeout time.Duration) (string, any, error) {
@@ -89,9 +219,18 @@ func (ws *Websocket) ReadWithTimeout(timeout time.Duration) (string, any, error)
 }
 
 func (ws *Websocket) Write(messageType string, data any) error {
+	return ws.writeWithTime(messageType, data, time.Now())
+}
+
+// Writes the given message, stamped with the time at which its data was generated.
+func (ws *Websocket) writeWithTime(messageType string, data any, dataTime time.Time) error {
 	ws.writeMutex.Lock()
 	defer ws.writeMutex.Unlock()
-	err := ws.conn.WriteJSON(Message{messageType, data})
+
+	// The client takes this message as the full state, so the next notification of the type can't be a delta from
+	// whatever was sent before it.
+	delete(ws.deltaBaselines, messageType)
+	err := ws.conn.WriteJSON(Message{Type: messageType, Data: data, Time: dataTime.UnixMilli()})
 	if err != nil {
 		// Include the caller of this method in the error message.
 		_, file, line, _ := runtime.Caller(1)
@@ -109,56 +248,80 @@ func (ws *Websocket) WriteError(errorMessage string) error {
 	return ws.Write("error", errorMessage)
 }
 
-// Creates listeners for the given notifiers and loops forever to pass their output directly through to the websocket.
+// Subscribes to the given notifiers and loops forever to pass their output directly through to the websocket. Returns
+// once the client has closed the connection, or after disconnecting it if it can't keep up with the notifications.
 func (ws *Websocket) HandleNotifiers(notifiers ...*Notifier) {
-	// Use reflection to dynamically build a select/case structure for all the notifiers.
-	listeners := make([]reflect.SelectCase, len(notifiers))
-	for i, notifier := range notifiers {
-		listener := notifier.listen()
-		defer close(listener)
-		listeners[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(listener)}
-
-		// Send each notifier's respective data immediately upon connection to bootstrap the client state.
+	subscriber := newSubscriber()
+	for _, notifier := range notifiers {
+		notifier.subscribe(subscriber)
+	}
+	defer func() {
+		for _, notifier := range notifiers {
+			notifier.unsubscribe(subscriber)
+		}
+		subscriber.close()
+	}()
+
+	// Send each notifier's respective data immediately upon connection to bootstrap the client state.
+	for _, notifier := range notifiers {
 		if notifier.messageProducer != nil {
 			err := ws.WriteNotifier(notifier)
 			if err != nil {
-				log.Printf("Websocket error writing inital value for notifier %v: %v", notifier, err)
+				logging.Web.Warn(
+					"Websocket error writing initial value for notifier.", "messageType", notifier.messageType,
+					"error", err,
+				)
 				return
 			}
 		}
 	}
 
-	// Add an additional case to periodically ping the websocket to detect whether the client has closed it.
-	pingCase := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.Tick(pingInterval))}
-	pingIndex := len(listeners)
-	listeners = append(listeners, pingCase)
+	// Periodically ping the websocket to detect whether the client has closed it.
+	pingTicker := time.NewTicker(pingInterval)
+	defer pingTicker.Stop()
 
 	for {
-		// Block until a message is available on any of the channels.
-		chosenIndex, value, ok := reflect.Select(listeners)
-		if ok && chosenIndex == pingIndex {
-			err := ws.Write("ping", nil)
-			if err != nil {
+		select {
+		case <-subscriber.ready:
+			for _, message := range subscriber.takePending() {
+				if err := ws.writeOutgoingMessage(message); err != nil {
+					// The client has probably closed the connection; bail out of the loop.
+					return
+				}
+			}
+		case <-subscriber.evicted:
+			logging.Web.Warn(
+				"Disconnecting websocket client that isn't keeping up with notifications.",
+				"address", ws.conn.RemoteAddr(),
+			)
+			ws.Close()
+			return
+		case <-pingTicker.C:
+			if err := ws.Write("ping", nil); err != nil {
 				// The client has probably closed the connection; bail out of the loop.
 				return
 			}
-			continue
-		}
-		if !ok {
-			log.Printf("Channel for notifier %v closed unexpectedly.", notifiers[chosenIndex])
-			return
-		}
-		message, ok := value.Interface().(messageEnvelope)
-		if !ok {
-			log.Printf("Channel for notifier %v sent unexpected value %v.", notifiers[chosenIndex], value)
-			continue
 		}
+	}
+}
 
-		// Forward the message verbatim on to the websocket.
-		err := ws.Write(message.messageType, message.messageBody)
-		if err != nil {
-			// The client has probably closed the connection; bail out of the loop.
-			return
+// Writes the given already-marshaled message, or just the fields of it that have changed since the last message of the
+// same type if the client has asked for deltas.
+func (ws *Websocket) writeOutgoingMessage(message *outgoingMessage) error {
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+
+	payload := message.payload
+	if ws.deltasEnabled.Load() && message.getFields() != nil {
+		if deltaPayload := message.deltaPayload(ws.deltaBaselines[message.messageType]); deltaPayload != nil {
+			payload = deltaPayload
 		}
+		ws.deltaBaselines[message.messageType] = message.getFields()
+	} else {
+		delete(ws.deltaBaselines, message.messageType)
 	}
+	if err := ws.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+		return fmt.Errorf("Websocket write error: %v", err)
+	}
+	return nil
 }
@@ -98,13 +98,200 @@ func TestWebsocket(t *testing.T) {
 		assert.Contains(t, err.Error(), "timed out")
 	}
 
-	// Test that closing the connection eliminates the listeners once another message is sent.
+	// Test that closing the connection eliminates the subscriber once another message is sent.
 	assert.Nil(t, ws.Close())
 	time.Sleep(time.Millisecond)
 	notifier1.Notify()
 	time.Sleep(time.Millisecond)
 	notifier1.Notify()
-	assert.Equal(t, 0, len(notifier1.listeners))
+	assert.Equal(t, 0, len(notifier1.subscribers))
+}
+
+func TestWebsocketTimeSync(t *testing.T) {
+	notifier := NewNotifier("messageType1", func() any { return "test message" })
+	testWebsocketHandler := func(w http.ResponseWriter, r *http.Request) {
+		ws, err := NewWebsocket(w, r)
+		assert.Nil(t, err)
+		defer ws.Close()
+		go ws.HandleNotifiers(notifier)
+		ws.HandleReads()
+	}
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", testWebsocketHandler)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[len("http"):], nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// Check that outgoing messages are stamped with the time at which they were generated.
+	var message Message
+	assert.Nil(t, conn.ReadJSON(&message))
+	assert.Equal(t, "messageType1", message.Type)
+	assert.InDelta(t, time.Now().UnixMilli(), message.Time, 1000)
+	beforeNotify := time.Now().UnixMilli()
+	notifier.Notify()
+	assert.Nil(t, conn.ReadJSON(&message))
+	assert.Equal(t, "messageType1", message.Type)
+	assert.GreaterOrEqual(t, message.Time, beforeNotify)
+
+	// Check that time sync requests are answered even by a handler that doesn't otherwise read.
+	assert.Nil(t, conn.WriteJSON(Message{Type: "timeSync", Data: 1234.5}))
+	assert.Nil(t, conn.ReadJSON(&message))
+	assert.Equal(t, "timeSyncReply", message.Type)
+	assert.Equal(t, 1234.5, message.Data)
+	assert.InDelta(t, time.Now().UnixMilli(), message.Time, 1000)
+}
+
+func TestWebsocketDeltas(t *testing.T) {
+	state := map[string]any{"MatchState": "pre_match", "Score": 0, "Teams": []int{254, 1114}}
+	notifier := NewNotifier("state", func() any { return state })
+	testWebsocketHandler := func(w http.ResponseWriter, r *http.Request) {
+		ws, err := NewWebsocket(w, r)
+		assert.Nil(t, err)
+		defer ws.Close()
+		go ws.HandleNotifiers(notifier)
+		for {
+			messageType, data, err := ws.Read()
+			if err != nil {
+				return
+			}
+			// Echo a full state message back out when asked, as a handler might in response to a command.
+			if messageType == "sendState" {
+				ws.Write("state", data)
+			}
+		}
+	}
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", testWebsocketHandler)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, response, err := dialer.Dial("ws"+server.URL[len("http"):], nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Contains(t, response.Header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+
+	readMessage := func() Message {
+		var message Message
+		assert.Nil(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		assert.Nil(t, conn.ReadJSON(&message))
+		return message
+	}
+
+	// Check that full messages are sent until the client asks for deltas.
+	message := readMessage()
+	assert.False(t, message.Delta)
+	assert.Equal(t, "pre_match", message.Data.(map[string]any)["MatchState"])
+	notifier.Notify()
+	assert.False(t, readMessage().Delta)
+	assert.Nil(t, conn.WriteJSON(Message{Type: "enableDeltas"}))
+	time.Sleep(10 * time.Millisecond)
+
+	// The first notification afterward has nothing to be compared against, so it is sent in full.
+	notifier.Notify()
+	message = readMessage()
+	assert.False(t, message.Delta)
+	assert.Equal(t, 3, len(message.Data.(map[string]any)))
+
+	state["Score"] = 12
+	notifier.Notify()
+	message = readMessage()
+	assert.True(t, message.Delta)
+	assert.Equal(t, map[string]any{"Score": 12.0}, message.Data)
+	assert.NotZero(t, message.Time)
+	notifier.Notify()
+	message = readMessage()
+	assert.True(t, message.Delta)
+	assert.Equal(t, map[string]any{}, message.Data)
+
+	// Check that a change to the set of fields causes a full message to be sent.
+	state["Winner"] = "red"
+	notifier.Notify()
+	message = readMessage()
+	assert.False(t, message.Delta)
+	assert.Equal(t, 4, len(message.Data.(map[string]any)))
+
+	// Check that a message of the same type written outside the notifier resets the baseline.
+	assert.Nil(t, conn.WriteJSON(Message{Type: "sendState", Data: map[string]any{"Score": 1}}))
+	message = readMessage()
+	assert.False(t, message.Delta)
+	notifier.Notify()
+	assert.False(t, readMessage().Delta)
+
+	// Check that discrete event messages are never delta-encoded.
+	notifier.NotifyWithMessage(state)
+	assert.False(t, readMessage().Delta)
+	notifier.Notify()
+	assert.False(t, readMessage().Delta)
+}
+
+func TestWebsocketOpenConnectionCount(t *testing.T) {
+	// Wait for the server side of any connections left over from other tests to finish closing.
+	assert.Eventually(t, func() bool { return OpenConnectionCount() == 0 }, time.Second, 10*time.Millisecond)
+	opened := make(chan *Websocket)
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := NewWebsocket(w, r)
+		assert.Nil(t, err)
+		opened <- ws
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[len("http"):], nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := <-opened
+	assert.Equal(t, 1, OpenConnectionCount())
+
+	// Check that closing the same websocket more than once only decrements the count once.
+	ws.Close()
+	ws.Close()
+	assert.Equal(t, 0, OpenConnectionCount())
+
+	// Check that client-side test websockets aren't counted.
+	NewTestWebsocket(conn).Close()
+	assert.Equal(t, 0, OpenConnectionCount())
+}
+
+func TestWebsocketCloseAllForRestart(t *testing.T) {
+	readErrors := make(chan error, 1)
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := NewWebsocket(w, r)
+		assert.Nil(t, err)
+		defer ws.Close()
+		_, _, err = ws.Read()
+		readErrors <- err
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[len("http"):], nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// Check that the client can find out which run of the server it is connected to.
+	var message Message
+	assert.Nil(t, conn.WriteJSON(Message{Type: "serverInstance"}))
+	assert.Nil(t, conn.ReadJSON(&message))
+	assert.Equal(t, "serverInstanceReply", message.Type)
+	assert.Equal(t, serverInstanceId, message.Data)
+
+	// Check that the client is told about the restart before its connection is closed.
+	CloseAllForRestart()
+	assert.Nil(t, conn.ReadJSON(&message))
+	assert.Equal(t, "serverRestarting", message.Type)
+	err = conn.ReadJSON(&message)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseServiceRestart), "%v", err)
+
+	// Check that the server side treats the connection as having been closed normally.
+	select {
+	case err = <-readErrors:
+		assert.Equal(t, io.EOF, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timed out waiting for the server-side read to return.")
+	}
+	assert.Equal(t, 0, OpenConnectionCount())
 }
 
 func assertMessage(t *testing.T, ws *Websocket, expectedMessageType string, expectedMessageBody any) {